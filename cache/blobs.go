@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/containerd/containerd/diff"
+	"github.com/containerd/containerd/images"
 	"github.com/containerd/containerd/leases"
 	"github.com/containerd/containerd/mount"
 	"github.com/moby/buildkit/session"
@@ -242,7 +243,13 @@ func isTypeWindows(sr *immutableRef) bool {
 }
 
 // ensureCompression ensures the specified ref has the blob of the specified compression Type.
+// Non-distributable layers are kept as they are because they are referenced by
+// their urls instead of being pushed.
 func ensureCompression(ctx context.Context, ref *immutableRef, desc ocispec.Descriptor, compressionType compression.Type, s session.Group) error {
+	if images.IsNonDistributable(desc.MediaType) {
+		return nil
+	}
+
 	// Resolve converters
 	layerConvertFunc, _, err := getConverters(desc, compressionType)
 	if err != nil {
@@ -712,12 +712,20 @@ func (cc *cacheContext) lazyChecksum(ctx context.Context, m *mount, p string) (*
 	k := convertPathToKey([]byte(p))
 	txn := cc.tree.Txn()
 	root = txn.Root()
+	var prehashed bool
+	if n := hashConcurrency(ctx); n > 1 {
+		prehashed, err = cc.prehash(ctx, root, txn, m, k, n)
+		if err != nil {
+			return nil, err
+		}
+		root = txn.Root()
+	}
 	cr, updated, err := cc.checksum(ctx, root, txn, m, k, true)
 	if err != nil {
 		return nil, err
 	}
 	cc.tree = txn.Commit()
-	cc.dirty = updated
+	cc.dirty = updated || prehashed
 	return cr, err
 }
 
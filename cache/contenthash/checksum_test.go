@@ -435,6 +435,52 @@ func TestChecksumBasicFile(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestChecksumHashConcurrency(t *testing.T) {
+	t.Parallel()
+	tmpdir, err := ioutil.TempDir("", "buildkit-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	snapshotter, err := native.NewSnapshotter(filepath.Join(tmpdir, "snapshots"))
+	require.NoError(t, err)
+	cm, _ := setupCacheManager(t, tmpdir, "native", snapshotter)
+	defer cm.Close()
+
+	ch := []string{
+		"ADD foo file data0",
+		"ADD bar file data1",
+		"ADD d0 dir",
+		"ADD d0/abc file data0",
+		"ADD d0/def symlink abc",
+		"ADD d0/ghi symlink nosuchfile",
+		"ADD d0/jkl file data2",
+		"ADD d1 dir",
+		"ADD d1/mno file data3",
+	}
+
+	// the refs have the same content but don't share their cache contexts
+	ref := createRef(t, cm, ch)
+	ref2 := createRef(t, cm, ch)
+
+	for _, p := range []string{"/", "d0", "foo"} {
+		cc, err := newCacheContext(ref.Metadata(), nil)
+		require.NoError(t, err)
+		expected, err := cc.Checksum(context.TODO(), ref, p, ChecksumOpts{FollowLinks: true}, nil)
+		require.NoError(t, err)
+
+		cc, err = newCacheContext(ref2.Metadata(), nil)
+		require.NoError(t, err)
+		dgst, err := cc.Checksum(WithHashConcurrency(context.TODO(), 4), ref2, p, ChecksumOpts{FollowLinks: true}, nil)
+		require.NoError(t, err)
+		require.Equal(t, expected, dgst, p)
+	}
+
+	err = ref.Release(context.TODO())
+	require.NoError(t, err)
+	err = ref2.Release(context.TODO())
+	require.NoError(t, err)
+}
+
 func TestChecksumIncludeExclude(t *testing.T) {
 	t.Parallel()
 	tmpdir, err := ioutil.TempDir("", "buildkit-state")
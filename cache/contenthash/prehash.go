@@ -0,0 +1,100 @@
+package contenthash
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+
+	iradix "github.com/hashicorp/go-immutable-radix"
+	digest "github.com/opencontainers/go-digest"
+	"golang.org/x/sync/errgroup"
+)
+
+type hashConcurrencyKey struct{}
+
+// WithHashConcurrency returns a context that makes the checksums computed with
+// it hash up to n files in parallel. The checksums don't depend on n.
+func WithHashConcurrency(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, hashConcurrencyKey{}, n)
+}
+
+func hashConcurrency(ctx context.Context) int {
+	n, _ := ctx.Value(hashConcurrencyKey{}).(int)
+	return n
+}
+
+// prehash computes the digests of the files, symlinks and directory headers
+// under k that don't have one yet with n goroutines and inserts them into txn.
+// checksum then only combines the digests in the order of the tree, so the
+// resulting checksum is the same as if the files were hashed one by one.
+func (cc *cacheContext) prehash(ctx context.Context, root *iradix.Node, txn *iradix.Txn, m *mount, k []byte, n int) (bool, error) {
+	var keys [][]byte
+	var records []*CacheRecord
+	add := func(k []byte, cr *CacheRecord) {
+		if cr.Digest == "" && cr.Type != CacheRecordTypeDir {
+			keys = append(keys, k)
+			records = append(records, cr)
+		}
+	}
+	if v, ok := root.Get(k); ok {
+		add(k, v.(*CacheRecord))
+	}
+	root.WalkPrefix(append(append([]byte{}, k...), 0), func(k []byte, v interface{}) bool {
+		add(k, v.(*CacheRecord))
+		return false
+	})
+	if len(keys) < 2 {
+		return false, nil
+	}
+
+	target, err := m.mount(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	digests := make([]digest.Digest, len(keys))
+	eg, ctx := errgroup.WithContext(ctx)
+	ch := make(chan int)
+	eg.Go(func() error {
+		defer close(ch)
+		for i := range keys {
+			select {
+			case ch <- i:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+	for i := 0; i < n && i < len(keys); i++ {
+		eg.Go(func() error {
+			for i := range ch {
+				p := string(convertKeyToPath(bytes.TrimSuffix(keys[i], []byte{0})))
+				fp := filepath.Join(target, filepath.FromSlash(p))
+				fi, err := os.Lstat(fp)
+				if err != nil {
+					return err
+				}
+				dgst, err := prepareDigest(fp, p, fi)
+				if err != nil {
+					return err
+				}
+				digests[i] = dgst
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return false, err
+	}
+
+	for i, k := range keys {
+		txn.Insert(k, &CacheRecord{
+			Digest:   digests[i],
+			Type:     records[i].Type,
+			Linkname: records[i].Linkname,
+		})
+	}
+	return true, nil
+}
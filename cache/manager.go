@@ -42,6 +42,9 @@ type ManagerOpt struct {
 	GarbageCollect  func(ctx context.Context) (gc.Stats, error)
 	Applier         diff.Applier
 	Differ          diff.Comparer
+	// BatchSnapshotCommits adds the committed snapshots to the leases of their
+	// records in the transaction of the snapshot commit
+	BatchSnapshotCommits bool
 }
 
 type Accessor interface {
@@ -257,6 +260,7 @@ func (cm *cacheManager) GetByBlob(ctx context.Context, desc ocispec.Descriptor,
 	queueBlobOnly(rec.md, blobOnly)
 	queueMediaType(rec.md, desc.MediaType)
 	queueBlobSize(rec.md, desc.Size)
+	queueURLs(rec.md, desc.URLs)
 	queueCommitted(rec.md)
 
 	if err := rec.md.Commit(); err != nil {
@@ -708,10 +712,11 @@ func (cm *cacheManager) prune(ctx context.Context, ch chan client.UsageInfo, opt
 			}
 
 			c := &client.UsageInfo{
-				ID:         cr.ID(),
-				Mutable:    cr.mutable,
-				RecordType: recordType,
-				Shared:     shared,
+				ID:           cr.ID(),
+				Mutable:      cr.mutable,
+				RecordType:   recordType,
+				Shared:       shared,
+				CacheMountID: GetCacheMountID(cr),
 			}
 
 			usageCount, lastUsedAt := getLastUsed(cr.md)
@@ -872,18 +877,19 @@ func (cm *cacheManager) markShared(m map[string]*cacheUsageInfo) error {
 }
 
 type cacheUsageInfo struct {
-	refs        int
-	parent      string
-	size        int64
-	mutable     bool
-	createdAt   time.Time
-	usageCount  int
-	lastUsedAt  *time.Time
-	description string
-	doubleRef   bool
-	recordType  client.UsageRecordType
-	shared      bool
-	parentChain []digest.Digest
+	refs         int
+	parent       string
+	size         int64
+	mutable      bool
+	createdAt    time.Time
+	usageCount   int
+	lastUsedAt   *time.Time
+	description  string
+	doubleRef    bool
+	recordType   client.UsageRecordType
+	shared       bool
+	parentChain  []digest.Digest
+	cacheMountID string
 }
 
 func (cm *cacheManager) DiskUsage(ctx context.Context, opt client.DiskUsageInfo) ([]*client.UsageInfo, error) {
@@ -907,16 +913,17 @@ func (cm *cacheManager) DiskUsage(ctx context.Context, opt client.DiskUsageInfo)
 
 		usageCount, lastUsedAt := getLastUsed(cr.md)
 		c := &cacheUsageInfo{
-			refs:        len(cr.refs),
-			mutable:     cr.mutable,
-			size:        getSize(cr.md),
-			createdAt:   GetCreatedAt(cr.md),
-			usageCount:  usageCount,
-			lastUsedAt:  lastUsedAt,
-			description: GetDescription(cr.md),
-			doubleRef:   cr.equalImmutable != nil,
-			recordType:  GetRecordType(cr),
-			parentChain: cr.parentChain(),
+			refs:         len(cr.refs),
+			mutable:      cr.mutable,
+			size:         getSize(cr.md),
+			createdAt:    GetCreatedAt(cr.md),
+			usageCount:   usageCount,
+			lastUsedAt:   lastUsedAt,
+			description:  GetDescription(cr.md),
+			doubleRef:    cr.equalImmutable != nil,
+			recordType:   GetRecordType(cr),
+			parentChain:  cr.parentChain(),
+			cacheMountID: GetCacheMountID(cr),
 		}
 		if c.recordType == "" {
 			c.recordType = client.UsageRecordTypeRegular
@@ -957,17 +964,18 @@ func (cm *cacheManager) DiskUsage(ctx context.Context, opt client.DiskUsageInfo)
 	var du []*client.UsageInfo
 	for id, cr := range m {
 		c := &client.UsageInfo{
-			ID:          id,
-			Mutable:     cr.mutable,
-			InUse:       cr.refs > 0,
-			Size:        cr.size,
-			Parent:      cr.parent,
-			CreatedAt:   cr.createdAt,
-			Description: cr.description,
-			LastUsedAt:  cr.lastUsedAt,
-			UsageCount:  cr.usageCount,
-			RecordType:  cr.recordType,
-			Shared:      cr.shared,
+			ID:           id,
+			Mutable:      cr.mutable,
+			InUse:        cr.refs > 0,
+			Size:         cr.size,
+			Parent:       cr.parent,
+			CreatedAt:    cr.createdAt,
+			Description:  cr.description,
+			LastUsedAt:   cr.lastUsedAt,
+			UsageCount:   cr.usageCount,
+			RecordType:   cr.recordType,
+			Shared:       cr.shared,
+			CacheMountID: cr.cacheMountID,
 		}
 		if filter.Match(adaptUsageInfo(c)) {
 			du = append(du, c)
@@ -1048,6 +1056,13 @@ func WithRecordType(t client.UsageRecordType) RefOption {
 	}
 }
 
+// WithCacheMountID records the ID of the cache mount the ref backs.
+func WithCacheMountID(id string) RefOption {
+	return func(m withMetadata) error {
+		return queueCacheMountID(m.Metadata(), id)
+	}
+}
+
 func WithCreationTime(tm time.Time) RefOption {
 	return func(m withMetadata) error {
 		return queueCreatedAt(m.Metadata(), tm)
@@ -1129,6 +1144,8 @@ func adaptUsageInfo(info *client.UsageInfo) filters.Adaptor {
 			return "", info.Shared
 		case "private":
 			return "", !info.Shared
+		case "cachemountid":
+			return info.CacheMountID, info.CacheMountID != ""
 		}
 
 		// TODO: add int/datetime/bytes support for more fields
@@ -15,6 +15,7 @@ import (
 	"github.com/containerd/containerd/content"
 	"github.com/containerd/containerd/content/local"
 	"github.com/containerd/containerd/diff/apply"
+	"github.com/containerd/containerd/images"
 	"github.com/containerd/containerd/leases"
 	ctdmetadata "github.com/containerd/containerd/metadata"
 	"github.com/containerd/containerd/namespaces"
@@ -24,6 +25,7 @@ import (
 	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/snapshot"
 	containerdsnapshot "github.com/moby/buildkit/snapshot/containerd"
+	"github.com/moby/buildkit/util/compression"
 	"github.com/moby/buildkit/util/leaseutil"
 	digest "github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
@@ -33,9 +35,10 @@ import (
 )
 
 type cmOpt struct {
-	snapshotterName string
-	snapshotter     snapshots.Snapshotter
-	tmpdir          string
+	snapshotterName      string
+	snapshotter          snapshots.Snapshotter
+	tmpdir               string
+	batchSnapshotCommits bool
 }
 
 type cmOut struct {
@@ -125,6 +128,8 @@ func newCacheManager(ctx context.Context, opt cmOpt) (co *cmOut, cleanup func()
 		LeaseManager:   leaseutil.WithNamespace(lm, ns),
 		GarbageCollect: mdb.GarbageCollect,
 		Applier:        apply.NewFileSystemApplier(mdb.ContentStore()),
+
+		BatchSnapshotCommits: opt.batchSnapshotCommits,
 	})
 	if err != nil {
 		return nil, nil, err
@@ -733,6 +738,73 @@ func TestSetBlob(t *testing.T) {
 	//snap.SetBlob()
 }
 
+func TestForeignLayerCompression(t *testing.T) {
+	t.Parallel()
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+
+	tmpdir, err := ioutil.TempDir("", "cachemanager")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	snapshotter, err := native.NewSnapshotter(filepath.Join(tmpdir, "snapshots"))
+	require.NoError(t, err)
+
+	co, cleanup, err := newCacheManager(ctx, cmOpt{
+		snapshotter:     snapshotter,
+		snapshotterName: "native",
+	})
+	require.NoError(t, err)
+
+	defer cleanup()
+
+	cm := co.manager
+
+	b, desc, err := mapToBlob(map[string]string{"foo": "bar"}, true)
+	require.NoError(t, err)
+
+	err = content.WriteBlob(ctx, co.cs, "ref1", bytes.NewBuffer(b), desc)
+	require.NoError(t, err)
+
+	desc.MediaType = images.MediaTypeDockerSchema2LayerForeignGzip
+	desc.URLs = []string{"https://example.com/layer"}
+
+	snap, err := cm.GetByBlob(ctx, desc, nil)
+	require.NoError(t, err)
+	defer snap.Release(context.TODO())
+
+	remote, err := snap.GetRemote(ctx, true, compression.Uncompressed, true, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(remote.Descriptors))
+
+	// the foreign layer is referenced with its urls instead of being converted
+	require.Equal(t, desc.Digest, remote.Descriptors[0].Digest)
+	require.Equal(t, desc.MediaType, remote.Descriptors[0].MediaType)
+	require.Equal(t, desc.URLs, remote.Descriptors[0].URLs)
+
+	_, err = snap.(*immutableRef).getCompressionBlob(ctx, compression.Uncompressed)
+	require.Error(t, err)
+
+	// distributable layers with urls are converted, only the media type marks
+	// foreign layers
+	b, desc, err = mapToBlob(map[string]string{"foo": "baz"}, true)
+	require.NoError(t, err)
+
+	err = content.WriteBlob(ctx, co.cs, "ref2", bytes.NewBuffer(b), desc)
+	require.NoError(t, err)
+
+	desc.URLs = []string{"https://example.com/layer2"}
+
+	snap2, err := cm.GetByBlob(ctx, desc, nil)
+	require.NoError(t, err)
+	defer snap2.Release(context.TODO())
+
+	remote, err = snap2.GetRemote(ctx, true, compression.Uncompressed, true, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(remote.Descriptors))
+	require.NotEqual(t, desc.Digest, remote.Descriptors[0].Digest)
+	require.Equal(t, ocispec.MediaTypeImageLayer, remote.Descriptors[0].MediaType)
+}
+
 func TestPrune(t *testing.T) {
 	t.Parallel()
 	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
@@ -843,6 +915,97 @@ func TestPrune(t *testing.T) {
 	require.Equal(t, 0, len(dirs))
 }
 
+func TestBatchSnapshotCommits(t *testing.T) {
+	t.Parallel()
+
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+
+	tmpdir, err := ioutil.TempDir("", "cachemanager")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	snapshotter, err := native.NewSnapshotter(filepath.Join(tmpdir, "snapshots"))
+	require.NoError(t, err)
+
+	co, cleanup, err := newCacheManager(ctx, cmOpt{
+		snapshotter:          snapshotter,
+		snapshotterName:      "native",
+		batchSnapshotCommits: true,
+	})
+	require.NoError(t, err)
+	defer cleanup()
+	cm := co.manager
+
+	active, err := cm.New(ctx, nil, nil, CachePolicyRetain)
+	require.NoError(t, err)
+	snap, err := active.Commit(ctx)
+	require.NoError(t, err)
+	require.NoError(t, snap.(*immutableRef).finalizeLocked(ctx))
+
+	// the committed snapshot is in the lease of the record
+	resources, err := co.lm.ListResources(ctx, leases.Lease{ID: snap.ID()})
+	require.NoError(t, err)
+	require.Equal(t, []leases.Resource{{ID: snap.ID(), Type: "snapshots/native"}}, resources)
+
+	// and is kept after the mutable ref is removed
+	require.NoError(t, snap.Release(ctx))
+	snap, err = cm.Get(ctx, snap.ID())
+	require.NoError(t, err)
+	m, err := snap.Mount(ctx, true, nil)
+	require.NoError(t, err)
+	_, release, err := m.Mount()
+	require.NoError(t, err)
+	require.NoError(t, release())
+	require.NoError(t, snap.Release(ctx))
+}
+
+func TestIgnoreForCacheInherited(t *testing.T) {
+	t.Parallel()
+
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+
+	tmpdir, err := ioutil.TempDir("", "cachemanager")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	snapshotter, err := native.NewSnapshotter(filepath.Join(tmpdir, "snapshots"))
+	require.NoError(t, err)
+
+	co, cleanup, err := newCacheManager(ctx, cmOpt{
+		snapshotter:     snapshotter,
+		snapshotterName: "native",
+	})
+	require.NoError(t, err)
+	defer cleanup()
+	cm := co.manager
+
+	active, err := cm.New(ctx, nil, nil, CachePolicyRetain)
+	require.NoError(t, err)
+	parent, err := active.Commit(ctx)
+	require.NoError(t, err)
+	defer parent.Release(ctx)
+	require.NoError(t, SetIgnoreForCache(parent, []string{"/build.log"}))
+
+	// the refs committed on top of the parent inherit its paths
+	active, err = cm.New(ctx, parent, nil, CachePolicyRetain)
+	require.NoError(t, err)
+	child, err := active.Commit(ctx)
+	require.NoError(t, err)
+	defer child.Release(ctx)
+	require.Equal(t, []string{"/build.log"}, GetIgnoreForCache(child))
+
+	require.NoError(t, SetIgnoreForCache(child, []string{"/out/timestamp", "/build.log"}))
+	require.Equal(t, []string{"/build.log", "/out/timestamp"}, GetIgnoreForCache(child))
+	require.Equal(t, []string{"/build.log"}, GetIgnoreForCache(parent))
+
+	active, err = cm.New(ctx, child, nil, CachePolicyRetain)
+	require.NoError(t, err)
+	grandchild, err := active.Commit(ctx)
+	require.NoError(t, err)
+	defer grandchild.Release(ctx)
+	require.Equal(t, []string{"/build.log", "/out/timestamp"}, GetIgnoreForCache(grandchild))
+}
+
 func TestLazyCommit(t *testing.T) {
 	t.Parallel()
 
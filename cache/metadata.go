@@ -5,6 +5,7 @@ import (
 
 	"github.com/moby/buildkit/cache/metadata"
 	"github.com/moby/buildkit/client"
+	digest "github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 	bolt "go.etcd.io/bbolt"
 )
@@ -19,6 +20,10 @@ const keyLastUsedAt = "cache.lastUsedAt"
 const keyUsageCount = "cache.usageCount"
 const keyLayerType = "cache.layerType"
 const keyRecordType = "cache.recordType"
+const keyCacheMountID = "cache.cacheMountID"
+const keyVertex = "cache.vertex"
+const keyIgnoreForCache = "cache.ignoreForCache"
+const keyExitCode = "exec.exitCode"
 const keyCommitted = "snapshot.committed"
 const keyParent = "cache.parent"
 const keyDiffID = "cache.diffID"
@@ -33,6 +38,10 @@ const keyImageRefs = "cache.imageRefs"
 // BlobSize is the packed blob size as specified in the oci descriptor
 const keyBlobSize = "cache.blobsize"
 
+// URLs are the locations the blob can be fetched from if it can't be
+// pushed to registries, like the base layers of Windows images
+const keyURLs = "cache.urls"
+
 const keyDeleted = "cache.deleted"
 
 func queueDiffID(si *metadata.StorageItem, str string) error {
@@ -333,6 +342,12 @@ func appendImageRef(si *metadata.StorageItem, s string) error {
 	})
 }
 
+// GetImageRefs returns the names of the images whose layer m was pulled for.
+// It is empty for layers created by builds.
+func GetImageRefs(m withMetadata) []string {
+	return getImageRefs(m.Metadata())
+}
+
 func getImageRefs(si *metadata.StorageItem) []string {
 	v := si.Get(keyImageRefs)
 	if v == nil {
@@ -368,6 +383,32 @@ func getBlobSize(si *metadata.StorageItem) int64 {
 	return size
 }
 
+func queueURLs(si *metadata.StorageItem, urls []string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+	v, err := metadata.NewValue(urls)
+	if err != nil {
+		return errors.Wrap(err, "failed to create urls value")
+	}
+	si.Queue(func(b *bolt.Bucket) error {
+		return si.SetValue(b, keyURLs, v)
+	})
+	return nil
+}
+
+func getURLs(si *metadata.StorageItem) []string {
+	v := si.Get(keyURLs)
+	if v == nil {
+		return nil
+	}
+	var urls []string
+	if err := v.Unmarshal(&urls); err != nil {
+		return nil
+	}
+	return urls
+}
+
 func getEqualMutable(si *metadata.StorageItem) string {
 	v := si.Get(keyEqualMutable)
 	if v == nil {
@@ -560,3 +601,133 @@ func queueRecordType(si *metadata.StorageItem, value client.UsageRecordType) err
 	})
 	return nil
 }
+
+func GetCacheMountID(m withMetadata) string {
+	v := m.Metadata().Get(keyCacheMountID)
+	if v == nil {
+		return ""
+	}
+	var str string
+	if err := v.Unmarshal(&str); err != nil {
+		return ""
+	}
+	return str
+}
+
+func queueCacheMountID(si *metadata.StorageItem, id string) error {
+	v, err := metadata.NewValue(id)
+	if err != nil {
+		return errors.Wrap(err, "failed to create cache mount id value")
+	}
+	si.Queue(func(b *bolt.Bucket) error {
+		return si.SetValue(b, keyCacheMountID, v)
+	})
+	return nil
+}
+
+// SetVertex records the digest of the vertex that created the ref
+func SetVertex(m withMetadata, dgst digest.Digest) error {
+	v, err := metadata.NewValue(dgst)
+	if err != nil {
+		return errors.Wrap(err, "failed to create vertex value")
+	}
+	m.Metadata().Queue(func(b *bolt.Bucket) error {
+		return m.Metadata().SetValue(b, keyVertex, v)
+	})
+	return m.Metadata().Commit()
+}
+
+// SetIgnoreForCache adds paths to the paths of the ref that are excluded from
+// its content based cache keys. The refs committed on top of the ref inherit
+// its paths.
+func SetIgnoreForCache(m withMetadata, paths []string) error {
+	existing := getIgnoreForCache(m.Metadata())
+	for _, p := range paths {
+		if !containsString(existing, p) {
+			existing = append(existing, p)
+		}
+	}
+	if err := queueIgnoreForCache(m.Metadata(), existing); err != nil {
+		return err
+	}
+	return m.Metadata().Commit()
+}
+
+func queueIgnoreForCache(si *metadata.StorageItem, paths []string) error {
+	v, err := metadata.NewValue(paths)
+	if err != nil {
+		return errors.Wrap(err, "failed to create ignore for cache value")
+	}
+	si.Queue(func(b *bolt.Bucket) error {
+		return si.SetValue(b, keyIgnoreForCache, v)
+	})
+	return nil
+}
+
+// GetIgnoreForCache returns the paths of the ref that are excluded from its
+// content based cache keys
+func GetIgnoreForCache(m withMetadata) []string {
+	return getIgnoreForCache(m.Metadata())
+}
+
+func getIgnoreForCache(si *metadata.StorageItem) []string {
+	v := si.Get(keyIgnoreForCache)
+	if v == nil {
+		return nil
+	}
+	var paths []string
+	if err := v.Unmarshal(&paths); err != nil {
+		return nil
+	}
+	return paths
+}
+
+func containsString(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// SetExitCode records the nonzero exit code of the process that created the
+// ref
+func SetExitCode(m withMetadata, code int) error {
+	v, err := metadata.NewValue(code)
+	if err != nil {
+		return errors.Wrap(err, "failed to create exit code value")
+	}
+	m.Metadata().Queue(func(b *bolt.Bucket) error {
+		return m.Metadata().SetValue(b, keyExitCode, v)
+	})
+	return m.Metadata().Commit()
+}
+
+// GetExitCode returns the exit code of the process that created the ref. It is
+// zero if the process succeeded or the ref wasn't created by a process.
+func GetExitCode(m withMetadata) int {
+	v := m.Metadata().Get(keyExitCode)
+	if v == nil {
+		return 0
+	}
+	var code int
+	if err := v.Unmarshal(&code); err != nil {
+		return 0
+	}
+	return code
+}
+
+// GetVertex returns the digest of the vertex that created the ref, if it is
+// known
+func GetVertex(m withMetadata) digest.Digest {
+	v := m.Metadata().Get(keyVertex)
+	if v == nil {
+		return ""
+	}
+	var dgst digest.Digest
+	if err := v.Unmarshal(&dgst); err != nil {
+		return ""
+	}
+	return dgst
+}
@@ -3,6 +3,7 @@ package metadata
 import (
 	"bytes"
 	"encoding/json"
+	"sort"
 	"strings"
 	"sync"
 
@@ -177,6 +178,59 @@ func (s *Store) Update(id string, fn func(b *bolt.Bucket) error) error {
 	}))
 }
 
+// Commit writes the queued changes of all the items in one transaction. The
+// queue locks of the items are taken in the order of their IDs, so concurrent
+// commits of overlapping items can't deadlock. The caller must not hold the
+// queue lock of any item, e.g. by calling Commit from a queued function.
+func (s *Store) Commit(items ...*StorageItem) error {
+	items = sortedItems(items)
+	for _, si := range items {
+		si.qmu.Lock()
+		defer si.qmu.Unlock()
+	}
+	if err := s.db.Update(func(tx *bolt.Tx) error {
+		main, err := tx.CreateBucketIfNotExists([]byte(mainBucket))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		for _, si := range items {
+			b, err := main.CreateBucketIfNotExists([]byte(si.id))
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			for _, fn := range si.queue {
+				if err := fn(b); err != nil {
+					return errors.WithStack(err)
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return errors.WithStack(err)
+	}
+	for _, si := range items {
+		si.queue = si.queue[:0]
+	}
+	return nil
+}
+
+// sortedItems returns the unique items ordered by their IDs
+func sortedItems(items []*StorageItem) []*StorageItem {
+	out := make([]*StorageItem, 0, len(items))
+	seen := map[*StorageItem]struct{}{}
+	for _, si := range items {
+		if _, ok := seen[si]; ok {
+			continue
+		}
+		seen[si] = struct{}{}
+		out = append(out, si)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].id < out[j].id
+	})
+	return out
+}
+
 func (s *Store) Get(id string) (*StorageItem, bool) {
 	empty := func() *StorageItem {
 		si, _ := newStorageItem(id, nil, s)
@@ -204,9 +258,11 @@ func (s *Store) Close() error {
 }
 
 type StorageItem struct {
-	id      string
-	vmu     sync.RWMutex
-	values  map[string]*Value
+	id     string
+	vmu    sync.RWMutex
+	values map[string]*Value
+	// qmu guards queue. Store.Commit locks the qmu of several items in the
+	// order of their IDs.
 	qmu     sync.Mutex
 	queue   []func(*bolt.Bucket) error
 	storage *Store
@@ -315,17 +371,7 @@ func (s *StorageItem) Queue(fn func(b *bolt.Bucket) error) {
 }
 
 func (s *StorageItem) Commit() error {
-	s.qmu.Lock()
-	defer s.qmu.Unlock()
-	return errors.WithStack(s.Update(func(b *bolt.Bucket) error {
-		for _, fn := range s.queue {
-			if err := fn(b); err != nil {
-				return errors.WithStack(err)
-			}
-		}
-		s.queue = s.queue[:0]
-		return nil
-	}))
+	return s.storage.Commit(s)
 }
 
 func (s *StorageItem) Indexes() (out []string) {
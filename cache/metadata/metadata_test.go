@@ -1,13 +1,16 @@
 package metadata
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	bolt "go.etcd.io/bbolt"
+	"golang.org/x/sync/errgroup"
 )
 
 func TestGetSetSearch(t *testing.T) {
@@ -210,3 +213,69 @@ func TestExternalData(t *testing.T) {
 	_, err = si.GetExternal("ext1")
 	require.Error(t, err)
 }
+
+func TestCommitItems(t *testing.T) {
+	t.Parallel()
+
+	tmpdir, err := ioutil.TempDir("", "buildkit-storage")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	dbPath := filepath.Join(tmpdir, "storage.db")
+
+	s, err := NewStore(dbPath)
+	require.NoError(t, err)
+	defer s.Close()
+
+	queue := func(si *StorageItem, val string) {
+		v, err := NewValue(val)
+		require.NoError(t, err)
+		si.Queue(func(b *bolt.Bucket) error {
+			return si.SetValue(b, "bar", v)
+		})
+	}
+
+	// concurrent commits of the same items in different orders don't deadlock
+	items := make([]*StorageItem, 10)
+	for i := range items {
+		items[i], _ = s.Get(fmt.Sprintf("foo%d", i))
+	}
+	var eg errgroup.Group
+	for i := range items {
+		i := i
+		eg.Go(func() error {
+			queue(items[i], fmt.Sprintf("val%d", i))
+			next := items[(i+1)%len(items)]
+			return s.Commit(items[i], next, items[i])
+		})
+	}
+	require.NoError(t, eg.Wait())
+
+	// a failing change discards the changes of all the items
+	si1, _ := s.Get("multi1")
+	si2, _ := s.Get("multi2")
+	queue(si1, "multi1")
+	si2.Queue(func(b *bolt.Bucket) error {
+		return errors.New("invalid")
+	})
+	require.Error(t, s.Commit(si1, si2))
+
+	err = s.Close()
+	require.NoError(t, err)
+
+	s, err = NewStore(dbPath)
+	require.NoError(t, err)
+	defer s.Close()
+
+	for i := range items {
+		si, ok := s.Get(fmt.Sprintf("foo%d", i))
+		require.True(t, ok)
+		var str string
+		require.NoError(t, si.Get("bar").Unmarshal(&str))
+		require.Equal(t, fmt.Sprintf("val%d", i), str)
+	}
+	_, ok := s.Get("multi1")
+	require.False(t, ok)
+	_, ok = s.Get("multi2")
+	require.False(t, ok)
+}
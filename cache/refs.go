@@ -365,6 +365,7 @@ func (sr *immutableRef) ociDesc() (ocispec.Descriptor, error) {
 		Digest:      digest.Digest(getBlob(sr.md)),
 		Size:        getBlobSize(sr.md),
 		MediaType:   getMediaType(sr.md),
+		URLs:        getURLs(sr.md),
 		Annotations: make(map[string]string),
 	}
 
@@ -794,6 +795,28 @@ func (cr *cacheRecord) finalize(ctx context.Context) error {
 		return nil
 	}
 
+	if err := cr.commitSnapshot(ctx, mutable); err != nil {
+		return err
+	}
+	mutable.dead = true
+	go func() {
+		cr.cm.mu.Lock()
+		defer cr.cm.mu.Unlock()
+		if err := mutable.remove(context.TODO(), true); err != nil {
+			logrus.Error(err)
+		}
+	}()
+
+	cr.equalMutable = nil
+	clearEqualMutable(cr.md)
+	return cr.md.Commit()
+}
+
+// commitSnapshot commits the snapshot of mutable as the snapshot of the record
+// and adds it to the lease of the record. With BatchSnapshotCommits the
+// snapshot is added to the lease by the commit itself, saving a metadata
+// transaction per commit.
+func (cr *cacheRecord) commitSnapshot(ctx context.Context, mutable *mutableRef) error {
 	_, err := cr.cm.ManagerOpt.LeaseManager.Create(ctx, func(l *leases.Lease) error {
 		l.ID = cr.ID()
 		l.Labels = map[string]string{
@@ -807,7 +830,9 @@ func (cr *cacheRecord) finalize(ctx context.Context) error {
 		}
 	}
 
-	if err := cr.cm.ManagerOpt.LeaseManager.AddResource(ctx, leases.Lease{ID: cr.ID()}, leases.Resource{
+	if cr.cm.ManagerOpt.BatchSnapshotCommits {
+		ctx = leases.WithLease(ctx, cr.ID())
+	} else if err := cr.cm.ManagerOpt.LeaseManager.AddResource(ctx, leases.Lease{ID: cr.ID()}, leases.Resource{
 		ID:   cr.ID(),
 		Type: "snapshots/" + cr.cm.ManagerOpt.Snapshotter.Name(),
 	}); err != nil {
@@ -815,23 +840,11 @@ func (cr *cacheRecord) finalize(ctx context.Context) error {
 		return errors.Wrapf(err, "failed to add snapshot %s to lease", cr.ID())
 	}
 
-	err = cr.cm.Snapshotter.Commit(ctx, cr.ID(), mutable.ID())
-	if err != nil {
+	if err := cr.cm.Snapshotter.Commit(ctx, cr.ID(), mutable.ID()); err != nil {
 		cr.cm.LeaseManager.Delete(context.TODO(), leases.Lease{ID: cr.ID()})
 		return errors.Wrapf(err, "failed to commit %s", mutable.ID())
 	}
-	mutable.dead = true
-	go func() {
-		cr.cm.mu.Lock()
-		defer cr.cm.mu.Unlock()
-		if err := mutable.remove(context.TODO(), true); err != nil {
-			logrus.Error(err)
-		}
-	}()
-
-	cr.equalMutable = nil
-	clearEqualMutable(cr.md)
-	return cr.md.Commit()
+	return nil
 }
 
 func (sr *mutableRef) updateLastUsed() bool {
@@ -863,6 +876,13 @@ func (sr *mutableRef) commit(ctx context.Context) (*immutableRef, error) {
 	parentID := ""
 	if rec.parent != nil {
 		parentID = rec.parent.ID()
+		// the files excluded from the cache keys of the parent are still
+		// part of the committed ref
+		if paths := getIgnoreForCache(rec.parent.md); len(paths) > 0 {
+			if err := queueIgnoreForCache(md, paths); err != nil {
+				return nil, err
+			}
+		}
 	}
 	if err := initializeMetadata(rec, parentID); err != nil {
 		return nil, err
@@ -870,14 +890,10 @@ func (sr *mutableRef) commit(ctx context.Context) (*immutableRef, error) {
 
 	sr.cm.records[id] = rec
 
-	if err := sr.md.Commit(); err != nil {
-		return nil, err
-	}
-
 	queueCommitted(md)
 	setSize(md, sizeUnknown)
 	setEqualMutable(md, sr.ID())
-	if err := md.Commit(); err != nil {
+	if err := sr.cm.md.Commit(sr.md, md); err != nil {
 		return nil, err
 	}
 
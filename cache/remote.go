@@ -8,6 +8,7 @@ import (
 
 	"github.com/containerd/containerd/content"
 	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
 	"github.com/containerd/containerd/reference"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/solver"
@@ -105,7 +106,9 @@ func (sr *immutableRef) GetRemote(ctx context.Context, createIfNeeded bool, comp
 			}
 		}
 
-		if forceCompression {
+		// non-distributable layers are not converted, they need to keep the
+		// digest that can be fetched from their urls
+		if forceCompression && !images.IsNonDistributable(desc.MediaType) {
 			// ensure the compression type.
 			// compressed blob must be created and stored in the content store.
 			_, convertMediaTypeFunc, err := getConverters(desc, compressionType)
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/containerd/containerd/content"
@@ -40,6 +41,79 @@ func oneOffProgress(ctx context.Context, id string) func(err error) error {
 	}
 }
 
+// progressProvider reports the bytes read from the wrapped provider as the
+// progress of writing a single blob.
+type progressProvider struct {
+	content.Provider
+	pw progress.Writer
+	id string
+
+	mu   sync.Mutex
+	st   progress.Status
+	last time.Time
+}
+
+func newProgressProvider(ctx context.Context, p content.Provider, id string, size int64) *progressProvider {
+	pw, _, _ := progress.NewFromContext(ctx)
+	now := time.Now()
+	pp := &progressProvider{
+		Provider: p,
+		pw:       pw,
+		id:       id,
+		st: progress.Status{
+			Total:   int(size),
+			Started: &now,
+		},
+		last: now,
+	}
+	pw.Write(id, pp.st)
+	return pp
+}
+
+func (p *progressProvider) ReaderAt(ctx context.Context, desc ocispec.Descriptor) (content.ReaderAt, error) {
+	ra, err := p.Provider.ReaderAt(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	return &progressReaderAt{ReaderAt: ra, p: p}, nil
+}
+
+func (p *progressProvider) update(offset int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if offset > p.st.Current {
+		p.st.Current = offset
+	}
+	if now := time.Now(); now.Sub(p.last) > 150*time.Millisecond {
+		p.last = now
+		p.pw.Write(p.id, p.st)
+	}
+}
+
+func (p *progressProvider) done(err error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	if err == nil {
+		p.st.Current = p.st.Total
+	}
+	p.st.Completed = &now
+	p.pw.Write(p.id, p.st)
+	p.pw.Close()
+	return err
+}
+
+type progressReaderAt struct {
+	content.ReaderAt
+	p *progressProvider
+}
+
+func (r *progressReaderAt) ReadAt(b []byte, off int64) (int, error) {
+	n, err := r.ReaderAt.ReadAt(b, off)
+	r.p.update(int(off) + n)
+	return n, err
+}
+
 type Exporter interface {
 	solver.CacheExporterTarget
 	// Finalize finalizes and return metadata that are returned to the client
@@ -58,15 +132,22 @@ type contentCacheExporter struct {
 	chains   *v1.CacheChains
 	ingester content.Ingester
 	oci      bool
+	maxAge   time.Duration
 }
 
-func NewExporter(ingester content.Ingester, oci bool) Exporter {
+// NewExporter returns an exporter that writes the cache to the ingester. If
+// maxAge is not zero the records with results older than maxAge are not
+// exported.
+func NewExporter(ingester content.Ingester, oci bool, maxAge time.Duration) Exporter {
 	cc := v1.NewCacheChains()
-	return &contentCacheExporter{CacheExporterTarget: cc, chains: cc, ingester: ingester, oci: oci}
+	return &contentCacheExporter{CacheExporterTarget: cc, chains: cc, ingester: ingester, oci: oci, maxAge: maxAge}
 }
 
 func (ce *contentCacheExporter) Finalize(ctx context.Context) (map[string]string, error) {
 	res := make(map[string]string)
+	if ce.maxAge > 0 {
+		ce.chains.PruneOlderThan(time.Now().Add(-ce.maxAge))
+	}
 	config, descs, err := ce.chains.Marshal()
 	if err != nil {
 		return nil, err
@@ -94,11 +175,11 @@ func (ce *contentCacheExporter) Finalize(ctx context.Context) (map[string]string
 		if !ok {
 			return nil, errors.Errorf("missing blob %s", l.Blob)
 		}
-		layerDone := oneOffProgress(ctx, fmt.Sprintf("writing layer %s", l.Blob))
-		if err := contentutil.Copy(ctx, ce.ingester, dgstPair.Provider, dgstPair.Descriptor, logs.LoggerFromContext(ctx)); err != nil {
-			return nil, layerDone(errors.Wrap(err, "error writing layer blob"))
+		pp := newProgressProvider(ctx, dgstPair.Provider, fmt.Sprintf("writing layer %s", l.Blob), dgstPair.Descriptor.Size)
+		if err := contentutil.Copy(ctx, ce.ingester, pp, dgstPair.Descriptor, logs.LoggerFromContext(ctx)); err != nil {
+			return nil, pp.done(errors.Wrap(err, "error writing layer blob"))
 		}
-		layerDone(nil)
+		pp.done(nil)
 		mfst.Manifests = append(mfst.Manifests, dgstPair.Descriptor)
 	}
 
@@ -19,6 +19,7 @@ const (
 	attrSrc              = "src"
 	attrDest             = "dest"
 	attrOCIMediatypes    = "oci-mediatypes"
+	attrMaxAge           = "max-age"
 	contentStoreIDPrefix = "local:"
 )
 
@@ -37,12 +38,23 @@ func ResolveCacheExporterFunc(sm *session.Manager) remotecache.ResolveCacheExpor
 			}
 			ociMediatypes = b
 		}
+		var maxAge time.Duration
+		if v, ok := attrs[attrMaxAge]; ok {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse %s", attrMaxAge)
+			}
+			if d < 0 {
+				return nil, errors.Errorf("invalid %s %s", attrMaxAge, v)
+			}
+			maxAge = d
+		}
 		csID := contentStoreIDPrefix + store
 		cs, err := getContentStore(ctx, sm, g, csID)
 		if err != nil {
 			return nil, err
 		}
-		return remotecache.NewExporter(cs, ociMediatypes), nil
+		return remotecache.NewExporter(cs, ociMediatypes, maxAge), nil
 	}
 }
 
@@ -3,6 +3,7 @@ package registry
 import (
 	"context"
 	"strconv"
+	"time"
 
 	"github.com/containerd/containerd/content"
 	"github.com/containerd/containerd/remotes/docker"
@@ -31,6 +32,7 @@ func canonicalizeRef(rawRef string) (string, error) {
 const (
 	attrRef           = "ref"
 	attrOCIMediatypes = "oci-mediatypes"
+	attrMaxAge        = "max-age"
 )
 
 func ResolveCacheExporterFunc(sm *session.Manager, hosts docker.RegistryHosts) remotecache.ResolveCacheExporterFunc {
@@ -47,12 +49,23 @@ func ResolveCacheExporterFunc(sm *session.Manager, hosts docker.RegistryHosts) r
 			}
 			ociMediatypes = b
 		}
+		var maxAge time.Duration
+		if v, ok := attrs[attrMaxAge]; ok {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse %s", attrMaxAge)
+			}
+			if d < 0 {
+				return nil, errors.Errorf("invalid %s %s", attrMaxAge, v)
+			}
+			maxAge = d
+		}
 		remote := resolver.DefaultPool.GetResolver(hosts, ref, "push", sm, g)
 		pusher, err := remote.Pusher(ctx, ref)
 		if err != nil {
 			return nil, err
 		}
-		return remotecache.NewExporter(contentutil.FromPusher(pusher), ociMediatypes), nil
+		return remotecache.NewExporter(contentutil.FromPusher(pusher), ociMediatypes, maxAge), nil
 	}
 }
 
@@ -38,6 +38,27 @@ func (c *CacheChains) Visited(v interface{}) bool {
 	return ok
 }
 
+// PruneOlderThan removes the records with a result created before t. Records
+// without a result, and stale records, are only kept if a record with a newer
+// result depends on them so that no dependency chain is broken. Results with
+// an unknown creation time are kept.
+func (c *CacheChains) PruneOlderThan(t time.Time) {
+	keep := map[*item]struct{}{}
+	for _, it := range c.items {
+		if it.result == nil || (!it.resultTime.IsZero() && it.resultTime.Before(t)) {
+			continue
+		}
+		it.walkAllResults(func(*item) error { return nil }, keep)
+	}
+	items := make([]*item, 0, len(keep))
+	for _, it := range c.items {
+		if _, ok := keep[it]; ok {
+			items = append(items, it)
+		}
+	}
+	c.items = items
+}
+
 func (c *CacheChains) normalize() error {
 	st := &normalizeState{
 		added: map[*item]*item{},
@@ -91,6 +91,47 @@ func TestSimpleMarshal(t *testing.T) {
 	require.Equal(t, len(cfg.Records), 4)
 }
 
+func TestPruneOlderThan(t *testing.T) {
+	cc := NewCacheChains()
+	now := time.Now()
+
+	remote := func(s ...string) *solver.Remote {
+		r := &solver.Remote{}
+		for _, s := range s {
+			r.Descriptors = append(r.Descriptors, ocispec.Descriptor{Digest: dgst(s)})
+		}
+		return r
+	}
+
+	// base is stale but a fresh record depends on it
+	base := cc.Add(outputKey(dgst("base"), 0))
+	base.AddResult(now.Add(-48*time.Hour), remote("d0"))
+	fresh := cc.Add(outputKey(dgst("fresh"), 0))
+	fresh.LinkFrom(base, 0, "")
+	fresh.AddResult(now, remote("d0", "d1"))
+
+	// stale and its input without a result are only used by stale records
+	input := cc.Add(outputKey(dgst("input"), 0))
+	stale := cc.Add(outputKey(dgst("stale"), 0))
+	stale.LinkFrom(input, 0, "")
+	stale.AddResult(now.Add(-48*time.Hour), remote("d2"))
+
+	cc.PruneOlderThan(now.Add(-24 * time.Hour))
+
+	cfg, _, err := cc.Marshal()
+	require.NoError(t, err)
+
+	require.Equal(t, 2, len(cfg.Records))
+	require.Equal(t, outputKey(dgst("fresh"), 0), cfg.Records[0].Digest)
+	require.Equal(t, outputKey(dgst("base"), 0), cfg.Records[1].Digest)
+	require.Equal(t, 1, len(cfg.Records[0].Inputs))
+	require.Equal(t, 1, cfg.Records[0].Inputs[0][0].LinkIndex)
+
+	require.Equal(t, 2, len(cfg.Layers))
+	require.Equal(t, dgst("d0"), cfg.Layers[0].Blob)
+	require.Equal(t, dgst("d1"), cfg.Layers[1].Blob)
+}
+
 func dgst(s string) digest.Digest {
 	return digest.FromBytes([]byte(s))
 }
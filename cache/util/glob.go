@@ -0,0 +1,107 @@
+package util
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/moby/buildkit/snapshot"
+	"github.com/pkg/errors"
+)
+
+// Glob returns the paths in the mount that match the pattern, relative to the
+// root of the mount. Path elements of the pattern are matched with
+// path.Match, and a "**" element matches any number of directories.
+// Symlinks are not followed.
+func Glob(ctx context.Context, mount snapshot.Mountable, pattern string) ([]string, error) {
+	elems, err := splitGlob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	var matches []string
+	err = withMount(ctx, mount, func(root string) error {
+		return filepath.Walk(root, func(p string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return errors.Wrapf(err, "walking %q", root)
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(root, p)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if rel == "." {
+				return nil
+			}
+			name := strings.Split(filepath.ToSlash(rel), "/")
+			if matchGlob(elems, name) {
+				matches = append(matches, path.Join(name...))
+			}
+			if fi.IsDir() && !matchGlobPrefix(elems, name) {
+				return filepath.SkipDir
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+func splitGlob(pattern string) ([]string, error) {
+	pattern = strings.Trim(path.Clean("/"+pattern), "/")
+	if pattern == "" {
+		return nil, errors.Errorf("invalid empty glob pattern")
+	}
+	elems := strings.Split(pattern, "/")
+	for _, e := range elems {
+		if _, err := path.Match(e, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid glob pattern %q", pattern)
+		}
+	}
+	return elems, nil
+}
+
+// matchGlob reports whether the path elements in name match the pattern
+func matchGlob(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchGlob(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// matchGlobPrefix reports whether paths in the directory name could match
+// the pattern
+func matchGlobPrefix(pattern, name []string) bool {
+	for len(name) > 0 {
+		if len(pattern) == 0 {
+			return false
+		}
+		if pattern[0] == "**" {
+			return true
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(pattern) > 0
+}
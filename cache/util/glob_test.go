@@ -0,0 +1,57 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchGlob(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{"package.json", "package.json", true},
+		{"package.json", "app/package.json", false},
+		{"/*.lock", "yarn.lock", true},
+		{"*.lock", "app/yarn.lock", false},
+		{"**/*.lock", "yarn.lock", true},
+		{"**/*.lock", "app/web/yarn.lock", true},
+		{"app/**", "app", true},
+		{"app/**", "app/web/yarn.lock", true},
+		{"app/**/go.mod", "app/go.mod", true},
+		{"app/**/go.mod", "app/a/b/go.mod", true},
+		{"app/**/go.mod", "lib/a/go.mod", false},
+		{"a/*/c", "a/b/c", true},
+		{"a/*/c", "a/b/b/c", false},
+	}
+	for _, tc := range cases {
+		elems, err := splitGlob(tc.pattern)
+		require.NoError(t, err)
+		require.Equal(t, tc.match, matchGlob(elems, strings.Split(tc.name, "/")), "%s %s", tc.pattern, tc.name)
+	}
+
+	_, err := splitGlob("a/[")
+	require.Error(t, err)
+	_, err = splitGlob("/")
+	require.Error(t, err)
+}
+
+func TestMatchGlobPrefix(t *testing.T) {
+	t.Parallel()
+
+	elems, err := splitGlob("app/*/go.mod")
+	require.NoError(t, err)
+	require.True(t, matchGlobPrefix(elems, []string{"app"}))
+	require.True(t, matchGlobPrefix(elems, []string{"app", "web"}))
+	require.False(t, matchGlobPrefix(elems, []string{"app", "web", "go.mod"}))
+	require.False(t, matchGlobPrefix(elems, []string{"lib"}))
+
+	elems, err = splitGlob("app/**/go.mod")
+	require.NoError(t, err)
+	require.True(t, matchGlobPrefix(elems, []string{"app", "a", "b", "c"}))
+}
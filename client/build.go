@@ -107,6 +107,14 @@ func (g *gatewayClientForBuild) StatFile(ctx context.Context, in *gatewayapi.Sta
 	return g.gateway.StatFile(ctx, in, opts...)
 }
 
+func (g *gatewayClientForBuild) Glob(ctx context.Context, in *gatewayapi.GlobRequest, opts ...grpc.CallOption) (*gatewayapi.GlobResponse, error) {
+	if err := g.caps.Supports(gatewayapi.CapGlob); err != nil {
+		return nil, err
+	}
+	ctx = buildid.AppendToOutgoingContext(ctx, g.buildID)
+	return g.gateway.Glob(ctx, in, opts...)
+}
+
 func (g *gatewayClientForBuild) Ping(ctx context.Context, in *gatewayapi.PingRequest, opts ...grpc.CallOption) (*gatewayapi.PongResponse, error) {
 	ctx = buildid.AppendToOutgoingContext(ctx, g.buildID)
 	return g.gateway.Ping(ctx, in, opts...)
@@ -148,3 +156,27 @@ func (g *gatewayClientForBuild) ExecProcess(ctx context.Context, opts ...grpc.Ca
 	ctx = buildid.AppendToOutgoingContext(ctx, g.buildID)
 	return g.gateway.ExecProcess(ctx, opts...)
 }
+
+func (g *gatewayClientForBuild) MetaSet(ctx context.Context, in *gatewayapi.MetaSetRequest, opts ...grpc.CallOption) (*gatewayapi.MetaSetResponse, error) {
+	if err := g.caps.Supports(gatewayapi.CapGatewayMetadata); err != nil {
+		return nil, err
+	}
+	ctx = buildid.AppendToOutgoingContext(ctx, g.buildID)
+	return g.gateway.MetaSet(ctx, in, opts...)
+}
+
+func (g *gatewayClientForBuild) MetaGet(ctx context.Context, in *gatewayapi.MetaGetRequest, opts ...grpc.CallOption) (*gatewayapi.MetaGetResponse, error) {
+	if err := g.caps.Supports(gatewayapi.CapGatewayMetadata); err != nil {
+		return nil, err
+	}
+	ctx = buildid.AppendToOutgoingContext(ctx, g.buildID)
+	return g.gateway.MetaGet(ctx, in, opts...)
+}
+
+func (g *gatewayClientForBuild) Warn(ctx context.Context, in *gatewayapi.WarnRequest, opts ...grpc.CallOption) (*gatewayapi.WarnResponse, error) {
+	if err := g.caps.Supports(gatewayapi.CapGatewayWarnings); err != nil {
+		return nil, err
+	}
+	ctx = buildid.AppendToOutgoingContext(ctx, g.buildID)
+	return g.gateway.Warn(ctx, in, opts...)
+}
@@ -34,6 +34,8 @@ func TestClientGatewayIntegration(t *testing.T) {
 		testClientGatewaySolve,
 		testClientGatewayFailedSolve,
 		testClientGatewayEmptySolve,
+		testClientGatewayMetadata,
+		testClientGatewayWarnings,
 		testNoBuildID,
 		testUnknownBuildID,
 		testClientGatewayContainerExecPipe,
@@ -171,6 +173,78 @@ func testClientGatewayEmptySolve(t *testing.T, sb integration.Sandbox) {
 	require.NoError(t, err)
 }
 
+func testClientGatewayMetadata(t *testing.T, sb integration.Sandbox) {
+	requiresLinux(t)
+
+	ctx := sb.Context()
+
+	c, err := New(ctx, sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	b := func(ctx context.Context, c client.Client) (*client.Result, error) {
+		_, ok, err := c.MetaGet(ctx, "foo")
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return nil, errors.New("metadata of a previous build is visible")
+		}
+		if err := c.MetaSet(ctx, "foo", []byte("bar")); err != nil {
+			return nil, err
+		}
+		dt, ok, err := c.MetaGet(ctx, "foo")
+		if err != nil {
+			return nil, err
+		}
+		if !ok || string(dt) != "bar" {
+			return nil, errors.Errorf("unexpected metadata value %q", dt)
+		}
+		return client.NewResult(), nil
+	}
+
+	_, err = c.Build(ctx, SolveOpt{}, "", b, nil)
+	require.NoError(t, err)
+
+	_, err = c.Build(ctx, SolveOpt{}, "", b, nil)
+	require.NoError(t, err)
+}
+
+func testClientGatewayWarnings(t *testing.T, sb integration.Sandbox) {
+	requiresLinux(t)
+
+	ctx := sb.Context()
+
+	c, err := New(ctx, sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	b := func(ctx context.Context, c client.Client) (*client.Result, error) {
+		for i := 0; i < 2; i++ {
+			err := c.Warn(ctx, "", "foo is deprecated", client.WarnOpts{
+				Code:   "FooDeprecated",
+				Detail: "use bar",
+				URL:    "https://example.com/foo",
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return client.NewResult(), nil
+	}
+
+	resp, err := c.Build(ctx, SolveOpt{}, "", b, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, len(resp.Warnings))
+	w := resp.Warnings[0]
+	require.Equal(t, "FooDeprecated", w.Code)
+	require.Equal(t, client.WarningLevelWarning, w.Level)
+	require.Equal(t, "foo is deprecated", w.Message)
+	require.Equal(t, "use bar", w.Detail)
+	require.Equal(t, "https://example.com/foo", w.URL)
+}
+
 func testNoBuildID(t *testing.T, sb integration.Sandbox) {
 	requiresLinux(t)
 
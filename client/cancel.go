@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/pkg/errors"
+)
+
+// CancelBuild cancels the active build with the given ref, e.g. a build
+// started by another client. The status stream of the build reports the
+// cancellation and its Solve call returns an error with code Canceled.
+func (c *Client) CancelBuild(ctx context.Context, ref string) error {
+	_, err := c.controlClient().CancelBuild(ctx, &controlapi.CancelBuildRequest{
+		Ref: ref,
+	})
+	return errors.Wrap(err, "failed to cancel build")
+}
+
+// BuildInfo describes an active build of the daemon
+type BuildInfo struct {
+	// Ref is the ref of the build that CancelBuild accepts
+	Ref       string
+	StartedAt time.Time
+	// Frontend is the frontend of the build, empty for LLB definitions
+	Frontend string
+}
+
+// ListBuilds lists the active builds of the daemon by the time they started,
+// including the builds of other clients
+func (c *Client) ListBuilds(ctx context.Context) ([]*BuildInfo, error) {
+	resp, err := c.controlClient().ListBuilds(ctx, &controlapi.ListBuildsRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list builds")
+	}
+	builds := make([]*BuildInfo, 0, len(resp.Builds))
+	for _, b := range resp.Builds {
+		builds = append(builds, &BuildInfo{
+			Ref:       b.Ref,
+			StartedAt: b.StartedAt,
+			Frontend:  b.Frontend,
+		})
+	}
+	return builds, nil
+}
@@ -154,6 +154,12 @@ func (c *Client) controlClient() controlapi.ControlClient {
 	return controlapi.NewControlClient(c.conn)
 }
 
+// Conn returns the connection of the client to the daemon, e.g. for the
+// services of the daemon that don't have a client method
+func (c *Client) Conn() *grpc.ClientConn {
+	return c.conn
+}
+
 func (c *Client) Dialer() session.Dialer {
 	return grpchijack.Dialer(c.controlClient())
 }
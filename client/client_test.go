@@ -40,15 +40,18 @@ import (
 	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/buildkit/util/contentutil"
 	"github.com/moby/buildkit/util/entitlements"
+	"github.com/moby/buildkit/util/grpcerrors"
 	"github.com/moby/buildkit/util/testutil"
 	"github.com/moby/buildkit/util/testutil/echoserver"
 	"github.com/moby/buildkit/util/testutil/httpserver"
 	"github.com/moby/buildkit/util/testutil/integration"
+	digest "github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/ssh/agent"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
 )
 
 func init() {
@@ -86,6 +89,7 @@ func TestIntegration(t *testing.T) {
 		testResolveAndHosts,
 		testUser,
 		testOCIExporter,
+		testOCIExporterContentStore,
 		testWhiteoutParentDir,
 		testFrontendImageNaming,
 		testDuplicateWhiteouts,
@@ -106,21 +110,37 @@ func TestIntegration(t *testing.T) {
 		testRunCacheWithMounts,
 		testParallelLocalBuilds,
 		testSecretMounts,
+		testSecretEnv,
+		testExecUmask,
+		testBuildDeadline,
 		testExtraHosts,
 		testNetworkMode,
 		testFrontendMetadataReturn,
+		testBuildLabels,
+		testExportLayerSizes,
+		testEvents,
+		testIgnoreForCache,
+		testExecRetry,
+		testExecUserNSMapping,
+		testExecSysctl,
+		testCancelBuild,
+		testCacheMountStats,
 		testFrontendUseSolveResults,
 		testSSHMount,
 		testStdinClosed,
 		testHostnameLookup,
 		testHostnameSpecifying,
 		testPushByDigest,
+		testPushAttestationReferrers,
+		testPushVerify,
 		testBasicInlineCacheImportExport,
 		testExportBusyboxLocal,
 		testBridgeNetworking,
 		testCacheMountNoCache,
 		testExporterTargetExists,
+		testExportOutputPipe,
 		testTarExporterWithSocket,
+		testTarExporterWithProvenance,
 		testTarExporterWithSocketCopy,
 		testTarExporterSymlink,
 		testMultipleRegistryCacheImportExport,
@@ -131,6 +151,9 @@ func TestIntegration(t *testing.T) {
 		testFileOpInputSwap,
 		testRelativeMountpoint,
 		testLocalSourceDiffer,
+		testEstimateBuildSize,
+		testSquashLocalChange,
+		testSharedPID,
 	}, mirrors)
 
 	integration.Run(t, []integration.Test{
@@ -599,6 +622,142 @@ func testPushByDigest(t *testing.T, sb integration.Sandbox) {
 	require.True(t, desc.Size > 0)
 }
 
+func testPushAttestationReferrers(t *testing.T, sb integration.Sandbox) {
+	skipDockerd(t, sb)
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	registry, err := sb.NewRegistry()
+	if errors.Is(err, integration.ErrorRequirements) {
+		t.Skip(err.Error())
+	}
+	require.NoError(t, err)
+
+	// the frontend returns an SBOM that is pushed next to the provenance
+	frontend := func(ctx context.Context, c gateway.Client) (*gateway.Result, error) {
+		st := llb.Scratch().File(llb.Mkfile("foo", 0600, []byte("attest")))
+		def, err := st.Marshal(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res, err := c.Solve(ctx, gateway.SolveRequest{
+			Definition: def.ToPB(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		sbom, err := json.Marshal(map[string]interface{}{
+			"predicateType": "https://spdx.dev/Document",
+			"predicate":     map[string]string{"spdxVersion": "SPDX-2.2"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		res.AddMeta("attestation.sbom", sbom)
+		return res, nil
+	}
+
+	name := registry + "/foo/attest:latest"
+
+	resp, err := c.Build(sb.Context(), SolveOpt{
+		Exports: []ExportEntry{
+			{
+				Type: "image",
+				Attrs: map[string]string{
+					"name":             name,
+					"push":             "true",
+					"attest-referrers": "true",
+				},
+			},
+		},
+	}, "", frontend, nil)
+	require.NoError(t, err)
+
+	dgst, err := digest.Parse(resp.ExporterResponse["containerimage.digest"])
+	require.NoError(t, err)
+
+	// the registry has no referrers API so the attestation is in the index
+	// tagged with the digest of the image
+	desc, provider, err := contentutil.ProviderFromRef(registry + "/foo/attest:sha256-" + dgst.Hex())
+	require.NoError(t, err)
+	require.Equal(t, ocispec.MediaTypeImageIndex, desc.MediaType)
+
+	dt, err := content.ReadBlob(sb.Context(), provider, desc)
+	require.NoError(t, err)
+	var idx ocispec.Index
+	require.NoError(t, json.Unmarshal(dt, &idx))
+	require.Equal(t, 2, len(idx.Manifests))
+
+	var predicateTypes []string
+	for _, m := range idx.Manifests {
+		dt, err = content.ReadBlob(sb.Context(), provider, m)
+		require.NoError(t, err)
+		var mfst struct {
+			ocispec.Manifest
+			ArtifactType string              `json:"artifactType"`
+			Subject      *ocispec.Descriptor `json:"subject"`
+		}
+		require.NoError(t, json.Unmarshal(dt, &mfst))
+		require.Equal(t, "application/vnd.in-toto+json", mfst.ArtifactType)
+		require.Equal(t, dgst, mfst.Subject.Digest)
+		require.Equal(t, 1, len(mfst.Layers))
+
+		dt, err = content.ReadBlob(sb.Context(), provider, mfst.Layers[0])
+		require.NoError(t, err)
+		var stmt struct {
+			PredicateType string `json:"predicateType"`
+			Subject       []struct {
+				Digest map[string]string `json:"digest"`
+			} `json:"subject"`
+		}
+		require.NoError(t, json.Unmarshal(dt, &stmt))
+		require.Equal(t, 1, len(stmt.Subject))
+		require.Equal(t, dgst.Hex(), stmt.Subject[0].Digest["sha256"])
+		predicateTypes = append(predicateTypes, stmt.PredicateType)
+	}
+	require.Equal(t, []string{"https://slsa.dev/provenance/v0.2", "https://spdx.dev/Document"}, predicateTypes)
+}
+
+func testPushVerify(t *testing.T, sb integration.Sandbox) {
+	skipDockerd(t, sb)
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	registry, err := sb.NewRegistry()
+	if errors.Is(err, integration.ErrorRequirements) {
+		t.Skip(err.Error())
+	}
+	require.NoError(t, err)
+
+	st := llb.Scratch().File(llb.Mkfile("foo", 0600, []byte("verify")))
+
+	def, err := st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	target := registry + "/buildkit/testpushverify:latest"
+	resp, err := c.Solve(sb.Context(), def, SolveOpt{
+		Exports: []ExportEntry{
+			{
+				Type: "image",
+				Attrs: map[string]string{
+					"name":   target,
+					"push":   "true",
+					"verify": "true",
+				},
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	desc, _, err := contentutil.ProviderFromRef(target)
+	require.NoError(t, err)
+	require.Equal(t, resp.ExporterResponse["containerimage.digest"], desc.Digest.String())
+}
+
 func testSecurityMode(t *testing.T, sb integration.Sandbox) {
 	var command string
 	mode := llb.SecurityModeSandbox
@@ -869,6 +1028,88 @@ func testFrontendImageNaming(t *testing.T, sb integration.Sandbox) {
 	checkAllReleasable(t, c, sb, true)
 }
 
+func testSecretEnv(t *testing.T, sb integration.Sandbox) {
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	st := llb.Image("busybox:latest").
+		Run(llb.Shlex(`sh -c '[ "$FOO_TOKEN" = "foo-secret" ] && [ -z "${BAR+x}" ] && ! ls /run/secrets'`),
+			llb.AddSecretEnv("token", "FOO_TOKEN"), llb.AddSecretEnv("bar", "BAR", llb.SecretOptional))
+
+	def, err := st.Marshal(sb.Context())
+	require.NoError(t, err)
+	for _, dt := range def.Def {
+		require.NotContains(t, string(dt), "foo-secret")
+	}
+
+	_, err = c.Solve(sb.Context(), def, SolveOpt{
+		Session: []session.Attachable{secretsprovider.FromMap(map[string][]byte{
+			"token": []byte("foo-secret"),
+		})},
+	}, nil)
+	require.NoError(t, err)
+
+	st = llb.Image("busybox:latest").
+		Run(llb.Shlex(`echo secret`), llb.AddSecretEnv("missing", "MISSING"))
+
+	def, err = st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	_, err = c.Solve(sb.Context(), def, SolveOpt{
+		Session: []session.Attachable{secretsprovider.FromMap(map[string][]byte{})},
+	}, nil)
+	require.Error(t, err)
+}
+
+func testExecUmask(t *testing.T, sb integration.Sandbox) {
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	st := llb.Image("busybox:latest").
+		Run(llb.Shlex(`sh -c '[ "$(umask)" = "0027" ] && touch /foo && [ "$(stat -c %a /foo)" = "640" ]'`), llb.WithUmask(0027))
+
+	def, err := st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	_, err = c.Solve(sb.Context(), def, SolveOpt{}, nil)
+	require.NoError(t, err)
+
+	st = llb.Image("busybox:latest").
+		Run(llb.Shlex(`true`), llb.WithUmask(01022))
+
+	def, err = st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	_, err = c.Solve(sb.Context(), def, SolveOpt{}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid umask")
+}
+
+func testBuildDeadline(t *testing.T, sb integration.Sandbox) {
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	st := llb.Image("busybox:latest").
+		Run(llb.Shlex(`sleep 60`), llb.IgnoreCache, llb.WithCustomName("sleeping"))
+
+	def, err := st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = c.Solve(sb.Context(), def, SolveOpt{
+		Deadline: time.Now().Add(5 * time.Second),
+	}, nil)
+	require.Error(t, err)
+	require.True(t, time.Since(start) < 30*time.Second)
+	require.Equal(t, codes.DeadlineExceeded, grpcerrors.Code(err))
+	require.Contains(t, err.Error(), "build deadline exceeded")
+	require.Contains(t, err.Error(), "running: sleeping")
+}
+
 func testSecretMounts(t *testing.T, sb integration.Sandbox) {
 	c, err := New(sb.Context(), sb.Address())
 	require.NoError(t, err)
@@ -1001,6 +1242,112 @@ func testLocalSymlinkEscape(t *testing.T, sb integration.Sandbox) {
 	require.NoError(t, err)
 }
 
+func testSquashLocalChange(t *testing.T, sb integration.Sandbox) {
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	dir, err := tmpdir(fstest.CreateFile("foo", []byte("foo0"), 0600))
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	st := llb.Scratch().
+		File(llb.Copy(llb.Local("mylocal"), "foo", "bar")).
+		File(llb.Mkfile("baz", 0600, []byte("baz"))).
+		Squash()
+	def, err := st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	for _, content := range []string{"foo0", "foo1"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "foo"), []byte(content), 0600))
+
+		destDir, err := ioutil.TempDir("", "buildkit")
+		require.NoError(t, err)
+		defer os.RemoveAll(destDir)
+
+		_, err = c.Solve(sb.Context(), def, SolveOpt{
+			LocalDirs: map[string]string{
+				"mylocal": dir,
+			},
+			Exports: []ExportEntry{
+				{
+					Type:      ExporterLocal,
+					OutputDir: destDir,
+				},
+			},
+		}, nil)
+		require.NoError(t, err)
+
+		// a changed local file invalidates the cached result of the subgraph
+		dt, err := ioutil.ReadFile(filepath.Join(destDir, "bar"))
+		require.NoError(t, err)
+		require.Equal(t, content, string(dt))
+	}
+}
+
+func testSharedPID(t *testing.T, sb integration.Sandbox) {
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	busybox := llb.Image("busybox:latest")
+	daemon := busybox.Run(llb.Shlex(`sleep 1000`),
+		llb.WithProxy(llb.ProxyEnv{HTTPProxy: "http://proxy.test:3128"}),
+		llb.AddSecretEnv("token", "TOKEN"))
+
+	// the daemon is not the init process of the namespace and its env is
+	// visible to the process
+	st := busybox.Run(llb.Shlex(`sh -c 'set -e; pid=$(pidof sleep); test "$pid" != 1; tr "\0" "\n" < /proc/$pid/environ > /out/env'`),
+		llb.WithSharedPID(daemon)).AddMount("/out", llb.Scratch())
+
+	def, err := st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	destDir, err := ioutil.TempDir("", "buildkit")
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+
+	_, err = c.Solve(sb.Context(), def, SolveOpt{
+		Session: []session.Attachable{secretsprovider.FromMap(map[string][]byte{
+			"token": []byte("secret"),
+		})},
+		Exports: []ExportEntry{
+			{
+				Type:      ExporterLocal,
+				OutputDir: destDir,
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	dt, err := ioutil.ReadFile(filepath.Join(destDir, "env"))
+	require.NoError(t, err)
+	require.Contains(t, string(dt), "HTTP_PROXY=http://proxy.test:3128\n")
+	require.Contains(t, string(dt), "TOKEN=secret\n")
+
+	// the process keeps running when the daemon exits first
+	st = busybox.Run(llb.Shlex(`sh -c 'sleep 1; echo ok > /out/ok'`),
+		llb.WithSharedPID(busybox.Run(llb.Shlex(`true`)))).AddMount("/out", llb.Scratch())
+	def, err = st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	_, err = c.Solve(sb.Context(), def, SolveOpt{
+		Exports: []ExportEntry{
+			{
+				Type:      ExporterLocal,
+				OutputDir: destDir,
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	dt, err = ioutil.ReadFile(filepath.Join(destDir, "ok"))
+	require.NoError(t, err)
+	require.Equal(t, "ok\n", string(dt))
+}
+
 func testRelativeWorkDir(t *testing.T, sb integration.Sandbox) {
 	requiresLinux(t)
 	c, err := New(sb.Context(), sb.Address())
@@ -1419,6 +1766,47 @@ func testCallDiskUsage(t *testing.T, sb integration.Sandbox) {
 	require.NoError(t, err)
 }
 
+func testEstimateBuildSize(t *testing.T, sb integration.Sandbox) {
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	busybox := llb.Image("busybox:latest")
+	st := busybox.Run(llb.Shlex(`sh -c "head -c 100000 /dev/urandom > /foo"`)).Root().
+		File(llb.Mkfile("/bar", 0600, []byte("bar")))
+
+	def, err := st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	est, err := c.EstimateBuildSize(sb.Context(), def)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(est.Vertexes))
+	require.True(t, est.Sources > 0)
+	require.Equal(t, int64(3), est.Estimated)
+
+	_, err = c.Solve(sb.Context(), def, SolveOpt{}, nil)
+	require.NoError(t, err)
+
+	// the vertexes of the previous build are cache hits
+	est, err = c.EstimateBuildSize(sb.Context(), def)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(est.Vertexes))
+	for _, v := range est.Vertexes {
+		require.True(t, v.Cached, v.Name)
+	}
+	require.Equal(t, int64(0), est.Sources)
+	require.Equal(t, int64(0), est.Estimated)
+
+	// a new copy of the output of the cached exec counts the size of the output
+	run := busybox.Run(llb.Shlex(`sh -c "head -c 100000 /dev/urandom > /foo"`)).Root()
+	def, err = llb.Scratch().File(llb.Copy(run, "/foo", "/foo")).Marshal(sb.Context())
+	require.NoError(t, err)
+	est, err = c.EstimateBuildSize(sb.Context(), def)
+	require.NoError(t, err)
+	require.True(t, est.Estimated >= 100000)
+}
+
 func testBuildMultiMount(t *testing.T, sb integration.Sandbox) {
 	requiresLinux(t)
 	c, err := New(sb.Context(), sb.Address())
@@ -1633,7 +2021,7 @@ func testUser(t *testing.T, sb integration.Sandbox) {
 	checkAllReleasable(t, c, sb, true)
 }
 
-func testOCIExporter(t *testing.T, sb integration.Sandbox) {
+func testOCIExporterContentStore(t *testing.T, sb integration.Sandbox) {
 	skipDockerd(t, sb)
 	requiresLinux(t)
 	c, err := New(sb.Context(), sb.Address())
@@ -1654,8 +2042,66 @@ func testOCIExporter(t *testing.T, sb integration.Sandbox) {
 	require.NoError(t, err)
 
 	for _, exp := range []string{ExporterOCI, ExporterDocker} {
+		store := contentutil.NewMemoryStore()
+		resp, err := c.Solve(sb.Context(), def, SolveOpt{
+			Exports: []ExportEntry{
+				{
+					Type:        exp,
+					OutputStore: store,
+				},
+			},
+		}, nil)
+		require.NoError(t, err)
 
-		destDir, err := ioutil.TempDir("", "buildkit")
+		dgst, err := digest.Parse(resp.ExporterResponse["containerimage.digest"])
+		require.NoError(t, err)
+
+		dt, err := content.ReadBlob(sb.Context(), store, ocispec.Descriptor{Digest: dgst})
+		require.NoError(t, err)
+
+		var mfst ocispec.Manifest
+		err = json.Unmarshal(dt, &mfst)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(mfst.Layers))
+
+		dt, err = content.ReadBlob(sb.Context(), store, mfst.Config)
+		require.NoError(t, err)
+
+		var ociimg ocispec.Image
+		err = json.Unmarshal(dt, &ociimg)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(ociimg.RootFS.DiffIDs))
+
+		for _, l := range mfst.Layers {
+			_, err := store.Info(sb.Context(), l.Digest)
+			require.NoError(t, err)
+		}
+	}
+}
+
+func testOCIExporter(t *testing.T, sb integration.Sandbox) {
+	skipDockerd(t, sb)
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	busybox := llb.Image("busybox:latest")
+	st := llb.Scratch()
+
+	run := func(cmd string) {
+		st = busybox.Run(llb.Shlex(cmd), llb.Dir("/wd")).AddMount("/wd", st)
+	}
+
+	run(`sh -c "echo -n first > foo"`)
+	run(`sh -c "echo -n second > bar"`)
+
+	def, err := st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	for _, exp := range []string{ExporterOCI, ExporterDocker} {
+
+		destDir, err := ioutil.TempDir("", "buildkit")
 		require.NoError(t, err)
 		defer os.RemoveAll(destDir)
 
@@ -1769,6 +2215,434 @@ func testFrontendMetadataReturn(t *testing.T, sb integration.Sandbox) {
 	checkAllReleasable(t, c, sb, true)
 }
 
+func testBuildLabels(t *testing.T, sb integration.Sandbox) {
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	st := llb.Scratch().File(llb.Mkfile("foo", 0600, []byte("data"))).WithBuildLabel("team", "core")
+	def, err := st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	destDir, err := ioutil.TempDir("", "buildkit")
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+
+	ch := make(chan *SolveStatus)
+	statusDone := make(chan struct{})
+	var labels []map[string]string
+	go func() {
+		defer close(statusDone)
+		for ss := range ch {
+			for _, v := range ss.Vertexes {
+				labels = append(labels, v.Labels)
+			}
+		}
+	}()
+
+	res, err := c.Solve(sb.Context(), def, SolveOpt{
+		Exports: []ExportEntry{
+			{
+				Type:      ExporterLocal,
+				OutputDir: destDir,
+			},
+		},
+	}, ch)
+	require.NoError(t, err)
+	<-statusDone
+	require.Equal(t, "core", res.ExporterResponse["llb.buildlabel.team"])
+	require.Contains(t, labels, map[string]string{"team": "core"})
+	checkAllReleasable(t, c, sb, true)
+}
+
+func testExportLayerSizes(t *testing.T, sb integration.Sandbox) {
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	st := llb.Scratch().File(llb.Mkfile("foo", 0600, []byte("data")))
+	def, err := st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	var op pb.Op
+	require.NoError(t, op.Unmarshal(def.Def[len(def.Def)-1]))
+	fileDgst := op.Inputs[0].Digest
+
+	res, err := c.Solve(sb.Context(), def, SolveOpt{
+		Exports: []ExportEntry{
+			{
+				Type:   ExporterTar,
+				Attrs:  map[string]string{"layer-sizes": "true"},
+				Output: fixedWriteCloser(nopWriteCloser{ioutil.Discard}),
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	var sizes []struct {
+		Digest           digest.Digest `json:"digest"`
+		DiffID           digest.Digest `json:"diffID"`
+		Size             int64         `json:"size"`
+		UncompressedSize int64         `json:"uncompressedSize"`
+		Vertex           digest.Digest `json:"vertex"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(res.ExporterResponse["layer.sizes"]), &sizes))
+	require.Equal(t, 1, len(sizes))
+	require.Equal(t, fileDgst, sizes[0].Vertex)
+	require.NotEqual(t, "", string(sizes[0].DiffID))
+	require.True(t, sizes[0].Size > 0)
+	require.True(t, sizes[0].UncompressedSize > int64(len("data")))
+	checkAllReleasable(t, c, sb, true)
+}
+
+func testEvents(t *testing.T, sb integration.Sandbox) {
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(sb.Context())
+	defer cancel()
+	events, err := c.Subscribe(ctx)
+	require.NoError(t, err)
+
+	def, err := llb.Scratch().File(llb.Mkfile("foo", 0600, []byte("data"))).Marshal(sb.Context())
+	require.NoError(t, err)
+
+	// the subscription may start after the call returns
+	time.Sleep(100 * time.Millisecond)
+
+	_, err = c.Solve(sb.Context(), def, SolveOpt{}, nil)
+	require.NoError(t, err)
+
+	var ref string
+	var types []EventType
+	for ev := range events {
+		if ev.Type == EventBuildStarted && ref == "" {
+			ref = ev.Ref
+		}
+		if ref == "" || ev.Ref != ref {
+			continue
+		}
+		types = append(types, ev.Type)
+		if ev.Type == EventBuildFinished {
+			require.Equal(t, "", ev.Error)
+			break
+		}
+	}
+	require.NotEqual(t, "", ref)
+	require.Equal(t, []EventType{EventBuildStarted, EventBuildFinished}, types)
+}
+
+func testIgnoreForCache(t *testing.T, sb integration.Sandbox) {
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	busybox := llb.Image("busybox:latest")
+
+	build := func(id string) (string, string) {
+		// the log differs on every run but is not part of the cache key of
+		// the step that uses the output
+		gen := busybox.Run(llb.Shlexf(`sh -c "mkdir -p /out && echo data > /out/data && echo %s > /out/log"`, id),
+			llb.IgnoreForCache("/out/log")).Root()
+
+		run := busybox.Run(llb.Shlex(`sh -c "cat /src/out/log > /dst/log && cat /dev/urandom | head -c 100 | md5sum > /dst/rand"`),
+			llb.AddMount("/src", gen, llb.Readonly))
+		def, err := run.AddMount("/dst", llb.Scratch()).Marshal(sb.Context())
+		require.NoError(t, err)
+
+		destDir, err := ioutil.TempDir("", "buildkit")
+		require.NoError(t, err)
+		defer os.RemoveAll(destDir)
+
+		_, err = c.Solve(sb.Context(), def, SolveOpt{
+			Exports: []ExportEntry{
+				{
+					Type:      ExporterLocal,
+					OutputDir: destDir,
+				},
+			},
+		}, nil)
+		require.NoError(t, err)
+
+		log, err := ioutil.ReadFile(filepath.Join(destDir, "log"))
+		require.NoError(t, err)
+		rand, err := ioutil.ReadFile(filepath.Join(destDir, "rand"))
+		require.NoError(t, err)
+		return string(log), string(rand)
+	}
+
+	log1, rand1 := build(identity.NewID())
+	log2, rand2 := build(identity.NewID())
+
+	// the second build reused the cache of the step that reads the output,
+	// including the log of the first build
+	require.Equal(t, rand1, rand2)
+	require.Equal(t, log1, log2)
+	require.NotEqual(t, "", log1)
+	checkAllReleasable(t, c, sb, true)
+}
+
+func testExecRetry(t *testing.T, sb integration.Sandbox) {
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	busybox := llb.Image("busybox:latest")
+
+	// the first attempt fails after writing to the root filesystem. The
+	// second attempt must not see the changes of the first one.
+	st := busybox.Run(llb.Shlexf(`sh -c "if [ -f /cache/%s ]; then test ! -f /marker && echo ok > /out/result; else touch /cache/%[1]s /marker; exit 3; fi"`, identity.NewID()),
+		llb.AddMount("/cache", llb.Scratch(), llb.AsPersistentCacheDir(identity.NewID(), llb.CacheMountShared)),
+		llb.WithRetry(2, []int{3}))
+	def, err := st.AddMount("/out", llb.Scratch()).Marshal(sb.Context())
+	require.NoError(t, err)
+
+	destDir, err := ioutil.TempDir("", "buildkit")
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+
+	ch := make(chan *SolveStatus)
+	statusDone := make(chan struct{})
+	var attempts []*Vertex
+	go func() {
+		defer close(statusDone)
+		for ss := range ch {
+			for _, v := range ss.Vertexes {
+				if strings.HasPrefix(v.Name, "[attempt ") && v.Completed != nil {
+					attempts = append(attempts, v)
+				}
+			}
+		}
+	}()
+
+	_, err = c.Solve(sb.Context(), def, SolveOpt{
+		Exports: []ExportEntry{
+			{
+				Type:      ExporterLocal,
+				OutputDir: destDir,
+			},
+		},
+	}, ch)
+	require.NoError(t, err)
+	<-statusDone
+
+	dt, err := ioutil.ReadFile(filepath.Join(destDir, "result"))
+	require.NoError(t, err)
+	require.Equal(t, "ok\n", string(dt))
+
+	// the retry is reported as a vertex of its own
+	require.Len(t, attempts, 1)
+	require.True(t, strings.HasPrefix(attempts[0].Name, "[attempt 2/3] "), attempts[0].Name)
+	require.Empty(t, attempts[0].Error)
+
+	// other exit codes are not retried
+	def, err = busybox.Run(llb.Shlexf(`sh -c "if [ -f /cache/%s ]; then exit 0; else touch /cache/%[1]s; exit 4; fi"`, identity.NewID()),
+		llb.AddMount("/cache", llb.Scratch(), llb.AsPersistentCacheDir(identity.NewID(), llb.CacheMountShared)),
+		llb.WithRetry(2, []int{3})).Root().Marshal(sb.Context())
+	require.NoError(t, err)
+
+	_, err = c.Solve(sb.Context(), def, SolveOpt{}, nil)
+	require.Error(t, err)
+	checkAllReleasable(t, c, sb, true)
+}
+
+func testExecUserNSMapping(t *testing.T, sb integration.Sandbox) {
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	// the files owned by root of the daemon are owned by uid 1 of the
+	// process
+	run := func(uidMap []llb.IDMap) error {
+		def, err := llb.Image("busybox:latest").Run(llb.Shlex(`sh -c "test $(stat -c %u /bin/busybox) = 1"`),
+			llb.WithUserNSMapping(uidMap, []llb.IDMap{{ContainerID: 0, HostID: 0, Size: 1}})).Root().Marshal(sb.Context())
+		require.NoError(t, err)
+		_, err = c.Solve(sb.Context(), def, SolveOpt{}, nil)
+		return err
+	}
+
+	err = run([]llb.IDMap{{ContainerID: 0, HostID: 1000, Size: 1}, {ContainerID: 1, HostID: 0, Size: 1}})
+	if !sb.Rootless() {
+		require.Error(t, err)
+		return
+	}
+	require.NoError(t, err)
+
+	err = run([]llb.IDMap{{ContainerID: 0, HostID: 1000, Size: 2}, {ContainerID: 1, HostID: 0, Size: 1}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "overlapping container ranges")
+}
+
+func testExecSysctl(t *testing.T, sb integration.Sandbox) {
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	// the test daemons don't allow any sysctls
+	def, err := llb.Image("busybox:latest").Run(llb.Shlex(`sh -c "test $(cat /proc/sys/net/core/somaxconn) = 1024"`),
+		llb.WithSysctl("net.core.somaxconn", "1024")).Root().Marshal(sb.Context())
+	require.NoError(t, err)
+	_, err = c.Solve(sb.Context(), def, SolveOpt{}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "setting sysctl net.core.somaxconn is not allowed by the daemon configuration")
+}
+
+func testCancelBuild(t *testing.T, sb integration.Sandbox) {
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	// the build is cancelled by another client
+	c2, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c2.Close()
+
+	ctx, cancel := context.WithCancel(sb.Context())
+	defer cancel()
+	events, err := c2.Subscribe(ctx)
+	require.NoError(t, err)
+
+	def, err := llb.Image("busybox:latest").Run(llb.Shlex("sleep 60")).Root().Marshal(sb.Context())
+	require.NoError(t, err)
+
+	ch := make(chan *SolveStatus)
+	var vertexes []*Vertex
+	statusDone := make(chan struct{})
+	go func() {
+		defer close(statusDone)
+		for ss := range ch {
+			vertexes = append(vertexes, ss.Vertexes...)
+		}
+	}()
+	solveErr := make(chan error, 1)
+	go func() {
+		_, err := c.Solve(sb.Context(), def, SolveOpt{}, ch)
+		solveErr <- err
+	}()
+
+	var ref string
+	for ev := range events {
+		if ev.Type == EventBuildStarted {
+			ref = ev.Ref
+			break
+		}
+	}
+	require.NotEmpty(t, ref)
+
+	// the build of the other client is listed once its job is created
+	var listed bool
+	for i := 0; i < 50 && !listed; i++ {
+		builds, err := c2.ListBuilds(sb.Context())
+		require.NoError(t, err)
+		for _, b := range builds {
+			if b.Ref == ref {
+				require.False(t, b.StartedAt.IsZero())
+				require.Equal(t, "", b.Frontend)
+				listed = true
+			}
+		}
+		if !listed {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	require.True(t, listed)
+
+	for i := 0; ; i++ {
+		err = c2.CancelBuild(sb.Context(), ref)
+		if err == nil || grpcerrors.Code(err) != codes.NotFound || i == 50 {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	require.NoError(t, err)
+
+	select {
+	case err = <-solveErr:
+	case <-time.After(30 * time.Second):
+		t.Fatal("build was not cancelled")
+	}
+	require.Error(t, err)
+	require.Equal(t, codes.Canceled, grpcerrors.Code(err))
+	require.Contains(t, err.Error(), "build cancelled")
+
+	builds, err := c2.ListBuilds(sb.Context())
+	require.NoError(t, err)
+	for _, b := range builds {
+		require.NotEqual(t, ref, b.Ref)
+	}
+
+	<-statusDone
+	var found bool
+	for _, v := range vertexes {
+		if v.Name == "build cancelled" {
+			found = true
+			require.NotEmpty(t, v.Error)
+		}
+	}
+	require.True(t, found)
+
+	err = c2.CancelBuild(sb.Context(), ref)
+	require.Error(t, err)
+	require.Equal(t, codes.NotFound, grpcerrors.Code(err))
+}
+
+func testCacheMountStats(t *testing.T, sb integration.Sandbox) {
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	busybox := llb.Image("busybox:latest")
+	id := identity.NewID()
+
+	build := func(cmd string, enabled bool) []*CacheMountStats {
+		st := busybox.Run(llb.Shlex(cmd),
+			llb.AddMount("/cache", llb.Scratch(), llb.AsPersistentCacheDir(id, llb.CacheMountShared)))
+		def, err := st.Root().Marshal(sb.Context())
+		require.NoError(t, err)
+
+		ch := make(chan *SolveStatus)
+		var stats []*CacheMountStats
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for ss := range ch {
+				stats = append(stats, ss.CacheMounts...)
+			}
+		}()
+		_, err = c.Solve(sb.Context(), def, SolveOpt{CacheMountStats: enabled}, ch)
+		require.NoError(t, err)
+		<-done
+		return stats
+	}
+
+	// the stats are opt-in
+	stats := build(`sh -c "head -c 100 /dev/urandom > /cache/baz"`, false)
+	require.Equal(t, 0, len(stats))
+
+	stats = build(`sh -c "rm /cache/baz && head -c 1000 /dev/urandom > /cache/foo"`, true)
+	require.Equal(t, 1, len(stats))
+	s := stats[0]
+	require.Equal(t, id, s.ID)
+	require.Equal(t, "/cache", s.Target)
+	require.Equal(t, int64(0), s.UnchangedBytes)
+	require.Equal(t, int64(1000), s.WrittenBytes)
+
+	stats = build(`sh -c "cat /cache/foo > /dev/null && head -c 10 /dev/urandom > /cache/bar"`, true)
+	require.Equal(t, 1, len(stats))
+	s = stats[0]
+	require.Equal(t, int64(1000), s.UnchangedBytes)
+	require.Equal(t, int64(10), s.WrittenBytes)
+}
+
 func testFrontendUseSolveResults(t *testing.T, sb integration.Sandbox) {
 	requiresLinux(t)
 	c, err := New(sb.Context(), sb.Address())
@@ -1904,6 +2778,58 @@ func testTarExporterWithSocket(t *testing.T, sb integration.Sandbox) {
 	require.NoError(t, err)
 }
 
+func testTarExporterWithProvenance(t *testing.T, sb integration.Sandbox) {
+	requiresLinux(t)
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	def, err := llb.Scratch().File(llb.Mkfile("foo", 0600, []byte("data"))).Marshal(sb.Context())
+	require.NoError(t, err)
+
+	var tarBuf, provBuf bytes.Buffer
+	res, err := c.Solve(sb.Context(), def, SolveOpt{
+		FrontendAttrs: map[string]string{"build-arg:TOKEN": "secret"},
+		Exports: []ExportEntry{
+			{
+				Type: ExporterTar,
+				Output: func(m map[string]string) (io.WriteCloser, error) {
+					return nopWriteCloser{&tarBuf}, nil
+				},
+			},
+			{
+				Type: ExporterProvenance,
+				Output: func(m map[string]string) (io.WriteCloser, error) {
+					return nopWriteCloser{&provBuf}, nil
+				},
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	tarDgst := digest.FromBytes(tarBuf.Bytes())
+	require.Equal(t, tarDgst.String(), res.ExporterResponse["tar.digest"])
+
+	var st struct {
+		Subject []struct {
+			Name   string            `json:"name"`
+			Digest map[string]string `json:"digest"`
+		} `json:"subject"`
+		Predicate struct {
+			Invocation struct {
+				Parameters struct {
+					Args map[string]string `json:"args"`
+				} `json:"parameters"`
+			} `json:"invocation"`
+		} `json:"predicate"`
+	}
+	require.NoError(t, json.Unmarshal(provBuf.Bytes(), &st))
+	require.Len(t, st.Subject, 1)
+	require.Equal(t, "tar", st.Subject[0].Name)
+	require.Equal(t, tarDgst.Encoded(), st.Subject[0].Digest["sha256"])
+	require.Equal(t, "<redacted>", st.Predicate.Invocation.Parameters.Args["build-arg:TOKEN"])
+}
+
 func testTarExporterWithSocketCopy(t *testing.T, sb integration.Sandbox) {
 	if os.Getenv("TEST_DOCKERD") == "1" {
 		t.Skip("tar exporter is temporarily broken on dockerd")
@@ -1972,6 +2898,76 @@ func testTarExporterSymlink(t *testing.T, sb integration.Sandbox) {
 	require.Equal(t, "foo", item.Header.Linkname)
 }
 
+func testExportOutputPipe(t *testing.T, sb integration.Sandbox) {
+	c, err := New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	st := llb.Scratch().File(llb.Mkfile("foo", 0600, []byte("first")))
+	def, err := st.Marshal(sb.Context())
+	require.NoError(t, err)
+
+	pr, pw := io.Pipe()
+	eg, ctx := errgroup.WithContext(sb.Context())
+	var dt []byte
+	eg.Go(func() error {
+		var err error
+		dt, err = ioutil.ReadAll(pr)
+		return err
+	})
+	eg.Go(func() error {
+		_, err := c.Solve(ctx, def, SolveOpt{
+			Exports: []ExportEntry{
+				{
+					Type:       ExporterTar,
+					OutputPipe: pw,
+				},
+			},
+		}, nil)
+		return err
+	})
+	require.NoError(t, eg.Wait())
+
+	m, err := testutil.ReadTarToMap(dt, false)
+	require.NoError(t, err)
+	item, ok := m["foo"]
+	require.True(t, ok)
+	require.Equal(t, []byte("first"), item.Data)
+
+	// the reader gets the error of a failed build
+	def, err = llb.Image("busybox:latest").Run(llb.Shlex("false")).Root().Marshal(sb.Context())
+	require.NoError(t, err)
+
+	pr, pw = io.Pipe()
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := c.Solve(sb.Context(), def, SolveOpt{
+			Exports: []ExportEntry{
+				{
+					Type:       ExporterTar,
+					OutputPipe: pw,
+				},
+			},
+		}, nil)
+		errCh <- err
+	}()
+	_, err = ioutil.ReadAll(pr)
+	require.Error(t, err)
+	require.Error(t, <-errCh)
+
+	_, err = c.Solve(sb.Context(), def, SolveOpt{
+		Exports: []ExportEntry{
+			{
+				Type:       ExporterTar,
+				Output:     fixedWriteCloser(nopWriteCloser{ioutil.Discard}),
+				OutputPipe: pw,
+			},
+		},
+	}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't be used together")
+}
+
 func testBuildExportWithUncompressed(t *testing.T, sb integration.Sandbox) {
 	if os.Getenv("TEST_DOCKERD") == "1" {
 		t.Skip("image exporter is missing in dockerd")
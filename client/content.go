@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"io"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// CopyContentTo copies the blobs with the given digests from the content
+// store of the daemon to the content store of other. Blobs that other already
+// has are skipped. The target daemon verifies the digest of every received
+// blob. Copied blobs that are not referenced by any build result are still
+// subject to the garbage collection of the target daemon.
+func (c *Client) CopyContentTo(ctx context.Context, other *Client, digests []digest.Digest) error {
+	if len(digests) == 0 {
+		return nil
+	}
+	resp, err := other.controlClient().ContentInfo(ctx, &controlapi.ContentInfoRequest{
+		Digests: digests,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to get content info")
+	}
+	for _, dgst := range resp.Missing {
+		if err := c.copyBlob(ctx, other, dgst); err != nil {
+			return errors.Wrapf(err, "failed to copy %s", dgst)
+		}
+	}
+	return nil
+}
+
+func (c *Client) copyBlob(ctx context.Context, other *Client, dgst digest.Digest) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rc, err := c.controlClient().ReadContent(ctx, &controlapi.ReadContentRequest{
+		Digest: dgst,
+	})
+	if err != nil {
+		return err
+	}
+	wc, err := other.controlClient().WriteContent(ctx)
+	if err != nil {
+		return err
+	}
+
+	first := true
+	for {
+		resp, err := rc.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		req := &controlapi.WriteContentRequest{Data: resp.Data}
+		if first {
+			req.Digest = dgst
+			req.Size_ = resp.Size_
+			first = false
+		}
+		if err := wc.Send(req); err != nil {
+			if err == io.EOF {
+				// the target closed the stream, get the error from CloseAndRecv
+				break
+			}
+			return err
+		}
+	}
+	if first {
+		// empty blob
+		if err := wc.Send(&controlapi.WriteContentRequest{Digest: dgst}); err != nil && err != io.EOF {
+			return err
+		}
+	}
+	_, err = wc.CloseAndRecv()
+	return err
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/moby/buildkit/client/llb"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// BuildSizeEstimate is the approximate disk space needed to build a
+// definition. None of the sizes are exact.
+type BuildSizeEstimate struct {
+	// Sources is the total compressed size of the layers of the source images
+	// read from their manifests. The extracted layers use more space.
+	Sources int64
+	// Estimated is the estimated total size of the outputs of the other
+	// vertexes, based on the cache records of previous builds
+	Estimated int64
+	Vertexes  []*VertexSizeEstimate
+}
+
+// Total returns the sum of the source and the estimated sizes
+func (e *BuildSizeEstimate) Total() int64 {
+	return e.Sources + e.Estimated
+}
+
+type VertexSizeEstimate struct {
+	Vertex digest.Digest
+	Name   string
+	// Size is the approximate disk space used by the vertex
+	Size int64
+	// FromManifest is true if Size is the compressed size of the layers in an
+	// image manifest
+	FromManifest bool
+	// Cached is true if the vertex is expected to be a cache hit, its size is
+	// not counted
+	Cached bool
+}
+
+// EstimateBuildSize estimates the disk space needed to build def without
+// running it. Vertexes with results in the cache are expected to be cache hits
+// and are not counted, which underestimates builds whose sources changed since
+// the cached build.
+func (c *Client) EstimateBuildSize(ctx context.Context, def *llb.Definition) (*BuildSizeEstimate, error) {
+	if def == nil {
+		return nil, errors.New("definition is required")
+	}
+	resp, err := c.controlClient().EstimateBuildSize(ctx, &controlapi.EstimateBuildSizeRequest{
+		Definition: def.ToPB(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to estimate build size")
+	}
+
+	est := &BuildSizeEstimate{}
+	for _, v := range resp.Vertexes {
+		est.Vertexes = append(est.Vertexes, &VertexSizeEstimate{
+			Vertex:       v.Vertex,
+			Name:         v.Name,
+			Size:         v.Size_,
+			FromManifest: v.FromManifest,
+			Cached:       v.Cached,
+		})
+		if v.FromManifest {
+			est.Sources += v.Size_
+		} else {
+			est.Estimated += v.Size_
+		}
+	}
+	return est, nil
+}
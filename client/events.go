@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/pkg/errors"
+)
+
+// EventType is the type of a daemon event
+type EventType string
+
+const (
+	// EventBuildStarted is sent when the daemon starts a build
+	EventBuildStarted EventType = "build.started"
+	// EventBuildFinished is sent when a build completes. Error is set if the
+	// build failed.
+	EventBuildFinished EventType = "build.finished"
+	// EventGC is sent after a garbage collection run that removed records
+	EventGC EventType = "gc"
+	// EventPrune is sent after a prune requested by a client
+	EventPrune EventType = "prune"
+	// EventWorkerAdded is sent when a worker registers with the daemon
+	EventWorkerAdded EventType = "worker.added"
+)
+
+// Event is an event of the daemon
+type Event struct {
+	Type      EventType
+	Timestamp time.Time
+	// Ref is the ID of the build of build events
+	Ref string
+	// Error is the error of a failed build
+	Error string
+	// Worker is the ID of the worker of gc, prune and worker events. It is
+	// empty if the event covers all workers.
+	Worker string
+	// BytesFreed is the size of the records removed by gc and prune
+	BytesFreed int64
+	// Records is the number of records removed by gc and prune
+	Records int64
+	// Dropped is the number of events the daemon did not send before this
+	// one because the subscriber did not keep up
+	Dropped int64
+}
+
+// Subscribe returns a channel that receives the events of the daemon until
+// ctx is canceled or the connection to the daemon fails. The channel is closed
+// when the subscription ends.
+func (c *Client) Subscribe(ctx context.Context) (<-chan *Event, error) {
+	cl, err := c.controlClient().Events(ctx, &controlapi.EventsRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to call events")
+	}
+
+	ch := make(chan *Event)
+	go func() {
+		defer close(ch)
+		for {
+			resp, err := cl.Recv()
+			if err != nil {
+				return
+			}
+			ev := &Event{
+				Type:       EventType(resp.Type),
+				Timestamp:  resp.Timestamp,
+				Ref:        resp.Ref,
+				Error:      resp.Error,
+				Worker:     resp.Worker,
+				BytesFreed: resp.BytesFreed,
+				Records:    resp.Records,
+				Dropped:    resp.Dropped,
+			}
+			select {
+			case ch <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
@@ -1,9 +1,12 @@
 package client
 
 const (
-	ExporterImage  = "image"
-	ExporterLocal  = "local"
-	ExporterTar    = "tar"
-	ExporterOCI    = "oci"
-	ExporterDocker = "docker"
+	ExporterImage      = "image"
+	ExporterLocal      = "local"
+	ExporterTar        = "tar"
+	ExporterOCI        = "oci"
+	ExporterDocker     = "docker"
+	ExporterMerkle     = "merkle"
+	ExporterProvenance = "provenance"
+	ExporterFSImage    = "fsimage"
 )
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"io"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/pkg/errors"
+)
+
+// ExportFullCache writes an archive of the build cache of the daemon to w.
+// The archive contains the cache keys of the solver and the layers of the
+// results of the default worker, and can be restored on another daemon with
+// ImportFullCache. Cache mounts, local sources and results whose blobs are not
+// in the content store of the daemon are not exported.
+func (c *Client) ExportFullCache(ctx context.Context, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cl, err := c.controlClient().ExportFullCache(ctx, &controlapi.ExportFullCacheRequest{})
+	if err != nil {
+		return errors.Wrap(err, "failed to call export full cache")
+	}
+	for {
+		msg, err := cl.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(msg.Data); err != nil {
+			return err
+		}
+	}
+}
+
+// ImportFullCache restores an archive created by ExportFullCache into the
+// build cache of the daemon. The cache that the daemon already has is kept.
+func (c *Client) ImportFullCache(ctx context.Context, r io.Reader) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cl, err := c.controlClient().ImportFullCache(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to call import full cache")
+	}
+	buf := make([]byte, 1<<20)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if err := cl.Send(&controlapi.BytesMessage{Data: buf[:n]}); err != nil {
+				if err == io.EOF {
+					// the daemon closed the stream, get the error from CloseAndRecv
+					break
+				}
+				return err
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	_, err = cl.CloseAndRecv()
+	return err
+}
@@ -3,17 +3,25 @@ package client
 import (
 	"time"
 
+	gateway "github.com/moby/buildkit/frontend/gateway/client"
+	"github.com/moby/buildkit/solver/pb"
 	digest "github.com/opencontainers/go-digest"
 )
 
 type Vertex struct {
-	Digest    digest.Digest
-	Inputs    []digest.Digest
-	Name      string
-	Started   *time.Time
-	Completed *time.Time
-	Cached    bool
-	Error     string
+	Digest        digest.Digest
+	Inputs        []digest.Digest
+	Name          string
+	Started       *time.Time
+	Completed     *time.Time
+	Cached        bool
+	Error         string
+	ProgressGroup *pb.ProgressGroup
+	// ExitCode is the nonzero exit code of the process run by the vertex
+	ExitCode int
+	// Labels are the build labels that were set on the vertex with
+	// llb.State.WithBuildLabel
+	Labels map[string]string
 }
 
 type VertexStatus struct {
@@ -34,13 +42,54 @@ type VertexLog struct {
 	Timestamp time.Time
 }
 
+// CacheMountStats describe the files the process of a vertex kept and wrote in
+// a cache mount. They are computed by comparing the files of the cache before
+// and after the process ran, so they don't tell which files the process read.
+// They are only sent for builds that set SolveOpt.CacheMountStats.
+type CacheMountStats struct {
+	Vertex digest.Digest
+	// ID is the ID of the cache
+	ID string
+	// Target is the path of the cache mount in the container
+	Target string
+	// UnchangedBytes is the size of the files of the cache that the process
+	// kept unchanged
+	UnchangedBytes int64
+	// WrittenBytes is the size of the files that the process created or
+	// modified in the cache
+	WrittenBytes int64
+	Timestamp time.Time
+}
+
 type SolveStatus struct {
-	Vertexes []*Vertex
-	Statuses []*VertexStatus
-	Logs     []*VertexLog
+	Vertexes    []*Vertex
+	Statuses    []*VertexStatus
+	Logs        []*VertexLog
+	CacheMounts []*CacheMountStats
 }
 
 type SolveResponse struct {
 	// ExporterResponse is also used for CacheExporter
 	ExporterResponse map[string]string
+	// Sources maps the identifiers of the image, Git and HTTP sources that
+	// the build resolved to their resolved digests. Git commits use the sha1
+	// algorithm.
+	Sources map[string]digest.Digest
+	// Warnings are the warnings recorded by the frontends and the solver
+	// during the build
+	Warnings []Warning
+}
+
+// Warning is a warning of a build. Code identifies the kind of the warning
+// and is the same across releases.
+type Warning struct {
+	Code    string
+	Level   gateway.WarningLevel
+	Message string
+	Detail  string
+	URL     string
+	// Vertex is the digest of the vertex that the warning is about, if any
+	Vertex     digest.Digest
+	SourceInfo *pb.SourceInfo
+	Ranges     []*pb.Range
 }
@@ -0,0 +1,33 @@
+package client
+
+import (
+	"context"
+	"io"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/pkg/errors"
+)
+
+// GetBuildHistory returns the status events recorded by the daemon for the
+// build with the given ref. The daemon needs to be configured to keep build
+// history.
+func (c *Client) GetBuildHistory(ctx context.Context, ref string) ([]*SolveStatus, error) {
+	cl, err := c.controlClient().BuildHistory(ctx, &controlapi.BuildHistoryRequest{
+		Ref: ref,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to call build history")
+	}
+
+	var statuses []*SolveStatus
+	for {
+		resp, err := cl.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return statuses, nil
+			}
+			return nil, errors.Wrap(err, "failed to receive build history")
+		}
+		statuses = append(statuses, statusFromResponse(resp))
+	}
+}
@@ -51,36 +51,9 @@ func NewDefinitionOp(def *pb.Definition) (*DefinitionOp, error) {
 		platforms[dgst] = platform
 	}
 
-	srcs := map[digest.Digest][]*SourceLocation{}
-
-	if def.Source != nil {
-		sourceMaps := make([]*SourceMap, len(def.Source.Infos))
-		for i, info := range def.Source.Infos {
-			var st *State
-			sdef := info.Definition
-			if sdef != nil {
-				op, err := NewDefinitionOp(sdef)
-				if err != nil {
-					return nil, err
-				}
-				state := NewState(op)
-				st = &state
-			}
-			sourceMaps[i] = NewSourceMap(st, info.Filename, info.Data)
-		}
-
-		for dgst, locs := range def.Source.Locations {
-			for _, loc := range locs.Locations {
-				if loc.SourceIndex < 0 || int(loc.SourceIndex) >= len(sourceMaps) {
-					return nil, errors.Errorf("failed to find source map with index %d", loc.SourceIndex)
-				}
-
-				srcs[digest.Digest(dgst)] = append(srcs[digest.Digest(dgst)], &SourceLocation{
-					SourceMap: sourceMaps[int(loc.SourceIndex)],
-					Ranges:    loc.Ranges,
-				})
-			}
-		}
+	srcs, err := sourceLocations(def.Source)
+	if err != nil {
+		return nil, err
 	}
 
 	var index pb.OutputIndex
@@ -101,6 +74,43 @@ func NewDefinitionOp(def *pb.Definition) (*DefinitionOp, error) {
 	}, nil
 }
 
+// sourceLocations returns the source locations of the ops of a definition
+func sourceLocations(src *pb.Source) (map[digest.Digest][]*SourceLocation, error) {
+	srcs := map[digest.Digest][]*SourceLocation{}
+	if src == nil {
+		return srcs, nil
+	}
+
+	sourceMaps := make([]*SourceMap, len(src.Infos))
+	for i, info := range src.Infos {
+		var st *State
+		sdef := info.Definition
+		if sdef != nil {
+			op, err := NewDefinitionOp(sdef)
+			if err != nil {
+				return nil, err
+			}
+			state := NewState(op)
+			st = &state
+		}
+		sourceMaps[i] = NewSourceMap(st, info.Filename, info.Data)
+	}
+
+	for dgst, locs := range src.Locations {
+		for _, loc := range locs.Locations {
+			if loc.SourceIndex < 0 || int(loc.SourceIndex) >= len(sourceMaps) {
+				return nil, errors.Errorf("failed to find source map with index %d", loc.SourceIndex)
+			}
+
+			srcs[digest.Digest(dgst)] = append(srcs[digest.Digest(dgst)], &SourceLocation{
+				SourceMap: sourceMaps[int(loc.SourceIndex)],
+				Ranges:    loc.Ranges,
+			})
+		}
+	}
+	return srcs, nil
+}
+
 func (d *DefinitionOp) ToInput(ctx context.Context, c *Constraints) (*pb.Input, error) {
 	return d.Output().ToInput(ctx, c)
 }
@@ -0,0 +1,226 @@
+package llb
+
+import (
+	"reflect"
+
+	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+type OpDiffType string
+
+const (
+	OpAdded   OpDiffType = "added"
+	OpRemoved OpDiffType = "removed"
+	OpChanged OpDiffType = "changed"
+)
+
+// OpDiff is a difference between the ops of two definitions
+type OpDiff struct {
+	Type OpDiffType
+	// Old is the op of the first definition, nil for added ops
+	Old *DiffOp
+	// New is the op of the second definition, nil for removed ops
+	New *DiffOp
+	// Invalidated is the number of ops of the second definition that depend
+	// on the added or changed op and can't be cached because of it
+	Invalidated int
+}
+
+type DiffOp struct {
+	Digest          digest.Digest
+	Op              *pb.Op
+	SourceLocations []*SourceLocation
+}
+
+// DiffDefinitions compares the ops of two definitions. Ops with the same
+// digest are unchanged. The other ops are matched by walking both graphs from
+// their outputs, following the inputs with the same index. Matched ops that
+// only differ because of their inputs are not reported, they are counted as
+// invalidated by the change of the input instead.
+//
+// The diffs of the added and changed ops are returned in the order of the
+// second definition, followed by the removed ops in the order of the first.
+// The ops selecting the outputs of the definitions are never reported.
+func DiffDefinitions(a, b *Definition) ([]OpDiff, error) {
+	da, err := newDiffDefinition(a)
+	if err != nil {
+		return nil, err
+	}
+	db, err := newDiffDefinition(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// matches maps the digests of the second definition to the op they
+	// replace in the first one
+	matches := map[digest.Digest]digest.Digest{}
+	matched := map[digest.Digest]struct{}{}
+	var match func(oldDgst, newDgst digest.Digest)
+	match = func(oldDgst, newDgst digest.Digest) {
+		if _, ok := db.ops[oldDgst]; ok {
+			return
+		}
+		if _, ok := da.ops[newDgst]; ok {
+			return
+		}
+		if _, ok := matches[newDgst]; ok {
+			return
+		}
+		if _, ok := matched[oldDgst]; ok {
+			return
+		}
+		oldOp, newOp := da.ops[oldDgst], db.ops[newDgst]
+		if oldOp == nil || newOp == nil || reflect.TypeOf(oldOp.Op) != reflect.TypeOf(newOp.Op) {
+			return
+		}
+		matches[newDgst] = oldDgst
+		matched[oldDgst] = struct{}{}
+		for i := range newOp.Inputs {
+			if i >= len(oldOp.Inputs) {
+				break
+			}
+			match(oldOp.Inputs[i].Digest, newOp.Inputs[i].Digest)
+		}
+	}
+	if len(da.order) > 0 && len(db.order) > 0 {
+		match(da.order[len(da.order)-1], db.order[len(db.order)-1])
+	}
+
+	var diffs []OpDiff
+	for _, dgst := range db.order {
+		if _, ok := da.ops[dgst]; ok {
+			continue
+		}
+		if db.ops[dgst].Op == nil {
+			continue
+		}
+		d := OpDiff{
+			Type:        OpAdded,
+			New:         db.diffOp(dgst),
+			Invalidated: db.dependents(dgst),
+		}
+		if oldDgst, ok := matches[dgst]; ok {
+			changed, err := opChanged(da.defs[oldDgst], db.defs[dgst], matches)
+			if err != nil {
+				return nil, err
+			}
+			if !changed {
+				continue
+			}
+			d.Type = OpChanged
+			d.Old = da.diffOp(oldDgst)
+		}
+		diffs = append(diffs, d)
+	}
+	for _, dgst := range da.order {
+		if _, ok := db.ops[dgst]; ok {
+			continue
+		}
+		if _, ok := matched[dgst]; ok || da.ops[dgst].Op == nil {
+			continue
+		}
+		diffs = append(diffs, OpDiff{
+			Type: OpRemoved,
+			Old:  da.diffOp(dgst),
+		})
+	}
+	return diffs, nil
+}
+
+// opChanged returns true if the op of the second definition differs from the
+// op it was matched with, after replacing its inputs with the matching inputs
+// of the first definition
+func opChanged(oldDt, newDt []byte, matches map[digest.Digest]digest.Digest) (bool, error) {
+	var oldOp, newOp pb.Op
+	if err := oldOp.Unmarshal(oldDt); err != nil {
+		return false, errors.Wrap(err, "failed to parse llb proto op")
+	}
+	if err := newOp.Unmarshal(newDt); err != nil {
+		return false, errors.Wrap(err, "failed to parse llb proto op")
+	}
+	for _, inp := range newOp.Inputs {
+		if dgst, ok := matches[inp.Digest]; ok {
+			inp.Digest = dgst
+		}
+	}
+	return !reflect.DeepEqual(oldOp, newOp), nil
+}
+
+type diffDefinition struct {
+	order   []digest.Digest
+	ops     map[digest.Digest]*pb.Op
+	defs    map[digest.Digest][]byte
+	sources map[digest.Digest][]*SourceLocation
+	// outputs maps the digests of the ops to the ops using them as input
+	outputs map[digest.Digest][]digest.Digest
+}
+
+func newDiffDefinition(def *Definition) (*diffDefinition, error) {
+	d := &diffDefinition{
+		ops:     map[digest.Digest]*pb.Op{},
+		defs:    map[digest.Digest][]byte{},
+		outputs: map[digest.Digest][]digest.Digest{},
+	}
+	if def == nil {
+		d.sources = map[digest.Digest][]*SourceLocation{}
+		return d, nil
+	}
+	for _, dt := range def.Def {
+		var op pb.Op
+		if err := (&op).Unmarshal(dt); err != nil {
+			return nil, errors.Wrap(err, "failed to parse llb proto op")
+		}
+		dgst := digest.FromBytes(dt)
+		if _, ok := d.ops[dgst]; ok {
+			continue
+		}
+		d.order = append(d.order, dgst)
+		d.ops[dgst] = &op
+		d.defs[dgst] = dt
+		for _, inp := range op.Inputs {
+			d.outputs[inp.Digest] = append(d.outputs[inp.Digest], dgst)
+		}
+	}
+	srcs, err := sourceLocations(def.Source)
+	if err != nil {
+		return nil, err
+	}
+	d.sources = srcs
+	return d, nil
+}
+
+func (d *diffDefinition) diffOp(dgst digest.Digest) *DiffOp {
+	return &DiffOp{
+		Digest:          dgst,
+		Op:              d.ops[dgst],
+		SourceLocations: d.sources[dgst],
+	}
+}
+
+// dependents returns the number of ops that depend on dgst directly or
+// through other ops, not counting the op selecting the output of the
+// definition
+func (d *diffDefinition) dependents(dgst digest.Digest) int {
+	seen := map[digest.Digest]struct{}{}
+	var walk func(digest.Digest)
+	walk = func(dgst digest.Digest) {
+		for _, out := range d.outputs[dgst] {
+			if _, ok := seen[out]; ok {
+				continue
+			}
+			seen[out] = struct{}{}
+			walk(out)
+		}
+	}
+	walk(dgst)
+
+	n := 0
+	for dgst := range seen {
+		if d.ops[dgst].Op != nil {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,73 @@
+package llb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffDefinitions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	sm := NewSourceMap(nil, "Dockerfile", []byte("data"))
+	line := func(l int32) ConstraintsOpt {
+		return sm.Location([]*pb.Range{{Start: pb.Position{Line: l}}})
+	}
+
+	build := func(second string, extra bool) *Definition {
+		st := Image("busybox", line(1)).
+			Run(Shlex("echo first"), line(2)).Root().
+			Run(Shlex(second), line(3)).Root().
+			Run(Shlex("echo third"), line(4)).Root()
+		if extra {
+			st = st.Run(Shlex("echo fourth"), line(5)).Root()
+		}
+		def, err := st.Marshal(ctx)
+		require.NoError(t, err)
+		return def
+	}
+
+	a := build("echo second", false)
+
+	diffs, err := DiffDefinitions(a, a)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(diffs))
+
+	diffs, err = DiffDefinitions(a, build("echo changed", false))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(diffs))
+	require.Equal(t, OpChanged, diffs[0].Type)
+	require.Equal(t, []string{"echo", "second"}, diffs[0].Old.Op.GetExec().Meta.Args)
+	require.Equal(t, []string{"echo", "changed"}, diffs[0].New.Op.GetExec().Meta.Args)
+	require.Equal(t, 1, diffs[0].Invalidated)
+	require.Equal(t, 1, len(diffs[0].New.SourceLocations))
+	require.Equal(t, "Dockerfile", diffs[0].New.SourceLocations[0].SourceMap.Filename)
+	require.Equal(t, int32(3), diffs[0].New.SourceLocations[0].Ranges[0].Start.Line)
+
+	diffs, err = DiffDefinitions(a, build("echo second", true))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(diffs))
+	require.Equal(t, OpAdded, diffs[0].Type)
+	require.Nil(t, diffs[0].Old)
+	require.Equal(t, []string{"echo", "fourth"}, diffs[0].New.Op.GetExec().Meta.Args)
+	require.Equal(t, 0, diffs[0].Invalidated)
+
+	diffs, err = DiffDefinitions(build("echo second", true), a)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(diffs))
+	require.Equal(t, OpRemoved, diffs[0].Type)
+	require.Nil(t, diffs[0].New)
+	require.Equal(t, []string{"echo", "fourth"}, diffs[0].Old.Op.GetExec().Meta.Args)
+	require.Equal(t, int32(5), diffs[0].Old.SourceLocations[0].Ranges[0].Start.Line)
+
+	diffs, err = DiffDefinitions(nil, a)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(diffs))
+	for _, d := range diffs {
+		require.Equal(t, OpAdded, d.Type)
+	}
+	require.Equal(t, 3, diffs[0].Invalidated)
+}
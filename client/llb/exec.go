@@ -5,7 +5,9 @@ import (
 	_ "crypto/sha256" // for opencontainers/go-digest
 	"fmt"
 	"net"
+	"os"
 	"sort"
+	"time"
 
 	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/buildkit/util/system"
@@ -43,6 +45,7 @@ type mount struct {
 	selector     string
 	cacheID      string
 	tmpfs        bool
+	hostPath     string
 	cacheSharing CacheMountSharingMode
 	noOutput     bool
 }
@@ -57,6 +60,25 @@ type ExecOp struct {
 	isValidated bool
 	secrets     []SecretInfo
 	ssh         []SSHInfo
+	exitCodes   []int
+	seccomp     *SeccompInfo
+	apparmor    string
+	ignoreCache []string
+	devices     []DeviceInfo
+	cacheIgnore []string
+	umask       *os.FileMode
+	passthrough []string
+	timezone    *TimezoneInfo
+	stdoutPath  string
+	stderrPath  string
+	after       []State
+	memoryLimit int64
+	cpuQuota    time.Duration
+	cpuPeriod   time.Duration
+	sharedPID   *ExecOp
+	retry       *RetryInfo
+	userNS      *UserNSMappingInfo
+	sysctls     map[string]string
 }
 
 func (e *ExecOp) AddMount(target string, source Output, opt ...MountOption) Output {
@@ -72,6 +94,8 @@ func (e *ExecOp) AddMount(target string, source Output, opt ...MountOption) Outp
 		m.output = source
 	} else if m.tmpfs {
 		m.output = &output{vertex: e, err: errors.Errorf("tmpfs mount for %s can't be used as a parent", target)}
+	} else if m.hostPath != "" {
+		m.output = &output{vertex: e, err: errors.Errorf("host path mount for %s can't be used as a parent", target)}
 	} else if m.noOutput {
 		m.output = &output{vertex: e, err: errors.Errorf("mount marked no-output and %s can't be used as a parent", target)}
 	} else {
@@ -113,6 +137,9 @@ func (e *ExecOp) Validate(ctx context.Context, c *Constraints) error {
 	if cwd == "" {
 		return errors.Errorf("working directory is required")
 	}
+	if e.memoryLimit < 0 || e.cpuQuota < 0 || e.cpuPeriod < 0 {
+		return errors.Errorf("resource limits must not be negative")
+	}
 	for _, m := range e.mounts {
 		if m.source != nil {
 			if err := m.source.Vertex(ctx, c).Validate(ctx, c); err != nil {
@@ -136,106 +163,118 @@ func (e *ExecOp) Marshal(ctx context.Context, c *Constraints) (digest.Digest, []
 		return e.mounts[i].target < e.mounts[j].target
 	})
 
-	env, err := getEnv(e.base)(ctx, c)
+	meta, err := e.marshalMeta(ctx, c, &e.constraints)
 	if err != nil {
 		return "", nil, nil, nil, err
 	}
 
-	if len(e.ssh) > 0 {
-		for i, s := range e.ssh {
-			if s.Target == "" {
-				e.ssh[i].Target = fmt.Sprintf("/run/buildkit/ssh_agent.%d", i)
-			}
-		}
-		if _, ok := env.Get("SSH_AUTH_SOCK"); !ok {
-			env = env.AddOrReplace("SSH_AUTH_SOCK", e.ssh[0].Target)
-		}
-	}
-	if c.Caps != nil {
-		if err := c.Caps.Supports(pb.CapExecMetaSetsDefaultPath); err != nil {
-			os := "linux"
-			if c.Platform != nil {
-				os = c.Platform.OS
-			} else if e.constraints.Platform != nil {
-				os = e.constraints.Platform.OS
-			}
-			env = env.SetDefault("PATH", system.DefaultPathEnv(os))
-		} else {
-			addCap(&e.constraints, pb.CapExecMetaSetsDefaultPath)
-		}
-	}
-
-	args, err := getArgs(e.base)(ctx, c)
+	network, err := getNetwork(e.base)(ctx, c)
 	if err != nil {
 		return "", nil, nil, nil, err
 	}
 
-	cwd, err := getDir(e.base)(ctx, c)
+	security, err := getSecurity(e.base)(ctx, c)
 	if err != nil {
 		return "", nil, nil, nil, err
 	}
 
-	user, err := getUser(e.base)(ctx, c)
-	if err != nil {
-		return "", nil, nil, nil, err
+	peo := &pb.ExecOp{
+		Meta:     meta,
+		Network:  network,
+		Security: security,
+	}
+	if network != NetModeSandbox {
+		addCap(&e.constraints, pb.CapExecMetaNetwork)
 	}
 
-	hostname, err := getHostname(e.base)(ctx, c)
-	if err != nil {
-		return "", nil, nil, nil, err
+	if security != SecurityModeSandbox {
+		addCap(&e.constraints, pb.CapExecMetaSecurity)
 	}
 
-	meta := &pb.Meta{
-		Args:     args,
-		Env:      env.ToArray(),
-		Cwd:      cwd,
-		User:     user,
-		Hostname: hostname,
+	if len(e.exitCodes) > 0 {
+		for _, c := range e.exitCodes {
+			peo.AllowedExitCodes = append(peo.AllowedExitCodes, int32(c))
+		}
+		addCap(&e.constraints, pb.CapExecAllowedExitCodes)
 	}
-	extraHosts, err := getExtraHosts(e.base)(ctx, c)
-	if err != nil {
-		return "", nil, nil, nil, err
+
+	if s := e.seccomp; s != nil {
+		peo.Seccomp = &pb.SeccompOpt{
+			Profile:    string(s.Profile),
+			Unconfined: s.Unconfined,
+		}
+		addCap(&e.constraints, pb.CapExecMetaSeccomp)
 	}
-	if len(extraHosts) > 0 {
-		hosts := make([]*pb.HostIP, len(extraHosts))
-		for i, h := range extraHosts {
-			hosts[i] = &pb.HostIP{Host: h.Host, IP: h.IP.String()}
+
+	if e.apparmor != "" {
+		peo.ApparmorProfile = e.apparmor
+		addCap(&e.constraints, pb.CapExecMetaApparmor)
+	}
+
+	if len(e.ignoreCache) > 0 {
+		peo.IgnoreForCache = e.ignoreCache
+		addCap(&e.constraints, pb.CapExecIgnoreForCache)
+	}
+
+	if r := e.retry; r != nil && r.Attempts > 0 {
+		peo.Retry = &pb.ExecRetry{Attempts: int64(r.Attempts)}
+		for _, c := range r.ExitCodes {
+			peo.Retry.ExitCodes = append(peo.Retry.ExitCodes, int32(c))
 		}
-		meta.ExtraHosts = hosts
+		addCap(&e.constraints, pb.CapExecRetry)
 	}
 
-	network, err := getNetwork(e.base)(ctx, c)
-	if err != nil {
-		return "", nil, nil, nil, err
+	if u := e.userNS; u != nil {
+		peo.UserNSMapping = &pb.UserNSMapping{
+			UidMappings: toPBIDMaps(u.UIDMappings),
+			GidMappings: toPBIDMaps(u.GIDMappings),
+		}
+		addCap(&e.constraints, pb.CapExecUserNSMapping)
 	}
 
-	security, err := getSecurity(e.base)(ctx, c)
-	if err != nil {
-		return "", nil, nil, nil, err
+	if len(e.sysctls) > 0 {
+		peo.Sysctls = e.sysctls
+		addCap(&e.constraints, pb.CapExecSysctl)
 	}
 
-	peo := &pb.ExecOp{
-		Meta:     meta,
-		Network:  network,
-		Security: security,
+	if e.stdoutPath != "" || e.stderrPath != "" {
+		peo.Meta.RedirectStdout = e.stdoutPath
+		peo.Meta.RedirectStderr = e.stderrPath
+		addCap(&e.constraints, pb.CapExecMetaRedirect)
 	}
-	if network != NetModeSandbox {
-		addCap(&e.constraints, pb.CapExecMetaNetwork)
+
+	if e.memoryLimit != 0 || e.cpuQuota != 0 || e.cpuPeriod != 0 {
+		peo.Resources = &pb.Resources{
+			Memory:    e.memoryLimit,
+			CpuQuota:  e.cpuQuota.Microseconds(),
+			CpuPeriod: uint64(e.cpuPeriod.Microseconds()),
+		}
+		addCap(&e.constraints, pb.CapExecMetaResources)
 	}
 
-	if security != SecurityModeSandbox {
-		addCap(&e.constraints, pb.CapExecMetaSecurity)
+	after := e.afterOutputs()
+	if len(after) > 0 {
+		addCap(&e.constraints, pb.CapExecAfter)
 	}
 
-	if p := e.proxyEnv; p != nil {
-		peo.Meta.ProxyEnv = &pb.ProxyEnv{
-			HttpProxy:  p.HTTPProxy,
-			HttpsProxy: p.HTTPSProxy,
-			FtpProxy:   p.FTPProxy,
-			NoProxy:    p.NoProxy,
-			AllProxy:   p.AllProxy,
+	var sharedPID *pb.SharedPID
+	var sharedPIDSecrets []*pb.Mount
+	if e.sharedPID != nil {
+		sharedPID, sharedPIDSecrets, err = e.marshalSharedPID(ctx, c)
+		if err != nil {
+			return "", nil, nil, nil, err
 		}
-		addCap(&e.constraints, pb.CapExecMetaProxy)
+		addCap(&e.constraints, pb.CapExecSharedPID)
+	}
+
+	if len(e.devices) > 0 {
+		for _, d := range e.devices {
+			peo.Devices = append(peo.Devices, &pb.Device{
+				Path:        d.Path,
+				Permissions: d.Permissions,
+			})
+		}
+		addCap(&e.constraints, pb.CapExecMetaDevices)
 	}
 
 	addCap(&e.constraints, pb.CapExecMetaBase)
@@ -249,13 +288,19 @@ func (e *ExecOp) Marshal(ctx context.Context, c *Constraints) (digest.Digest, []
 			addCap(&e.constraints, pb.CapExecMountCacheSharing)
 		} else if m.tmpfs {
 			addCap(&e.constraints, pb.CapExecMountTmpfs)
+		} else if m.hostPath != "" {
+			addCap(&e.constraints, pb.CapExecMountHostPath)
 		} else if m.source != nil {
 			addCap(&e.constraints, pb.CapExecMountBind)
 		}
 	}
 
-	if len(e.secrets) > 0 {
-		addCap(&e.constraints, pb.CapExecMountSecret)
+	for _, s := range e.secrets {
+		if s.Env != "" {
+			addCap(&e.constraints, pb.CapExecSecretEnv)
+		} else {
+			addCap(&e.constraints, pb.CapExecMountSecret)
+		}
 	}
 
 	if len(e.ssh) > 0 {
@@ -282,6 +327,9 @@ func (e *ExecOp) Marshal(ctx context.Context, c *Constraints) (digest.Digest, []
 			if m.tmpfs {
 				return "", nil, nil, nil, errors.Errorf("tmpfs mounts must use scratch")
 			}
+			if m.hostPath != "" {
+				return "", nil, nil, nil, errors.Errorf("host path mounts must use scratch")
+			}
 			inp, err := m.source.ToInput(ctx, c)
 			if err != nil {
 				return "", nil, nil, nil, err
@@ -305,40 +353,184 @@ func (e *ExecOp) Marshal(ctx context.Context, c *Constraints) (digest.Digest, []
 		}
 
 		outputIndex := pb.OutputIndex(-1)
-		if !m.noOutput && !m.readonly && m.cacheID == "" && !m.tmpfs {
+		if !m.noOutput && !m.readonly && m.cacheID == "" && !m.tmpfs && m.hostPath == "" {
 			outputIndex = pb.OutputIndex(outIndex)
 			outIndex++
 		}
 
-		pm := &pb.Mount{
-			Input:    inputIndex,
-			Dest:     m.target,
-			Readonly: m.readonly,
-			Output:   outputIndex,
-			Selector: m.selector,
+		peo.Mounts = append(peo.Mounts, m.marshal(inputIndex, outputIndex))
+	}
+
+	for _, o := range after {
+		inp, err := o.ToInput(ctx, c)
+		if err != nil {
+			return "", nil, nil, nil, err
 		}
-		if m.cacheID != "" {
-			pm.MountType = pb.MountType_CACHE
-			pm.CacheOpt = &pb.CacheOpt{
-				ID: m.cacheID,
+		inputIndex := pb.InputIndex(len(pop.Inputs))
+		newInput := true
+		for i, inp2 := range pop.Inputs {
+			if *inp == *inp2 {
+				inputIndex = pb.InputIndex(i)
+				newInput = false
+				break
 			}
-			switch m.cacheSharing {
-			case CacheMountShared:
-				pm.CacheOpt.Sharing = pb.CacheSharingOpt_SHARED
-			case CacheMountPrivate:
-				pm.CacheOpt.Sharing = pb.CacheSharingOpt_PRIVATE
-			case CacheMountLocked:
-				pm.CacheOpt.Sharing = pb.CacheSharingOpt_LOCKED
+		}
+		if !newInput {
+			// already a dependency of a mount
+			continue
+		}
+		pop.Inputs = append(pop.Inputs, inp)
+		peo.After = append(peo.After, inputIndex)
+	}
+
+	if sharedPID != nil {
+		if err := e.marshalSharedPIDMounts(ctx, c, pop, sharedPID, sharedPIDSecrets); err != nil {
+			return "", nil, nil, nil, err
+		}
+		peo.SharedPID = sharedPID
+	}
+
+	secretenv, mounts := e.marshalSecrets()
+	peo.Secretenv = append(peo.Secretenv, secretenv...)
+	peo.Mounts = append(peo.Mounts, mounts...)
+
+	dt, err := pop.Marshal()
+	if err != nil {
+		return "", nil, nil, nil, err
+	}
+	e.Store(dt, md, e.constraints.SourceLocations, c)
+	return e.Load()
+}
+
+// marshalMeta marshals the process of e. The caps it requires are added to
+// caps.
+func (e *ExecOp) marshalMeta(ctx context.Context, c *Constraints, caps *Constraints) (*pb.Meta, error) {
+	env, err := getEnv(e.base)(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(e.ssh) > 0 {
+		for i, s := range e.ssh {
+			if s.Target == "" {
+				e.ssh[i].Target = fmt.Sprintf("/run/buildkit/ssh_agent.%d", i)
 			}
 		}
-		if m.tmpfs {
-			pm.MountType = pb.MountType_TMPFS
+		if _, ok := env.Get("SSH_AUTH_SOCK"); !ok {
+			env = env.AddOrReplace("SSH_AUTH_SOCK", e.ssh[0].Target)
+		}
+	}
+	if c.Caps != nil {
+		if err := c.Caps.Supports(pb.CapExecMetaSetsDefaultPath); err != nil {
+			os := "linux"
+			if c.Platform != nil {
+				os = c.Platform.OS
+			} else if e.constraints.Platform != nil {
+				os = e.constraints.Platform.OS
+			}
+			env = env.SetDefault("PATH", system.DefaultPathEnv(os))
+		} else {
+			addCap(caps, pb.CapExecMetaSetsDefaultPath)
+		}
+	}
+
+	args, err := getArgs(e.base)(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	cwd, err := getDir(e.base)(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := getUser(e.base)(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, err := getHostname(e.base)(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	entrypoint, err := getEntrypoint(e.base)(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &pb.Meta{
+		Args:       args,
+		Env:        env.ToArray(),
+		Cwd:        cwd,
+		User:       user,
+		Hostname:   hostname,
+		Entrypoint: entrypoint,
+	}
+	if entrypoint != "" {
+		addCap(caps, pb.CapExecMetaEntrypoint)
+	}
+	extraHosts, err := getExtraHosts(e.base)(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	if len(extraHosts) > 0 {
+		hosts := make([]*pb.HostIP, len(extraHosts))
+		for i, h := range extraHosts {
+			hosts[i] = &pb.HostIP{Host: h.Host, IP: h.IP.String()}
+		}
+		meta.ExtraHosts = hosts
+	}
+
+	if len(e.cacheIgnore) > 0 {
+		meta.CacheIgnoreEnv = e.cacheIgnore
+		addCap(caps, pb.CapExecMetaCacheIgnoreEnv)
+	}
+
+	if e.umask != nil {
+		meta.Umask = fmt.Sprintf("%04o", uint32(*e.umask))
+		addCap(caps, pb.CapExecMetaUmask)
+	}
+
+	if len(e.passthrough) > 0 {
+		meta.PassthroughEnv = e.passthrough
+		addCap(caps, pb.CapExecMetaPassthroughEnv)
+	}
+
+	if e.timezone != nil {
+		meta.Timezone = &pb.Timezone{
+			Name:          e.timezone.Name,
+			MountZoneinfo: e.timezone.MountZoneinfo,
+		}
+		addCap(caps, pb.CapExecMetaTimezone)
+	}
+
+	if p := e.proxyEnv; p != nil {
+		meta.ProxyEnv = &pb.ProxyEnv{
+			HttpProxy:  p.HTTPProxy,
+			HttpsProxy: p.HTTPSProxy,
+			FtpProxy:   p.FTPProxy,
+			NoProxy:    p.NoProxy,
+			AllProxy:   p.AllProxy,
 		}
-		peo.Mounts = append(peo.Mounts, pm)
+		addCap(caps, pb.CapExecMetaProxy)
 	}
+	return meta, nil
+}
 
+// marshalSecrets marshals the secrets and ssh sockets of e as env variables
+// and mounts
+func (e *ExecOp) marshalSecrets() (secretenv []*pb.SecretEnv, mounts []*pb.Mount) {
 	for _, s := range e.secrets {
-		pm := &pb.Mount{
+		if s.Env != "" {
+			secretenv = append(secretenv, &pb.SecretEnv{
+				ID:       s.ID,
+				Name:     s.Env,
+				Optional: s.Optional,
+			})
+			continue
+		}
+		mounts = append(mounts, &pb.Mount{
 			Dest:      s.Target,
 			MountType: pb.MountType_SECRET,
 			SecretOpt: &pb.SecretOpt{
@@ -348,12 +540,11 @@ func (e *ExecOp) Marshal(ctx context.Context, c *Constraints) (digest.Digest, []
 				Optional: s.Optional,
 				Mode:     uint32(s.Mode),
 			},
-		}
-		peo.Mounts = append(peo.Mounts, pm)
+		})
 	}
 
 	for _, s := range e.ssh {
-		pm := &pb.Mount{
+		mounts = append(mounts, &pb.Mount{
 			Dest:      s.Target,
 			MountType: pb.MountType_SSH,
 			SSHOpt: &pb.SSHOpt{
@@ -363,16 +554,113 @@ func (e *ExecOp) Marshal(ctx context.Context, c *Constraints) (digest.Digest, []
 				Mode:     uint32(s.Mode),
 				Optional: s.Optional,
 			},
+		})
+	}
+	return secretenv, mounts
+}
+
+func (m *mount) marshal(inputIndex pb.InputIndex, outputIndex pb.OutputIndex) *pb.Mount {
+	pm := &pb.Mount{
+		Input:    inputIndex,
+		Dest:     m.target,
+		Readonly: m.readonly,
+		Output:   outputIndex,
+		Selector: m.selector,
+	}
+	if m.cacheID != "" {
+		pm.MountType = pb.MountType_CACHE
+		pm.CacheOpt = &pb.CacheOpt{
+			ID: m.cacheID,
 		}
-		peo.Mounts = append(peo.Mounts, pm)
+		switch m.cacheSharing {
+		case CacheMountShared:
+			pm.CacheOpt.Sharing = pb.CacheSharingOpt_SHARED
+		case CacheMountPrivate:
+			pm.CacheOpt.Sharing = pb.CacheSharingOpt_PRIVATE
+		case CacheMountLocked:
+			pm.CacheOpt.Sharing = pb.CacheSharingOpt_LOCKED
+		}
+	}
+	if m.tmpfs {
+		pm.MountType = pb.MountType_TMPFS
 	}
+	if m.hostPath != "" {
+		pm.MountType = pb.MountType_HOSTPATH
+		pm.HostPathOpt = &pb.HostPathOpt{
+			Name: m.hostPath,
+		}
+	}
+	return pm
+}
 
-	dt, err := pop.Marshal()
+// marshalSharedPID marshals the process of the op that shares its PID
+// namespace with e and the mounts of its secrets. The caps they require are
+// added to e.
+func (e *ExecOp) marshalSharedPID(ctx context.Context, c *Constraints) (*pb.SharedPID, []*pb.Mount, error) {
+	s := e.sharedPID
+	if s.sharedPID != nil {
+		return nil, nil, errors.Errorf("shared pid process can't share its pid namespace with another process")
+	}
+	if err := s.Validate(ctx, c); err != nil {
+		return nil, nil, err
+	}
+	meta, err := s.marshalMeta(ctx, c, &e.constraints)
 	if err != nil {
-		return "", nil, nil, nil, err
+		return nil, nil, err
 	}
-	e.Store(dt, md, e.constraints.SourceLocations, c)
-	return e.Load()
+	secretenv, secretMounts := s.marshalSecrets()
+	sp := &pb.SharedPID{
+		Meta:      meta,
+		Secretenv: secretenv,
+	}
+	for _, s := range s.secrets {
+		if s.Env != "" {
+			addCap(&e.constraints, pb.CapExecSecretEnv)
+		} else {
+			addCap(&e.constraints, pb.CapExecMountSecret)
+		}
+	}
+	if len(s.ssh) > 0 {
+		addCap(&e.constraints, pb.CapExecMountSSH)
+	}
+	return sp, secretMounts, nil
+}
+
+// marshalSharedPIDMounts adds the mounts of the op that shares its PID
+// namespace with e and the mounts of its secrets to sp. The sources of its mounts are added to the inputs of
+// e, reusing the inputs that are already mounted by e.
+func (e *ExecOp) marshalSharedPIDMounts(ctx context.Context, c *Constraints, pop *pb.Op, sp *pb.SharedPID, secretMounts []*pb.Mount) error {
+	mounts := append([]*mount{}, e.sharedPID.mounts...)
+	sort.Slice(mounts, func(i, j int) bool {
+		return mounts[i].target < mounts[j].target
+	})
+	for _, m := range mounts {
+		inputIndex := pb.Empty
+		if m.source != nil {
+			inp, err := m.source.ToInput(ctx, c)
+			if err != nil {
+				return err
+			}
+			inputIndex = pb.InputIndex(len(pop.Inputs))
+			newInput := true
+			for i, inp2 := range pop.Inputs {
+				if *inp == *inp2 {
+					inputIndex = pb.InputIndex(i)
+					newInput = false
+					break
+				}
+			}
+			if newInput {
+				pop.Inputs = append(pop.Inputs, inp)
+			}
+		}
+		sp.Mounts = append(sp.Mounts, m.marshal(inputIndex, pb.SkipOutput))
+	}
+	for _, m := range secretMounts {
+		m.Output = pb.SkipOutput
+		sp.Mounts = append(sp.Mounts, m)
+	}
+	return nil
 }
 
 func (e *ExecOp) Output() Output {
@@ -386,12 +674,31 @@ func (e *ExecOp) Inputs() (inputs []Output) {
 			mm[m.source] = struct{}{}
 		}
 	}
+	for _, o := range e.afterOutputs() {
+		mm[o] = struct{}{}
+	}
+	if e.sharedPID != nil {
+		for _, m := range e.sharedPID.mounts {
+			if m.source != nil {
+				mm[m.source] = struct{}{}
+			}
+		}
+	}
 	for o := range mm {
 		inputs = append(inputs, o)
 	}
 	return
 }
 
+func (e *ExecOp) afterOutputs() (outputs []Output) {
+	for _, s := range e.after {
+		if o := s.Output(); o != nil {
+			outputs = append(outputs, o)
+		}
+	}
+	return
+}
+
 func (e *ExecOp) getMountIndexFn(m *mount) func() (pb.OutputIndex, error) {
 	return func() (pb.OutputIndex, error) {
 		// make sure mounts are sorted
@@ -401,7 +708,7 @@ func (e *ExecOp) getMountIndexFn(m *mount) func() (pb.OutputIndex, error) {
 
 		i := 0
 		for _, m2 := range e.mounts {
-			if m2.noOutput || m2.readonly || m2.tmpfs || m2.cacheID != "" {
+			if m2.noOutput || m2.readonly || m2.tmpfs || m2.hostPath != "" || m2.cacheID != "" {
 				continue
 			}
 			if m == m2 {
@@ -459,6 +766,14 @@ func Tmpfs() MountOption {
 	}
 }
 
+// HostPath mounts the host directory that the daemon configuration allows
+// under name. The mount must use scratch as its source.
+func HostPath(name string) MountOption {
+	return func(m *mount) {
+		m.hostPath = name
+	}
+}
+
 type RunOption interface {
 	SetRunOption(es *ExecInfo)
 }
@@ -576,6 +891,19 @@ func AddSecret(dest string, opts ...SecretOption) RunOption {
 	})
 }
 
+// AddSecretEnv sets the secret with the given ID as the environment variable
+// name of the process. The value is read from the session when the process is
+// started and is not stored in the definition or in the result.
+func AddSecretEnv(id, name string, opts ...SecretOption) RunOption {
+	return runOptionFunc(func(ei *ExecInfo) {
+		s := &SecretInfo{ID: id, Env: name}
+		for _, opt := range opts {
+			opt.SetSecretOption(s)
+		}
+		ei.Secrets = append(ei.Secrets, *s)
+	})
+}
+
 type SecretOption interface {
 	SetSecretOption(*SecretInfo)
 }
@@ -593,6 +921,9 @@ type SecretInfo struct {
 	UID      int
 	GID      int
 	Optional bool
+	// Env is the name of the environment variable for secrets added with
+	// AddSecretEnv
+	Env string
 }
 
 var SecretOptional = secretOptionFunc(func(si *SecretInfo) {
@@ -619,6 +950,223 @@ func ReadonlyRootFS() RunOption {
 	})
 }
 
+// AllowExitCodes marks nonzero exit codes of the process that don't fail the
+// build. The actual exit code is still reported in the vertex logs and status.
+func AllowExitCodes(codes ...int) RunOption {
+	return runOptionFunc(func(ei *ExecInfo) {
+		ei.ExitCodes = append(ei.ExitCodes, codes...)
+	})
+}
+
+// WithSeccompProfile runs the process with the seccomp profile instead of the
+// default profile of the daemon. The profile uses the JSON format of Docker
+// seccomp profiles. As the profile can allow any syscall, the build needs the
+// same security.insecure entitlement and daemon setting as for Unconfined.
+func WithSeccompProfile(profile []byte) RunOption {
+	return runOptionFunc(func(ei *ExecInfo) {
+		ei.Seccomp = &SeccompInfo{Profile: profile}
+	})
+}
+
+// Unconfined runs the process without seccomp. The build needs to be allowed
+// the security.insecure entitlement and the daemon must allow running
+// processes without seccomp.
+func Unconfined() RunOption {
+	return runOptionFunc(func(ei *ExecInfo) {
+		ei.Seccomp = &SeccompInfo{Unconfined: true}
+	})
+}
+
+// WithApparmorProfile runs the process with the apparmor profile instead of
+// the profile of the daemon. The profile must be allowed in the daemon
+// configuration and loaded on the host. Profiles in complain mode are only
+// allowed if the unconfined profile is.
+func WithApparmorProfile(name string) RunOption {
+	return runOptionFunc(func(ei *ExecInfo) {
+		ei.ApparmorProfile = name
+	})
+}
+
+// IgnoreForCache excludes paths of the outputs of the process from the
+// content based cache keys of the operations that use the outputs, e.g. a log
+// file whose content changes on every run. The files are still part of the
+// outputs. Relative paths are resolved against the working directory. The
+// paths stay excluded in the outputs of later processes that run on top of
+// the outputs.
+func IgnoreForCache(paths ...string) RunOption {
+	return runOptionFunc(func(ei *ExecInfo) {
+		ei.IgnoreForCache = append(ei.IgnoreForCache, paths...)
+	})
+}
+
+// WithRetry runs the process again, up to n times, if it exits with one of
+// onExitCodes or any nonzero exit code if onExitCodes is empty. Every attempt
+// starts from the same inputs. Caches mounted by the process are shared
+// between attempts. The daemon limits the number of retries, and reports
+// every retry as a vertex of its own in the progress of the build.
+func WithRetry(n int, onExitCodes []int) RunOption {
+	return runOptionFunc(func(ei *ExecInfo) {
+		ei.Retry = &RetryInfo{Attempts: n, ExitCodes: onExitCodes}
+	})
+}
+
+// WithUserNSMapping runs the process in a new user namespace with the uid and
+// gid mappings. The host IDs are IDs of the user namespace of the rootless
+// daemon, e.g. to give the files of a mount the owners that they have on the
+// host. Only rootless workers support user namespace mappings.
+func WithUserNSMapping(uidMap, gidMap []IDMap) RunOption {
+	return runOptionFunc(func(ei *ExecInfo) {
+		ei.UserNSMapping = &UserNSMappingInfo{UIDMappings: uidMap, GIDMappings: gidMap}
+	})
+}
+
+// WithSysctl sets the kernel parameter key of the namespaces of the process,
+// e.g. net.core.somaxconn. The parameter has to be allowed in the daemon
+// configuration.
+func WithSysctl(key, value string) RunOption {
+	return runOptionFunc(func(ei *ExecInfo) {
+		if ei.Sysctls == nil {
+			ei.Sysctls = map[string]string{}
+		}
+		ei.Sysctls[key] = value
+	})
+}
+
+// WithDevice gives the process access to a host device, e.g. /dev/fuse.
+// Permissions is a combination of r (read), w (write) and m (mknod) and
+// defaults to rwm. The device has to be allowed in the daemon configuration.
+func WithDevice(path, permissions string) RunOption {
+	return runOptionFunc(func(ei *ExecInfo) {
+		if permissions == "" {
+			permissions = "rwm"
+		}
+		ei.Devices = append(ei.Devices, DeviceInfo{Path: path, Permissions: permissions})
+	})
+}
+
+// CacheIgnoreEnv excludes the environment variables with the given names
+// from the cache key of the process. Changing their values doesn't
+// invalidate the cache, and cached results may have been created with
+// different values.
+func CacheIgnoreEnv(keys ...string) RunOption {
+	return runOptionFunc(func(ei *ExecInfo) {
+		ei.CacheIgnoreEnv = append(ei.CacheIgnoreEnv, keys...)
+	})
+}
+
+// WithUmask sets the umask of the process. Only permission bits are valid,
+// other bits make the solve fail.
+func WithUmask(mask os.FileMode) RunOption {
+	return runOptionFunc(func(ei *ExecInfo) {
+		ei.Umask = &mask
+	})
+}
+
+// PassthroughEnv adds the environment variables with the given names from the
+// host of the worker to the process. Only variables allowed by the daemon
+// configuration are added, other names are ignored. The values are not part
+// of the cache key of the process.
+func PassthroughEnv(names ...string) RunOption {
+	return runOptionFunc(func(ei *ExecInfo) {
+		ei.PassthroughEnv = append(ei.PassthroughEnv, names...)
+	})
+}
+
+// WithTimezone sets the TZ env variable of the process to the timezone with
+// the given name in the tz database, e.g. "Europe/Berlin". Names that are not
+// valid make the solve fail.
+func WithTimezone(name string, opts ...TimezoneOption) RunOption {
+	return runOptionFunc(func(ei *ExecInfo) {
+		tz := &TimezoneInfo{Name: name}
+		for _, opt := range opts {
+			opt.SetTimezoneOption(tz)
+		}
+		ei.Timezone = tz
+	})
+}
+
+type TimezoneOption interface {
+	SetTimezoneOption(*TimezoneInfo)
+}
+
+type timezoneOptionFunc func(*TimezoneInfo)
+
+func (fn timezoneOptionFunc) SetTimezoneOption(tz *TimezoneInfo) {
+	fn(tz)
+}
+
+type TimezoneInfo struct {
+	Name          string
+	MountZoneinfo bool
+}
+
+// MountHostZoneinfo bind mounts the zoneinfo file of the timezone from the
+// host of the worker, so that the timezone works in images without tzdata.
+// The daemon configuration has to allow the timezone, otherwise the solve
+// fails.
+var MountHostZoneinfo = timezoneOptionFunc(func(tz *TimezoneInfo) {
+	tz.MountZoneinfo = true
+})
+
+// RedirectStdout writes the stdout of the process to the file at path in the
+// root filesystem instead of the build logs. Relative paths are relative to
+// the working directory. The file is written when the process exits.
+func RedirectStdout(path string) RunOption {
+	return runOptionFunc(func(ei *ExecInfo) {
+		ei.RedirectStdout = path
+	})
+}
+
+// RedirectStderr writes the stderr of the process to the file at path in the
+// root filesystem instead of the build logs. If stdout is redirected to the
+// same path, the output of both streams is interleaved in the file.
+func RedirectStderr(path string) RunOption {
+	return runOptionFunc(func(ei *ExecInfo) {
+		ei.RedirectStderr = path
+	})
+}
+
+// After makes the process wait until s has been built without mounting it or
+// making the cache key of the process depend on the contents of s. It can be
+// used to order processes that have side effects outside of their mounts.
+func After(s State) RunOption {
+	return runOptionFunc(func(ei *ExecInfo) {
+		ei.After = append(ei.After, s)
+	})
+}
+
+// WithMemoryLimit limits the memory of the process to limit bytes. A process
+// that exceeds the limit is killed and fails with an out of memory error.
+func WithMemoryLimit(limit int64) RunOption {
+	return runOptionFunc(func(ei *ExecInfo) {
+		ei.MemoryLimit = limit
+	})
+}
+
+// WithCPUQuota limits the process to quota of CPU time in each period. For
+// example, a quota of 50ms in a period of 100ms limits the process to half a
+// CPU. A zero period uses the default period of the executor.
+func WithCPUQuota(quota, period time.Duration) RunOption {
+	return runOptionFunc(func(ei *ExecInfo) {
+		ei.CPUQuota = quota
+		ei.CPUPeriod = period
+	})
+}
+
+// WithSharedPID runs the process of other together with the process in a
+// shared PID namespace, for example to run a daemon that the process talks to
+// or signals. The namespace has an init process of its own and both processes
+// run as its children. The process of other is started first and is killed
+// when the process exits. If the process of other exits first, the process
+// keeps running. The process, mounts, secrets and ssh sockets of other are
+// used with the network and security options of the process. It doesn't run
+// as a step of its own and changes it makes to its mounts are discarded.
+func WithSharedPID(other ExecState) RunOption {
+	return runOptionFunc(func(ei *ExecInfo) {
+		ei.SharedPID = other.exec
+	})
+}
+
 func WithProxy(ps ProxyEnv) RunOption {
 	return runOptionFunc(func(ei *ExecInfo) {
 		ei.ProxyEnv = &ps
@@ -627,12 +1175,70 @@ func WithProxy(ps ProxyEnv) RunOption {
 
 type ExecInfo struct {
 	constraintsWrapper
-	State          State
-	Mounts         []MountInfo
-	ReadonlyRootFS bool
-	ProxyEnv       *ProxyEnv
-	Secrets        []SecretInfo
-	SSH            []SSHInfo
+	State           State
+	Mounts          []MountInfo
+	ReadonlyRootFS  bool
+	ProxyEnv        *ProxyEnv
+	Secrets         []SecretInfo
+	SSH             []SSHInfo
+	ExitCodes       []int
+	Seccomp         *SeccompInfo
+	ApparmorProfile string
+	IgnoreForCache  []string
+	Devices         []DeviceInfo
+	CacheIgnoreEnv  []string
+	Umask           *os.FileMode
+	PassthroughEnv  []string
+	Timezone        *TimezoneInfo
+	RedirectStdout  string
+	RedirectStderr  string
+	After           []State
+	MemoryLimit     int64
+	CPUQuota        time.Duration
+	CPUPeriod       time.Duration
+	SharedPID       *ExecOp
+	Retry           *RetryInfo
+	UserNSMapping   *UserNSMappingInfo
+	Sysctls         map[string]string
+}
+
+type SeccompInfo struct {
+	Profile    []byte
+	Unconfined bool
+}
+
+type RetryInfo struct {
+	Attempts  int
+	ExitCodes []int
+}
+
+type UserNSMappingInfo struct {
+	UIDMappings []IDMap
+	GIDMappings []IDMap
+}
+
+// IDMap maps Size IDs starting at ContainerID to the IDs starting at HostID
+type IDMap struct {
+	ContainerID int
+	HostID      int
+	Size        int
+}
+
+func toPBIDMaps(in []IDMap) []*pb.IDMap {
+	out := make([]*pb.IDMap, 0, len(in))
+	for _, m := range in {
+		out = append(out, &pb.IDMap{
+			ContainerID: uint32(m.ContainerID),
+			HostID:      uint32(m.HostID),
+			Size_:       uint32(m.Size),
+		})
+	}
+	return out
+}
+
+type DeviceInfo struct {
+	Path        string
+	Permissions string
 }
 
 type MountInfo struct {
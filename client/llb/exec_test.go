@@ -2,9 +2,13 @@ package llb
 
 import (
 	"context"
+	"net"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/apicaps"
 	"github.com/stretchr/testify/require"
 )
 
@@ -50,3 +54,480 @@ func TestValidGetMountIndex(t *testing.T) {
 	require.NoError(t, err, "failed to getIndex")
 	require.Equal(t, pb.OutputIndex(1), mountIndex, "unexpected mount index")
 }
+
+func TestExecEntrypoint(t *testing.T) {
+	t.Parallel()
+
+	st := Image("foo").Run(WithEntrypoint("/opt/my tools/dbg"), Shlex("run --flag")).Root()
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	require.Equal(t, 3, len(arr))
+
+	dgst, idx := last(t, arr)
+	require.Equal(t, 0, idx)
+
+	exec := m[dgst].Op.(*pb.Op_Exec).Exec
+	require.Equal(t, "/opt/my tools/dbg", exec.Meta.Entrypoint)
+	require.Equal(t, []string{"run", "--flag"}, exec.Meta.Args)
+	require.Equal(t, []string{"/opt/my tools/dbg", "run", "--flag"}, exec.Meta.ProcessArgs())
+
+	_, ok := def.Metadata[dgst].Caps[pb.CapExecMetaEntrypoint]
+	require.True(t, ok)
+}
+
+func TestHostPathMount(t *testing.T) {
+	t.Parallel()
+
+	st := Image("foo").Run(Shlex("args"), AddMount("/cache", Scratch(), HostPath("shared"))).Root()
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	dgst, _ := last(t, arr)
+
+	exec := m[dgst].Op.(*pb.Op_Exec).Exec
+	require.Equal(t, 2, len(exec.Mounts))
+	require.Equal(t, "/cache", exec.Mounts[1].Dest)
+	require.Equal(t, pb.MountType_HOSTPATH, exec.Mounts[1].MountType)
+	require.Equal(t, "shared", exec.Mounts[1].HostPathOpt.Name)
+	require.Equal(t, pb.SkipOutput, exec.Mounts[1].Output)
+
+	_, ok := def.Metadata[dgst].Caps[pb.CapExecMountHostPath]
+	require.True(t, ok)
+
+	st = Image("foo").Run(Shlex("args")).AddMount("/cache", Scratch(), HostPath("shared"))
+	_, err = st.Marshal(context.TODO())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't be used as a parent")
+
+	st = Image("foo").Run(Shlex("args"), AddMount("/cache", Image("bar"), HostPath("shared"))).Root()
+	_, err = st.Marshal(context.TODO())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "must use scratch")
+}
+
+func TestExecSeccomp(t *testing.T) {
+	t.Parallel()
+
+	profile := []byte(`{"defaultAction":"SCMP_ACT_ALLOW"}`)
+	st := Image("foo").Run(Shlex("args"), WithSeccompProfile(profile)).Root()
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	dgst, _ := last(t, arr)
+
+	exec := m[dgst].Op.(*pb.Op_Exec).Exec
+	require.Equal(t, string(profile), exec.Seccomp.Profile)
+	require.False(t, exec.Seccomp.Unconfined)
+
+	_, ok := def.Metadata[dgst].Caps[pb.CapExecMetaSeccomp]
+	require.True(t, ok)
+
+	st = Image("foo").Run(Shlex("args"), Unconfined()).Root()
+	def, err = st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr = parseDef(t, def.Def)
+	dgst, _ = last(t, arr)
+
+	exec = m[dgst].Op.(*pb.Op_Exec).Exec
+	require.True(t, exec.Seccomp.Unconfined)
+	require.Equal(t, "", exec.Seccomp.Profile)
+
+	st = Image("foo").Run(Shlex("args")).Root()
+	def, err = st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr = parseDef(t, def.Def)
+	dgst, _ = last(t, arr)
+	require.Nil(t, m[dgst].Op.(*pb.Op_Exec).Exec.Seccomp)
+}
+
+func TestExecUserNSMapping(t *testing.T) {
+	t.Parallel()
+
+	st := Image("foo").Run(Shlex("args"), WithUserNSMapping(
+		[]IDMap{{ContainerID: 0, HostID: 1000, Size: 1}, {ContainerID: 1, HostID: 100000, Size: 65535}},
+		[]IDMap{{ContainerID: 0, HostID: 1000, Size: 1}},
+	)).Root()
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	dgst, _ := last(t, arr)
+
+	require.Equal(t, &pb.UserNSMapping{
+		UidMappings: []*pb.IDMap{{ContainerID: 0, HostID: 1000, Size_: 1}, {ContainerID: 1, HostID: 100000, Size_: 65535}},
+		GidMappings: []*pb.IDMap{{ContainerID: 0, HostID: 1000, Size_: 1}},
+	}, m[dgst].Op.(*pb.Op_Exec).Exec.UserNSMapping)
+	_, ok := def.Metadata[dgst].Caps[pb.CapExecUserNSMapping]
+	require.True(t, ok)
+
+	st = Image("foo").Run(Shlex("args")).Root()
+	def, err = st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr = parseDef(t, def.Def)
+	dgst, _ = last(t, arr)
+	require.Nil(t, m[dgst].Op.(*pb.Op_Exec).Exec.UserNSMapping)
+	_, ok = def.Metadata[dgst].Caps[pb.CapExecUserNSMapping]
+	require.False(t, ok)
+}
+
+func TestExecSysctl(t *testing.T) {
+	t.Parallel()
+
+	st := Image("foo").Run(Shlex("args"), WithSysctl("net.core.somaxconn", "1024"), WithSysctl("net.ipv4.ip_forward", "1")).Root()
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	dgst, _ := last(t, arr)
+
+	require.Equal(t, map[string]string{
+		"net.core.somaxconn":  "1024",
+		"net.ipv4.ip_forward": "1",
+	}, m[dgst].Op.(*pb.Op_Exec).Exec.Sysctls)
+	_, ok := def.Metadata[dgst].Caps[pb.CapExecSysctl]
+	require.True(t, ok)
+
+	st = Image("foo").Run(Shlex("args")).Root()
+	def, err = st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr = parseDef(t, def.Def)
+	dgst, _ = last(t, arr)
+	require.Nil(t, m[dgst].Op.(*pb.Op_Exec).Exec.Sysctls)
+	_, ok = def.Metadata[dgst].Caps[pb.CapExecSysctl]
+	require.False(t, ok)
+}
+
+func TestExecApparmorProfile(t *testing.T) {
+	t.Parallel()
+
+	st := Image("foo").Run(Shlex("args"), WithApparmorProfile("build-restricted")).Root()
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	dgst, _ := last(t, arr)
+
+	require.Equal(t, "build-restricted", m[dgst].Op.(*pb.Op_Exec).Exec.ApparmorProfile)
+	_, ok := def.Metadata[dgst].Caps[pb.CapExecMetaApparmor]
+	require.True(t, ok)
+
+	st = Image("foo").Run(Shlex("args")).Root()
+	def, err = st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr = parseDef(t, def.Def)
+	dgst, _ = last(t, arr)
+	require.Equal(t, "", m[dgst].Op.(*pb.Op_Exec).Exec.ApparmorProfile)
+	_, ok = def.Metadata[dgst].Caps[pb.CapExecMetaApparmor]
+	require.False(t, ok)
+}
+
+func TestExecIgnoreForCache(t *testing.T) {
+	t.Parallel()
+
+	st := Image("foo").Run(Shlex("args"), IgnoreForCache("/out/log"), IgnoreForCache("build.log")).Root()
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	dgst, _ := last(t, arr)
+
+	require.Equal(t, []string{"/out/log", "build.log"}, m[dgst].Op.(*pb.Op_Exec).Exec.IgnoreForCache)
+	_, ok := def.Metadata[dgst].Caps[pb.CapExecIgnoreForCache]
+	require.True(t, ok)
+}
+
+func TestExecRetry(t *testing.T) {
+	t.Parallel()
+
+	st := Image("foo").Run(Shlex("args"), WithRetry(3, []int{1, 2})).Root()
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	dgst, _ := last(t, arr)
+
+	require.Equal(t, &pb.ExecRetry{Attempts: 3, ExitCodes: []int32{1, 2}}, m[dgst].Op.(*pb.Op_Exec).Exec.Retry)
+	_, ok := def.Metadata[dgst].Caps[pb.CapExecRetry]
+	require.True(t, ok)
+
+	st = Image("foo").Run(Shlex("args"), WithRetry(0, nil)).Root()
+	def, err = st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr = parseDef(t, def.Def)
+	dgst, _ = last(t, arr)
+	require.Nil(t, m[dgst].Op.(*pb.Op_Exec).Exec.Retry)
+}
+
+func TestExecDevices(t *testing.T) {
+	t.Parallel()
+
+	st := Image("foo").Run(Shlex("args"), WithDevice("/dev/fuse", ""), WithDevice("/dev/nvidia0", "rw")).Root()
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	dgst, _ := last(t, arr)
+
+	exec := m[dgst].Op.(*pb.Op_Exec).Exec
+	require.Equal(t, []*pb.Device{
+		{Path: "/dev/fuse", Permissions: "rwm"},
+		{Path: "/dev/nvidia0", Permissions: "rw"},
+	}, exec.Devices)
+
+	_, ok := def.Metadata[dgst].Caps[pb.CapExecMetaDevices]
+	require.True(t, ok)
+}
+
+func TestExecSecretEnv(t *testing.T) {
+	t.Parallel()
+
+	st := Image("foo").Run(Shlex("args"), AddSecretEnv("token", "FOO_TOKEN"), AddSecretEnv("bar", "BAR", SecretOptional), AddSecret("/run/secrets/baz")).Root()
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	dgst, _ := last(t, arr)
+
+	exec := m[dgst].Op.(*pb.Op_Exec).Exec
+	require.Equal(t, []*pb.SecretEnv{
+		{ID: "token", Name: "FOO_TOKEN"},
+		{ID: "bar", Name: "BAR", Optional: true},
+	}, exec.Secretenv)
+	require.Equal(t, 2, len(exec.Mounts))
+	require.Equal(t, pb.MountType_SECRET, exec.Mounts[1].MountType)
+
+	_, ok := def.Metadata[dgst].Caps[pb.CapExecSecretEnv]
+	require.True(t, ok)
+	_, ok = def.Metadata[dgst].Caps[pb.CapExecMountSecret]
+	require.True(t, ok)
+}
+
+func TestExecUmask(t *testing.T) {
+	t.Parallel()
+
+	st := Image("foo").Run(Shlex("args"), WithUmask(0027)).Root()
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	dgst, _ := last(t, arr)
+
+	exec := m[dgst].Op.(*pb.Op_Exec).Exec
+	require.Equal(t, "0027", exec.Meta.Umask)
+
+	umask, err := exec.Meta.ParseUmask()
+	require.NoError(t, err)
+	require.Equal(t, uint32(0027), *umask)
+
+	_, ok := def.Metadata[dgst].Caps[pb.CapExecMetaUmask]
+	require.True(t, ok)
+
+	st = Image("foo").Run(Shlex("args"), WithUmask(os.ModeDir|0022)).Root()
+	def, err = st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr = parseDef(t, def.Def)
+	dgst, _ = last(t, arr)
+
+	_, err = m[dgst].Op.(*pb.Op_Exec).Exec.Meta.ParseUmask()
+	require.Error(t, err)
+}
+
+func TestExecPassthroughEnv(t *testing.T) {
+	t.Parallel()
+
+	st := Image("foo").Run(Shlex("args"), PassthroughEnv("HTTP_PROXY", "NO_PROXY")).Root()
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	dgst, _ := last(t, arr)
+
+	exec := m[dgst].Op.(*pb.Op_Exec).Exec
+	require.Equal(t, []string{"HTTP_PROXY", "NO_PROXY"}, exec.Meta.PassthroughEnv)
+
+	_, ok := def.Metadata[dgst].Caps[pb.CapExecMetaPassthroughEnv]
+	require.True(t, ok)
+}
+
+func TestExecTimezone(t *testing.T) {
+	t.Parallel()
+
+	st := Image("foo").Run(Shlex("args"), WithTimezone("Europe/Berlin", MountHostZoneinfo)).Root()
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	dgst, _ := last(t, arr)
+
+	exec := m[dgst].Op.(*pb.Op_Exec).Exec
+	require.Equal(t, &pb.Timezone{Name: "Europe/Berlin", MountZoneinfo: true}, exec.Meta.Timezone)
+
+	_, ok := def.Metadata[dgst].Caps[pb.CapExecMetaTimezone]
+	require.True(t, ok)
+
+	st = Image("foo").Run(Shlex("args"), WithTimezone("UTC")).Root()
+	def, err = st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr = parseDef(t, def.Def)
+	dgst, _ = last(t, arr)
+	require.Equal(t, &pb.Timezone{Name: "UTC"}, m[dgst].Op.(*pb.Op_Exec).Exec.Meta.Timezone)
+}
+
+func TestExecRedirect(t *testing.T) {
+	t.Parallel()
+
+	st := Image("foo").Run(Shlex("args"), RedirectStdout("/out.log"), RedirectStderr("/out.log")).Root()
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	dgst, _ := last(t, arr)
+
+	exec := m[dgst].Op.(*pb.Op_Exec).Exec
+	require.Equal(t, "/out.log", exec.Meta.RedirectStdout)
+	require.Equal(t, "/out.log", exec.Meta.RedirectStderr)
+
+	_, ok := def.Metadata[dgst].Caps[pb.CapExecMetaRedirect]
+	require.True(t, ok)
+}
+
+func TestExecAfter(t *testing.T) {
+	t.Parallel()
+
+	base := Image("foo")
+	first := base.Run(Shlex("first")).Root()
+	st := base.Run(Shlex("second"), After(first), After(base), After(Scratch())).Root()
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	require.Equal(t, 4, len(arr))
+	dgst, idx := last(t, arr)
+	require.Equal(t, 0, idx)
+
+	op := m[dgst]
+	require.Equal(t, 2, len(op.Inputs))
+	exec := op.Op.(*pb.Op_Exec).Exec
+	require.Equal(t, []pb.InputIndex{1}, exec.After)
+	require.Equal(t, pb.InputIndex(0), exec.Mounts[0].Input)
+
+	firstExec := m[op.Inputs[1].Digest].Op.(*pb.Op_Exec).Exec
+	require.Equal(t, []string{"first"}, firstExec.Meta.Args)
+
+	_, ok := def.Metadata[dgst].Caps[pb.CapExecAfter]
+	require.True(t, ok)
+}
+
+func TestExecResources(t *testing.T) {
+	t.Parallel()
+
+	st := Image("foo").Run(Shlex("args"), WithMemoryLimit(64<<20), WithCPUQuota(50*time.Millisecond, 100*time.Millisecond)).Root()
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	dgst, _ := last(t, arr)
+
+	exec := m[dgst].Op.(*pb.Op_Exec).Exec
+	require.Equal(t, &pb.Resources{Memory: 64 << 20, CpuQuota: 50000, CpuPeriod: 100000}, exec.Resources)
+
+	_, ok := def.Metadata[dgst].Caps[pb.CapExecMetaResources]
+	require.True(t, ok)
+
+	st = Image("foo").Run(Shlex("args"), WithMemoryLimit(-1)).Root()
+	_, err = st.Marshal(context.TODO())
+	require.Error(t, err)
+}
+
+func TestExecSharedPID(t *testing.T) {
+	t.Parallel()
+
+	daemon := Image("daemon").Run(Shlex("daemon --foreground"), Dir("/srv"), AddMount("/data", Image("data"), Readonly), AddMount("/cache", Scratch(), AsPersistentCacheDir("cache", CacheMountShared)))
+	st := Image("foo").Run(Shlex("client"), WithSharedPID(daemon)).Root()
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	// the process of daemon is not a step of its own
+	require.Equal(t, 5, len(arr))
+	dgst, idx := last(t, arr)
+	require.Equal(t, 0, idx)
+
+	op := m[dgst]
+	require.Equal(t, 3, len(op.Inputs))
+	exec := op.Op.(*pb.Op_Exec).Exec
+	require.Equal(t, []string{"client"}, exec.Meta.Args)
+	require.Equal(t, 1, len(exec.Mounts))
+
+	sp := exec.SharedPID
+	require.NotNil(t, sp)
+	require.Equal(t, []string{"daemon", "--foreground"}, sp.Meta.Args)
+	require.Equal(t, "/srv", sp.Meta.Cwd)
+	require.Equal(t, 3, len(sp.Mounts))
+
+	require.Equal(t, "/", sp.Mounts[0].Dest)
+	require.Equal(t, pb.SkipOutput, sp.Mounts[0].Output)
+	src := m[op.Inputs[sp.Mounts[0].Input].Digest].Op.(*pb.Op_Source).Source
+	require.Equal(t, "docker-image://docker.io/library/daemon:latest", src.Identifier)
+
+	require.Equal(t, "/cache", sp.Mounts[1].Dest)
+	require.Equal(t, pb.MountType_CACHE, sp.Mounts[1].MountType)
+	require.Equal(t, pb.Empty, sp.Mounts[1].Input)
+
+	require.Equal(t, "/data", sp.Mounts[2].Dest)
+	require.True(t, sp.Mounts[2].Readonly)
+	src = m[op.Inputs[sp.Mounts[2].Input].Digest].Op.(*pb.Op_Source).Source
+	require.Equal(t, "docker-image://docker.io/library/data:latest", src.Identifier)
+
+	_, ok := def.Metadata[dgst].Caps[pb.CapExecSharedPID]
+	require.True(t, ok)
+
+	// inputs that are already mounted are reused
+	st = Image("foo").Run(Shlex("client"), WithSharedPID(Image("foo").Run(Shlex("daemon")))).Root()
+	def, err = st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr = parseDef(t, def.Def)
+	dgst, _ = last(t, arr)
+	op = m[dgst]
+	require.Equal(t, 1, len(op.Inputs))
+	require.Equal(t, pb.InputIndex(0), op.Op.(*pb.Op_Exec).Exec.SharedPID.Mounts[0].Input)
+
+	// the proxy env, extra hosts and secrets of the process are kept
+	daemon = Image("foo").Run(Shlex("daemon"),
+		WithProxy(ProxyEnv{HTTPProxy: "http://proxy:3128"}),
+		AddExtraHost("registry", net.ParseIP("10.0.0.1")),
+		AddSecret("/run/secrets/token", SecretID("token")),
+		AddSecretEnv("key", "API_KEY"))
+	st = Image("foo").Run(Shlex("client"), WithSharedPID(daemon)).Root()
+	def, err = st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr = parseDef(t, def.Def)
+	dgst, _ = last(t, arr)
+	sp = m[dgst].Op.(*pb.Op_Exec).Exec.SharedPID
+	require.Equal(t, "http://proxy:3128", sp.Meta.ProxyEnv.HttpProxy)
+	require.Equal(t, []*pb.HostIP{{Host: "registry", IP: "10.0.0.1"}}, sp.Meta.ExtraHosts)
+	require.Equal(t, []*pb.SecretEnv{{ID: "key", Name: "API_KEY"}}, sp.Secretenv)
+	require.Equal(t, 2, len(sp.Mounts))
+	require.Equal(t, pb.MountType_SECRET, sp.Mounts[1].MountType)
+	require.Equal(t, "/run/secrets/token", sp.Mounts[1].Dest)
+	require.Equal(t, "token", sp.Mounts[1].SecretOpt.ID)
+	require.Equal(t, pb.SkipOutput, sp.Mounts[1].Output)
+	for _, c := range []apicaps.CapID{pb.CapExecMetaProxy, pb.CapExecSecretEnv, pb.CapExecMountSecret} {
+		_, ok := def.Metadata[dgst].Caps[c]
+		require.True(t, ok, c)
+	}
+}
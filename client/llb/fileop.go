@@ -3,6 +3,7 @@ package llb
 import (
 	"context"
 	_ "crypto/sha256" // for opencontainers/go-digest
+	"net/url"
 	"os"
 	"path"
 	"strconv"
@@ -423,6 +424,44 @@ func Copy(input CopyInput, src, dest string, opts ...CopyOption) *FileAction {
 	}
 }
 
+// CopyFromURL copies the file downloaded from rawURL to dest. It is a shorthand
+// for copying the file of an HTTP source, the options are applied to the
+// source. A dest ending with "/" is a directory that the file is copied into
+// with its filename, the missing parent directories of dest are created.
+//
+// Example:
+// llb.Image("alpine").File(llb.CopyFromURL("https://example.com/foo.tar.gz", "/src/", llb.Checksum(dgst), llb.Chmod(0600)))
+func CopyFromURL(rawURL, dest string, opts ...HTTPOption) *FileAction {
+	var hi HTTPInfo
+	for _, o := range opts {
+		o.SetHTTPOption(&hi)
+	}
+	filename := hi.Filename
+	if filename == "" {
+		if strings.HasSuffix(dest, "/") {
+			filename = urlFilename(rawURL)
+		} else {
+			filename = path.Base(dest)
+		}
+		opts = append(opts[:len(opts):len(opts)], Filename(filename))
+	}
+	return Copy(HTTP(rawURL, opts...), "/"+filename, dest, &CopyInfo{
+		CreateDestPath: true,
+	})
+}
+
+// urlFilename returns the filename that the HTTP source uses for rawURL when
+// the server doesn't set one
+func urlFilename(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err == nil {
+		if base := path.Base(u.Path); base != "." && base != "/" {
+			return base
+		}
+	}
+	return "download"
+}
+
 type CopyOption interface {
 	SetCopyOption(*CopyInfo)
 }
@@ -439,6 +478,8 @@ type CopyInfo struct {
 	AllowEmptyWildcard  bool
 	ChownOpt            *ChownOpt
 	CreatedTime         *time.Time
+	CreatedTimeNow      bool
+	DirMode             *os.FileMode
 }
 
 func (mi *CopyInfo) SetCopyOption(mi2 *CopyInfo) {
@@ -473,12 +514,16 @@ func (a *fileActionCopy) toProtoAction(ctx context.Context, parent string, base
 		AttemptUnpackDockerCompatibility: a.info.AttemptUnpack,
 		CreateDestPath:                   a.info.CreateDestPath,
 		Timestamp:                        marshalTime(a.info.CreatedTime),
+		TimestampNow:                     a.info.CreatedTimeNow,
 	}
 	if a.info.Mode != nil {
 		c.Mode = int32(*a.info.Mode)
 	} else {
 		c.Mode = -1
 	}
+	if a.info.DirMode != nil {
+		c.DirMode = int32(*a.info.DirMode & 0777)
+	}
 	return &pb.FileAction_Copy{
 		Copy: c,
 	}, nil
@@ -508,6 +553,12 @@ func (a *fileActionCopy) addCaps(f *FileOp) {
 	if len(a.info.IncludePatterns) != 0 || len(a.info.ExcludePatterns) != 0 {
 		addCap(&f.constraints, pb.CapFileCopyIncludeExcludePatterns)
 	}
+	if a.info.CreatedTimeNow {
+		addCap(&f.constraints, pb.CapFileCopyTimestampNow)
+	}
+	if a.info.DirMode != nil {
+		addCap(&f.constraints, pb.CapFileCopyDirMode)
+	}
 }
 
 type CreatedTime time.Time
@@ -528,6 +579,48 @@ func (c CreatedTime) SetCopyOption(mi *CopyInfo) {
 	mi.CreatedTime = (*time.Time)(&c)
 }
 
+type copyTimestamp struct {
+	t   time.Time
+	now bool
+}
+
+// WithCopyTimestamp sets the modification time of all copied files and
+// directories to t instead of preserving the source times.
+func WithCopyTimestamp(t time.Time) CopyOption {
+	return copyTimestamp{t: t}
+}
+
+// WithCopyTimestampNow sets the modification time of all copied files and
+// directories to the time the copy runs. The result is not reproducible.
+func WithCopyTimestampNow() CopyOption {
+	return copyTimestamp{now: true}
+}
+
+func (c copyTimestamp) SetCopyOption(ci *CopyInfo) {
+	if c.now {
+		ci.CreatedTime = nil
+		ci.CreatedTimeNow = true
+		return
+	}
+	t := c.t
+	ci.CreatedTime = &t
+	ci.CreatedTimeNow = false
+}
+
+type copyDirMode os.FileMode
+
+// WithCopyDirMode sets the permission bits of the parent directories of the
+// destination that are created by the copy. They are created with 0755 by
+// default. The mode of the copied directories is not changed.
+func WithCopyDirMode(mode os.FileMode) CopyOption {
+	return copyDirMode(mode)
+}
+
+func (m copyDirMode) SetCopyOption(ci *CopyInfo) {
+	mode := os.FileMode(m)
+	ci.DirMode = &mode
+}
+
 func marshalTime(t *time.Time) int64 {
 	if t == nil {
 		return -1
@@ -683,17 +776,11 @@ func (f *FileOp) Marshal(ctx context.Context, c *Constraints) (digest.Digest, []
 		f.constraints.Platform = p
 	}
 
-	pop, md := MarshalConstraints(c, &f.constraints)
-	pop.Op = &pb.Op_File{
-		File: pfo,
-	}
-
 	state := newMarshalState(ctx)
 	_, err := state.add(f.action, c)
 	if err != nil {
 		return "", nil, nil, nil, err
 	}
-	pop.Inputs = state.inputs
 
 	for i, st := range state.actions {
 		if adder, isCapAdder := st.action.(capAdder); isCapAdder {
@@ -726,6 +813,13 @@ func (f *FileOp) Marshal(ctx context.Context, c *Constraints) (digest.Digest, []
 		})
 	}
 
+	// caps are added by the actions so constraints are marshaled last
+	pop, md := MarshalConstraints(c, &f.constraints)
+	pop.Op = &pb.Op_File{
+		File: pfo,
+	}
+	pop.Inputs = state.inputs
+
 	dt, err := pop.Marshal()
 	if err != nil {
 		return "", nil, nil, nil, err
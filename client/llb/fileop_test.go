@@ -641,6 +641,108 @@ func TestFileCreatedTime(t *testing.T) {
 	require.Equal(t, dt3.UnixNano(), copy.Timestamp)
 }
 
+func TestFileCopyTimestamp(t *testing.T) {
+	t.Parallel()
+
+	dt := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+
+	st := Image("foo").File(
+		Copy(Scratch(), "a", "b", WithCopyTimestamp(dt)).
+			Copy(Scratch(), "c", "d", WithCopyTimestampNow()).
+			Copy(Scratch(), "e", "f"))
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	dgst, _ := last(t, arr)
+	f := m[dgst].Op.(*pb.Op_File).File
+	require.Equal(t, 3, len(f.Actions))
+
+	copy := f.Actions[0].Action.(*pb.FileAction_Copy).Copy
+	require.Equal(t, dt.UnixNano(), copy.Timestamp)
+	require.False(t, copy.TimestampNow)
+
+	copy = f.Actions[1].Action.(*pb.FileAction_Copy).Copy
+	require.Equal(t, int64(-1), copy.Timestamp)
+	require.True(t, copy.TimestampNow)
+
+	copy = f.Actions[2].Action.(*pb.FileAction_Copy).Copy
+	require.Equal(t, int64(-1), copy.Timestamp)
+	require.False(t, copy.TimestampNow)
+
+	_, ok := def.Metadata[dgst].Caps[pb.CapFileCopyTimestampNow]
+	require.True(t, ok)
+}
+
+func TestFileCopyDirMode(t *testing.T) {
+	t.Parallel()
+
+	st := Image("foo").File(
+		Copy(Scratch(), "a", "/b/c/d", &CopyInfo{CreateDestPath: true}, WithCopyDirMode(0700), WithUIDGID(1, 2)).
+			Copy(Scratch(), "e", "/f/g"))
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	dgst, _ := last(t, arr)
+	f := m[dgst].Op.(*pb.Op_File).File
+	require.Equal(t, 2, len(f.Actions))
+
+	copy := f.Actions[0].Action.(*pb.FileAction_Copy).Copy
+	require.Equal(t, int32(0700), copy.DirMode)
+	require.Equal(t, int32(-1), copy.Mode)
+	require.Equal(t, 1, int(copy.Owner.User.User.(*pb.UserOpt_ByID).ByID))
+
+	copy = f.Actions[1].Action.(*pb.FileAction_Copy).Copy
+	require.Equal(t, int32(0), copy.DirMode)
+
+	_, ok := def.Metadata[dgst].Caps[pb.CapFileCopyDirMode]
+	require.True(t, ok)
+}
+
+func TestFileCopyFromURL(t *testing.T) {
+	t.Parallel()
+
+	dgst := digest.FromString("foo")
+	st := Image("foo").Dir("/tmp").File(CopyFromURL("https://example.com/dl/foo.tar.gz?x=y", "dir/", Checksum(dgst), HTTPHeader("Accept", "application/gzip"), HTTPSecretHeader("Authorization", "token"), Chmod(0600)))
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	require.Equal(t, 4, len(arr))
+
+	dgst2, _ := last(t, arr)
+	f := m[dgst2].Op.(*pb.Op_File).File
+	require.Equal(t, 2, len(m[dgst2].Inputs))
+
+	src := m[m[dgst2].Inputs[1].Digest].Op.(*pb.Op_Source).Source
+	require.Equal(t, "https://example.com/dl/foo.tar.gz?x=y", src.Identifier)
+	require.Equal(t, map[string]string{
+		pb.AttrHTTPChecksum:                             dgst.String(),
+		pb.AttrHTTPFilename:                             "foo.tar.gz",
+		pb.AttrHTTPPerm:                                 "0600",
+		pb.AttrHTTPHeaderPrefix + "Accept":              "application/gzip",
+		pb.AttrHTTPSecretHeaderPrefix + "Authorization": "token",
+	}, src.Attrs)
+
+	require.Equal(t, 1, len(f.Actions))
+	copy := f.Actions[0].Action.(*pb.FileAction_Copy).Copy
+	require.Equal(t, "/foo.tar.gz", copy.Src)
+	require.Equal(t, "/tmp/dir/", copy.Dest)
+	require.True(t, copy.CreateDestPath)
+
+	st = Scratch().File(CopyFromURL("https://example.com/", "/bin/tool", Filename("tool-linux")))
+	def, err = st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr = parseDef(t, def.Def)
+	dgst2, _ = last(t, arr)
+	f = m[dgst2].Op.(*pb.Op_File).File
+	copy = f.Actions[0].Action.(*pb.FileAction_Copy).Copy
+	require.Equal(t, "/tool-linux", copy.Src)
+	require.Equal(t, "/bin/tool", copy.Dest)
+}
+
 func parseDef(t *testing.T, def [][]byte) (map[digest.Digest]pb.Op, []pb.Op) {
 	m := map[digest.Digest]pb.Op{}
 	arr := make([]pb.Op, 0, len(def))
@@ -16,6 +16,7 @@ type contextKeyT string
 
 var (
 	keyArgs      = contextKeyT("llb.exec.args")
+	keyEntry     = contextKeyT("llb.exec.entrypoint")
 	keyDir       = contextKeyT("llb.exec.dir")
 	keyEnv       = contextKeyT("llb.exec.env")
 	keyUser      = contextKeyT("llb.exec.user")
@@ -163,6 +164,27 @@ func getHostname(s State) func(context.Context, *Constraints) (string, error) {
 	}
 }
 
+// WithEntrypoint sets the executable that is run with the args appended to
+// it. Unlike args set with Shlex, the path is used verbatim.
+func WithEntrypoint(path string) StateOption {
+	return func(s State) State {
+		return s.WithValue(keyEntry, path)
+	}
+}
+
+func getEntrypoint(s State) func(context.Context, *Constraints) (string, error) {
+	return func(ctx context.Context, c *Constraints) (string, error) {
+		v, err := s.getValue(keyEntry)(ctx, c)
+		if err != nil {
+			return "", err
+		}
+		if v != nil {
+			return v.(string), nil
+		}
+		return "", nil
+	}
+}
+
 func args(args ...string) StateOption {
 	return func(s State) State {
 		return s.WithValue(keyArgs, args)
@@ -67,6 +67,23 @@ func TestImageResolveDigest(t *testing.T) {
 	require.Equal(t, "/foo", d)
 }
 
+func TestImagePullPolicy(t *testing.T) {
+	t.Parallel()
+
+	for policy, mode := range map[PullPolicy]string{
+		PullPolicyAlways:       pb.AttrImageResolveModeForcePull,
+		PullPolicyIfNotPresent: pb.AttrImageResolveModePreferLocal,
+		PullPolicyNever:        pb.AttrImageResolveModeLocalOnly,
+	} {
+		def, err := Image("alpine", WithPullPolicy(policy)).Marshal(context.TODO())
+		require.NoError(t, err)
+
+		_, arr := parseDef(t, def.Def)
+		require.Equal(t, 2, len(arr))
+		require.Equal(t, mode, arr[0].Op.(*pb.Op_Source).Source.Attrs[pb.AttrImageResolveMode])
+	}
+}
+
 type testResolver struct {
 	digest   digest.Digest
 	dir      string
@@ -110,12 +110,21 @@ func Image(ref string, opts ...ImageOption) State {
 		if info.resolveMode == ResolveModeForcePull {
 			addCap(&info.Constraints, pb.CapSourceImageResolveMode) // only require cap for security enforced mode
 		}
+		if info.resolveMode == ResolveModeLocalOnly {
+			addCap(&info.Constraints, pb.CapSourceImageResolveModeLocalOnly)
+		}
 	}
 
 	if info.RecordType != "" {
 		attrs[pb.AttrImageRecordType] = info.RecordType
 	}
 
+	if len(info.mirrorPreference) > 0 {
+		dt, _ := json.Marshal(info.mirrorPreference) // empty on error
+		attrs[pb.AttrImageMirrorPreference] = string(dt)
+		addCap(&info.Constraints, pb.CapSourceImageMirrorPreference)
+	}
+
 	src := NewSource("docker-image://"+ref, attrs, info.Constraints) // controversial
 	if err != nil {
 		src.err = err
@@ -180,6 +189,7 @@ const (
 	ResolveModeDefault ResolveMode = iota
 	ResolveModeForcePull
 	ResolveModePreferLocal
+	ResolveModeLocalOnly
 )
 
 func (r ResolveMode) SetImageOption(ii *ImageInfo) {
@@ -194,17 +204,61 @@ func (r ResolveMode) String() string {
 		return pb.AttrImageResolveModeForcePull
 	case ResolveModePreferLocal:
 		return pb.AttrImageResolveModePreferLocal
+	case ResolveModeLocalOnly:
+		return pb.AttrImageResolveModeLocalOnly
 	default:
 		return ""
 	}
 }
 
+// PullPolicy controls when the image source checks the registry for the
+// image reference.
+type PullPolicy int
+
+const (
+	// PullPolicyAlways resolves the reference from the registry even if the
+	// image is available locally.
+	PullPolicyAlways PullPolicy = iota
+	// PullPolicyIfNotPresent only resolves the reference from the registry
+	// if the image is not available locally.
+	PullPolicyIfNotPresent
+	// PullPolicyNever only uses content that is available locally, i.e. the
+	// image was pulled by the daemon before or is in its image store.
+	PullPolicyNever
+)
+
+// WithPullPolicy sets the resolve mode of the image source from the pull
+// policy.
+func WithPullPolicy(p PullPolicy) ImageOption {
+	return imageOptionFunc(func(ii *ImageInfo) {
+		switch p {
+		case PullPolicyAlways:
+			ii.resolveMode = ResolveModeForcePull
+		case PullPolicyIfNotPresent:
+			ii.resolveMode = ResolveModePreferLocal
+		case PullPolicyNever:
+			ii.resolveMode = ResolveModeLocalOnly
+		}
+	})
+}
+
+// WithMirrorPreference sets the registry hosts that should be tried first
+// when pulling the image, in the order they are passed. Hosts that fail fall
+// through to the next preferred host and then to the remaining mirrors
+// configured in the daemon.
+func WithMirrorPreference(hosts ...string) ImageOption {
+	return imageOptionFunc(func(ii *ImageInfo) {
+		ii.mirrorPreference = append(ii.mirrorPreference, hosts...)
+	})
+}
+
 type ImageInfo struct {
 	constraintsWrapper
-	metaResolver  ImageMetaResolver
-	resolveDigest bool
-	resolveMode   ResolveMode
-	RecordType    string
+	metaResolver     ImageMetaResolver
+	resolveDigest    bool
+	resolveMode      ResolveMode
+	mirrorPreference []string
+	RecordType       string
 }
 
 func Git(remote, ref string, opts ...GitOption) State {
@@ -375,6 +429,20 @@ func Local(name string, opts ...LocalOption) State {
 			addCap(&gi.Constraints, pb.CapSourceLocalDiffer)
 		}
 	}
+	if gi.PreserveOwnership {
+		attrs[pb.AttrLocalPreserveOwnership] = "true"
+		if gi.UIDMap != "" {
+			attrs[pb.AttrLocalUIDMap] = gi.UIDMap
+		}
+		if gi.GIDMap != "" {
+			attrs[pb.AttrLocalGIDMap] = gi.GIDMap
+		}
+		addCap(&gi.Constraints, pb.CapSourceLocalPreserveOwnership)
+	}
+	if gi.SymlinkMode != "" {
+		attrs[pb.AttrLocalSymlinkMode] = string(gi.SymlinkMode)
+		addCap(&gi.Constraints, pb.CapSourceLocalSymlinkMode)
+	}
 
 	addCap(&gi.Constraints, pb.CapSourceLocal)
 
@@ -446,6 +514,42 @@ func Differ(t DiffType, required bool) LocalOption {
 	})
 }
 
+// PreserveOwnership keeps the uid and gid of the files on the client
+// instead of making them owned by root:root in the build.
+func PreserveOwnership() LocalOption {
+	return localOptionFunc(func(li *LocalInfo) {
+		li.PreserveOwnership = true
+	})
+}
+
+// OwnershipMap preserves the ownership of the files like PreserveOwnership
+// but translates the client uids and gids with the given mappings first.
+// Ids that are not in a mapping are kept as is. This is useful for rootless
+// clients where the files are owned by an unprivileged user.
+func OwnershipMap(uids, gids map[int]int) LocalOption {
+	return localOptionFunc(func(li *LocalInfo) {
+		li.PreserveOwnership = true
+		li.UIDMap = marshalIDMap(uids)
+		li.GIDMap = marshalIDMap(gids)
+	})
+}
+
+// SymlinkMode sets how the symlinks of the local directory are transferred.
+// By default they are transferred as symlinks.
+func SymlinkMode(m SymlinkModeType) LocalOption {
+	return localOptionFunc(func(li *LocalInfo) {
+		li.SymlinkMode = m
+	})
+}
+
+func marshalIDMap(m map[int]int) string {
+	if len(m) == 0 {
+		return ""
+	}
+	dt, _ := json.Marshal(m) // empty on error
+	return string(dt)
+}
+
 type DiffType string
 
 const (
@@ -458,6 +562,21 @@ const (
 	DiffMetadata DiffType = pb.AttrLocalDifferMetadata
 )
 
+type SymlinkModeType string
+
+const (
+	// SymlinkPreserve transfers symlinks as symlinks. This is the default
+	// behavior.
+	SymlinkPreserve SymlinkModeType = pb.AttrLocalSymlinkModePreserve
+	// SymlinkDereference transfers the files and directories that symlinks
+	// point to in place of the symlinks. Symlinks that point outside of the
+	// local directory fail the transfer.
+	SymlinkDereference SymlinkModeType = pb.AttrLocalSymlinkModeDereference
+	// SymlinkError fails the transfer if the local directory contains a
+	// symlink.
+	SymlinkError SymlinkModeType = pb.AttrLocalSymlinkModeError
+)
+
 type DifferInfo struct {
 	Type     DiffType
 	Required bool
@@ -471,6 +590,12 @@ type LocalInfo struct {
 	FollowPaths     string
 	SharedKeyHint   string
 	Differ          DifferInfo
+
+	PreserveOwnership bool
+	UIDMap            string
+	GIDMap            string
+
+	SymlinkMode SymlinkModeType
 }
 
 func HTTP(url string, opts ...HTTPOption) State {
@@ -498,6 +623,19 @@ func HTTP(url string, opts ...HTTPOption) State {
 		attrs[pb.AttrHTTPGID] = strconv.Itoa(hi.GID)
 		addCap(&hi.Constraints, pb.CapSourceHTTPUIDGID)
 	}
+	if len(hi.Mirrors) > 0 {
+		dt, _ := json.Marshal(hi.Mirrors) // empty on error
+		attrs[pb.AttrHTTPMirrors] = string(dt)
+		addCap(&hi.Constraints, pb.CapSourceHTTPMirrors)
+	}
+	for k, v := range hi.Headers {
+		attrs[pb.AttrHTTPHeaderPrefix+k] = v
+		addCap(&hi.Constraints, pb.CapSourceHTTPHeader)
+	}
+	for k, id := range hi.SecretHeaders {
+		attrs[pb.AttrHTTPSecretHeaderPrefix+k] = id
+		addCap(&hi.Constraints, pb.CapSourceHTTPSecretHeader)
+	}
 
 	addCap(&hi.Constraints, pb.CapSourceHTTP)
 	source := NewSource(url, attrs, hi.Constraints)
@@ -511,6 +649,11 @@ type HTTPInfo struct {
 	Perm     int
 	UID      int
 	GID      int
+	Mirrors  []string
+	Headers  map[string]string
+	// SecretHeaders maps the names of headers to the IDs of the secrets
+	// that are their values
+	SecretHeaders map[string]string
 }
 
 type HTTPOption interface {
@@ -529,6 +672,42 @@ func Checksum(dgst digest.Digest) HTTPOption {
 	})
 }
 
+// WithMirrors adds URLs that are tried in order when the content of the main
+// URL can't be fetched or doesn't match the checksum. Mirrors require a
+// checksum to be set with Checksum. The cache key only depends on the
+// checksum, not on the URL that served the content.
+func WithMirrors(urls ...string) HTTPOption {
+	return httpOptionFunc(func(hi *HTTPInfo) {
+		hi.Mirrors = append(hi.Mirrors, urls...)
+	})
+}
+
+// HTTPHeader sets a header of the request to URL. The header is not sent to
+// the mirrors or to the hosts that URL redirects to. The headers are not
+// part of the cache key of the downloaded file, that only depends on its
+// content, but downloads with different headers don't share the ETags they
+// revalidate with.
+func HTTPHeader(key, value string) HTTPOption {
+	return httpOptionFunc(func(hi *HTTPInfo) {
+		if hi.Headers == nil {
+			hi.Headers = map[string]string{}
+		}
+		hi.Headers[key] = value
+	})
+}
+
+// HTTPSecretHeader sets a header of the request to URL to the value of the
+// secret with the ID, like HTTPHeader. The value is loaded from the session
+// of the build and is not part of the definition.
+func HTTPSecretHeader(key, secretID string) HTTPOption {
+	return httpOptionFunc(func(hi *HTTPInfo) {
+		if hi.SecretHeaders == nil {
+			hi.SecretHeaders = map[string]string{}
+		}
+		hi.SecretHeaders[key] = secretID
+	})
+}
+
 func Chmod(perm os.FileMode) HTTPOption {
 	return httpOptionFunc(func(hi *HTTPInfo) {
 		hi.Perm = int(perm) & 0777
@@ -548,8 +727,54 @@ func Chown(uid, gid int) HTTPOption {
 	})
 }
 
+// OCILayout returns the filesystem of an image from a content store of the
+// client, e.g. an OCI layout directory or an image saved with docker save.
+// The reference must contain the digest of the manifest or index of the
+// image, e.g. "busybox@sha256:...". The store is set with OCIStore and is
+// attached to the session with client.SolveOpt.OCIStores. The config of the
+// image is not applied to the state.
+func OCILayout(ref string, opts ...OCILayoutOption) State {
+	var info OCILayoutInfo
+	for _, opt := range opts {
+		opt.SetOCILayoutOption(&info)
+	}
+
+	addCap(&info.Constraints, pb.CapSourceOCILayout)
+
+	attrs := map[string]string{}
+	if info.storeID != "" {
+		attrs[pb.AttrOCILayoutStoreID] = info.storeID
+	}
+
+	source := NewSource("oci-layout://"+ref, attrs, info.Constraints)
+	return NewState(source.Output())
+}
+
+type OCILayoutOption interface {
+	SetOCILayoutOption(*OCILayoutInfo)
+}
+
+type ociLayoutOptionFunc func(*OCILayoutInfo)
+
+func (fn ociLayoutOptionFunc) SetOCILayoutOption(li *OCILayoutInfo) {
+	fn(li)
+}
+
+// OCIStore sets the ID of the content store of the client that contains the
+// image
+func OCIStore(id string) OCILayoutOption {
+	return ociLayoutOptionFunc(func(li *OCILayoutInfo) {
+		li.storeID = id
+	})
+}
+
+type OCILayoutInfo struct {
+	constraintsWrapper
+	storeID string
+}
+
 func platformSpecificSource(id string) bool {
-	return strings.HasPrefix(id, "docker-image://")
+	return strings.HasPrefix(id, "docker-image://") || strings.HasPrefix(id, "oci-layout://")
 }
 
 func addCap(c *Constraints, id apicaps.CapID) {
@@ -0,0 +1,104 @@
+package llb
+
+import (
+	"context"
+
+	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
+)
+
+// squash is a vertex that builds the definition of a state as a nested build.
+// The solver caches the result of the nested build with a single cache key
+// derived from the whole definition and the cache keys of its sources, that
+// are the inputs of the vertex.
+type squash struct {
+	MarshalCache
+	state       State
+	sources     []Output
+	output      Output
+	constraints Constraints
+}
+
+func newSquash(s State, sources []Output, c Constraints) *squash {
+	sq := &squash{state: s, sources: sources, constraints: c}
+	sq.output = &output{vertex: sq, platform: c.Platform}
+	return sq
+}
+
+// squashSources returns the outputs of the vertexes of the subgraph of v that
+// have no inputs
+func squashSources(ctx context.Context, v Vertex, c *Constraints, seen map[Vertex]struct{}, sources []Output) []Output {
+	if v == nil {
+		return sources
+	}
+	if _, ok := seen[v]; ok {
+		return sources
+	}
+	seen[v] = struct{}{}
+	inputs := v.Inputs()
+	if len(inputs) == 0 {
+		return append(sources, v.Output())
+	}
+	for _, inp := range inputs {
+		sources = squashSources(ctx, inp.Vertex(ctx, c), c, seen, sources)
+	}
+	return sources
+}
+
+func (sq *squash) Validate(ctx context.Context, c *Constraints) error {
+	return nil
+}
+
+func (sq *squash) Marshal(ctx context.Context, c *Constraints) (digest.Digest, []byte, *pb.OpMetadata, []*SourceLocation, error) {
+	if sq.Cached(c) {
+		return sq.Load()
+	}
+
+	def, err := sq.state.Marshal(ctx, constraintsOptFunc(func(c2 *Constraints) {
+		*c2 = *c
+	}))
+	if err != nil {
+		return "", nil, nil, nil, err
+	}
+
+	addCap(&sq.constraints, pb.CapBuildOpDefinition)
+
+	pop, md := MarshalConstraints(c, &sq.constraints)
+	pop.Op = &pb.Op_Build{
+		Build: &pb.BuildOp{
+			Builder: pb.LLBBuilder,
+			Def:     def.ToPB(),
+		},
+	}
+	for _, o := range sq.sources {
+		inp, err := o.ToInput(ctx, c)
+		if err != nil {
+			return "", nil, nil, nil, err
+		}
+		newInput := true
+		for _, inp2 := range pop.Inputs {
+			if *inp == *inp2 {
+				newInput = false
+				break
+			}
+		}
+		if newInput {
+			pop.Inputs = append(pop.Inputs, inp)
+		}
+	}
+
+	dt, err := pop.Marshal()
+	if err != nil {
+		return "", nil, nil, nil, err
+	}
+	sq.Store(dt, md, sq.constraints.SourceLocations, c)
+	return sq.Load()
+}
+
+func (sq *squash) Output() Output {
+	return sq.output
+}
+
+func (sq *squash) Inputs() []Output {
+	return sq.sources
+}
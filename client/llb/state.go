@@ -161,14 +161,19 @@ func (s State) Marshal(ctx context.Context, co ...ConstraintsOpt) (*Definition,
 		if m.ExportCache != nil {
 			md.Caps[pb.CapMetaExportCache] = true
 		}
+		if m.ProgressGroup != nil {
+			md.Caps[pb.CapMetaProgressGroup] = true
+		}
 	}
 
 	def.Metadata[dgst] = md
-	sm, err := smc.Marshal(ctx, co...)
-	if err != nil {
-		return nil, err
+	if !c.NoSourceMaps {
+		sm, err := smc.Marshal(ctx, co...)
+		if err != nil {
+			return nil, err
+		}
+		def.Source = sm
 	}
-	def.Source = sm
 
 	return def, nil
 }
@@ -223,6 +228,27 @@ func (s State) WithOutput(o Output) State {
 	return s
 }
 
+// WithCustomName sets the name that is shown in the progress output for the
+// vertex that produces the state. The digest of the vertex is not changed.
+func (s State) WithCustomName(name string) State {
+	if s.Output() == nil {
+		return s
+	}
+	return s.WithOutput(&describedOutput{Output: s.Output(), desc: map[string]string{"llb.customname": name}})
+}
+
+// WithBuildLabel sets a label on the vertex that produces the state. Build
+// labels are recorded in the provenance of the build and returned in the
+// exporter response with the key "llb.buildlabel.<key>" and sent with the
+// vertex in the status stream. They are not added to the exported image
+// config and don't change the digest of the vertex.
+func (s State) WithBuildLabel(key, value string) State {
+	if s.Output() == nil {
+		return s
+	}
+	return s.WithOutput(&describedOutput{Output: s.Output(), desc: map[string]string{pb.BuildLabelPrefix + key: value}})
+}
+
 func (s State) WithImageConfig(c []byte) (State, error) {
 	var img struct {
 		Config struct {
@@ -259,6 +285,25 @@ func (s State) Run(ro ...RunOption) ExecState {
 	}
 	exec.secrets = ei.Secrets
 	exec.ssh = ei.SSH
+	exec.exitCodes = ei.ExitCodes
+	exec.seccomp = ei.Seccomp
+	exec.apparmor = ei.ApparmorProfile
+	exec.ignoreCache = ei.IgnoreForCache
+	exec.devices = ei.Devices
+	exec.cacheIgnore = ei.CacheIgnoreEnv
+	exec.umask = ei.Umask
+	exec.passthrough = ei.PassthroughEnv
+	exec.timezone = ei.Timezone
+	exec.stdoutPath = ei.RedirectStdout
+	exec.stderrPath = ei.RedirectStderr
+	exec.after = ei.After
+	exec.memoryLimit = ei.MemoryLimit
+	exec.cpuQuota = ei.CPUQuota
+	exec.cpuPeriod = ei.CPUPeriod
+	exec.sharedPID = ei.SharedPID
+	exec.retry = ei.Retry
+	exec.userNS = ei.UserNSMapping
+	exec.sysctls = ei.Sysctls
 
 	return ExecState{
 		State: s.WithOutput(exec.Output()),
@@ -275,6 +320,33 @@ func (s State) File(a *FileAction, opts ...ConstraintsOpt) State {
 	return s.WithOutput(NewFileOp(s, a, c).Output())
 }
 
+// Squash returns a state that builds the subgraph of s as a single unit. The
+// solver caches the result of the subgraph with one cache key computed from
+// its definition and the cache keys of its sources, without evaluating the
+// cache keys of the other vertexes. The contents of the local sources are
+// part of the cache key, so a new image for the same tag or a changed local
+// file invalidates the cached result. The result keeps the layers of the
+// subgraph.
+func (s State) Squash(opts ...ConstraintsOpt) State {
+	if s.Output() == nil {
+		return s
+	}
+	var c Constraints
+	for _, o := range opts {
+		o.SetConstraintsOption(&c)
+	}
+
+	sq := s.Async(func(ctx context.Context, _ State, c2 *Constraints) (State, error) {
+		sources := squashSources(ctx, s.Output().Vertex(ctx, c2), c2, map[Vertex]struct{}{}, nil)
+		return NewState(newSquash(s, sources, c).Output()), nil
+	})
+	st := s.WithOutput(sq.Output())
+	if c.Platform != nil {
+		st = platform(*c.Platform)(st)
+	}
+	return st
+}
+
 func (s State) AddEnv(key, value string) State {
 	return AddEnv(key, value)(s)
 }
@@ -339,6 +411,18 @@ func (s State) User(v string) State {
 	return User(v)(s)
 }
 
+func (s State) WithEntrypoint(path string) State {
+	return WithEntrypoint(path)(s)
+}
+
+func (s State) GetEntrypoint(ctx context.Context, co ...ConstraintsOpt) (string, error) {
+	c := &Constraints{}
+	for _, f := range co {
+		f.SetConstraintsOption(c)
+	}
+	return getEntrypoint(s)(ctx, c)
+}
+
 func (s State) Hostname(v string) State {
 	return Hostname(v)(s)
 }
@@ -440,6 +524,7 @@ type ConstraintsOpt interface {
 	HTTPOption
 	ImageOption
 	GitOption
+	OCILayoutOption
 }
 
 type constraintsOptFunc func(m *Constraints)
@@ -468,6 +553,10 @@ func (fn constraintsOptFunc) SetGitOption(gi *GitInfo) {
 	gi.applyConstraints(fn)
 }
 
+func (fn constraintsOptFunc) SetOCILayoutOption(li *OCILayoutInfo) {
+	li.applyConstraints(fn)
+}
+
 func mergeMetadata(m1, m2 pb.OpMetadata) pb.OpMetadata {
 	if m2.IgnoreCache {
 		m1.IgnoreCache = true
@@ -483,6 +572,9 @@ func mergeMetadata(m1, m2 pb.OpMetadata) pb.OpMetadata {
 	if m2.ExportCache != nil {
 		m1.ExportCache = m2.ExportCache
 	}
+	if m2.ProgressGroup != nil {
+		m1.ProgressGroup = m2.ProgressGroup
+	}
 
 	for k := range m2.Caps {
 		if m1.Caps == nil {
@@ -519,6 +611,14 @@ func WithCustomNamef(name string, a ...interface{}) ConstraintsOpt {
 	return WithCustomName(fmt.Sprintf(name, a...))
 }
 
+// WithProgressGroup adds the vertex to a group in the progress output. The
+// vertexes with the same id are shown together under the name of the group.
+func WithProgressGroup(id, name string) ConstraintsOpt {
+	return constraintsOptFunc(func(c *Constraints) {
+		c.Metadata.ProgressGroup = &pb.ProgressGroup{Id: id, Name: name}
+	})
+}
+
 // WithExportCache forces results for this vertex to be exported with the cache
 func WithExportCache() ConstraintsOpt {
 	return constraintsOptFunc(func(c *Constraints) {
@@ -535,6 +635,15 @@ func WithoutExportCache() ConstraintsOpt {
 	})
 }
 
+// WithoutSourceMaps omits the source locations from the marshaled
+// definition. The definition doesn't contain source files or local paths then
+// but errors can't be mapped back to the source.
+func WithoutSourceMaps() ConstraintsOpt {
+	return constraintsOptFunc(func(c *Constraints) {
+		c.NoSourceMaps = true
+	})
+}
+
 // WithoutDefaultExportCache resets the cache export for the vertex to use
 // the default defined by the build configuration.
 func WithoutDefaultExportCache() ConstraintsOpt {
@@ -566,6 +675,7 @@ type Constraints struct {
 	LocalUniqueID     string
 	Caps              *apicaps.CapSet
 	SourceLocations   []*SourceLocation
+	NoSourceMaps      bool
 }
 
 func Platform(p specs.Platform) ConstraintsOpt {
@@ -603,3 +713,43 @@ func Require(filters ...string) ConstraintsOpt {
 func nilValue(context.Context, *Constraints) (interface{}, error) {
 	return nil, nil
 }
+
+// describedOutput adds values to the description of the vertex of the output
+// when it is marshaled
+type describedOutput struct {
+	Output
+	desc map[string]string
+}
+
+func (o *describedOutput) Vertex(ctx context.Context, c *Constraints) Vertex {
+	v := o.Output.Vertex(ctx, c)
+	if v == nil {
+		return nil
+	}
+	return &describedVertex{Vertex: v, desc: o.desc}
+}
+
+type describedVertex struct {
+	Vertex
+	desc map[string]string
+}
+
+func (v *describedVertex) Marshal(ctx context.Context, c *Constraints) (digest.Digest, []byte, *pb.OpMetadata, []*SourceLocation, error) {
+	dgst, dt, md, sls, err := v.Vertex.Marshal(ctx, c)
+	if err != nil {
+		return "", nil, nil, nil, err
+	}
+	var md2 pb.OpMetadata
+	if md != nil {
+		md2 = *md
+	}
+	// the metadata may be shared with other marshal calls of the vertex
+	md2.Description = make(map[string]string, len(md2.Description)+len(v.desc))
+	for k, val := range md.GetDescription() {
+		md2.Description[k] = val
+	}
+	for k, val := range v.desc {
+		md2.Description[k] = val
+	}
+	return dgst, dt, &md2, sls, nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/moby/buildkit/solver/pb"
 	digest "github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -101,6 +102,24 @@ func TestStateSourceMapMarshal(t *testing.T) {
 	require.Equal(t, int32(9), def.Source.Locations[dgst.String()].Locations[2].Ranges[0].Start.Line)
 }
 
+func TestStateWithoutSourceMaps(t *testing.T) {
+	t.Parallel()
+
+	sm := NewSourceMap(nil, "/home/user/foo", []byte("data"))
+
+	s := Image("myimage", sm.Location([]*pb.Range{{Start: pb.Position{Line: 7}}}))
+
+	def, err := s.Marshal(context.TODO(), WithoutSourceMaps())
+	require.NoError(t, err)
+	require.Equal(t, 2, len(def.Def))
+	require.Nil(t, def.Source)
+
+	def2, err := s.Marshal(context.TODO())
+	require.NoError(t, err)
+	require.NotNil(t, def2.Source)
+	require.Equal(t, def.Def, def2.Def)
+}
+
 func TestPlatformFromImage(t *testing.T) {
 	t.Parallel()
 
@@ -161,6 +180,178 @@ func TestPlatformFromImage(t *testing.T) {
 	require.Equal(t, "s390x", vtx.Platform.Architecture)
 }
 
+func TestLocalPreserveOwnership(t *testing.T) {
+	t.Parallel()
+
+	def, err := Local("foo", OwnershipMap(map[int]int{1000: 0}, nil)).Marshal(context.TODO())
+	require.NoError(t, err)
+
+	_, arr := parseDef(t, def.Def)
+	src, ok := arr[0].Op.(*pb.Op_Source)
+	require.True(t, ok)
+	require.Equal(t, "local://foo", src.Source.Identifier)
+	require.Equal(t, "true", src.Source.Attrs[pb.AttrLocalPreserveOwnership])
+	require.Equal(t, `{"1000":0}`, src.Source.Attrs[pb.AttrLocalUIDMap])
+	_, ok = src.Source.Attrs[pb.AttrLocalGIDMap]
+	require.False(t, ok)
+	require.True(t, def.Metadata[digest.FromBytes(def.Def[0])].Caps[pb.CapSourceLocalPreserveOwnership])
+
+	def, err = Local("foo").Marshal(context.TODO())
+	require.NoError(t, err)
+
+	_, arr = parseDef(t, def.Def)
+	src, ok = arr[0].Op.(*pb.Op_Source)
+	require.True(t, ok)
+	_, ok = src.Source.Attrs[pb.AttrLocalPreserveOwnership]
+	require.False(t, ok)
+}
+
+func TestLocalSymlinkMode(t *testing.T) {
+	t.Parallel()
+
+	def, err := Local("foo", SymlinkMode(SymlinkDereference)).Marshal(context.TODO())
+	require.NoError(t, err)
+
+	_, arr := parseDef(t, def.Def)
+	src, ok := arr[0].Op.(*pb.Op_Source)
+	require.True(t, ok)
+	require.Equal(t, pb.AttrLocalSymlinkModeDereference, src.Source.Attrs[pb.AttrLocalSymlinkMode])
+	require.True(t, def.Metadata[digest.FromBytes(def.Def[0])].Caps[pb.CapSourceLocalSymlinkMode])
+
+	def, err = Local("foo").Marshal(context.TODO())
+	require.NoError(t, err)
+
+	_, arr = parseDef(t, def.Def)
+	src, ok = arr[0].Op.(*pb.Op_Source)
+	require.True(t, ok)
+	_, ok = src.Source.Attrs[pb.AttrLocalSymlinkMode]
+	require.False(t, ok)
+}
+
+func TestOCILayout(t *testing.T) {
+	t.Parallel()
+
+	ref := "busybox@sha256:6d3ac1bd2a39aeba0b2e3fe22e4ed5ec267e07a395c7bda7d3b5c7bde5baa5d1"
+	def, err := OCILayout(ref, OCIStore("images"), Platform(specs.Platform{OS: "linux", Architecture: "arm64"})).Marshal(context.TODO())
+	require.NoError(t, err)
+
+	_, arr := parseDef(t, def.Def)
+	src, ok := arr[0].Op.(*pb.Op_Source)
+	require.True(t, ok)
+	require.Equal(t, "oci-layout://"+ref, src.Source.Identifier)
+	require.Equal(t, "images", src.Source.Attrs[pb.AttrOCILayoutStoreID])
+	require.Equal(t, "arm64", arr[0].Platform.Architecture)
+	require.True(t, def.Metadata[digest.FromBytes(def.Def[0])].Caps[pb.CapSourceOCILayout])
+}
+
+func TestSquash(t *testing.T) {
+	t.Parallel()
+
+	st := Image("busybox").Run(Shlex("mkdir /foo")).Root().Squash().File(Mkdir("/bar", 0700))
+	def, err := st.Marshal(context.TODO(), Platform(specs.Platform{OS: "linux", Architecture: "arm64"}))
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	require.Equal(t, 4, len(arr))
+
+	dgst, idx := last(t, arr)
+	require.Equal(t, 0, idx)
+	f, ok := m[dgst].Op.(*pb.Op_File)
+	require.True(t, ok)
+	require.Equal(t, 1, len(m[dgst].Inputs))
+
+	sqDgst := m[dgst].Inputs[0].Digest
+	sq, ok := m[sqDgst].Op.(*pb.Op_Build)
+	require.True(t, ok)
+	require.Equal(t, pb.LLBBuilder, sq.Build.Builder)
+	require.True(t, def.Metadata[sqDgst].Caps[pb.CapBuildOpDefinition])
+	require.Equal(t, "/bar", f.File.Actions[0].Action.(*pb.FileAction_Mkdir).Mkdir.Path)
+
+	// the sources of the squashed subgraph are the inputs of the squash op
+	require.Equal(t, 1, len(m[sqDgst].Inputs))
+	src, ok := m[m[sqDgst].Inputs[0].Digest].Op.(*pb.Op_Source)
+	require.True(t, ok)
+	require.Equal(t, "docker-image://docker.io/library/busybox:latest", src.Source.Identifier)
+
+	// the other ops of the squashed subgraph are only in the nested definition
+	_, inner := parseDef(t, sq.Build.Def.Def)
+	require.Equal(t, 3, len(inner))
+	_, ok = inner[1].Op.(*pb.Op_Exec)
+	require.True(t, ok)
+	require.Equal(t, "arm64", inner[1].Platform.Architecture)
+	for _, op := range arr {
+		_, ok := op.Op.(*pb.Op_Exec)
+		require.False(t, ok)
+	}
+}
+
+func TestProgressGroup(t *testing.T) {
+	t.Parallel()
+
+	st := Image("busybox").
+		Run(Shlex("apk add git"), WithProgressGroup("deps", "install dependencies")).Root().
+		Run(Shlex("apk add make"), WithProgressGroup("deps", "install dependencies")).Root()
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	m, arr := parseDef(t, def.Def)
+	require.Equal(t, 4, len(arr))
+
+	require.True(t, def.Metadata[digest.FromBytes(def.Def[len(def.Def)-1])].Caps[pb.CapMetaProgressGroup])
+
+	dgst, _ := last(t, arr)
+
+	for _, inp := range []digest.Digest{dgst, m[dgst].Inputs[0].Digest} {
+		require.Equal(t, &pb.ProgressGroup{Id: "deps", Name: "install dependencies"}, def.Metadata[inp].ProgressGroup)
+	}
+}
+
+func TestStateWithCustomName(t *testing.T) {
+	t.Parallel()
+
+	base := Image("busybox").Run(Shlex("make")).Root()
+	st := base.WithCustomName("build").File(Mkdir("/out", 0700))
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	def2, err := base.File(Mkdir("/out", 0700)).Marshal(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, def2.Def, def.Def)
+
+	m, arr := parseDef(t, def.Def)
+	dgst, _ := last(t, arr)
+	execDgst := m[dgst].Inputs[0].Digest
+	require.Equal(t, "build", def.Metadata[execDgst].Description["llb.customname"])
+
+	_, ok := def2.Metadata[execDgst].Description["llb.customname"]
+	require.False(t, ok)
+}
+
+func TestStateWithBuildLabel(t *testing.T) {
+	t.Parallel()
+
+	base := Image("busybox").Run(Shlex("make")).Root()
+	st := base.WithCustomName("build").WithBuildLabel("team", "core").WithBuildLabel("ticket", "123").File(Mkdir("/out", 0700))
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	def2, err := base.File(Mkdir("/out", 0700)).Marshal(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, def2.Def, def.Def)
+
+	m, arr := parseDef(t, def.Def)
+	dgst, _ := last(t, arr)
+	execDgst := m[dgst].Inputs[0].Digest
+	require.Equal(t, map[string]string{
+		"llb.customname":        "build",
+		"llb.buildlabel.team":   "core",
+		"llb.buildlabel.ticket": "123",
+	}, def.Metadata[execDgst].Description)
+
+	_, ok := def.Metadata[dgst].Description["llb.buildlabel.team"]
+	require.False(t, ok)
+}
+
 func getEnvHelper(t *testing.T, s State, k string) (string, bool) {
 	t.Helper()
 	v, ok, err := s.GetEnv(context.TODO(), k)
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"io"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/pkg/errors"
+)
+
+// MountCache mounts the cache mount with the given id read-only in a directory
+// under the root of the daemon. The mount is kept until ctx is canceled. If
+// ready is set it is called with the ID of the mounted cache record and the
+// path of the mount on the daemon host once the mount is in place.
+func (c *Client) MountCache(ctx context.Context, id string, ready func(ref, path string)) error {
+	cl, err := c.controlClient().MountCache(ctx, &controlapi.MountCacheRequest{
+		ID: id,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to call mount cache")
+	}
+
+	for {
+		resp, err := cl.Recv()
+		if err != nil {
+			if err == io.EOF || ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if ready != nil {
+			ready(resp.Ref, resp.Path)
+		}
+	}
+}
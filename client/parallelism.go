@@ -0,0 +1,22 @@
+package client
+
+import (
+	"context"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/pkg/errors"
+)
+
+// SetExecParallelism changes the number of exec operations the daemon runs at
+// the same time across all builds and returns the previous limit. Zero removes
+// the limit. The change is not persisted, max-exec-parallelism of the daemon
+// config applies again after a restart.
+func (c *Client) SetExecParallelism(ctx context.Context, limit int) (int, error) {
+	resp, err := c.controlClient().SetExecParallelism(ctx, &controlapi.SetExecParallelismRequest{
+		Limit: int64(limit),
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to set exec parallelism")
+	}
+	return int(resp.Previous), nil
+}
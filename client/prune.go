@@ -19,6 +19,7 @@ func (c *Client) Prune(ctx context.Context, ch chan UsageInfo, opts ...PruneOpti
 		Filter:       info.Filter,
 		KeepDuration: int64(info.KeepDuration),
 		KeepBytes:    int64(info.KeepBytes),
+		Worker:       info.Worker,
 	}
 	if info.All {
 		req.All = true
@@ -38,17 +39,18 @@ func (c *Client) Prune(ctx context.Context, ch chan UsageInfo, opts ...PruneOpti
 		}
 		if ch != nil {
 			ch <- UsageInfo{
-				ID:          d.ID,
-				Mutable:     d.Mutable,
-				InUse:       d.InUse,
-				Size:        d.Size_,
-				Parent:      d.Parent,
-				CreatedAt:   d.CreatedAt,
-				Description: d.Description,
-				UsageCount:  int(d.UsageCount),
-				LastUsedAt:  d.LastUsedAt,
-				RecordType:  UsageRecordType(d.RecordType),
-				Shared:      d.Shared,
+				ID:           d.ID,
+				Mutable:      d.Mutable,
+				InUse:        d.InUse,
+				Size:         d.Size_,
+				Parent:       d.Parent,
+				CreatedAt:    d.CreatedAt,
+				Description:  d.Description,
+				UsageCount:   int(d.UsageCount),
+				LastUsedAt:   d.LastUsedAt,
+				RecordType:   UsageRecordType(d.RecordType),
+				Shared:       d.Shared,
+				CacheMountID: d.CacheMountID,
 			}
 		}
 	}
@@ -63,6 +65,7 @@ type PruneInfo struct {
 	All          bool
 	KeepDuration time.Duration
 	KeepBytes    int64
+	Worker       string
 }
 
 type pruneOptionFunc func(*PruneInfo)
@@ -81,3 +84,10 @@ func WithKeepOpt(duration time.Duration, bytes int64) PruneOption {
 		pi.KeepBytes = bytes
 	})
 }
+
+// WithPruneWorker limits the prune to the cache of the worker with the given ID.
+func WithPruneWorker(id string) PruneOption {
+	return pruneOptionFunc(func(pi *PruneInfo) {
+		pi.Worker = id
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	controlapi "github.com/moby/buildkit/api/services/control"
 	"github.com/moby/buildkit/client/llb"
 	"github.com/moby/buildkit/client/ociindex"
+	gateway "github.com/moby/buildkit/frontend/gateway/client"
 	"github.com/moby/buildkit/identity"
 	"github.com/moby/buildkit/session"
 	sessioncontent "github.com/moby/buildkit/session/content"
@@ -21,6 +23,7 @@ import (
 	"github.com/moby/buildkit/session/grpchijack"
 	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/buildkit/util/entitlements"
+	digest "github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -32,23 +35,36 @@ import (
 type SolveOpt struct {
 	Exports               []ExportEntry
 	LocalDirs             map[string]string
+	LocalRetry            *filesync.RetryPolicy // retries transfers of LocalDirs that fail because of transient connection errors
 	SharedKey             string
 	Frontend              string
 	FrontendAttrs         map[string]string
 	FrontendInputs        map[string]llb.State
 	CacheExports          []CacheOptionsEntry
 	CacheImports          []CacheOptionsEntry
+	OCIStores             map[string]content.Store
 	Session               []session.Attachable
 	AllowedEntitlements   []entitlements.Entitlement
+	CacheNamespace        string           // isolates the build cache from builds that don't use the same namespace
+	CheckpointID          string           // builds with the same checkpoint ID reuse each other's completed results
+	Deadline              time.Time        // the daemon cancels the build if it hasn't completed by this time
+	HashConcurrency       int              // number of files hashed in parallel for the checksums of the build contexts, capped by the daemon
+	CacheMountStats       bool             // sends the stats of the cache mounts of the execs in the status stream
 	SharedSession         *session.Session // TODO: refactor to better session syncing
 	SessionPreInitialized bool             // TODO: refactor to better session syncing
 }
 
 type ExportEntry struct {
-	Type      string
-	Attrs     map[string]string
-	Output    func(map[string]string) (io.WriteCloser, error) // for ExporterOCI and ExporterDocker
-	OutputDir string                                          // for ExporterLocal
+	Type        string
+	Attrs       map[string]string
+	Output      func(map[string]string) (io.WriteCloser, error) // for ExporterOCI, ExporterDocker, ExporterTar, ExporterMerkle, ExporterProvenance and ExporterFSImage
+	OutputDir   string                                          // for ExporterLocal
+	OutputStore content.Store                                   // for ExporterOCI and ExporterDocker, receives the image blobs instead of a tarball
+	// OutputPipe streams the tarball of the exporters that support Output to
+	// the reader of the pipe instead of calling Output. The export blocks
+	// until the data is read. The pipe is closed when Solve returns, with the
+	// error of the build if it failed.
+	OutputPipe *io.PipeWriter
 }
 
 type CacheOptionsEntry struct {
@@ -77,12 +93,12 @@ func (c *Client) Solve(ctx context.Context, def *llb.Definition, opt SolveOpt, s
 
 type runGatewayCB func(ref string, s *session.Session) error
 
-func (c *Client) solve(ctx context.Context, def *llb.Definition, runGateway runGatewayCB, opt SolveOpt, statusChan chan *SolveStatus) (*SolveResponse, error) {
+func (c *Client) solve(ctx context.Context, def *llb.Definition, runGateway runGatewayCB, opt SolveOpt, statusChan chan *SolveStatus) (_ *SolveResponse, retErr error) {
 	if def != nil && runGateway != nil {
 		return nil, errors.New("invalid with def and cb")
 	}
 
-	syncedDirs, err := prepareSyncedDirs(def, opt.LocalDirs)
+	syncedDirs, err := prepareSyncedDirs(def, opt.LocalDirs, opt.LocalRetry)
 	if err != nil {
 		return nil, err
 	}
@@ -114,12 +130,34 @@ func (c *Client) solve(ctx context.Context, def *llb.Definition, runGateway runG
 		return nil, err
 	}
 
-	var ex ExportEntry
-	if len(opt.Exports) > 1 {
-		return nil, errors.New("currently only single Exports can be specified")
-	}
-	if len(opt.Exports) == 1 {
-		ex = opt.Exports[0]
+	exports := make([]ExportEntry, len(opt.Exports))
+	var outputStore content.Store
+	for i, ex := range opt.Exports {
+		if ex.OutputPipe != nil {
+			if ex.Output != nil {
+				return nil, errors.New("output file writer and output pipe can't be used together")
+			}
+			pw := ex.OutputPipe
+			defer func() {
+				pw.CloseWithError(retErr)
+			}()
+			ex.Output = func(map[string]string) (io.WriteCloser, error) {
+				return &pipeWriter{pw}, nil
+			}
+		}
+		if ex.OutputStore != nil {
+			if outputStore != nil {
+				return nil, errors.New("only one export can use an output store")
+			}
+			outputStore = ex.OutputStore
+			attrs := map[string]string{}
+			for k, v := range ex.Attrs {
+				attrs[k] = v
+			}
+			attrs["tar"] = "false"
+			ex.Attrs = attrs
+		}
+		exports[i] = ex
 	}
 
 	if !opt.SessionPreInitialized {
@@ -131,34 +169,42 @@ func (c *Client) solve(ctx context.Context, def *llb.Definition, runGateway runG
 			s.Allow(a)
 		}
 
-		switch ex.Type {
-		case ExporterLocal:
-			if ex.Output != nil {
-				return nil, errors.New("output file writer is not supported by local exporter")
-			}
-			if ex.OutputDir == "" {
-				return nil, errors.New("output directory is required for local exporter")
-			}
-			s.Allow(filesync.NewFSSyncTargetDir(ex.OutputDir))
-		case ExporterOCI, ExporterDocker, ExporterTar:
-			if ex.OutputDir != "" {
-				return nil, errors.Errorf("output directory %s is not supported by %s exporter", ex.OutputDir, ex.Type)
-			}
-			if ex.Output == nil {
-				return nil, errors.Errorf("output file writer is required for %s exporter", ex.Type)
+		targets := map[string]filesync.FSSyncTarget{}
+		for i, ex := range exports {
+			t, err := exportTarget(ex)
+			if err != nil {
+				return nil, err
 			}
-			s.Allow(filesync.NewFSSyncTarget(ex.Output))
-		default:
-			if ex.Output != nil {
-				return nil, errors.Errorf("output file writer is not supported by %s exporter", ex.Type)
+			if t != nil {
+				targets[strconv.Itoa(i)] = *t
 			}
-			if ex.OutputDir != "" {
-				return nil, errors.Errorf("output directory %s is not supported by %s exporter", ex.OutputDir, ex.Type)
+		}
+		if len(exports) == 1 {
+			if t, ok := targets["0"]; ok {
+				if t.OutDir != "" {
+					s.Allow(filesync.NewFSSyncTargetDir(t.OutDir))
+				} else {
+					s.Allow(filesync.NewFSSyncTarget(t.Output))
+				}
 			}
+		} else if len(targets) > 0 {
+			// the exporters of builds with more than one exporter are run
+			// with their index as ID
+			s.Allow(filesync.NewFSSyncTargets(targets))
 		}
 
-		if len(cacheOpt.contentStores) > 0 {
-			s.Allow(sessioncontent.NewAttachable(cacheOpt.contentStores))
+		contentStores := map[string]content.Store{}
+		for k, v := range cacheOpt.contentStores {
+			contentStores[k] = v
+		}
+		for k, v := range opt.OCIStores {
+			contentStores["oci:"+k] = v
+		}
+		if outputStore != nil {
+			contentStores["export"] = outputStore
+		}
+		if len(contentStores) > 0 {
+			s.Allow(sessioncontent.NewAttachable(contentStores))
 		}
 
 		eg.Go(func() error {
@@ -189,6 +235,11 @@ func (c *Client) solve(ctx context.Context, def *llb.Definition, runGateway runG
 			pbd = def.ToPB()
 		}
 
+		var deadline *time.Time
+		if !opt.Deadline.IsZero() {
+			deadline = &opt.Deadline
+		}
+
 		frontendInputs := make(map[string]*pb.Definition)
 		for key, st := range opt.FrontendInputs {
 			def, err := st.Marshal(ctx)
@@ -198,24 +249,54 @@ func (c *Client) solve(ctx context.Context, def *llb.Definition, runGateway runG
 			frontendInputs[key] = def.ToPB()
 		}
 
-		resp, err := c.controlClient().Solve(ctx, &controlapi.SolveRequest{
-			Ref:            ref,
-			Definition:     pbd,
-			Exporter:       ex.Type,
-			ExporterAttrs:  ex.Attrs,
-			Session:        s.ID(),
-			Frontend:       opt.Frontend,
-			FrontendAttrs:  opt.FrontendAttrs,
-			FrontendInputs: frontendInputs,
-			Cache:          cacheOpt.options,
-			Entitlements:   opt.AllowedEntitlements,
-		})
+		req := &controlapi.SolveRequest{
+			Ref:             ref,
+			Definition:      pbd,
+			Session:         s.ID(),
+			Frontend:        opt.Frontend,
+			FrontendAttrs:   opt.FrontendAttrs,
+			FrontendInputs:  frontendInputs,
+			Cache:           cacheOpt.options,
+			Entitlements:    opt.AllowedEntitlements,
+			CacheNamespace:  opt.CacheNamespace,
+			CheckpointID:    opt.CheckpointID,
+			Deadline:        deadline,
+			HashConcurrency: int32(opt.HashConcurrency),
+			CacheMountStats: opt.CacheMountStats,
+		}
+		if len(exports) == 1 {
+			req.Exporter = exports[0].Type
+			req.ExporterAttrs = exports[0].Attrs
+		} else {
+			for _, ex := range exports {
+				req.Exporters = append(req.Exporters, &controlapi.Exporter{Type: ex.Type, Attrs: ex.Attrs})
+			}
+		}
+		resp, err := c.controlClient().Solve(ctx, req)
 		if err != nil {
 			return errors.Wrap(err, "failed to solve")
 		}
 		res = &SolveResponse{
 			ExporterResponse: resp.ExporterResponse,
 		}
+		if len(resp.Sources) > 0 {
+			res.Sources = make(map[string]digest.Digest, len(resp.Sources))
+			for k, v := range resp.Sources {
+				res.Sources[k] = digest.Digest(v)
+			}
+		}
+		for _, w := range resp.Warnings {
+			res.Warnings = append(res.Warnings, Warning{
+				Code:       w.Code,
+				Level:      gateway.WarningLevel(w.Level),
+				Message:    w.Message,
+				Detail:     w.Detail,
+				URL:        w.URL,
+				Vertex:     w.Vertex,
+				SourceInfo: w.Info,
+				Ranges:     w.Ranges,
+			})
+		}
 		return nil
 	})
 
@@ -256,40 +337,9 @@ func (c *Client) solve(ctx context.Context, def *llb.Definition, runGateway runG
 				}
 				return errors.Wrap(err, "failed to receive status")
 			}
-			s := SolveStatus{}
-			for _, v := range resp.Vertexes {
-				s.Vertexes = append(s.Vertexes, &Vertex{
-					Digest:    v.Digest,
-					Inputs:    v.Inputs,
-					Name:      v.Name,
-					Started:   v.Started,
-					Completed: v.Completed,
-					Error:     v.Error,
-					Cached:    v.Cached,
-				})
-			}
-			for _, v := range resp.Statuses {
-				s.Statuses = append(s.Statuses, &VertexStatus{
-					ID:        v.ID,
-					Vertex:    v.Vertex,
-					Name:      v.Name,
-					Total:     v.Total,
-					Current:   v.Current,
-					Timestamp: v.Timestamp,
-					Started:   v.Started,
-					Completed: v.Completed,
-				})
-			}
-			for _, v := range resp.Logs {
-				s.Logs = append(s.Logs, &VertexLog{
-					Vertex:    v.Vertex,
-					Stream:    int(v.Stream),
-					Data:      v.Msg,
-					Timestamp: v.Timestamp,
-				})
-			}
+			s := statusFromResponse(resp)
 			if statusChan != nil {
-				statusChan <- &s
+				statusChan <- s
 			}
 		}
 	})
@@ -313,7 +363,52 @@ func (c *Client) solve(ctx context.Context, def *llb.Definition, runGateway runG
 	return res, nil
 }
 
-func prepareSyncedDirs(def *llb.Definition, localDirs map[string]string) ([]filesync.SyncedDir, error) {
+// exportTarget validates the outputs of the export and returns the output of
+// the client that the exporter writes to, if it has one
+func exportTarget(ex ExportEntry) (*filesync.FSSyncTarget, error) {
+	switch ex.Type {
+	case ExporterLocal:
+		if ex.Output != nil {
+			return nil, errors.New("output file writer is not supported by local exporter")
+		}
+		if ex.OutputDir == "" {
+			return nil, errors.New("output directory is required for local exporter")
+		}
+		return &filesync.FSSyncTarget{OutDir: ex.OutputDir}, nil
+	case ExporterOCI, ExporterDocker:
+		if ex.OutputDir != "" {
+			return nil, errors.Errorf("output directory %s is not supported by %s exporter", ex.OutputDir, ex.Type)
+		}
+		if ex.OutputStore != nil {
+			if ex.Output != nil {
+				return nil, errors.Errorf("output file writer and output store can't be used together by %s exporter", ex.Type)
+			}
+			return nil, nil
+		}
+		if ex.Output == nil {
+			return nil, errors.Errorf("output file writer is required for %s exporter", ex.Type)
+		}
+		return &filesync.FSSyncTarget{Output: ex.Output}, nil
+	case ExporterTar, ExporterMerkle, ExporterProvenance, ExporterFSImage:
+		if ex.OutputDir != "" {
+			return nil, errors.Errorf("output directory %s is not supported by %s exporter", ex.OutputDir, ex.Type)
+		}
+		if ex.Output == nil {
+			return nil, errors.Errorf("output file writer is required for %s exporter", ex.Type)
+		}
+		return &filesync.FSSyncTarget{Output: ex.Output}, nil
+	default:
+		if ex.Output != nil {
+			return nil, errors.Errorf("output file writer is not supported by %s exporter", ex.Type)
+		}
+		if ex.OutputDir != "" {
+			return nil, errors.Errorf("output directory %s is not supported by %s exporter", ex.OutputDir, ex.Type)
+		}
+		return nil, nil
+	}
+}
+
+func prepareSyncedDirs(def *llb.Definition, localDirs map[string]string, retry *filesync.RetryPolicy) ([]filesync.SyncedDir, error) {
 	for _, d := range localDirs {
 		fi, err := os.Stat(d)
 		if err != nil {
@@ -332,24 +427,36 @@ func prepareSyncedDirs(def *llb.Definition, localDirs map[string]string) ([]file
 	dirs := make([]filesync.SyncedDir, 0, len(localDirs))
 	if def == nil {
 		for name, d := range localDirs {
-			dirs = append(dirs, filesync.SyncedDir{Name: name, Dir: d, Map: resetUIDAndGID})
+			dirs = append(dirs, filesync.SyncedDir{Name: name, Dir: d, Map: resetUIDAndGID, Retry: retry})
 		}
 	} else {
-		for _, dt := range def.Def {
-			var op pb.Op
-			if err := (&op).Unmarshal(dt); err != nil {
-				return nil, errors.Wrap(err, "failed to parse llb proto op")
-			}
-			if src := op.GetSource(); src != nil {
-				if strings.HasPrefix(src.Identifier, "local://") { // TODO: just make a type property
-					name := strings.TrimPrefix(src.Identifier, "local://")
-					d, ok := localDirs[name]
-					if !ok {
-						return nil, errors.Errorf("local directory %s not enabled", name)
+		var walk func(defs [][]byte) error
+		walk = func(defs [][]byte) error {
+			for _, dt := range defs {
+				var op pb.Op
+				if err := (&op).Unmarshal(dt); err != nil {
+					return errors.Wrap(err, "failed to parse llb proto op")
+				}
+				if b := op.GetBuild(); b != nil && b.Def != nil {
+					if err := walk(b.Def.Def); err != nil {
+						return err
+					}
+				}
+				if src := op.GetSource(); src != nil {
+					if strings.HasPrefix(src.Identifier, "local://") { // TODO: just make a type property
+						name := strings.TrimPrefix(src.Identifier, "local://")
+						d, ok := localDirs[name]
+						if !ok {
+							return errors.Errorf("local directory %s not enabled", name)
+						}
+						dirs = append(dirs, filesync.SyncedDir{Name: name, Dir: d, Map: resetUIDAndGID, Retry: retry}) // TODO: excludes
 					}
-					dirs = append(dirs, filesync.SyncedDir{Name: name, Dir: d, Map: resetUIDAndGID}) // TODO: excludes
 				}
 			}
+			return nil
+		}
+		if err := walk(def.Def); err != nil {
+			return nil, err
 		}
 	}
 	return dirs, nil
@@ -486,3 +593,60 @@ func parseCacheOptions(opt SolveOpt) (*cacheOptions, error) {
 	}
 	return &res, nil
 }
+
+func statusFromResponse(resp *controlapi.StatusResponse) *SolveStatus {
+	s := SolveStatus{}
+	for _, v := range resp.Vertexes {
+		s.Vertexes = append(s.Vertexes, &Vertex{
+			Digest:        v.Digest,
+			Inputs:        v.Inputs,
+			Name:          v.Name,
+			Started:       v.Started,
+			Completed:     v.Completed,
+			Error:         v.Error,
+			Cached:        v.Cached,
+			ProgressGroup: v.ProgressGroup,
+			ExitCode:      int(v.ExitCode),
+			Labels:        v.Labels,
+		})
+	}
+	for _, v := range resp.Statuses {
+		s.Statuses = append(s.Statuses, &VertexStatus{
+			ID:        v.ID,
+			Vertex:    v.Vertex,
+			Name:      v.Name,
+			Total:     v.Total,
+			Current:   v.Current,
+			Timestamp: v.Timestamp,
+			Started:   v.Started,
+			Completed: v.Completed,
+		})
+	}
+	for _, v := range resp.Logs {
+		s.Logs = append(s.Logs, &VertexLog{
+			Vertex:    v.Vertex,
+			Stream:    int(v.Stream),
+			Data:      v.Msg,
+			Timestamp: v.Timestamp,
+		})
+	}
+	for _, v := range resp.CacheMounts {
+		s.CacheMounts = append(s.CacheMounts, &CacheMountStats{
+			Vertex:         v.Vertex,
+			ID:             v.ID,
+			Target:         v.Target,
+			UnchangedBytes: v.UnchangedBytes,
+			WrittenBytes:   v.WrittenBytes,
+			Timestamp:      v.Timestamp,
+		})
+	}
+	return &s
+}
+
+// pipeWriter leaves the closing of the pipe to Solve so that the reader gets
+// the error of a failed export instead of a truncated tarball
+type pipeWriter struct {
+	*io.PipeWriter
+}
+
+func (pipeWriter) Close() error { return nil }
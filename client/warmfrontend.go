@@ -0,0 +1,29 @@
+package client
+
+import (
+	"context"
+
+	"github.com/moby/buildkit/session"
+	"github.com/pkg/errors"
+)
+
+// WarmFrontend makes the daemon resolve and extract the gateway frontend
+// image ref ahead of the builds that use it. Later builds with the same
+// frontend source reuse the warmed image without pulling it again as long as
+// the source still resolves to the same digest and the build has the same
+// registry credentials. The attachables are used for pulling the image, e.g.
+// an auth provider for private registries.
+func (c *Client) WarmFrontend(ctx context.Context, ref string, attachables ...session.Attachable) error {
+	if ref == "" {
+		return errors.New("frontend image is required")
+	}
+	_, err := c.Solve(ctx, nil, SolveOpt{
+		Frontend: "gateway.v0",
+		FrontendAttrs: map[string]string{
+			"source":        ref,
+			"frontend.warm": "",
+		},
+		Session: attachables,
+	}, nil)
+	return errors.Wrapf(err, "failed to warm frontend %s", ref)
+}
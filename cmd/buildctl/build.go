@@ -1,10 +1,12 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"io"
 	"os"
+	"time"
 
 	"github.com/containerd/continuity"
 	"github.com/moby/buildkit/client"
@@ -13,6 +15,7 @@ import (
 	bccommon "github.com/moby/buildkit/cmd/buildctl/common"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/session/auth/authprovider"
+	"github.com/moby/buildkit/session/cabundle/cabundleprovider"
 	"github.com/moby/buildkit/session/sshforward/sshprovider"
 	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/buildkit/util/progress/progresswriter"
@@ -49,13 +52,17 @@ var buildCommand = cli.Command{
 		},
 		cli.StringFlag{
 			Name:  "progress",
-			Usage: "Set type of progress (auto, plain, tty). Use plain to show container output",
+			Usage: "Set type of progress (auto, plain, tty). Use plain to show container output. Append ,hide-cached to hide the steps loaded from cache",
 			Value: "auto",
 		},
 		cli.StringFlag{
 			Name:  "trace",
 			Usage: "Path to trace file. Defaults to no tracing.",
 		},
+		cli.StringFlag{
+			Name:  "dump-graph",
+			Usage: "Write the graph of the solved vertices to a file in Graphviz DOT format",
+		},
 		cli.StringSliceFlag{
 			Name:  "local",
 			Usage: "Allow build access to the local directory",
@@ -64,6 +71,10 @@ var buildCommand = cli.Command{
 			Name:  "frontend",
 			Usage: "Define frontend used for build",
 		},
+		cli.StringFlag{
+			Name:  "definition",
+			Usage: "Read the LLB definition from a file instead of stdin, \"-\" reads stdin",
+		},
 		cli.StringSliceFlag{
 			Name:  "opt",
 			Usage: "Define custom options for frontend, e.g. --opt target=foo --opt build-arg:foo=bar",
@@ -102,13 +113,62 @@ var buildCommand = cli.Command{
 			Name:  "ssh",
 			Usage: "Allow forwarding SSH agent to the builder. Format default|<id>[=<socket>|<key>[,<key>]]",
 		},
+		cli.StringSliceFlag{
+			Name:  "registry-auth-config",
+			Usage: "Use the registry credentials of a docker config directory for pulling or pushing only. Format pull|push=<dir>",
+		},
+		cli.StringSliceFlag{
+			Name:  "registry-token-file",
+			Usage: "Use the registry token of a file that is read again when it changes. Format host=<host>,src=<path>[,username=<username>]",
+		},
+		cli.StringSliceFlag{
+			Name:  "proxy",
+			Usage: "Proxy for pulling images and fetching HTTP and Git sources of the build instead of the proxy of the daemon. Format env|http_proxy=<url>|https_proxy=<url>|no_proxy=<hosts>",
+		},
+		cli.StringFlag{
+			Name:  "image-policy",
+			Usage: "Require the images pulled by the build to be signed according to the imagePolicy rules of a TOML file, in addition to the policy of the daemon",
+		},
+		cli.StringSliceFlag{
+			Name:  "ca-bundle",
+			Usage: "Trust the CA certificates of a PEM file for pulling images and fetching HTTP and Git sources of the build",
+		},
+		cli.StringSliceFlag{
+			Name:  "oci-layout",
+			Usage: "Allow build access to the images of an OCI layout directory or a saved image tarball. Format <id>=<path>",
+		},
 		cli.StringFlag{
 			Name:  "metadata-file",
 			Usage: "Output build metadata (e.g., image digest) to a file as JSON",
 		},
+		cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "Cancel the build if it doesn't complete within this duration (e.g., 30m)",
+		},
 	},
 }
 
+// readDefinition reads the LLB definition from the file of the --definition
+// flag. Stdin is only read if the flag is not set or is "-".
+func readDefinition(clicontext *cli.Context) (*llb.Definition, error) {
+	fn := clicontext.String("definition")
+	if fn == "-" {
+		return read(os.Stdin, clicontext)
+	}
+	if fn == "" {
+		if fi, _ := os.Stdin.Stat(); fi != nil && (fi.Mode()&os.ModeCharDevice) != 0 {
+			return nil, errors.Errorf("please specify --frontend or --definition, or pipe LLB definition to stdin")
+		}
+		return read(os.Stdin, clicontext)
+	}
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open definition")
+	}
+	defer f.Close()
+	return read(f, clicontext)
+}
+
 func read(r io.Reader, clicontext *cli.Context) (*llb.Definition, error) {
 	def, err := llb.ReadFrom(r)
 	if err != nil {
@@ -158,7 +218,21 @@ func buildAction(clicontext *cli.Context) error {
 		logrus.Infof("tracing logs to %s", traceFile.Name())
 	}
 
-	attachable := []session.Attachable{authprovider.NewDockerAuthProvider(os.Stderr)}
+	ap, err := build.ParseRegistryAuthConfig(os.Stderr, clicontext.StringSlice("registry-auth-config"))
+	if err != nil {
+		return err
+	}
+	if v := clicontext.StringSlice("registry-token-file"); len(v) > 0 {
+		files, err := build.ParseRegistryTokenFiles(v)
+		if err != nil {
+			return err
+		}
+		ap, err = authprovider.WithTokenFiles(ap, files)
+		if err != nil {
+			return err
+		}
+	}
+	attachable := []session.Attachable{ap}
 
 	if ssh := clicontext.StringSlice("ssh"); len(ssh) > 0 {
 		configs, err := build.ParseSSH(ssh)
@@ -180,6 +254,30 @@ func buildAction(clicontext *cli.Context) error {
 		attachable = append(attachable, secretProvider)
 	}
 
+	if v := clicontext.StringSlice("proxy"); len(v) > 0 {
+		pp, err := build.ParseProxy(v)
+		if err != nil {
+			return err
+		}
+		attachable = append(attachable, pp)
+	}
+
+	if v := clicontext.String("image-policy"); v != "" {
+		ip, err := build.ParseImagePolicy(v)
+		if err != nil {
+			return err
+		}
+		attachable = append(attachable, ip)
+	}
+
+	if v := clicontext.StringSlice("ca-bundle"); len(v) > 0 {
+		cp, err := cabundleprovider.FromFiles(v)
+		if err != nil {
+			return err
+		}
+		attachable = append(attachable, cp)
+	}
+
 	allowed, err := build.ParseAllow(clicontext.StringSlice("allow"))
 	if err != nil {
 		return err
@@ -220,6 +318,9 @@ func buildAction(clicontext *cli.Context) error {
 		Session:             attachable,
 		AllowedEntitlements: allowed,
 	}
+	if timeout := clicontext.Duration("timeout"); timeout > 0 {
+		solveOpt.Deadline = time.Now().Add(timeout)
+	}
 
 	solveOpt.FrontendAttrs, err = build.ParseOpt(clicontext.StringSlice("opt"), clicontext.StringSlice("frontend-opt"))
 	if err != nil {
@@ -231,12 +332,16 @@ func buildAction(clicontext *cli.Context) error {
 		return errors.Wrap(err, "invalid local")
 	}
 
+	ociStores, cleanupOCIStores, err := build.ParseOCILayout(ctx, os.Stderr, clicontext.StringSlice("oci-layout"))
+	if err != nil {
+		return errors.Wrap(err, "invalid oci-layout")
+	}
+	defer cleanupOCIStores()
+	solveOpt.OCIStores = ociStores
+
 	var def *llb.Definition
 	if clicontext.String("frontend") == "" {
-		if fi, _ := os.Stdin.Stat(); (fi.Mode() & os.ModeCharDevice) != 0 {
-			return errors.Errorf("please specify --frontend or pipe LLB definition to stdin")
-		}
-		def, err = read(os.Stdin, clicontext)
+		def, err = readDefinition(clicontext)
 		if err != nil {
 			return err
 		}
@@ -244,6 +349,9 @@ func buildAction(clicontext *cli.Context) error {
 			return errors.Errorf("empty definition sent to build. Specify --frontend instead?")
 		}
 	} else {
+		if clicontext.String("definition") != "" {
+			return errors.New("--definition can't be used with --frontend")
+		}
 		if clicontext.Bool("no-cache") {
 			solveOpt.FrontendAttrs["no-cache"] = ""
 		}
@@ -267,6 +375,21 @@ func buildAction(clicontext *cli.Context) error {
 			return nil
 		})
 	}
+	if graphFile := clicontext.String("dump-graph"); graphFile != "" {
+		graph := build.NewGraph()
+		graphCh := make(chan *client.SolveStatus)
+		pw = progresswriter.Tee(pw, graphCh)
+		eg.Go(func() error {
+			for s := range graphCh {
+				graph.Update(s)
+			}
+			var buf bytes.Buffer
+			if err := graph.WriteDot(&buf); err != nil {
+				return err
+			}
+			return continuity.AtomicWriteFile(graphFile, buf.Bytes(), 0666)
+		})
+	}
 	mw := progresswriter.NewMultiWriter(pw)
 
 	var writers []progresswriter.Writer
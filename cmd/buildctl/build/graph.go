@@ -0,0 +1,65 @@
+package build
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/moby/buildkit/client"
+	digest "github.com/opencontainers/go-digest"
+)
+
+// Graph collects the vertices of the status of a build for --dump-graph
+type Graph struct {
+	vertices map[digest.Digest]*client.Vertex
+	order    []digest.Digest
+}
+
+// NewGraph returns an empty graph
+func NewGraph() *Graph {
+	return &Graph{vertices: map[digest.Digest]*client.Vertex{}}
+}
+
+// Update adds the vertices of a status update to the graph. Vertices that are
+// already known are replaced with their latest state.
+func (g *Graph) Update(ss *client.SolveStatus) {
+	for _, v := range ss.Vertexes {
+		if _, ok := g.vertices[v.Digest]; !ok {
+			g.order = append(g.order, v.Digest)
+		}
+		g.vertices[v.Digest] = v
+	}
+}
+
+// WriteDot writes the graph in the Graphviz DOT format. Edges point from the
+// inputs of a vertex to the vertex. Cached vertices are filled green and
+// failed vertices red.
+func (g *Graph) WriteDot(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "digraph {"); err != nil {
+		return err
+	}
+	for _, dgst := range g.order {
+		v := g.vertices[dgst]
+		attrs := fmt.Sprintf("label=%q shape=%q", v.Name, "box")
+		switch {
+		case v.Error != "":
+			attrs += ` style="filled" fillcolor="lightcoral"`
+		case v.Cached:
+			attrs += ` style="filled" fillcolor="palegreen"`
+		}
+		if _, err := fmt.Fprintf(w, "  %q [%s];\n", v.Digest, attrs); err != nil {
+			return err
+		}
+	}
+	for _, dgst := range g.order {
+		for _, inp := range g.vertices[dgst].Inputs {
+			if _, ok := g.vertices[inp]; !ok {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "  %q -> %q;\n", inp, dgst); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
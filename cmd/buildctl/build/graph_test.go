@@ -0,0 +1,31 @@
+package build
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/moby/buildkit/client"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphWriteDot(t *testing.T) {
+	g := NewGraph()
+	g.Update(&client.SolveStatus{Vertexes: []*client.Vertex{
+		{Digest: "sha256:a", Name: "docker-image://busybox"},
+		{Digest: "sha256:b", Name: "sh -c true", Inputs: []digest.Digest{"sha256:a"}},
+	}})
+	g.Update(&client.SolveStatus{Vertexes: []*client.Vertex{
+		{Digest: "sha256:a", Name: "docker-image://busybox", Cached: true},
+		{Digest: "sha256:b", Name: "sh -c true", Inputs: []digest.Digest{"sha256:a", "sha256:unknown"}, Error: "failed"},
+	}})
+
+	var buf bytes.Buffer
+	require.NoError(t, g.WriteDot(&buf))
+	require.Equal(t, `digraph {
+  "sha256:a" [label="docker-image://busybox" shape="box" style="filled" fillcolor="palegreen"];
+  "sha256:b" [label="sh -c true" shape="box" style="filled" fillcolor="lightcoral"];
+  "sha256:a" -> "sha256:b";
+}
+`, buf.String())
+}
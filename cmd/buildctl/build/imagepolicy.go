@@ -0,0 +1,21 @@
+package build
+
+import (
+	"github.com/BurntSushi/toml"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/imagepolicy/imagepolicyprovider"
+	"github.com/moby/buildkit/util/imagepolicy"
+	"github.com/pkg/errors"
+)
+
+// ParseImagePolicy parses --image-policy. The file has the imagePolicy rules
+// of the daemon configuration.
+func ParseImagePolicy(p string) (session.Attachable, error) {
+	var cfg struct {
+		ImagePolicy []imagepolicy.Rule `toml:"imagePolicy"`
+	}
+	if _, err := toml.DecodeFile(p, &cfg); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse image policy %s", p)
+	}
+	return imagepolicyprovider.NewImagePolicyProvider(cfg.ImagePolicy)
+}
@@ -0,0 +1,76 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/content/local"
+	"github.com/containerd/containerd/images/archive"
+	"github.com/pkg/errors"
+)
+
+// ParseOCILayout parses --oci-layout. The path of a store is either an OCI
+// layout directory or a tarball created by docker save or of an OCI layout.
+// Tarballs are imported into temporary directories that are removed by the
+// returned function. The digest of the index of every imported tarball is
+// written to stderr.
+func ParseOCILayout(ctx context.Context, stderr io.Writer, layouts []string) (map[string]content.Store, func(), error) {
+	var dirs []string
+	cleanup := func() {
+		for _, d := range dirs {
+			os.RemoveAll(d)
+		}
+	}
+
+	m, err := attrMap(layouts)
+	if err != nil {
+		return nil, nil, err
+	}
+	stores := make(map[string]content.Store, len(m))
+	for id, p := range m {
+		fi, err := os.Stat(p)
+		if err != nil {
+			cleanup()
+			return nil, nil, errors.Wrapf(err, "invalid store %s", id)
+		}
+		if fi.IsDir() {
+			cs, err := local.NewStore(p)
+			if err != nil {
+				cleanup()
+				return nil, nil, errors.Wrapf(err, "failed to open store %s", id)
+			}
+			stores[id] = cs
+			continue
+		}
+
+		dir, err := ioutil.TempDir("", "buildctl-oci-layout")
+		if err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+		dirs = append(dirs, dir)
+		cs, err := local.NewStore(dir)
+		if err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+		desc, err := archive.ImportIndex(ctx, cs, f)
+		f.Close()
+		if err != nil {
+			cleanup()
+			return nil, nil, errors.Wrapf(err, "failed to import %s", p)
+		}
+		fmt.Fprintf(stderr, "imported %s to oci store %s: %s\n", p, id, desc.Digest)
+		stores[id] = cs
+	}
+	return stores, cleanup, nil
+}
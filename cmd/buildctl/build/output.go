@@ -100,7 +100,7 @@ func resolveExporterDest(exporter, dest string) (func(map[string]string) (io.Wri
 			return nil, "", errors.New("output directory is required for local exporter")
 		}
 		return nil, dest, nil
-	case client.ExporterOCI, client.ExporterDocker, client.ExporterTar:
+	case client.ExporterOCI, client.ExporterDocker, client.ExporterTar, client.ExporterMerkle, client.ExporterProvenance, client.ExporterFSImage:
 		if dest != "" && dest != "-" {
 			fi, err := os.Stat(dest)
 			if err != nil && !errors.Is(err, os.ErrNotExist) {
@@ -0,0 +1,60 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOutput(t *testing.T) {
+	type testCase struct {
+		exports     []string // --output
+		expected    []client.ExportEntry
+		expectedErr string
+	}
+	testCases := []testCase{
+		{
+			exports: []string{"type=image,name=example.com/foo/bar,config.env-remove=FOO:BAR"},
+			expected: []client.ExportEntry{
+				{
+					Type: "image",
+					Attrs: map[string]string{
+						"name":              "example.com/foo/bar",
+						"config.env-remove": "FOO:BAR",
+					},
+				},
+			},
+		},
+		{
+			exports: []string{`type=image,"config.env=[""A=1"",""B=2""]"`},
+			expected: []client.ExportEntry{
+				{
+					Type: "image",
+					Attrs: map[string]string{
+						"config.env": `["A=1","B=2"]`,
+					},
+				},
+			},
+		},
+		{
+			// commas separate the options
+			exports:     []string{"type=image,config.env-remove=FOO,BAR"},
+			expectedErr: "invalid value BAR",
+		},
+		{
+			exports:     []string{"name=example.com/foo/bar"},
+			expectedErr: "--output requires type=<type>",
+		},
+	}
+	for _, tc := range testCases {
+		ex, err := ParseOutput(tc.exports)
+		if tc.expectedErr == "" {
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expected, ex)
+		} else {
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expectedErr)
+		}
+	}
+}
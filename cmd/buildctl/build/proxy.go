@@ -0,0 +1,35 @@
+package build
+
+import (
+	"strings"
+
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/proxy"
+	"github.com/moby/buildkit/session/proxy/proxyprovider"
+	"github.com/pkg/errors"
+)
+
+// ParseProxy parses --proxy
+func ParseProxy(inp []string) (session.Attachable, error) {
+	if len(inp) == 1 && inp[0] == "env" {
+		return proxyprovider.FromEnvironment(), nil
+	}
+	var cfg proxy.Config
+	for _, v := range inp {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid proxy option %q, expected env or key=value", v)
+		}
+		switch strings.ToLower(parts[0]) {
+		case "http_proxy":
+			cfg.HTTPProxy = parts[1]
+		case "https_proxy":
+			cfg.HTTPSProxy = parts[1]
+		case "no_proxy":
+			cfg.NoProxy = parts[1]
+		default:
+			return nil, errors.Errorf("unexpected key %q in proxy option %q", parts[0], v)
+		}
+	}
+	return proxyprovider.NewProxyProvider(cfg), nil
+}
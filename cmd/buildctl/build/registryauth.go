@@ -0,0 +1,60 @@
+package build
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/auth/authprovider"
+	"github.com/pkg/errors"
+)
+
+// ParseRegistryAuthConfig parses --registry-auth-config
+func ParseRegistryAuthConfig(stderr io.Writer, inp []string) (session.Attachable, error) {
+	if len(inp) == 0 {
+		return authprovider.NewDockerAuthProvider(stderr), nil
+	}
+	dirs := make(map[string]string, len(inp))
+	for _, v := range inp {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.Errorf("invalid registry auth config %q, expected <role>=<dir>", v)
+		}
+		dirs[parts[0]] = parts[1]
+	}
+	return authprovider.NewDockerAuthProviderWithRoles(stderr, dirs)
+}
+
+// ParseRegistryTokenFiles parses --registry-token-file
+func ParseRegistryTokenFiles(inp []string) ([]authprovider.TokenFile, error) {
+	files := make([]authprovider.TokenFile, 0, len(inp))
+	for _, v := range inp {
+		fields, err := csv.NewReader(strings.NewReader(v)).Read()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse csv registry token file")
+		}
+		var tf authprovider.TokenFile
+		for _, field := range fields {
+			parts := strings.SplitN(field, "=", 2)
+			if len(parts) != 2 {
+				return nil, errors.Errorf("invalid field '%s' must be a key=value pair", field)
+			}
+			switch key := strings.ToLower(parts[0]); key {
+			case "host":
+				tf.Host = parts[1]
+			case "source", "src":
+				tf.Path = parts[1]
+			case "username":
+				tf.Username = parts[1]
+			default:
+				return nil, errors.Errorf("unexpected key '%s' in '%s'", key, field)
+			}
+		}
+		if tf.Host == "" || tf.Path == "" {
+			return nil, errors.Errorf("invalid registry token file %q, expected host=<host>,src=<path>", v)
+		}
+		files = append(files, tf)
+	}
+	return files, nil
+}
@@ -68,6 +68,52 @@ func testBuildLocalExporter(t *testing.T, sb integration.Sandbox) {
 	require.Equal(t, string(dt), "bar")
 }
 
+func testBuildDefinitionFile(t *testing.T, sb integration.Sandbox) {
+	st := llb.Image("busybox").
+		Run(llb.Shlex("sh -c 'echo -n bar > /out/foo'"))
+
+	out := st.AddMount("/out", llb.Scratch())
+
+	rdr, err := marshal(sb.Context(), out)
+	require.NoError(t, err)
+
+	tmpdir, err := ioutil.TempDir("", "buildkit-buildctl")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	dt, err := ioutil.ReadAll(rdr)
+	require.NoError(t, err)
+	defFile := filepath.Join(tmpdir, "def.pb")
+	require.NoError(t, ioutil.WriteFile(defFile, dt, 0600))
+
+	outDir := filepath.Join(tmpdir, "out")
+	cmd := sb.Cmd(fmt.Sprintf("build --progress=plain --definition %s --output type=local,dest=%s", defFile, outDir))
+	require.NoError(t, cmd.Run())
+
+	dt, err = ioutil.ReadFile(filepath.Join(outDir, "foo"))
+	require.NoError(t, err)
+	require.Equal(t, "bar", string(dt))
+
+	// stdin is not read when the definition is read from a file
+	defDt, err := ioutil.ReadFile(defFile)
+	require.NoError(t, err)
+	outDir = filepath.Join(tmpdir, "out2")
+	cmd = sb.Cmd(fmt.Sprintf("build --progress=plain --definition %s --output type=local,dest=%s", defFile, outDir))
+	cmd.Stdin = strings.NewReader("not a definition")
+	require.NoError(t, cmd.Run())
+	_, err = os.Stat(filepath.Join(outDir, "foo"))
+	require.NoError(t, err)
+
+	// "-" reads the definition from stdin
+	outDir = filepath.Join(tmpdir, "out3")
+	cmd = sb.Cmd(fmt.Sprintf("build --progress=plain --definition - --output type=local,dest=%s", outDir))
+	cmd.Stdin = bytes.NewReader(defDt)
+	require.NoError(t, cmd.Run())
+	dt, err = ioutil.ReadFile(filepath.Join(outDir, "foo"))
+	require.NoError(t, err)
+	require.Equal(t, "bar", string(dt))
+}
+
 func testBuildContainerdExporter(t *testing.T, sb integration.Sandbox) {
 	cdAddress := sb.ContainerdAddress()
 	if cdAddress == "" {
@@ -17,6 +17,7 @@ func TestCLIIntegration(t *testing.T) {
 		testDiskUsage,
 		testBuildWithLocalFiles,
 		testBuildLocalExporter,
+		testBuildDefinitionFile,
 		testBuildContainerdExporter,
 		testBuildMetadataFile,
 		testPrune,
@@ -0,0 +1,93 @@
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/moby/buildkit/client"
+	bccommon "github.com/moby/buildkit/cmd/buildctl/common"
+	gateway "github.com/moby/buildkit/frontend/gateway/client"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var DockerfileCheckCommand = cli.Command{
+	Name:      "dockerfile-check",
+	Usage:     "validate a Dockerfile without building it",
+	ArgsUsage: "<Dockerfile>",
+	Action:    dockerfileCheck,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "target",
+			Usage: "Stage that is expected to exist in the Dockerfile",
+		},
+	},
+}
+
+type dockerfileDiagnostic struct {
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+func dockerfileCheck(clicontext *cli.Context) error {
+	if clicontext.NArg() != 1 {
+		return errors.Errorf("dockerfile-check requires exactly 1 argument: <Dockerfile>")
+	}
+	fn, err := filepath.Abs(clicontext.Args().First())
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(fn)
+
+	c, err := bccommon.ResolveClient(clicontext)
+	if err != nil {
+		return err
+	}
+
+	var diags []dockerfileDiagnostic
+	_, err = c.Build(commandContext(clicontext), client.SolveOpt{
+		LocalDirs: map[string]string{
+			"dockerfile": dir,
+			"context":    dir,
+		},
+	}, "buildctl", func(ctx context.Context, gc gateway.Client) (*gateway.Result, error) {
+		res, err := gc.Solve(ctx, gateway.SolveRequest{
+			Frontend: "dockerfile.v0",
+			FrontendOpt: map[string]string{
+				"filename": filepath.Base(fn),
+				"target":   clicontext.String("target"),
+				"check":    "true",
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		dt, ok := res.Metadata["result.json"]
+		if !ok {
+			return nil, errors.Errorf("frontend does not support the check option")
+		}
+		if err := json.Unmarshal(dt, &diags); err != nil {
+			return nil, errors.Wrap(err, "failed to parse check result")
+		}
+		return gateway.NewResult(), nil
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range diags {
+		if d.Line > 0 {
+			fmt.Fprintf(os.Stdout, "%s:%d:%d: %s\n", clicontext.Args().First(), d.Line, d.Column, d.Message)
+		} else {
+			fmt.Fprintf(os.Stdout, "%s: %s\n", clicontext.Args().First(), d.Message)
+		}
+	}
+	if len(diags) > 0 {
+		return errors.Errorf("found %d problems in %s", len(diags), clicontext.Args().First())
+	}
+	return nil
+}
@@ -108,7 +108,7 @@ func attr(dgst digest.Digest, op pb.Op) (string, string) {
 	case *pb.Op_Source:
 		return op.Source.Identifier, "ellipse"
 	case *pb.Op_Exec:
-		return strings.Join(op.Exec.Meta.Args, " "), "box"
+		return strings.Join(op.Exec.Meta.ProcessArgs(), " "), "box"
 	case *pb.Op_Build:
 		return "build", "box3d"
 	case *pb.Op_File:
@@ -0,0 +1,73 @@
+package debug
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/containerd/containerd/mount"
+	bccommon "github.com/moby/buildkit/cmd/buildctl/common"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var MountCacheCommand = cli.Command{
+	Name:      "mount-cache",
+	Usage:     "mount a cache mount read-only for inspection (the daemon must run on the same host)",
+	ArgsUsage: "<id> <dir>",
+	Action:    mountCache,
+}
+
+func mountCache(clicontext *cli.Context) error {
+	if clicontext.NArg() != 2 {
+		return errors.Errorf("mount-cache requires exactly 2 arguments: <id> <dir>")
+	}
+	id := clicontext.Args().Get(0)
+	target, err := filepath.Abs(clicontext.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	c, err := bccommon.ResolveClient(clicontext)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(commandContext(clicontext))
+	defer cancel()
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(ch)
+	go func() {
+		select {
+		case <-ch:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var mounted bool
+	var mountErr error
+	err = c.MountCache(ctx, id, func(ref, p string) {
+		if err := mount.All([]mount.Mount{{Type: "bind", Source: p, Options: []string{"rbind", "ro"}}}, target); err != nil {
+			mountErr = errors.Wrapf(err, "failed to mount cache %s from %s at %s", id, p, target)
+			cancel()
+			return
+		}
+		mounted = true
+		fmt.Fprintf(os.Stderr, "mounted cache %s (%s) at %s, press Ctrl-C to unmount\n", id, ref, target)
+	})
+	if mounted {
+		if err := mount.UnmountAll(target, 0); err != nil {
+			return errors.Wrapf(err, "failed to unmount %s", target)
+		}
+	}
+	if mountErr != nil {
+		return mountErr
+	}
+	return err
+}
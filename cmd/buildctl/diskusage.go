@@ -79,6 +79,9 @@ func printVerbose(tw *tabwriter.Writer, du []*client.UsageInfo) {
 		if di.RecordType != "" {
 			printKV(tw, "Type", di.RecordType)
 		}
+		if di.CacheMountID != "" {
+			printKV(tw, "Cache mount ID", di.CacheMountID)
+		}
 
 		fmt.Fprintf(tw, "\n")
 	}
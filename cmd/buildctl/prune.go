@@ -32,6 +32,10 @@ var pruneCommand = cli.Command{
 			Name:  "all",
 			Usage: "Include internal/frontend references",
 		},
+		cli.StringFlag{
+			Name:  "worker",
+			Usage: "Only prune the cache of the worker with this ID",
+		},
 		cli.BoolFlag{
 			Name:  "verbose, v",
 			Usage: "Verbose output",
@@ -78,6 +82,10 @@ func prune(clicontext *cli.Context) error {
 		opts = append(opts, client.PruneAll)
 	}
 
+	if w := clicontext.String("worker"); w != "" {
+		opts = append(opts, client.WithPruneWorker(w))
+	}
+
 	err = c.Prune(bccommon.CommandContext(clicontext), ch, opts...)
 	close(ch)
 	<-printed
@@ -2,6 +2,7 @@ package config
 
 import (
 	"github.com/BurntSushi/toml"
+	"github.com/moby/buildkit/util/imagepolicy"
 	"github.com/moby/buildkit/util/resolver"
 )
 
@@ -24,7 +25,69 @@ type Config struct {
 
 	Registries map[string]resolver.RegistryConfig `toml:"registry"`
 
+	// ImagePolicy requires the images pulled by image sources to be signed.
+	// The first rule matching an image applies.
+	ImagePolicy []imagepolicy.Rule `toml:"imagePolicy"`
+
 	DNS *DNSConfig `toml:"dns"`
+
+	// History configures keeping the status of recent builds
+	History *HistoryConfig `toml:"history"`
+
+	// MaxExecParallelism limits the exec operations that run at the same
+	// time across all builds and workers. Zero means no limit. The
+	// SetExecParallelism control API changes it until the daemon restarts.
+	MaxExecParallelism int `toml:"max-exec-parallelism"`
+
+	// CriticalPathScheduling makes the exec operations waiting for
+	// max-exec-parallelism start in the order of the longest chain of
+	// operations that depend on them instead of the order they became ready.
+	CriticalPathScheduling bool `toml:"critical-path-scheduling"`
+
+	// MaxHashConcurrency limits the number of files that a build can hash in
+	// parallel with SolveOpt.HashConcurrency. Zero means the number of CPUs.
+	MaxHashConcurrency int `toml:"max-hash-concurrency"`
+
+	// MaxExecRetries limits the number of times a step is re-run with
+	// llb.WithRetry. Zero means 5, a negative value disables retries.
+	MaxExecRetries int `toml:"max-exec-retries"`
+
+	// MaxLogLineSize clips the lines of the logs of the steps that are longer
+	// than the size in bytes. Zero means no limit.
+	MaxLogLineSize int `toml:"max-log-line-size"`
+
+	Frontends struct {
+		Gateway GatewayFrontendConfig `toml:"gateway"`
+	} `toml:"frontend"`
+
+	// RemoteWorker configures running exec ops on other daemons
+	RemoteWorker RemoteWorkerConfig `toml:"remoteworker"`
+}
+
+type RemoteWorkerConfig struct {
+	// Serve makes the daemon run the exec ops of the remote workers of other
+	// daemons with its default worker. The processes are checked against the
+	// entitlements and the security settings of this daemon.
+	Serve bool `toml:"serve"`
+	// Executors are the daemons whose default workers are added as remote
+	// workers. Exec ops whose constraints match the labels of a remote
+	// worker run on its daemon.
+	Executors []RemoteExecutorConfig `toml:"executor"`
+}
+
+type RemoteExecutorConfig struct {
+	Address string    `toml:"address"`
+	TLS     TLSConfig `toml:"tls"`
+	// ServerName overrides the name used to verify the TLS certificate of
+	// the daemon
+	ServerName string `toml:"serverName"`
+}
+
+type GatewayFrontendConfig struct {
+	// AllowedImages are patterns of the image names that can be run as
+	// frontends, e.g. "docker.io/docker/dockerfile" or "registry.example.com/frontends/*".
+	// All images are allowed when empty.
+	AllowedImages []string `toml:"allowedImages"`
 }
 
 type GRPCConfig struct {
@@ -38,6 +101,11 @@ type GRPCConfig struct {
 	// MaxSendMsgSize int    `toml:"max_send_message_size"`
 }
 
+type HistoryConfig struct {
+	MaxBuilds int `toml:"maxBuilds"`
+	MaxEvents int `toml:"maxEvents"`
+}
+
 type TLSConfig struct {
 	Cert string `toml:"cert"`
 	Key  string `toml:"key"`
@@ -80,8 +148,70 @@ type OCIConfig struct {
 	// ApparmorProfile is the name of the apparmor profile that should be used to constrain build containers.
 	// The profile should already be loaded (by a higher level system) before creating a worker.
 	ApparmorProfile string `toml:"apparmor-profile"`
+	// AllowedApparmorProfiles lists the apparmor profiles that builds can
+	// run processes with with llb.WithApparmorProfile. "unconfined" also
+	// allows the profiles that are loaded in complain mode.
+	AllowedApparmorProfiles []string `toml:"allowedApparmorProfiles"`
+	// AllowSeccompUnconfined allows builds with the security.insecure
+	// entitlement to run processes without seccomp with llb.Unconfined or
+	// with custom profiles with llb.WithSeccompProfile.
+	AllowSeccompUnconfined bool `toml:"allowSeccompUnconfined"`
+	// AllowedSysctls lists the sysctls that builds can set with
+	// llb.WithSysctl. Patterns like "net.ipv4.*" are allowed.
+	AllowedSysctls []string `toml:"allowedSysctls"`
 
 	MaxParallelism int `toml:"max-parallelism"`
+
+	// HostPaths maps names to host directories that builds can bind mount
+	// into build containers. Only directories listed here can be mounted.
+	HostPaths map[string]string `toml:"hostPaths"`
+
+	// PassthroughEnv lists the env variables of the daemon that builds can
+	// pass to their processes with llb.PassthroughEnv. Other names are ignored.
+	PassthroughEnv []string `toml:"passthroughEnv"`
+
+	// HostZoneinfo lists the timezones whose zoneinfo file of the daemon
+	// builds can mount with llb.MountHostZoneinfo. Patterns like "Europe/*"
+	// are allowed.
+	HostZoneinfo []string `toml:"hostZoneinfo"`
+
+	// Hooks are OCI lifecycle hooks that are added to every build container.
+	// They run with the privileges of the daemon.
+	Hooks *OCIHooksConfig `toml:"hooks"`
+
+	// Devices are the host devices, e.g. /dev/fuse, that builds can request
+	// access to. Builds can't use any devices that are not listed.
+	Devices []string `toml:"devices"`
+
+	// TempDir is the directory for the bundles and temporary mounts of build
+	// containers. Defaults to the worker state directory and the OS temporary
+	// directory.
+	TempDir string `toml:"tempDir"`
+
+	// BatchCommits adds the snapshots committed for build steps to their
+	// leases in the transaction of the snapshot commit.
+	BatchCommits bool `toml:"batchCommits"`
+
+	// ContentWriteBufferSize is the size in bytes of the buffer of the writes
+	// to the content store, e.g. of exported and pulled blobs. Zero keeps the
+	// unbuffered writes.
+	ContentWriteBufferSize int `toml:"contentWriteBufferSize"`
+}
+
+type OCIHooksConfig struct {
+	Prestart        []OCIHook `toml:"prestart"`
+	CreateRuntime   []OCIHook `toml:"createRuntime"`
+	CreateContainer []OCIHook `toml:"createContainer"`
+	StartContainer  []OCIHook `toml:"startContainer"`
+	Poststart       []OCIHook `toml:"poststart"`
+	Poststop        []OCIHook `toml:"poststop"`
+}
+
+type OCIHook struct {
+	Path    string   `toml:"path"`
+	Args    []string `toml:"args"`
+	Env     []string `toml:"env"`
+	Timeout *int     `toml:"timeout"`
 }
 
 type ContainerdConfig struct {
@@ -97,8 +227,41 @@ type ContainerdConfig struct {
 	// ApparmorProfile is the name of the apparmor profile that should be used to constrain build containers.
 	// The profile should already be loaded (by a higher level system) before creating a worker.
 	ApparmorProfile string `toml:"apparmor-profile"`
+	// AllowedApparmorProfiles lists the apparmor profiles that builds can
+	// run processes with with llb.WithApparmorProfile. "unconfined" also
+	// allows the profiles that are loaded in complain mode.
+	AllowedApparmorProfiles []string `toml:"allowedApparmorProfiles"`
+	// AllowSeccompUnconfined allows builds with the security.insecure
+	// entitlement to run processes without seccomp with llb.Unconfined or
+	// with custom profiles with llb.WithSeccompProfile.
+	AllowSeccompUnconfined bool `toml:"allowSeccompUnconfined"`
+	// AllowedSysctls lists the sysctls that builds can set with
+	// llb.WithSysctl. Patterns like "net.ipv4.*" are allowed.
+	AllowedSysctls []string `toml:"allowedSysctls"`
 
 	MaxParallelism int `toml:"max-parallelism"`
+
+	// HostPaths maps names to host directories that builds can bind mount
+	// into build containers. Only directories listed here can be mounted.
+	HostPaths map[string]string `toml:"hostPaths"`
+
+	// PassthroughEnv lists the env variables of the daemon that builds can
+	// pass to their processes with llb.PassthroughEnv. Other names are ignored.
+	PassthroughEnv []string `toml:"passthroughEnv"`
+
+	// HostZoneinfo lists the timezones whose zoneinfo file of the daemon
+	// builds can mount with llb.MountHostZoneinfo. Patterns like "Europe/*"
+	// are allowed.
+	HostZoneinfo []string `toml:"hostZoneinfo"`
+
+	// BatchCommits adds the snapshots committed for build steps to their
+	// leases in the transaction of the snapshot commit.
+	BatchCommits bool `toml:"batchCommits"`
+
+	// ContentWriteBufferSize is the size in bytes of the buffer of the writes
+	// to the content store, e.g. of exported and pulled blobs. Zero keeps the
+	// unbuffered writes.
+	ContentWriteBufferSize int `toml:"contentWriteBufferSize"`
 }
 
 type GCPolicy struct {
@@ -13,6 +13,7 @@ func TestConfig(t *testing.T) {
 root = "/foo/bar"
 debug=true
 insecure-entitlements = ["security.insecure"]
+max-log-line-size = 4096
 
 [gc]
 enabled=true
@@ -39,6 +40,7 @@ foo="bar"
 namespace="non-default"
 platforms=["linux/amd64"]
 address="containerd.sock"
+allowedSysctls=["net.ipv4.*"]
 [[worker.containerd.gcpolicy]]
 all=true
 filters=["foo==bar"]
@@ -92,6 +94,7 @@ searchDomains=["example.com"]
 	require.Nil(t, cfg.Workers.Containerd.Enabled)
 	require.Equal(t, 1, len(cfg.Workers.Containerd.Platforms))
 	require.Equal(t, "containerd.sock", cfg.Workers.Containerd.Address)
+	require.Equal(t, []string{"net.ipv4.*"}, cfg.Workers.Containerd.AllowedSysctls)
 
 	require.Equal(t, 0, len(cfg.Workers.OCI.GCPolicy))
 	require.Equal(t, "non-default", cfg.Workers.Containerd.Namespace)
@@ -119,4 +122,6 @@ searchDomains=["example.com"]
 	require.Equal(t, cfg.DNS.Nameservers, []string{"1.1.1.1", "8.8.8.8"})
 	require.Equal(t, cfg.DNS.SearchDomains, []string{"example.com"})
 	require.Equal(t, cfg.DNS.Options, []string{"edns0"})
+
+	require.Equal(t, 4096, cfg.MaxLogLineSize)
 }
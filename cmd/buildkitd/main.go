@@ -45,7 +45,9 @@ import (
 	"github.com/moby/buildkit/util/appdefaults"
 	"github.com/moby/buildkit/util/archutil"
 	"github.com/moby/buildkit/util/grpcerrors"
+	"github.com/moby/buildkit/util/imagepolicy"
 	"github.com/moby/buildkit/util/profiler"
+	"github.com/moby/buildkit/util/progress/logs"
 	"github.com/moby/buildkit/util/resolver"
 	"github.com/moby/buildkit/util/stack"
 	"github.com/moby/buildkit/util/tracing/detect"
@@ -83,6 +85,7 @@ type workerInitializerOpt struct {
 	configMetaData *toml.MetaData
 	sessionManager *session.Manager
 	traceSocket    string
+	imagePolicy    *imagepolicy.Policy
 }
 
 type workerInitializer struct {
@@ -189,6 +192,11 @@ func main() {
 			Name:  "allow-insecure-entitlement",
 			Usage: "allows insecure entitlements e.g. network.host, security.insecure",
 		},
+		cli.IntFlag{
+			Name:  "max-log-line-size",
+			Usage: "clip the lines of the logs of the build steps that are longer than the size in bytes",
+			Value: defaultConf.MaxLogLineSize,
+		},
 	)
 	app.Flags = append(app.Flags, appFlags...)
 
@@ -216,6 +224,10 @@ func main() {
 			logrus.SetLevel(logrus.DebugLevel)
 		}
 
+		if cfg.MaxLogLineSize > 0 {
+			logs.SetMaxLineSize(cfg.MaxLogLineSize)
+		}
+
 		if cfg.GRPC.DebugAddress != "" {
 			if err := setupDebugHandlers(cfg.GRPC.DebugAddress); err != nil {
 				return err
@@ -260,7 +272,7 @@ func main() {
 			os.RemoveAll(lockPath)
 		}()
 
-		controller, err := newController(c, &cfg, md)
+		controller, err := newController(ctx, c, &cfg, md, server)
 		if err != nil {
 			return err
 		}
@@ -457,6 +469,10 @@ func applyMainFlags(c *cli.Context, cfg *config.Config, md *toml.MetaData) error
 		cfg.GRPC.DebugAddress = c.String("debugaddr")
 	}
 
+	if c.IsSet("max-log-line-size") {
+		cfg.MaxLogLineSize = c.Int("max-log-line-size")
+	}
+
 	if md == nil || !md.IsDefined("grpc", "uid") {
 		cfg.GRPC.UID = os.Getuid()
 	}
@@ -608,7 +624,7 @@ func serverCredentials(cfg config.TLSConfig) (*tls.Config, error) {
 	return tlsConf, nil
 }
 
-func newController(c *cli.Context, cfg *config.Config, md *toml.MetaData) (*control.Controller, error) {
+func newController(ctx context.Context, c *cli.Context, cfg *config.Config, md *toml.MetaData, server *grpc.Server) (*control.Controller, error) {
 	sessionManager, err := session.NewManager()
 	if err != nil {
 		return nil, err
@@ -627,18 +643,31 @@ func newController(c *cli.Context, cfg *config.Config, md *toml.MetaData) (*cont
 		}
 	}
 
+	imagePolicy, err := imagepolicy.New(cfg.ImagePolicy)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid image policy")
+	}
+
 	wc, err := newWorkerController(c, workerInitializerOpt{
 		config:         cfg,
 		configMetaData: md,
 		sessionManager: sessionManager,
 		traceSocket:    traceSocket,
+		imagePolicy:    imagePolicy,
 	})
 	if err != nil {
 		return nil, err
 	}
+	if err := setupRemoteWorkers(ctx, cfg, wc, server); err != nil {
+		return nil, err
+	}
 	frontends := map[string]frontend.Frontend{}
 	frontends["dockerfile.v0"] = forwarder.NewGatewayForwarder(wc, dockerfile.Build)
-	frontends["gateway.v0"] = gateway.NewGatewayFrontend(wc)
+	gwfe, err := gateway.NewGatewayFrontend(wc, cfg.Frontends.Gateway.AllowedImages)
+	if err != nil {
+		return nil, err
+	}
+	frontends["gateway.v0"] = gwfe
 
 	cacheStorage, err := bboltcachestorage.NewStore(filepath.Join(cfg.Root, "cache.db"))
 	if err != nil {
@@ -662,6 +691,12 @@ func newController(c *cli.Context, cfg *config.Config, md *toml.MetaData) (*cont
 		"local":    localremotecache.ResolveCacheImporterFunc(sessionManager),
 	}
 
+	var historyMaxBuilds, historyMaxEvents int
+	if cfg.History != nil {
+		historyMaxBuilds = cfg.History.MaxBuilds
+		historyMaxEvents = cfg.History.MaxEvents
+	}
+
 	return control.NewController(control.Opt{
 		SessionManager:            sessionManager,
 		WorkerController:          wc,
@@ -671,6 +706,13 @@ func newController(c *cli.Context, cfg *config.Config, md *toml.MetaData) (*cont
 		CacheKeyStorage:           cacheStorage,
 		Entitlements:              cfg.Entitlements,
 		TraceCollector:            tc,
+		HistoryMaxBuilds:          historyMaxBuilds,
+		HistoryMaxEvents:          historyMaxEvents,
+		MaxExecParallelism:        cfg.MaxExecParallelism,
+		CriticalPathScheduling:    cfg.CriticalPathScheduling,
+		MaxHashConcurrency:        cfg.MaxHashConcurrency,
+		MaxExecRetries:            cfg.MaxExecRetries,
+		MountCacheRoot:            filepath.Join(cfg.Root, "cache-mounts"),
 	})
 }
 
@@ -759,6 +801,23 @@ func getGCPolicy(cfg config.GCConfig, root string) []client.PruneInfo {
 	return out
 }
 
+func getHostPaths(cfg map[string]string) (map[string]string, error) {
+	if len(cfg) == 0 {
+		return nil, nil
+	}
+	m := make(map[string]string, len(cfg))
+	for name, p := range cfg {
+		if name == "" {
+			return nil, errors.Errorf("empty name for host path %s", p)
+		}
+		if !filepath.IsAbs(p) {
+			return nil, errors.Errorf("host path %s for %s needs to be absolute", p, name)
+		}
+		m[name] = filepath.Clean(p)
+	}
+	return m, nil
+}
+
 func getDNSConfig(cfg *config.DNSConfig) *oci.DNSConfig {
 	var dns *oci.DNSConfig
 	if cfg != nil {
@@ -96,6 +96,10 @@ func init() {
 			Name:  "containerd-worker-apparmor-profile",
 			Usage: "set the name of the apparmor profile applied to containers",
 		},
+		cli.BoolFlag{
+			Name:  "containerd-worker-allow-seccomp-unconfined",
+			Usage: "allow builds with the security.insecure entitlement to run processes without seccomp or with custom seccomp profiles",
+		},
 	}
 
 	if defaultConf.Workers.Containerd.GC == nil || *defaultConf.Workers.Containerd.GC {
@@ -200,6 +204,9 @@ func applyContainerdFlags(c *cli.Context, cfg *config.Config) error {
 	if c.GlobalIsSet("containerd-worker-apparmor-profile") {
 		cfg.Workers.Containerd.ApparmorProfile = c.GlobalString("containerd-worker-apparmor-profile")
 	}
+	if c.GlobalIsSet("containerd-worker-allow-seccomp-unconfined") {
+		cfg.Workers.Containerd.AllowSeccompUnconfined = c.GlobalBool("containerd-worker-allow-seccomp-unconfined")
+	}
 
 	return nil
 }
@@ -235,12 +242,26 @@ func containerdWorkerInitializer(c *cli.Context, common workerInitializerOpt) ([
 	if cfg.Snapshotter != "" {
 		snapshotter = cfg.Snapshotter
 	}
-	opt, err := containerd.NewWorkerOpt(common.config.Root, cfg.Address, snapshotter, cfg.Namespace, cfg.Labels, dns, nc, common.config.Workers.Containerd.ApparmorProfile, parallelismSem, common.traceSocket, ctd.WithTimeout(60*time.Second))
+	opt, err := containerd.NewWorkerOpt(common.config.Root, cfg.Address, snapshotter, cfg.Namespace, cfg.Labels, dns, nc, common.config.Workers.Containerd.ApparmorProfile, parallelismSem, common.traceSocket, containerd.Options{WriteBufferSize: cfg.ContentWriteBufferSize}, ctd.WithTimeout(60*time.Second))
 	if err != nil {
 		return nil, err
 	}
 	opt.GCPolicy = getGCPolicy(cfg.GCConfig, common.config.Root)
+	opt.BatchSnapshotCommits = cfg.BatchCommits
 	opt.RegistryHosts = resolverFunc(common.config)
+	hostPaths, err := getHostPaths(cfg.HostPaths)
+	if err != nil {
+		return nil, err
+	}
+	opt.Security = worker.SecurityConfig{
+		HostPaths:               hostPaths,
+		PassthroughEnv:          cfg.PassthroughEnv,
+		HostZoneinfo:            cfg.HostZoneinfo,
+		AllowedApparmorProfiles: cfg.AllowedApparmorProfiles,
+		AllowSeccompUnconfined:  cfg.AllowSeccompUnconfined,
+		AllowedSysctls:          cfg.AllowedSysctls,
+	}
+	opt.ImagePolicy = common.imagePolicy
 
 	if platformsStr := cfg.Platforms; len(platformsStr) != 0 {
 		platforms, err := parsePlatforms(platformsStr)
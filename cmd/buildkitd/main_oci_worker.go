@@ -5,6 +5,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -29,6 +30,7 @@ import (
 	sgzconf "github.com/containerd/stargz-snapshotter/fs/config"
 	sgzsource "github.com/containerd/stargz-snapshotter/fs/source"
 	remotesn "github.com/containerd/stargz-snapshotter/snapshot"
+	"github.com/docker/go-units"
 	"github.com/moby/buildkit/cmd/buildkitd/config"
 	"github.com/moby/buildkit/executor/oci"
 	"github.com/moby/buildkit/session"
@@ -38,10 +40,12 @@ import (
 	"github.com/moby/buildkit/worker"
 	"github.com/moby/buildkit/worker/base"
 	"github.com/moby/buildkit/worker/runc"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 	"golang.org/x/sync/semaphore"
+	"golang.org/x/sys/unix"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/backoff"
 )
@@ -107,6 +111,10 @@ func init() {
 			Name:  "oci-worker-apparmor-profile",
 			Usage: "set the name of the apparmor profile applied to containers",
 		},
+		cli.BoolFlag{
+			Name:  "oci-worker-allow-seccomp-unconfined",
+			Usage: "allow builds with the security.insecure entitlement to run processes without seccomp or with custom seccomp profiles",
+		},
 	}
 	n := "oci-worker-rootless"
 	u := "enable rootless mode"
@@ -229,6 +237,9 @@ func applyOCIFlags(c *cli.Context, cfg *config.Config) error {
 	if c.GlobalIsSet("oci-worker-apparmor-profile") {
 		cfg.Workers.OCI.ApparmorProfile = c.GlobalString("oci-worker-apparmor-profile")
 	}
+	if c.GlobalIsSet("oci-worker-allow-seccomp-unconfined") {
+		cfg.Workers.OCI.AllowSeccompUnconfined = c.GlobalBool("oci-worker-allow-seccomp-unconfined")
+	}
 	return nil
 }
 
@@ -287,12 +298,37 @@ func ociWorkerInitializer(c *cli.Context, common workerInitializerOpt) ([]worker
 		parallelismSem = semaphore.NewWeighted(int64(cfg.MaxParallelism))
 	}
 
-	opt, err := runc.NewWorkerOpt(common.config.Root, snFactory, cfg.Rootless, processMode, cfg.Labels, idmapping, nc, dns, cfg.Binary, cfg.ApparmorProfile, parallelismSem, common.traceSocket)
+	if cfg.TempDir != "" {
+		if err := validateTempDir(cfg.TempDir); err != nil {
+			return nil, err
+		}
+	}
+
+	opt, err := runc.NewWorkerOpt(common.config.Root, snFactory, cfg.Rootless, processMode, cfg.Labels, idmapping, nc, dns, cfg.Binary, cfg.ApparmorProfile, parallelismSem, common.traceSocket, runc.Options{
+		Hooks:           getOCIHooks(cfg.Hooks),
+		Devices:         cfg.Devices,
+		TempDir:         cfg.TempDir,
+		WriteBufferSize: cfg.ContentWriteBufferSize,
+	})
 	if err != nil {
 		return nil, err
 	}
 	opt.GCPolicy = getGCPolicy(cfg.GCConfig, common.config.Root)
+	opt.BatchSnapshotCommits = cfg.BatchCommits
 	opt.RegistryHosts = hosts
+	hostPaths, err := getHostPaths(cfg.HostPaths)
+	if err != nil {
+		return nil, err
+	}
+	opt.Security = worker.SecurityConfig{
+		HostPaths:               hostPaths,
+		PassthroughEnv:          cfg.PassthroughEnv,
+		HostZoneinfo:            cfg.HostZoneinfo,
+		AllowedApparmorProfiles: cfg.AllowedApparmorProfiles,
+		AllowSeccompUnconfined:  cfg.AllowSeccompUnconfined,
+		AllowedSysctls:          cfg.AllowedSysctls,
+	}
+	opt.ImagePolicy = common.imagePolicy
 
 	if platformsStr := cfg.Platforms; len(platformsStr) != 0 {
 		platforms, err := parsePlatforms(platformsStr)
@@ -478,3 +514,57 @@ func sourceWithSession(hosts docker.RegistryHosts, sm *session.Manager) sgzsourc
 		return src, nil
 	}
 }
+
+func getOCIHooks(cfg *config.OCIHooksConfig) *specs.Hooks {
+	if cfg == nil {
+		return nil
+	}
+	conv := func(in []config.OCIHook) []specs.Hook {
+		var out []specs.Hook
+		for _, h := range in {
+			out = append(out, specs.Hook{
+				Path:    h.Path,
+				Args:    h.Args,
+				Env:     h.Env,
+				Timeout: h.Timeout,
+			})
+		}
+		return out
+	}
+	return &specs.Hooks{
+		Prestart:        conv(cfg.Prestart),
+		CreateRuntime:   conv(cfg.CreateRuntime),
+		CreateContainer: conv(cfg.CreateContainer),
+		StartContainer:  conv(cfg.StartContainer),
+		Poststart:       conv(cfg.Poststart),
+		Poststop:        conv(cfg.Poststop),
+	}
+}
+
+// minTempDirSpace is the free space that the temporary directory of the
+// executor needs to have at startup
+const minTempDirSpace = 100 << 20
+
+func validateTempDir(dir string) error {
+	if !filepath.IsAbs(dir) {
+		return errors.Errorf("temporary directory %s needs to be absolute", dir)
+	}
+	if err := os.MkdirAll(dir, 0711); err != nil {
+		return errors.Wrapf(err, "failed to create temporary directory %s", dir)
+	}
+	f, err := ioutil.TempFile(dir, "buildkit-check")
+	if err != nil {
+		return errors.Wrapf(err, "temporary directory %s is not writable", dir)
+	}
+	f.Close()
+	os.Remove(f.Name())
+
+	var st unix.Statfs_t
+	if err := unix.Statfs(dir, &st); err != nil {
+		return errors.Wrapf(err, "failed to get free space of temporary directory %s", dir)
+	}
+	if free := int64(st.Bavail) * int64(st.Bsize); free < minTempDirSpace {
+		return errors.Errorf("temporary directory %s has %s free, at least %s is needed", dir, units.BytesSize(float64(free)), units.BytesSize(minTempDirSpace))
+	}
+	return nil
+}
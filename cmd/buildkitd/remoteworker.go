@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/cmd/buildkitd/config"
+	"github.com/moby/buildkit/util/entitlements"
+	"github.com/moby/buildkit/worker"
+	"github.com/moby/buildkit/worker/remote"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+// remoteWorkerRetryInterval is the time between the attempts to register the
+// remote worker of an executor that is not reachable
+const remoteWorkerRetryInterval = 10 * time.Second
+
+// setupRemoteWorkers serves the remote executor of the default worker if it
+// is enabled and registers the remote workers of the configured executors.
+// Remote workers are registered in the background once their daemon is
+// reachable.
+func setupRemoteWorkers(ctx context.Context, cfg *config.Config, wc *worker.Controller, server *grpc.Server) error {
+	local, err := wc.GetDefault()
+	if err != nil {
+		return err
+	}
+	if cfg.RemoteWorker.Serve {
+		// the processes of other daemons can use the entitlements that the
+		// builds of this daemon can be granted
+		var allowed []entitlements.Entitlement
+		for _, e := range cfg.Entitlements {
+			allowed = append(allowed, entitlements.Entitlement(e))
+		}
+		ent, err := entitlements.WhiteList(allowed, nil)
+		if err != nil {
+			return err
+		}
+		s, err := remote.NewServer(local, filepath.Join(cfg.Root, "remote-executor"), ent)
+		if err != nil {
+			return err
+		}
+		s.Register(server)
+	}
+	for _, ecfg := range cfg.RemoteWorker.Executors {
+		var opts []client.ClientOpt
+		if ecfg.TLS.CA != "" {
+			opts = append(opts, client.WithCredentials(ecfg.ServerName, ecfg.TLS.CA, ecfg.TLS.Cert, ecfg.TLS.Key))
+		}
+		c, err := client.New(ctx, ecfg.Address, opts...)
+		if err != nil {
+			return err
+		}
+		go registerRemoteWorker(ctx, ecfg.Address, c.Conn(), local, wc)
+	}
+	return nil
+}
+
+func registerRemoteWorker(ctx context.Context, address string, conn *grpc.ClientConn, local worker.Worker, wc *worker.Controller) {
+	for {
+		w, err := remote.NewWorker(ctx, conn, local)
+		if err == nil {
+			if err := wc.Add(w); err != nil {
+				logrus.Errorf("failed to add remote worker of %s: %v", address, err)
+				return
+			}
+			logrus.Infof("found remote worker %q of %s, labels=%v, platforms=%v", w.ID(), address, w.Labels(), formatPlatforms(w.Platforms(false)))
+			return
+		}
+		logrus.Warnf("failed to register remote worker of %s, retrying in %s: %v", address, remoteWorkerRetryInterval, err)
+		select {
+		case <-time.After(remoteWorkerRetryInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package control
+
+import (
+	"context"
+	"io"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/moby/buildkit/identity"
+	"github.com/moby/buildkit/util/leaseutil"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+const contentChunkSize = 1 << 20
+
+func (c *Controller) contentStore() (content.Store, error) {
+	w, err := c.opt.WorkerController.GetDefault()
+	if err != nil {
+		return nil, err
+	}
+	return w.ContentStore(), nil
+}
+
+func (c *Controller) ContentInfo(ctx context.Context, req *controlapi.ContentInfoRequest) (*controlapi.ContentInfoResponse, error) {
+	cs, err := c.contentStore()
+	if err != nil {
+		return nil, err
+	}
+	resp := &controlapi.ContentInfoResponse{}
+	for _, dgst := range req.Digests {
+		if _, err := cs.Info(ctx, dgst); err != nil {
+			if !errdefs.IsNotFound(err) {
+				return nil, err
+			}
+			resp.Missing = append(resp.Missing, dgst)
+		}
+	}
+	return resp, nil
+}
+
+func (c *Controller) ReadContent(req *controlapi.ReadContentRequest, stream controlapi.Control_ReadContentServer) error {
+	if err := req.Digest.Validate(); err != nil {
+		return errors.Wrapf(err, "invalid digest %q", req.Digest)
+	}
+	ctx := stream.Context()
+	cs, err := c.contentStore()
+	if err != nil {
+		return err
+	}
+	info, err := cs.Info(ctx, req.Digest)
+	if err != nil {
+		return err
+	}
+	ra, err := cs.ReaderAt(ctx, ocispec.Descriptor{Digest: info.Digest, Size: info.Size})
+	if err != nil {
+		return err
+	}
+	defer ra.Close()
+
+	resp := &controlapi.ReadContentResponse{Size_: info.Size}
+	buf := make([]byte, contentChunkSize)
+	r := content.NewReader(ra)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 || resp.Size_ != 0 {
+			resp.Data = buf[:n]
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+			resp = &controlapi.ReadContentResponse{}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Controller) WriteContent(stream controlapi.Control_WriteContentServer) error {
+	ctx := stream.Context()
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if err := req.Digest.Validate(); err != nil {
+		return errors.Wrapf(err, "invalid digest %q", req.Digest)
+	}
+	w, err := c.opt.WorkerController.GetDefault()
+	if err != nil {
+		return err
+	}
+
+	// the lease keeps the ingest from being garbage collected while the blob
+	// is written
+	ctx, done, err := leaseutil.WithLease(ctx, w.LeaseManager(), leaseutil.MakeTemporary)
+	if err != nil {
+		return err
+	}
+	defer done(context.TODO())
+
+	// WriteBlob verifies the size and digest of the written data and skips
+	// blobs that already exist. Concurrent copies of the same blob use their
+	// own ingests.
+	desc := ocispec.Descriptor{Digest: req.Digest, Size: req.Size_}
+	r := &contentReceiver{stream: stream, buf: req.Data}
+	ref := "copy-" + req.Digest.String() + "-" + identity.NewID()
+	if err := content.WriteBlob(ctx, w.ContentStore(), ref, r, desc); err != nil {
+		// the ingest of a unique ref is never resumed
+		w.ContentStore().Abort(context.TODO(), ref)
+		return errors.Wrapf(err, "failed to write %s", req.Digest)
+	}
+	return stream.SendAndClose(&controlapi.WriteContentResponse{})
+}
+
+// contentReceiver reads the data of the blob from a WriteContent stream
+type contentReceiver struct {
+	stream controlapi.Control_WriteContentServer
+	buf    []byte
+}
+
+func (r *contentReceiver) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		if req.Digest != "" {
+			return 0, errors.Errorf("unexpected digest %s in content data", req.Digest)
+		}
+		r.buf = req.Data
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
@@ -0,0 +1,174 @@
+package control
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/content/local"
+	"github.com/containerd/containerd/leases"
+	ctdmetadata "github.com/containerd/containerd/metadata"
+	"github.com/containerd/containerd/snapshots"
+	"github.com/moby/buildkit/client"
+	containerdsnapshot "github.com/moby/buildkit/snapshot/containerd"
+	"github.com/moby/buildkit/util/leaseutil"
+	"github.com/moby/buildkit/worker"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+)
+
+func TestCopyContent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	src, cleanup := newContentDaemon(t)
+	defer cleanup()
+	dst, cleanup := newContentDaemon(t)
+	defer cleanup()
+
+	blobs := [][]byte{[]byte("foo"), bytes.Repeat([]byte("bar"), contentChunkSize), {}}
+	var digests []digest.Digest
+	for _, dt := range blobs {
+		digests = append(digests, src.writeBlob(t, dt))
+	}
+
+	// concurrent copies of the same blobs don't share ingests
+	eg, egctx := errgroup.WithContext(ctx)
+	for i := 0; i < 3; i++ {
+		eg.Go(func() error {
+			return src.client.CopyContentTo(egctx, dst.client, digests)
+		})
+	}
+	require.NoError(t, eg.Wait())
+
+	for i, dgst := range digests {
+		dt, err := content.ReadBlob(ctx, dst.cs, ocispec.Descriptor{Digest: dgst, Size: int64(len(blobs[i]))})
+		require.NoError(t, err)
+		require.Equal(t, blobs[i], dt)
+	}
+	statuses, err := dst.cs.ListStatuses(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 0)
+
+	// existing blobs are skipped
+	require.NoError(t, src.client.CopyContentTo(ctx, dst.client, digests))
+
+	err = src.client.CopyContentTo(ctx, dst.client, []digest.Digest{digest.FromString("missing")})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not found")
+}
+
+func TestCopyContentDigestMismatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	src, cleanup := newContentDaemon(t)
+	defer cleanup()
+	dst, cleanup := newContentDaemon(t)
+	defer cleanup()
+
+	// the blob is changed in the store of the source daemon
+	dgst := src.writeBlob(t, []byte("foo"))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src.root, "blobs", dgst.Algorithm().String(), dgst.Hex()), []byte("bar"), 0644))
+
+	err := src.client.CopyContentTo(ctx, dst.client, []digest.Digest{dgst})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unexpected commit digest")
+
+	_, err = dst.cs.Info(ctx, dgst)
+	require.Error(t, err)
+	statuses, err := dst.cs.ListStatuses(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 0)
+}
+
+type contentDaemon struct {
+	root   string
+	cs     content.Store
+	lm     leases.Manager
+	client *client.Client
+}
+
+// writeBlob adds dt to the content store of the daemon
+func (d *contentDaemon) writeBlob(t *testing.T, dt []byte) digest.Digest {
+	ctx, done, err := leaseutil.WithLease(context.TODO(), d.lm)
+	require.NoError(t, err)
+	defer done(context.TODO())
+	dgst := digest.FromBytes(dt)
+	require.NoError(t, content.WriteBlob(ctx, d.cs, "test-"+dgst.String(), bytes.NewReader(dt), ocispec.Descriptor{Digest: dgst, Size: int64(len(dt))}))
+	return dgst
+}
+
+// newContentDaemon serves the content RPCs of a controller with a worker
+// that only has a content store
+func newContentDaemon(t *testing.T) (*contentDaemon, func()) {
+	tmpdir, err := ioutil.TempDir("", "buildkit-content")
+	require.NoError(t, err)
+	var defers []func()
+	cleanup := func() {
+		for i := len(defers) - 1; i >= 0; i-- {
+			defers[i]()
+		}
+		os.RemoveAll(tmpdir)
+	}
+
+	store, err := local.NewStore(tmpdir)
+	require.NoError(t, err)
+	db, err := bolt.Open(filepath.Join(tmpdir, "containerdmeta.db"), 0644, nil)
+	require.NoError(t, err)
+	defers = append(defers, func() { db.Close() })
+	mdb := ctdmetadata.NewDB(db, store, map[string]snapshots.Snapshotter{})
+	require.NoError(t, mdb.Init(context.TODO()))
+
+	d := &contentDaemon{
+		root: tmpdir,
+		cs:   containerdsnapshot.NewContentStore(mdb.ContentStore(), "buildkit"),
+		lm:   leaseutil.WithNamespace(ctdmetadata.NewLeaseManager(mdb), "buildkit"),
+	}
+	wc := &worker.Controller{}
+	require.NoError(t, wc.Add(&contentWorker{cs: d.cs, lm: d.lm}))
+	c := &Controller{opt: Opt{WorkerController: wc}}
+
+	server := grpc.NewServer()
+	c.Register(server)
+	sockPath := filepath.Join(tmpdir, "buildkitd.sock")
+	l, err := net.Listen("unix", sockPath)
+	require.NoError(t, err)
+	go server.Serve(l)
+	defers = append(defers, server.Stop)
+
+	d.client, err = client.New(context.TODO(), "unix://"+sockPath)
+	if err != nil {
+		cleanup()
+	}
+	require.NoError(t, err)
+	defers = append(defers, func() { d.client.Close() })
+	return d, cleanup
+}
+
+type contentWorker struct {
+	worker.Worker
+	cs content.Store
+	lm leases.Manager
+}
+
+func (w *contentWorker) ID() string {
+	return "content"
+}
+
+func (w *contentWorker) ContentStore() content.Store {
+	return w.cs
+}
+
+func (w *contentWorker) LeaseManager() leases.Manager {
+	return w.lm
+}
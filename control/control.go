@@ -2,12 +2,18 @@ package control
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/mount"
 	controlapi "github.com/moby/buildkit/api/services/control"
 	apitypes "github.com/moby/buildkit/api/types"
+	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/cache/remotecache"
 	"github.com/moby/buildkit/client"
 	controlgateway "github.com/moby/buildkit/control/gateway"
@@ -17,6 +23,7 @@ import (
 	"github.com/moby/buildkit/session/grpchijack"
 	"github.com/moby/buildkit/solver"
 	"github.com/moby/buildkit/solver/llbsolver"
+	"github.com/moby/buildkit/solver/llbsolver/mounts"
 	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/buildkit/util/imageutil"
 	"github.com/moby/buildkit/util/throttle"
@@ -42,6 +49,27 @@ type Opt struct {
 	ResolveCacheImporterFuncs map[string]remotecache.ResolveCacheImporterFunc
 	Entitlements              []string
 	TraceCollector            sdktrace.SpanExporter
+	// HistoryMaxBuilds is the number of recent builds whose status is kept
+	// for BuildHistory. Zero disables the history.
+	HistoryMaxBuilds int
+	// HistoryMaxEvents limits the status events kept per build. Zero means
+	// no limit.
+	HistoryMaxEvents int
+	// MaxExecParallelism limits the exec operations running at the same time
+	// across all builds. Zero means no limit.
+	MaxExecParallelism int
+	// CriticalPathScheduling makes the exec operations waiting for
+	// MaxExecParallelism start in the order of their critical path.
+	CriticalPathScheduling bool
+	// MaxHashConcurrency limits the hash concurrency that builds can request.
+	// Zero means the number of CPUs.
+	MaxHashConcurrency int
+	// MaxExecRetries limits the number of times an exec is retried with
+	// llb.WithRetry. Zero means 5, a negative value disables retries.
+	MaxExecRetries int
+	// MountCacheRoot is the directory the cache mounts inspected with
+	// MountCache are mounted in.
+	MountCacheRoot string
 }
 
 type Controller struct { // TODO: ControlService
@@ -54,14 +82,34 @@ type Controller struct { // TODO: ControlService
 	gatewayForwarder *controlgateway.GatewayForwarder
 	throttledGC      func()
 	gcmu             sync.Mutex
+	history          *buildHistory
+	events           *eventBus
 }
 
 func NewController(opt Opt) (*Controller, error) {
+	if opt.MountCacheRoot != "" {
+		if err := cleanMountCacheRoot(opt.MountCacheRoot); err != nil {
+			return nil, err
+		}
+	}
+
 	cache := solver.NewCacheManager("local", opt.CacheKeyStorage, worker.NewCacheResultStorage(opt.WorkerController))
 
 	gatewayForwarder := controlgateway.NewGatewayForwarder()
 
-	solver, err := llbsolver.New(opt.WorkerController, opt.Frontends, cache, opt.ResolveCacheImporterFuncs, gatewayForwarder, opt.SessionManager, opt.Entitlements)
+	solver, err := llbsolver.New(llbsolver.Opt{
+		WorkerController:          opt.WorkerController,
+		Frontends:                 opt.Frontends,
+		CacheManager:              cache,
+		ResolveCacheImporterFuncs: opt.ResolveCacheImporterFuncs,
+		GatewayForwarder:          gatewayForwarder,
+		SessionManager:            opt.SessionManager,
+		Entitlements:              opt.Entitlements,
+		MaxExecParallelism:        opt.MaxExecParallelism,
+		CriticalPathScheduling:    opt.CriticalPathScheduling,
+		MaxHashConcurrency:        opt.MaxHashConcurrency,
+		MaxExecRetries:            opt.MaxExecRetries,
+	})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create solver")
 	}
@@ -71,7 +119,12 @@ func NewController(opt Opt) (*Controller, error) {
 		solver:           solver,
 		cache:            cache,
 		gatewayForwarder: gatewayForwarder,
+		history:          newBuildHistory(opt.HistoryMaxBuilds, opt.HistoryMaxEvents),
+		events:           newEventBus(),
 	}
+	opt.WorkerController.OnAdd(func(w worker.Worker) {
+		c.events.publish(client.Event{Type: client.EventWorkerAdded, Worker: w.ID()})
+	})
 	c.throttledGC = throttle.After(time.Minute, c.gc)
 
 	defer func() {
@@ -94,7 +147,7 @@ func (c *Controller) DiskUsage(ctx context.Context, r *controlapi.DiskUsageReque
 	if err != nil {
 		return nil, err
 	}
-	for _, w := range workers {
+	for _, w := range uniqueCacheWorkers(workers) {
 		du, err := w.DiskUsage(ctx, client.DiskUsageInfo{
 			Filter: r.Filter,
 		})
@@ -105,17 +158,18 @@ func (c *Controller) DiskUsage(ctx context.Context, r *controlapi.DiskUsageReque
 		for _, r := range du {
 			resp.Record = append(resp.Record, &controlapi.UsageRecord{
 				// TODO: add worker info
-				ID:          r.ID,
-				Mutable:     r.Mutable,
-				InUse:       r.InUse,
-				Size_:       r.Size,
-				Parent:      r.Parent,
-				UsageCount:  int64(r.UsageCount),
-				Description: r.Description,
-				CreatedAt:   r.CreatedAt,
-				LastUsedAt:  r.LastUsedAt,
-				RecordType:  string(r.RecordType),
-				Shared:      r.Shared,
+				ID:           r.ID,
+				Mutable:      r.Mutable,
+				InUse:        r.InUse,
+				Size_:        r.Size,
+				Parent:       r.Parent,
+				UsageCount:   int64(r.UsageCount),
+				Description:  r.Description,
+				CreatedAt:    r.CreatedAt,
+				LastUsedAt:   r.LastUsedAt,
+				RecordType:   string(r.RecordType),
+				Shared:       r.Shared,
+				CacheMountID: r.CacheMountID,
 			})
 		}
 	}
@@ -130,9 +184,20 @@ func (c *Controller) Prune(req *controlapi.PruneRequest, stream controlapi.Contr
 	ch := make(chan client.UsageInfo)
 
 	eg, ctx := errgroup.WithContext(stream.Context())
-	workers, err := c.opt.WorkerController.List()
-	if err != nil {
-		return errors.Wrap(err, "failed to list workers for prune")
+	var workers []worker.Worker
+	if req.Worker != "" {
+		w, err := c.opt.WorkerController.Get(req.Worker)
+		if err != nil {
+			return err
+		}
+		workers = []worker.Worker{w}
+	} else {
+		var err error
+		workers, err = c.opt.WorkerController.List()
+		if err != nil {
+			return errors.Wrap(err, "failed to list workers for prune")
+		}
+		workers = uniqueCacheWorkers(workers)
 	}
 
 	didPrune := false
@@ -168,22 +233,26 @@ func (c *Controller) Prune(req *controlapi.PruneRequest, stream controlapi.Contr
 		return eg.Wait()
 	})
 
+	var size, records int64
 	eg2.Go(func() error {
 		for r := range ch {
 			didPrune = true
+			size += r.Size
+			records++
 			if err := stream.Send(&controlapi.UsageRecord{
 				// TODO: add worker info
-				ID:          r.ID,
-				Mutable:     r.Mutable,
-				InUse:       r.InUse,
-				Size_:       r.Size,
-				Parent:      r.Parent,
-				UsageCount:  int64(r.UsageCount),
-				Description: r.Description,
-				CreatedAt:   r.CreatedAt,
-				LastUsedAt:  r.LastUsedAt,
-				RecordType:  string(r.RecordType),
-				Shared:      r.Shared,
+				ID:           r.ID,
+				Mutable:      r.Mutable,
+				InUse:        r.InUse,
+				Size_:        r.Size,
+				Parent:       r.Parent,
+				UsageCount:   int64(r.UsageCount),
+				Description:  r.Description,
+				CreatedAt:    r.CreatedAt,
+				LastUsedAt:   r.LastUsedAt,
+				RecordType:   string(r.RecordType),
+				Shared:       r.Shared,
+				CacheMountID: r.CacheMountID,
 			}); err != nil {
 				return err
 			}
@@ -191,7 +260,9 @@ func (c *Controller) Prune(req *controlapi.PruneRequest, stream controlapi.Contr
 		return nil
 	})
 
-	return eg2.Wait()
+	err := eg2.Wait()
+	c.events.publish(client.Event{Type: client.EventPrune, Worker: req.Worker, BytesFreed: size, Records: records})
+	return err
 }
 
 func (c *Controller) Export(ctx context.Context, req *tracev1.ExportTraceServiceRequest) (*tracev1.ExportTraceServiceResponse, error) {
@@ -246,22 +317,27 @@ func (c *Controller) Solve(ctx context.Context, req *controlapi.SolveRequest) (*
 		time.AfterFunc(time.Second, c.throttledGC)
 	}()
 
-	var expi exporter.ExporterInstance
+	var expis []exporter.ExporterInstance
 	// TODO: multiworker
 	// This is actually tricky, as the exporter should come from the worker that has the returned reference. We may need to delay this so that the solver loads this.
 	w, err := c.opt.WorkerController.GetDefault()
 	if err != nil {
 		return nil, err
 	}
-	if req.Exporter != "" {
-		exp, err := w.Exporter(req.Exporter, c.opt.SessionManager)
+	exporters := req.Exporters
+	if len(exporters) == 0 && req.Exporter != "" {
+		exporters = []*controlapi.Exporter{{Type: req.Exporter, Attrs: req.ExporterAttrs}}
+	}
+	for _, ex := range exporters {
+		exp, err := w.Exporter(ex.Type, c.opt.SessionManager)
 		if err != nil {
 			return nil, err
 		}
-		expi, err = exp.Resolve(ctx, req.ExporterAttrs)
+		expi, err := exp.Resolve(ctx, ex.Attrs)
 		if err != nil {
 			return nil, err
 		}
+		expis = append(expis, expi)
 	}
 
 	var (
@@ -293,6 +369,12 @@ func (c *Controller) Solve(ctx context.Context, req *controlapi.SolveRequest) (*
 		})
 	}
 
+	if c.history != nil {
+		go c.history.record(req.Ref, c.solver.Status)
+	}
+
+	c.events.publish(client.Event{Type: client.EventBuildStarted, Ref: req.Ref})
+
 	resp, err := c.solver.Solve(ctx, req.Ref, req.Session, frontend.SolveRequest{
 		Frontend:       req.Frontend,
 		Definition:     req.Definition,
@@ -300,15 +382,45 @@ func (c *Controller) Solve(ctx context.Context, req *controlapi.SolveRequest) (*
 		FrontendInputs: req.FrontendInputs,
 		CacheImports:   cacheImports,
 	}, llbsolver.ExporterRequest{
-		Exporter:        expi,
+		Exporters:       expis,
 		CacheExporter:   cacheExporter,
 		CacheExportMode: cacheExportMode,
-	}, req.Entitlements)
+	}, req.Entitlements, llbsolver.SolveOpt{
+		CacheNamespace:  req.CacheNamespace,
+		CheckpointID:    req.CheckpointID,
+		Deadline:        req.Deadline,
+		HashConcurrency: int(req.HashConcurrency),
+		CacheMountStats: req.CacheMountStats,
+	})
+	finished := client.Event{Type: client.EventBuildFinished, Ref: req.Ref}
+	if err != nil {
+		finished.Error = err.Error()
+	}
+	c.events.publish(finished)
 	if err != nil {
 		return nil, err
 	}
+	sources := make(map[string]string, len(resp.Sources))
+	for k, v := range resp.Sources {
+		sources[k] = v.String()
+	}
+	warnings := make([]*controlapi.Warning, 0, len(resp.Warnings))
+	for _, w := range resp.Warnings {
+		warnings = append(warnings, &controlapi.Warning{
+			Code:    w.Code,
+			Level:   int64(w.Level),
+			Message: w.Message,
+			Detail:  w.Detail,
+			URL:     w.URL,
+			Vertex:  w.Vertex,
+			Info:    w.SourceInfo,
+			Ranges:  w.Ranges,
+		})
+	}
 	return &controlapi.SolveResponse{
 		ExporterResponse: resp.ExporterResponse,
+		Sources:          sources,
+		Warnings:         warnings,
 	}, nil
 }
 
@@ -321,66 +433,150 @@ func (c *Controller) Status(req *controlapi.StatusRequest, stream controlapi.Con
 	})
 
 	eg.Go(func() error {
+		return sendStatus(ch, stream)
+	})
+
+	return eg.Wait()
+}
+
+func (c *Controller) BuildHistory(req *controlapi.BuildHistoryRequest, stream controlapi.Control_BuildHistoryServer) error {
+	if c.history == nil {
+		return status.Errorf(codes.Unavailable, "build history is disabled")
+	}
+	statuses, ok := c.history.get(req.Ref)
+	if !ok {
+		return status.Errorf(codes.NotFound, "no history for build %s", req.Ref)
+	}
+	ch := make(chan *client.SolveStatus, len(statuses))
+	for _, ss := range statuses {
+		ch <- ss
+	}
+	close(ch)
+	return sendStatus(ch, stream)
+}
+
+func (c *Controller) CancelBuild(ctx context.Context, req *controlapi.CancelBuildRequest) (*controlapi.CancelBuildResponse, error) {
+	if req.Ref == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "build ref is required")
+	}
+	if err := c.solver.Cancel(req.Ref); err != nil {
+		return nil, err
+	}
+	return &controlapi.CancelBuildResponse{}, nil
+}
+
+func (c *Controller) SetExecParallelism(ctx context.Context, req *controlapi.SetExecParallelismRequest) (*controlapi.SetExecParallelismResponse, error) {
+	if req.Limit < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid exec parallelism %d", req.Limit)
+	}
+	prev := c.solver.SetMaxExecParallelism(int(req.Limit))
+	return &controlapi.SetExecParallelismResponse{Previous: int64(prev)}, nil
+}
+
+func (c *Controller) ListBuilds(ctx context.Context, req *controlapi.ListBuildsRequest) (*controlapi.ListBuildsResponse, error) {
+	builds := c.solver.ListBuilds()
+	resp := &controlapi.ListBuildsResponse{
+		Builds: make([]*controlapi.BuildInfo, 0, len(builds)),
+	}
+	for _, b := range builds {
+		resp.Builds = append(resp.Builds, &controlapi.BuildInfo{
+			Ref:       b.Ref,
+			StartedAt: b.StartedAt,
+			Frontend:  b.Frontend,
+		})
+	}
+	return resp, nil
+}
+
+func (c *Controller) Events(req *controlapi.EventsRequest, stream controlapi.Control_EventsServer) error {
+	s := c.events.subscribe()
+	defer c.events.unsubscribe(s)
+
+	ctx := stream.Context()
+	for {
+		select {
+		case ev := <-s.ch:
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func sendStatus(ch chan *client.SolveStatus, stream grpc.ServerStream) error {
+	for {
+		ss, ok := <-ch
+		if !ok {
+			return nil
+		}
+		logSize := 0
+		retry := false
 		for {
-			ss, ok := <-ch
-			if !ok {
-				return nil
+			sr := controlapi.StatusResponse{}
+			for _, v := range ss.Vertexes {
+				sr.Vertexes = append(sr.Vertexes, &controlapi.Vertex{
+					Digest:        v.Digest,
+					Inputs:        v.Inputs,
+					Name:          v.Name,
+					Started:       v.Started,
+					Completed:     v.Completed,
+					Error:         v.Error,
+					Cached:        v.Cached,
+					ProgressGroup: v.ProgressGroup,
+					ExitCode:      int32(v.ExitCode),
+					Labels:        v.Labels,
+				})
 			}
-			logSize := 0
-			retry := false
-			for {
-				sr := controlapi.StatusResponse{}
-				for _, v := range ss.Vertexes {
-					sr.Vertexes = append(sr.Vertexes, &controlapi.Vertex{
-						Digest:    v.Digest,
-						Inputs:    v.Inputs,
-						Name:      v.Name,
-						Started:   v.Started,
-						Completed: v.Completed,
-						Error:     v.Error,
-						Cached:    v.Cached,
-					})
-				}
-				for _, v := range ss.Statuses {
-					sr.Statuses = append(sr.Statuses, &controlapi.VertexStatus{
-						ID:        v.ID,
-						Vertex:    v.Vertex,
-						Name:      v.Name,
-						Current:   v.Current,
-						Total:     v.Total,
-						Timestamp: v.Timestamp,
-						Started:   v.Started,
-						Completed: v.Completed,
-					})
-				}
-				for i, v := range ss.Logs {
-					sr.Logs = append(sr.Logs, &controlapi.VertexLog{
-						Vertex:    v.Vertex,
-						Stream:    int64(v.Stream),
-						Msg:       v.Data,
-						Timestamp: v.Timestamp,
-					})
-					logSize += len(v.Data)
-					// avoid logs growing big and split apart if they do
-					if logSize > 1024*1024 {
-						ss.Vertexes = nil
-						ss.Statuses = nil
-						ss.Logs = ss.Logs[i+1:]
-						retry = true
-						break
-					}
-				}
-				if err := stream.SendMsg(&sr); err != nil {
-					return err
-				}
-				if !retry {
+			for _, v := range ss.Statuses {
+				sr.Statuses = append(sr.Statuses, &controlapi.VertexStatus{
+					ID:        v.ID,
+					Vertex:    v.Vertex,
+					Name:      v.Name,
+					Current:   v.Current,
+					Total:     v.Total,
+					Timestamp: v.Timestamp,
+					Started:   v.Started,
+					Completed: v.Completed,
+				})
+			}
+			for _, v := range ss.CacheMounts {
+				sr.CacheMounts = append(sr.CacheMounts, &controlapi.CacheMountStats{
+					Vertex:         v.Vertex,
+					ID:             v.ID,
+					Target:         v.Target,
+					UnchangedBytes: v.UnchangedBytes,
+					WrittenBytes:   v.WrittenBytes,
+					Timestamp:      v.Timestamp,
+				})
+			}
+			for i, v := range ss.Logs {
+				sr.Logs = append(sr.Logs, &controlapi.VertexLog{
+					Vertex:    v.Vertex,
+					Stream:    int64(v.Stream),
+					Msg:       v.Data,
+					Timestamp: v.Timestamp,
+				})
+				logSize += len(v.Data)
+				// avoid logs growing big and split apart if they do
+				if logSize > 1024*1024 {
+					ss.Vertexes = nil
+					ss.Statuses = nil
+					ss.CacheMounts = nil
+					ss.Logs = ss.Logs[i+1:]
+					retry = true
 					break
 				}
 			}
+			if err := stream.SendMsg(&sr); err != nil {
+				return err
+			}
+			if !retry {
+				break
+			}
 		}
-	})
-
-	return eg.Wait()
+	}
 }
 
 func (c *Controller) Session(stream controlapi.Control_SessionServer) error {
@@ -416,6 +612,106 @@ func (c *Controller) ListWorkers(ctx context.Context, r *controlapi.ListWorkersR
 	return resp, nil
 }
 
+func (c *Controller) MountCache(req *controlapi.MountCacheRequest, stream controlapi.Control_MountCacheServer) error {
+	if req.ID == "" {
+		return errors.New("cache mount id is required")
+	}
+	if c.opt.MountCacheRoot == "" {
+		return errors.New("mounting cache mounts is not supported by the daemon")
+	}
+	ctx := stream.Context()
+
+	workers, err := c.opt.WorkerController.List()
+	if err != nil {
+		return err
+	}
+	var ref cache.MutableRef
+	for _, w := range workers {
+		ref, err = mounts.GetCacheMountRef(ctx, w.CacheManager(), w.MetadataStore(), req.ID)
+		if err == nil {
+			break
+		}
+		if !errdefs.IsNotFound(err) {
+			return err
+		}
+	}
+	if ref == nil {
+		return err
+	}
+	defer ref.Release(context.TODO())
+
+	mountable, err := ref.Mount(ctx, true, nil)
+	if err != nil {
+		return err
+	}
+	mnts, release, err := mountable.Mount()
+	if err != nil {
+		return err
+	}
+	if release != nil {
+		defer release()
+	}
+	target, err := ioutil.TempDir(c.opt.MountCacheRoot, "cache-")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer os.Remove(target)
+	if err := mount.All(mnts, target); err != nil {
+		return errors.Wrapf(err, "failed to mount cache %s", req.ID)
+	}
+	defer func() {
+		if err := mount.UnmountAll(target, 0); err != nil {
+			logrus.Errorf("failed to unmount cache %s from %s: %v", req.ID, target, err)
+		}
+	}()
+
+	if err := stream.Send(&controlapi.MountCacheResponse{Ref: ref.ID(), Path: target}); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return nil
+}
+
+// cleanMountCacheRoot unmounts and removes the cache mounts left behind in
+// root by a previous daemon
+func cleanMountCacheRoot(root string) error {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return errors.WithStack(err)
+	}
+	fis, err := ioutil.ReadDir(root)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, fi := range fis {
+		p := filepath.Join(root, fi.Name())
+		if err := mount.UnmountAll(p, 0); err != nil {
+			return errors.Wrapf(err, "failed to unmount %s", p)
+		}
+		// never remove recursively as the cache could still be mounted
+		if err := os.Remove(p); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// uniqueCacheWorkers returns the first of the workers sharing a cache
+// manager, e.g. a local worker and the remote workers wrapping it, so that
+// the records of the cache are only reported and pruned once
+func uniqueCacheWorkers(workers []worker.Worker) []worker.Worker {
+	var out []worker.Worker
+	seen := map[cache.Manager]struct{}{}
+	for _, w := range workers {
+		cm := w.CacheManager()
+		if _, ok := seen[cm]; ok {
+			continue
+		}
+		seen[cm] = struct{}{}
+		out = append(out, w)
+	}
+	return out
+}
+
 func (c *Controller) gc() {
 	c.gcmu.Lock()
 	defer c.gcmu.Unlock()
@@ -424,15 +720,17 @@ func (c *Controller) gc() {
 	if err != nil {
 		return
 	}
+	workers = uniqueCacheWorkers(workers)
 
 	eg, ctx := errgroup.WithContext(context.TODO())
 
-	var size int64
+	var size, records int64
 	ch := make(chan client.UsageInfo)
 	done := make(chan struct{})
 	go func() {
 		for ui := range ch {
 			size += ui.Size
+			records++
 		}
 		close(done)
 	}()
@@ -457,6 +755,9 @@ func (c *Controller) gc() {
 	if size > 0 {
 		logrus.Debugf("gc cleaned up %d bytes", size)
 	}
+	if records > 0 {
+		c.events.publish(client.Event{Type: client.EventGC, BytesFreed: size, Records: records})
+	}
 }
 
 func parseCacheExportMode(mode string) solver.CacheExportMode {
@@ -0,0 +1,69 @@
+package control
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/worker"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanMountCacheRoot(t *testing.T) {
+	t.Parallel()
+
+	tmpdir, err := ioutil.TempDir("", "buildkit-mountcache")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	root := filepath.Join(tmpdir, "cache-mounts")
+	require.NoError(t, cleanMountCacheRoot(root))
+
+	require.NoError(t, os.Mkdir(filepath.Join(root, "cache-stale"), 0700))
+	require.NoError(t, cleanMountCacheRoot(root))
+	fis, err := ioutil.ReadDir(root)
+	require.NoError(t, err)
+	require.Len(t, fis, 0)
+
+	// the contents of a directory that is still mounted are never removed
+	require.NoError(t, os.Mkdir(filepath.Join(root, "cache-busy"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "cache-busy", "data"), nil, 0644))
+	require.Error(t, cleanMountCacheRoot(root))
+	_, err = os.Stat(filepath.Join(root, "cache-busy", "data"))
+	require.NoError(t, err)
+}
+
+func TestUniqueCacheWorkers(t *testing.T) {
+	t.Parallel()
+
+	cm1 := &testCacheManager{id: "cm1"}
+	cm2 := &testCacheManager{id: "cm2"}
+	local := &testCacheWorker{id: "local", cm: cm1}
+	remote := &testCacheWorker{id: "remote", cm: cm1}
+	other := &testCacheWorker{id: "other", cm: cm2}
+
+	workers := uniqueCacheWorkers([]worker.Worker{local, remote, other})
+	require.Equal(t, []worker.Worker{local, other}, workers)
+	require.Len(t, uniqueCacheWorkers(nil), 0)
+}
+
+type testCacheManager struct {
+	cache.Manager
+	id string
+}
+
+type testCacheWorker struct {
+	worker.Worker
+	id string
+	cm cache.Manager
+}
+
+func (w *testCacheWorker) ID() string {
+	return w.id
+}
+
+func (w *testCacheWorker) CacheManager() cache.Manager {
+	return w.cm
+}
@@ -0,0 +1,248 @@
+package control
+
+import (
+	"context"
+	"strings"
+
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/platforms"
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/client/llb"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/source"
+	"github.com/moby/buildkit/util/imageutil"
+	"github.com/moby/buildkit/worker"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// EstimateBuildSize estimates the disk space needed to build a definition
+// without running it. All the sizes are approximate. Vertexes with a result
+// in the cache that is still linked to a cache key are expected to be cache
+// hits and don't count, assuming their sources resolve to the same content as
+// in the previous build. The sizes of image sources are the compressed sizes
+// of their layers in the manifests. The sizes of the other vertexes are
+// estimated from the records of previous builds of the same vertex that are
+// still in the cache, or from the sizes of the inputs of file copies.
+func (c *Controller) EstimateBuildSize(ctx context.Context, req *controlapi.EstimateBuildSizeRequest) (*controlapi.EstimateBuildSizeResponse, error) {
+	resp := &controlapi.EstimateBuildSizeResponse{}
+	if req.Definition == nil || len(req.Definition.Def) == 0 {
+		return resp, nil
+	}
+
+	w, err := c.opt.WorkerController.GetDefault()
+	if err != nil {
+		return nil, err
+	}
+	records, err := w.DiskUsage(ctx, client.DiskUsageInfo{})
+	if err != nil {
+		return nil, err
+	}
+
+	e := &sizeEstimator{
+		c:      c,
+		w:      w,
+		sizes:  map[digest.Digest]int64{},
+		layers: map[digest.Digest]struct{}{},
+	}
+	e.history = e.vertexHistory(ctx, records)
+
+	for _, dt := range req.Definition.Def {
+		var op pb.Op
+		if err := (&op).Unmarshal(dt); err != nil {
+			return nil, errors.Wrap(err, "failed to parse llb proto op")
+		}
+		if op.Op == nil {
+			// terminal vertex
+			continue
+		}
+		dgst := digest.FromBytes(dt)
+		est, err := e.estimate(ctx, dgst, &op)
+		if err != nil {
+			return nil, err
+		}
+		est.Vertex = dgst
+		if md, ok := req.Definition.Metadata[dgst]; ok {
+			if name, ok := md.Description["llb.customname"]; ok {
+				est.Name = name
+			}
+		}
+		resp.Vertexes = append(resp.Vertexes, est)
+	}
+	return resp, nil
+}
+
+type sizeEstimator struct {
+	c       *Controller
+	w       worker.Worker
+	history map[digest.Digest]*vertexHistory
+	// sizes are the output sizes of the vertexes, including the cache hits
+	sizes  map[digest.Digest]int64
+	layers map[digest.Digest]struct{}
+}
+
+// vertexHistory is the cache records of previous builds of a vertex
+type vertexHistory struct {
+	// sizes is the size of the largest record of each output of the vertex by
+	// the description of the record
+	sizes map[string]int64
+	// cached is set if one of the records is the result of a cache key
+	cached bool
+}
+
+// size returns the total size of the outputs, zero for vertexes without
+// records
+func (h *vertexHistory) size() int64 {
+	if h == nil {
+		return 0
+	}
+	var size int64
+	for _, s := range h.sizes {
+		size += s
+	}
+	return size
+}
+
+// vertexHistory groups the immutable records by the vertex that created them
+func (e *sizeEstimator) vertexHistory(ctx context.Context, records []*client.UsageInfo) map[digest.Digest]*vertexHistory {
+	m := map[digest.Digest]*vertexHistory{}
+	for _, r := range records {
+		if r.Mutable {
+			continue
+		}
+		ref, err := e.w.CacheManager().Get(ctx, r.ID, cache.NoUpdateLastUsed)
+		if err != nil {
+			continue
+		}
+		vtx := cache.GetVertex(ref)
+		ref.Release(context.TODO())
+		if vtx == "" {
+			continue
+		}
+		h, ok := m[vtx]
+		if !ok {
+			h = &vertexHistory{sizes: map[string]int64{}}
+			m[vtx] = h
+		}
+		if r.Size > h.sizes[r.Description] {
+			h.sizes[r.Description] = r.Size
+		}
+		if !h.cached && e.hasCacheKey(r.ID) {
+			h.cached = true
+		}
+	}
+	return m
+}
+
+// hasCacheKey returns true if the record with the ID is the result of a
+// cache key that the solver can match
+func (e *sizeEstimator) hasCacheKey(id string) bool {
+	if e.c.opt.CacheKeyStorage == nil {
+		return false
+	}
+	errFound := errors.New("found")
+	err := e.c.opt.CacheKeyStorage.WalkIDsByResult(id, func(string) error {
+		return errFound
+	})
+	return err == errFound
+}
+
+func (e *sizeEstimator) estimate(ctx context.Context, dgst digest.Digest, op *pb.Op) (*controlapi.VertexSizeEstimate, error) {
+	est := &controlapi.VertexSizeEstimate{Name: vertexName(op)}
+	h := e.history[dgst]
+	if h != nil && h.cached {
+		est.Cached = true
+		e.sizes[dgst] = h.size()
+		return est, nil
+	}
+	switch o := op.Op.(type) {
+	case *pb.Op_Source:
+		id, err := source.FromLLB(o, op.Platform)
+		if err != nil {
+			return nil, err
+		}
+		if id, ok := id.(*source.ImageIdentifier); ok {
+			size, err := e.imageSize(ctx, id)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to resolve size of %s", est.Name)
+			}
+			est.Size_ = size
+			est.FromManifest = true
+		} else {
+			est.Size_ = h.size()
+		}
+	case *pb.Op_Exec:
+		est.Size_ = h.size()
+	case *pb.Op_File:
+		for _, a := range o.File.Actions {
+			switch action := a.Action.(type) {
+			case *pb.FileAction_Mkfile:
+				est.Size_ += int64(len(action.Mkfile.Data))
+			case *pb.FileAction_Copy:
+				if idx := int(a.SecondaryInput); idx >= 0 && idx < len(op.Inputs) {
+					est.Size_ += e.sizes[op.Inputs[idx].Digest]
+				}
+			}
+		}
+	}
+	e.sizes[dgst] = est.Size_
+	return est, nil
+}
+
+func vertexName(op *pb.Op) string {
+	switch o := op.Op.(type) {
+	case *pb.Op_Source:
+		return o.Source.Identifier
+	case *pb.Op_Exec:
+		return strings.Join(o.Exec.Meta.ProcessArgs(), " ")
+	}
+	return ""
+}
+
+// imageSize returns the size of the layers of an image that were not already
+// counted for another image source of the definition
+func (e *sizeEstimator) imageSize(ctx context.Context, id *source.ImageIdentifier) (int64, error) {
+	dgst, _, err := e.w.ResolveImageConfig(ctx, id.Reference.String(), llb.ResolveImageConfigOpt{
+		Platform:    id.Platform,
+		ResolveMode: id.ResolveMode.String(),
+	}, e.c.opt.SessionManager, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	// the manifest was stored in the content store when resolving the config
+	cs := e.w.ContentStore()
+	desc := ocispec.Descriptor{Digest: dgst}
+	ra, err := cs.ReaderAt(ctx, desc)
+	if err != nil {
+		return 0, err
+	}
+	desc.Size = ra.Size()
+	desc.MediaType, err = imageutil.DetectManifestMediaType(ra)
+	ra.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	platform := platforms.Default()
+	if id.Platform != nil {
+		platform = platforms.Only(*id.Platform)
+	}
+	mfst, err := images.Manifest(ctx, cs, desc, platform)
+	if err != nil {
+		return 0, err
+	}
+
+	var size int64
+	for _, l := range mfst.Layers {
+		if _, ok := e.layers[l.Digest]; ok {
+			continue
+		}
+		e.layers[l.Digest] = struct{}{}
+		size += l.Size
+	}
+	return size, nil
+}
@@ -0,0 +1,65 @@
+package control
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateCacheHits(t *testing.T) {
+	t.Parallel()
+
+	storage := solver.NewInMemoryCacheStorage()
+	require.NoError(t, storage.AddResult("key1", solver.CacheResult{ID: "ref1", CreatedAt: time.Now()}))
+	e := &sizeEstimator{
+		c:      &Controller{opt: Opt{CacheKeyStorage: storage}},
+		sizes:  map[digest.Digest]int64{},
+		layers: map[digest.Digest]struct{}{},
+	}
+	require.True(t, e.hasCacheKey("ref1"))
+	require.False(t, e.hasCacheKey("ref2"))
+
+	cached := digest.FromString("cached")
+	uncached := digest.FromString("uncached")
+	e.history = map[digest.Digest]*vertexHistory{
+		cached: {sizes: map[string]int64{"mount / from exec foo": 100}, cached: true},
+		// the records of a vertex without cache keys, e.g. of a build with
+		// other sources, estimate the size of each output
+		uncached: {sizes: map[string]int64{"mount / from exec bar": 10, "mount /out from exec bar": 20}},
+	}
+	exec := &pb.Op{Op: &pb.Op_Exec{Exec: &pb.ExecOp{Meta: &pb.Meta{Args: []string{"foo"}}}}}
+
+	est, err := e.estimate(context.TODO(), cached, exec)
+	require.NoError(t, err)
+	require.True(t, est.Cached)
+	require.Equal(t, "foo", est.Name)
+	require.Equal(t, int64(0), est.Size_)
+
+	est, err = e.estimate(context.TODO(), uncached, exec)
+	require.NoError(t, err)
+	require.False(t, est.Cached)
+	require.Equal(t, int64(30), est.Size_)
+
+	est, err = e.estimate(context.TODO(), digest.FromString("new"), exec)
+	require.NoError(t, err)
+	require.False(t, est.Cached)
+	require.Equal(t, int64(0), est.Size_)
+
+	// copies count the size of cached inputs
+	cp := &pb.Op{
+		Inputs: []*pb.Input{{Digest: cached}},
+		Op: &pb.Op_File{File: &pb.FileOp{Actions: []*pb.FileAction{{
+			Input:          -1,
+			SecondaryInput: 0,
+			Action:         &pb.FileAction_Copy{Copy: &pb.FileActionCopy{Src: "/", Dest: "/"}},
+		}}}},
+	}
+	est, err = e.estimate(context.TODO(), digest.FromString("copy"), cp)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), est.Size_)
+}
@@ -0,0 +1,71 @@
+package control
+
+import (
+	"sync"
+	"time"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/moby/buildkit/client"
+)
+
+// eventsBuffer is the number of events buffered per subscriber before events
+// are dropped
+const eventsBuffer = 64
+
+// eventBus sends the events of the daemon to the subscribers of the Events
+// API. Publishing never blocks: events are dropped for subscribers that don't
+// keep up and the number of dropped events is reported with the next event
+// that is sent to them.
+type eventBus struct {
+	mu   sync.Mutex
+	subs map[*eventSubscriber]struct{}
+}
+
+type eventSubscriber struct {
+	ch      chan *controlapi.Event
+	dropped int64
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{subs: map[*eventSubscriber]struct{}{}}
+}
+
+func (b *eventBus) subscribe() *eventSubscriber {
+	s := &eventSubscriber{ch: make(chan *controlapi.Event, eventsBuffer)}
+	b.mu.Lock()
+	b.subs[s] = struct{}{}
+	b.mu.Unlock()
+	return s
+}
+
+func (b *eventBus) unsubscribe(s *eventSubscriber) {
+	b.mu.Lock()
+	delete(b.subs, s)
+	b.mu.Unlock()
+}
+
+func (b *eventBus) publish(ev client.Event) {
+	if ev.Timestamp.IsZero() {
+		ev.Timestamp = time.Now()
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for s := range b.subs {
+		e := &controlapi.Event{
+			Type:       string(ev.Type),
+			Timestamp:  ev.Timestamp,
+			Ref:        ev.Ref,
+			Error:      ev.Error,
+			Worker:     ev.Worker,
+			BytesFreed: ev.BytesFreed,
+			Records:    ev.Records,
+			Dropped:    s.dropped,
+		}
+		select {
+		case s.ch <- e:
+			s.dropped = 0
+		default:
+			s.dropped++
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package control
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBus(t *testing.T) {
+	t.Parallel()
+
+	b := newEventBus()
+	b.publish(client.Event{Type: client.EventGC})
+
+	s := b.subscribe()
+	for i := 0; i < eventsBuffer+3; i++ {
+		b.publish(client.Event{Type: client.EventBuildStarted, Ref: "a"})
+	}
+	require.Equal(t, eventsBuffer, len(s.ch))
+
+	for i := 0; i < eventsBuffer; i++ {
+		ev := <-s.ch
+		require.Equal(t, string(client.EventBuildStarted), ev.Type)
+		require.Equal(t, int64(0), ev.Dropped)
+		require.False(t, ev.Timestamp.IsZero())
+	}
+
+	b.publish(client.Event{Type: client.EventBuildFinished, Ref: "a"})
+	ev := <-s.ch
+	require.Equal(t, string(client.EventBuildFinished), ev.Type)
+	require.Equal(t, int64(3), ev.Dropped)
+
+	b.unsubscribe(s)
+	b.publish(client.Event{Type: client.EventGC})
+	require.Equal(t, 0, len(s.ch))
+}
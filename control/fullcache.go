@@ -0,0 +1,425 @@
+package control
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/util/compression"
+	"github.com/moby/buildkit/util/leaseutil"
+	"github.com/moby/buildkit/worker"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// fullCacheIndexName is the name of the first entry of a full cache archive.
+// It is followed by the blobs of the results, in the order of the results of
+// the index. Blobs shared by several results are only stored once.
+const fullCacheIndexName = "index.json"
+
+const (
+	annotationDescription = "buildkit/description"
+	annotationCreatedAt   = "buildkit/createdat"
+)
+
+type fullCacheIndex struct {
+	Keys    []fullCacheKey    `json:"keys"`
+	Links   []fullCacheLink   `json:"links,omitempty"`
+	Results []fullCacheResult `json:"results"`
+}
+
+type fullCacheKey struct {
+	ID string `json:"id"`
+	// Results refer to the IDs of the results of the index
+	Results []solver.CacheResult `json:"results,omitempty"`
+}
+
+type fullCacheLink struct {
+	Source string               `json:"source"`
+	Link   solver.CacheInfoLink `json:"link"`
+	Target string               `json:"target"`
+}
+
+type fullCacheResult struct {
+	ID string `json:"id"`
+	// Layers are the blobs of the result, from the base layer up. Results
+	// without layers are empty.
+	Layers []ocispec.Descriptor `json:"layers,omitempty"`
+}
+
+// ExportFullCache streams an archive of the cache keys of the solver and the
+// results of the default worker they refer to. Every result is exported with
+// the blobs of its layers, blobs are created for results that don't have any.
+// Results whose blobs are not in the content store, e.g. lazily pulled image
+// layers, are skipped. Cache mounts and local sources are not exported.
+func (c *Controller) ExportFullCache(req *controlapi.ExportFullCacheRequest, stream controlapi.Control_ExportFullCacheServer) error {
+	ctx := stream.Context()
+	w, err := c.opt.WorkerController.GetDefault()
+	if err != nil {
+		return err
+	}
+	cs := w.ContentStore()
+
+	keys, links, err := exportCacheKeys(c.opt.CacheKeyStorage)
+	if err != nil {
+		return err
+	}
+
+	// the refs of the exported results are held until the blobs are written
+	// so that they can't be pruned
+	var refs []cache.ImmutableRef
+	defer func() {
+		for _, ref := range refs {
+			ref.Release(context.TODO())
+		}
+	}()
+
+	idx := &fullCacheIndex{Links: links}
+	exported := map[string]bool{}
+	for _, k := range keys {
+		var results []solver.CacheResult
+		for _, res := range k.Results {
+			ok, found := exported[res.ID]
+			if !found {
+				layers, ref, err := exportCacheResult(ctx, w, res.ID)
+				if err != nil {
+					logrus.Warnf("skipping cache result %s: %v", res.ID, err)
+				}
+				if ref != nil {
+					refs = append(refs, ref)
+				}
+				ok = err == nil
+				exported[res.ID] = ok
+				if ok {
+					idx.Results = append(idx.Results, fullCacheResult{ID: res.ID, Layers: layers})
+				}
+			}
+			if ok {
+				results = append(results, res)
+			}
+		}
+		idx.Keys = append(idx.Keys, fullCacheKey{ID: k.ID, Results: results})
+	}
+
+	dt, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriterSize(&bytesMessageWriter{stream: stream}, contentChunkSize)
+	tw := tar.NewWriter(bw)
+	now := time.Now()
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    fullCacheIndexName,
+		Mode:    0644,
+		Size:    int64(len(dt)),
+		ModTime: now,
+	}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(dt); err != nil {
+		return err
+	}
+
+	written := map[digest.Digest]struct{}{}
+	for _, res := range idx.Results {
+		for _, desc := range res.Layers {
+			if _, ok := written[desc.Digest]; ok {
+				continue
+			}
+			written[desc.Digest] = struct{}{}
+			if err := tw.WriteHeader(&tar.Header{
+				Name:    blobPath(desc.Digest),
+				Mode:    0444,
+				Size:    desc.Size,
+				ModTime: now,
+			}); err != nil {
+				return err
+			}
+			ra, err := cs.ReaderAt(ctx, desc)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read %s", desc.Digest)
+			}
+			_, err = io.Copy(tw, content.NewReader(ra))
+			ra.Close()
+			if err != nil {
+				return errors.Wrapf(err, "failed to write %s", desc.Digest)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// exportCacheResult returns the layers of the cache result with the given ID
+// and the ref of the result, if it has one. The ref is also returned together
+// with an error.
+func exportCacheResult(ctx context.Context, w worker.Worker, id string) ([]ocispec.Descriptor, cache.ImmutableRef, error) {
+	workerID, refID, err := parseResultID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if workerID != w.ID() {
+		return nil, nil, errors.Errorf("result of worker %s is not exported", workerID)
+	}
+	if refID == "" {
+		return nil, nil, nil
+	}
+	ref, err := w.LoadRef(ctx, refID, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	remote, err := (&worker.WorkerRef{ImmutableRef: ref, Worker: w}).GetRemote(ctx, true, compression.Default, false, nil)
+	if err != nil {
+		return nil, ref, err
+	}
+
+	var chain []cache.ImmutableRef
+	for r := ref; r != nil; r = r.Parent() {
+		chain = append([]cache.ImmutableRef{r}, chain...)
+	}
+	defer func() {
+		for _, r := range chain[:len(chain)-1] {
+			r.Release(context.TODO())
+		}
+	}()
+	if len(chain) != len(remote.Descriptors) {
+		return nil, ref, errors.Errorf("mismatched layers of %s", refID)
+	}
+
+	layers := make([]ocispec.Descriptor, len(remote.Descriptors))
+	for i, desc := range remote.Descriptors {
+		if _, err := w.ContentStore().Info(ctx, desc.Digest); err != nil {
+			return nil, ref, errors.Wrapf(err, "blob %s is not available", desc.Digest)
+		}
+		annotations := map[string]string{}
+		for k, v := range desc.Annotations {
+			annotations[k] = v
+		}
+		md := chain[i].Metadata()
+		if descr := cache.GetDescription(md); descr != "" {
+			annotations[annotationDescription] = descr
+		}
+		if tm := cache.GetCreatedAt(md); !tm.IsZero() {
+			if dt, err := tm.MarshalText(); err == nil {
+				annotations[annotationCreatedAt] = string(dt)
+			}
+		}
+		desc.Annotations = annotations
+		layers[i] = desc
+	}
+	return layers, ref, nil
+}
+
+// ImportFullCache reads an archive created by ExportFullCache. The results
+// of the archive are added to the default worker and the cache keys referring
+// to them are added to the cache key storage of the solver. Existing cache
+// keys and results are kept.
+func (c *Controller) ImportFullCache(stream controlapi.Control_ImportFullCacheServer) error {
+	ctx := stream.Context()
+	w, err := c.opt.WorkerController.GetDefault()
+	if err != nil {
+		return err
+	}
+	cs := w.ContentStore()
+
+	tr := tar.NewReader(&bytesMessageReader{stream: stream})
+	hdr, err := tr.Next()
+	if err != nil {
+		return errors.Wrap(err, "failed to read full cache archive")
+	}
+	if hdr.Name != fullCacheIndexName {
+		return errors.Errorf("invalid full cache archive, expected %s, got %s", fullCacheIndexName, hdr.Name)
+	}
+	var idx fullCacheIndex
+	if err := json.NewDecoder(tr).Decode(&idx); err != nil {
+		return errors.Wrap(err, "failed to parse full cache index")
+	}
+
+	// the blobs are held by a lease until the refs of the results hold them.
+	// The refs are only created after all the blobs were written, so that a
+	// truncated archive doesn't leave partial results.
+	ctx, done, err := leaseutil.WithLease(ctx, w.LeaseManager(), leaseutil.MakeTemporary)
+	if err != nil {
+		return err
+	}
+	defer done(context.TODO())
+
+	read := map[digest.Digest]struct{}{}
+	for _, res := range idx.Results {
+		for _, desc := range res.Layers {
+			if _, ok := read[desc.Digest]; ok {
+				continue
+			}
+			read[desc.Digest] = struct{}{}
+			hdr, err := tr.Next()
+			if err != nil {
+				return errors.Wrapf(err, "failed to read blob %s", desc.Digest)
+			}
+			if hdr.Name != blobPath(desc.Digest) {
+				return errors.Errorf("invalid full cache archive, expected %s, got %s", blobPath(desc.Digest), hdr.Name)
+			}
+			// WriteBlob verifies the size and digest of the blob and skips
+			// blobs that already exist
+			if err := content.WriteBlob(ctx, cs, "import-"+desc.Digest.String(), tr, ocispec.Descriptor{Digest: desc.Digest, Size: hdr.Size}); err != nil {
+				return errors.Wrapf(err, "failed to write %s", desc.Digest)
+			}
+		}
+	}
+
+	refs := make([]cache.ImmutableRef, len(idx.Results))
+	defer func() {
+		for _, ref := range refs {
+			if ref != nil {
+				ref.Release(context.TODO())
+			}
+		}
+	}()
+	for i, res := range idx.Results {
+		if len(res.Layers) == 0 {
+			continue
+		}
+		ref, err := w.FromRemote(ctx, &solver.Remote{
+			Descriptors: res.Layers,
+			Provider:    cs,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to import result %s", res.ID)
+		}
+		refs[i] = ref
+	}
+
+	storage := worker.NewCacheResultStorage(c.opt.WorkerController)
+	resultIDs := map[string]string{}
+	for i, res := range idx.Results {
+		if refs[i] == nil {
+			resultIDs[res.ID] = w.ID() + "::"
+			continue
+		}
+		cr, err := storage.Save(worker.NewWorkerRefResult(refs[i], w), time.Now())
+		if err != nil {
+			return err
+		}
+		resultIDs[res.ID] = cr.ID
+	}
+
+	if err := importCacheKeys(c.opt.CacheKeyStorage, idx.Keys, idx.Links, resultIDs); err != nil {
+		return err
+	}
+	return stream.SendAndClose(&controlapi.ImportFullCacheResponse{
+		Keys:    int64(len(idx.Keys)),
+		Results: int64(len(resultIDs)),
+	})
+}
+
+// linkWalker is implemented by the cache key storages of the solver. Unlike
+// WalkBacklinks, it returns the links the way they were added.
+type linkWalker interface {
+	WalkAllLinks(id string, fn func(link solver.CacheInfoLink, target string) error) error
+}
+
+// exportCacheKeys returns all cache keys of the storage with their results and
+// the links between them
+func exportCacheKeys(s solver.CacheKeyStorage) ([]fullCacheKey, []fullCacheLink, error) {
+	lw, ok := s.(linkWalker)
+	if !ok {
+		return nil, nil, errors.Errorf("cache key storage %T does not support walking links", s)
+	}
+	var keys []fullCacheKey
+	var links []fullCacheLink
+	err := s.Walk(func(id string) error {
+		k := fullCacheKey{ID: id}
+		if err := s.WalkResults(id, func(res solver.CacheResult) error {
+			k.Results = append(k.Results, res)
+			return nil
+		}); err != nil {
+			return err
+		}
+		keys = append(keys, k)
+		return lw.WalkAllLinks(id, func(link solver.CacheInfoLink, target string) error {
+			links = append(links, fullCacheLink{Source: id, Link: link, Target: target})
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to walk cache keys")
+	}
+	return keys, links, nil
+}
+
+// importCacheKeys adds the cache keys and links to the storage. The IDs of the
+// results are replaced with the IDs in resultIDs, results missing from it are
+// skipped.
+func importCacheKeys(s solver.CacheKeyStorage, keys []fullCacheKey, links []fullCacheLink, resultIDs map[string]string) error {
+	for _, k := range keys {
+		for _, res := range k.Results {
+			id, ok := resultIDs[res.ID]
+			if !ok {
+				continue
+			}
+			if err := s.AddResult(k.ID, solver.CacheResult{ID: id, CreatedAt: res.CreatedAt}); err != nil {
+				return errors.Wrapf(err, "failed to add result of %s", k.ID)
+			}
+		}
+	}
+	for _, l := range links {
+		if err := s.AddLink(l.Source, l.Link, l.Target); err != nil {
+			return errors.Wrapf(err, "failed to add link of %s", l.Source)
+		}
+	}
+	return nil
+}
+
+func parseResultID(id string) (string, string, error) {
+	parts := strings.Split(id, "::")
+	if len(parts) != 2 {
+		return "", "", errors.Errorf("invalid result id %s", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+func blobPath(dgst digest.Digest) string {
+	return path.Join("blobs", dgst.Algorithm().String(), dgst.Encoded())
+}
+
+type bytesMessageWriter struct {
+	stream controlapi.Control_ExportFullCacheServer
+}
+
+func (w *bytesMessageWriter) Write(dt []byte) (int, error) {
+	if err := w.stream.Send(&controlapi.BytesMessage{Data: dt}); err != nil {
+		return 0, err
+	}
+	return len(dt), nil
+}
+
+type bytesMessageReader struct {
+	stream controlapi.Control_ImportFullCacheServer
+	buf    []byte
+}
+
+func (r *bytesMessageReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		msg, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = msg.Data
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
@@ -0,0 +1,39 @@
+package control
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/solver"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportCacheKeys(t *testing.T) {
+	t.Parallel()
+
+	src := solver.NewInMemoryCacheStorage()
+	tm := time.Now().UTC()
+	require.NoError(t, src.AddResult("foo", solver.CacheResult{ID: "w1::ref1", CreatedAt: tm}))
+	require.NoError(t, src.AddResult("bar", solver.CacheResult{ID: "w1::ref2", CreatedAt: tm}))
+	link := solver.CacheInfoLink{Input: 0, Digest: digest.FromString("op")}
+	require.NoError(t, src.AddLink("foo", link, "bar"))
+
+	keys, links, err := exportCacheKeys(src)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(keys))
+	require.Equal(t, []fullCacheLink{{Source: "foo", Link: link, Target: "bar"}}, links)
+
+	dst := solver.NewInMemoryCacheStorage()
+	err = importCacheKeys(dst, keys, links, map[string]string{"w1::ref1": "w2::new1"})
+	require.NoError(t, err)
+
+	res, err := dst.Load("foo", "w2::new1")
+	require.NoError(t, err)
+	require.Equal(t, tm, res.CreatedAt)
+
+	// results that were not imported are skipped
+	_, err = dst.Load("bar", "w1::ref2")
+	require.Error(t, err)
+	require.True(t, dst.HasLink("foo", link, "bar"))
+}
@@ -153,6 +153,14 @@ func (gwf *GatewayForwarder) StatFile(ctx context.Context, req *gwapi.StatFileRe
 	return fwd.StatFile(ctx, req)
 }
 
+func (gwf *GatewayForwarder) Glob(ctx context.Context, req *gwapi.GlobRequest) (*gwapi.GlobResponse, error) {
+	fwd, err := gwf.lookupForwarder(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "forwarding Glob")
+	}
+	return fwd.Glob(ctx, req)
+}
+
 func (gwf *GatewayForwarder) NewContainer(ctx context.Context, req *gwapi.NewContainerRequest) (*gwapi.NewContainerResponse, error) {
 	fwd, err := gwf.lookupForwarder(ctx)
 	if err != nil {
@@ -169,6 +177,30 @@ func (gwf *GatewayForwarder) ReleaseContainer(ctx context.Context, req *gwapi.Re
 	return fwd.ReleaseContainer(ctx, req)
 }
 
+func (gwf *GatewayForwarder) MetaSet(ctx context.Context, req *gwapi.MetaSetRequest) (*gwapi.MetaSetResponse, error) {
+	fwd, err := gwf.lookupForwarder(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "forwarding MetaSet")
+	}
+	return fwd.MetaSet(ctx, req)
+}
+
+func (gwf *GatewayForwarder) MetaGet(ctx context.Context, req *gwapi.MetaGetRequest) (*gwapi.MetaGetResponse, error) {
+	fwd, err := gwf.lookupForwarder(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "forwarding MetaGet")
+	}
+	return fwd.MetaGet(ctx, req)
+}
+
+func (gwf *GatewayForwarder) Warn(ctx context.Context, req *gwapi.WarnRequest) (*gwapi.WarnResponse, error) {
+	fwd, err := gwf.lookupForwarder(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "forwarding Warn")
+	}
+	return fwd.Warn(ctx, req)
+}
+
 func (gwf *GatewayForwarder) ExecProcess(srv gwapi.LLBBridge_ExecProcessServer) error {
 	fwd, err := gwf.lookupForwarder(srv.Context())
 	if err != nil {
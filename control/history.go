@@ -0,0 +1,73 @@
+package control
+
+import (
+	"context"
+	"sync"
+
+	"github.com/moby/buildkit/client"
+)
+
+// buildHistory keeps the status stream of the most recent builds in memory so
+// it can be replayed after the build has completed.
+type buildHistory struct {
+	maxBuilds int
+	maxEvents int
+
+	mu      sync.Mutex
+	entries map[string][]*client.SolveStatus
+	order   []string
+}
+
+func newBuildHistory(maxBuilds, maxEvents int) *buildHistory {
+	if maxBuilds <= 0 {
+		return nil
+	}
+	return &buildHistory{
+		maxBuilds: maxBuilds,
+		maxEvents: maxEvents,
+		entries:   map[string][]*client.SolveStatus{},
+	}
+}
+
+// record reads the status of the build with the given ref until it completes.
+// Events over the per build limit are dropped.
+func (h *buildHistory) record(ref string, status func(ctx context.Context, ref string, ch chan *client.SolveStatus) error) {
+	h.mu.Lock()
+	if _, ok := h.entries[ref]; !ok {
+		h.order = append(h.order, ref)
+		for len(h.order) > h.maxBuilds {
+			delete(h.entries, h.order[0])
+			h.order = h.order[1:]
+		}
+	}
+	h.entries[ref] = nil
+	h.mu.Unlock()
+
+	ch := make(chan *client.SolveStatus, 8)
+	go status(context.TODO(), ref, ch)
+
+	for ss := range ch {
+		h.mu.Lock()
+		entry, ok := h.entries[ref]
+		if ok && (h.maxEvents <= 0 || len(entry) < h.maxEvents) {
+			h.entries[ref] = append(entry, ss)
+		}
+		h.mu.Unlock()
+	}
+}
+
+func (h *buildHistory) get(ref string) ([]*client.SolveStatus, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	entry, ok := h.entries[ref]
+	if !ok {
+		return nil, false
+	}
+	// copy so that splitting large logs on send doesn't modify the history
+	out := make([]*client.SolveStatus, 0, len(entry))
+	for _, ss := range entry {
+		ss := *ss
+		out = append(out, &ss)
+	}
+	return out, true
+}
@@ -0,0 +1,42 @@
+package control
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/buildkit/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildHistory(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, newBuildHistory(0, 0))
+
+	h := newBuildHistory(2, 3)
+	status := func(n int) func(context.Context, string, chan *client.SolveStatus) error {
+		return func(_ context.Context, ref string, ch chan *client.SolveStatus) error {
+			for i := 0; i < n; i++ {
+				ch <- &client.SolveStatus{Logs: []*client.VertexLog{{Data: []byte(ref)}}}
+			}
+			close(ch)
+			return nil
+		}
+	}
+
+	h.record("a", status(5))
+	ss, ok := h.get("a")
+	require.True(t, ok)
+	require.Equal(t, 3, len(ss))
+	require.Equal(t, "a", string(ss[0].Logs[0].Data))
+
+	h.record("b", status(1))
+	h.record("c", status(1))
+
+	_, ok = h.get("a")
+	require.False(t, ok)
+
+	ss, ok = h.get("c")
+	require.True(t, ok)
+	require.Equal(t, 1, len(ss))
+}
@@ -39,6 +39,7 @@ type containerdExecutor struct {
 	mu               sync.Mutex
 	apparmorProfile  string
 	traceSocket      string
+	pidNamespaces    oci.PIDNamespaces
 }
 
 // New creates a new executor backed by connection to containerd API
@@ -84,6 +85,13 @@ func (w *containerdExecutor) Run(ctx context.Context, id string, root executor.M
 
 	meta := process.Meta
 
+	if len(meta.Devices) > 0 {
+		return errors.New("devices are not supported by the containerd worker")
+	}
+	if meta.UserNSMapping != nil {
+		return errors.New("user namespace mappings are not supported by the containerd worker")
+	}
+
 	resolvConf, err := oci.GetResolvConf(ctx, w.root, nil, w.dnsConfig)
 	if err != nil {
 		return err
@@ -172,12 +180,32 @@ func (w *containerdExecutor) Run(ctx context.Context, id string, root executor.M
 		opts = append(opts, containerdoci.WithCgroup(cgroupsPath))
 	}
 	processMode := oci.ProcessSandbox // FIXME(AkihiroSuda)
-	spec, cleanup, err := oci.GenerateSpec(ctx, meta, mounts, id, resolvConf, hostsFile, namespace, processMode, nil, w.apparmorProfile, w.traceSocket, opts...)
+	if meta.SharedPID != "" {
+		nsPath, release, err := w.pidNamespaces.Join(meta.SharedPID)
+		if err != nil {
+			return errors.Wrap(err, "failed to join shared pid namespace")
+		}
+		defer func() {
+			if err := release(); err != nil {
+				logrus.Warnf("failed to remove shared pid namespace %s: %v", meta.SharedPID, err)
+			}
+		}()
+		opts = append(opts, oci.WithPIDNamespace(nsPath))
+	}
+	spec, cleanup, err := oci.GenerateSpec(ctx, meta, mounts, id, resolvConf, hostsFile, namespace, processMode, nil, w.apparmorProfile, w.traceSocket, "", opts...)
 	if err != nil {
 		return err
 	}
 	defer cleanup()
 	spec.Process.Terminal = meta.Tty
+	if len(meta.Sysctls) > 0 {
+		if spec.Linux.Sysctl == nil {
+			spec.Linux.Sysctl = map[string]string{}
+		}
+		for k, v := range meta.Sysctls {
+			spec.Linux.Sysctl[k] = v
+		}
+	}
 
 	container, err := w.client.NewContainer(ctx, id,
 		containerd.WithSpec(spec),
@@ -223,13 +251,7 @@ func (w *containerdExecutor) Run(ctx context.Context, id string, root executor.M
 	return err
 }
 
-func (w *containerdExecutor) Exec(ctx context.Context, id string, process executor.ProcessInfo) (err error) {
-	meta := process.Meta
-
-	// first verify the container is running, if we get an error assume the container
-	// is in the process of being created and check again every 100ms or until
-	// context is canceled.
-
+func (w *containerdExecutor) waitRunning(ctx context.Context, id string) (containerd.Container, containerd.Task, error) {
 	var container containerd.Container
 	var task containerd.Task
 	for {
@@ -238,7 +260,7 @@ func (w *containerdExecutor) Exec(ctx context.Context, id string, process execut
 		w.mu.Unlock()
 
 		if !ok {
-			return errors.Errorf("container %s not found", id)
+			return nil, nil, errors.Errorf("container %s not found", id)
 		}
 
 		if container == nil {
@@ -250,21 +272,34 @@ func (w *containerdExecutor) Exec(ctx context.Context, id string, process execut
 		if task != nil {
 			status, _ := task.Status(ctx)
 			if status.Status == containerd.Running {
-				break
+				return container, task, nil
 			}
 		}
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return nil, nil, ctx.Err()
 		case err, ok := <-done:
 			if !ok || err == nil {
-				return errors.Errorf("container %s has stopped", id)
+				return nil, nil, errors.Errorf("container %s has stopped", id)
 			}
-			return errors.Wrapf(err, "container %s has exited with error", id)
+			return nil, nil, errors.Wrapf(err, "container %s has exited with error", id)
 		case <-time.After(100 * time.Millisecond):
 			continue
 		}
 	}
+}
+
+func (w *containerdExecutor) Exec(ctx context.Context, id string, process executor.ProcessInfo) (err error) {
+	meta := process.Meta
+
+	// first verify the container is running, if we get an error assume the container
+	// is in the process of being created and check again every 100ms or until
+	// context is canceled.
+
+	container, task, err := w.waitRunning(ctx, id)
+	if err != nil {
+		return err
+	}
 
 	spec, err := container.Spec(ctx)
 	if err != nil {
@@ -284,8 +319,12 @@ func (w *containerdExecutor) Exec(ctx context.Context, id string, process execut
 			UID:            uid,
 			GID:            gid,
 			AdditionalGids: []uint32{},
+			Umask:          proc.User.Umask,
 		}
 	}
+	if meta.Umask != nil {
+		proc.User.Umask = meta.Umask
+	}
 
 	proc.Terminal = meta.Tty
 	proc.Args = meta.Args
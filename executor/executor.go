@@ -20,6 +20,25 @@ type Meta struct {
 	ExtraHosts     []HostIP
 	NetMode        pb.NetMode
 	SecurityMode   pb.SecurityMode
+	Seccomp        *pb.SeccompOpt
+	// ApparmorProfile overrides the apparmor profile of the executor
+	ApparmorProfile string
+	Devices         []*pb.Device
+	// Umask of the process, nil for the default umask
+	Umask *uint32
+	// Resources are the cgroup limits of the process, nil for no limits
+	Resources *pb.Resources
+	// SharedPID is the ID of a PID namespace that the process shares with the
+	// other processes with the same ID, empty for a new PID namespace. The
+	// namespace has an init process of its own, it is created when the first
+	// process is started and removed when the last process exits.
+	SharedPID string
+	// UserNSMapping runs the process in a new user namespace with the
+	// mappings, nil for the user namespace of the executor. Only rootless
+	// executors support it.
+	UserNSMapping *pb.UserNSMapping
+	// Sysctls are the kernel parameters of the namespaces of the process
+	Sysctls map[string]string
 }
 
 type Mountable interface {
@@ -0,0 +1,55 @@
+package oci
+
+import (
+	"sync"
+)
+
+// PIDNamespaces are the PID namespaces shared by the processes of an
+// executor. Each namespace has an init process of its own, so that the
+// processes that share it are siblings and none of them owns the namespace.
+// The namespace is created when the first process joins it and removed with
+// all remaining processes when the last process leaves it.
+type PIDNamespaces struct {
+	mu sync.Mutex
+	m  map[string]*pidNamespace
+}
+
+type pidNamespace struct {
+	path  string
+	refs  int
+	close func() error
+}
+
+// Join returns the path of the PID namespace with the ID, creating it if it
+// doesn't exist. release must be called when the process has exited.
+func (n *PIDNamespaces) Join(id string) (path string, release func() error, err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.m == nil {
+		n.m = map[string]*pidNamespace{}
+	}
+	ns, ok := n.m[id]
+	if !ok {
+		path, closeFn, err := newPIDNamespace()
+		if err != nil {
+			return "", nil, err
+		}
+		ns = &pidNamespace{path: path, close: closeFn}
+		n.m[id] = ns
+	}
+	ns.refs++
+	var once sync.Once
+	return ns.path, func() error {
+		var err error
+		once.Do(func() {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+			ns.refs--
+			if ns.refs == 0 {
+				delete(n.m, id)
+				err = ns.close()
+			}
+		})
+		return err
+	}, nil
+}
@@ -0,0 +1,76 @@
+package oci
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"github.com/docker/docker/pkg/reexec"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const pidNamespaceInit = "buildkit-pidns-init"
+
+func init() {
+	reexec.Register(pidNamespaceInit, pidNamespaceInitMain)
+}
+
+// pidNamespaceInitMain is the init process of a shared PID namespace. It
+// reaps the processes that are reparented to it until it is killed or the
+// daemon exits and closes its stdin.
+func pidNamespaceInitMain() {
+	go func() {
+		io.Copy(ioutil.Discard, os.Stdin)
+		os.Exit(0)
+	}()
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, unix.SIGCHLD)
+	for {
+		for {
+			pid, err := unix.Wait4(-1, nil, unix.WNOHANG, nil)
+			if pid <= 0 || err != nil {
+				break
+			}
+		}
+		<-ch
+	}
+}
+
+// newPIDNamespace starts the init process of a new PID namespace and returns
+// the path of the namespace
+func newPIDNamespace() (string, func() error, error) {
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return "", nil, errors.WithStack(err)
+	}
+	defer pr.Close()
+	cmd := reexec.Command(pidNamespaceInit)
+	cmd.Stdin = pr
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags: unix.CLONE_NEWPID,
+	}
+	if err := cmd.Start(); err != nil {
+		pw.Close()
+		return "", nil, errors.Wrap(err, "failed to start init process of pid namespace")
+	}
+	return fmt.Sprintf("/proc/%d/ns/pid", cmd.Process.Pid), func() error {
+		// the processes that are left in the namespace are killed with its
+		// init process
+		defer pw.Close()
+		if err := cmd.Process.Kill(); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := cmd.Wait(); err != nil {
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) {
+				return errors.WithStack(err)
+			}
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,66 @@
+package oci
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/pkg/reexec"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	if reexec.Init() {
+		return
+	}
+	os.Exit(m.Run())
+}
+
+func TestPIDNamespaces(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("requires root")
+	}
+
+	var n PIDNamespaces
+	p1, release1, err := n.Join("foo")
+	require.NoError(t, err)
+	p2, release2, err := n.Join("foo")
+	require.NoError(t, err)
+	require.Equal(t, p1, p2)
+
+	p3, release3, err := n.Join("bar")
+	require.NoError(t, err)
+	require.NotEqual(t, p1, p3)
+	require.NoError(t, release3())
+
+	self, err := os.Readlink("/proc/self/ns/pid")
+	require.NoError(t, err)
+	ns, err := os.Readlink(p1)
+	require.NoError(t, err)
+	require.NotEqual(t, self, ns)
+
+	pid, err := strconv.Atoi(strings.Split(p1, "/")[2])
+	require.NoError(t, err)
+
+	// the processes that join the namespace are children of its init process
+	if _, err := exec.LookPath("nsenter"); err == nil {
+		for i := 0; i < 2; i++ {
+			out, err := exec.Command("nsenter", "--pid="+p1, "--", "sh", "-c", "echo $$").CombinedOutput()
+			require.NoError(t, err, string(out))
+			require.NotEqual(t, "1", strings.TrimSpace(string(out)))
+		}
+	}
+
+	// the namespace is kept until the last process leaves it
+	require.NoError(t, release1())
+	require.NoError(t, release1())
+	_, err = os.Stat(p1)
+	require.NoError(t, err)
+
+	require.NoError(t, release2())
+	_, err = os.Stat("/proc/" + strconv.Itoa(pid))
+	require.True(t, os.IsNotExist(err), "%v", err)
+	require.Equal(t, 0, len(n.m))
+}
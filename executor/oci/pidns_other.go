@@ -0,0 +1,11 @@
+// +build !linux
+
+package oci
+
+import (
+	"github.com/pkg/errors"
+)
+
+func newPIDNamespace() (string, func() error, error) {
+	return "", nil, errors.New("shared pid namespaces are only supported on linux")
+}
@@ -3,6 +3,8 @@ package oci
 import (
 	"context"
 	"path"
+	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/containerd/containerd/containers"
@@ -14,6 +16,7 @@ import (
 	"github.com/mitchellh/hashstructure"
 	"github.com/moby/buildkit/executor"
 	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/buildkit/util/network"
 	traceexec "github.com/moby/buildkit/util/tracing/exec"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
@@ -33,11 +36,41 @@ const (
 	NoProcessSandbox
 )
 
+// ValidateDevices checks that all the devices requested by a process are in
+// the list of devices that the worker allows
+func ValidateDevices(devices []*pb.Device, allowed []string) error {
+	for _, d := range devices {
+		if d.Permissions == "" || strings.Trim(d.Permissions, "rwm") != "" {
+			return errors.Errorf("invalid permissions %q for device %s", d.Permissions, d.Path)
+		}
+		var ok bool
+		for _, a := range allowed {
+			if filepath.Clean(a) == filepath.Clean(d.Path) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return errors.Errorf("device %s is not allowed by the worker, add it to the devices of the worker configuration to allow it", d.Path)
+		}
+	}
+	return nil
+}
+
+// WithPIDNamespace makes the container join the PID namespace at path
+// instead of creating a new PID namespace
+func WithPIDNamespace(path string) oci.SpecOpts {
+	return oci.WithLinuxNamespace(specs.LinuxNamespace{
+		Type: specs.PIDNamespace,
+		Path: path,
+	})
+}
+
 // Ideally we don't have to import whole containerd just for the default spec
 
 // GenerateSpec generates spec using containerd functionality.
 // opts are ignored for s.Process, s.Hostname, and s.Mounts .
-func GenerateSpec(ctx context.Context, meta executor.Meta, mounts []executor.Mount, id, resolvConf, hostsFile string, namespace network.Namespace, processMode ProcessMode, idmap *idtools.IdentityMapping, apparmorProfile string, tracingSocket string, opts ...oci.SpecOpts) (*specs.Spec, func(), error) {
+func GenerateSpec(ctx context.Context, meta executor.Meta, mounts []executor.Mount, id, resolvConf, hostsFile string, namespace network.Namespace, processMode ProcessMode, idmap *idtools.IdentityMapping, apparmorProfile, tracingSocket, tempDir string, opts ...oci.SpecOpts) (*specs.Spec, func(), error) {
 	c := &containers.Container{
 		ID: id,
 	}
@@ -54,12 +87,27 @@ func GenerateSpec(ctx context.Context, meta executor.Meta, mounts []executor.Mou
 		return nil, nil, err
 	}
 
-	if securityOpts, err := generateSecurityOpts(meta.SecurityMode, apparmorProfile); err == nil {
+	if meta.ApparmorProfile != "" {
+		apparmorProfile = meta.ApparmorProfile
+	}
+	if securityOpts, err := generateSecurityOpts(meta.SecurityMode, meta.Seccomp, apparmorProfile); err == nil {
 		opts = append(opts, securityOpts...)
 	} else {
 		return nil, nil, err
 	}
 
+	if deviceOpts, err := generateDeviceOpts(meta.Devices); err == nil {
+		opts = append(opts, deviceOpts...)
+	} else {
+		return nil, nil, err
+	}
+
+	if resourceOpts, err := generateResourceOpts(meta.Resources); err == nil {
+		opts = append(opts, resourceOpts...)
+	} else {
+		return nil, nil, err
+	}
+
 	if processModeOpts, err := generateProcessModeOpts(processMode); err == nil {
 		opts = append(opts, processModeOpts...)
 	} else {
@@ -103,7 +151,11 @@ func GenerateSpec(ctx context.Context, meta executor.Meta, mounts []executor.Mou
 
 	s.Process.Rlimits = nil // reset open files limit
 
-	sm := &submounts{}
+	if meta.Umask != nil {
+		s.Process.User.Umask = meta.Umask
+	}
+
+	sm := &submounts{tempDir: tempDir}
 
 	var releasers []func() error
 	releaseAll := func() {
@@ -164,7 +216,8 @@ type mountRef struct {
 }
 
 type submounts struct {
-	m map[uint64]mountRef
+	m       map[uint64]mountRef
+	tempDir string
 }
 
 func (s *submounts) subMount(m mount.Mount, subPath string) (mount.Mount, error) {
@@ -186,7 +239,7 @@ func (s *submounts) subMount(m mount.Mount, subPath string) (mount.Mount, error)
 		return sm, nil
 	}
 
-	lm := snapshot.LocalMounterWithMounts([]mount.Mount{m})
+	lm := snapshot.LocalMounterWithMountsInDir(s.tempDir, []mount.Mount{m})
 
 	mp, err := lm.Mount()
 	if err != nil {
@@ -250,3 +303,20 @@ func specMapping(s []idtools.IDMap) []specs.LinuxIDMapping {
 	}
 	return ids
 }
+
+// MergeHooks returns the hooks of s with the hooks of extra appended.
+func MergeHooks(s, extra *specs.Hooks) *specs.Hooks {
+	if extra == nil {
+		return s
+	}
+	if s == nil {
+		s = &specs.Hooks{}
+	}
+	s.Prestart = append(s.Prestart, extra.Prestart...)
+	s.CreateRuntime = append(s.CreateRuntime, extra.CreateRuntime...)
+	s.CreateContainer = append(s.CreateContainer, extra.CreateContainer...)
+	s.StartContainer = append(s.StartContainer, extra.StartContainer...)
+	s.Poststart = append(s.Poststart, extra.Poststart...)
+	s.Poststop = append(s.Poststop, extra.Poststop...)
+	return s
+}
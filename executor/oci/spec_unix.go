@@ -14,6 +14,7 @@ import (
 	"github.com/moby/buildkit/util/system"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/opencontainers/selinux/go-selinux/label"
+	"github.com/pkg/errors"
 )
 
 func generateMountOpts(resolvConf, hostsFile string) ([]oci.SpecOpts, error) {
@@ -27,7 +28,7 @@ func generateMountOpts(resolvConf, hostsFile string) ([]oci.SpecOpts, error) {
 }
 
 // generateSecurityOpts may affect mounts, so must be called after generateMountOpts
-func generateSecurityOpts(mode pb.SecurityMode, apparmorProfile string) (opts []oci.SpecOpts, _ error) {
+func generateSecurityOpts(mode pb.SecurityMode, seccompOpt *pb.SeccompOpt, apparmorProfile string) (opts []oci.SpecOpts, _ error) {
 	switch mode {
 	case pb.SecurityMode_INSECURE:
 		return []oci.SpecOpts{
@@ -41,7 +42,14 @@ func generateSecurityOpts(mode pb.SecurityMode, apparmorProfile string) (opts []
 			},
 		}, nil
 	case pb.SecurityMode_SANDBOX:
-		if system.SeccompSupported() {
+		switch {
+		case seccompOpt != nil && seccompOpt.Unconfined:
+		case seccompOpt != nil && seccompOpt.Profile != "":
+			if !system.SeccompSupported() {
+				return nil, errors.New("seccomp is not supported by the daemon")
+			}
+			opts = append(opts, withProfile(seccompOpt.Profile))
+		case system.SeccompSupported():
 			opts = append(opts, withDefaultProfile())
 		}
 		if apparmorProfile != "" {
@@ -57,6 +65,49 @@ func generateSecurityOpts(mode pb.SecurityMode, apparmorProfile string) (opts []
 	return nil, nil
 }
 
+func generateDeviceOpts(devices []*pb.Device) (opts []oci.SpecOpts, _ error) {
+	for _, d := range devices {
+		opts = append(opts, oci.WithLinuxDevice(d.Path, d.Permissions))
+	}
+	return opts, nil
+}
+
+func generateResourceOpts(r *pb.Resources) ([]oci.SpecOpts, error) {
+	if r == nil {
+		return nil, nil
+	}
+	if r.Memory < 0 || r.CpuQuota < 0 {
+		return nil, errors.Errorf("invalid resource limits %+v", r)
+	}
+	return []oci.SpecOpts{
+		func(_ context.Context, _ oci.Client, _ *containers.Container, s *specs.Spec) error {
+			if s.Linux == nil {
+				s.Linux = &specs.Linux{}
+			}
+			if s.Linux.Resources == nil {
+				s.Linux.Resources = &specs.LinuxResources{}
+			}
+			if r.Memory > 0 {
+				limit := r.Memory
+				// swap is included in the limit so the process can't use
+				// more memory than requested
+				swap := r.Memory
+				s.Linux.Resources.Memory = &specs.LinuxMemory{Limit: &limit, Swap: &swap}
+			}
+			if r.CpuQuota > 0 {
+				quota := r.CpuQuota
+				cpu := &specs.LinuxCPU{Quota: &quota}
+				if r.CpuPeriod > 0 {
+					period := r.CpuPeriod
+					cpu.Period = &period
+				}
+				s.Linux.Resources.CPU = cpu
+			}
+			return nil
+		},
+	}, nil
+}
+
 // generateProcessModeOpts may affect mounts, so must be called after generateMountOpts
 func generateProcessModeOpts(mode ProcessMode) ([]oci.SpecOpts, error) {
 	if mode == NoProcessSandbox {
@@ -78,6 +129,17 @@ func generateIDmapOpts(idmap *idtools.IdentityMapping) ([]oci.SpecOpts, error) {
 	}, nil
 }
 
+// withProfile sets a custom seccomp profile in the JSON format of Docker
+// profiles to the spec.
+// Note: must follow the setting of process capabilities
+func withProfile(profile string) oci.SpecOpts {
+	return func(_ context.Context, _ oci.Client, _ *containers.Container, s *specs.Spec) error {
+		var err error
+		s.Linux.Seccomp, err = seccomp.LoadProfile(profile, s)
+		return errors.Wrap(err, "invalid seccomp profile")
+	}
+}
+
 // withDefaultProfile sets the default seccomp profile to the spec.
 // Note: must follow the setting of process capabilities
 func withDefaultProfile() oci.SpecOpts {
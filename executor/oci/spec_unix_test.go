@@ -0,0 +1,75 @@
+// +build linux
+
+package oci
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/appcontext"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithSeccompProfile(t *testing.T) {
+	t.Parallel()
+
+	s := &specs.Spec{Linux: &specs.Linux{}}
+	err := withProfile(`{"defaultAction":"SCMP_ACT_ERRNO","syscalls":[{"names":["read"],"action":"SCMP_ACT_ALLOW"}]}`)(appcontext.Context(), nil, nil, s)
+	require.NoError(t, err)
+	require.NotNil(t, s.Linux.Seccomp)
+	require.Equal(t, specs.ActErrno, s.Linux.Seccomp.DefaultAction)
+	require.Equal(t, 1, len(s.Linux.Seccomp.Syscalls))
+
+	s = &specs.Spec{Linux: &specs.Linux{}}
+	err = withProfile(`{"defaultAction":`)(appcontext.Context(), nil, nil, s)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid seccomp profile")
+}
+
+func TestValidateDevices(t *testing.T) {
+	t.Parallel()
+
+	allowed := []string{"/dev/fuse", "/dev/nvidia0/"}
+
+	err := ValidateDevices([]*pb.Device{{Path: "/dev/fuse", Permissions: "rwm"}, {Path: "/dev/nvidia0", Permissions: "r"}}, allowed)
+	require.NoError(t, err)
+
+	err = ValidateDevices([]*pb.Device{{Path: "/dev/sda", Permissions: "rwm"}}, allowed)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "device /dev/sda is not allowed")
+
+	err = ValidateDevices([]*pb.Device{{Path: "/dev/fuse", Permissions: "rwx"}}, allowed)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid permissions")
+
+	err = ValidateDevices([]*pb.Device{{Path: "/dev/fuse", Permissions: "rw"}}, nil)
+	require.Error(t, err)
+}
+
+func TestGenerateResourceOpts(t *testing.T) {
+	t.Parallel()
+
+	opts, err := generateResourceOpts(&pb.Resources{Memory: 64 << 20, CpuQuota: 50000, CpuPeriod: 100000})
+	require.NoError(t, err)
+	s := &specs.Spec{}
+	for _, o := range opts {
+		require.NoError(t, o(appcontext.Context(), nil, nil, s))
+	}
+	require.Equal(t, int64(64<<20), *s.Linux.Resources.Memory.Limit)
+	require.Equal(t, int64(64<<20), *s.Linux.Resources.Memory.Swap)
+	require.Equal(t, int64(50000), *s.Linux.Resources.CPU.Quota)
+	require.Equal(t, uint64(100000), *s.Linux.Resources.CPU.Period)
+
+	opts, err = generateResourceOpts(&pb.Resources{CpuQuota: 50000})
+	require.NoError(t, err)
+	s = &specs.Spec{}
+	for _, o := range opts {
+		require.NoError(t, o(appcontext.Context(), nil, nil, s))
+	}
+	require.Nil(t, s.Linux.Resources.Memory)
+	require.Nil(t, s.Linux.Resources.CPU.Period)
+
+	_, err = generateResourceOpts(&pb.Resources{Memory: -1})
+	require.Error(t, err)
+}
@@ -14,10 +14,27 @@ func generateMountOpts(resolvConf, hostsFile string) ([]oci.SpecOpts, error) {
 }
 
 // generateSecurityOpts may affect mounts, so must be called after generateMountOpts
-func generateSecurityOpts(mode pb.SecurityMode, apparmorProfile string) ([]oci.SpecOpts, error) {
+func generateSecurityOpts(mode pb.SecurityMode, seccomp *pb.SeccompOpt, apparmorProfile string) ([]oci.SpecOpts, error) {
 	if mode == pb.SecurityMode_INSECURE {
 		return nil, errors.New("no support for running in insecure mode on Windows")
 	}
+	if seccomp != nil {
+		return nil, errors.New("no support for seccomp on Windows")
+	}
+	return nil, nil
+}
+
+func generateDeviceOpts(devices []*pb.Device) ([]oci.SpecOpts, error) {
+	if len(devices) > 0 {
+		return nil, errors.New("no support for devices on Windows")
+	}
+	return nil, nil
+}
+
+func generateResourceOpts(r *pb.Resources) ([]oci.SpecOpts, error) {
+	if r != nil {
+		return nil, errors.New("no support for resource limits on Windows")
+	}
 	return nil, nil
 }
 
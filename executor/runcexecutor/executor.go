@@ -47,6 +47,13 @@ type Opt struct {
 	OOMScoreAdj     *int
 	ApparmorProfile string
 	TracingSocket   string
+	// Hooks are OCI lifecycle hooks added to every container
+	Hooks *specs.Hooks
+	// AllowedDevices are the host devices that processes can request
+	AllowedDevices []string
+	// TempDir is the directory for container bundles and temporary mounts.
+	// Defaults to Root for bundles and the OS temporary directory for mounts.
+	TempDir string
 }
 
 var defaultCommandCandidates = []string{"buildkit-runc", "runc"}
@@ -64,8 +71,12 @@ type runcExecutor struct {
 	oomScoreAdj      *int
 	running          map[string]chan error
 	mu               sync.Mutex
+	pidNamespaces    oci.PIDNamespaces
 	apparmorProfile  string
 	tracingSocket    string
+	hooks            *specs.Hooks
+	allowedDevices   []string
+	tempDir          string
 }
 
 func New(opt Opt, networkProviders map[pb.NetMode]network.Provider) (executor.Executor, error) {
@@ -101,6 +112,12 @@ func New(opt Opt, networkProviders map[pb.NetMode]network.Provider) (executor.Ex
 		return nil, err
 	}
 
+	if opt.TempDir != "" {
+		if err := os.MkdirAll(opt.TempDir, 0711); err != nil {
+			return nil, errors.Wrapf(err, "failed to create %s", opt.TempDir)
+		}
+	}
+
 	// clean up old hosts/resolv.conf file. ignore errors
 	os.RemoveAll(filepath.Join(root, "hosts"))
 	os.RemoveAll(filepath.Join(root, "resolv.conf"))
@@ -129,6 +146,9 @@ func New(opt Opt, networkProviders map[pb.NetMode]network.Provider) (executor.Ex
 		oomScoreAdj:      opt.OOMScoreAdj,
 		running:          make(map[string]chan error),
 		apparmorProfile:  opt.ApparmorProfile,
+		hooks:            opt.Hooks,
+		allowedDevices:   opt.AllowedDevices,
+		tempDir:          opt.TempDir,
 		tracingSocket:    opt.TracingSocket,
 	}
 	return w, nil
@@ -155,6 +175,10 @@ func (w *runcExecutor) Run(ctx context.Context, id string, root executor.Mount,
 		}
 	}()
 
+	if err := oci.ValidateDevices(meta.Devices, w.allowedDevices); err != nil {
+		return err
+	}
+
 	provider, ok := w.networkProviders[meta.NetMode]
 	if !ok {
 		return errors.Errorf("unknown network mode %s", meta.NetMode)
@@ -198,7 +222,11 @@ func (w *runcExecutor) Run(ctx context.Context, id string, root executor.Mount,
 	if id == "" {
 		id = identity.NewID()
 	}
-	bundle := filepath.Join(w.root, id)
+	bundleRoot := w.root
+	if w.tempDir != "" {
+		bundleRoot = w.tempDir
+	}
+	bundle := filepath.Join(bundleRoot, id)
 
 	if err := os.Mkdir(bundle, 0711); err != nil {
 		return err
@@ -249,7 +277,18 @@ func (w *runcExecutor) Run(ctx context.Context, id string, root executor.Mount,
 		}
 	}
 
-	if w.cgroupParent != "" {
+	var oomCgroupPath string
+	if meta.Resources != nil && meta.Resources.Memory > 0 {
+		oomCgroupPath = oomCgroup(w.cgroupParent, id, w.rootless)
+	}
+	if oomCgroupPath != "" {
+		opts = append(opts, containerdoci.WithCgroup(filepath.Join(oomCgroupPath, "init")))
+		defer func() {
+			if err := removeCgroup(oomCgroupPath); err != nil {
+				logrus.Warnf("failed to remove cgroup %s: %v", oomCgroupPath, err)
+			}
+		}()
+	} else if w.cgroupParent != "" {
 		var cgroupsPath string
 		lastSeparator := w.cgroupParent[len(w.cgroupParent)-1:]
 		if strings.Contains(w.cgroupParent, ".slice") && lastSeparator == ":" {
@@ -259,7 +298,21 @@ func (w *runcExecutor) Run(ctx context.Context, id string, root executor.Mount,
 		}
 		opts = append(opts, containerdoci.WithCgroup(cgroupsPath))
 	}
-	spec, cleanup, err := oci.GenerateSpec(ctx, meta, mounts, id, resolvConf, hostsFile, namespace, w.processMode, w.idmap, w.apparmorProfile, w.tracingSocket, opts...)
+	// without a process sandbox all containers already share the PID
+	// namespace of the host
+	if meta.SharedPID != "" && w.processMode == oci.ProcessSandbox {
+		nsPath, release, err := w.pidNamespaces.Join(meta.SharedPID)
+		if err != nil {
+			return errors.Wrap(err, "failed to join shared pid namespace")
+		}
+		defer func() {
+			if err := release(); err != nil {
+				logrus.Warnf("failed to remove shared pid namespace %s: %v", meta.SharedPID, err)
+			}
+		}()
+		opts = append(opts, oci.WithPIDNamespace(nsPath))
+	}
+	spec, cleanup, err := oci.GenerateSpec(ctx, meta, mounts, id, resolvConf, hostsFile, namespace, w.processMode, w.idmap, w.apparmorProfile, w.tracingSocket, w.tempDir, opts...)
 	if err != nil {
 		return err
 	}
@@ -282,10 +335,28 @@ func (w *runcExecutor) Run(ctx context.Context, id string, root executor.Mount,
 
 	spec.Process.Terminal = meta.Tty
 	spec.Process.OOMScoreAdj = w.oomScoreAdj
+	if len(meta.Sysctls) > 0 {
+		if spec.Linux.Sysctl == nil {
+			spec.Linux.Sysctl = map[string]string{}
+		}
+		for k, v := range meta.Sysctls {
+			spec.Linux.Sysctl[k] = v
+		}
+	}
+	if w.hooks != nil {
+		spec.Hooks = oci.MergeHooks(spec.Hooks, w.hooks)
+	}
 	if w.rootless {
 		if err := rootlessspecconv.ToRootless(spec); err != nil {
 			return err
 		}
+		if m := meta.UserNSMapping; m != nil {
+			if err := rootlessspecconv.WithUserNSMapping(spec, specIDMappings(m.UidMappings), specIDMappings(m.GidMappings)); err != nil {
+				return err
+			}
+		}
+	} else if meta.UserNSMapping != nil {
+		return errors.New("user namespace mappings require a rootless worker")
 	}
 
 	if err := json.NewEncoder(f).Encode(spec); err != nil {
@@ -334,10 +405,21 @@ func (w *runcExecutor) Run(ctx context.Context, id string, root executor.Mount,
 
 	err = w.run(runCtx, id, bundle, process)
 	close(ended)
-	return exitError(ctx, err)
+	var oomErr error
+	if err != nil && oomCgroupPath != "" && ctx.Err() == nil {
+		killed, kerr := oomKilled(oomCgroupPath)
+		if kerr != nil {
+			logrus.Warnf("failed to check oom kills of %s: %v", id, kerr)
+		} else if killed {
+			oomErr = &errdefs.OOMError{Limit: meta.Resources.Memory}
+		}
+	}
+	return exitError(ctx, err, oomErr)
 }
 
-func exitError(ctx context.Context, err error) error {
+// exitError returns the ExitError of the process. oomErr is the cause of the
+// exit of a process that was killed by the OOM killer.
+func exitError(ctx context.Context, err error, oomErr error) error {
 	if err != nil {
 		exitErr := &errdefs.ExitError{
 			ExitCode: errdefs.UnknownExitStatus,
@@ -348,6 +430,9 @@ func exitError(ctx context.Context, err error) error {
 			exitErr = &errdefs.ExitError{
 				ExitCode: uint32(runcExitError.Status),
 			}
+			if oomErr != nil {
+				exitErr.Err = oomErr
+			}
 		}
 		select {
 		case <-ctx.Done():
@@ -361,34 +446,40 @@ func exitError(ctx context.Context, err error) error {
 	return nil
 }
 
-func (w *runcExecutor) Exec(ctx context.Context, id string, process executor.ProcessInfo) (err error) {
-	// first verify the container is running, if we get an error assume the container
-	// is in the process of being created and check again every 100ms or until
-	// context is canceled.
-	var state *runc.Container
+func (w *runcExecutor) waitRunning(ctx context.Context, id string) (*runc.Container, error) {
 	for {
 		w.mu.Lock()
 		done, ok := w.running[id]
 		w.mu.Unlock()
 		if !ok {
-			return errors.Errorf("container %s not found", id)
+			return nil, errors.Errorf("container %s not found", id)
 		}
 
-		state, _ = w.runc.State(ctx, id)
+		state, _ := w.runc.State(ctx, id)
 		if state != nil && state.Status == "running" {
-			break
+			return state, nil
 		}
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return nil, ctx.Err()
 		case err, ok := <-done:
 			if !ok || err == nil {
-				return errors.Errorf("container %s has stopped", id)
+				return nil, errors.Errorf("container %s has stopped", id)
 			}
-			return errors.Wrapf(err, "container %s has exited with error", id)
+			return nil, errors.Wrapf(err, "container %s has exited with error", id)
 		case <-time.After(100 * time.Millisecond):
 		}
 	}
+}
+
+func (w *runcExecutor) Exec(ctx context.Context, id string, process executor.ProcessInfo) (err error) {
+	// first verify the container is running, if we get an error assume the container
+	// is in the process of being created and check again every 100ms or until
+	// context is canceled.
+	state, err := w.waitRunning(ctx, id)
+	if err != nil {
+		return err
+	}
 
 	// load default process spec (for Env, Cwd etc) from bundle
 	f, err := os.Open(filepath.Join(state.Bundle, "config.json"))
@@ -411,8 +502,12 @@ func (w *runcExecutor) Exec(ctx context.Context, id string, process executor.Pro
 			UID:            uid,
 			GID:            gid,
 			AdditionalGids: sgids,
+			Umask:          spec.Process.User.Umask,
 		}
 	}
+	if process.Meta.Umask != nil {
+		spec.Process.User.Umask = process.Meta.Umask
+	}
 
 	spec.Process.Terminal = process.Meta.Tty
 	spec.Process.Args = process.Meta.Args
@@ -425,7 +520,7 @@ func (w *runcExecutor) Exec(ctx context.Context, id string, process executor.Pro
 	}
 
 	err = w.exec(ctx, id, state.Bundle, spec.Process, process)
-	return exitError(ctx, err)
+	return exitError(ctx, err, nil)
 }
 
 type forwardIO struct {
@@ -454,3 +549,15 @@ func (s *forwardIO) Stdout() io.ReadCloser {
 func (s *forwardIO) Stderr() io.ReadCloser {
 	return nil
 }
+
+func specIDMappings(maps []*pb.IDMap) []specs.LinuxIDMapping {
+	out := make([]specs.LinuxIDMapping, 0, len(maps))
+	for _, m := range maps {
+		out = append(out, specs.LinuxIDMapping{
+			ContainerID: m.ContainerID,
+			HostID:      m.HostID,
+			Size:        m.Size_,
+		})
+	}
+	return out
+}
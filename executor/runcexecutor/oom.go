@@ -0,0 +1,65 @@
+package runcexecutor
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// cgroupRoot is the mount point of the unified cgroup hierarchy
+var cgroupRoot = "/sys/fs/cgroup"
+
+// oomCgroup returns the cgroup that contains the cgroup of a process with a
+// memory limit, or an empty string if OOM kills can't be detected. runc
+// removes the cgroup of the process when it exits, so the OOM kills are read
+// from the memory.events of the parent, that counts the events of its
+// descendants.
+func oomCgroup(cgroupParent, id string, rootless bool) string {
+	if rootless {
+		return ""
+	}
+	// systemd cgroup paths can't be nested
+	if strings.Contains(cgroupParent, ".slice") && strings.HasSuffix(cgroupParent, ":") {
+		return ""
+	}
+	// memory.events only exists in the unified hierarchy
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+		return ""
+	}
+	return filepath.Join("/", cgroupParent, "buildkit", id)
+}
+
+// oomKilled returns true if a process of the cgroup was killed by the OOM
+// killer
+func oomKilled(cgroup string) (bool, error) {
+	f, err := os.Open(filepath.Join(cgroupRoot, cgroup, "memory.events"))
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) != 2 || fields[0] != "oom_kill" {
+			continue
+		}
+		n, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return false, errors.Wrapf(err, "invalid oom_kill count %q", fields[1])
+		}
+		return n > 0, nil
+	}
+	return false, errors.WithStack(s.Err())
+}
+
+// removeCgroup removes the cgroup that contained the cgroup of a process
+func removeCgroup(cgroup string) error {
+	if err := os.Remove(filepath.Join(cgroupRoot, cgroup)); err != nil && !os.IsNotExist(err) {
+		return errors.WithStack(err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package runcexecutor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOOMKilled(t *testing.T) {
+	root, err := ioutil.TempDir("", "buildkit-cgroup")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	defer func(old string) { cgroupRoot = old }(cgroupRoot)
+	cgroupRoot = root
+
+	// cgroup v1
+	require.Equal(t, "", oomCgroup("", "foo", false))
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "cgroup.controllers"), []byte("cpu memory"), 0644))
+	require.Equal(t, "/buildkit/foo", oomCgroup("", "foo", false))
+	require.Equal(t, "/parent/buildkit/foo", oomCgroup("parent", "foo", false))
+	require.Equal(t, "", oomCgroup("system.slice:buildkit:", "foo", false))
+	require.Equal(t, "", oomCgroup("", "foo", true))
+
+	cg := oomCgroup("", "foo", false)
+	require.NoError(t, os.MkdirAll(filepath.Join(root, cg), 0755))
+	events := filepath.Join(root, cg, "memory.events")
+
+	require.NoError(t, ioutil.WriteFile(events, []byte("low 0\nhigh 0\nmax 3\noom 1\noom_kill 0\n"), 0644))
+	killed, err := oomKilled(cg)
+	require.NoError(t, err)
+	require.False(t, killed)
+
+	require.NoError(t, ioutil.WriteFile(events, []byte("low 0\nhigh 0\nmax 5\noom 1\noom_kill 1\n"), 0644))
+	killed, err = oomKilled(cg)
+	require.NoError(t, err)
+	require.True(t, killed)
+
+	require.NoError(t, os.Remove(events))
+	require.NoError(t, removeCgroup(cg))
+	require.NoError(t, removeCgroup(cg))
+}
@@ -0,0 +1,87 @@
+package containerimage
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	"github.com/moby/buildkit/exporter"
+	"github.com/moby/buildkit/exporter/containerimage/exptypes"
+	"github.com/moby/buildkit/solver/llbsolver/provenance"
+	"github.com/moby/buildkit/util/push"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// statement is an in-toto statement with an arbitrary predicate
+type statement struct {
+	Type          string               `json:"_type"`
+	PredicateType string               `json:"predicateType"`
+	Subject       []provenance.Subject `json:"subject"`
+	Predicate     json.RawMessage      `json:"predicate"`
+}
+
+// imageAttestations returns the in-toto statements of the build provenance
+// and of the attestations returned by the frontend with the image desc of name
+// as subject
+func imageAttestations(src exporter.Source, name string, desc ocispec.Descriptor) ([]push.Attestation, error) {
+	var attestations []exptypes.Attestation
+	if dt, ok := src.Metadata[exptypes.ExporterProvenanceKey]; ok {
+		var predicate provenance.Predicate
+		if err := json.Unmarshal(dt, &predicate); err != nil {
+			return nil, errors.Wrap(err, "failed to parse build provenance")
+		}
+		attestations = append(attestations, exptypes.Attestation{
+			PredicateType: provenance.PredicateType,
+			Predicate:     dt,
+		})
+	}
+
+	var keys []string
+	for k := range src.Metadata {
+		if strings.HasPrefix(k, exptypes.ExporterAttestationPrefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		var a exptypes.Attestation
+		if err := json.Unmarshal(src.Metadata[k], &a); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse attestation %s", strings.TrimPrefix(k, exptypes.ExporterAttestationPrefix))
+		}
+		if a.PredicateType == "" {
+			return nil, errors.Errorf("attestation %s has no predicate type", strings.TrimPrefix(k, exptypes.ExporterAttestationPrefix))
+		}
+		attestations = append(attestations, a)
+	}
+	if len(attestations) == 0 {
+		return nil, nil
+	}
+
+	parsed, err := reference.ParseNormalizedNamed(name)
+	if err != nil {
+		return nil, err
+	}
+	subject := []provenance.Subject{{
+		Name:   reference.TrimNamed(parsed).String(),
+		Digest: provenance.DigestSet{desc.Digest.Algorithm().String(): desc.Digest.Hex()},
+	}}
+	out := make([]push.Attestation, 0, len(attestations))
+	for _, a := range attestations {
+		dt, err := json.Marshal(statement{
+			Type:          provenance.StatementType,
+			PredicateType: a.PredicateType,
+			Subject:       subject,
+			Predicate:     a.Predicate,
+		})
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		out = append(out, push.Attestation{
+			PredicateType: a.PredicateType,
+			Statement:     dt,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,55 @@
+package containerimage
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/moby/buildkit/exporter"
+	"github.com/moby/buildkit/exporter/containerimage/exptypes"
+	"github.com/moby/buildkit/solver/llbsolver/provenance"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageAttestations(t *testing.T) {
+	t.Parallel()
+
+	prov, err := json.Marshal(provenance.Predicate{})
+	require.NoError(t, err)
+	sbom, err := json.Marshal(exptypes.Attestation{
+		PredicateType: "https://spdx.dev/Document",
+		Predicate:     json.RawMessage(`{"spdxVersion":"SPDX-2.2"}`),
+	})
+	require.NoError(t, err)
+
+	desc := ocispec.Descriptor{Digest: digest.FromString("image")}
+	attestations, err := imageAttestations(exporter.Source{
+		Metadata: map[string][]byte{
+			exptypes.ExporterProvenanceKey:              prov,
+			exptypes.ExporterAttestationPrefix + "sbom": sbom,
+		},
+	}, "docker.io/library/foo:latest", desc)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(attestations))
+	require.Equal(t, provenance.PredicateType, attestations[0].PredicateType)
+	require.Equal(t, "https://spdx.dev/Document", attestations[1].PredicateType)
+
+	var stmt statement
+	require.NoError(t, json.Unmarshal(attestations[1].Statement, &stmt))
+	require.Equal(t, provenance.StatementType, stmt.Type)
+	require.Equal(t, "https://spdx.dev/Document", stmt.PredicateType)
+	require.Equal(t, []provenance.Subject{{
+		Name:   "docker.io/library/foo",
+		Digest: provenance.DigestSet{"sha256": desc.Digest.Hex()},
+	}}, stmt.Subject)
+	require.JSONEq(t, `{"spdxVersion":"SPDX-2.2"}`, string(stmt.Predicate))
+
+	_, err = imageAttestations(exporter.Source{
+		Metadata: map[string][]byte{
+			exptypes.ExporterAttestationPrefix + "sbom": []byte(`{"predicate":{}}`),
+		},
+	}, "foo", desc)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no predicate type")
+}
@@ -0,0 +1,461 @@
+package containerimage
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/pkg/signal"
+	"github.com/moby/buildkit/exporter/containerimage/exptypes"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// healthConfig mirrors the Healthcheck field of the image config
+type healthConfig struct {
+	Test        []string      `json:",omitempty"`
+	Interval    time.Duration `json:",omitempty"`
+	Timeout     time.Duration `json:",omitempty"`
+	StartPeriod time.Duration `json:",omitempty"`
+	Retries     int           `json:",omitempty"`
+}
+
+// configPatch holds the image config overrides set with exporter options
+type configPatch struct {
+	stopSignal  string
+	test        []string
+	interval    *time.Duration
+	timeout     *time.Duration
+	startPeriod *time.Duration
+	retries     *int
+	user        *string
+	workingDir  *string
+	env         []string
+	envRemove   []string
+	entrypoint  []string
+	cmd         []string
+
+	os           *string
+	architecture *string
+	variant      *string
+}
+
+func parseConfigPatch(meta map[string][]byte) (*configPatch, error) {
+	var p configPatch
+	found := false
+	for k, v := range meta {
+		val := string(v)
+		switch k {
+		case exptypes.ExporterConfigStopSignal:
+			if _, err := signal.ParseSignal(val); err != nil {
+				return nil, errors.Wrapf(err, "invalid %s", k)
+			}
+			p.stopSignal = val
+		case exptypes.ExporterConfigHealthcheckTest:
+			test, err := parseHealthcheckTest(val)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid %s", k)
+			}
+			p.test = test
+		case exptypes.ExporterConfigHealthcheckInterval:
+			d, err := parseHealthcheckDuration(val)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid %s", k)
+			}
+			p.interval = &d
+		case exptypes.ExporterConfigHealthcheckTimeout:
+			d, err := parseHealthcheckDuration(val)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid %s", k)
+			}
+			p.timeout = &d
+		case exptypes.ExporterConfigHealthcheckStartPeriod:
+			d, err := parseHealthcheckDuration(val)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid %s", k)
+			}
+			p.startPeriod = &d
+		case exptypes.ExporterConfigHealthcheckRetries:
+			n, err := strconv.Atoi(val)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid %s", k)
+			}
+			if n < 1 {
+				return nil, errors.Errorf("invalid %s: must be at least 1", k)
+			}
+			p.retries = &n
+		case exptypes.ExporterConfigUser:
+			p.user = &val
+		case exptypes.ExporterConfigWorkingDir:
+			p.workingDir = &val
+		case exptypes.ExporterConfigEnv:
+			env, err := parseEnv(val)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid %s", k)
+			}
+			p.env = env
+		case exptypes.ExporterConfigEnvRemove:
+			names, err := parseEnvNames(val)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid %s", k)
+			}
+			p.envRemove = names
+		case exptypes.ExporterConfigEntrypoint:
+			args, err := parseCommand(val)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid %s", k)
+			}
+			p.entrypoint = args
+		case exptypes.ExporterConfigCmd:
+			args, err := parseCommand(val)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid %s", k)
+			}
+			p.cmd = args
+		case exptypes.ExporterConfigOS, exptypes.ExporterConfigArchitecture:
+			if err := validatePlatformField(val); err != nil {
+				return nil, errors.Wrapf(err, "invalid %s", k)
+			}
+			if k == exptypes.ExporterConfigOS {
+				p.os = &val
+			} else {
+				p.architecture = &val
+			}
+		case exptypes.ExporterConfigVariant:
+			// an empty variant removes the variant of the platform
+			if val != "" {
+				if err := validatePlatformField(val); err != nil {
+					return nil, errors.Wrapf(err, "invalid %s", k)
+				}
+			}
+			p.variant = &val
+		default:
+			continue
+		}
+		found = true
+	}
+	if !found {
+		return nil, nil
+	}
+	return &p, nil
+}
+
+// parseHealthcheckTest accepts either a JSON array in the format of the image
+// config, e.g. ["CMD", "curl", "-f", "http://localhost"], or a plain command
+// that is run with the default shell.
+func parseHealthcheckTest(v string) ([]string, error) {
+	if strings.HasPrefix(strings.TrimSpace(v), "[") {
+		var test []string
+		if err := json.Unmarshal([]byte(v), &test); err != nil {
+			return nil, errors.Wrap(err, "failed to parse healthcheck test")
+		}
+		if len(test) == 0 {
+			return nil, errors.New("empty healthcheck test")
+		}
+		switch test[0] {
+		case "NONE":
+			if len(test) != 1 {
+				return nil, errors.New("healthcheck test NONE takes no arguments")
+			}
+		case "CMD", "CMD-SHELL":
+			if len(test) == 1 {
+				return nil, errors.Errorf("healthcheck test %s requires a command", test[0])
+			}
+		default:
+			return nil, errors.Errorf("unknown healthcheck test type %q", test[0])
+		}
+		return test, nil
+	}
+	if strings.TrimSpace(v) == "" {
+		return nil, errors.New("empty healthcheck test")
+	}
+	return []string{"CMD-SHELL", v}, nil
+}
+
+// parseEnv accepts either a JSON array of KEY=VALUE pairs or a single pair
+func parseEnv(v string) ([]string, error) {
+	var env []string
+	if strings.HasPrefix(strings.TrimSpace(v), "[") {
+		if err := json.Unmarshal([]byte(v), &env); err != nil {
+			return nil, errors.Wrap(err, "failed to parse env")
+		}
+	} else {
+		env = []string{v}
+	}
+	for _, e := range env {
+		if strings.Index(e, "=") <= 0 {
+			return nil, errors.Errorf("env %q is not in KEY=VALUE format", e)
+		}
+	}
+	return env, nil
+}
+
+// parseEnvNames accepts a colon separated list of variable names. Commas
+// separate the options of --output, so they can't separate the names.
+func parseEnvNames(v string) ([]string, error) {
+	var names []string
+	for _, n := range strings.Split(v, ":") {
+		n = strings.TrimSpace(n)
+		if n == "" {
+			continue
+		}
+		if strings.ContainsAny(n, "=,") {
+			return nil, errors.Errorf("env name %q must not contain = or ,", n)
+		}
+		names = append(names, n)
+	}
+	if len(names) == 0 {
+		return nil, errors.New("no env names")
+	}
+	return names, nil
+}
+
+// parseCommand accepts either a JSON array of arguments or a plain command
+// that is run with /bin/sh -c.
+func parseCommand(v string) ([]string, error) {
+	if strings.HasPrefix(strings.TrimSpace(v), "[") {
+		var args []string
+		if err := json.Unmarshal([]byte(v), &args); err != nil {
+			return nil, errors.Wrap(err, "failed to parse command")
+		}
+		return args, nil
+	}
+	if strings.TrimSpace(v) == "" {
+		return nil, errors.New("empty command")
+	}
+	return []string{"/bin/sh", "-c", v}, nil
+}
+
+// mergeEnv sets the variables of add in env, replacing the existing values
+func mergeEnv(env, add []string) []string {
+	out := append([]string{}, env...)
+	for _, a := range add {
+		k := strings.SplitN(a, "=", 2)[0]
+		found := false
+		for i, e := range out {
+			if strings.SplitN(e, "=", 2)[0] == k {
+				out[i] = a
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// removeEnv removes the variables with the given names from env. Names that
+// are not set are ignored.
+func removeEnv(env, names []string) []string {
+	out := make([]string, 0, len(env))
+loop:
+	for _, e := range env {
+		k := strings.SplitN(e, "=", 2)[0]
+		for _, n := range names {
+			if k == n {
+				continue loop
+			}
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+func validatePlatformField(v string) error {
+	if v == "" {
+		return errors.New("empty value")
+	}
+	if strings.ContainsAny(v, "/, ") {
+		return errors.Errorf("%q is not a valid platform component", v)
+	}
+	return nil
+}
+
+// platform applies the os, architecture and variant overrides to the platform
+// of a manifest, so that it matches the patched image config. A variant is
+// only kept with an overridden architecture when it is set explicitly.
+func (p *configPatch) platform(pl ocispec.Platform) ocispec.Platform {
+	if p == nil {
+		return pl
+	}
+	if p.os != nil {
+		pl.OS = *p.os
+	}
+	if p.architecture != nil && *p.architecture != pl.Architecture {
+		pl.Architecture = *p.architecture
+		pl.Variant = ""
+	}
+	if p.variant != nil {
+		pl.Variant = *p.variant
+	}
+	return pl
+}
+
+// checkPlatforms returns an error if the platform is overridden for an index
+// of multiple platforms, as all the manifests would claim the same platform
+func (p *configPatch) checkPlatforms(n int) error {
+	if p == nil || n < 2 {
+		return nil
+	}
+	if p.os != nil || p.architecture != nil || p.variant != nil {
+		return errors.Errorf("%s, %s and %s are not supported for multi-platform images", exptypes.ExporterConfigOS, exptypes.ExporterConfigArchitecture, exptypes.ExporterConfigVariant)
+	}
+	return nil
+}
+
+func parseHealthcheckDuration(v string) (time.Duration, error) {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, err
+	}
+	if d < time.Millisecond {
+		return 0, errors.Errorf("duration %s must be at least 1ms", v)
+	}
+	return d, nil
+}
+
+func (p *configPatch) apply(dt []byte) ([]byte, error) {
+	if p == nil {
+		return dt, nil
+	}
+
+	m := map[string]json.RawMessage{}
+	if err := json.Unmarshal(dt, &m); err != nil {
+		return nil, errors.Wrap(err, "failed to parse image config for patch")
+	}
+	cfg := map[string]json.RawMessage{}
+	if v, ok := m["config"]; ok && string(v) != "null" {
+		if err := json.Unmarshal(v, &cfg); err != nil {
+			return nil, errors.Wrap(err, "failed to parse image config for patch")
+		}
+	}
+
+	if p.os != nil || p.architecture != nil || p.variant != nil {
+		var pl ocispec.Platform
+		for k, v := range map[string]*string{"os": &pl.OS, "architecture": &pl.Architecture, "variant": &pl.Variant} {
+			if dt, ok := m[k]; ok {
+				if err := json.Unmarshal(dt, v); err != nil {
+					return nil, errors.Wrapf(err, "failed to parse image %s", k)
+				}
+			}
+		}
+		pl = p.platform(pl)
+		for k, v := range map[string]string{"os": pl.OS, "architecture": pl.Architecture, "variant": pl.Variant} {
+			if v == "" {
+				delete(m, k)
+				continue
+			}
+			dt, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = dt
+		}
+	}
+
+	if p.stopSignal != "" {
+		v, err := json.Marshal(p.stopSignal)
+		if err != nil {
+			return nil, err
+		}
+		cfg["StopSignal"] = v
+	}
+
+	setString := func(k string, v *string) error {
+		if v == nil {
+			return nil
+		}
+		dt, err := json.Marshal(*v)
+		if err != nil {
+			return err
+		}
+		cfg[k] = dt
+		return nil
+	}
+	if err := setString("User", p.user); err != nil {
+		return nil, err
+	}
+	if err := setString("WorkingDir", p.workingDir); err != nil {
+		return nil, err
+	}
+
+	setArgs := func(k string, args []string) error {
+		if args == nil {
+			return nil
+		}
+		dt, err := json.Marshal(args)
+		if err != nil {
+			return err
+		}
+		cfg[k] = dt
+		return nil
+	}
+	if err := setArgs("Entrypoint", p.entrypoint); err != nil {
+		return nil, err
+	}
+	if err := setArgs("Cmd", p.cmd); err != nil {
+		return nil, err
+	}
+
+	if p.env != nil || p.envRemove != nil {
+		var env []string
+		v, ok := cfg["Env"]
+		if ok && string(v) != "null" {
+			if err := json.Unmarshal(v, &env); err != nil {
+				return nil, errors.Wrap(err, "failed to parse image env")
+			}
+		}
+		// variables removed from the base config can still be set again
+		// with config.env
+		env = removeEnv(env, p.envRemove)
+		if ok || p.env != nil {
+			if err := setArgs("Env", mergeEnv(env, p.env)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if p.test != nil || p.interval != nil || p.timeout != nil || p.startPeriod != nil || p.retries != nil {
+		var hc healthConfig
+		if v, ok := cfg["Healthcheck"]; ok && string(v) != "null" {
+			if err := json.Unmarshal(v, &hc); err != nil {
+				return nil, errors.Wrap(err, "failed to parse image healthcheck")
+			}
+		}
+		if p.test != nil {
+			hc.Test = p.test
+		}
+		if p.interval != nil {
+			hc.Interval = *p.interval
+		}
+		if p.timeout != nil {
+			hc.Timeout = *p.timeout
+		}
+		if p.startPeriod != nil {
+			hc.StartPeriod = *p.startPeriod
+		}
+		if p.retries != nil {
+			hc.Retries = *p.retries
+		}
+		if len(hc.Test) == 0 {
+			return nil, errors.New("healthcheck options require a healthcheck test")
+		}
+		v, err := json.Marshal(hc)
+		if err != nil {
+			return nil, err
+		}
+		cfg["Healthcheck"] = v
+	}
+
+	v, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	m["config"] = v
+
+	dt, err = json.Marshal(m)
+	return dt, errors.Wrap(err, "failed to marshal config after patch")
+}
@@ -0,0 +1,219 @@
+package containerimage
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/exporter/containerimage/exptypes"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigPatch(t *testing.T) {
+	t.Parallel()
+
+	p, err := parseConfigPatch(map[string][]byte{"foo": []byte("bar")})
+	require.NoError(t, err)
+	require.Nil(t, p)
+
+	dt, err := p.apply([]byte(`{"config":{"Env":["A=B"]}}`))
+	require.NoError(t, err)
+	require.Equal(t, `{"config":{"Env":["A=B"]}}`, string(dt))
+
+	p, err = parseConfigPatch(map[string][]byte{
+		exptypes.ExporterConfigStopSignal:          []byte("SIGKILL"),
+		exptypes.ExporterConfigHealthcheckTest:     []byte("curl -f http://localhost"),
+		exptypes.ExporterConfigHealthcheckInterval: []byte("10s"),
+		exptypes.ExporterConfigHealthcheckRetries:  []byte("3"),
+	})
+	require.NoError(t, err)
+
+	dt, err = p.apply([]byte(`{"config":{"Env":["A=B"],"Healthcheck":{"Test":["CMD","true"],"Timeout":5000000000}}}`))
+	require.NoError(t, err)
+
+	var img struct {
+		Config struct {
+			Env         []string
+			StopSignal  string
+			Healthcheck healthConfig
+		} `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal(dt, &img))
+	require.Equal(t, []string{"A=B"}, img.Config.Env)
+	require.Equal(t, "SIGKILL", img.Config.StopSignal)
+	require.Equal(t, healthConfig{
+		Test:     []string{"CMD-SHELL", "curl -f http://localhost"},
+		Interval: 10 * time.Second,
+		Timeout:  5 * time.Second,
+		Retries:  3,
+	}, img.Config.Healthcheck)
+
+	p, err = parseConfigPatch(map[string][]byte{
+		exptypes.ExporterConfigHealthcheckTest: []byte(`["CMD", "/bin/check"]`),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"CMD", "/bin/check"}, p.test)
+
+	p, err = parseConfigPatch(map[string][]byte{
+		exptypes.ExporterConfigHealthcheckInterval: []byte("1s"),
+	})
+	require.NoError(t, err)
+	_, err = p.apply([]byte(`{}`))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "require a healthcheck test")
+
+	for k, v := range map[string]string{
+		exptypes.ExporterConfigStopSignal:             "SIGFOO",
+		exptypes.ExporterConfigHealthcheckTest:        `["RUN", "true"]`,
+		exptypes.ExporterConfigHealthcheckTimeout:     "soon",
+		exptypes.ExporterConfigHealthcheckStartPeriod: "1ns",
+		exptypes.ExporterConfigHealthcheckRetries:     "0",
+	} {
+		_, err = parseConfigPatch(map[string][]byte{k: []byte(v)})
+		require.Error(t, err, k)
+		require.Contains(t, err.Error(), "invalid "+k)
+	}
+}
+
+func TestConfigPatchProcess(t *testing.T) {
+	t.Parallel()
+
+	p, err := parseConfigPatch(map[string][]byte{
+		exptypes.ExporterConfigUser:       []byte("1000:1000"),
+		exptypes.ExporterConfigWorkingDir: []byte("/app"),
+		exptypes.ExporterConfigEnv:        []byte(`["A=C","PATH=/app/bin","EMPTY="]`),
+		exptypes.ExporterConfigEntrypoint: []byte(`["/app/bin/server"]`),
+		exptypes.ExporterConfigCmd:        []byte("--listen :8080"),
+	})
+	require.NoError(t, err)
+
+	dt, err := p.apply([]byte(`{"config":{"Env":["A=B","HOME=/root"],"Cmd":["sh"]}}`))
+	require.NoError(t, err)
+
+	var img struct {
+		Config struct {
+			User       string
+			WorkingDir string
+			Env        []string
+			Entrypoint []string
+			Cmd        []string
+		} `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal(dt, &img))
+	require.Equal(t, "1000:1000", img.Config.User)
+	require.Equal(t, "/app", img.Config.WorkingDir)
+	require.Equal(t, []string{"A=C", "HOME=/root", "PATH=/app/bin", "EMPTY="}, img.Config.Env)
+	require.Equal(t, []string{"/app/bin/server"}, img.Config.Entrypoint)
+	require.Equal(t, []string{"/bin/sh", "-c", "--listen :8080"}, img.Config.Cmd)
+
+	p, err = parseConfigPatch(map[string][]byte{
+		exptypes.ExporterConfigEnv: []byte("FOO=bar=baz"),
+	})
+	require.NoError(t, err)
+	dt, err = p.apply([]byte(`{}`))
+	require.NoError(t, err)
+	require.Equal(t, `{"config":{"Env":["FOO=bar=baz"]}}`, string(dt))
+
+	for k, v := range map[string]string{
+		exptypes.ExporterConfigEnv:        "FOO",
+		exptypes.ExporterConfigEntrypoint: `["/bin/sh"`,
+		exptypes.ExporterConfigCmd:        " ",
+	} {
+		_, err = parseConfigPatch(map[string][]byte{k: []byte(v)})
+		require.Error(t, err, k)
+		require.Contains(t, err.Error(), "invalid "+k)
+	}
+}
+
+func TestConfigPatchEnvRemove(t *testing.T) {
+	t.Parallel()
+
+	p, err := parseConfigPatch(map[string][]byte{
+		exptypes.ExporterConfigEnvRemove: []byte("FOO: BAR:MISSING"),
+	})
+	require.NoError(t, err)
+
+	dt, err := p.apply([]byte(`{"config":{"Env":["FOO=1","PATH=/bin","BAR=2","FOOBAR=3"]}}`))
+	require.NoError(t, err)
+	require.Equal(t, `{"config":{"Env":["PATH=/bin","FOOBAR=3"]}}`, string(dt))
+
+	dt, err = p.apply([]byte(`{"config":{}}`))
+	require.NoError(t, err)
+	require.Equal(t, `{"config":{}}`, string(dt))
+
+	p, err = parseConfigPatch(map[string][]byte{
+		exptypes.ExporterConfigEnvRemove: []byte("FOO"),
+		exptypes.ExporterConfigEnv:       []byte("FOO=set"),
+	})
+	require.NoError(t, err)
+	dt, err = p.apply([]byte(`{"config":{"Env":["FOO=1","PATH=/bin"]}}`))
+	require.NoError(t, err)
+	require.Equal(t, `{"config":{"Env":["PATH=/bin","FOO=set"]}}`, string(dt))
+
+	for _, v := range []string{"", " : ", "FOO=1", "FOO,BAR"} {
+		_, err = parseConfigPatch(map[string][]byte{exptypes.ExporterConfigEnvRemove: []byte(v)})
+		require.Error(t, err, v)
+		require.Contains(t, err.Error(), "invalid "+exptypes.ExporterConfigEnvRemove)
+	}
+}
+
+func TestConfigPatchPlatform(t *testing.T) {
+	t.Parallel()
+
+	p, err := parseConfigPatch(map[string][]byte{
+		exptypes.ExporterConfigArchitecture: []byte("arm64"),
+	})
+	require.NoError(t, err)
+
+	dt, err := p.apply([]byte(`{"architecture":"arm","config":{},"os":"linux","variant":"v7"}`))
+	require.NoError(t, err)
+	require.Equal(t, `{"architecture":"arm64","config":{},"os":"linux"}`, string(dt))
+	require.Equal(t, ocispec.Platform{OS: "linux", Architecture: "arm64"}, p.platform(ocispec.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}))
+
+	// the variant is kept when the architecture doesn't change
+	dt, err = p.apply([]byte(`{"architecture":"arm64","config":{},"os":"linux","variant":"v8"}`))
+	require.NoError(t, err)
+	require.Equal(t, `{"architecture":"arm64","config":{},"os":"linux","variant":"v8"}`, string(dt))
+
+	p, err = parseConfigPatch(map[string][]byte{
+		exptypes.ExporterConfigOS:           []byte("linux"),
+		exptypes.ExporterConfigArchitecture: []byte("arm"),
+		exptypes.ExporterConfigVariant:      []byte("v6"),
+	})
+	require.NoError(t, err)
+
+	dt, err = p.apply([]byte(`{"architecture":"amd64","config":{},"os":"linux"}`))
+	require.NoError(t, err)
+	require.Equal(t, `{"architecture":"arm","config":{},"os":"linux","variant":"v6"}`, string(dt))
+	require.Equal(t, ocispec.Platform{OS: "linux", Architecture: "arm", Variant: "v6"}, p.platform(ocispec.Platform{OS: "linux", Architecture: "amd64"}))
+
+	p, err = parseConfigPatch(map[string][]byte{
+		exptypes.ExporterConfigVariant: []byte(""),
+	})
+	require.NoError(t, err)
+	dt, err = p.apply([]byte(`{"architecture":"arm","config":{},"os":"linux","variant":"v7"}`))
+	require.NoError(t, err)
+	require.Equal(t, `{"architecture":"arm","config":{},"os":"linux"}`, string(dt))
+
+	// all manifests of a multi-platform index would claim the same platform
+	require.NoError(t, p.checkPlatforms(1))
+	err = p.checkPlatforms(2)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not supported for multi-platform images")
+	p, err = parseConfigPatch(map[string][]byte{
+		exptypes.ExporterConfigUser: []byte("nobody"),
+	})
+	require.NoError(t, err)
+	require.NoError(t, p.checkPlatforms(2))
+
+	for k, v := range map[string]string{
+		exptypes.ExporterConfigOS:           "",
+		exptypes.ExporterConfigArchitecture: "linux/arm64",
+		exptypes.ExporterConfigVariant:      "v7,v8",
+	} {
+		_, err = parseConfigPatch(map[string][]byte{k: []byte(v)})
+		require.Error(t, err, k)
+		require.Contains(t, err.Error(), "invalid "+k)
+	}
+}
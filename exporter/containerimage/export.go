@@ -18,6 +18,7 @@ import (
 	"github.com/moby/buildkit/exporter"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/solver"
 	"github.com/moby/buildkit/util/compression"
 	"github.com/moby/buildkit/util/contentutil"
 	"github.com/moby/buildkit/util/leaseutil"
@@ -32,12 +33,19 @@ const (
 	keyImageName        = "name"
 	keyPush             = "push"
 	keyPushByDigest     = "push-by-digest"
+	keyVerify           = "verify"
 	keyInsecure         = "registry.insecure"
 	keyUnpack           = "unpack"
 	keyDanglingPrefix   = "dangling-name-prefix"
 	keyNameCanonical    = "name-canonical"
 	keyLayerCompression = "compression"
 	keyForceCompression = "force-compression"
+	keyDedupLayers      = "dedup-layers"
+	keyMaxLayers        = "max-layers"
+	keyRejectPerms      = "reject-insecure-perms"
+	keyPermsAllow       = "insecure-perms-allow"
+	keyLayerSizes       = "layer-sizes"
+	keyAttestReferrers  = "attest-referrers"
 	ociTypes            = "oci-mediatypes"
 )
 
@@ -92,6 +100,16 @@ func (e *imageExporter) Resolve(ctx context.Context, opt map[string]string) (exp
 				return nil, errors.Wrapf(err, "non-bool value specified for %s", k)
 			}
 			i.pushByDigest = b
+		case keyVerify:
+			if v == "" {
+				i.verify = true
+				continue
+			}
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "non-bool value specified for %s", k)
+			}
+			i.verify = b
 		case keyInsecure:
 			if v == "" {
 				i.insecure = true
@@ -135,14 +153,11 @@ func (e *imageExporter) Resolve(ctx context.Context, opt map[string]string) (exp
 			}
 			i.nameCanonical = b
 		case keyLayerCompression:
-			switch v {
-			case "gzip":
-				i.layerCompression = compression.Gzip
-			case "uncompressed":
-				i.layerCompression = compression.Uncompressed
-			default:
-				return nil, errors.Errorf("unsupported layer compression type: %v", v)
+			ct, err := ParseCompressionType(v)
+			if err != nil {
+				return nil, err
 			}
+			i.layerCompression = ct
 		case keyForceCompression:
 			if v == "" {
 				i.forceCompression = true
@@ -153,13 +168,82 @@ func (e *imageExporter) Resolve(ctx context.Context, opt map[string]string) (exp
 				return nil, errors.Wrapf(err, "non-bool value specified for %s", k)
 			}
 			i.forceCompression = b
+		case keyDedupLayers:
+			if v == "" {
+				i.dedupLayers = true
+				continue
+			}
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "non-bool value specified for %s", k)
+			}
+			i.dedupLayers = b
+		case keyMaxLayers:
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return nil, errors.Errorf("invalid value %q for %s, must be a positive integer", v, k)
+			}
+			i.maxLayers = n
+		case keyRejectPerms:
+			if v == "" {
+				i.rejectPerms = true
+				continue
+			}
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "non-bool value specified for %s", k)
+			}
+			i.rejectPerms = b
+		case keyPermsAllow:
+			patterns, err := parsePermsAllowlist(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid value for %s", k)
+			}
+			i.permsAllow = patterns
+		case keyLayerSizes:
+			if v == "" {
+				i.layerSizes = true
+				continue
+			}
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "non-bool value specified for %s", k)
+			}
+			i.layerSizes = b
+		case keyAttestReferrers:
+			if v == "" {
+				i.attestReferrers = true
+				continue
+			}
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "non-bool value specified for %s", k)
+			}
+			i.attestReferrers = b
 		default:
+			if idx, ct, ok, err := ParseLayerCompressionOpt(k, v); ok {
+				if err != nil {
+					return nil, err
+				}
+				if idx < 0 {
+					i.layerCompression = ct
+				} else {
+					if i.layerCompressionOverrides == nil {
+						i.layerCompressionOverrides = map[int]compression.Type{}
+					}
+					i.layerCompressionOverrides[idx] = ct
+				}
+				continue
+			}
 			if i.meta == nil {
 				i.meta = make(map[string][]byte)
 			}
 			i.meta[k] = []byte(v)
 		}
 	}
+	if i.attestReferrers && !i.push {
+		return nil, errors.Errorf("%s requires %s", keyAttestReferrers, keyPush)
+	}
 	return i, nil
 }
 
@@ -168,6 +252,7 @@ type imageExporterInstance struct {
 	targetName       string
 	push             bool
 	pushByDigest     bool
+	verify           bool
 	unpack           bool
 	insecure         bool
 	ociTypes         bool
@@ -175,7 +260,15 @@ type imageExporterInstance struct {
 	danglingPrefix   string
 	layerCompression compression.Type
 	forceCompression bool
+	dedupLayers      bool
+	maxLayers        int
+	rejectPerms      bool
+	permsAllow       []string
+	layerSizes       bool
+	attestReferrers  bool
 	meta             map[string][]byte
+
+	layerCompressionOverrides map[int]compression.Type
 }
 
 func (e *imageExporterInstance) Name() string {
@@ -196,7 +289,14 @@ func (e *imageExporterInstance) Export(ctx context.Context, src exporter.Source,
 	}
 	defer done(context.TODO())
 
-	desc, err := e.opt.ImageWriter.Commit(ctx, src, e.ociTypes, e.layerCompression, e.forceCompression, sessionID)
+	if e.rejectPerms {
+		permsDone := oneOffProgress(ctx, "checking file permissions")
+		if err := permsDone(checkInsecurePerms(ctx, src, e.permsAllow, session.NewGroup(sessionID))); err != nil {
+			return nil, err
+		}
+	}
+
+	desc, err := e.opt.ImageWriter.Commit(ctx, src, e.ociTypes, e.layerCompression, e.forceCompression, e.layerCompressionOverrides, e.dedupLayers, e.maxLayers, sessionID)
 	if err != nil {
 		return nil, err
 	}
@@ -254,7 +354,7 @@ func (e *imageExporterInstance) Export(ctx context.Context, src exporter.Source,
 				annotations := map[digest.Digest]map[string]string{}
 				mprovider := contentutil.NewMultiProvider(e.opt.ImageWriter.ContentStore())
 				if src.Ref != nil {
-					remote, err := src.Ref.GetRemote(ctx, false, e.layerCompression, e.forceCompression, session.NewGroup(sessionID))
+					remote, err := GetRemote(ctx, src.Ref, false, e.layerCompression, e.forceCompression, e.layerCompressionOverrides, session.NewGroup(sessionID))
 					if err != nil {
 						return nil, err
 					}
@@ -265,7 +365,7 @@ func (e *imageExporterInstance) Export(ctx context.Context, src exporter.Source,
 				}
 				if len(src.Refs) > 0 {
 					for _, r := range src.Refs {
-						remote, err := r.GetRemote(ctx, false, e.layerCompression, e.forceCompression, session.NewGroup(sessionID))
+						remote, err := GetRemote(ctx, r, false, e.layerCompression, e.forceCompression, e.layerCompressionOverrides, session.NewGroup(sessionID))
 						if err != nil {
 							return nil, err
 						}
@@ -279,6 +379,20 @@ func (e *imageExporterInstance) Export(ctx context.Context, src exporter.Source,
 				if err := push.Push(ctx, e.opt.SessionManager, sessionID, mprovider, e.opt.ImageWriter.ContentStore(), desc.Digest, targetName, e.insecure, e.opt.RegistryHosts, e.pushByDigest, annotations); err != nil {
 					return nil, err
 				}
+				if e.verify {
+					if err := push.Verify(ctx, e.opt.SessionManager, sessionID, desc.Digest, targetName, e.insecure, e.opt.RegistryHosts); err != nil {
+						return nil, errors.Wrapf(err, "failed to verify push of %s", targetName)
+					}
+				}
+				if e.attestReferrers {
+					attestations, err := imageAttestations(src, targetName, *desc)
+					if err != nil {
+						return nil, err
+					}
+					if err := push.PushReferrers(ctx, e.opt.SessionManager, sessionID, *desc, targetName, e.insecure, e.opt.RegistryHosts, attestations); err != nil {
+						return nil, errors.Wrapf(err, "failed to push attestations of %s", targetName)
+					}
+				}
 			}
 		}
 		resp["image.name"] = e.targetName
@@ -288,6 +402,11 @@ func (e *imageExporterInstance) Export(ctx context.Context, src exporter.Source,
 	if v, ok := desc.Annotations["config.digest"]; ok {
 		resp["containerimage.config.digest"] = v
 	}
+	if e.layerSizes {
+		if err := exporter.AddLayerSizes(ctx, resp, src, e.remoteFunc(session.NewGroup(sessionID))); err != nil {
+			return nil, err
+		}
+	}
 	return resp, nil
 }
 
@@ -318,7 +437,7 @@ func (e *imageExporterInstance) unpackImage(ctx context.Context, img images.Imag
 		}
 	}
 
-	remote, err := topLayerRef.GetRemote(ctx, true, e.layerCompression, e.forceCompression, s)
+	remote, err := GetRemote(ctx, topLayerRef, true, e.layerCompression, e.forceCompression, e.layerCompressionOverrides, s)
 	if err != nil {
 		return err
 	}
@@ -330,7 +449,20 @@ func (e *imageExporterInstance) unpackImage(ctx context.Context, img images.Imag
 		}
 	}
 
-	layers, err := getLayers(ctx, remote.Descriptors, manifest)
+	descs := remote.Descriptors
+	if e.maxLayers > 0 {
+		// merged layers only exist in the exported image
+		diffIDs, err := images.RootFS(ctx, contentStore, manifest.Config)
+		if err != nil {
+			return err
+		}
+		descs = make([]ocispec.Descriptor, len(diffIDs))
+		for i, diffID := range diffIDs {
+			descs[i].Annotations = map[string]string{"containerd.io/uncompressed": diffID.String()}
+		}
+	}
+
+	layers, err := getLayers(ctx, descs, manifest)
 	if err != nil {
 		return err
 	}
@@ -389,3 +521,10 @@ func addAnnotations(m map[digest.Digest]map[string]string, desc ocispec.Descript
 		a[k] = v
 	}
 }
+
+// remoteFunc returns the layers of a ref with the compression of the exporter
+func (e *imageExporterInstance) remoteFunc(s session.Group) exporter.RemoteFunc {
+	return func(ctx context.Context, ref cache.ImmutableRef) (*solver.Remote, error) {
+		return GetRemote(ctx, ref, true, e.layerCompression, e.forceCompression, e.layerCompressionOverrides, s)
+	}
+}
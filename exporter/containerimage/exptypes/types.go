@@ -1,6 +1,8 @@
 package exptypes
 
 import (
+	"encoding/json"
+
 	"github.com/opencontainers/go-digest"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 )
@@ -9,6 +11,48 @@ const ExporterImageConfigKey = "containerimage.config"
 const ExporterInlineCache = "containerimage.inlinecache"
 const ExporterPlatformsKey = "refs.platforms"
 
+// ExporterProvenanceKey is the metadata key of the JSON encoded provenance
+// predicate of the build
+const ExporterProvenanceKey = "buildkit.provenance"
+
+// ExporterAttestationPrefix is the prefix of the metadata keys of the
+// attestations returned by frontends, e.g. SBOMs. The values are JSON encoded
+// Attestations and the rest of the key is the name of the attestation.
+const ExporterAttestationPrefix = "attestation."
+
+// Attestation is an in-toto predicate about the exported image
+type Attestation struct {
+	PredicateType string          `json:"predicateType"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+// Exporter options with these prefixes set annotations on the exported image.
+// The rest of the key is used as the annotation name.
+const (
+	ExporterAnnotationPrefix         = "annotation."          // manifest
+	ExporterAnnotationManifestPrefix = "annotation-manifest." // manifest
+	ExporterAnnotationIndexPrefix    = "annotation-index."    // index
+)
+
+// Exporter options that override fields of the exported image config.
+const (
+	ExporterConfigStopSignal             = "config.stopsignal"
+	ExporterConfigHealthcheckTest        = "config.healthcheck.test"         // JSON array or shell command
+	ExporterConfigHealthcheckInterval    = "config.healthcheck.interval"     // duration
+	ExporterConfigHealthcheckTimeout     = "config.healthcheck.timeout"      // duration
+	ExporterConfigHealthcheckStartPeriod = "config.healthcheck.start-period" // duration
+	ExporterConfigHealthcheckRetries     = "config.healthcheck.retries"
+	ExporterConfigUser                   = "config.user"
+	ExporterConfigWorkingDir             = "config.workingdir"
+	ExporterConfigEnv                    = "config.env"        // JSON array or single KEY=VALUE
+	ExporterConfigEnvRemove              = "config.env-remove" // colon separated names
+	ExporterConfigEntrypoint             = "config.entrypoint" // JSON array or shell command
+	ExporterConfigCmd                    = "config.cmd"        // JSON array or shell command
+	ExporterConfigOS                     = "config.os"
+	ExporterConfigArchitecture           = "config.architecture"
+	ExporterConfigVariant                = "config.variant"
+)
+
 const EmptyGZLayer = digest.Digest("sha256:4f4fb700ef54461cfa02571ae0db9a0dc1e0cdb5577484a6d75e68dc38e8acc1")
 
 type Platforms struct {
@@ -0,0 +1,124 @@
+package containerimage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/containerd/containerd/content"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/solver/llbsolver"
+	"github.com/moby/buildkit/util/compression"
+	"github.com/moby/buildkit/util/contentutil"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	keyLayerCompressionPrefix  = "compression."
+	keyLayerCompressionDefault = "default"
+)
+
+// ParseCompressionType parses the value of a compression exporter option
+func ParseCompressionType(v string) (compression.Type, error) {
+	switch v {
+	case "gzip":
+		return compression.Gzip, nil
+	case "uncompressed":
+		return compression.Uncompressed, nil
+	default:
+		return 0, errors.Errorf("unsupported layer compression type: %v", v)
+	}
+}
+
+// ParseLayerCompressionOpt parses a compression.<index> exporter option that
+// overrides the compression of the layer at index, counting from the base
+// layer. The index is -1 for compression.default that sets the compression of
+// all other layers. ok is false if the key is not a layer compression option.
+func ParseLayerCompressionOpt(k, v string) (index int, ct compression.Type, ok bool, err error) {
+	if !strings.HasPrefix(k, keyLayerCompressionPrefix) {
+		return 0, 0, false, nil
+	}
+	idx := strings.TrimPrefix(k, keyLayerCompressionPrefix)
+	if idx == keyLayerCompressionDefault {
+		index = -1
+	} else {
+		index, err = strconv.Atoi(idx)
+		if err != nil || index < 0 {
+			return 0, 0, true, errors.Errorf("invalid layer index in %s", k)
+		}
+	}
+	ct, err = ParseCompressionType(v)
+	if err != nil {
+		return 0, 0, true, err
+	}
+	return index, ct, true, nil
+}
+
+// GetRemote returns the remote for ref with layers compressed with
+// compressionType, except for the layers that have their compression set in
+// layerCompression. Overrides for layers that don't exist in ref are ignored
+// with a warning of the build.
+func GetRemote(ctx context.Context, ref cache.ImmutableRef, createIfNeeded bool, compressionType compression.Type, forceCompression bool, layerCompression map[int]compression.Type, s session.Group) (*solver.Remote, error) {
+	remote, err := ref.GetRemote(ctx, createIfNeeded, compressionType, forceCompression, s)
+	if err != nil || len(layerCompression) == 0 || len(remote.Descriptors) == 0 {
+		return remote, err
+	}
+
+	// layers[i] is the ref whose top layer is remote.Descriptors[i]
+	layers := make([]cache.ImmutableRef, len(remote.Descriptors))
+	layers[len(layers)-1] = ref
+	for i := len(layers) - 2; i >= 0; i-- {
+		p := layers[i+1].Parent()
+		if p == nil {
+			return nil, errors.Errorf("invalid parent chain for %s", ref.ID())
+		}
+		defer p.Release(context.TODO())
+		layers[i] = p
+	}
+
+	mprovider := &overrideProvider{
+		MultiProvider: contentutil.NewMultiProvider(remote.Provider),
+		base:          remote.Provider,
+	}
+	for idx, ct := range layerCompression {
+		if idx >= len(layers) {
+			logrus.Warnf("ignoring compression override for layer %d of %s with %d layers", idx, ref.ID(), len(layers))
+			llbsolver.Warn(ctx, client.Warning{
+				Code:    "LayerCompressionIgnored",
+				Message: fmt.Sprintf("compression override for layer %d is ignored, the image has %d layers", idx, len(layers)),
+			})
+			continue
+		}
+		if ct == compressionType && forceCompression {
+			continue
+		}
+		r, err := layers[idx].GetRemote(ctx, createIfNeeded, ct, true, s)
+		if err != nil {
+			return nil, err
+		}
+		desc := r.Descriptors[len(r.Descriptors)-1]
+		mprovider.Add(desc.Digest, r.Provider)
+		remote.Descriptors[idx] = desc
+	}
+	remote.Provider = mprovider
+	return remote, nil
+}
+
+// overrideProvider keeps the base provider of the remote available for
+// unlazying
+type overrideProvider struct {
+	*contentutil.MultiProvider
+	base content.Provider
+}
+
+func (p *overrideProvider) Unlazy(ctx context.Context) error {
+	if unlazier, ok := p.base.(cache.Unlazier); ok {
+		return unlazier.Unlazy(ctx)
+	}
+	return nil
+}
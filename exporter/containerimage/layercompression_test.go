@@ -0,0 +1,38 @@
+package containerimage
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/util/compression"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLayerCompressionOpt(t *testing.T) {
+	idx, ct, ok, err := ParseLayerCompressionOpt("compression.0", "uncompressed")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 0, idx)
+	require.Equal(t, compression.Uncompressed, ct)
+
+	idx, ct, ok, err = ParseLayerCompressionOpt("compression.default", "gzip")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, -1, idx)
+	require.Equal(t, compression.Gzip, ct)
+
+	_, _, ok, err = ParseLayerCompressionOpt("compression-level", "1")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, _, ok, err = ParseLayerCompressionOpt("compression.foo", "gzip")
+	require.Error(t, err)
+	require.True(t, ok)
+
+	_, _, ok, err = ParseLayerCompressionOpt("compression.-1", "gzip")
+	require.Error(t, err)
+	require.True(t, ok)
+
+	_, _, ok, err = ParseLayerCompressionOpt("compression.1", "lz4")
+	require.Error(t, err)
+	require.True(t, ok)
+}
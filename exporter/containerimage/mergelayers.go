@@ -0,0 +1,457 @@
+package containerimage
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	ctdcompression "github.com/containerd/containerd/archive/compression"
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/labels"
+	"github.com/moby/buildkit/identity"
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/util/compression"
+	"github.com/moby/buildkit/util/contentutil"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	whiteoutPrefix = ".wh."
+	whiteoutOpaque = ".wh..wh..opq"
+)
+
+// layerGroup is a range of adjacent layers that are exported as one layer
+type layerGroup struct {
+	start, end int
+}
+
+// planLayerGroups groups adjacent layers, always merging the pair of groups
+// with the smallest total size, until there are at most maxLayers groups.
+// Non-distributable layers are never merged.
+func planLayerGroups(descs []ocispec.Descriptor, maxLayers int) ([]layerGroup, error) {
+	groups := make([]layerGroup, len(descs))
+	sizes := make([]int64, len(descs))
+	for i, desc := range descs {
+		groups[i] = layerGroup{start: i, end: i + 1}
+		sizes[i] = desc.Size
+	}
+	mergeable := func(g layerGroup) bool {
+		for _, desc := range descs[g.start:g.end] {
+			if images.IsNonDistributable(desc.MediaType) {
+				return false
+			}
+		}
+		return true
+	}
+	for len(groups) > maxLayers {
+		best := -1
+		for i := 0; i < len(groups)-1; i++ {
+			if !mergeable(groups[i]) || !mergeable(groups[i+1]) {
+				continue
+			}
+			if best == -1 || sizes[i]+sizes[i+1] < sizes[best]+sizes[best+1] {
+				best = i
+			}
+		}
+		if best == -1 {
+			return nil, errors.Errorf("cannot merge %d layers into %d: non-distributable layers can't be merged", len(descs), maxLayers)
+		}
+		groups[best].end = groups[best+1].end
+		sizes[best] += sizes[best+1]
+		groups = append(groups[:best+1], groups[best+2:]...)
+		sizes = append(sizes[:best+1], sizes[best+2:]...)
+	}
+	return groups, nil
+}
+
+// mergeLayers merges the smallest adjacent layers of remote until the image
+// has at most maxLayers layers. The history entries of the layers in a merged
+// group are replaced by a single entry that lists the commands of the group.
+func (ic *ImageWriter) mergeLayers(ctx context.Context, remote *solver.Remote, history []ocispec.History, maxLayers int, compressionType compression.Type, oci bool) (*solver.Remote, []ocispec.History, error) {
+	groups, err := planLayerGroups(remote.Descriptors, maxLayers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mergeDone := oneOffProgress(ctx, fmt.Sprintf("merging %d layers into %d", len(remote.Descriptors), len(groups)))
+	mprovider := contentutil.NewMultiProvider(remote.Provider)
+	descs := make([]ocispec.Descriptor, 0, len(groups))
+	for _, g := range groups {
+		if g.end-g.start == 1 {
+			descs = append(descs, remote.Descriptors[g.start])
+			continue
+		}
+		desc, err := ic.mergeLayerGroup(ctx, remote.Provider, remote.Descriptors[g.start:g.end], compressionType)
+		if err != nil {
+			return nil, nil, mergeDone(err)
+		}
+		mprovider.Add(desc.Digest, ic.opt.ContentStore)
+		descs = append(descs, *desc)
+	}
+	mergeDone(nil)
+
+	return &solver.Remote{
+		Descriptors: compression.ConvertAllLayerMediaTypes(oci, descs...),
+		Provider:    mprovider,
+	}, mergeHistory(history, groups), nil
+}
+
+// mergeHistory replaces the history entries of the layers in each group with
+// one entry. Entries of empty layers are kept.
+func mergeHistory(history []ocispec.History, groups []layerGroup) []ocispec.History {
+	out := make([]ocispec.History, 0, len(history))
+	var layerIndex, groupIndex int
+	var createdBy []string
+	for _, h := range history {
+		if h.EmptyLayer {
+			out = append(out, h)
+			continue
+		}
+		if groupIndex >= len(groups) {
+			out = append(out, h)
+			continue
+		}
+		g := groups[groupIndex]
+		if g.end-g.start == 1 {
+			out = append(out, h)
+		} else {
+			createdBy = append(createdBy, h.CreatedBy)
+			if layerIndex == g.end-1 {
+				h.CreatedBy = strings.Join(createdBy, "; ")
+				h.Comment = fmt.Sprintf("buildkit.exporter.image.v0: merged %d layers", len(createdBy))
+				out = append(out, h)
+				createdBy = nil
+			}
+		}
+		layerIndex++
+		if layerIndex == g.end {
+			groupIndex++
+		}
+	}
+	return out
+}
+
+// mergeLayerGroup writes a layer with the combined changes of descs to the
+// content store. The layers of the group can be compressed with any of the
+// algorithms that are detected from the blob data, including zstd, and the
+// merged layer is compressed with compressionType.
+func (ic *ImageWriter) mergeLayerGroup(ctx context.Context, provider content.Provider, descs []ocispec.Descriptor, compressionType compression.Type) (*ocispec.Descriptor, error) {
+	layers := make([]io.Reader, 0, len(descs))
+	for _, desc := range descs {
+		ra, err := provider.ReaderAt(ctx, desc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read layer %s", desc.Digest)
+		}
+		defer ra.Close()
+		rc, err := ctdcompression.DecompressStream(io.NewSectionReader(ra, 0, ra.Size()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decompress layer %s", desc.Digest)
+		}
+		defer rc.Close()
+		layers = append(layers, rc)
+	}
+
+	cs := ic.opt.ContentStore
+	w, err := cs.Writer(ctx, content.WithRef("merge-layers-"+identity.NewID()))
+	if err != nil {
+		return nil, err
+	}
+	defer w.Close()
+
+	var zw io.WriteCloser
+	var mediaType string
+	switch compressionType {
+	case compression.Uncompressed:
+		mediaType = ocispec.MediaTypeImageLayer
+	case compression.Gzip:
+		zw = gzip.NewWriter(w)
+		mediaType = ocispec.MediaTypeImageLayerGzip
+	default:
+		return nil, errors.Errorf("unsupported compression type %s for merged layers", compressionType)
+	}
+
+	diffID := digest.Canonical.Digester()
+	var out io.Writer = w
+	if zw != nil {
+		out = zw
+	}
+	if err := mergeTars(io.MultiWriter(out, diffID.Hash()), layers); err != nil {
+		return nil, err
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	labelz := map[string]string{
+		labels.LabelUncompressed: diffID.Digest().String(),
+	}
+	if err := w.Commit(ctx, 0, "", content.WithLabels(labelz)); err != nil && !errdefs.IsAlreadyExists(err) {
+		return nil, err
+	}
+	info, err := cs.Info(ctx, w.Digest())
+	if err != nil {
+		return nil, err
+	}
+
+	return &ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    info.Digest,
+		Size:      info.Size,
+		Annotations: map[string]string{
+			"containerd.io/uncompressed": diffID.Digest().String(),
+		},
+	}, nil
+}
+
+type mergeEntry struct {
+	hdr          *tar.Header
+	offset, size int64
+	// link is the entry of the same group that a hardlink points to
+	link *mergeEntry
+}
+
+// tarMerger computes the changes of a group of layers relative to the layers
+// below the group. File contents are buffered in a temporary file.
+type tarMerger struct {
+	tmp       *os.File
+	offset    int64
+	entries   map[string]*mergeEntry
+	whiteouts map[string]*tar.Header
+	opaques   map[string]*tar.Header
+}
+
+// mergeTars writes a layer tar to w that has the same effect as applying the
+// layer tars in order
+func mergeTars(w io.Writer, layers []io.Reader) error {
+	tmp, err := ioutil.TempFile("", "buildkit-merge-layers")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	m := &tarMerger{
+		tmp:       tmp,
+		entries:   map[string]*mergeEntry{},
+		whiteouts: map[string]*tar.Header{},
+		opaques:   map[string]*tar.Header{},
+	}
+	for _, l := range layers {
+		if err := m.add(l); err != nil {
+			return err
+		}
+	}
+	return m.write(w)
+}
+
+func cleanTarPath(p string) string {
+	return strings.TrimPrefix(path.Clean("/"+p), "/")
+}
+
+func (m *tarMerger) add(r io.Reader) error {
+	var entries []*mergeEntry
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to read layer tar")
+		}
+		e := &mergeEntry{hdr: hdr}
+		if hdr.Typeflag == tar.TypeReg || hdr.Typeflag == tar.TypeRegA || hdr.Typeflag == tar.TypeGNUSparse {
+			n, err := io.Copy(m.tmp, tr)
+			if err != nil {
+				return err
+			}
+			e.offset, e.size = m.offset, n
+			m.offset += n
+		}
+		entries = append(entries, e)
+	}
+
+	// whiteouts only apply to the layers below, so they are handled before
+	// the other changes of the layer
+	for _, e := range entries {
+		p := cleanTarPath(e.hdr.Name)
+		dir, base := path.Split(p)
+		dir = strings.TrimSuffix(dir, "/")
+		switch {
+		case base == whiteoutOpaque:
+			m.removeUnder(dir)
+			m.opaques[dir] = e.hdr
+		case strings.HasPrefix(base, whiteoutPrefix):
+			target := path.Join(dir, strings.TrimPrefix(base, whiteoutPrefix))
+			delete(m.entries, target)
+			delete(m.opaques, target)
+			m.removeUnder(target)
+			m.whiteouts[target] = e.hdr
+		}
+	}
+	for _, e := range entries {
+		p := cleanTarPath(e.hdr.Name)
+		if strings.HasPrefix(path.Base(p), whiteoutPrefix) {
+			continue
+		}
+		if e.hdr.Typeflag == tar.TypeDir {
+			_, removed := m.whiteouts[p]
+			if prev, ok := m.entries[p]; ok && prev.hdr.Typeflag != tar.TypeDir {
+				removed = true
+			}
+			if removed {
+				// a directory that was removed and created again must
+				// hide the contents of the layers below
+				delete(m.whiteouts, p)
+				m.opaques[p] = &tar.Header{Name: path.Join(p, whiteoutOpaque), Typeflag: tar.TypeReg, ModTime: e.hdr.ModTime}
+			}
+		} else {
+			delete(m.whiteouts, p)
+			delete(m.opaques, p)
+			m.removeUnder(p)
+		}
+		if e.hdr.Typeflag == tar.TypeLink {
+			if target, ok := m.entries[cleanTarPath(e.hdr.Linkname)]; ok {
+				if target.link != nil {
+					target = target.link
+				}
+				e.link = target
+			}
+		}
+		m.entries[p] = e
+	}
+	return nil
+}
+
+// removeUnder removes the changes to the children of p. An empty p is the
+// root directory.
+func (m *tarMerger) removeUnder(p string) {
+	under := func(k string) bool {
+		return p == "" && k != "" || strings.HasPrefix(k, p+"/")
+	}
+	for k := range m.entries {
+		if under(k) {
+			delete(m.entries, k)
+		}
+	}
+	for k := range m.whiteouts {
+		if under(k) {
+			delete(m.whiteouts, k)
+		}
+	}
+	for k := range m.opaques {
+		if under(k) {
+			delete(m.opaques, k)
+		}
+	}
+}
+
+func (m *tarMerger) write(w io.Writer) error {
+	type item struct {
+		name string
+		hdr  *tar.Header
+		e    *mergeEntry
+	}
+	var items, links, whiteouts []item
+	for p, e := range m.entries {
+		it := item{name: p, hdr: e.hdr, e: e}
+		if e.hdr.Typeflag == tar.TypeLink {
+			if e.link != nil && m.entries[cleanTarPath(e.hdr.Linkname)] != e.link {
+				// the target was replaced by a later layer of the group so
+				// the link becomes a copy of the original file
+				hdr := *e.link.hdr
+				hdr.Typeflag = tar.TypeReg
+				hdr.Linkname = ""
+				items = append(items, item{name: p, hdr: &hdr, e: e.link})
+				continue
+			}
+			// the targets of hardlinks need to exist when they are extracted
+			links = append(links, it)
+			continue
+		}
+		items = append(items, it)
+	}
+	// whiteouts are written last so that hardlinks to files of the layers
+	// below can still be created before the files are removed
+	for p, hdr := range m.whiteouts {
+		dir, base := path.Split(p)
+		whiteouts = append(whiteouts, item{name: path.Join(dir, whiteoutPrefix+base), hdr: hdr})
+	}
+	for p, hdr := range m.opaques {
+		items = append(items, item{name: path.Join(p, whiteoutOpaque), hdr: hdr})
+	}
+	less := func(s []item) func(i, j int) bool {
+		return func(i, j int) bool {
+			return comparePaths(s[i].name, s[j].name) < 0
+		}
+	}
+	sort.Slice(items, less(items))
+	sort.Slice(links, less(links))
+	sort.Slice(whiteouts, less(whiteouts))
+
+	tw := tar.NewWriter(w)
+	for _, it := range append(append(items, links...), whiteouts...) {
+		hdr := *it.hdr
+		hdr.Name = it.name
+		if hdr.Typeflag == tar.TypeDir {
+			hdr.Name += "/"
+			if it.name == "" {
+				hdr.Name = "./"
+			}
+		}
+		if hdr.Typeflag == tar.TypeGNUSparse {
+			hdr.Typeflag = tar.TypeReg
+		}
+		for k := range hdr.PAXRecords {
+			if strings.HasPrefix(k, "GNU.sparse.") {
+				delete(hdr.PAXRecords, k)
+			}
+		}
+		if it.e != nil && (hdr.Typeflag == tar.TypeReg || hdr.Typeflag == tar.TypeRegA) {
+			hdr.Size = it.e.size
+		} else {
+			hdr.Size = 0
+		}
+		if err := tw.WriteHeader(&hdr); err != nil {
+			return errors.Wrapf(err, "failed to write %s", hdr.Name)
+		}
+		if hdr.Size > 0 {
+			if _, err := io.Copy(tw, io.NewSectionReader(m.tmp, it.e.offset, it.e.size)); err != nil {
+				return err
+			}
+		}
+	}
+	return tw.Close()
+}
+
+// comparePaths orders paths so that directories come before their children
+func comparePaths(a, b string) int {
+	ac, bc := strings.Split(a, "/"), strings.Split(b, "/")
+	if a == "" {
+		ac = nil
+	}
+	if b == "" {
+		bc = nil
+	}
+	for i := 0; i < len(ac) && i < len(bc); i++ {
+		if c := strings.Compare(ac[i], bc[i]); c != 0 {
+			return c
+		}
+	}
+	return len(ac) - len(bc)
+}
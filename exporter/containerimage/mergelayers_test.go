@@ -0,0 +1,225 @@
+package containerimage
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	ctdcompression "github.com/containerd/containerd/archive/compression"
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/content/local"
+	"github.com/containerd/containerd/images"
+	"github.com/klauspost/compress/zstd"
+	"github.com/moby/buildkit/util/compression"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanLayerGroups(t *testing.T) {
+	t.Parallel()
+
+	descs := func(sizes ...int64) []ocispec.Descriptor {
+		var out []ocispec.Descriptor
+		for _, s := range sizes {
+			out = append(out, ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayerGzip, Size: s})
+		}
+		return out
+	}
+
+	groups, err := planLayerGroups(descs(100, 5, 3, 50, 2, 1), 3)
+	require.NoError(t, err)
+	require.Equal(t, []layerGroup{{0, 1}, {1, 3}, {3, 6}}, groups)
+
+	groups, err = planLayerGroups(descs(1, 2), 2)
+	require.NoError(t, err)
+	require.Equal(t, []layerGroup{{0, 1}, {1, 2}}, groups)
+
+	d := descs(1, 2, 3)
+	d[1].MediaType = images.MediaTypeDockerSchema2LayerForeignGzip
+	_, err = planLayerGroups(d, 2)
+	require.Error(t, err)
+
+	d[1].MediaType = "application/vnd.oci.image.layer.nondistributable.v1.tar+zstd"
+	_, err = planLayerGroups(d, 2)
+	require.Error(t, err)
+
+	d[1].MediaType = "application/vnd.oci.image.layer.v1.tar+zstd"
+	groups, err = planLayerGroups(d, 2)
+	require.NoError(t, err)
+	require.Equal(t, []layerGroup{{0, 2}, {2, 3}}, groups)
+}
+
+func TestMergeHistory(t *testing.T) {
+	t.Parallel()
+
+	history := []ocispec.History{
+		{CreatedBy: "base"},
+		{CreatedBy: "ENV A=1", EmptyLayer: true},
+		{CreatedBy: "RUN a"},
+		{CreatedBy: "RUN b"},
+		{CreatedBy: "CMD c", EmptyLayer: true},
+	}
+	out := mergeHistory(history, []layerGroup{{0, 1}, {1, 3}})
+	require.Equal(t, []ocispec.History{
+		{CreatedBy: "base"},
+		{CreatedBy: "ENV A=1", EmptyLayer: true},
+		{CreatedBy: "RUN a; RUN b", Comment: "buildkit.exporter.image.v0: merged 2 layers"},
+		{CreatedBy: "CMD c", EmptyLayer: true},
+	}, out)
+}
+
+type tarEntry struct {
+	name     string
+	typ      byte
+	data     string
+	linkname string
+}
+
+func writeTar(t *testing.T, entries ...tarEntry) io.Reader {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Typeflag: e.typ, Mode: 0644, Size: int64(len(e.data)), Linkname: e.linkname}
+		if e.typ == tar.TypeDir {
+			hdr.Mode = 0755
+		}
+		require.NoError(t, tw.WriteHeader(hdr))
+		_, err := tw.Write([]byte(e.data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf
+}
+
+func readTar(t *testing.T, r io.Reader) []tarEntry {
+	var out []tarEntry
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return out
+		}
+		require.NoError(t, err)
+		dt, err := ioutil.ReadAll(tr)
+		require.NoError(t, err)
+		out = append(out, tarEntry{name: hdr.Name, typ: hdr.Typeflag, data: string(dt), linkname: hdr.Linkname})
+	}
+}
+
+func TestMergeTars(t *testing.T) {
+	t.Parallel()
+
+	l1 := writeTar(t,
+		tarEntry{name: "a/", typ: tar.TypeDir},
+		tarEntry{name: "a/keep", typ: tar.TypeReg, data: "keep"},
+		tarEntry{name: "a/overwrite", typ: tar.TypeReg, data: "old"},
+		tarEntry{name: "a/removed", typ: tar.TypeReg, data: "removed"},
+		tarEntry{name: "b/", typ: tar.TypeDir},
+		tarEntry{name: "b/old", typ: tar.TypeReg, data: "old"},
+		tarEntry{name: "c/", typ: tar.TypeDir},
+		tarEntry{name: "c/file", typ: tar.TypeReg, data: "c"},
+		tarEntry{name: "c/link", typ: tar.TypeLink, linkname: "c/file"},
+		tarEntry{name: "d", typ: tar.TypeReg, data: "d"},
+	)
+	l2 := writeTar(t,
+		tarEntry{name: "a/", typ: tar.TypeDir},
+		tarEntry{name: "a/overwrite", typ: tar.TypeReg, data: "new"},
+		tarEntry{name: "a/.wh.removed", typ: tar.TypeReg},
+		tarEntry{name: "a/.wh.base", typ: tar.TypeReg},
+		tarEntry{name: "b/", typ: tar.TypeDir},
+		tarEntry{name: "b/new", typ: tar.TypeReg, data: "new"},
+		tarEntry{name: "b/.wh..wh..opq", typ: tar.TypeReg},
+		tarEntry{name: "c/", typ: tar.TypeDir},
+		tarEntry{name: "c/file", typ: tar.TypeReg, data: "c2"},
+		tarEntry{name: ".wh.d", typ: tar.TypeReg},
+	)
+	l3 := writeTar(t,
+		tarEntry{name: "d/", typ: tar.TypeDir},
+		tarEntry{name: "d/new", typ: tar.TypeReg, data: "d2"},
+	)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, mergeTars(buf, []io.Reader{l1, l2, l3}))
+
+	require.Equal(t, []tarEntry{
+		{name: "a/", typ: tar.TypeDir},
+		{name: "a/keep", typ: tar.TypeReg, data: "keep"},
+		{name: "a/overwrite", typ: tar.TypeReg, data: "new"},
+		{name: "b/", typ: tar.TypeDir},
+		{name: "b/.wh..wh..opq", typ: tar.TypeReg},
+		{name: "b/new", typ: tar.TypeReg, data: "new"},
+		{name: "c/", typ: tar.TypeDir},
+		{name: "c/file", typ: tar.TypeReg, data: "c2"},
+		// the link keeps the content of the file it was created for
+		{name: "c/link", typ: tar.TypeReg, data: "c"},
+		{name: "d/", typ: tar.TypeDir},
+		{name: "d/.wh..wh..opq", typ: tar.TypeReg},
+		{name: "d/new", typ: tar.TypeReg, data: "d2"},
+		{name: "a/.wh.base", typ: tar.TypeReg},
+		{name: "a/.wh.removed", typ: tar.TypeReg},
+	}, readTar(t, buf))
+}
+
+func TestMergeLayerGroupCompression(t *testing.T) {
+	t.Parallel()
+
+	tmpdir, err := ioutil.TempDir("", "mergelayers")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+	cs, err := local.NewStore(tmpdir)
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	writeLayer := func(mediaType string, compress func(io.Writer) io.WriteCloser, r io.Reader) ocispec.Descriptor {
+		buf := &bytes.Buffer{}
+		zw := compress(buf)
+		_, err := io.Copy(zw, r)
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+		desc := ocispec.Descriptor{MediaType: mediaType, Digest: digest.FromBytes(buf.Bytes()), Size: int64(buf.Len())}
+		require.NoError(t, content.WriteBlob(ctx, cs, desc.Digest.String(), bytes.NewReader(buf.Bytes()), desc))
+		return desc
+	}
+	gzipLayer := writeLayer(ocispec.MediaTypeImageLayerGzip, func(w io.Writer) io.WriteCloser {
+		return gzip.NewWriter(w)
+	}, writeTar(t,
+		tarEntry{name: "a", typ: tar.TypeReg, data: "gzip"},
+		tarEntry{name: "b", typ: tar.TypeReg, data: "b"},
+	))
+	zstdLayer := writeLayer("application/vnd.oci.image.layer.v1.tar+zstd", func(w io.Writer) io.WriteCloser {
+		zw, err := zstd.NewWriter(w)
+		require.NoError(t, err)
+		return zw
+	}, writeTar(t,
+		tarEntry{name: "a", typ: tar.TypeReg, data: "zstd"},
+	))
+
+	ic := &ImageWriter{opt: WriterOpt{ContentStore: cs}}
+	for _, ct := range []compression.Type{compression.Gzip, compression.Uncompressed} {
+		desc, err := ic.mergeLayerGroup(ctx, cs, []ocispec.Descriptor{gzipLayer, zstdLayer}, ct)
+		require.NoError(t, err)
+
+		ra, err := cs.ReaderAt(ctx, *desc)
+		require.NoError(t, err)
+		rc, err := ctdcompression.DecompressStream(content.NewReader(ra))
+		require.NoError(t, err)
+		require.Equal(t, []tarEntry{
+			{name: "a", typ: tar.TypeReg, data: "zstd"},
+			{name: "b", typ: tar.TypeReg, data: "b"},
+		}, readTar(t, rc))
+		rc.Close()
+		ra.Close()
+
+		if ct == compression.Gzip {
+			require.Equal(t, ocispec.MediaTypeImageLayerGzip, desc.MediaType)
+		} else {
+			require.Equal(t, ocispec.MediaTypeImageLayer, desc.MediaType)
+		}
+	}
+}
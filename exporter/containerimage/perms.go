@@ -0,0 +1,200 @@
+package containerimage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/containerd/continuity/fs"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/exporter"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/pkg/errors"
+)
+
+// maxReportedPerms is the number of files listed in the error of an export
+// that was rejected because of insecure permissions
+const maxReportedPerms = 20
+
+// parsePermsAllowlist parses a comma-separated list of glob patterns
+func parsePermsAllowlist(v string) ([]string, error) {
+	var patterns []string
+	for _, p := range strings.Split(v, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern %q", p)
+		}
+		patterns = append(patterns, path.Clean("/"+p))
+	}
+	return patterns, nil
+}
+
+// checkInsecurePerms returns an error listing the files of the exported
+// filesystems that are world-writable or have the setuid or setgid bit set,
+// unless their path matches one of the allowed patterns. Symlinks and
+// world-writable directories with the sticky bit, like /tmp, are allowed.
+// Only the files added or changed by the build are checked, the files of the
+// base image are not part of what the build produced.
+func checkInsecurePerms(ctx context.Context, src exporter.Source, allow []string, s session.Group) error {
+	refs := map[string]cache.ImmutableRef{}
+	if len(src.Refs) > 0 {
+		refs = src.Refs
+	} else if src.Ref != nil {
+		refs[""] = src.Ref
+	}
+
+	var found []string
+	for k, ref := range refs {
+		files, err := insecurePerms(ctx, ref, allow, s)
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			if k != "" {
+				f = k + ": " + f
+			}
+			found = append(found, f)
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+
+	sort.Strings(found)
+	n := len(found)
+	if n > maxReportedPerms {
+		found = append(found[:maxReportedPerms], fmt.Sprintf("and %d more", n-maxReportedPerms))
+	}
+	return errors.Errorf("image contains %d files with insecure permissions:\n%s", n, strings.Join(found, "\n"))
+}
+
+func insecurePerms(ctx context.Context, ref cache.ImmutableRef, allow []string, s session.Group) ([]string, error) {
+	var lowerRoot string
+	if base := baseImageRef(ref); base != nil {
+		defer base.Release(context.TODO())
+		if base.ID() == ref.ID() {
+			// the result is the base image
+			return nil, nil
+		}
+		lower, err := mountRef(ctx, base, s)
+		if err != nil {
+			return nil, err
+		}
+		defer lower.Unmount()
+		lowerRoot = lower.root
+	}
+	upper, err := mountRef(ctx, ref, s)
+	if err != nil {
+		return nil, err
+	}
+	defer upper.Unmount()
+	return findInsecurePerms(ctx, lowerRoot, upper.root, allow)
+}
+
+// baseImageRef returns the topmost layer of ref that, with all its parents,
+// was pulled from an image. It returns nil if the bottom layer was created by
+// the build.
+func baseImageRef(ref cache.ImmutableRef) cache.ImmutableRef {
+	chain := []cache.ImmutableRef{ref.Clone()}
+	for {
+		p := chain[len(chain)-1].Parent()
+		if p == nil {
+			break
+		}
+		chain = append(chain, p)
+	}
+	var base cache.ImmutableRef
+	for i := len(chain) - 1; i >= 0; i-- {
+		if len(cache.GetImageRefs(chain[i])) == 0 {
+			break
+		}
+		base = chain[i]
+	}
+	for _, r := range chain {
+		if r != base {
+			r.Release(context.TODO())
+		}
+	}
+	return base
+}
+
+type mountedRef struct {
+	root string
+	lm   snapshot.Mounter
+}
+
+func (m *mountedRef) Unmount() error {
+	return m.lm.Unmount()
+}
+
+func mountRef(ctx context.Context, ref cache.ImmutableRef, s session.Group) (*mountedRef, error) {
+	mount, err := ref.Mount(ctx, true, s)
+	if err != nil {
+		return nil, err
+	}
+	lm := snapshot.LocalMounter(mount)
+	root, err := lm.Mount()
+	if err != nil {
+		return nil, err
+	}
+	return &mountedRef{root: root, lm: lm}, nil
+}
+
+// findInsecurePerms returns the files with insecure permissions that were
+// added or changed in upper compared to lower. All files of upper are checked
+// if lower is empty.
+func findInsecurePerms(ctx context.Context, lower, upper string, allow []string) ([]string, error) {
+	var found []string
+	err := fs.Changes(ctx, lower, upper, func(kind fs.ChangeKind, p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if kind == fs.ChangeKindDelete || kind == fs.ChangeKindUnmodified || fi == nil {
+			return nil
+		}
+		mode := fi.Mode()
+		var reasons []string
+		switch {
+		case mode&os.ModeSymlink != 0:
+		case mode.IsDir():
+			if mode.Perm()&0002 != 0 && mode&os.ModeSticky == 0 {
+				reasons = append(reasons, "world-writable")
+			}
+		default:
+			if mode.Perm()&0002 != 0 {
+				reasons = append(reasons, "world-writable")
+			}
+		}
+		if mode&os.ModeSetuid != 0 {
+			reasons = append(reasons, "setuid")
+		}
+		if mode&os.ModeSetgid != 0 && !mode.IsDir() {
+			reasons = append(reasons, "setgid")
+		}
+		if len(reasons) == 0 {
+			return nil
+		}
+
+		rel := path.Join("/", filepath.ToSlash(p))
+		for _, pattern := range allow {
+			if ok, _ := path.Match(pattern, rel); ok {
+				return nil
+			}
+		}
+		found = append(found, fmt.Sprintf("%s (%s)", rel, strings.Join(reasons, ", ")))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check permissions")
+	}
+	sort.Strings(found)
+	return found, nil
+}
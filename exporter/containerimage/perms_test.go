@@ -0,0 +1,118 @@
+package containerimage
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindInsecurePerms(t *testing.T) {
+	t.Parallel()
+
+	tmpdir, err := ioutil.TempDir("", "perms")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	mkfile := func(p string, mode os.FileMode) {
+		p = filepath.Join(tmpdir, p)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, ioutil.WriteFile(p, nil, 0600))
+		require.NoError(t, os.Chmod(p, mode))
+	}
+	mkdir := func(p string, mode os.FileMode) {
+		p = filepath.Join(tmpdir, p)
+		require.NoError(t, os.MkdirAll(p, 0755))
+		require.NoError(t, os.Chmod(p, mode))
+	}
+
+	mkfile("etc/passwd", 0644)
+	mkfile("etc/shadow", 0666)
+	mkfile("usr/bin/sudo", 0755|os.ModeSetuid)
+	mkfile("usr/bin/passwd", 0755|os.ModeSetuid)
+	mkfile("usr/bin/wall", 0755|os.ModeSetgid)
+	mkdir("tmp", 0777|os.ModeSticky)
+	mkdir("data", 0777)
+	mkdir("shared", 0775|os.ModeSetgid)
+	require.NoError(t, os.Symlink("passwd", filepath.Join(tmpdir, "etc/link")))
+
+	found, err := findInsecurePerms(context.TODO(), "", tmpdir, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"/data (world-writable)",
+		"/etc/shadow (world-writable)",
+		"/usr/bin/passwd (setuid)",
+		"/usr/bin/sudo (setuid)",
+		"/usr/bin/wall (setgid)",
+	}, found)
+
+	allow, err := parsePermsAllowlist("usr/bin/pass*, /data")
+	require.NoError(t, err)
+	found, err = findInsecurePerms(context.TODO(), "", tmpdir, allow)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"/etc/shadow (world-writable)",
+		"/usr/bin/sudo (setuid)",
+		"/usr/bin/wall (setgid)",
+	}, found)
+
+	_, err = parsePermsAllowlist("/usr/bin/[")
+	require.Error(t, err)
+}
+
+func TestFindInsecurePermsChanges(t *testing.T) {
+	t.Parallel()
+
+	tmpdir, err := ioutil.TempDir("", "perms")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+	lower := filepath.Join(tmpdir, "lower")
+	upper := filepath.Join(tmpdir, "upper")
+
+	mkfile := func(root, p string, mode os.FileMode) {
+		p = filepath.Join(root, p)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, ioutil.WriteFile(p, nil, 0600))
+		require.NoError(t, os.Chmod(p, mode))
+	}
+	// the files of the base image are in both directories
+	for _, root := range []string{lower, upper} {
+		mkfile(root, "usr/bin/sudo", 0755|os.ModeSetuid)
+		mkfile(root, "etc/shadow", 0666)
+		mkfile(root, "etc/passwd", 0644)
+	}
+	// the build adds a setuid file and makes a file of the base world-writable
+	mkfile(upper, "usr/local/bin/tool", 0755|os.ModeSetuid)
+	require.NoError(t, os.Chmod(filepath.Join(upper, "etc/passwd"), 0666))
+
+	// unchanged files have the same times in both directories
+	tm := time.Now().Add(-time.Hour)
+	for _, root := range []string{lower, upper} {
+		require.NoError(t, filepath.Walk(root, func(p string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			return os.Chtimes(p, tm, tm)
+		}))
+	}
+
+	found, err := findInsecurePerms(context.TODO(), lower, upper, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"/etc/passwd (world-writable)",
+		"/usr/local/bin/tool (setuid)",
+	}, found)
+
+	found, err = findInsecurePerms(context.TODO(), "", upper, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"/etc/passwd (world-writable)",
+		"/etc/shadow (world-writable)",
+		"/usr/bin/sudo (setuid)",
+		"/usr/local/bin/tool (setuid)",
+	}, found)
+}
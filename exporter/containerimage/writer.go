@@ -19,6 +19,7 @@ import (
 	"github.com/moby/buildkit/snapshot"
 	"github.com/moby/buildkit/solver"
 	"github.com/moby/buildkit/util/compression"
+	"github.com/moby/buildkit/util/contentutil"
 	"github.com/moby/buildkit/util/progress"
 	"github.com/moby/buildkit/util/system"
 	digest "github.com/opencontainers/go-digest"
@@ -44,19 +45,35 @@ type ImageWriter struct {
 	opt WriterOpt
 }
 
-func (ic *ImageWriter) Commit(ctx context.Context, inp exporter.Source, oci bool, compressionType compression.Type, forceCompression bool, sessionID string) (*ocispec.Descriptor, error) {
+func (ic *ImageWriter) Commit(ctx context.Context, inp exporter.Source, oci bool, compressionType compression.Type, forceCompression bool, layerCompression map[int]compression.Type, dedup bool, maxLayers int, sessionID string) (*ocispec.Descriptor, error) {
 	platformsBytes, ok := inp.Metadata[exptypes.ExporterPlatformsKey]
 
 	if len(inp.Refs) > 0 && !ok {
 		return nil, errors.Errorf("unable to export multiple refs, missing platforms mapping")
 	}
 
+	ann, err := parseAnnotations(inp.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := parseConfigPatch(inp.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	if !oci && !ann.empty() {
+		return nil, errors.Errorf("annotations require oci-mediatypes")
+	}
+
 	if len(inp.Refs) == 0 {
-		remotes, err := ic.exportLayers(ctx, compressionType, forceCompression, session.NewGroup(sessionID), inp.Ref)
+		if len(ann.index) > 0 {
+			return nil, errors.Errorf("index annotations are not supported for single-platform images")
+		}
+		remotes, err := ic.exportLayers(ctx, compressionType, forceCompression, layerCompression, session.NewGroup(sessionID), inp.Ref)
 		if err != nil {
 			return nil, err
 		}
-		mfstDesc, configDesc, err := ic.commitDistributionManifest(ctx, inp.Ref, inp.Metadata[exptypes.ExporterImageConfigKey], &remotes[0], oci, inp.Metadata[exptypes.ExporterInlineCache])
+		config := inp.Metadata[exptypes.ExporterImageConfigKey]
+		mfstDesc, configDesc, err := ic.commitDistributionManifest(ctx, inp.Ref, config, &remotes[0], oci, inp.Metadata[exptypes.ExporterInlineCache], ann.forPlatform(configPlatform(config)), patch, maxLayers, compressionType)
 		if err != nil {
 			return nil, err
 		}
@@ -75,6 +92,9 @@ func (ic *ImageWriter) Commit(ctx context.Context, inp exporter.Source, oci bool
 	if len(p.Platforms) != len(inp.Refs) {
 		return nil, errors.Errorf("number of platforms does not match references %d %d", len(p.Platforms), len(inp.Refs))
 	}
+	if err := patch.checkPlatforms(len(p.Platforms)); err != nil {
+		return nil, err
+	}
 
 	refs := make([]cache.ImmutableRef, 0, len(inp.Refs))
 	remotesMap := make(map[string]int, len(inp.Refs))
@@ -83,10 +103,13 @@ func (ic *ImageWriter) Commit(ctx context.Context, inp exporter.Source, oci bool
 		refs = append(refs, r)
 	}
 
-	remotes, err := ic.exportLayers(ctx, compressionType, forceCompression, session.NewGroup(sessionID), refs...)
+	remotes, err := ic.exportLayers(ctx, compressionType, forceCompression, layerCompression, session.NewGroup(sessionID), refs...)
 	if err != nil {
 		return nil, err
 	}
+	if dedup {
+		dedupLayers(remotes)
+	}
 
 	idx := struct {
 		// MediaType is reserved in the OCI spec but
@@ -100,6 +123,7 @@ func (ic *ImageWriter) Commit(ctx context.Context, inp exporter.Source, oci bool
 			Versioned: specs.Versioned{
 				SchemaVersion: 2,
 			},
+			Annotations: ann.index,
 		},
 	}
 
@@ -116,11 +140,11 @@ func (ic *ImageWriter) Commit(ctx context.Context, inp exporter.Source, oci bool
 		}
 		config := inp.Metadata[fmt.Sprintf("%s/%s", exptypes.ExporterImageConfigKey, p.ID)]
 
-		desc, _, err := ic.commitDistributionManifest(ctx, r, config, &remotes[remotesMap[p.ID]], oci, inp.Metadata[fmt.Sprintf("%s/%s", exptypes.ExporterInlineCache, p.ID)])
+		desc, _, err := ic.commitDistributionManifest(ctx, r, config, &remotes[remotesMap[p.ID]], oci, inp.Metadata[fmt.Sprintf("%s/%s", exptypes.ExporterInlineCache, p.ID)], ann.forPlatform(p.Platform), patch, maxLayers, compressionType)
 		if err != nil {
 			return nil, err
 		}
-		dp := p.Platform
+		dp := patch.platform(platformWithConfig(p.Platform, config))
 		desc.Platform = &dp
 		idx.Manifests = append(idx.Manifests, *desc)
 
@@ -148,7 +172,7 @@ func (ic *ImageWriter) Commit(ctx context.Context, inp exporter.Source, oci bool
 	return &idxDesc, nil
 }
 
-func (ic *ImageWriter) exportLayers(ctx context.Context, compressionType compression.Type, forceCompression bool, s session.Group, refs ...cache.ImmutableRef) ([]solver.Remote, error) {
+func (ic *ImageWriter) exportLayers(ctx context.Context, compressionType compression.Type, forceCompression bool, layerCompression map[int]compression.Type, s session.Group, refs ...cache.ImmutableRef) ([]solver.Remote, error) {
 	eg, ctx := errgroup.WithContext(ctx)
 	layersDone := oneOffProgress(ctx, "exporting layers")
 
@@ -160,7 +184,7 @@ func (ic *ImageWriter) exportLayers(ctx context.Context, compressionType compres
 				return
 			}
 			eg.Go(func() error {
-				remote, err := ref.GetRemote(ctx, true, compressionType, forceCompression, s)
+				remote, err := GetRemote(ctx, ref, true, compressionType, forceCompression, layerCompression, s)
 				if err != nil {
 					return err
 				}
@@ -177,7 +201,52 @@ func (ic *ImageWriter) exportLayers(ctx context.Context, compressionType compres
 	return out, nil
 }
 
-func (ic *ImageWriter) commitDistributionManifest(ctx context.Context, ref cache.ImmutableRef, config []byte, remote *solver.Remote, oci bool, inlineCache []byte) (*ocispec.Descriptor, *ocispec.Descriptor, error) {
+// dedupLayers replaces the layers of the remotes that have the same
+// uncompressed digest as a layer of a previous remote with the blob of the
+// previous remote. Layers that were compressed differently for different
+// platforms then share a single blob in the manifests.
+func dedupLayers(remotes []solver.Remote) {
+	type blob struct {
+		desc     ocispec.Descriptor
+		provider content.Provider
+	}
+	blobs := map[digest.Digest]blob{}
+	for i := range remotes {
+		r := &remotes[i]
+		var mprovider *contentutil.MultiProvider
+		for j, desc := range r.Descriptors {
+			diffID := digest.Digest(desc.Annotations["containerd.io/uncompressed"])
+			if diffID == "" {
+				continue
+			}
+			b, ok := blobs[diffID]
+			if !ok {
+				blobs[diffID] = blob{desc: desc, provider: r.Provider}
+				continue
+			}
+			if b.desc.Digest == desc.Digest {
+				continue
+			}
+			// annotations are modified when the manifest is written so every
+			// descriptor needs its own copy
+			d := b.desc
+			d.Annotations = make(map[string]string, len(b.desc.Annotations))
+			for k, v := range b.desc.Annotations {
+				d.Annotations[k] = v
+			}
+			r.Descriptors[j] = d
+			if mprovider == nil {
+				mprovider = contentutil.NewMultiProvider(r.Provider)
+			}
+			mprovider.Add(d.Digest, b.provider)
+		}
+		if mprovider != nil {
+			r.Provider = mprovider
+		}
+	}
+}
+
+func (ic *ImageWriter) commitDistributionManifest(ctx context.Context, ref cache.ImmutableRef, config []byte, remote *solver.Remote, oci bool, inlineCache []byte, annotations map[string]string, patch *configPatch, maxLayers int, compressionType compression.Type) (*ocispec.Descriptor, *ocispec.Descriptor, error) {
 	if len(config) == 0 {
 		var err error
 		config, err = emptyImageConfig()
@@ -199,11 +268,23 @@ func (ic *ImageWriter) commitDistributionManifest(ctx context.Context, ref cache
 
 	remote, history = normalizeLayersAndHistory(remote, history, ref, oci)
 
+	if maxLayers > 0 && len(remote.Descriptors) > maxLayers {
+		remote, history, err = ic.mergeLayers(ctx, remote, history, maxLayers, compressionType, oci)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	config, err = patchImageConfig(config, remote.Descriptors, history, inlineCache)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	config, err = patch.apply(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var (
 		configDigest = digest.FromBytes(config)
 		manifestType = ocispec.MediaTypeImageManifest
@@ -233,6 +314,7 @@ func (ic *ImageWriter) commitDistributionManifest(ctx context.Context, ref cache
 				Size:      int64(len(config)),
 				MediaType: configType,
 			},
+			Annotations: annotations,
 		},
 	}
 
@@ -293,6 +375,153 @@ func (ic *ImageWriter) commitDistributionManifest(ctx context.Context, ref cache
 	}, &configDesc, nil
 }
 
+// platformWithConfig adds the platform fields that are only known from the
+// image config, like the os.version of Windows images, to the platform of a
+// manifest in the index.
+func platformWithConfig(p ocispec.Platform, config []byte) ocispec.Platform {
+	var img struct {
+		OS         string   `json:"os"`
+		OSVersion  string   `json:"os.version,omitempty"`
+		OSFeatures []string `json:"os.features,omitempty"`
+	}
+	if err := json.Unmarshal(config, &img); err != nil || img.OS != p.OS {
+		return p
+	}
+	if p.OSVersion == "" {
+		p.OSVersion = img.OSVersion
+	}
+	if len(p.OSFeatures) == 0 {
+		p.OSFeatures = img.OSFeatures
+	}
+	return p
+}
+
+// annotations are the annotations set with annotation exporter options
+type annotations struct {
+	index    map[string]string
+	manifest map[string]string
+	// platformManifest are the annotations of the manifests of a platform by
+	// the normalized platform
+	platformManifest map[string]map[string]string
+}
+
+func (a *annotations) empty() bool {
+	return len(a.index) == 0 && len(a.manifest) == 0 && len(a.platformManifest) == 0
+}
+
+// forPlatform returns the annotations of the manifest of platform p
+func (a *annotations) forPlatform(p ocispec.Platform) map[string]string {
+	pm := a.platformManifest[platforms.Format(platforms.Normalize(p))]
+	if len(pm) == 0 {
+		return a.manifest
+	}
+	m := make(map[string]string, len(a.manifest)+len(pm))
+	for k, v := range a.manifest {
+		m[k] = v
+	}
+	for k, v := range pm {
+		m[k] = v
+	}
+	return m
+}
+
+// parseAnnotations returns the annotations set with annotation exporter
+// options. The manifest annotations can be limited to a platform with
+// annotation[<platform>].<key> and annotation-manifest[<platform>].<key>.
+func parseAnnotations(meta map[string][]byte) (*annotations, error) {
+	a := &annotations{}
+	for k, v := range meta {
+		var index bool
+		var rest string
+		switch {
+		case strings.HasPrefix(k, strings.TrimSuffix(exptypes.ExporterAnnotationIndexPrefix, ".")):
+			index = true
+			rest = strings.TrimPrefix(k, strings.TrimSuffix(exptypes.ExporterAnnotationIndexPrefix, "."))
+		case strings.HasPrefix(k, strings.TrimSuffix(exptypes.ExporterAnnotationManifestPrefix, ".")):
+			rest = strings.TrimPrefix(k, strings.TrimSuffix(exptypes.ExporterAnnotationManifestPrefix, "."))
+		case strings.HasPrefix(k, strings.TrimSuffix(exptypes.ExporterAnnotationPrefix, ".")):
+			rest = strings.TrimPrefix(k, strings.TrimSuffix(exptypes.ExporterAnnotationPrefix, "."))
+		default:
+			continue
+		}
+		p, key, ok, err := parseAnnotationKey(rest)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid annotation option %s", k)
+		}
+		if !ok {
+			continue
+		}
+		switch {
+		case index && p != nil:
+			return nil, errors.Errorf("invalid annotation option %s: index annotations can't have a platform", k)
+		case index:
+			if a.index == nil {
+				a.index = map[string]string{}
+			}
+			a.index[key] = string(v)
+		case p != nil:
+			if a.platformManifest == nil {
+				a.platformManifest = map[string]map[string]string{}
+			}
+			pk := platforms.Format(platforms.Normalize(*p))
+			if a.platformManifest[pk] == nil {
+				a.platformManifest[pk] = map[string]string{}
+			}
+			a.platformManifest[pk][key] = string(v)
+		default:
+			if a.manifest == nil {
+				a.manifest = map[string]string{}
+			}
+			a.manifest[key] = string(v)
+		}
+	}
+	return a, nil
+}
+
+// parseAnnotationKey parses the part of an annotation option after its
+// prefix, either .<key> or [<platform>].<key>. ok is false for other options
+// with the same prefix.
+func parseAnnotationKey(s string) (p *ocispec.Platform, key string, ok bool, err error) {
+	switch {
+	case strings.HasPrefix(s, "."):
+		key = s[1:]
+	case strings.HasPrefix(s, "["):
+		i := strings.Index(s, "]")
+		if i < 0 {
+			return nil, "", true, errors.New("missing ] after the platform")
+		}
+		pp, err := platforms.Parse(s[1:i])
+		if err != nil {
+			return nil, "", true, err
+		}
+		p = &pp
+		if !strings.HasPrefix(s[i+1:], ".") {
+			return nil, "", true, errors.New("missing . after the platform")
+		}
+		key = s[i+2:]
+	default:
+		return nil, "", false, nil
+	}
+	if key == "" {
+		return nil, "", true, errors.New("empty annotation key")
+	}
+	return p, key, true, nil
+}
+
+// configPlatform returns the platform of an image config, or the default
+// platform for images without a config
+func configPlatform(config []byte) ocispec.Platform {
+	var img struct {
+		OS           string `json:"os"`
+		Architecture string `json:"architecture"`
+		Variant      string `json:"variant,omitempty"`
+	}
+	if err := json.Unmarshal(config, &img); err != nil || img.OS == "" {
+		return platforms.DefaultSpec()
+	}
+	return ocispec.Platform{OS: img.OS, Architecture: img.Architecture, Variant: img.Variant}
+}
+
 func (ic *ImageWriter) ContentStore() content.Store {
 	return ic.opt.ContentStore
 }
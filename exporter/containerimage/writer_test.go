@@ -0,0 +1,182 @@
+package containerimage
+
+import (
+	"testing"
+
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/platforms"
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/util/compression"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlatformWithConfig(t *testing.T) {
+	t.Parallel()
+
+	config := []byte(`{"architecture":"amd64","os":"windows","os.version":"10.0.17763.1879","os.features":["win32k"]}`)
+
+	p := platformWithConfig(ocispec.Platform{OS: "windows", Architecture: "amd64"}, config)
+	require.Equal(t, "10.0.17763.1879", p.OSVersion)
+	require.Equal(t, []string{"win32k"}, p.OSFeatures)
+
+	// values set in the platform are kept
+	p = platformWithConfig(ocispec.Platform{OS: "windows", Architecture: "amd64", OSVersion: "10.0.20348.1"}, config)
+	require.Equal(t, "10.0.20348.1", p.OSVersion)
+
+	p = platformWithConfig(ocispec.Platform{OS: "linux", Architecture: "amd64"}, config)
+	require.Equal(t, "", p.OSVersion)
+	require.Nil(t, p.OSFeatures)
+}
+
+func TestForeignLayerMediaTypes(t *testing.T) {
+	t.Parallel()
+
+	descs := []ocispec.Descriptor{
+		{MediaType: images.MediaTypeDockerSchema2LayerForeignGzip, URLs: []string{"https://example.com/layer"}},
+		{MediaType: images.MediaTypeDockerSchema2LayerForeignGzip},
+		{MediaType: images.MediaTypeDockerSchema2LayerGzip},
+	}
+
+	docker := compression.ConvertAllLayerMediaTypes(false, descs...)
+	require.Equal(t, images.MediaTypeDockerSchema2LayerForeignGzip, docker[0].MediaType)
+	require.Equal(t, images.MediaTypeDockerSchema2LayerGzip, docker[1].MediaType)
+	require.Equal(t, images.MediaTypeDockerSchema2LayerGzip, docker[2].MediaType)
+
+	oci := compression.ConvertAllLayerMediaTypes(true, descs...)
+	require.Equal(t, ocispec.MediaTypeImageLayerNonDistributableGzip, oci[0].MediaType)
+	require.Equal(t, []string{"https://example.com/layer"}, oci[0].URLs)
+	require.Equal(t, ocispec.MediaTypeImageLayerGzip, oci[1].MediaType)
+	require.Equal(t, ocispec.MediaTypeImageLayerGzip, oci[2].MediaType)
+}
+
+func TestDedupLayers(t *testing.T) {
+	t.Parallel()
+
+	layer := func(dgst, diffID string) ocispec.Descriptor {
+		return ocispec.Descriptor{
+			MediaType:   ocispec.MediaTypeImageLayerGzip,
+			Digest:      digest.Digest(dgst),
+			Annotations: map[string]string{"containerd.io/uncompressed": diffID},
+		}
+	}
+
+	remotes := []solver.Remote{
+		{Descriptors: []ocispec.Descriptor{layer("sha256:base-amd64", "sha256:diff-base-amd64"), layer("sha256:data1", "sha256:diff-data")}},
+		{Descriptors: []ocispec.Descriptor{layer("sha256:base-arm64", "sha256:diff-base-arm64"), layer("sha256:data2", "sha256:diff-data")}},
+	}
+	dedupLayers(remotes)
+
+	require.Equal(t, digest.Digest("sha256:base-amd64"), remotes[0].Descriptors[0].Digest)
+	require.Equal(t, digest.Digest("sha256:data1"), remotes[0].Descriptors[1].Digest)
+	require.Equal(t, digest.Digest("sha256:base-arm64"), remotes[1].Descriptors[0].Digest)
+	require.Equal(t, digest.Digest("sha256:data1"), remotes[1].Descriptors[1].Digest)
+
+	// the descriptors don't share annotations
+	delete(remotes[0].Descriptors[1].Annotations, "containerd.io/uncompressed")
+	require.Equal(t, "sha256:diff-data", remotes[1].Descriptors[1].Annotations["containerd.io/uncompressed"])
+}
+
+func TestParseAnnotations(t *testing.T) {
+	t.Parallel()
+
+	amd64 := ocispec.Platform{OS: "linux", Architecture: "amd64"}
+	arm64 := ocispec.Platform{OS: "linux", Architecture: "arm64"}
+	for _, tc := range []struct {
+		name        string
+		meta        map[string]string
+		index       map[string]string
+		amd64       map[string]string
+		arm64       map[string]string
+		expectedErr string
+	}{
+		{
+			name: "none",
+			meta: map[string]string{"name": "foo", "annotationfoo": "bar"},
+		},
+		{
+			name: "prefixes",
+			meta: map[string]string{
+				"annotation.foo":          "a",
+				"annotation-manifest.bar": "b",
+				"annotation-index.baz":    "c",
+			},
+			index: map[string]string{"baz": "c"},
+			amd64: map[string]string{"foo": "a", "bar": "b"},
+			arm64: map[string]string{"foo": "a", "bar": "b"},
+		},
+		{
+			name: "platform",
+			meta: map[string]string{
+				"annotation.foo":                       "a",
+				"annotation[linux/amd64].foo":          "b",
+				"annotation-manifest[linux/arm64].bar": "c",
+			},
+			amd64: map[string]string{"foo": "b"},
+			arm64: map[string]string{"foo": "a", "bar": "c"},
+		},
+		{
+			name:  "normalized platform",
+			meta:  map[string]string{"annotation[linux/x86_64].foo": "a"},
+			amd64: map[string]string{"foo": "a"},
+		},
+		{
+			name:        "empty key",
+			meta:        map[string]string{"annotation.": "a"},
+			expectedErr: "empty annotation key",
+		},
+		{
+			name:        "empty platform key",
+			meta:        map[string]string{"annotation-manifest[linux/amd64].": "a"},
+			expectedErr: "empty annotation key",
+		},
+		{
+			name:        "unterminated platform",
+			meta:        map[string]string{"annotation[linux/amd64.foo": "a"},
+			expectedErr: "missing ]",
+		},
+		{
+			name:        "missing dot",
+			meta:        map[string]string{"annotation[linux/amd64]foo": "a"},
+			expectedErr: "missing .",
+		},
+		{
+			name:        "invalid platform",
+			meta:        map[string]string{"annotation[linux/amd64/v8/foo].foo": "a"},
+			expectedErr: "linux/amd64/v8/foo",
+		},
+		{
+			name:        "index platform",
+			meta:        map[string]string{"annotation-index[linux/amd64].foo": "a"},
+			expectedErr: "index annotations can't have a platform",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			meta := map[string][]byte{}
+			for k, v := range tc.meta {
+				meta[k] = []byte(v)
+			}
+			a, err := parseAnnotations(meta)
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.index, a.index)
+			require.Equal(t, tc.amd64, a.forPlatform(amd64))
+			require.Equal(t, tc.arm64, a.forPlatform(arm64))
+		})
+	}
+}
+
+func TestConfigPlatform(t *testing.T) {
+	t.Parallel()
+
+	p := configPlatform([]byte(`{"architecture":"arm","os":"linux","variant":"v7"}`))
+	require.Equal(t, ocispec.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}, p)
+
+	require.Equal(t, platforms.DefaultSpec(), configPlatform(nil))
+}
@@ -19,4 +19,7 @@ type Source struct {
 	Ref      cache.ImmutableRef
 	Refs     map[string]cache.ImmutableRef
 	Metadata map[string][]byte
+	// ExporterResponses are the merged responses of the exporters that ran
+	// before this exporter in a build with more than one exporter
+	ExporterResponses map[string]string
 }
@@ -0,0 +1,133 @@
+package fsimage
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	units "github.com/docker/go-units"
+	"github.com/moby/buildkit/exporter"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/filesync"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/util/progress"
+	"github.com/pkg/errors"
+)
+
+const (
+	keyFormat = "format"
+	keySize   = "size"
+)
+
+type Opt struct {
+	SessionManager *session.Manager
+}
+
+type fsImageExporter struct {
+	opt Opt
+}
+
+// New returns an exporter that sends the result to the client as a squashfs
+// or ext4 filesystem image
+func New(opt Opt) (exporter.Exporter, error) {
+	return &fsImageExporter{opt: opt}, nil
+}
+
+func (e *fsImageExporter) Resolve(ctx context.Context, opt map[string]string) (exporter.ExporterInstance, error) {
+	i := &fsImageExporterInstance{fsImageExporter: e, format: FormatSquashfs}
+	for k, v := range opt {
+		switch k {
+		case keyFormat:
+			switch v {
+			case FormatSquashfs, FormatExt4:
+				i.format = v
+			default:
+				return nil, errors.Errorf("unsupported filesystem image format %q, expected %s or %s", v, FormatSquashfs, FormatExt4)
+			}
+		case keySize:
+			size, err := units.RAMInBytes(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid size %s", v)
+			}
+			i.size = size
+		}
+	}
+	if i.size != 0 && i.format != FormatExt4 {
+		return nil, errors.Errorf("%s is only supported for %s images", keySize, FormatExt4)
+	}
+	return i, nil
+}
+
+type fsImageExporterInstance struct {
+	*fsImageExporter
+	format string
+	size   int64
+}
+
+func (e *fsImageExporterInstance) Name() string {
+	return "exporting " + e.format + " image to client"
+}
+
+func (e *fsImageExporterInstance) Export(ctx context.Context, inp exporter.Source, sessionID string) (map[string]string, error) {
+	if len(inp.Refs) > 0 {
+		return nil, errors.New("fsimage exporter does not support multi-platform results")
+	}
+
+	tmpdir, err := ioutil.TempDir("", "buildkit-fsimage")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpdir)
+
+	src := filepath.Join(tmpdir, "rootfs")
+	if inp.Ref == nil {
+		if err := os.Mkdir(src, 0755); err != nil {
+			return nil, err
+		}
+	} else {
+		mount, err := inp.Ref.Mount(ctx, true, session.NewGroup(sessionID))
+		if err != nil {
+			return nil, err
+		}
+		lm := snapshot.LocalMounter(mount)
+		src, err = lm.Mount()
+		if err != nil {
+			return nil, err
+		}
+		defer lm.Unmount()
+	}
+
+	dest := filepath.Join(tmpdir, "image")
+	report := progress.OneOff(ctx, "creating "+e.format+" image")
+	if err := report(buildImage(ctx, e.format, src, dest, e.size)); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(dest)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	caller, err := e.opt.SessionManager.Get(timeoutCtx, sessionID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := filesync.CopyFileWriter(ctx, nil, caller)
+	if err != nil {
+		return nil, err
+	}
+	report = progress.OneOff(ctx, "sending "+e.format+" image")
+	if _, err := io.Copy(w, f); err != nil {
+		w.Close()
+		return nil, report(err)
+	}
+	return nil, report(w.Close())
+}
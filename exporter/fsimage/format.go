@@ -0,0 +1,80 @@
+package fsimage
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	FormatSquashfs = "squashfs"
+	FormatExt4     = "ext4"
+)
+
+// ext4UUID is used as the UUID and the directory hash seed of ext4 images so
+// that images of the same files are identical
+const ext4UUID = "6b2e0a3a-2ddc-4f5c-9b1c-0d3c4b7e1f20"
+
+// buildImage writes the files of src to a filesystem image of the format at
+// dest. The image is built with the mksquashfs or mkfs.ext4 tools of the host.
+func buildImage(ctx context.Context, format, src, dest string, size int64) error {
+	var cmd *exec.Cmd
+	switch format {
+	case FormatSquashfs:
+		// mksquashfs sorts the directory entries, the timestamps of the
+		// filesystem and of the files are reset to make the image reproducible
+		cmd = exec.CommandContext(ctx, "mksquashfs", src, dest, "-noappend", "-no-progress", "-mkfs-time", "0", "-all-time", "0")
+	case FormatExt4:
+		if size == 0 {
+			var err error
+			size, err = ext4Size(src)
+			if err != nil {
+				return err
+			}
+		}
+		f, err := os.Create(dest)
+		if err != nil {
+			return err
+		}
+		f.Close()
+		cmd = exec.CommandContext(ctx, "mkfs.ext4", "-q", "-F", "-d", src, "-U", ext4UUID, "-E", "hash_seed="+ext4UUID, dest, strconv.FormatInt(size/1024, 10)+"k")
+		cmd.Env = append(os.Environ(), "E2FSPROGS_FAKE_TIME=1")
+	default:
+		return errors.Errorf("unsupported filesystem image format %q", format)
+	}
+
+	if _, err := exec.LookPath(cmd.Path); err != nil {
+		return errors.Wrapf(err, "%s is required on the daemon host to export %s images", filepath.Base(cmd.Path), format)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "failed to create %s image: %s", format, out)
+	}
+	return nil
+}
+
+// ext4Size returns the size of an ext4 image that fits the files of src
+func ext4Size(src string) (int64, error) {
+	const blockSize = 4096
+	var size int64
+	err := filepath.Walk(src, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		// every inode may need a block for its data or the directory entries
+		size += blockSize
+		if fi.Mode().IsRegular() {
+			size += (fi.Size() + blockSize - 1) / blockSize * blockSize
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	// leave space for the metadata and the journal
+	size = size + size/4 + 32<<20
+	return (size + blockSize - 1) / blockSize * blockSize, nil
+}
@@ -0,0 +1,63 @@
+package fsimage
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildImage(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		format string
+		tool   string
+	}{
+		{FormatSquashfs, "mksquashfs"},
+		{FormatExt4, "mkfs.ext4"},
+	} {
+		tc := tc
+		t.Run(tc.format, func(t *testing.T) {
+			t.Parallel()
+			if _, err := exec.LookPath(tc.tool); err != nil {
+				t.Skipf("%s not found", tc.tool)
+			}
+
+			tmpdir, err := ioutil.TempDir("", "fsimage")
+			require.NoError(t, err)
+			defer os.RemoveAll(tmpdir)
+
+			src := filepath.Join(tmpdir, "src")
+			require.NoError(t, os.MkdirAll(filepath.Join(src, "etc"), 0755))
+			require.NoError(t, ioutil.WriteFile(filepath.Join(src, "etc/foo"), []byte("foo"), 0644))
+
+			ctx := context.TODO()
+			require.NoError(t, buildImage(ctx, tc.format, src, filepath.Join(tmpdir, "image1"), 0))
+
+			// the image doesn't depend on the build time
+			time.Sleep(1100 * time.Millisecond)
+			require.NoError(t, buildImage(ctx, tc.format, src, filepath.Join(tmpdir, "image2"), 0))
+
+			dt1, err := ioutil.ReadFile(filepath.Join(tmpdir, "image1"))
+			require.NoError(t, err)
+			dt2, err := ioutil.ReadFile(filepath.Join(tmpdir, "image2"))
+			require.NoError(t, err)
+			require.True(t, len(dt1) > 0)
+			require.True(t, bytes.Equal(dt1, dt2))
+		})
+	}
+}
+
+func TestBuildImageUnsupportedFormat(t *testing.T) {
+	t.Parallel()
+	err := buildImage(context.TODO(), "iso9660", "/", "/dev/null", 0)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported filesystem image format")
+}
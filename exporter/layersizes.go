@@ -0,0 +1,162 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	ctdcompression "github.com/containerd/containerd/archive/compression"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/util/compression"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// LayerSizesKey is the key of the exporter response that holds the JSON
+// encoded layer sizes of the exported result
+const LayerSizesKey = "layer.sizes"
+
+// LayerSize is the size of a layer of the exported result
+type LayerSize struct {
+	// Platform is the platform of the result the layer belongs to, set for
+	// multi-platform results
+	Platform         string        `json:"platform,omitempty"`
+	Digest           digest.Digest `json:"digest"`
+	MediaType        string        `json:"mediaType"`
+	DiffID           digest.Digest `json:"diffID"`
+	Size             int64         `json:"size"`
+	UncompressedSize int64         `json:"uncompressedSize"`
+	// Vertex is the digest of the vertex that created the layer. Layers of
+	// images use the vertex of the image source.
+	Vertex digest.Digest `json:"vertex,omitempty"`
+}
+
+// RemoteFunc returns the remote with the layer blobs of a ref
+type RemoteFunc func(context.Context, cache.ImmutableRef) (*solver.Remote, error)
+
+// DefaultRemote returns a RemoteFunc that creates the blobs of the layers of a
+// ref with the compression type if they don't exist yet
+func DefaultRemote(compressionType compression.Type, forceCompression bool, s session.Group) RemoteFunc {
+	return func(ctx context.Context, ref cache.ImmutableRef) (*solver.Remote, error) {
+		return ref.GetRemote(ctx, true, compressionType, forceCompression, s)
+	}
+}
+
+// LayerSizes returns the sizes of the layers of the refs of src, from the
+// base layer to the top layer of every ref. Layers are not merged or
+// deduplicated like in an image with a maximum number of layers.
+func LayerSizes(ctx context.Context, src Source, getRemote RemoteFunc) ([]LayerSize, error) {
+	if len(src.Refs) == 0 {
+		return layerSizes(ctx, "", src.Ref, getRemote)
+	}
+	keys := make([]string, 0, len(src.Refs))
+	for k := range src.Refs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var out []LayerSize
+	for _, k := range keys {
+		sizes, err := layerSizes(ctx, k, src.Refs[k], getRemote)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, sizes...)
+	}
+	return out, nil
+}
+
+// AddLayerSizes adds the JSON encoded layer sizes of src to the exporter
+// response resp
+func AddLayerSizes(ctx context.Context, resp map[string]string, src Source, getRemote RemoteFunc) error {
+	sizes, err := LayerSizes(ctx, src, getRemote)
+	if err != nil {
+		return errors.Wrap(err, "failed to compute layer sizes")
+	}
+	if sizes == nil {
+		sizes = []LayerSize{}
+	}
+	dt, err := json.Marshal(sizes)
+	if err != nil {
+		return err
+	}
+	resp[LayerSizesKey] = string(dt)
+	return nil
+}
+
+func layerSizes(ctx context.Context, platform string, ref cache.ImmutableRef, getRemote RemoteFunc) ([]LayerSize, error) {
+	if ref == nil {
+		return nil, nil
+	}
+	remote, err := getRemote(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	vertices := layerVertices(ref)
+	if len(vertices) != len(remote.Descriptors) {
+		return nil, errors.Errorf("invalid layer chain of %s: %d refs for %d layers", ref.ID(), len(vertices), len(remote.Descriptors))
+	}
+
+	out := make([]LayerSize, 0, len(remote.Descriptors))
+	for i, desc := range remote.Descriptors {
+		size, err := uncompressedSize(ctx, remote, i)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, LayerSize{
+			Platform:         platform,
+			Digest:           desc.Digest,
+			MediaType:        desc.MediaType,
+			DiffID:           digest.Digest(desc.Annotations["containerd.io/uncompressed"]),
+			Size:             desc.Size,
+			UncompressedSize: size,
+			Vertex:           vertices[i],
+		})
+	}
+	return out, nil
+}
+
+// layerVertices returns the vertices of the layers of ref, from the base layer
+// to the top layer. Layers without a recorded vertex, like the lower layers of
+// an image, use the vertex of the layer above them.
+func layerVertices(ref cache.ImmutableRef) []digest.Digest {
+	var out []digest.Digest
+	var vtx digest.Digest
+	for r := ref; r != nil; {
+		if v := cache.GetVertex(r); v != "" {
+			vtx = v
+		}
+		out = append(out, vtx)
+		p := r.Parent()
+		if r != ref {
+			r.Release(context.TODO())
+		}
+		r = p
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+func uncompressedSize(ctx context.Context, remote *solver.Remote, i int) (int64, error) {
+	desc := remote.Descriptors[i]
+	ra, err := remote.Provider.ReaderAt(ctx, desc)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to read layer %s", desc.Digest)
+	}
+	defer ra.Close()
+	rc, err := ctdcompression.DecompressStream(io.NewSectionReader(ra, 0, ra.Size()))
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to decompress layer %s", desc.Digest)
+	}
+	defer rc.Close()
+	n, err := io.Copy(ioutil.Discard, rc)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to decompress layer %s", desc.Digest)
+	}
+	return n, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"io/ioutil"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,13 +14,20 @@ import (
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/session/filesync"
 	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/util/compression"
 	"github.com/moby/buildkit/util/progress"
+	"github.com/pkg/errors"
 	"github.com/tonistiigi/fsutil"
 	fstypes "github.com/tonistiigi/fsutil/types"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/time/rate"
 )
 
+const (
+	keyResume     = "resume"
+	keyLayerSizes = "layer-sizes"
+)
+
 type Opt struct {
 	SessionManager *session.Manager
 }
@@ -35,11 +43,34 @@ func New(opt Opt) (exporter.Exporter, error) {
 }
 
 func (e *localExporter) Resolve(ctx context.Context, opt map[string]string) (exporter.ExporterInstance, error) {
-	return &localExporterInstance{localExporter: e}, nil
+	i := &localExporterInstance{localExporter: e}
+	for k, v := range opt {
+		switch k {
+		case keyResume:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "non-bool value specified for %s", k)
+			}
+			i.resume = b
+		case keyLayerSizes:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "non-bool value specified for %s", k)
+			}
+			i.layerSizes = b
+		}
+	}
+	return i, nil
 }
 
 type localExporterInstance struct {
 	*localExporter
+	// resume sends the digests of the files so that the files of an
+	// interrupted export with the same content are not sent again
+	resume bool
+	// layerSizes returns the sizes of the layers of the result as they would
+	// be exported to an image
+	layerSizes bool
 }
 
 func (e *localExporterInstance) Name() string {
@@ -56,6 +87,14 @@ func (e *localExporterInstance) Export(ctx context.Context, inp exporter.Source,
 		return nil, err
 	}
 
+	var resp map[string]string
+	if e.layerSizes {
+		resp = map[string]string{}
+		if err := exporter.AddLayerSizes(ctx, resp, inp, exporter.DefaultRemote(compression.Default, false, session.NewGroup(sessionID))); err != nil {
+			return nil, err
+		}
+	}
+
 	isMap := len(inp.Refs) > 0
 
 	export := func(ctx context.Context, k string, ref cache.ImmutableRef) func() error {
@@ -104,6 +143,10 @@ func (e *localExporterInstance) Export(ctx context.Context, inp exporter.Source,
 				}
 			}
 
+			if e.resume {
+				walkOpt.Map = filesync.WithContentDigests(src, walkOpt.Map)
+			}
+
 			fs := fsutil.NewFS(src, walkOpt)
 			lbl := "copying files"
 			if isMap {
@@ -138,7 +181,7 @@ func (e *localExporterInstance) Export(ctx context.Context, inp exporter.Source,
 	if err := eg.Wait(); err != nil {
 		return nil, err
 	}
-	return nil, nil
+	return resp, nil
 }
 
 func newProgressHandler(ctx context.Context, id string) func(int, bool) {
@@ -0,0 +1,154 @@
+package merkle
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/exporter"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/filesync"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/util/progress"
+)
+
+// ExporterResponseRoot is the key of the root digest of the tree in the
+// exporter response
+const ExporterResponseRoot = "merkle.root"
+
+type Opt struct {
+	SessionManager *session.Manager
+}
+
+type merkleExporter struct {
+	opt Opt
+}
+
+// New returns an exporter that sends a Merkle tree of the result to the client
+// as JSON instead of the files of the result
+func New(opt Opt) (exporter.Exporter, error) {
+	return &merkleExporter{opt: opt}, nil
+}
+
+func (e *merkleExporter) Resolve(ctx context.Context, opt map[string]string) (exporter.ExporterInstance, error) {
+	return &merkleExporterInstance{merkleExporter: e}, nil
+}
+
+type merkleExporterInstance struct {
+	*merkleExporter
+}
+
+func (e *merkleExporterInstance) Name() string {
+	return "exporting merkle tree to client"
+}
+
+func (e *merkleExporterInstance) Export(ctx context.Context, inp exporter.Source, sessionID string) (map[string]string, error) {
+	dirs, release, err := MountDirs(ctx, inp, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	report := progress.OneOff(ctx, "computing merkle tree")
+	tree, err := NewTree(dirs)
+	if err := report(err); err != nil {
+		return nil, err
+	}
+	dt, err := json.MarshalIndent(tree, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	caller, err := e.opt.SessionManager.Get(timeoutCtx, sessionID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := filesync.CopyFileWriter(ctx, nil, caller)
+	if err != nil {
+		return nil, err
+	}
+	report = progress.OneOff(ctx, "sending merkle tree")
+	if _, err := w.Write(dt); err != nil {
+		w.Close()
+		return nil, report(err)
+	}
+	if err := report(w.Close()); err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		ExporterResponseRoot: tree.Root.String(),
+	}, nil
+}
+
+// MountDirs mounts the results of inp read-only as the directories of a tree.
+// The results of multi-platform builds are named by their platform. The
+// returned function unmounts the directories.
+func MountDirs(ctx context.Context, inp exporter.Source, sessionID string) (_ []Dir, _ func(), rerr error) {
+	var defers []func()
+	release := func() {
+		for i := len(defers) - 1; i >= 0; i-- {
+			defers[i]()
+		}
+	}
+	defer func() {
+		if rerr != nil {
+			release()
+		}
+	}()
+
+	getDir := func(ctx context.Context, k string, ref cache.ImmutableRef) (*Dir, error) {
+		var src string
+		var err error
+		if ref == nil {
+			src, err = ioutil.TempDir("", "buildkit")
+			if err != nil {
+				return nil, err
+			}
+			defers = append(defers, func() { os.RemoveAll(src) })
+		} else {
+			mount, err := ref.Mount(ctx, true, session.NewGroup(sessionID))
+			if err != nil {
+				return nil, err
+			}
+
+			lm := snapshot.LocalMounter(mount)
+
+			src, err = lm.Mount()
+			if err != nil {
+				return nil, err
+			}
+
+			defers = append(defers, func() { lm.Unmount() })
+		}
+		return &Dir{
+			Name: strings.Replace(k, "/", "_", -1),
+			Path: src,
+		}, nil
+	}
+
+	var dirs []Dir
+	if len(inp.Refs) > 0 {
+		for k, ref := range inp.Refs {
+			d, err := getDir(ctx, k, ref)
+			if err != nil {
+				return nil, nil, err
+			}
+			dirs = append(dirs, *d)
+		}
+	} else {
+		d, err := getDir(ctx, "", inp.Ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		dirs = append(dirs, *d)
+	}
+	return dirs, release, nil
+}
@@ -0,0 +1,146 @@
+package merkle
+
+import (
+	"crypto/sha256"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+const (
+	TypeFile    = "file"
+	TypeDir     = "dir"
+	TypeSymlink = "symlink"
+	// TypeSpecial is the type of devices, sockets and named pipes
+	TypeSpecial = "special"
+)
+
+// Tree is a Merkle tree of a filesystem.
+//
+// The digest of a regular file is the sha256 of its contents, the digest of
+// a symlink is the sha256 of its target and special files have the digest of
+// empty content. The digest of a directory is the sha256 of the list of its
+// entries sorted by name, where every entry is encoded as
+// "<type> <digest> <name>\x00". Metadata like permissions, ownership and
+// timestamps are not part of the digests.
+type Tree struct {
+	Algorithm digest.Algorithm `json:"algorithm"`
+	// Root is the digest of the root directory
+	Root digest.Digest `json:"root"`
+	// Entries contains every path of the filesystem, starting with "/" for
+	// the root directory
+	Entries map[string]Entry `json:"entries"`
+}
+
+type Entry struct {
+	Type   string        `json:"type"`
+	Digest digest.Digest `json:"digest"`
+}
+
+// Dir is a directory that is added to the tree under Name. An empty Name adds
+// the directory as the root of the tree.
+type Dir struct {
+	Name string
+	Path string
+}
+
+// NewTree computes the Merkle tree of dirs. If a dir has a name, it is added
+// as a subdirectory of an otherwise empty root directory.
+func NewTree(dirs []Dir) (*Tree, error) {
+	t := &Tree{
+		Algorithm: digest.SHA256,
+		Entries:   map[string]Entry{},
+	}
+	if len(dirs) == 1 && dirs[0].Name == "" {
+		dgst, err := t.hashDir(dirs[0].Path, "/")
+		if err != nil {
+			return nil, err
+		}
+		t.Root = dgst
+		t.Entries["/"] = Entry{Type: TypeDir, Digest: dgst}
+		return t, nil
+	}
+
+	entries := make([]dirEntry, 0, len(dirs))
+	for _, d := range dirs {
+		if d.Name == "" {
+			return nil, errors.New("name is required for multiple directories")
+		}
+		dgst, err := t.hashDir(d.Path, "/"+d.Name)
+		if err != nil {
+			return nil, err
+		}
+		t.Entries["/"+d.Name] = Entry{Type: TypeDir, Digest: dgst}
+		entries = append(entries, dirEntry{name: d.Name, typ: TypeDir, digest: dgst})
+	}
+	t.Root = hashEntries(entries)
+	t.Entries["/"] = Entry{Type: TypeDir, Digest: t.Root}
+	return t, nil
+}
+
+type dirEntry struct {
+	name   string
+	typ    string
+	digest digest.Digest
+}
+
+func (t *Tree) hashDir(dir, p string) (digest.Digest, error) {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	entries := make([]dirEntry, 0, len(fis))
+	for _, fi := range fis {
+		fp := filepath.Join(dir, fi.Name())
+		ep := path.Join(p, fi.Name())
+		e := dirEntry{name: fi.Name()}
+		switch {
+		case fi.IsDir():
+			e.typ = TypeDir
+			e.digest, err = t.hashDir(fp, ep)
+		case fi.Mode()&os.ModeSymlink != 0:
+			e.typ = TypeSymlink
+			var target string
+			target, err = os.Readlink(fp)
+			e.digest = digest.FromString(target)
+		case fi.Mode().IsRegular():
+			e.typ = TypeFile
+			e.digest, err = hashFile(fp)
+		default:
+			e.typ = TypeSpecial
+			e.digest = digest.FromBytes(nil)
+		}
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to hash %s", ep)
+		}
+		t.Entries[ep] = Entry{Type: e.typ, Digest: e.digest}
+		entries = append(entries, e)
+	}
+	return hashEntries(entries), nil
+}
+
+func hashEntries(entries []dirEntry) digest.Digest {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+	h := sha256.New()
+	for _, e := range entries {
+		io.WriteString(h, e.typ+" "+e.digest.String()+" "+e.name+"\x00")
+	}
+	return digest.NewDigest(digest.SHA256, h)
+}
+
+func hashFile(p string) (digest.Digest, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return digest.SHA256.FromReader(f)
+}
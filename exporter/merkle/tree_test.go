@@ -0,0 +1,55 @@
+package merkle
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTree(t *testing.T) {
+	t.Parallel()
+
+	tmpdir, err := ioutil.TempDir("", "merkle")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpdir, "sub"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, "foo"), []byte("foo"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, "sub/bar"), []byte("bar"), 0755))
+	require.NoError(t, os.Symlink("../foo", filepath.Join(tmpdir, "sub/baz")))
+
+	tree, err := NewTree([]Dir{{Path: tmpdir}})
+	require.NoError(t, err)
+
+	foo := digest.FromString("foo")
+	bar := digest.FromString("bar")
+	baz := digest.FromString("../foo")
+	sub := digest.FromString("file " + bar.String() + " bar\x00symlink " + baz.String() + " baz\x00")
+	root := digest.FromString("file " + foo.String() + " foo\x00dir " + sub.String() + " sub\x00")
+
+	require.Equal(t, digest.SHA256, tree.Algorithm)
+	require.Equal(t, root, tree.Root)
+	require.Equal(t, map[string]Entry{
+		"/":        {Type: TypeDir, Digest: root},
+		"/foo":     {Type: TypeFile, Digest: foo},
+		"/sub":     {Type: TypeDir, Digest: sub},
+		"/sub/bar": {Type: TypeFile, Digest: bar},
+		"/sub/baz": {Type: TypeSymlink, Digest: baz},
+	}, tree.Entries)
+
+	// metadata is not part of the digests
+	require.NoError(t, os.Chmod(filepath.Join(tmpdir, "sub/bar"), 0600))
+	tree2, err := NewTree([]Dir{{Path: tmpdir}})
+	require.NoError(t, err)
+	require.Equal(t, tree, tree2)
+
+	tree, err = NewTree([]Dir{{Name: "linux_amd64", Path: tmpdir}, {Name: "linux_arm64", Path: filepath.Join(tmpdir, "sub")}})
+	require.NoError(t, err)
+	require.Equal(t, digest.FromString("dir "+root.String()+" linux_amd64\x00dir "+sub.String()+" linux_arm64\x00"), tree.Root)
+	require.Equal(t, Entry{Type: TypeFile, Digest: bar}, tree.Entries["/linux_arm64/bar"])
+	require.Equal(t, Entry{Type: TypeFile, Digest: bar}, tree.Entries["/linux_amd64/sub/bar"])
+}
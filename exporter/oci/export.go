@@ -13,7 +13,9 @@ import (
 	"github.com/moby/buildkit/exporter"
 	"github.com/moby/buildkit/exporter/containerimage"
 	"github.com/moby/buildkit/session"
+	sessioncontent "github.com/moby/buildkit/session/content"
 	"github.com/moby/buildkit/session/filesync"
+	"github.com/moby/buildkit/solver"
 	"github.com/moby/buildkit/util/compression"
 	"github.com/moby/buildkit/util/contentutil"
 	"github.com/moby/buildkit/util/grpcerrors"
@@ -33,6 +35,16 @@ const (
 	VariantDocker       = "docker"
 	ociTypes            = "oci-mediatypes"
 	keyForceCompression = "force-compression"
+	keyDedupLayers      = "dedup-layers"
+	keyMaxLayers        = "max-layers"
+	keyLayerSizes       = "layer-sizes"
+	// keyTar=false sends the blobs of the image to the content store of the
+	// client instead of a tarball
+	keyTar = "tar"
+
+	// exportStoreID is the ID of the session content store that receives the
+	// image when it is not exported as a tarball
+	exportStoreID = "export"
 )
 
 type Opt struct {
@@ -56,20 +68,18 @@ func (e *imageExporter) Resolve(ctx context.Context, opt map[string]string) (exp
 	i := &imageExporterInstance{
 		imageExporter:    e,
 		layerCompression: compression.Default,
+		tar:              true,
 	}
 	for k, v := range opt {
 		switch k {
 		case keyImageName:
 			i.name = v
 		case keyLayerCompression:
-			switch v {
-			case "gzip":
-				i.layerCompression = compression.Gzip
-			case "uncompressed":
-				i.layerCompression = compression.Uncompressed
-			default:
-				return nil, errors.Errorf("unsupported layer compression type: %v", v)
+			ct, err := containerimage.ParseCompressionType(v)
+			if err != nil {
+				return nil, err
 			}
+			i.layerCompression = ct
 		case keyForceCompression:
 			if v == "" {
 				i.forceCompression = true
@@ -80,6 +90,42 @@ func (e *imageExporter) Resolve(ctx context.Context, opt map[string]string) (exp
 				return nil, errors.Wrapf(err, "non-bool value specified for %s", k)
 			}
 			i.forceCompression = b
+		case keyDedupLayers:
+			if v == "" {
+				i.dedupLayers = true
+				continue
+			}
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "non-bool value specified for %s", k)
+			}
+			i.dedupLayers = b
+		case keyTar:
+			if v == "" {
+				i.tar = true
+				continue
+			}
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "non-bool value specified for %s", k)
+			}
+			i.tar = b
+		case keyMaxLayers:
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return nil, errors.Errorf("invalid value %q for %s, must be a positive integer", v, k)
+			}
+			i.maxLayers = n
+		case keyLayerSizes:
+			if v == "" {
+				i.layerSizes = true
+				continue
+			}
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "non-bool value specified for %s", k)
+			}
+			i.layerSizes = b
 		case ociTypes:
 			ot = new(bool)
 			if v == "" {
@@ -92,6 +138,20 @@ func (e *imageExporter) Resolve(ctx context.Context, opt map[string]string) (exp
 			}
 			*ot = b
 		default:
+			if idx, ct, ok, err := containerimage.ParseLayerCompressionOpt(k, v); ok {
+				if err != nil {
+					return nil, err
+				}
+				if idx < 0 {
+					i.layerCompression = ct
+				} else {
+					if i.layerCompressionOverrides == nil {
+						i.layerCompressionOverrides = map[int]compression.Type{}
+					}
+					i.layerCompressionOverrides[idx] = ct
+				}
+				continue
+			}
 			if i.meta == nil {
 				i.meta = make(map[string][]byte)
 			}
@@ -113,6 +173,12 @@ type imageExporterInstance struct {
 	ociTypes         bool
 	layerCompression compression.Type
 	forceCompression bool
+	dedupLayers      bool
+	maxLayers        int
+	tar              bool
+	layerSizes       bool
+
+	layerCompressionOverrides map[int]compression.Type
 }
 
 func (e *imageExporterInstance) Name() string {
@@ -137,7 +203,7 @@ func (e *imageExporterInstance) Export(ctx context.Context, src exporter.Source,
 	}
 	defer done(context.TODO())
 
-	desc, err := e.opt.ImageWriter.Commit(ctx, src, e.ociTypes, e.layerCompression, e.forceCompression, sessionID)
+	desc, err := e.opt.ImageWriter.Commit(ctx, src, e.ociTypes, e.layerCompression, e.forceCompression, e.layerCompressionOverrides, e.dedupLayers, e.maxLayers, sessionID)
 	if err != nil {
 		return nil, err
 	}
@@ -186,14 +252,9 @@ func (e *imageExporterInstance) Export(ctx context.Context, src exporter.Source,
 		return nil, err
 	}
 
-	w, err := filesync.CopyFileWriter(ctx, resp, caller)
-	if err != nil {
-		return nil, err
-	}
-
 	mprovider := contentutil.NewMultiProvider(e.opt.ImageWriter.ContentStore())
 	if src.Ref != nil {
-		remote, err := src.Ref.GetRemote(ctx, false, e.layerCompression, e.forceCompression, session.NewGroup(sessionID))
+		remote, err := containerimage.GetRemote(ctx, src.Ref, false, e.layerCompression, e.forceCompression, e.layerCompressionOverrides, session.NewGroup(sessionID))
 		if err != nil {
 			return nil, err
 		}
@@ -210,7 +271,7 @@ func (e *imageExporterInstance) Export(ctx context.Context, src exporter.Source,
 	}
 	if len(src.Refs) > 0 {
 		for _, r := range src.Refs {
-			remote, err := r.GetRemote(ctx, false, e.layerCompression, e.forceCompression, session.NewGroup(sessionID))
+			remote, err := containerimage.GetRemote(ctx, r, false, e.layerCompression, e.forceCompression, e.layerCompressionOverrides, session.NewGroup(sessionID))
 			if err != nil {
 				return nil, err
 			}
@@ -225,6 +286,33 @@ func (e *imageExporterInstance) Export(ctx context.Context, src exporter.Source,
 		}
 	}
 
+	if e.layerSizes {
+		if err := exporter.AddLayerSizes(ctx, resp, src, func(ctx context.Context, ref cache.ImmutableRef) (*solver.Remote, error) {
+			return containerimage.GetRemote(ctx, ref, true, e.layerCompression, e.forceCompression, e.layerCompressionOverrides, session.NewGroup(sessionID))
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if !e.tar {
+		report := oneOffProgress(ctx, "sending image to client content store")
+		store := sessioncontent.NewCallerStore(caller, exportStoreID)
+		return resp, report(contentutil.CopyChain(ctx, store, mprovider, *desc))
+	}
+
+	// the layer sizes are only returned in the response, they can be too large
+	// for the metadata of the tarball stream
+	md := make(map[string]string, len(resp))
+	for k, v := range resp {
+		if k != exporter.LayerSizesKey {
+			md[k] = v
+		}
+	}
+	w, err := filesync.CopyFileWriter(ctx, md, caller)
+	if err != nil {
+		return nil, err
+	}
+
 	report := oneOffProgress(ctx, "sending tarball")
 	if err := archiveexporter.Export(ctx, mprovider, w, expOpts...); err != nil {
 		w.Close()
@@ -0,0 +1,173 @@
+package provenance
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moby/buildkit/exporter"
+	"github.com/moby/buildkit/exporter/containerimage/exptypes"
+	"github.com/moby/buildkit/exporter/merkle"
+	tarexporter "github.com/moby/buildkit/exporter/tar"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/filesync"
+	"github.com/moby/buildkit/solver/llbsolver/provenance"
+	"github.com/moby/buildkit/util/progress"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+const keyBuilderID = "builder-id"
+
+// keys of the responses of the image, oci and docker exporters
+const (
+	keyImageDigest = "containerimage.digest"
+	keyImageName   = "image.name"
+)
+
+type Opt struct {
+	SessionManager *session.Manager
+}
+
+type provenanceExporter struct {
+	opt Opt
+}
+
+// New returns an exporter that sends the in-toto provenance statement of the
+// build to the client instead of the files of the result
+func New(opt Opt) (exporter.Exporter, error) {
+	return &provenanceExporter{opt: opt}, nil
+}
+
+func (e *provenanceExporter) Resolve(ctx context.Context, opt map[string]string) (exporter.ExporterInstance, error) {
+	i := &provenanceExporterInstance{provenanceExporter: e}
+	for k, v := range opt {
+		switch k {
+		case keyBuilderID:
+			i.builderID = v
+		}
+	}
+	return i, nil
+}
+
+type provenanceExporterInstance struct {
+	*provenanceExporter
+	builderID string
+}
+
+func (e *provenanceExporterInstance) Name() string {
+	return "exporting provenance to client"
+}
+
+func (e *provenanceExporterInstance) Export(ctx context.Context, inp exporter.Source, sessionID string) (map[string]string, error) {
+	dt, ok := inp.Metadata[exptypes.ExporterProvenanceKey]
+	if !ok {
+		return nil, errors.New("build provenance is not available")
+	}
+	var predicate provenance.Predicate
+	if err := json.Unmarshal(dt, &predicate); err != nil {
+		return nil, errors.Wrap(err, "failed to parse build provenance")
+	}
+	predicate.Builder.ID = e.builderID
+
+	subjects, ok := responseSubjects(inp.ExporterResponses)
+	if !ok {
+		dirs, release, err := merkle.MountDirs(ctx, inp, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		report := progress.OneOff(ctx, "computing subject digests")
+		subjects, err = dirSubjects(dirs)
+		if err := report(err); err != nil {
+			return nil, err
+		}
+	}
+
+	dt, err := json.MarshalIndent(provenance.Statement{
+		Type:          provenance.StatementType,
+		PredicateType: provenance.PredicateType,
+		Subject:       subjects,
+		Predicate:     predicate,
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	caller, err := e.opt.SessionManager.Get(timeoutCtx, sessionID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := filesync.CopyFileWriter(ctx, nil, caller)
+	if err != nil {
+		return nil, err
+	}
+	report := progress.OneOff(ctx, "sending provenance")
+	if _, err := w.Write(dt); err != nil {
+		w.Close()
+		return nil, report(err)
+	}
+	return nil, report(w.Close())
+}
+
+// responseSubjects returns the subjects of the outputs of the exporters that
+// ran before the provenance exporter in the same build
+func responseSubjects(resp map[string]string) ([]provenance.Subject, bool) {
+	var out []provenance.Subject
+	add := func(name, v string) {
+		dgst, err := digest.Parse(v)
+		if err != nil {
+			return
+		}
+		out = append(out, provenance.Subject{
+			Name:   name,
+			Digest: provenance.DigestSet{dgst.Algorithm().String(): dgst.Encoded()},
+		})
+	}
+	if v, ok := resp[keyImageDigest]; ok {
+		name := "image"
+		if names := resp[keyImageName]; names != "" {
+			name = strings.Split(names, ",")[0]
+		}
+		add(name, v)
+	}
+	if v, ok := resp[tarexporter.ExporterResponseDigest]; ok {
+		add("tar", v)
+	}
+	if v, ok := resp[merkle.ExporterResponseRoot]; ok {
+		add(".", v)
+	}
+	return out, len(out) > 0
+}
+
+// dirSubjects returns a subject for every directory of the result. The digest of
+// a subject is the digest of the root of the Merkle tree of the directory,
+// like returned by the merkle exporter.
+func dirSubjects(dirs []merkle.Dir) ([]provenance.Subject, error) {
+	out := make([]provenance.Subject, 0, len(dirs))
+	for _, d := range dirs {
+		tree, err := merkle.NewTree([]merkle.Dir{{Path: d.Path}})
+		if err != nil {
+			return nil, err
+		}
+		name := d.Name
+		if name == "" {
+			name = "."
+		}
+		out = append(out, provenance.Subject{
+			Name:   name,
+			Digest: provenance.DigestSet{tree.Root.Algorithm().String(): tree.Root.Encoded()},
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+	return out, nil
+}
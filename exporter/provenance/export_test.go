@@ -0,0 +1,33 @@
+package provenance
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/solver/llbsolver/provenance"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseSubjects(t *testing.T) {
+	t.Parallel()
+
+	imgDgst := digest.FromString("image")
+	tarDgst := digest.FromString("tar")
+
+	subjects, ok := responseSubjects(map[string]string{
+		"containerimage.digest": imgDgst.String(),
+		"image.name":            "docker.io/library/foo:latest,docker.io/library/foo:v1",
+		"tar.digest":            tarDgst.String(),
+	})
+	require.True(t, ok)
+	require.Equal(t, []provenance.Subject{
+		{Name: "docker.io/library/foo:latest", Digest: provenance.DigestSet{"sha256": imgDgst.Encoded()}},
+		{Name: "tar", Digest: provenance.DigestSet{"sha256": tarDgst.Encoded()}},
+	}, subjects)
+
+	// without an exporter before it the subjects are computed from the result
+	_, ok = responseSubjects(nil)
+	require.False(t, ok)
+	_, ok = responseSubjects(map[string]string{"tar.digest": "invalid"})
+	require.False(t, ok)
+}
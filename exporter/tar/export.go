@@ -2,8 +2,10 @@ package local
 
 import (
 	"context"
+	"io"
 	"io/ioutil"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,11 +15,20 @@ import (
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/session/filesync"
 	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/util/compression"
 	"github.com/moby/buildkit/util/progress"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
 	"github.com/tonistiigi/fsutil"
 	fstypes "github.com/tonistiigi/fsutil/types"
 )
 
+const keyLayerSizes = "layer-sizes"
+
+// ExporterResponseDigest is the key of the digest of the tarball in the
+// exporter response
+const ExporterResponseDigest = "tar.digest"
+
 type Opt struct {
 	SessionManager *session.Manager
 }
@@ -34,11 +45,24 @@ func New(opt Opt) (exporter.Exporter, error) {
 
 func (e *localExporter) Resolve(ctx context.Context, opt map[string]string) (exporter.ExporterInstance, error) {
 	li := &localExporterInstance{localExporter: e}
+	for k, v := range opt {
+		switch k {
+		case keyLayerSizes:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "non-bool value specified for %s", k)
+			}
+			li.layerSizes = b
+		}
+	}
 	return li, nil
 }
 
 type localExporterInstance struct {
 	*localExporter
+	// layerSizes returns the sizes of the layers of the result as they would
+	// be exported to an image
+	layerSizes bool
 }
 
 func (e *localExporterInstance) Name() string {
@@ -46,6 +70,13 @@ func (e *localExporterInstance) Name() string {
 }
 
 func (e *localExporterInstance) Export(ctx context.Context, inp exporter.Source, sessionID string) (map[string]string, error) {
+	resp := map[string]string{}
+	if e.layerSizes {
+		if err := exporter.AddLayerSizes(ctx, resp, inp, exporter.DefaultRemote(compression.Default, false, session.NewGroup(sessionID))); err != nil {
+			return nil, err
+		}
+	}
+
 	var defers []func()
 
 	defer func() {
@@ -145,11 +176,16 @@ func (e *localExporterInstance) Export(ctx context.Context, inp exporter.Source,
 		return nil, err
 	}
 	report := oneOffProgress(ctx, "sending tarball")
-	if err := fsutil.WriteTar(ctx, fs, w); err != nil {
+	dgstr := digest.Canonical.Digester()
+	if err := fsutil.WriteTar(ctx, fs, io.MultiWriter(w, dgstr.Hash())); err != nil {
 		w.Close()
 		return nil, report(err)
 	}
-	return nil, report(w.Close())
+	if err := report(w.Close()); err != nil {
+		return nil, err
+	}
+	resp[ExporterResponseDigest] = dgstr.Digest().String()
+	return resp, nil
 }
 
 func oneOffProgress(ctx context.Context, id string) func(err error) error {
@@ -56,6 +56,9 @@ const (
 	keySyntax                  = "build-arg:BUILDKIT_SYNTAX"
 	keyMultiPlatformArg        = "build-arg:BUILDKIT_MULTI_PLATFORM"
 	keyHostname                = "hostname"
+	keyCheck                   = "check"
+	keyCacheIgnoreArgs         = "cache-ignore-args"
+	keyTargetLabel             = "target-label"
 )
 
 var httpPrefix = regexp.MustCompile(`^https?://`)
@@ -129,6 +132,11 @@ func Build(ctx context.Context, c client.Client) (*client.Result, error) {
 		}
 	}
 
+	var cacheIgnoreArgs []string
+	if v := opts[keyCacheIgnoreArgs]; v != "" {
+		cacheIgnoreArgs = strings.Split(v, ",")
+	}
+
 	name := "load build definition from " + filename
 
 	filenames := []string{filename, filename + ".dockerignore"}
@@ -370,6 +378,16 @@ func Build(ctx context.Context, c client.Client) (*client.Result, error) {
 		return res, err
 	}
 
+	if v, ok := opts[keyCheck]; ok {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errors.Errorf("invalid boolean value %s", v)
+		}
+		if b {
+			return check(dtDockerfile, opts[keyTarget])
+		}
+	}
+
 	exportMap := len(targetPlatforms) > 1
 
 	if v := opts[keyMultiPlatformArg]; v != "" {
@@ -422,6 +440,11 @@ func Build(ctx context.Context, c client.Client) (*client.Result, error) {
 					LLBCaps:           &caps,
 					SourceMap:         sourceMap,
 					Hostname:          opts[keyHostname],
+					CacheIgnoreArgs:   cacheIgnoreArgs,
+					TargetLabel:       opts[keyTargetLabel],
+					Warn: func(w parser.Warning) {
+						warn(ctx, c, sourceMap, w)
+					},
 				})
 
 				if err != nil {
@@ -710,15 +733,40 @@ func wrapSource(err error, sm *llb.SourceMap, ranges []parser.Range) error {
 		return err
 	}
 	s := errdefs.Source{
-		Info: &pb.SourceInfo{
-			Data:       sm.Data,
-			Filename:   sm.Filename,
-			Definition: sm.Definition.ToPB(),
-		},
-		Ranges: make([]*pb.Range, 0, len(ranges)),
+		Info:   sourceInfo(sm),
+		Ranges: toPBRanges(ranges),
+	}
+	return errdefs.WithSource(err, s)
+}
+
+// warn records a warning of the Dockerfile. Warnings are only informational,
+// so the frontend doesn't fail when they can't be recorded, e.g. because the
+// daemon doesn't support them.
+func warn(ctx context.Context, c client.Client, sm *llb.SourceMap, w parser.Warning) {
+	opts := client.WarnOpts{
+		Code:   w.Code,
+		Detail: w.Detail,
+		URL:    w.URL,
+	}
+	if sm != nil {
+		opts.SourceInfo = sourceInfo(sm)
+		opts.Range = toPBRanges(w.Location)
+	}
+	c.Warn(ctx, "", w.Short, opts)
+}
+
+func sourceInfo(sm *llb.SourceMap) *pb.SourceInfo {
+	return &pb.SourceInfo{
+		Data:       sm.Data,
+		Filename:   sm.Filename,
+		Definition: sm.Definition.ToPB(),
 	}
+}
+
+func toPBRanges(ranges []parser.Range) []*pb.Range {
+	out := make([]*pb.Range, 0, len(ranges))
 	for _, r := range ranges {
-		s.Ranges = append(s.Ranges, &pb.Range{
+		out = append(out, &pb.Range{
 			Start: pb.Position{
 				Line:      int32(r.Start.Line),
 				Character: int32(r.Start.Character),
@@ -729,5 +777,5 @@ func wrapSource(err error, sm *llb.SourceMap, ranges []parser.Range) error {
 			},
 		})
 	}
-	return errdefs.WithSource(err, s)
+	return out
 }
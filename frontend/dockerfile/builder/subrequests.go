@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 
+	"github.com/moby/buildkit/frontend/dockerfile/dockerfile2llb"
 	"github.com/moby/buildkit/frontend/gateway/client"
 	"github.com/moby/buildkit/frontend/subrequests"
 	"github.com/moby/buildkit/solver/errdefs"
@@ -37,3 +38,21 @@ func describe() (*client.Result, error) {
 	}
 	return res, nil
 }
+
+// check validates the Dockerfile without building it and returns the found
+// problems as JSON in the result metadata
+func check(dt []byte, target string) (*client.Result, error) {
+	diags := dockerfile2llb.Check(dt, target)
+	if diags == nil {
+		diags = []dockerfile2llb.Diagnostic{}
+	}
+	dt, err := json.MarshalIndent(diags, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	res := client.NewResult()
+	res.Metadata = map[string][]byte{
+		"result.json": dt,
+	}
+	return res, nil
+}
@@ -0,0 +1,108 @@
+package dockerfile2llb
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	"github.com/pkg/errors"
+)
+
+// Diagnostic is a problem found in a Dockerfile by Check
+type Diagnostic struct {
+	Message   string `json:"message"`
+	Line      int    `json:"line,omitempty"`
+	Column    int    `json:"column,omitempty"`
+	EndLine   int    `json:"endLine,omitempty"`
+	EndColumn int    `json:"endColumn,omitempty"`
+}
+
+// Check validates a Dockerfile without converting it to LLB. It reports
+// syntax errors, unknown instructions and references to stages that don't
+// exist.
+func Check(dt []byte, target string) []Diagnostic {
+	if len(dt) == 0 {
+		return []Diagnostic{{Message: "the Dockerfile cannot be empty"}}
+	}
+
+	dockerfile, err := parser.Parse(bytes.NewReader(dt))
+	if err != nil {
+		return []Diagnostic{newDiagnostic(err, nil)}
+	}
+
+	stages, _, err := instructions.Parse(dockerfile.AST)
+	if err != nil {
+		return []Diagnostic{newDiagnostic(err, nil)}
+	}
+
+	var out []Diagnostic
+
+	names := map[string]int{}
+	for i, st := range stages {
+		if st.Name == "" {
+			continue
+		}
+		name := strings.ToLower(st.Name)
+		if _, ok := names[name]; ok {
+			out = append(out, newDiagnostic(errors.Errorf("duplicate stage name %q", st.Name), st.Location))
+			continue
+		}
+		names[name] = i
+	}
+
+	checkFrom := func(i int, from string, loc []parser.Range) {
+		if from == "" {
+			return
+		}
+		index, err := strconv.Atoi(from)
+		if err != nil {
+			if idx, ok := names[strings.ToLower(from)]; ok && idx == i {
+				out = append(out, newDiagnostic(errors.Errorf("stage %q cannot refer to itself", from), loc))
+			}
+			return
+		}
+		if index < 0 || index >= len(stages) {
+			out = append(out, newDiagnostic(errors.Errorf("invalid stage index %d", index), loc))
+		} else if index >= i {
+			out = append(out, newDiagnostic(errors.Errorf("stage index %d refers to the current or a later stage", index), loc))
+		}
+	}
+
+	for i, st := range stages {
+		for _, cmd := range st.Commands {
+			switch c := cmd.(type) {
+			case *instructions.CopyCommand:
+				checkFrom(i, c.From, c.Location())
+			case *instructions.RunCommand:
+				for _, m := range instructions.GetMounts(c) {
+					checkFrom(i, m.From, c.Location())
+				}
+			}
+		}
+	}
+
+	if target != "" {
+		if _, ok := names[strings.ToLower(target)]; !ok {
+			out = append(out, Diagnostic{Message: errors.Errorf("target stage %s could not be found", target).Error()})
+		}
+	}
+
+	return out
+}
+
+func newDiagnostic(err error, loc []parser.Range) Diagnostic {
+	var el *parser.ErrorLocation
+	if errors.As(err, &el) && len(el.Location) > 0 {
+		loc = el.Location
+	}
+	d := Diagnostic{Message: err.Error()}
+	if len(loc) > 0 {
+		d.Line = loc[0].Start.Line
+		d.Column = loc[0].Start.Character
+		d.EndLine = loc[len(loc)-1].End.Line
+		d.EndColumn = loc[len(loc)-1].End.Character
+	}
+	return d
+}
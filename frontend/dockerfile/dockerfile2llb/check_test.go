@@ -0,0 +1,51 @@
+package dockerfile2llb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	df := `FROM busybox AS base
+RUN true
+FROM base AS build
+COPY --from=0 /foo /foo
+RUN --mount=from=base,target=/mnt true
+`
+	require.Empty(t, Check([]byte(df), ""))
+	require.Empty(t, Check([]byte(df), "build"))
+
+	diags := Check([]byte(df), "nosuch")
+	require.Len(t, diags, 1)
+	require.Equal(t, "target stage nosuch could not be found", diags[0].Message)
+
+	df = `FROM busybox
+FOO bar
+`
+	diags = Check([]byte(df), "")
+	require.Len(t, diags, 1)
+	require.Contains(t, diags[0].Message, "unknown instruction")
+	require.Equal(t, 2, diags[0].Line)
+
+	df = `FROM busybox AS base
+COPY --from=1 /foo /foo
+FROM busybox AS base
+COPY --from=5 /foo /foo
+FROM busybox
+COPY --from=base /foo /foo
+`
+	diags = Check([]byte(df), "")
+	require.Len(t, diags, 3)
+	require.Equal(t, "duplicate stage name \"base\"", diags[0].Message)
+	require.Equal(t, 3, diags[0].Line)
+	require.Equal(t, "stage index 1 refers to the current or a later stage", diags[1].Message)
+	require.Equal(t, 2, diags[1].Line)
+	require.Equal(t, "invalid stage index 5", diags[2].Message)
+	require.Equal(t, 4, diags[2].Line)
+
+	diags = Check(nil, "")
+	require.Len(t, diags, 1)
+}
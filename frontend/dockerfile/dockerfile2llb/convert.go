@@ -36,6 +36,7 @@ const (
 	emptyImageName          = "scratch"
 	defaultContextLocalName = "context"
 	historyComment          = "buildkit.dockerfile.v0"
+	targetLabelKey          = "buildkit.target"
 
 	DefaultCopyImage = "docker/dockerfile-copy:v0.1.9@sha256:e8f159d3f00786604b93c675ee2783f8dc194bb565e61ca5788f6a6e9d304061"
 )
@@ -64,6 +65,14 @@ type ConvertOpt struct {
 	ContextLocalName  string
 	SourceMap         *llb.SourceMap
 	Hostname          string
+	// CacheIgnoreArgs contains names of build args whose values are not
+	// part of the cache keys of RUN commands
+	CacheIgnoreArgs []string
+	// TargetLabel selects the target stage by the value of its
+	// buildkit.target label instead of its name
+	TargetLabel string
+	// Warn is called for the warnings found in the Dockerfile
+	Warn func(parser.Warning)
 }
 
 func Dockerfile2LLB(ctx context.Context, dt []byte, opt ConvertOpt) (*llb.State, *Image, error) {
@@ -74,6 +83,9 @@ func Dockerfile2LLB(ctx context.Context, dt []byte, opt ConvertOpt) (*llb.State,
 	if opt.ContextLocalName == "" {
 		opt.ContextLocalName = defaultContextLocalName
 	}
+	if opt.Warn == nil {
+		opt.Warn = func(parser.Warning) {}
+	}
 
 	platformOpt := buildPlatformOpt(&opt)
 
@@ -86,6 +98,9 @@ func Dockerfile2LLB(ctx context.Context, dt []byte, opt ConvertOpt) (*llb.State,
 	if err != nil {
 		return nil, nil, err
 	}
+	for _, w := range dockerfile.WarningDetails {
+		opt.Warn(w)
+	}
 
 	proxyEnv := proxyEnvFromBuildArgs(opt.BuildArgs)
 
@@ -179,14 +194,24 @@ func Dockerfile2LLB(ctx context.Context, dt []byte, opt ConvertOpt) (*llb.State,
 	}
 
 	var target *dispatchState
-	if opt.Target == "" {
-		target = allDispatchStates.lastTarget()
-	} else {
+	switch {
+	case opt.Target != "" && opt.TargetLabel != "":
+		return nil, nil, errors.Errorf("target and target label can't be set at the same time")
+	case opt.Target != "":
 		var ok bool
 		target, ok = allDispatchStates.findStateByName(opt.Target)
 		if !ok {
 			return nil, nil, errors.Errorf("target stage %s could not be found", opt.Target)
 		}
+	case opt.TargetLabel != "":
+		target, err = allDispatchStates.findStateByTargetLabel(opt.TargetLabel, func(word string) (string, error) {
+			return shlex.ProcessWordWithMap(word, metaArgsToMap(optMetaArgs))
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	default:
+		target = allDispatchStates.lastTarget()
 	}
 
 	// fill dependencies to stages so unreachable ones can avoid loading image configs
@@ -373,7 +398,9 @@ func Dockerfile2LLB(ctx context.Context, dt []byte, opt ConvertOpt) (*llb.State,
 			extraHosts:        opt.ExtraHosts,
 			copyImage:         opt.OverrideCopyImage,
 			llbCaps:           opt.LLBCaps,
+			cacheIgnoreArgs:   opt.CacheIgnoreArgs,
 			sourceMap:         opt.SourceMap,
+			warn:              opt.Warn,
 		}
 		if opt.copyImage == "" {
 			opt.copyImage = DefaultCopyImage
@@ -492,6 +519,8 @@ type dispatchOpt struct {
 	copyImage         string
 	llbCaps           *apicaps.CapSet
 	sourceMap         *llb.SourceMap
+	cacheIgnoreArgs   []string
+	warn              func(parser.Warning)
 }
 
 func dispatch(d *dispatchState, cmd command, opt dispatchOpt) error {
@@ -511,6 +540,12 @@ func dispatch(d *dispatchState, cmd command, opt dispatchOpt) error {
 	var err error
 	switch c := cmd.Command.(type) {
 	case *instructions.MaintainerCommand:
+		opt.warn(parser.Warning{
+			Code:     "MaintainerDeprecated",
+			Short:    "MAINTAINER instruction is deprecated in favor of using label",
+			URL:      "https://docs.docker.com/engine/reference/builder/#maintainer-deprecated",
+			Location: c.Location(),
+		})
 		err = dispatchMaintainer(d, c)
 	case *instructions.EnvCommand:
 		err = dispatchEnv(d, c)
@@ -609,6 +644,68 @@ func (dss *dispatchStates) findStateByName(name string) (*dispatchState, bool) {
 	return ds, ok
 }
 
+// findStateByTargetLabel returns the stage with a buildkit.target label
+// matching value. The label is set with a LABEL instruction or with a
+// "# buildkit.target=<value>" comment before the FROM instruction of the
+// stage. Label values are expanded with the global build args only.
+func (dss *dispatchStates) findStateByTargetLabel(value string, expand func(string) (string, error)) (*dispatchState, error) {
+	var matches []*dispatchState
+	for _, ds := range dss.states {
+		labels, err := targetLabels(ds.stage, expand)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range labels {
+			if l == value {
+				matches = append(matches, ds)
+				break
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, errors.Errorf("no stage with target label %s could be found", value)
+	case 1:
+		return matches[0], nil
+	default:
+		names := make([]string, len(matches))
+		for i, ds := range matches {
+			names[i] = ds.stageName
+		}
+		return nil, errors.Errorf("target label %s is ambiguous, it matches stages %s", value, strings.Join(names, ", "))
+	}
+}
+
+func targetLabels(st instructions.Stage, expand func(string) (string, error)) ([]string, error) {
+	var out []string
+	for _, c := range st.Comments {
+		if v := strings.TrimPrefix(c, targetLabelKey+"="); v != c {
+			out = append(out, strings.TrimSpace(v))
+		}
+	}
+	for _, cmd := range st.Commands {
+		lc, ok := cmd.(*instructions.LabelCommand)
+		if !ok {
+			continue
+		}
+		for _, kv := range lc.Labels {
+			k, err := expand(kv.Key)
+			if err != nil {
+				return nil, parser.WithLocation(err, lc.Location())
+			}
+			if k != targetLabelKey {
+				continue
+			}
+			v, err := expand(kv.Value)
+			if err != nil {
+				return nil, parser.WithLocation(err, lc.Location())
+			}
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
 func (dss *dispatchStates) findStateByIndex(index int) (*dispatchState, error) {
 	if index < 0 || index >= len(dss.states) {
 		return nil, errors.Errorf("invalid stage index %d", index)
@@ -732,6 +829,11 @@ func dispatchRun(d *dispatchState, c *instructions.RunCommand, proxy *llb.ProxyE
 	if proxy != nil {
 		opt = append(opt, llb.WithProxy(*proxy))
 	}
+	if ignored := cacheIgnoredArgs(d.buildArgs, dopt.cacheIgnoreArgs); len(ignored) > 0 {
+		if dopt.llbCaps == nil || dopt.llbCaps.Supports(pb.CapExecMetaCacheIgnoreEnv) == nil {
+			opt = append(opt, llb.CacheIgnoreEnv(ignored...))
+		}
+	}
 
 	runMounts, err := dispatchRunMounts(d, c, sources, dopt)
 	if err != nil {
@@ -1285,6 +1387,24 @@ func dfCmd(cmd interface{}) llb.ConstraintsOpt {
 	})
 }
 
+// cacheIgnoredArgs returns the names of the build args of the stage that are
+// set and are listed in ignore
+func cacheIgnoredArgs(buildArgs []instructions.KeyValuePairOptional, ignore []string) []string {
+	var out []string
+	for _, arg := range buildArgs {
+		if arg.Value == nil {
+			continue
+		}
+		for _, k := range ignore {
+			if arg.Key == k {
+				out = append(out, k)
+				break
+			}
+		}
+	}
+	return out
+}
+
 func runCommandString(args []string, buildArgs []instructions.KeyValuePairOptional, envMap map[string]string) string {
 	var tmpBuildEnv []string
 	for _, arg := range buildArgs {
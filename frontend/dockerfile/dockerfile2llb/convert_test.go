@@ -4,9 +4,12 @@ import (
 	"testing"
 
 	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
 	"github.com/moby/buildkit/frontend/dockerfile/shell"
+	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/buildkit/util/appcontext"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func toEnvMap(args []instructions.KeyValuePairOptional, env []string) map[string]string {
@@ -188,3 +191,98 @@ COPY --from=stage1 f2 /sub/
 	_, _, err = Dockerfile2LLB(appcontext.Context(), []byte(df), ConvertOpt{})
 	assert.EqualError(t, err, "circular dependency detected on stage: stage0")
 }
+
+func TestCacheIgnoreArgs(t *testing.T) {
+	t.Parallel()
+	df := `FROM scratch
+ARG BUILD_TIMESTAMP
+ARG VERSION
+RUN true
+`
+	st, _, err := Dockerfile2LLB(appcontext.Context(), []byte(df), ConvertOpt{
+		BuildArgs:       map[string]string{"BUILD_TIMESTAMP": "1", "VERSION": "1.0"},
+		CacheIgnoreArgs: []string{"BUILD_TIMESTAMP", "OTHER"},
+	})
+	require.NoError(t, err)
+
+	def, err := st.Marshal(appcontext.Context())
+	require.NoError(t, err)
+
+	var found bool
+	for _, dt := range def.Def {
+		var op pb.Op
+		require.NoError(t, (&op).Unmarshal(dt))
+		if exec := op.GetExec(); exec != nil {
+			require.Equal(t, []string{"BUILD_TIMESTAMP"}, exec.Meta.CacheIgnoreEnv)
+			found = true
+		}
+	}
+	require.True(t, found)
+}
+
+func TestDockerfileTargetLabel(t *testing.T) {
+	t.Parallel()
+
+	df := `ARG LABEL=lint
+FROM scratch AS foo
+LABEL buildkit.target=test
+
+# buildkit.target=deploy
+FROM scratch AS bar
+ENV FOO bar
+
+FROM scratch
+LABEL "buildkit.target"="$LABEL"
+`
+	for _, label := range []string{"test", "deploy", "lint"} {
+		_, _, err := Dockerfile2LLB(appcontext.Context(), []byte(df), ConvertOpt{
+			TargetLabel: label,
+		})
+		assert.NoError(t, err)
+	}
+
+	_, _, err := Dockerfile2LLB(appcontext.Context(), []byte(df), ConvertOpt{
+		TargetLabel: "nosuch",
+	})
+	assert.EqualError(t, err, "no stage with target label nosuch could be found")
+
+	_, _, err = Dockerfile2LLB(appcontext.Context(), []byte(df), ConvertOpt{
+		Target:      "foo",
+		TargetLabel: "test",
+	})
+	assert.Error(t, err)
+
+	df = `FROM scratch AS foo
+LABEL buildkit.target=deploy
+
+FROM scratch AS bar
+LABEL buildkit.target=deploy
+`
+	_, _, err = Dockerfile2LLB(appcontext.Context(), []byte(df), ConvertOpt{
+		TargetLabel: "deploy",
+	})
+	assert.EqualError(t, err, "target label deploy is ambiguous, it matches stages foo, bar")
+}
+
+func TestDockerfileWarnings(t *testing.T) {
+	df := `FROM scratch
+MAINTAINER foo
+ENV FOO=bar \
+
+  BAR=baz
+`
+	var warnings []parser.Warning
+	_, _, err := Dockerfile2LLB(appcontext.Context(), []byte(df), ConvertOpt{
+		Warn: func(w parser.Warning) {
+			warnings = append(warnings, w)
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(warnings))
+
+	require.Equal(t, "EmptyContinuationLine", warnings[0].Code)
+	require.Equal(t, 3, warnings[0].Location[0].Start.Line)
+
+	require.Equal(t, "MaintainerDeprecated", warnings[1].Code)
+	require.Equal(t, []parser.Range{{Start: parser.Position{Line: 2}, End: parser.Position{Line: 2}}}, warnings[1].Location)
+}
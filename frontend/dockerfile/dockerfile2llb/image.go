@@ -53,6 +53,12 @@ type Image struct {
 
 	// Variant defines platform variant. To be added to OCI.
 	Variant string `json:"variant,omitempty"`
+
+	// OSVersion is the version of the operating system, used by Windows images.
+	OSVersion string `json:"os.version,omitempty"`
+
+	// OSFeatures are the features of the operating system required by the image.
+	OSFeatures []string `json:"os.features,omitempty"`
 }
 
 func clone(src Image) Image {
@@ -70,7 +76,9 @@ func emptyImage(platform specs.Platform) Image {
 			Architecture: platform.Architecture,
 			OS:           platform.OS,
 		},
-		Variant: platform.Variant,
+		Variant:    platform.Variant,
+		OSVersion:  platform.OSVersion,
+		OSFeatures: platform.OSFeatures,
 	}
 	img.RootFS.Type = "layers"
 	img.Config.WorkingDir = "/"
@@ -105,6 +105,7 @@ var allTests = []integration.Test{
 	testErrorsSourceMap,
 	testMultiArgs,
 	testFrontendSubrequests,
+	testWarmFrontend,
 	testDockefileCheckHostname,
 	testDefaultShellAndPath,
 	testDockerfileLowercase,
@@ -5033,6 +5034,76 @@ COPY foo foo2
 	require.Equal(t, expected, actual)
 }
 
+func testWarmFrontend(t *testing.T, sb integration.Sandbox) {
+	f := getFrontend(t, sb)
+	gf, ok := f.(*gatewayFrontend)
+	if !ok {
+		t.Skip("test is only for gateway frontends")
+	}
+
+	c, err := client.New(sb.Context(), sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	err = c.WarmFrontend(sb.Context(), gf.gw)
+	require.NoError(t, err)
+
+	dockerfile := []byte(`
+FROM scratch
+COPY foo foo
+`)
+
+	dir, err := tmpdir(
+		fstest.CreateFile("Dockerfile", dockerfile, 0600),
+		fstest.CreateFile("foo", []byte("bar"), 0600),
+	)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	destDir, err := ioutil.TempDir("", "buildkit")
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+
+	// the warmed frontend image is resolved again but not pulled
+	status := make(chan *client.SolveStatus)
+	var vertexes []string
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for st := range status {
+			for _, v := range st.Vertexes {
+				vertexes = append(vertexes, v.Name)
+			}
+		}
+	}()
+
+	_, err = f.Solve(sb.Context(), c, client.SolveOpt{
+		Exports: []client.ExportEntry{
+			{
+				Type:      client.ExporterLocal,
+				OutputDir: destDir,
+			},
+		},
+		LocalDirs: map[string]string{
+			builder.DefaultLocalNameDockerfile: dir,
+			builder.DefaultLocalNameContext:    dir,
+		},
+	}, status)
+	require.NoError(t, err)
+	<-done
+
+	for _, v := range vertexes {
+		require.False(t, strings.HasPrefix(v, "docker-image://"), v)
+	}
+
+	dt, err := ioutil.ReadFile(filepath.Join(destDir, "foo"))
+	require.NoError(t, err)
+	require.Equal(t, "bar", string(dt))
+
+	err = c.WarmFrontend(sb.Context(), "")
+	require.Error(t, err)
+}
+
 func testFrontendSubrequests(t *testing.T, sb integration.Sandbox) {
 	f := getFrontend(t, sb)
 
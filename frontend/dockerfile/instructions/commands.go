@@ -465,6 +465,8 @@ type Stage struct {
 	Platform   string
 	Location   []parser.Range
 	Comment    string
+	// Comments are the comment lines directly preceding the FROM instruction
+	Comments []string
 }
 
 // AddCommand to the stage
@@ -345,6 +345,7 @@ func parseFrom(req parseRequest) (*Stage, error) {
 		Platform:   flPlatform.Value,
 		Location:   req.location,
 		Comment:    getComment(req.comments, stageName),
+		Comments:   req.comments,
 	}, nil
 
 }
@@ -270,6 +270,19 @@ type Result struct {
 	AST         *Node
 	EscapeToken rune
 	Warnings    []string
+	// WarningDetails are the warnings of Warnings with their codes and
+	// locations in the Dockerfile
+	WarningDetails []Warning
+}
+
+// Warning is a warning found in a Dockerfile
+type Warning struct {
+	// Code identifies the kind of the warning
+	Code     string
+	Short    string
+	Detail   string
+	URL      string
+	Location []Range
 }
 
 // PrintWarnings to the writer
@@ -289,6 +302,7 @@ func Parse(rwc io.Reader) (*Result, error) {
 	scanner := bufio.NewScanner(rwc)
 	scanner.Split(scanLines)
 	warnings := []string{}
+	var warningDetails []Warning
 	var comments []string
 
 	var err error
@@ -342,6 +356,12 @@ func Parse(rwc io.Reader) (*Result, error) {
 
 		if hasEmptyContinuationLine {
 			warnings = append(warnings, "[WARNING]: Empty continuation line found in:\n    "+line)
+			warningDetails = append(warningDetails, Warning{
+				Code:     "EmptyContinuationLine",
+				Short:    "Empty continuation lines will become errors in a future release",
+				Detail:   line,
+				Location: toRanges(startLine, currentLine),
+			})
 		}
 
 		child, err := newNodeFromLine(line, d, comments)
@@ -393,9 +413,10 @@ func Parse(rwc io.Reader) (*Result, error) {
 	}
 
 	return &Result{
-		AST:         root,
-		Warnings:    warnings,
-		EscapeToken: d.escapeToken,
+		AST:            root,
+		Warnings:       warnings,
+		WarningDetails: warningDetails,
+		EscapeToken:    d.escapeToken,
 	}, withLocation(handleScannerError(scanner.Err()), currentLine, 0)
 }
 
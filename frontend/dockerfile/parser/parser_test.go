@@ -167,6 +167,11 @@ RUN indented \
 	require.Contains(t, warnings[0], "RUN something     following     more")
 	require.Contains(t, warnings[1], "RUN another     thing")
 	require.Contains(t, warnings[2], "will become errors in a future release")
+
+	require.Equal(t, 2, len(result.WarningDetails))
+	require.Equal(t, "EmptyContinuationLine", result.WarningDetails[0].Code)
+	require.Contains(t, result.WarningDetails[0].Detail, "RUN something     following     more")
+	require.Equal(t, 7, result.WarningDetails[0].Location[0].Start.Line)
 }
 
 func TestParseReturnsScannerErrors(t *testing.T) {
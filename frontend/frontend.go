@@ -17,8 +17,15 @@ type Frontend interface {
 type FrontendLLBBridge interface {
 	Solve(ctx context.Context, req SolveRequest, sid string) (*Result, error)
 	ResolveImageConfig(ctx context.Context, ref string, opt llb.ResolveImageConfigOpt) (digest.Digest, []byte, error)
+	MetaSet(ctx context.Context, key string, value []byte) error
+	MetaGet(ctx context.Context, key string) ([]byte, bool, error)
+	Warn(ctx context.Context, dgst digest.Digest, msg string, opts WarnOpts) error
 }
 
 type SolveRequest = gw.SolveRequest
 
+type WarnOpts = gw.WarnOpts
+
+type WarningLevel = gw.WarningLevel
+
 type CacheOptionsEntry = gw.CacheOptionsEntry
@@ -32,6 +32,7 @@ func TestFrontendIntegration(t *testing.T) {
 		testRefReadFile,
 		testRefReadDir,
 		testRefStatFile,
+		testRefGlob,
 		testReturnNil,
 	})
 }
@@ -296,6 +297,64 @@ func testRefStatFile(t *testing.T, sb integration.Sandbox) {
 	require.NoError(t, err)
 }
 
+func testRefGlob(t *testing.T, sb integration.Sandbox) {
+	ctx := sb.Context()
+
+	c, err := client.New(ctx, sb.Address())
+	require.NoError(t, err)
+	defer c.Close()
+
+	dir, err := tmpdir(
+		fstest.CreateFile("go.mod", nil, 0666),
+		fstest.CreateDir("app", 0700),
+		fstest.CreateFile("app/yarn.lock", nil, 0666),
+		fstest.CreateDir("app/web", 0700),
+		fstest.CreateFile("app/web/yarn.lock", nil, 0666),
+		fstest.CreateFile("app/web/package.json", nil, 0666),
+	)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	frontend := func(ctx context.Context, c gateway.Client) (*gateway.Result, error) {
+		def, err := llb.Local("mylocal").Marshal(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := c.Solve(ctx, gateway.SolveRequest{
+			Definition: def.ToPB(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		ref, err := res.SingleRef()
+		if err != nil {
+			return nil, err
+		}
+
+		paths, err := ref.Glob(ctx, "**/yarn.lock")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"app/web/yarn.lock", "app/yarn.lock"}, paths)
+
+		paths, err = ref.Glob(ctx, "*.mod")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go.mod"}, paths)
+
+		paths, err = ref.Glob(ctx, "package-lock.json")
+		require.NoError(t, err)
+		assert.Equal(t, 0, len(paths))
+		return gateway.NewResult(), nil
+	}
+
+	_, err = c.Build(ctx, client.SolveOpt{
+		LocalDirs: map[string]string{
+			"mylocal": dir,
+		},
+	}, "", frontend, nil)
+	require.NoError(t, err)
+}
+
 func tmpdir(appliers ...fstest.Applier) (string, error) {
 	tmpdir, err := ioutil.TempDir("", "buildkit-frontend")
 	if err != nil {
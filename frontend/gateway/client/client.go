@@ -18,6 +18,35 @@ type Client interface {
 	BuildOpts() BuildOpts
 	Inputs(ctx context.Context) (map[string]llb.State, error)
 	NewContainer(ctx context.Context, req NewContainerRequest) (Container, error)
+	// MetaSet stores a value that can be read by all the frontends of the
+	// build with MetaGet. The values are removed when the build completes.
+	MetaSet(ctx context.Context, key string, value []byte) error
+	MetaGet(ctx context.Context, key string) ([]byte, bool, error)
+	// Warn records a warning that is returned to the client in the response
+	// of the build. dgst optionally is the digest of the vertex that the
+	// warning is about.
+	Warn(ctx context.Context, dgst digest.Digest, msg string, opts WarnOpts) error
+}
+
+// WarningLevel is the severity of a warning
+type WarningLevel int
+
+const (
+	WarningLevelWarning WarningLevel = iota
+	WarningLevelInfo
+	WarningLevelError
+)
+
+// WarnOpts are the details of a warning
+type WarnOpts struct {
+	// Code identifies the kind of the warning. It doesn't change between
+	// releases so that clients can allowlist specific warnings.
+	Code       string
+	Level      WarningLevel
+	Detail     string
+	URL        string
+	SourceInfo *pb.SourceInfo
+	Range      []*pb.Range
 }
 
 // NewContainerRequest encapsulates the requirements for a client to define a
@@ -82,6 +111,9 @@ type Reference interface {
 	ReadFile(ctx context.Context, req ReadRequest) ([]byte, error)
 	StatFile(ctx context.Context, req StatRequest) (*fstypes.Stat, error)
 	ReadDir(ctx context.Context, req ReadDirRequest) ([]*fstypes.Stat, error)
+	// Glob returns the paths of the reference that match pattern. A "**"
+	// element of the pattern matches any number of directories.
+	Glob(ctx context.Context, pattern string) ([]string, error)
 }
 
 type ReadRequest struct {
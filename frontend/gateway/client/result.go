@@ -29,6 +29,9 @@ func (r *Result) AddMeta(k string, v []byte) {
 	r.mu.Unlock()
 }
 
+// AddRef adds a named reference to the result. Multi-platform results are
+// named by platform. A single named reference can be exported with the
+// export-ref frontend option, e.g. buildctl build --opt export-ref=<name>.
 func (r *Result) AddRef(k string, ref Reference) {
 	r.mu.Lock()
 	if r.Refs == nil {
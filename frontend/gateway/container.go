@@ -75,7 +75,7 @@ func NewContainer(ctx context.Context, w worker.Worker, sm *session.Manager, g s
 	}
 
 	name := fmt.Sprintf("container %s", req.ContainerID)
-	mm := mounts.NewMountManager(name, w.CacheManager(), sm, w.MetadataStore())
+	mm := mounts.NewMountManager(name, w.CacheManager(), sm, w.MetadataStore(), w.SecurityConfig().HostPaths)
 	p, err := PrepareMounts(ctx, mm, w.CacheManager(), g, "", mnts, refs, func(m *opspb.Mount, ref cache.ImmutableRef) (cache.MutableRef, error) {
 		cm := w.CacheManager()
 		if m.Input != opspb.Empty {
@@ -209,6 +209,12 @@ func PrepareMounts(ctx context.Context, mm *mounts.MountManager, cm cache.Manage
 
 		case opspb.MountType_TMPFS:
 			mountable = mm.MountableTmpFS()
+		case opspb.MountType_HOSTPATH:
+			var err error
+			mountable, err = mm.MountableHostPath(m)
+			if err != nil {
+				return p, err
+			}
 		case opspb.MountType_SECRET:
 			var err error
 			mountable, err = mm.MountableSecret(ctx, m, g)
@@ -32,3 +32,14 @@ func (err *ExitError) Unwrap() error {
 	}
 	return err.Err
 }
+
+// OOMError is the cause of the ExitError of a process that was killed because
+// it exceeded its memory limit.
+type OOMError struct {
+	// Limit is the memory limit of the process in bytes
+	Limit int64
+}
+
+func (err *OOMError) Error() string {
+	return fmt.Sprintf("process was killed because it ran out of memory, limit is %d bytes", err.Limit)
+}
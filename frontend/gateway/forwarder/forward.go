@@ -340,6 +340,14 @@ func (r *ref) StatFile(ctx context.Context, req client.StatRequest) (*fstypes.St
 	return cacheutil.StatFile(ctx, m, req.Path)
 }
 
+func (r *ref) Glob(ctx context.Context, pattern string) ([]string, error) {
+	m, err := r.getMountable(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cacheutil.Glob(ctx, m, pattern)
+}
+
 func (r *ref) getMountable(ctx context.Context) (snapshot.Mountable, error) {
 	rr, err := r.ResultProxy.Result(ctx)
 	if err != nil {
@@ -55,16 +55,86 @@ import (
 const (
 	keySource = "source"
 	keyDevel  = "gateway-devel"
+	// keyImageResolveMode is the resolve mode of the frontend image, and of
+	// the base images of frontends that support it
+	keyImageResolveMode = "image-resolve-mode"
 )
 
-func NewGatewayFrontend(w worker.Infos) frontend.Frontend {
+// NewGatewayFrontend returns the gateway frontend. If allowedImages is not
+// empty, only images with names matching one of the patterns can be run as
+// frontends and development frontends are disabled.
+func NewGatewayFrontend(w worker.Infos, allowedImages []string) (frontend.Frontend, error) {
+	policy, err := newSourcePolicy(allowedImages)
+	if err != nil {
+		return nil, err
+	}
 	return &gatewayFrontend{
 		workers: w,
-	}
+		policy:  policy,
+		warm:    newWarmFrontends(maxWarmFrontends, warmFrontendTTL),
+	}, nil
 }
 
 type gatewayFrontend struct {
 	workers worker.Infos
+	policy  *sourcePolicy
+	warm    *warmFrontends
+}
+
+// loadFrontend pulls the resolved frontend image and extracts its root
+// filesystem. The image is pulled and extracted with the session of the build,
+// so the registry credentials of the client are used for private frontend
+// images. The caller has to release the ref of the returned frontend.
+func (gf *gatewayFrontend) loadFrontend(ctx context.Context, llbBridge frontend.FrontendLLBBridge, sourceRef reference.Named, dgst digest.Digest, config []byte, mode llb.ResolveMode, sid string) (*warmFrontend, error) {
+	if dgst != "" {
+		var err error
+		sourceRef, err = reference.WithDigest(sourceRef, dgst)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	src := llb.Image(sourceRef.String(), &markTypeFrontend{}, mode)
+
+	def, err := src.Marshal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := llbBridge.Solve(ctx, frontend.SolveRequest{
+		Definition: def.ToPB(),
+	}, sid)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		res.EachRef(func(ref solver.ResultProxy) error {
+			return ref.Release(context.TODO())
+		})
+	}()
+	if res.Ref == nil {
+		return nil, errors.Errorf("gateway source didn't return default result")
+	}
+	r, err := res.Ref.Result(ctx)
+	if err != nil {
+		return nil, err
+	}
+	workerRef, ok := r.Sys().(*worker.WorkerRef)
+	if !ok {
+		return nil, errors.Errorf("invalid ref: %T", r.Sys())
+	}
+	f := &warmFrontend{
+		digest: dgst,
+		config: config,
+		worker: workerRef.Worker,
+	}
+	if workerRef.ImmutableRef != nil {
+		if err := workerRef.ImmutableRef.Extract(ctx, session.NewGroup(sid)); err != nil {
+			return nil, err
+		}
+		f.ref = workerRef.ImmutableRef.Clone()
+	}
+	return f, nil
 }
 
 func filterPrefix(opts map[string]string, pfx string) map[string]string {
@@ -90,6 +160,12 @@ func (gf *gatewayFrontend) Solve(ctx context.Context, llbBridge frontend.Fronten
 	var readonly bool // TODO: try to switch to read-only by default.
 
 	if isDevel {
+		if _, ok := opts[keyWarm]; ok {
+			return nil, errors.Errorf("%s frontends can't be warmed", keyDevel)
+		}
+		if gf.policy.restricted() {
+			return nil, errors.Errorf("%s is not allowed by the daemon policy restricting frontend images", keyDevel)
+		}
 		devRes, err := llbBridge.Solve(ctx,
 			frontend.SolveRequest{
 				Frontend:       source,
@@ -132,55 +208,60 @@ func (gf *gatewayFrontend) Solve(ctx context.Context, llbBridge frontend.Fronten
 		if err != nil {
 			return nil, err
 		}
-
-		dgst, config, err := llbBridge.ResolveImageConfig(ctx, reference.TagNameOnly(sourceRef).String(), llb.ResolveImageConfigOpt{})
+		if err := gf.policy.check(sourceRef); err != nil {
+			return nil, err
+		}
+		mode, err := parseResolveMode(opts[keyImageResolveMode])
 		if err != nil {
 			return nil, err
 		}
-		mfstDigest = dgst
 
-		if err := json.Unmarshal(config, &img); err != nil {
+		// the image is resolved for every build so that warmed frontends are
+		// only used while the reference still points to their image
+		dgst, config, err := llbBridge.ResolveImageConfig(ctx, reference.TagNameOnly(sourceRef).String(), llb.ResolveImageConfigOpt{
+			ResolveMode: mode.String(),
+		})
+		if err != nil {
 			return nil, err
 		}
+		_, warm := opts[keyWarm]
 
-		if dgst != "" {
-			sourceRef, err = reference.WithDigest(sourceRef, dgst)
+		// warmed frontends are only shared by builds with the same
+		// credentials for the registry of the frontend
+		scope, scopeErr := authScope(ctx, sm, sid, sourceRef)
+		if warm && dgst == "" {
+			return nil, errors.Errorf("frontend %s has no digest and can't be warmed", source)
+		}
+		if warm && scopeErr != nil {
+			return nil, errors.Wrapf(scopeErr, "failed to get credentials of frontend %s", source)
+		}
+		key := warmKey{digest: dgst, scope: scope}
+
+		var f *warmFrontend
+		var ok bool
+		if !warm && dgst != "" && scopeErr == nil {
+			f, ok = gf.warm.get(key)
+		}
+		if !ok {
+			f, err = gf.loadFrontend(ctx, llbBridge, sourceRef, dgst, config, mode, sid)
 			if err != nil {
 				return nil, err
 			}
+			if warm {
+				gf.warm.set(key, f)
+				return &frontend.Result{}, nil
+			}
 		}
-
-		src := llb.Image(sourceRef.String(), &markTypeFrontend{})
-
-		def, err := src.Marshal(ctx)
-		if err != nil {
-			return nil, err
+		if f.ref != nil {
+			defer f.ref.Release(context.TODO())
 		}
+		mfstDigest = f.digest
 
-		res, err := llbBridge.Solve(ctx, frontend.SolveRequest{
-			Definition: def.ToPB(),
-		}, sid)
-		if err != nil {
+		if err := json.Unmarshal(f.config, &img); err != nil {
 			return nil, err
 		}
-		defer func() {
-			res.EachRef(func(ref solver.ResultProxy) error {
-				return ref.Release(context.TODO())
-			})
-		}()
-		if res.Ref == nil {
-			return nil, errors.Errorf("gateway source didn't return default result")
 
-		}
-		r, err := res.Ref.Result(ctx)
-		if err != nil {
-			return nil, err
-		}
-		workerRef, ok := r.Sys().(*worker.WorkerRef)
-		if !ok {
-			return nil, errors.Errorf("invalid ref: %T", r.Sys())
-		}
-		rootFS, err = workerRef.Worker.CacheManager().New(ctx, workerRef.ImmutableRef, session.NewGroup(sid))
+		rootFS, err = f.worker.CacheManager().New(ctx, f.ref, session.NewGroup(sid))
 		if err != nil {
 			return nil, err
 		}
@@ -241,6 +322,16 @@ func (gf *gatewayFrontend) Solve(ctx context.Context, llbBridge frontend.Fronten
 		}
 	}
 
+	if _, ok := curCaps[capOptionsSchema]; ok {
+		schema, err := parseOptionsSchema(img.Config.Labels[labelOptionsSchema])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid frontend %s", source)
+		}
+		if invalid := schema.validate(opts); len(invalid) > 0 {
+			return nil, stack.Enable(grpcerrors.WrapCode(errdefs.NewInvalidFrontendOptionsError(invalid), codes.InvalidArgument))
+		}
+	}
+
 	lbf, ctx, err := serveLLBBridgeForwarder(ctx, llbBridge, gf.workers, inputs, sid, sm)
 	defer lbf.conn.Close() //nolint
 	if err != nil {
@@ -759,6 +850,29 @@ func (lbf *llbBridgeForwarder) StatFile(ctx context.Context, req *pb.StatFileReq
 	return &pb.StatFileResponse{Stat: st}, nil
 }
 
+func (lbf *llbBridgeForwarder) Glob(ctx context.Context, req *pb.GlobRequest) (*pb.GlobResponse, error) {
+	ctx = tracing.ContextWithSpanFromContext(ctx, lbf.callCtx)
+
+	ref, err := lbf.getImmutableRef(ctx, req.Ref, "/")
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// nothing matches in an empty result
+			return &pb.GlobResponse{}, nil
+		}
+		return nil, err
+	}
+	m, err := ref.Mount(ctx, true, session.NewGroup(lbf.sid))
+	if err != nil {
+		return nil, err
+	}
+	paths, err := cacheutil.Glob(ctx, m, req.Pattern)
+	if err != nil {
+		return nil, lbf.wrapSolveError(err)
+	}
+
+	return &pb.GlobResponse{Paths: paths}, nil
+}
+
 func (lbf *llbBridgeForwarder) Ping(context.Context, *pb.PingRequest) (*pb.PongResponse, error) {
 
 	workers := lbf.workers.WorkerInfos()
@@ -833,6 +947,36 @@ func (lbf *llbBridgeForwarder) Inputs(ctx context.Context, in *pb.InputsRequest)
 	}, nil
 }
 
+func (lbf *llbBridgeForwarder) MetaSet(ctx context.Context, in *pb.MetaSetRequest) (*pb.MetaSetResponse, error) {
+	if err := lbf.llbBridge.MetaSet(ctx, in.Key, in.Value); err != nil {
+		return nil, err
+	}
+	return &pb.MetaSetResponse{}, nil
+}
+
+func (lbf *llbBridgeForwarder) MetaGet(ctx context.Context, in *pb.MetaGetRequest) (*pb.MetaGetResponse, error) {
+	v, ok, err := lbf.llbBridge.MetaGet(ctx, in.Key)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.MetaGetResponse{Value: v, Found: ok}, nil
+}
+
+func (lbf *llbBridgeForwarder) Warn(ctx context.Context, in *pb.WarnRequest) (*pb.WarnResponse, error) {
+	err := lbf.llbBridge.Warn(ctx, in.Digest, in.Message, frontend.WarnOpts{
+		Code:       in.Code,
+		Level:      frontend.WarningLevel(in.Level),
+		Detail:     in.Detail,
+		URL:        in.Url,
+		SourceInfo: in.Info,
+		Range:      in.Ranges,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.WarnResponse{}, nil
+}
+
 func (lbf *llbBridgeForwarder) NewContainer(ctx context.Context, in *pb.NewContainerRequest) (_ *pb.NewContainerResponse, err error) {
 	logrus.Debugf("|<--- NewContainer %s", in.ContainerID)
 	ctrReq := NewContainerRequest{
@@ -938,6 +1082,22 @@ type processIO struct {
 	serverReaders map[uint32]io.ReadCloser
 }
 
+// startRequest returns the request to start the process of an init message.
+// The process runs the entrypoint of the meta with its args, like the
+// processes of exec ops.
+func startRequest(init *pb.InitMessage, pio *processIO) gwclient.StartRequest {
+	return gwclient.StartRequest{
+		Args:   init.Meta.ProcessArgs(),
+		Env:    init.Meta.Env,
+		User:   init.Meta.User,
+		Cwd:    init.Meta.Cwd,
+		Tty:    init.Tty,
+		Stdin:  pio.processReaders[0],
+		Stdout: pio.processWriters[1],
+		Stderr: pio.processWriters[2],
+	}
+}
+
 func newProcessIO(id string, openFds []uint32) *processIO {
 	pio := &processIO{
 		id:             id,
@@ -1133,16 +1293,7 @@ func (lbf *llbBridgeForwarder) ExecProcess(srv pb.LLBBridge_ExecProcessServer) e
 				pio := newProcessIO(pid, init.Fds)
 				pios[pid] = pio
 
-				proc, err := ctr.Start(initCtx, gwclient.StartRequest{
-					Args:   init.Meta.Args,
-					Env:    init.Meta.Env,
-					User:   init.Meta.User,
-					Cwd:    init.Meta.Cwd,
-					Tty:    init.Tty,
-					Stdin:  pio.processReaders[0],
-					Stdout: pio.processWriters[1],
-					Stderr: pio.processWriters[2],
-				})
+				proc, err := ctr.Start(initCtx, startRequest(init, pio))
 				if err != nil {
 					return stack.Enable(err)
 				}
@@ -0,0 +1,87 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/distribution/reference"
+	"github.com/moby/buildkit/client/llb"
+	"github.com/moby/buildkit/frontend"
+	gwpb "github.com/moby/buildkit/frontend/gateway/pb"
+	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFrontendSession(t *testing.T) {
+	t.Parallel()
+
+	dgst := digest.FromBytes([]byte("manifest"))
+	b := &testBridge{}
+	ref, err := reference.ParseNormalizedNamed("registry.example.com/frontends/custom")
+	require.NoError(t, err)
+
+	gf := &gatewayFrontend{}
+	_, err = gf.loadFrontend(context.TODO(), b, ref, dgst, []byte("{}"), llb.ResolveModeForcePull, "build-session")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "didn't return default result")
+
+	require.Equal(t, []string{"build-session"}, b.sessions)
+
+	// the image is pulled by the resolved digest with the resolve mode of the
+	// build
+	require.Len(t, b.defs, 1)
+	var found bool
+	for _, dt := range b.defs[0].Def {
+		var op pb.Op
+		require.NoError(t, op.Unmarshal(dt))
+		if src := op.GetSource(); src != nil {
+			require.Equal(t, "docker-image://registry.example.com/frontends/custom@"+dgst.String(), src.Identifier)
+			require.Equal(t, pb.AttrImageResolveModeForcePull, src.Attrs[pb.AttrImageResolveMode])
+			found = true
+		}
+	}
+	require.True(t, found)
+}
+
+func TestStartRequest(t *testing.T) {
+	t.Parallel()
+
+	pio := newProcessIO("p1", []uint32{0, 1, 2})
+	defer pio.Close()
+	init := &gwpb.InitMessage{
+		Meta: &pb.Meta{
+			Entrypoint: "/bin/sh",
+			Args:       []string{"-c", "true"},
+			Env:        []string{"FOO=bar"},
+			Cwd:        "/work",
+			User:       "1000",
+		},
+		Tty: true,
+	}
+	req := startRequest(init, pio)
+	require.Equal(t, []string{"/bin/sh", "-c", "true"}, req.Args)
+	require.Equal(t, []string{"FOO=bar"}, req.Env)
+	require.Equal(t, "/work", req.Cwd)
+	require.Equal(t, "1000", req.User)
+	require.True(t, req.Tty)
+	require.NotNil(t, req.Stdin)
+	require.NotNil(t, req.Stdout)
+	require.NotNil(t, req.Stderr)
+
+	// without an entrypoint the first arg is the executable
+	init.Meta.Entrypoint = ""
+	require.Equal(t, []string{"-c", "true"}, startRequest(init, pio).Args)
+}
+
+type testBridge struct {
+	frontend.FrontendLLBBridge
+	sessions []string
+	defs     []*pb.Definition
+}
+
+func (b *testBridge) Solve(ctx context.Context, req frontend.SolveRequest, sid string) (*frontend.Result, error) {
+	b.sessions = append(b.sessions, sid)
+	b.defs = append(b.defs, req.Definition)
+	return &frontend.Result{}, nil
+}
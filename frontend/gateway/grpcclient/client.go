@@ -457,6 +457,45 @@ func (c *grpcClient) BuildOpts() client.BuildOpts {
 	}
 }
 
+func (c *grpcClient) MetaSet(ctx context.Context, key string, value []byte) error {
+	if err := c.caps.Supports(pb.CapGatewayMetadata); err != nil {
+		return err
+	}
+
+	_, err := c.client.MetaSet(ctx, &pb.MetaSetRequest{Key: key, Value: value})
+	return err
+}
+
+func (c *grpcClient) MetaGet(ctx context.Context, key string) ([]byte, bool, error) {
+	if err := c.caps.Supports(pb.CapGatewayMetadata); err != nil {
+		return nil, false, err
+	}
+
+	resp, err := c.client.MetaGet(ctx, &pb.MetaGetRequest{Key: key})
+	if err != nil {
+		return nil, false, err
+	}
+	return resp.Value, resp.Found, nil
+}
+
+func (c *grpcClient) Warn(ctx context.Context, dgst digest.Digest, msg string, opts client.WarnOpts) error {
+	if err := c.caps.Supports(pb.CapGatewayWarnings); err != nil {
+		return err
+	}
+
+	_, err := c.client.Warn(ctx, &pb.WarnRequest{
+		Digest:  dgst,
+		Code:    opts.Code,
+		Level:   int64(opts.Level),
+		Message: msg,
+		Detail:  opts.Detail,
+		Url:     opts.URL,
+		Info:    opts.SourceInfo,
+		Ranges:  opts.Range,
+	})
+	return err
+}
+
 func (c *grpcClient) Inputs(ctx context.Context) (map[string]llb.State, error) {
 	err := c.caps.Supports(pb.CapFrontendInputs)
 	if err != nil {
@@ -1001,6 +1040,20 @@ func (r *reference) StatFile(ctx context.Context, req client.StatRequest) (*fsty
 	return resp.Stat, nil
 }
 
+func (r *reference) Glob(ctx context.Context, pattern string) ([]string, error) {
+	if err := r.c.caps.Supports(pb.CapGlob); err != nil {
+		return nil, err
+	}
+	resp, err := r.c.client.Glob(ctx, &pb.GlobRequest{
+		Ref:     r.id,
+		Pattern: pattern,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Paths, nil
+}
+
 func grpcClientConn(ctx context.Context) (context.Context, *grpc.ClientConn, error) {
 	dialOpt := grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
 		return stdioConn(), nil
@@ -0,0 +1,177 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/moby/buildkit/solver/errdefs"
+	"github.com/pkg/errors"
+)
+
+const (
+	// capOptionsSchema is the frontend capability of frontend images whose
+	// options are validated against the schema in labelOptionsSchema
+	capOptionsSchema   = "moby.buildkit.frontend.optionsschema"
+	labelOptionsSchema = "moby.buildkit.frontend.options.schema"
+)
+
+// reservedOptions are set by the daemon and the client libraries and are not
+// validated against the schema of the frontend
+var reservedOptions = map[string]struct{}{
+	keySource:       {},
+	"frontend.caps": {},
+	"cmdline":       {},
+	"cache-imports": {},
+	"cache-from":    {},
+	"export-ref":    {},
+}
+
+// optionsSchema is the subset of JSON schema that describes the options of a
+// frontend. Options are strings, their type sets how the value is parsed.
+type optionsSchema struct {
+	Type                 string                   `json:"type"`
+	Properties           map[string]*optionSchema `json:"properties"`
+	PatternProperties    map[string]*optionSchema `json:"patternProperties"`
+	AdditionalProperties *bool                    `json:"additionalProperties"`
+	Required             []string                 `json:"required"`
+
+	patterns []*optionSchema
+}
+
+type optionSchema struct {
+	Type    string   `json:"type"`
+	Enum    []string `json:"enum"`
+	Pattern string   `json:"pattern"`
+
+	key     *regexp.Regexp
+	pattern *regexp.Regexp
+}
+
+func parseOptionsSchema(dt string) (*optionsSchema, error) {
+	if dt == "" {
+		return nil, errors.Errorf("frontend has capability %s but no %s label", capOptionsSchema, labelOptionsSchema)
+	}
+	var s optionsSchema
+	if err := json.Unmarshal([]byte(dt), &s); err != nil {
+		return nil, errors.Wrap(err, "failed to parse options schema")
+	}
+	if s.Type != "" && s.Type != "object" {
+		return nil, errors.Errorf("invalid options schema type %q", s.Type)
+	}
+	for k, o := range s.Properties {
+		if err := o.init(); err != nil {
+			return nil, errors.Wrapf(err, "invalid schema of option %s", k)
+		}
+	}
+	keys := make([]string, 0, len(s.PatternProperties))
+	for k := range s.PatternProperties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		o := s.PatternProperties[k]
+		re, err := regexp.Compile(k)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid option pattern %s", k)
+		}
+		if err := o.init(); err != nil {
+			return nil, errors.Wrapf(err, "invalid schema of options %s", k)
+		}
+		o.key = re
+		s.patterns = append(s.patterns, o)
+	}
+	return &s, nil
+}
+
+func (o *optionSchema) init() error {
+	switch o.Type {
+	case "", "string", "boolean", "integer", "number":
+	default:
+		return errors.Errorf("unsupported type %q", o.Type)
+	}
+	if o.Pattern != "" {
+		re, err := regexp.Compile(o.Pattern)
+		if err != nil {
+			return errors.Wrapf(err, "invalid pattern %s", o.Pattern)
+		}
+		o.pattern = re
+	}
+	return nil
+}
+
+// validate returns the options that don't match the schema, sorted by name
+func (s *optionsSchema) validate(opts map[string]string) []*errdefs.FrontendOption {
+	var invalid []*errdefs.FrontendOption
+	for k, v := range opts {
+		if _, ok := reservedOptions[k]; ok || strings.HasPrefix(k, "gateway-") {
+			continue
+		}
+		var schemas []*optionSchema
+		if o, ok := s.Properties[k]; ok {
+			schemas = append(schemas, o)
+		}
+		for _, o := range s.patterns {
+			if o.key.MatchString(k) {
+				schemas = append(schemas, o)
+			}
+		}
+		if len(schemas) == 0 && s.AdditionalProperties != nil && !*s.AdditionalProperties {
+			invalid = append(invalid, &errdefs.FrontendOption{Name: k, Reason: "unknown option"})
+			continue
+		}
+		for _, o := range schemas {
+			if reason := o.validate(v); reason != "" {
+				invalid = append(invalid, &errdefs.FrontendOption{Name: k, Reason: reason})
+				break
+			}
+		}
+	}
+	for _, k := range s.Required {
+		if _, ok := opts[k]; !ok {
+			invalid = append(invalid, &errdefs.FrontendOption{Name: k, Reason: "required option is missing"})
+		}
+	}
+	sort.Slice(invalid, func(i, j int) bool {
+		return invalid[i].Name < invalid[j].Name
+	})
+	return invalid
+}
+
+// validate returns the reason why v is not a valid value of the option
+func (o *optionSchema) validate(v string) string {
+	switch o.Type {
+	case "boolean":
+		// options without a value are set
+		if _, err := strconv.ParseBool(v); err != nil && v != "" {
+			return fmt.Sprintf("invalid boolean value %q", v)
+		}
+	case "integer":
+		if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+			return fmt.Sprintf("invalid integer value %q", v)
+		}
+	case "number":
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return fmt.Sprintf("invalid number value %q", v)
+		}
+	}
+	if len(o.Enum) > 0 {
+		found := false
+		for _, e := range o.Enum {
+			if e == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Sprintf("value %q is not one of %s", v, strings.Join(o.Enum, ", "))
+		}
+	}
+	if o.pattern != nil && !o.pattern.MatchString(v) {
+		return fmt.Sprintf("value %q does not match %s", v, o.Pattern)
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/solver/errdefs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionsSchema(t *testing.T) {
+	t.Parallel()
+
+	s, err := parseOptionsSchema(`{
+		"type": "object",
+		"properties": {
+			"target": {"type": "string", "pattern": "^[a-z]+$"},
+			"no-cache": {"type": "boolean"},
+			"mode": {"enum": ["fast", "slow"]},
+			"jobs": {"type": "integer"}
+		},
+		"patternProperties": {
+			"^build-arg:": {"type": "string"}
+		},
+		"additionalProperties": false,
+		"required": ["target"]
+	}`)
+	require.NoError(t, err)
+
+	require.Empty(t, s.validate(map[string]string{
+		"target":        "app",
+		"no-cache":      "",
+		"mode":          "fast",
+		"jobs":          "4",
+		"build-arg:FOO": "bar",
+		"source":        "docker/dockerfile",
+		"frontend.caps": "moby.buildkit.frontend.inputs",
+		"gateway-devel": "",
+		"cache-imports": "[]",
+	}))
+
+	invalid := s.validate(map[string]string{
+		"no-cache": "maybe",
+		"mode":     "medium",
+		"jobs":     "four",
+		"taget":    "app",
+	})
+	require.Equal(t, []*errdefs.FrontendOption{
+		{Name: "jobs", Reason: `invalid integer value "four"`},
+		{Name: "mode", Reason: `value "medium" is not one of fast, slow`},
+		{Name: "no-cache", Reason: `invalid boolean value "maybe"`},
+		{Name: "taget", Reason: "unknown option"},
+		{Name: "target", Reason: "required option is missing"},
+	}, invalid)
+
+	invalid = s.validate(map[string]string{"target": "App"})
+	require.Equal(t, []*errdefs.FrontendOption{
+		{Name: "target", Reason: `value "App" does not match ^[a-z]+$`},
+	}, invalid)
+
+	err = errdefs.NewInvalidFrontendOptionsError(invalid)
+	require.EqualError(t, err, `invalid frontend options: target: value "App" does not match ^[a-z]+$`)
+
+	// unknown options are allowed by default
+	s, err = parseOptionsSchema(`{"properties": {"target": {"type": "string"}}}`)
+	require.NoError(t, err)
+	require.Empty(t, s.validate(map[string]string{"foo": "bar"}))
+
+	_, err = parseOptionsSchema("")
+	require.Error(t, err)
+	_, err = parseOptionsSchema(`{"type": "array"}`)
+	require.Error(t, err)
+	_, err = parseOptionsSchema(`{"properties": {"target": {"type": "object"}}}`)
+	require.Error(t, err)
+	_, err = parseOptionsSchema(`{"patternProperties": {"(": {}}}`)
+	require.Error(t, err)
+}
@@ -47,6 +47,18 @@ const (
 	// results. This is generally used by the client to return and handle solve
 	// errors.
 	CapGatewayEvaluateSolve apicaps.CapID = "gateway.solve.evaluate"
+
+	// CapGatewayMetadata is a capability to store key-value metadata that
+	// is shared by all the frontends of a build
+	CapGatewayMetadata apicaps.CapID = "gateway.metadata"
+
+	// CapGatewayWarnings is a capability to record warnings that are
+	// returned to the client with the response of the build
+	CapGatewayWarnings apicaps.CapID = "gateway.warnings"
+
+	// CapGlob is a capability to list the paths of a reference that match a
+	// glob pattern
+	CapGlob apicaps.CapID = "glob"
 )
 
 func init() {
@@ -169,4 +181,25 @@ func init() {
 		Enabled: true,
 		Status:  apicaps.CapStatusExperimental,
 	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapGatewayMetadata,
+		Name:    "gateway metadata",
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapGlob,
+		Name:    "glob files",
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapGatewayWarnings,
+		Name:    "gateway warnings",
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
 }
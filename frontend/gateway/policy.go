@@ -0,0 +1,48 @@
+package gateway
+
+import (
+	"path"
+
+	"github.com/docker/distribution/reference"
+	"github.com/pkg/errors"
+)
+
+// sourcePolicy restricts the images that can be run as frontends. An empty
+// policy allows every image.
+type sourcePolicy struct {
+	patterns []string
+}
+
+// newSourcePolicy returns a policy allowing the images with names matching one
+// of the patterns. Patterns without wildcards are normalized like image names,
+// patterns with wildcards need to use the full name of the repository, e.g.
+// "docker.io/myorg/*".
+func newSourcePolicy(patterns []string) (*sourcePolicy, error) {
+	p := &sourcePolicy{}
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid frontend image pattern %q", pattern)
+		}
+		if named, err := reference.ParseNormalizedNamed(pattern); err == nil {
+			pattern = named.Name()
+		}
+		p.patterns = append(p.patterns, pattern)
+	}
+	return p, nil
+}
+
+func (p *sourcePolicy) restricted() bool {
+	return len(p.patterns) > 0
+}
+
+func (p *sourcePolicy) check(ref reference.Named) error {
+	if !p.restricted() {
+		return nil
+	}
+	for _, pattern := range p.patterns {
+		if ok, _ := path.Match(pattern, ref.Name()); ok {
+			return nil
+		}
+	}
+	return errors.Errorf("frontend image %s is not allowed by the daemon policy, allowed images: %v", ref.Name(), p.patterns)
+}
@@ -0,0 +1,38 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/docker/distribution/reference"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourcePolicy(t *testing.T) {
+	t.Parallel()
+
+	check := func(p *sourcePolicy, ref string) error {
+		named, err := reference.ParseNormalizedNamed(ref)
+		require.NoError(t, err)
+		return p.check(named)
+	}
+
+	p, err := newSourcePolicy(nil)
+	require.NoError(t, err)
+	require.False(t, p.restricted())
+	require.NoError(t, check(p, "example.com/any/frontend:latest"))
+
+	p, err = newSourcePolicy([]string{"docker/dockerfile", "registry.example.com/frontends/*"})
+	require.NoError(t, err)
+	require.True(t, p.restricted())
+	require.NoError(t, check(p, "docker/dockerfile:1.3"))
+	require.NoError(t, check(p, "docker.io/docker/dockerfile@sha256:4f4fb700ef54461cfa02571ae0db9a0dc1e0cdb5577484a6d75e68dc38e8acc1"))
+	require.NoError(t, check(p, "registry.example.com/frontends/custom:v1"))
+	require.Error(t, check(p, "docker/dockerfile-upstream"))
+	require.Error(t, check(p, "registry.example.com/frontends/nested/custom"))
+	err = check(p, "example.com/other")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not allowed by the daemon policy")
+
+	_, err = newSourcePolicy([]string{"[invalid"})
+	require.Error(t, err)
+}
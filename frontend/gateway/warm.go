@@ -0,0 +1,192 @@
+package gateway
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/client/llb"
+	"github.com/moby/buildkit/session"
+	sessionauth "github.com/moby/buildkit/session/auth"
+	opspb "github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/worker"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// keyWarm makes the gateway frontend resolve and extract the frontend image
+// of source and keep it for later builds instead of running it
+const keyWarm = "frontend.warm"
+
+const (
+	// maxWarmFrontends is the number of warmed frontends that are kept. The
+	// least recently used one is released first.
+	maxWarmFrontends = 8
+	// warmFrontendTTL is the time a warmed frontend is kept after its last
+	// use
+	warmFrontendTTL = 24 * time.Hour
+)
+
+// warmKey identifies a warmed frontend by the digest of its image and the
+// credentials it was pulled with, so that builds only reuse frontends they are
+// allowed to pull
+type warmKey struct {
+	digest digest.Digest
+	scope  digest.Digest
+}
+
+// warmFrontend is a frontend image that was resolved and extracted ahead of
+// the builds that use it
+type warmFrontend struct {
+	digest digest.Digest
+	config []byte
+	worker worker.Worker
+	ref    cache.ImmutableRef
+}
+
+type warmEntry struct {
+	key   warmKey
+	f     *warmFrontend
+	timer *time.Timer
+	elem  *list.Element
+	used  time.Time
+}
+
+// warmFrontends are the warmed frontends. A frontend is released when it
+// wasn't used for ttl or when more than max frontends are warmed.
+type warmFrontends struct {
+	mu  sync.Mutex
+	m   map[warmKey]*warmEntry
+	lru *list.List // front is the most recently used entry
+	max int
+	ttl time.Duration
+}
+
+func newWarmFrontends(max int, ttl time.Duration) *warmFrontends {
+	return &warmFrontends{
+		m:   map[warmKey]*warmEntry{},
+		lru: list.New(),
+		max: max,
+		ttl: ttl,
+	}
+}
+
+// get returns the warmed frontend of key with a clone of its ref that the
+// caller has to release
+func (w *warmFrontends) get(key warmKey) (*warmFrontend, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	e, ok := w.m[key]
+	if !ok {
+		return nil, false
+	}
+	w.lru.MoveToFront(e.elem)
+	e.used = time.Now()
+	e.timer.Reset(w.ttl)
+	out := &warmFrontend{
+		digest: e.f.digest,
+		config: e.f.config,
+		worker: e.f.worker,
+	}
+	if e.f.ref != nil {
+		out.ref = e.f.ref.Clone()
+	}
+	return out, true
+}
+
+// set stores the warmed frontend of key and releases the frontend it replaces
+// and the frontends over the limit
+func (w *warmFrontends) set(key warmKey, f *warmFrontend) {
+	w.mu.Lock()
+	var released []*warmFrontend
+	if prev, ok := w.m[key]; ok {
+		released = append(released, w.removeLocked(prev))
+	}
+	e := &warmEntry{key: key, f: f, used: time.Now()}
+	e.elem = w.lru.PushFront(e)
+	e.timer = time.AfterFunc(w.ttl, func() {
+		w.expire(e)
+	})
+	w.m[key] = e
+	for w.lru.Len() > w.max {
+		released = append(released, w.removeLocked(w.lru.Back().Value.(*warmEntry)))
+	}
+	w.mu.Unlock()
+	for _, f := range released {
+		f.release()
+	}
+}
+
+// expire releases the frontend of e if it wasn't replaced or used since the
+// timer was set
+func (w *warmFrontends) expire(e *warmEntry) {
+	w.mu.Lock()
+	if w.m[e.key] != e || time.Since(e.used) < w.ttl {
+		w.mu.Unlock()
+		return
+	}
+	f := w.removeLocked(e)
+	w.mu.Unlock()
+	f.release()
+}
+
+func (w *warmFrontends) removeLocked(e *warmEntry) *warmFrontend {
+	e.timer.Stop()
+	w.lru.Remove(e.elem)
+	delete(w.m, e.key)
+	return e.f
+}
+
+func (f *warmFrontend) release() {
+	if f.ref != nil {
+		f.ref.Release(context.TODO())
+	}
+}
+
+func parseResolveMode(v string) (llb.ResolveMode, error) {
+	switch v {
+	case opspb.AttrImageResolveModeDefault, "":
+		return llb.ResolveModeDefault, nil
+	case opspb.AttrImageResolveModeForcePull:
+		return llb.ResolveModeForcePull, nil
+	case opspb.AttrImageResolveModePreferLocal:
+		return llb.ResolveModePreferLocal, nil
+	case opspb.AttrImageResolveModeLocalOnly:
+		return llb.ResolveModeLocalOnly, nil
+	default:
+		return 0, errors.Errorf("invalid image-resolve-mode: %s", v)
+	}
+}
+
+// authScope returns the digest of the pull credentials the session of the
+// build has for the registry of ref. Builds without credentials share the
+// empty scope. An error is returned if the credentials couldn't be looked up.
+func authScope(ctx context.Context, sm *session.Manager, sid string, ref reference.Named) (digest.Digest, error) {
+	if sid == "" {
+		return "", nil
+	}
+	host := reference.Domain(ref)
+	if host == "docker.io" {
+		host = "registry-1.docker.io"
+	}
+	g := session.NewGroup(sid)
+	_, username, secret, err := sessionauth.CredentialsFunc(sm, g, "pull")(host)
+	if err != nil {
+		return "", err
+	}
+	_, pubKey, err := sessionauth.GetTokenAuthority(ctx, host, "pull", sm, g)
+	if err != nil {
+		return "", err
+	}
+	if username == "" && secret == "" && pubKey == nil {
+		return "", nil
+	}
+	dt := []byte(username + "\x00" + secret + "\x00")
+	if pubKey != nil {
+		dt = append(dt, pubKey[:]...)
+	}
+	return digest.FromBytes(dt), nil
+}
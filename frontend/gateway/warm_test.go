@@ -0,0 +1,120 @@
+package gateway
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/client/llb"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarmFrontendsLRU(t *testing.T) {
+	t.Parallel()
+
+	w := newWarmFrontends(2, time.Hour)
+	refs := map[string]*testRef{}
+	for _, name := range []string{"a", "b"} {
+		refs[name] = &testRef{refs: 1}
+		w.set(testWarmKey(name, ""), &warmFrontend{digest: digest.FromString(name), ref: refs[name]})
+	}
+
+	f, ok := w.get(testWarmKey("a", ""))
+	require.True(t, ok)
+	require.Equal(t, digest.FromString("a"), f.digest)
+	f.release()
+
+	// b is the least recently used frontend
+	refs["c"] = &testRef{refs: 1}
+	w.set(testWarmKey("c", ""), &warmFrontend{digest: digest.FromString("c"), ref: refs["c"]})
+	_, ok = w.get(testWarmKey("b", ""))
+	require.False(t, ok)
+	require.Equal(t, 0, refs["b"].count())
+	require.Equal(t, 1, refs["a"].count())
+	require.Equal(t, 1, refs["c"].count())
+
+	// frontends are not shared between credentials
+	_, ok = w.get(testWarmKey("a", "user"))
+	require.False(t, ok)
+
+	// warming a frontend again releases the previous one
+	prev := refs["a"]
+	refs["a"] = &testRef{refs: 1}
+	w.set(testWarmKey("a", ""), &warmFrontend{digest: digest.FromString("a"), ref: refs["a"]})
+	require.Equal(t, 0, prev.count())
+	require.Equal(t, 1, refs["a"].count())
+}
+
+func TestWarmFrontendsTTL(t *testing.T) {
+	t.Parallel()
+
+	w := newWarmFrontends(8, 50*time.Millisecond)
+	ref := &testRef{refs: 1}
+	w.set(testWarmKey("a", ""), &warmFrontend{ref: ref})
+
+	f, ok := w.get(testWarmKey("a", ""))
+	require.True(t, ok)
+	f.release()
+
+	require.Eventually(t, func() bool {
+		return ref.count() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	_, ok = w.get(testWarmKey("a", ""))
+	require.False(t, ok)
+}
+
+func TestParseResolveMode(t *testing.T) {
+	t.Parallel()
+
+	for v, mode := range map[string]llb.ResolveMode{
+		"":           llb.ResolveModeDefault,
+		"default":    llb.ResolveModeDefault,
+		"pull":       llb.ResolveModeForcePull,
+		"local":      llb.ResolveModePreferLocal,
+		"local-only": llb.ResolveModeLocalOnly,
+	} {
+		m, err := parseResolveMode(v)
+		require.NoError(t, err)
+		require.Equal(t, mode, m)
+	}
+	_, err := parseResolveMode("always")
+	require.Error(t, err)
+}
+
+func testWarmKey(name, scope string) warmKey {
+	k := warmKey{digest: digest.FromString(name)}
+	if scope != "" {
+		k.scope = digest.FromString(scope)
+	}
+	return k
+}
+
+// testRef counts the references to a frontend image that weren't released
+type testRef struct {
+	cache.ImmutableRef
+	mu   sync.Mutex
+	refs int
+}
+
+func (r *testRef) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.refs
+}
+
+func (r *testRef) Clone() cache.ImmutableRef {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.refs++
+	return r
+}
+
+func (r *testRef) Release(context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.refs--
+	return nil
+}
@@ -13,6 +13,13 @@ import (
 	"google.golang.org/grpc/codes"
 )
 
+const (
+	// RolePull is the role of the credentials used to resolve and pull images
+	RolePull = "pull"
+	// RolePush is the role of the credentials used to push images and cache
+	RolePush = "push"
+)
+
 var salt []byte
 var saltOnce sync.Once
 
@@ -25,7 +32,10 @@ func getSalt() []byte {
 	return salt
 }
 
-func CredentialsFunc(sm *session.Manager, g session.Group) func(string) (session, username, secret string, err error) {
+// CredentialsFunc returns a function looking up the credentials of a host. The
+// role allows the client to return different credentials for the same host
+// depending on whether they are used for pulling or pushing.
+func CredentialsFunc(sm *session.Manager, g session.Group, role string) func(string) (session, username, secret string, err error) {
 	return func(host string) (string, string, string, error) {
 		var sessionID, user, secret string
 		err := sm.Any(context.TODO(), g, func(ctx context.Context, id string, c session.Caller) error {
@@ -33,6 +43,7 @@ func CredentialsFunc(sm *session.Manager, g session.Group) func(string) (session
 
 			resp, err := client.Credentials(ctx, &CredentialsRequest{
 				Host: host,
+				Role: role,
 			})
 			if err != nil {
 				if grpcerrors.Code(err) == codes.Unimplemented {
@@ -68,7 +79,7 @@ func FetchToken(ctx context.Context, req *FetchTokenRequest, sm *session.Manager
 	return resp, nil
 }
 
-func VerifyTokenAuthority(ctx context.Context, host string, pubKey *[32]byte, sm *session.Manager, g session.Group) (sessionID string, ok bool, err error) {
+func VerifyTokenAuthority(ctx context.Context, host, role string, pubKey *[32]byte, sm *session.Manager, g session.Group) (sessionID string, ok bool, err error) {
 	var verified bool
 	err = sm.Any(ctx, g, func(ctx context.Context, id string, c session.Caller) error {
 		client := NewAuthClient(c.Conn())
@@ -77,6 +88,7 @@ func VerifyTokenAuthority(ctx context.Context, host string, pubKey *[32]byte, sm
 		rand.Read(payload)
 		resp, err := client.VerifyTokenAuthority(ctx, &VerifyTokenAuthorityRequest{
 			Host:    host,
+			Role:    role,
 			Salt:    getSalt(),
 			Payload: payload,
 		})
@@ -100,12 +112,13 @@ func VerifyTokenAuthority(ctx context.Context, host string, pubKey *[32]byte, sm
 	return sessionID, verified, nil
 }
 
-func GetTokenAuthority(ctx context.Context, host string, sm *session.Manager, g session.Group) (sessionID string, pubKey *[32]byte, err error) {
+func GetTokenAuthority(ctx context.Context, host, role string, sm *session.Manager, g session.Group) (sessionID string, pubKey *[32]byte, err error) {
 	err = sm.Any(ctx, g, func(ctx context.Context, id string, c session.Caller) error {
 		client := NewAuthClient(c.Conn())
 
 		resp, err := client.GetTokenAuthority(ctx, &GetTokenAuthorityRequest{
 			Host: host,
+			Role: role,
 			Salt: getSalt(),
 		})
 		if err != nil {
@@ -31,6 +31,7 @@ const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
 type CredentialsRequest struct {
 	Host string `protobuf:"bytes,1,opt,name=Host,proto3" json:"Host,omitempty"`
+	Role string `protobuf:"bytes,2,opt,name=Role,proto3" json:"Role,omitempty"`
 }
 
 func (m *CredentialsRequest) Reset()      { *m = CredentialsRequest{} }
@@ -72,6 +73,13 @@ func (m *CredentialsRequest) GetHost() string {
 	return ""
 }
 
+func (m *CredentialsRequest) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
 type CredentialsResponse struct {
 	Username string `protobuf:"bytes,1,opt,name=Username,proto3" json:"Username,omitempty"`
 	Secret   string `protobuf:"bytes,2,opt,name=Secret,proto3" json:"Secret,omitempty"`
@@ -129,6 +137,7 @@ type FetchTokenRequest struct {
 	Realm    string   `protobuf:"bytes,3,opt,name=Realm,proto3" json:"Realm,omitempty"`
 	Service  string   `protobuf:"bytes,4,opt,name=Service,proto3" json:"Service,omitempty"`
 	Scopes   []string `protobuf:"bytes,5,rep,name=Scopes,proto3" json:"Scopes,omitempty"`
+	Role     string   `protobuf:"bytes,6,opt,name=Role,proto3" json:"Role,omitempty"`
 }
 
 func (m *FetchTokenRequest) Reset()      { *m = FetchTokenRequest{} }
@@ -198,6 +207,13 @@ func (m *FetchTokenRequest) GetScopes() []string {
 	return nil
 }
 
+func (m *FetchTokenRequest) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
 type FetchTokenResponse struct {
 	Token     string `protobuf:"bytes,1,opt,name=Token,proto3" json:"Token,omitempty"`
 	ExpiresIn int64  `protobuf:"varint,2,opt,name=ExpiresIn,proto3" json:"ExpiresIn,omitempty"`
@@ -260,6 +276,7 @@ func (m *FetchTokenResponse) GetIssuedAt() int64 {
 type GetTokenAuthorityRequest struct {
 	Host string `protobuf:"bytes,1,opt,name=Host,proto3" json:"Host,omitempty"`
 	Salt []byte `protobuf:"bytes,2,opt,name=Salt,proto3" json:"Salt,omitempty"`
+	Role string `protobuf:"bytes,3,opt,name=Role,proto3" json:"Role,omitempty"`
 }
 
 func (m *GetTokenAuthorityRequest) Reset()      { *m = GetTokenAuthorityRequest{} }
@@ -308,6 +325,13 @@ func (m *GetTokenAuthorityRequest) GetSalt() []byte {
 	return nil
 }
 
+func (m *GetTokenAuthorityRequest) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
 type GetTokenAuthorityResponse struct {
 	PublicKey []byte `protobuf:"bytes,1,opt,name=PublicKey,proto3" json:"PublicKey,omitempty"`
 }
@@ -355,6 +379,7 @@ type VerifyTokenAuthorityRequest struct {
 	Host    string `protobuf:"bytes,1,opt,name=Host,proto3" json:"Host,omitempty"`
 	Payload []byte `protobuf:"bytes,2,opt,name=Payload,proto3" json:"Payload,omitempty"`
 	Salt    []byte `protobuf:"bytes,3,opt,name=Salt,proto3" json:"Salt,omitempty"`
+	Role    string `protobuf:"bytes,4,opt,name=Role,proto3" json:"Role,omitempty"`
 }
 
 func (m *VerifyTokenAuthorityRequest) Reset()      { *m = VerifyTokenAuthorityRequest{} }
@@ -410,6 +435,13 @@ func (m *VerifyTokenAuthorityRequest) GetSalt() []byte {
 	return nil
 }
 
+func (m *VerifyTokenAuthorityRequest) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
 type VerifyTokenAuthorityResponse struct {
 	Signed []byte `protobuf:"bytes,1,opt,name=Signed,proto3" json:"Signed,omitempty"`
 }
@@ -467,40 +499,40 @@ func init() {
 func init() { proto.RegisterFile("auth.proto", fileDescriptor_8bbd6f3875b0e874) }
 
 var fileDescriptor_8bbd6f3875b0e874 = []byte{
-	// 513 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x94, 0xcd, 0x6e, 0xd3, 0x40,
-	0x10, 0xc7, 0xbd, 0x75, 0xd2, 0x36, 0x43, 0x0f, 0x74, 0x89, 0x90, 0x31, 0xd1, 0xaa, 0x32, 0x45,
-	0xaa, 0x40, 0x58, 0x02, 0x24, 0x24, 0xb8, 0xb5, 0xe5, 0x2b, 0xe2, 0x52, 0x39, 0x7c, 0x48, 0xbd,
-	0x20, 0xc7, 0x9e, 0x12, 0x0b, 0xc7, 0x0e, 0xde, 0x75, 0x85, 0x6f, 0xdc, 0xb9, 0xf0, 0x08, 0x1c,
-	0x79, 0x14, 0x8e, 0x39, 0xf6, 0x48, 0x9c, 0x0b, 0xc7, 0x3c, 0x02, 0xf2, 0x66, 0x9d, 0x04, 0x1c,
-	0xd2, 0xdc, 0xfc, 0x1f, 0xff, 0x77, 0xe6, 0xb7, 0x33, 0xa3, 0x05, 0x70, 0x53, 0xd1, 0xb3, 0x07,
-	0x49, 0x2c, 0x62, 0x7a, 0xb5, 0x1f, 0x77, 0x33, 0xfb, 0x2c, 0x08, 0x91, 0x67, 0x91, 0x67, 0x9f,
-	0xdf, 0xb7, 0x0e, 0x80, 0x1e, 0x27, 0xe8, 0x63, 0x24, 0x02, 0x37, 0xe4, 0x0e, 0x7e, 0x4a, 0x91,
-	0x0b, 0x4a, 0xa1, 0xf6, 0x32, 0xe6, 0xc2, 0x20, 0x7b, 0xe4, 0xa0, 0xe1, 0xc8, 0x6f, 0xab, 0x0d,
-	0xd7, 0xfe, 0x72, 0xf2, 0x41, 0x1c, 0x71, 0xa4, 0x26, 0x6c, 0xbf, 0xe1, 0x98, 0x44, 0x6e, 0x1f,
-	0x95, 0x7d, 0xa6, 0xe9, 0x75, 0xd8, 0xec, 0xa0, 0x97, 0xa0, 0x30, 0x36, 0xe4, 0x1f, 0xa5, 0xac,
-	0xaf, 0x04, 0x76, 0x9f, 0xa3, 0xf0, 0x7a, 0xaf, 0xe3, 0x8f, 0x18, 0x95, 0x45, 0x4d, 0xd8, 0x3e,
-	0x0e, 0x03, 0x8c, 0x44, 0xfb, 0x69, 0x99, 0xa9, 0xd4, 0x33, 0xa0, 0x8d, 0x39, 0x10, 0x6d, 0x42,
-	0xdd, 0x41, 0x37, 0xec, 0x1b, 0xba, 0x0c, 0x4e, 0x05, 0x35, 0x60, 0xab, 0x83, 0xc9, 0x79, 0xe0,
-	0xa1, 0x51, 0x93, 0xf1, 0x52, 0x4a, 0x1a, 0x2f, 0x1e, 0x20, 0x37, 0xea, 0x7b, 0xba, 0xa4, 0x91,
-	0xca, 0xf2, 0x81, 0x2e, 0xc2, 0xa8, 0x7b, 0x35, 0xa1, 0x2e, 0x03, 0x0a, 0x65, 0x2a, 0x68, 0x0b,
-	0x1a, 0xcf, 0x3e, 0x0f, 0x82, 0x04, 0x79, 0x3b, 0x92, 0x30, 0xba, 0x33, 0x0f, 0x14, 0x37, 0x68,
-	0x73, 0x9e, 0xa2, 0x7f, 0x28, 0x24, 0x94, 0xee, 0xcc, 0xb4, 0x75, 0x04, 0xc6, 0x0b, 0x14, 0x32,
-	0xcb, 0x61, 0x2a, 0x7a, 0x71, 0x12, 0x88, 0x6c, 0x45, 0xbb, 0x8b, 0x58, 0xc7, 0x0d, 0xa7, 0x37,
-	0xde, 0x71, 0xe4, 0xb7, 0xf5, 0x18, 0x6e, 0x2c, 0xc9, 0xa1, 0x80, 0x5b, 0xd0, 0x38, 0x49, 0xbb,
-	0x61, 0xe0, 0xbd, 0xc2, 0x4c, 0x66, 0xda, 0x71, 0xe6, 0x01, 0xeb, 0x3d, 0xdc, 0x7c, 0x8b, 0x49,
-	0x70, 0x96, 0xad, 0x4f, 0x60, 0xc0, 0xd6, 0x89, 0x9b, 0x85, 0xb1, 0xeb, 0x2b, 0x88, 0x52, 0xce,
-	0xd8, 0xf4, 0x05, 0xb6, 0x47, 0xd0, 0x5a, 0x5e, 0x40, 0xe1, 0x15, 0xdd, 0x0f, 0x3e, 0x44, 0xe8,
-	0x2b, 0x36, 0xa5, 0x1e, 0x7c, 0xd7, 0xa1, 0x56, 0xb8, 0xe9, 0x29, 0x5c, 0x59, 0xd8, 0x2f, 0xba,
-	0x6f, 0xff, 0xbb, 0xab, 0x76, 0x75, 0x51, 0xcd, 0xdb, 0x97, 0xb8, 0x54, 0xf1, 0x77, 0x00, 0xf3,
-	0x11, 0xd3, 0x5b, 0xd5, 0x43, 0x95, 0x6d, 0x34, 0xf7, 0x57, 0x9b, 0x54, 0xe2, 0x10, 0x76, 0x2b,
-	0x13, 0xa1, 0x77, 0xaa, 0x47, 0xff, 0x37, 0x7a, 0xf3, 0xee, 0x5a, 0x5e, 0x55, 0x2d, 0x85, 0xe6,
-	0xb2, 0x1e, 0xd3, 0x7b, 0xd5, 0x24, 0x2b, 0x86, 0x6d, 0xda, 0xeb, 0xda, 0xa7, 0x65, 0x8f, 0x9e,
-	0x0c, 0x47, 0x4c, 0xbb, 0x18, 0x31, 0x6d, 0x32, 0x62, 0xe4, 0x4b, 0xce, 0xc8, 0x8f, 0x9c, 0x91,
-	0x9f, 0x39, 0x23, 0xc3, 0x9c, 0x91, 0x5f, 0x39, 0x23, 0xbf, 0x73, 0xa6, 0x4d, 0x72, 0x46, 0xbe,
-	0x8d, 0x99, 0x36, 0x1c, 0x33, 0xed, 0x62, 0xcc, 0xb4, 0xd3, 0x5a, 0xf1, 0xee, 0x74, 0x37, 0xe5,
-	0xc3, 0xf3, 0xf0, 0x4f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xba, 0xb3, 0x18, 0x70, 0x86, 0x04, 0x00,
-	0x00,
+	// 527 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x94, 0xcf, 0x6e, 0xd3, 0x4c,
+	0x14, 0xc5, 0x3d, 0x75, 0x92, 0x36, 0xf7, 0xeb, 0xe2, 0xeb, 0x10, 0x21, 0x63, 0xa2, 0x51, 0x15,
+	0x8a, 0x84, 0x40, 0x58, 0x02, 0x24, 0x24, 0x10, 0x9b, 0x52, 0xfe, 0x45, 0x6c, 0x2a, 0x07, 0x8a,
+	0xd4, 0x9d, 0xe3, 0xdc, 0x12, 0x0b, 0xc7, 0x13, 0x3c, 0xe3, 0x0a, 0xef, 0x78, 0x04, 0xde, 0x00,
+	0x96, 0x3c, 0x0a, 0xcb, 0x2c, 0xbb, 0x24, 0xce, 0x86, 0x65, 0x1f, 0x01, 0x79, 0x32, 0x71, 0x0c,
+	0x0e, 0x6d, 0x76, 0x73, 0xae, 0xee, 0x9c, 0xfb, 0xbb, 0x9e, 0x23, 0x03, 0x78, 0x89, 0x1c, 0x3a,
+	0xe3, 0x98, 0x4b, 0x4e, 0xff, 0x1f, 0xf1, 0x7e, 0xea, 0x9c, 0x04, 0x21, 0x8a, 0x34, 0xf2, 0x9d,
+	0xd3, 0x7b, 0x9d, 0x27, 0x40, 0x0f, 0x62, 0x1c, 0x60, 0x24, 0x03, 0x2f, 0x14, 0x2e, 0x7e, 0x4c,
+	0x50, 0x48, 0x4a, 0xa1, 0xf6, 0x8a, 0x0b, 0x69, 0x91, 0x5d, 0x72, 0xab, 0xe9, 0xaa, 0x73, 0x5e,
+	0x73, 0x79, 0x88, 0xd6, 0xc6, 0xbc, 0x96, 0x9f, 0x3b, 0x5d, 0xb8, 0xf2, 0xc7, 0x6d, 0x31, 0xe6,
+	0x91, 0x40, 0x6a, 0xc3, 0xd6, 0x5b, 0x81, 0x71, 0xe4, 0x8d, 0x50, 0x5b, 0x14, 0x9a, 0x5e, 0x85,
+	0x46, 0x0f, 0xfd, 0x18, 0xa5, 0x36, 0xd2, 0xaa, 0xf3, 0x95, 0xc0, 0xce, 0x0b, 0x94, 0xfe, 0xf0,
+	0x0d, 0xff, 0x80, 0xd1, 0x02, 0xc4, 0x86, 0xad, 0x83, 0x30, 0xc0, 0x48, 0x76, 0x9f, 0x2d, 0x9c,
+	0x16, 0xba, 0x80, 0xdc, 0x28, 0x41, 0xb6, 0xa0, 0xee, 0xa2, 0x17, 0x8e, 0x2c, 0x53, 0x15, 0xe7,
+	0x82, 0x5a, 0xb0, 0xd9, 0xc3, 0xf8, 0x34, 0xf0, 0xd1, 0xaa, 0xa9, 0xfa, 0x42, 0x2a, 0x1a, 0x9f,
+	0x8f, 0x51, 0x58, 0xf5, 0x5d, 0x53, 0xd1, 0x28, 0x55, 0x2c, 0xdb, 0x28, 0x2d, 0x3b, 0x00, 0x5a,
+	0x06, 0xd4, 0xbb, 0xb6, 0xa0, 0xae, 0x0a, 0x1a, 0x6f, 0x2e, 0x68, 0x1b, 0x9a, 0xcf, 0x3f, 0x8d,
+	0x83, 0x18, 0x45, 0x37, 0x52, 0x80, 0xa6, 0xbb, 0x2c, 0xe4, 0x5b, 0x75, 0x85, 0x48, 0x70, 0xb0,
+	0x2f, 0x15, 0xa8, 0xe9, 0x16, 0xba, 0x73, 0x04, 0xd6, 0x4b, 0x94, 0xca, 0x65, 0x3f, 0x91, 0x43,
+	0x1e, 0x07, 0x32, 0xbd, 0xe4, 0x59, 0x7a, 0x5e, 0x38, 0xff, 0x0a, 0xdb, 0xae, 0x3a, 0x17, 0xf4,
+	0x66, 0x89, 0xfe, 0x11, 0x5c, 0x5b, 0xe1, 0xab, 0x97, 0x68, 0x43, 0xf3, 0x30, 0xe9, 0x87, 0x81,
+	0xff, 0x1a, 0x53, 0xe5, 0xbe, 0xed, 0x2e, 0x0b, 0x1d, 0x01, 0xd7, 0x8f, 0x30, 0x0e, 0x4e, 0xd2,
+	0xf5, 0xa9, 0x2c, 0xd8, 0x3c, 0xf4, 0xd2, 0x90, 0x7b, 0x03, 0x0d, 0xb6, 0x90, 0x05, 0xaf, 0xb9,
+	0x82, 0xb7, 0x56, 0xe2, 0x7d, 0x08, 0xed, 0xd5, 0x43, 0x35, 0x72, 0xfe, 0x72, 0xc1, 0xfb, 0x08,
+	0x07, 0x9a, 0x57, 0xab, 0xfb, 0xdf, 0x4c, 0xa8, 0xe5, 0xdd, 0xf4, 0x18, 0xfe, 0x2b, 0x65, 0x93,
+	0xee, 0x39, 0x7f, 0x67, 0xdf, 0xa9, 0x06, 0xdf, 0xbe, 0x79, 0x49, 0x97, 0x1e, 0xfe, 0x0e, 0x60,
+	0x19, 0x05, 0x7a, 0xa3, 0x7a, 0xa9, 0x92, 0x64, 0x7b, 0xef, 0xe2, 0x26, 0x6d, 0x1c, 0xc2, 0x4e,
+	0xe5, 0x95, 0xe8, 0xed, 0xea, 0xd5, 0x7f, 0x45, 0xc4, 0xbe, 0xb3, 0x56, 0xaf, 0x9e, 0x96, 0x40,
+	0x6b, 0xd5, 0x37, 0xa6, 0x77, 0xab, 0x26, 0x17, 0x04, 0xc0, 0x76, 0xd6, 0x6d, 0x9f, 0x8f, 0x7d,
+	0xfa, 0x78, 0x32, 0x65, 0xc6, 0xd9, 0x94, 0x19, 0xe7, 0x53, 0x46, 0x3e, 0x67, 0x8c, 0x7c, 0xcf,
+	0x18, 0xf9, 0x91, 0x31, 0x32, 0xc9, 0x18, 0xf9, 0x99, 0x31, 0xf2, 0x2b, 0x63, 0xc6, 0x79, 0xc6,
+	0xc8, 0x97, 0x19, 0x33, 0x26, 0x33, 0x66, 0x9c, 0xcd, 0x98, 0x71, 0x5c, 0xcb, 0xff, 0x63, 0xfd,
+	0x86, 0xfa, 0x91, 0x3d, 0xf8, 0x3d, 0x00, 0xaa, 0x64, 0x66, 0x6a, 0xd6, 0x04, 0x00, 0x00,
 }
 
 func (this *CredentialsRequest) Equal(that interface{}) bool {
@@ -525,6 +557,9 @@ func (this *CredentialsRequest) Equal(that interface{}) bool {
 	if this.Host != that1.Host {
 		return false
 	}
+	if this.Role != that1.Role {
+		return false
+	}
 	return true
 }
 func (this *CredentialsResponse) Equal(that interface{}) bool {
@@ -593,6 +628,9 @@ func (this *FetchTokenRequest) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if this.Role != that1.Role {
+		return false
+	}
 	return true
 }
 func (this *FetchTokenResponse) Equal(that interface{}) bool {
@@ -650,6 +688,9 @@ func (this *GetTokenAuthorityRequest) Equal(that interface{}) bool {
 	if !bytes.Equal(this.Salt, that1.Salt) {
 		return false
 	}
+	if this.Role != that1.Role {
+		return false
+	}
 	return true
 }
 func (this *GetTokenAuthorityResponse) Equal(that interface{}) bool {
@@ -704,6 +745,9 @@ func (this *VerifyTokenAuthorityRequest) Equal(that interface{}) bool {
 	if !bytes.Equal(this.Salt, that1.Salt) {
 		return false
 	}
+	if this.Role != that1.Role {
+		return false
+	}
 	return true
 }
 func (this *VerifyTokenAuthorityResponse) Equal(that interface{}) bool {
@@ -734,9 +778,10 @@ func (this *CredentialsRequest) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 5)
+	s := make([]string, 0, 6)
 	s = append(s, "&auth.CredentialsRequest{")
 	s = append(s, "Host: "+fmt.Sprintf("%#v", this.Host)+",\n")
+	s = append(s, "Role: "+fmt.Sprintf("%#v", this.Role)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -755,13 +800,14 @@ func (this *FetchTokenRequest) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 9)
+	s := make([]string, 0, 10)
 	s = append(s, "&auth.FetchTokenRequest{")
 	s = append(s, "ClientID: "+fmt.Sprintf("%#v", this.ClientID)+",\n")
 	s = append(s, "Host: "+fmt.Sprintf("%#v", this.Host)+",\n")
 	s = append(s, "Realm: "+fmt.Sprintf("%#v", this.Realm)+",\n")
 	s = append(s, "Service: "+fmt.Sprintf("%#v", this.Service)+",\n")
 	s = append(s, "Scopes: "+fmt.Sprintf("%#v", this.Scopes)+",\n")
+	s = append(s, "Role: "+fmt.Sprintf("%#v", this.Role)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -781,10 +827,11 @@ func (this *GetTokenAuthorityRequest) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 6)
+	s := make([]string, 0, 7)
 	s = append(s, "&auth.GetTokenAuthorityRequest{")
 	s = append(s, "Host: "+fmt.Sprintf("%#v", this.Host)+",\n")
 	s = append(s, "Salt: "+fmt.Sprintf("%#v", this.Salt)+",\n")
+	s = append(s, "Role: "+fmt.Sprintf("%#v", this.Role)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -802,11 +849,12 @@ func (this *VerifyTokenAuthorityRequest) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 7)
+	s := make([]string, 0, 8)
 	s = append(s, "&auth.VerifyTokenAuthorityRequest{")
 	s = append(s, "Host: "+fmt.Sprintf("%#v", this.Host)+",\n")
 	s = append(s, "Payload: "+fmt.Sprintf("%#v", this.Payload)+",\n")
 	s = append(s, "Salt: "+fmt.Sprintf("%#v", this.Salt)+",\n")
+	s = append(s, "Role: "+fmt.Sprintf("%#v", this.Role)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -1037,6 +1085,13 @@ func (m *CredentialsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Role) > 0 {
+		i -= len(m.Role)
+		copy(dAtA[i:], m.Role)
+		i = encodeVarintAuth(dAtA, i, uint64(len(m.Role)))
+		i--
+		dAtA[i] = 0x12
+	}
 	if len(m.Host) > 0 {
 		i -= len(m.Host)
 		copy(dAtA[i:], m.Host)
@@ -1104,6 +1159,13 @@ func (m *FetchTokenRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Role) > 0 {
+		i -= len(m.Role)
+		copy(dAtA[i:], m.Role)
+		i = encodeVarintAuth(dAtA, i, uint64(len(m.Role)))
+		i--
+		dAtA[i] = 0x32
+	}
 	if len(m.Scopes) > 0 {
 		for iNdEx := len(m.Scopes) - 1; iNdEx >= 0; iNdEx-- {
 			i -= len(m.Scopes[iNdEx])
@@ -1204,6 +1266,13 @@ func (m *GetTokenAuthorityRequest) MarshalToSizedBuffer(dAtA []byte) (int, error
 	_ = i
 	var l int
 	_ = l
+	if len(m.Role) > 0 {
+		i -= len(m.Role)
+		copy(dAtA[i:], m.Role)
+		i = encodeVarintAuth(dAtA, i, uint64(len(m.Role)))
+		i--
+		dAtA[i] = 0x1a
+	}
 	if len(m.Salt) > 0 {
 		i -= len(m.Salt)
 		copy(dAtA[i:], m.Salt)
@@ -1271,6 +1340,13 @@ func (m *VerifyTokenAuthorityRequest) MarshalToSizedBuffer(dAtA []byte) (int, er
 	_ = i
 	var l int
 	_ = l
+	if len(m.Role) > 0 {
+		i -= len(m.Role)
+		copy(dAtA[i:], m.Role)
+		i = encodeVarintAuth(dAtA, i, uint64(len(m.Role)))
+		i--
+		dAtA[i] = 0x22
+	}
 	if len(m.Salt) > 0 {
 		i -= len(m.Salt)
 		copy(dAtA[i:], m.Salt)
@@ -1346,6 +1422,10 @@ func (m *CredentialsRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovAuth(uint64(l))
 	}
+	l = len(m.Role)
+	if l > 0 {
+		n += 1 + l + sovAuth(uint64(l))
+	}
 	return n
 }
 
@@ -1394,6 +1474,10 @@ func (m *FetchTokenRequest) Size() (n int) {
 			n += 1 + l + sovAuth(uint64(l))
 		}
 	}
+	l = len(m.Role)
+	if l > 0 {
+		n += 1 + l + sovAuth(uint64(l))
+	}
 	return n
 }
 
@@ -1430,6 +1514,10 @@ func (m *GetTokenAuthorityRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovAuth(uint64(l))
 	}
+	l = len(m.Role)
+	if l > 0 {
+		n += 1 + l + sovAuth(uint64(l))
+	}
 	return n
 }
 
@@ -1464,6 +1552,10 @@ func (m *VerifyTokenAuthorityRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovAuth(uint64(l))
 	}
+	l = len(m.Role)
+	if l > 0 {
+		n += 1 + l + sovAuth(uint64(l))
+	}
 	return n
 }
 
@@ -1492,6 +1584,7 @@ func (this *CredentialsRequest) String() string {
 	}
 	s := strings.Join([]string{`&CredentialsRequest{`,
 		`Host:` + fmt.Sprintf("%v", this.Host) + `,`,
+		`Role:` + fmt.Sprintf("%v", this.Role) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -1517,6 +1610,7 @@ func (this *FetchTokenRequest) String() string {
 		`Realm:` + fmt.Sprintf("%v", this.Realm) + `,`,
 		`Service:` + fmt.Sprintf("%v", this.Service) + `,`,
 		`Scopes:` + fmt.Sprintf("%v", this.Scopes) + `,`,
+		`Role:` + fmt.Sprintf("%v", this.Role) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -1540,6 +1634,7 @@ func (this *GetTokenAuthorityRequest) String() string {
 	s := strings.Join([]string{`&GetTokenAuthorityRequest{`,
 		`Host:` + fmt.Sprintf("%v", this.Host) + `,`,
 		`Salt:` + fmt.Sprintf("%v", this.Salt) + `,`,
+		`Role:` + fmt.Sprintf("%v", this.Role) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -1562,6 +1657,7 @@ func (this *VerifyTokenAuthorityRequest) String() string {
 		`Host:` + fmt.Sprintf("%v", this.Host) + `,`,
 		`Payload:` + fmt.Sprintf("%v", this.Payload) + `,`,
 		`Salt:` + fmt.Sprintf("%v", this.Salt) + `,`,
+		`Role:` + fmt.Sprintf("%v", this.Role) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -1645,6 +1741,38 @@ func (m *CredentialsRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.Host = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Role", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuth
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAuth
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAuth
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Role = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAuth(dAtA[iNdEx:])
@@ -1969,6 +2097,38 @@ func (m *FetchTokenRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.Scopes = append(m.Scopes, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Role", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuth
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAuth
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAuth
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Role = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAuth(dAtA[iNdEx:])
@@ -2205,6 +2365,38 @@ func (m *GetTokenAuthorityRequest) Unmarshal(dAtA []byte) error {
 				m.Salt = []byte{}
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Role", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuth
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAuth
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAuth
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Role = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAuth(dAtA[iNdEx:])
@@ -2439,6 +2631,38 @@ func (m *VerifyTokenAuthorityRequest) Unmarshal(dAtA []byte) error {
 				m.Salt = []byte{}
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Role", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuth
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAuth
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAuth
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Role = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAuth(dAtA[iNdEx:])
@@ -18,6 +18,7 @@ import (
 	remoteserrors "github.com/containerd/containerd/remotes/errors"
 	"github.com/docker/cli/cli/config"
 	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/types"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/session/auth"
 	"github.com/moby/buildkit/util/progress/progresswriter"
@@ -36,8 +37,31 @@ func NewDockerAuthProvider(stderr io.Writer) session.Attachable {
 	}
 }
 
+// NewDockerAuthProviderWithRoles returns an auth provider that looks up the
+// credentials requested for a role, like "pull" or "push", in the docker
+// config of the directory of the role first. Hosts that are not configured
+// for the role use the credentials of the default docker config.
+func NewDockerAuthProviderWithRoles(stderr io.Writer, dirs map[string]string) (session.Attachable, error) {
+	ap := &authProvider{
+		config:      config.LoadDefaultConfigFile(stderr),
+		roles:       map[string]*configfile.ConfigFile{},
+		seeds:       &tokenSeeds{dir: config.Dir()},
+		loggerCache: map[string]struct{}{},
+	}
+	for role, dir := range dirs {
+		cfg, err := config.Load(dir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load docker config for role %s", role)
+		}
+		ap.roles[role] = cfg
+	}
+	return ap, nil
+}
+
 type authProvider struct {
 	config      *configfile.ConfigFile
+	roles       map[string]*configfile.ConfigFile
+	tokenFiles  map[string]*tokenFile
 	seeds       *tokenSeeds
 	logger      progresswriter.Logger
 	loggerCache map[string]struct{}
@@ -60,7 +84,7 @@ func (ap *authProvider) Register(server *grpc.Server) {
 }
 
 func (ap *authProvider) FetchToken(ctx context.Context, req *auth.FetchTokenRequest) (rr *auth.FetchTokenResponse, err error) {
-	creds, err := ap.credentials(req.Host)
+	creds, _, err := ap.credentials(req.Host, req.Role)
 	if err != nil {
 		return nil, err
 	}
@@ -116,16 +140,35 @@ func (ap *authProvider) FetchToken(ctx context.Context, req *auth.FetchTokenRequ
 	return toTokenResponse(resp.Token, resp.IssuedAt, resp.ExpiresIn), nil
 }
 
-func (ap *authProvider) credentials(host string) (*auth.CredentialsResponse, error) {
+// credentials returns the credentials of the host for the role. The returned
+// bool is true if the credentials are specific to the role.
+func (ap *authProvider) credentials(host, role string) (*auth.CredentialsResponse, bool, error) {
 	ap.mu.Lock()
 	defer ap.mu.Unlock()
+	if tf, ok := ap.tokenFile(host); ok {
+		creds, err := tf.credentials()
+		return creds, false, err
+	}
 	if host == "registry-1.docker.io" {
 		host = "https://index.docker.io/v1/"
 	}
+	if cfg, ok := ap.roles[role]; ok {
+		ac, err := cfg.GetAuthConfig(host)
+		if err != nil {
+			return nil, false, err
+		}
+		if ac.IdentityToken != "" || ac.Password != "" {
+			return toCredentialsResponse(ac), true, nil
+		}
+	}
 	ac, err := ap.config.GetAuthConfig(host)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
+	return toCredentialsResponse(ac), false, nil
+}
+
+func toCredentialsResponse(ac types.AuthConfig) *auth.CredentialsResponse {
 	res := &auth.CredentialsResponse{}
 	if ac.IdentityToken != "" {
 		res.Secret = ac.IdentityToken
@@ -133,18 +176,22 @@ func (ap *authProvider) credentials(host string) (*auth.CredentialsResponse, err
 		res.Username = ac.Username
 		res.Secret = ac.Password
 	}
-	return res, nil
+	return res
 }
 
 func (ap *authProvider) Credentials(ctx context.Context, req *auth.CredentialsRequest) (*auth.CredentialsResponse, error) {
-	resp, err := ap.credentials(req.Host)
+	resp, scoped, err := ap.credentials(req.Host, req.Role)
 	if err != nil || resp.Secret != "" {
 		ap.mu.Lock()
 		defer ap.mu.Unlock()
-		_, ok := ap.loggerCache[req.Host]
-		ap.loggerCache[req.Host] = struct{}{}
+		name := fmt.Sprintf("[auth] sharing credentials for %s", req.Host)
+		if scoped {
+			name = fmt.Sprintf("[auth] sharing %s credentials for %s", req.Role, req.Host)
+		}
+		_, ok := ap.loggerCache[name]
+		ap.loggerCache[name] = struct{}{}
 		if !ok {
-			return resp, progresswriter.Wrap(fmt.Sprintf("[auth] sharing credentials for %s", req.Host), ap.logger, func(progresswriter.SubLogger) error {
+			return resp, progresswriter.Wrap(name, ap.logger, func(progresswriter.SubLogger) error {
 				return err
 			})
 		}
@@ -153,7 +200,7 @@ func (ap *authProvider) Credentials(ctx context.Context, req *auth.CredentialsRe
 }
 
 func (ap *authProvider) GetTokenAuthority(ctx context.Context, req *auth.GetTokenAuthorityRequest) (*auth.GetTokenAuthorityResponse, error) {
-	key, err := ap.getAuthorityKey(req.Host, req.Salt)
+	key, err := ap.getAuthorityKey(req.Host, req.Role, req.Salt)
 	if err != nil {
 		return nil, err
 	}
@@ -162,7 +209,7 @@ func (ap *authProvider) GetTokenAuthority(ctx context.Context, req *auth.GetToke
 }
 
 func (ap *authProvider) VerifyTokenAuthority(ctx context.Context, req *auth.VerifyTokenAuthorityRequest) (*auth.VerifyTokenAuthorityResponse, error) {
-	key, err := ap.getAuthorityKey(req.Host, req.Salt)
+	key, err := ap.getAuthorityKey(req.Host, req.Role, req.Salt)
 	if err != nil {
 		return nil, err
 	}
@@ -173,12 +220,12 @@ func (ap *authProvider) VerifyTokenAuthority(ctx context.Context, req *auth.Veri
 	return &auth.VerifyTokenAuthorityResponse{Signed: sign.Sign(nil, req.Payload, priv)}, nil
 }
 
-func (ap *authProvider) getAuthorityKey(host string, salt []byte) (ed25519.PrivateKey, error) {
+func (ap *authProvider) getAuthorityKey(host, role string, salt []byte) (ed25519.PrivateKey, error) {
 	if v, err := strconv.ParseBool(os.Getenv("BUILDKIT_NO_CLIENT_TOKEN")); err == nil && v {
 		return nil, status.Errorf(codes.Unavailable, "client side tokens disabled")
 	}
 
-	creds, err := ap.credentials(host)
+	creds, scoped, err := ap.credentials(host, role)
 	if err != nil {
 		return nil, err
 	}
@@ -190,6 +237,11 @@ func (ap *authProvider) getAuthorityKey(host string, salt []byte) (ed25519.Priva
 	mac := hmac.New(sha256.New, salt)
 	if creds.Secret != "" {
 		mac.Write(seed)
+		// tokens signed for the credentials of a role must not verify for
+		// the default credentials
+		if scoped {
+			mac.Write([]byte(role))
+		}
 	}
 
 	sum := mac.Sum(nil)
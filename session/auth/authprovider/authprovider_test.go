@@ -0,0 +1,101 @@
+package authprovider
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/moby/buildkit/session/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialsRoles(t *testing.T) {
+	t.Parallel()
+
+	tmpdir, err := ioutil.TempDir("", "authprovider")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	writeConfig := func(name, auths string) string {
+		dir := filepath.Join(tmpdir, name)
+		require.NoError(t, os.MkdirAll(dir, 0700))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"auths":`+auths+`}`), 0600))
+		return dir
+	}
+
+	// base64 of "user:pass" and "pusher:pushpass"
+	def := writeConfig("default", `{"example.com":{"auth":"dXNlcjpwYXNz"},"other.com":{"auth":"dXNlcjpwYXNz"}}`)
+	push := writeConfig("push", `{"example.com":{"auth":"cHVzaGVyOnB1c2hwYXNz"}}`)
+
+	a, err := NewDockerAuthProviderWithRoles(ioutil.Discard, map[string]string{auth.RolePush: push})
+	require.NoError(t, err)
+	ap := a.(*authProvider)
+	ap.config, err = config.Load(def)
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	resp, err := ap.Credentials(ctx, &auth.CredentialsRequest{Host: "example.com", Role: auth.RolePush})
+	require.NoError(t, err)
+	require.Equal(t, "pusher", resp.Username)
+	require.Equal(t, "pushpass", resp.Secret)
+
+	resp, err = ap.Credentials(ctx, &auth.CredentialsRequest{Host: "example.com", Role: auth.RolePull})
+	require.NoError(t, err)
+	require.Equal(t, "user", resp.Username)
+	require.Equal(t, "pass", resp.Secret)
+
+	resp, err = ap.Credentials(ctx, &auth.CredentialsRequest{Host: "example.com"})
+	require.NoError(t, err)
+	require.Equal(t, "user", resp.Username)
+
+	// hosts missing from the config of the role use the default config
+	resp, err = ap.Credentials(ctx, &auth.CredentialsRequest{Host: "other.com", Role: auth.RolePush})
+	require.NoError(t, err)
+	require.Equal(t, "user", resp.Username)
+	require.Equal(t, "pass", resp.Secret)
+}
+
+func TestCredentialsTokenFile(t *testing.T) {
+	t.Parallel()
+
+	tmpdir, err := ioutil.TempDir("", "authprovider")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	tokenPath := filepath.Join(tmpdir, "token")
+	require.NoError(t, ioutil.WriteFile(tokenPath, []byte("token1\n"), 0600))
+
+	_, err = WithTokenFiles(NewDockerAuthProvider(ioutil.Discard), []TokenFile{{Host: "example.com", Path: filepath.Join(tmpdir, "missing")}})
+	require.Error(t, err)
+
+	a, err := WithTokenFiles(NewDockerAuthProvider(ioutil.Discard), []TokenFile{
+		{Host: "example.com", Path: tokenPath, Username: "oauth2"},
+		{Host: "docker.io", Path: tokenPath},
+	})
+	require.NoError(t, err)
+	ap := a.(*authProvider)
+
+	ctx := context.TODO()
+	resp, err := ap.Credentials(ctx, &auth.CredentialsRequest{Host: "example.com", Role: auth.RolePush})
+	require.NoError(t, err)
+	require.Equal(t, "oauth2", resp.Username)
+	require.Equal(t, "token1", resp.Secret)
+
+	// the renewed token is used for the next request
+	require.NoError(t, ioutil.WriteFile(tokenPath, []byte("token-renewed\n"), 0600))
+	resp, err = ap.Credentials(ctx, &auth.CredentialsRequest{Host: "example.com"})
+	require.NoError(t, err)
+	require.Equal(t, "token-renewed", resp.Secret)
+
+	resp, err = ap.Credentials(ctx, &auth.CredentialsRequest{Host: "registry-1.docker.io"})
+	require.NoError(t, err)
+	require.Equal(t, "", resp.Username)
+	require.Equal(t, "token-renewed", resp.Secret)
+
+	require.NoError(t, ioutil.WriteFile(tokenPath, nil, 0600))
+	_, err = ap.Credentials(ctx, &auth.CredentialsRequest{Host: "example.com"})
+	require.Error(t, err)
+}
@@ -0,0 +1,110 @@
+package authprovider
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/auth"
+	"github.com/pkg/errors"
+)
+
+// TokenFile is a file that contains the registry token of a host. The file
+// is read again when it has changed since the last auth request, so a CI
+// system can renew a short-lived token while a build is running.
+type TokenFile struct {
+	// Host is the registry that the token is used for
+	Host string
+	// Path is the path of the file with the token
+	Path string
+	// Username is sent with the token as the password. Without a username
+	// the token is used as an identity token.
+	Username string
+}
+
+// WithTokenFiles makes an auth provider created with NewDockerAuthProvider or
+// NewDockerAuthProviderWithRoles use the tokens of files for their hosts
+// instead of the credentials of the docker config.
+func WithTokenFiles(a session.Attachable, files []TokenFile) (session.Attachable, error) {
+	ap, ok := a.(*authProvider)
+	if !ok {
+		return nil, errors.Errorf("token files are not supported by %T", a)
+	}
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
+	if ap.tokenFiles == nil {
+		ap.tokenFiles = map[string]*tokenFile{}
+	}
+	for _, f := range files {
+		if f.Host == "" || f.Path == "" {
+			return nil, errors.Errorf("token file requires a host and a path")
+		}
+		tf := &tokenFile{TokenFile: f}
+		if _, err := tf.token(); err != nil {
+			return nil, err
+		}
+		ap.tokenFiles[f.Host] = tf
+	}
+	return ap, nil
+}
+
+type tokenFile struct {
+	TokenFile
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	value   string
+}
+
+// token returns the token of the file, reading the file again if it has been
+// modified
+func (tf *tokenFile) token() (string, error) {
+	tf.mu.Lock()
+	defer tf.mu.Unlock()
+	fi, err := os.Stat(tf.Path)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to stat token file for %s", tf.Host)
+	}
+	if tf.value != "" && fi.ModTime().Equal(tf.modTime) && fi.Size() == tf.size {
+		return tf.value, nil
+	}
+	dt, err := ioutil.ReadFile(tf.Path)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read token file for %s", tf.Host)
+	}
+	v := strings.TrimSpace(string(dt))
+	if v == "" {
+		return "", errors.Errorf("token file %s for %s is empty", tf.Path, tf.Host)
+	}
+	tf.value = v
+	tf.modTime = fi.ModTime()
+	tf.size = fi.Size()
+	return v, nil
+}
+
+func (tf *tokenFile) credentials() (*auth.CredentialsResponse, error) {
+	token, err := tf.token()
+	if err != nil {
+		return nil, err
+	}
+	return &auth.CredentialsResponse{
+		Username: tf.Username,
+		Secret:   token,
+	}, nil
+}
+
+func (ap *authProvider) tokenFile(host string) (*tokenFile, bool) {
+	if tf, ok := ap.tokenFiles[host]; ok {
+		return tf, true
+	}
+	if host == "registry-1.docker.io" {
+		if tf, ok := ap.tokenFiles["docker.io"]; ok {
+			return tf, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,218 @@
+package cabundle
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"runtime"
+	"sync"
+
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/util/flightcontrol"
+	"github.com/moby/buildkit/util/grpcerrors"
+	"github.com/moby/buildkit/util/tracing"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+)
+
+// Bundle is the CA bundle of a session
+type Bundle struct {
+	// PEM are the PEM encoded CA certificates of the bundle
+	PEM []byte
+
+	mu         sync.Mutex
+	transports map[http.RoundTripper]http.RoundTripper
+}
+
+// Digest returns the digest of the certificates of the bundle
+func (b *Bundle) Digest() digest.Digest {
+	return digest.FromBytes(b.PEM)
+}
+
+// GetCABundle returns the CA bundle that the requests of the session group
+// trust in addition to the roots of the daemon. nil is returned if none of
+// the sessions provides a bundle or the sessions can't be reached. The bundle
+// is only kept in memory until its session ends.
+func GetCABundle(ctx context.Context, sm *session.Manager, g session.Group) *Bundle {
+	var b *Bundle
+	err := sm.Any(ctx, g, func(ctx context.Context, id string, c session.Caller) error {
+		var err error
+		b, err = bundles.get(ctx, id, c)
+		return err
+	})
+	if err != nil {
+		logrus.Warnf("failed to get CA bundle of the build, using the roots of the daemon: %v", err)
+		return nil
+	}
+	return b
+}
+
+// Transport returns a copy of rt that trusts the certificates of the bundle,
+// see NewTransport. The copies are reused for the requests of the session and
+// their connections are closed when the session ends. A nil bundle returns
+// rt.
+func (b *Bundle) Transport(rt http.RoundTripper) (http.RoundTripper, error) {
+	if b == nil {
+		return rt, nil
+	}
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	switch rt.(type) {
+	case *http.Transport, *tracing.Transport:
+	default:
+		return NewTransport(rt, b.PEM)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if t, ok := b.transports[rt]; ok {
+		return t, nil
+	}
+	t, err := NewTransport(rt, b.PEM)
+	if err != nil {
+		return nil, err
+	}
+	if b.transports == nil {
+		b.transports = map[http.RoundTripper]http.RoundTripper{}
+	}
+	b.transports[rt] = t
+	return t, nil
+}
+
+func (b *Bundle) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for rt, t := range b.transports {
+		if t != rt {
+			closeIdleConnections(t)
+		}
+	}
+	b.transports = nil
+}
+
+func closeIdleConnections(rt http.RoundTripper) {
+	switch t := rt.(type) {
+	case *tracing.Transport:
+		closeIdleConnections(t.RoundTripper)
+	case *http.Transport:
+		t.CloseIdleConnections()
+	}
+}
+
+// bundles caches the CA bundles of the sessions
+var bundles = &bundleCache{m: map[string]*Bundle{}}
+
+type bundleCache struct {
+	mu sync.Mutex
+	// m is the bundle of each session, nil if the session has none
+	m map[string]*Bundle
+	g flightcontrol.Group
+}
+
+func (bc *bundleCache) get(ctx context.Context, id string, c session.Caller) (*Bundle, error) {
+	bc.mu.Lock()
+	b, ok := bc.m[id]
+	bc.mu.Unlock()
+	if ok {
+		return b, nil
+	}
+	v, err := bc.g.Do(ctx, id, func(ctx context.Context) (interface{}, error) {
+		bc.mu.Lock()
+		b, ok := bc.m[id]
+		bc.mu.Unlock()
+		if ok {
+			return b, nil
+		}
+		b, err := requestBundle(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		bc.mu.Lock()
+		bc.m[id] = b
+		bc.mu.Unlock()
+		go func() {
+			<-c.Context().Done()
+			bc.mu.Lock()
+			delete(bc.m, id)
+			bc.mu.Unlock()
+			if b != nil {
+				b.close()
+			}
+		}()
+		return b, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Bundle), nil
+}
+
+func requestBundle(ctx context.Context, c session.Caller) (*Bundle, error) {
+	client := NewCABundleClient(c.Conn())
+	resp, err := client.GetCABundle(ctx, &GetCABundleRequest{})
+	if err != nil {
+		if grpcerrors.Code(err) == codes.Unimplemented {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(resp.PEM) == 0 {
+		return nil, nil
+	}
+	return &Bundle{PEM: resp.PEM}, nil
+}
+
+// CertPool returns the system roots with the certificates of bundle
+func CertPool(bundle []byte) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		if runtime.GOOS != "windows" {
+			return nil, errors.Wrap(err, "unable to get system cert pool")
+		}
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(bundle) {
+		return nil, errors.New("no certificates in CA bundle")
+	}
+	return pool, nil
+}
+
+// NewTransport returns a copy of rt that trusts the certificates of bundle in
+// addition to the system roots. rt has to be an *http.Transport, optionally
+// wrapped with tracing.NewTransport. Transports with their own root CAs, e.g.
+// of the registry configuration of the daemon, keep using them.
+func NewTransport(rt http.RoundTripper, bundle []byte) (http.RoundTripper, error) {
+	if len(bundle) == 0 {
+		return rt, nil
+	}
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	switch t := rt.(type) {
+	case *tracing.Transport:
+		inner, err := NewTransport(t.RoundTripper, bundle)
+		if err != nil {
+			return nil, err
+		}
+		return tracing.NewTransport(inner), nil
+	case *http.Transport:
+		if t.TLSClientConfig != nil && t.TLSClientConfig.RootCAs != nil {
+			return rt, nil
+		}
+		pool, err := CertPool(bundle)
+		if err != nil {
+			return nil, err
+		}
+		t2 := t.Clone()
+		if t2.TLSClientConfig == nil {
+			t2.TLSClientConfig = &tls.Config{}
+		}
+		t2.TLSClientConfig.RootCAs = pool
+		return t2, nil
+	default:
+		return nil, errors.Errorf("CA bundles are not supported by transport %T", rt)
+	}
+}
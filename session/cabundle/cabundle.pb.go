@@ -0,0 +1,624 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: cabundle.proto
+
+package cabundle
+
+import (
+	bytes "bytes"
+	context "context"
+	fmt "fmt"
+	proto "github.com/gogo/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+	reflect "reflect"
+	strings "strings"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+type GetCABundleRequest struct {
+}
+
+func (m *GetCABundleRequest) Reset()      { *m = GetCABundleRequest{} }
+func (*GetCABundleRequest) ProtoMessage() {}
+func (*GetCABundleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9805938c4840cab9, []int{0}
+}
+func (m *GetCABundleRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GetCABundleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GetCABundleRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GetCABundleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetCABundleRequest.Merge(m, src)
+}
+func (m *GetCABundleRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *GetCABundleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetCABundleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetCABundleRequest proto.InternalMessageInfo
+
+type GetCABundleResponse struct {
+	// PEM are the PEM encoded CA certificates
+	PEM []byte `protobuf:"bytes,1,opt,name=PEM,proto3" json:"PEM,omitempty"`
+}
+
+func (m *GetCABundleResponse) Reset()      { *m = GetCABundleResponse{} }
+func (*GetCABundleResponse) ProtoMessage() {}
+func (*GetCABundleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9805938c4840cab9, []int{1}
+}
+func (m *GetCABundleResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GetCABundleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GetCABundleResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GetCABundleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetCABundleResponse.Merge(m, src)
+}
+func (m *GetCABundleResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *GetCABundleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetCABundleResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetCABundleResponse proto.InternalMessageInfo
+
+func (m *GetCABundleResponse) GetPEM() []byte {
+	if m != nil {
+		return m.PEM
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*GetCABundleRequest)(nil), "moby.buildkit.cabundle.v1.GetCABundleRequest")
+	proto.RegisterType((*GetCABundleResponse)(nil), "moby.buildkit.cabundle.v1.GetCABundleResponse")
+}
+
+func init() { proto.RegisterFile("cabundle.proto", fileDescriptor_9805938c4840cab9) }
+
+var fileDescriptor_9805938c4840cab9 = []byte{
+	// 197 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x4b, 0x4e, 0x4c, 0x2a,
+	0xcd, 0x4b, 0xc9, 0x49, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x92, 0xcc, 0xcd, 0x4f, 0xaa,
+	0xd4, 0x4b, 0x2a, 0xcd, 0xcc, 0x49, 0xc9, 0xce, 0x2c, 0xd1, 0x83, 0xcb, 0x96, 0x19, 0x2a, 0x89,
+	0x70, 0x09, 0xb9, 0xa7, 0x96, 0x38, 0x3b, 0x3a, 0x81, 0x45, 0x82, 0x52, 0x0b, 0x4b, 0x53, 0x8b,
+	0x4b, 0x94, 0xd4, 0xb9, 0x84, 0x51, 0x44, 0x8b, 0x0b, 0xf2, 0xf3, 0x8a, 0x53, 0x85, 0x04, 0xb8,
+	0x98, 0x03, 0x5c, 0x7d, 0x25, 0x18, 0x15, 0x18, 0x35, 0x78, 0x82, 0x40, 0x4c, 0xa3, 0x0a, 0x2e,
+	0x0e, 0x98, 0x2a, 0xa1, 0x1c, 0x2e, 0x6e, 0x24, 0x4d, 0x42, 0xba, 0x7a, 0x38, 0x6d, 0xd5, 0xc3,
+	0xb4, 0x52, 0x4a, 0x8f, 0x58, 0xe5, 0x10, 0xb7, 0x38, 0xd9, 0x5d, 0x78, 0x28, 0xc7, 0x70, 0xe3,
+	0xa1, 0x1c, 0xc3, 0x87, 0x87, 0x72, 0x8c, 0x0d, 0x8f, 0xe4, 0x18, 0x57, 0x3c, 0x92, 0x63, 0x3c,
+	0xf1, 0x48, 0x8e, 0xf1, 0xc2, 0x23, 0x39, 0xc6, 0x07, 0x8f, 0xe4, 0x18, 0x5f, 0x3c, 0x92, 0x63,
+	0xf8, 0xf0, 0x48, 0x8e, 0x71, 0xc2, 0x63, 0x39, 0x86, 0x0b, 0x8f, 0xe5, 0x18, 0x6e, 0x3c, 0x96,
+	0x63, 0x88, 0xe2, 0x80, 0x19, 0x9b, 0xc4, 0x06, 0x0e, 0x1a, 0x63, 0xc0, 0x00, 0x7b, 0xd8, 0x7e,
+	0xe9, 0x2c, 0x01, 0x00, 0x00,
+}
+
+func (this *GetCABundleRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*GetCABundleRequest)
+	if !ok {
+		that2, ok := that.(GetCABundleRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
+func (this *GetCABundleResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*GetCABundleResponse)
+	if !ok {
+		that2, ok := that.(GetCABundleResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.PEM, that1.PEM) {
+		return false
+	}
+	return true
+}
+func (this *GetCABundleRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&cabundle.GetCABundleRequest{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *GetCABundleResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&cabundle.GetCABundleResponse{")
+	s = append(s, "PEM: "+fmt.Sprintf("%#v", this.PEM)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func valueToGoStringCabundle(v interface{}, typ string) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// CABundleClient is the client API for CABundle service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type CABundleClient interface {
+	GetCABundle(ctx context.Context, in *GetCABundleRequest, opts ...grpc.CallOption) (*GetCABundleResponse, error)
+}
+
+type cABundleClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewCABundleClient(cc *grpc.ClientConn) CABundleClient {
+	return &cABundleClient{cc}
+}
+
+func (c *cABundleClient) GetCABundle(ctx context.Context, in *GetCABundleRequest, opts ...grpc.CallOption) (*GetCABundleResponse, error) {
+	out := new(GetCABundleResponse)
+	err := c.cc.Invoke(ctx, "/moby.buildkit.cabundle.v1.CABundle/GetCABundle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CABundleServer is the server API for CABundle service.
+type CABundleServer interface {
+	GetCABundle(context.Context, *GetCABundleRequest) (*GetCABundleResponse, error)
+}
+
+// UnimplementedCABundleServer can be embedded to have forward compatible implementations.
+type UnimplementedCABundleServer struct {
+}
+
+func (*UnimplementedCABundleServer) GetCABundle(ctx context.Context, req *GetCABundleRequest) (*GetCABundleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCABundle not implemented")
+}
+
+func RegisterCABundleServer(s *grpc.Server, srv CABundleServer) {
+	s.RegisterService(&_CABundle_serviceDesc, srv)
+}
+
+func _CABundle_GetCABundle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCABundleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CABundleServer).GetCABundle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/moby.buildkit.cabundle.v1.CABundle/GetCABundle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CABundleServer).GetCABundle(ctx, req.(*GetCABundleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _CABundle_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "moby.buildkit.cabundle.v1.CABundle",
+	HandlerType: (*CABundleServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCABundle",
+			Handler:    _CABundle_GetCABundle_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "cabundle.proto",
+}
+
+func (m *GetCABundleRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetCABundleRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GetCABundleRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *GetCABundleResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetCABundleResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GetCABundleResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.PEM) > 0 {
+		i -= len(m.PEM)
+		copy(dAtA[i:], m.PEM)
+		i = encodeVarintCabundle(dAtA, i, uint64(len(m.PEM)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintCabundle(dAtA []byte, offset int, v uint64) int {
+	offset -= sovCabundle(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *GetCABundleRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *GetCABundleResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.PEM)
+	if l > 0 {
+		n += 1 + l + sovCabundle(uint64(l))
+	}
+	return n
+}
+
+func sovCabundle(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozCabundle(x uint64) (n int) {
+	return sovCabundle(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *GetCABundleRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&GetCABundleRequest{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *GetCABundleResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&GetCABundleResponse{`,
+		`PEM:` + fmt.Sprintf("%v", this.PEM) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringCabundle(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *GetCABundleRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCabundle
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetCABundleRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetCABundleRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCabundle(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthCabundle
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetCABundleResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCabundle
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetCABundleResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetCABundleResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PEM", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCabundle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCabundle
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCabundle
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PEM = append(m.PEM[:0], dAtA[iNdEx:postIndex]...)
+			if m.PEM == nil {
+				m.PEM = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCabundle(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthCabundle
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipCabundle(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowCabundle
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowCabundle
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowCabundle
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthCabundle
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupCabundle
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthCabundle
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthCabundle        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowCabundle          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupCabundle = fmt.Errorf("proto: unexpected end of group")
+)
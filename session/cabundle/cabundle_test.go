@@ -0,0 +1,169 @@
+package cabundle
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/session"
+	sessiontestutil "github.com/moby/buildkit/session/testutil"
+	"github.com/moby/buildkit/util/tracing"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestTransport(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	get := func(rt http.RoundTripper) error {
+		resp, err := (&http.Client{Transport: rt}).Get(srv.URL)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	require.Error(t, get(base))
+
+	rt, err := NewTransport(base, bundle)
+	require.NoError(t, err)
+	require.NoError(t, get(rt))
+	require.Nil(t, base.TLSClientConfig.RootCAs)
+
+	rt, err = NewTransport(tracing.NewTransport(base), bundle)
+	require.NoError(t, err)
+	require.IsType(t, &tracing.Transport{}, rt)
+	require.NoError(t, get(rt))
+
+	// transports with their own roots are not changed
+	own := base.Clone()
+	own.TLSClientConfig = &tls.Config{RootCAs: x509.NewCertPool()}
+	rt, err = NewTransport(own, bundle)
+	require.NoError(t, err)
+	require.Equal(t, own, rt)
+
+	rt, err = NewTransport(base, nil)
+	require.NoError(t, err)
+	require.Equal(t, base, rt)
+
+	_, err = NewTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, nil
+	}), bundle)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not supported by transport")
+
+	_, err = NewTransport(base, []byte("foo"))
+	require.EqualError(t, err, "no certificates in CA bundle")
+}
+
+func TestGetCABundle(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	sm, err := session.NewManager()
+	require.NoError(t, err)
+
+	newSession := func(attachables ...session.Attachable) *session.Session {
+		s, err := session.NewSession(ctx, "foo", "bar")
+		require.NoError(t, err)
+		for _, a := range attachables {
+			s.Allow(a)
+		}
+		go s.Run(ctx, session.Dialer(sessiontestutil.TestStream(sessiontestutil.Handler(sm.HandleConn))))
+		return s
+	}
+
+	pemData := []byte("-----BEGIN CERTIFICATE-----\n")
+	s := newSession(&testProvider{bundle: pemData})
+	defer s.Close()
+	b := GetCABundle(ctx, sm, session.NewGroup(s.ID()))
+	require.NotNil(t, b)
+	require.Equal(t, pemData, b.PEM)
+
+	// the bundle and its transports are reused for the requests of the
+	// session
+	require.True(t, b == GetCABundle(ctx, sm, session.NewGroup(s.ID())))
+
+	// sessions without a provider have no bundle
+	s2 := newSession()
+	defer s2.Close()
+	require.Nil(t, GetCABundle(ctx, sm, session.NewGroup(s2.ID())))
+
+	// sessions that can't be reached have no bundle
+	require.Nil(t, GetCABundle(ctx, sm, session.NewGroup("missing")))
+	require.Nil(t, GetCABundle(ctx, sm, nil))
+
+	// the bundle is dropped when the session ends
+	s.Close()
+	require.Eventually(t, func() bool {
+		bundles.mu.Lock()
+		defer bundles.mu.Unlock()
+		_, ok := bundles.m[s.ID()]
+		return !ok
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestBundleTransport(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	b := &Bundle{PEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})}
+
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	rt, err := b.Transport(base)
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: rt}).Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// one transport is created for each base transport
+	rt2, err := b.Transport(base)
+	require.NoError(t, err)
+	require.True(t, rt == rt2)
+	rt3, err := b.Transport(base.Clone())
+	require.NoError(t, err)
+	require.False(t, rt == rt3)
+
+	var nb *Bundle
+	rt, err = nb.Transport(base)
+	require.NoError(t, err)
+	require.Equal(t, base, rt)
+
+	b.close()
+	require.Nil(t, b.transports)
+}
+
+type testProvider struct {
+	bundle []byte
+}
+
+func (p *testProvider) Register(server *grpc.Server) {
+	RegisterCABundleServer(server, p)
+}
+
+func (p *testProvider) GetCABundle(ctx context.Context, req *GetCABundleRequest) (*GetCABundleResponse, error) {
+	return &GetCABundleResponse{PEM: p.bundle}, nil
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,75 @@
+package cabundleprovider
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/cabundle"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// NewCABundleProvider returns a session attachable that makes the image, HTTP
+// and Git sources of the build trust the PEM encoded CA certificates of
+// bundle
+func NewCABundleProvider(bundle []byte) session.Attachable {
+	return &caBundleProvider{bundle: bundle}
+}
+
+// FromFiles returns a CA bundle provider with the certificates of the PEM
+// files
+func FromFiles(paths []string) (session.Attachable, error) {
+	var buf bytes.Buffer
+	for _, p := range paths {
+		dt, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if err := validate(dt); err != nil {
+			return nil, errors.Wrapf(err, "invalid CA bundle %s", p)
+		}
+		buf.Write(dt)
+		buf.WriteString("\n")
+	}
+	return NewCABundleProvider(buf.Bytes()), nil
+}
+
+type caBundleProvider struct {
+	bundle []byte
+}
+
+func (cp *caBundleProvider) Register(server *grpc.Server) {
+	cabundle.RegisterCABundleServer(server, cp)
+}
+
+func (cp *caBundleProvider) GetCABundle(ctx context.Context, req *cabundle.GetCABundleRequest) (*cabundle.GetCABundleResponse, error) {
+	return &cabundle.GetCABundleResponse{PEM: cp.bundle}, nil
+}
+
+// validate checks that dt has at least one certificate and no other PEM
+// blocks
+func validate(dt []byte) error {
+	var n int
+	for {
+		var block *pem.Block
+		block, dt = pem.Decode(dt)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return errors.Errorf("unexpected PEM block %q", block.Type)
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return errors.WithStack(err)
+		}
+		n++
+	}
+	if n == 0 {
+		return errors.New("no certificates found")
+	}
+	return nil
+}
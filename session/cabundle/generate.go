@@ -0,0 +1,3 @@
+package cabundle
+
+//go:generate protoc --gogoslick_out=plugins=grpc:. cabundle.proto
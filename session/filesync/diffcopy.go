@@ -101,15 +101,18 @@ func syncTargetDiffCopy(ds grpc.ServerStream, dest string) error {
 	if err := os.MkdirAll(dest, 0700); err != nil {
 		return errors.Wrapf(err, "failed to create synctarget dest dir %s", dest)
 	}
+	rt := newResumeTarget(dest)
 	return errors.WithStack(fsutil.Receive(ds.Context(), ds, dest, fsutil.ReceiveOpt{
-		Merge: true,
+		Merge:         true,
+		NotifyHashed:  rt.handleChange,
+		ContentHasher: rt.contentHasher,
 		Filter: func() func(string, *fstypes.Stat) bool {
 			uid := os.Getuid()
 			gid := os.Getgid()
 			return func(p string, st *fstypes.Stat) bool {
 				st.Uid = uint32(uid)
 				st.Gid = uint32(gid)
-				return true
+				return rt.filter(p, st)
 			}
 		}(),
 	}))
@@ -5,10 +5,15 @@ import (
 	"fmt"
 	io "io"
 	"os"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/util/grpcerrors"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"github.com/tonistiigi/fsutil"
 	fstypes "github.com/tonistiigi/fsutil/types"
 	"google.golang.org/grpc"
@@ -23,7 +28,13 @@ const (
 	keyExcludePatterns    = "exclude-patterns"
 	keyFollowPaths        = "followpaths"
 	keyDirName            = "dir-name"
+	keyPreserveOwnership  = "preserve-ownership"
+	keySymlinkMode        = "symlink-mode"
 	keyExporterMetaPrefix = "exporter-md-"
+	keyExporterID         = "exporter-id"
+	keyRetryAttempts      = "retry-attempts"
+	keyRetryBackoff       = "retry-backoff"
+	keyRetryMaxBackoff    = "retry-max-backoff"
 )
 
 type fsSyncProvider struct {
@@ -37,6 +48,83 @@ type SyncedDir struct {
 	Dir      string
 	Excludes []string
 	Map      func(string, *fstypes.Stat) bool
+	// Retry is sent to the daemon to retry the transfers of the directory
+	// that fail because of transient connection errors
+	Retry *RetryPolicy
+}
+
+// RetryPolicy defines how the transfer of a synced directory is retried after
+// a transient connection error. A retried transfer resumes from the files that
+// the daemon has already received.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts, including the first one
+	MaxAttempts int
+	// Backoff is the delay before the first retry. It is doubled for every
+	// following retry.
+	Backoff time.Duration
+	// MaxBackoff limits the delay between retries if set
+	MaxBackoff time.Duration
+}
+
+func (rp *RetryPolicy) delay(attempt int) time.Duration {
+	d := rp.Backoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if rp.MaxBackoff > 0 && d >= rp.MaxBackoff {
+			break
+		}
+	}
+	if rp.MaxBackoff > 0 && d > rp.MaxBackoff {
+		d = rp.MaxBackoff
+	}
+	return d
+}
+
+func (rp *RetryPolicy) header() metadata.MD {
+	return metadata.Pairs(
+		keyRetryAttempts, strconv.Itoa(rp.MaxAttempts),
+		keyRetryBackoff, rp.Backoff.String(),
+		keyRetryMaxBackoff, rp.MaxBackoff.String(),
+	)
+}
+
+// retryPolicyFromHeader returns the retry policy sent by the client in the
+// header of a transfer stream. Clients that don't send a policy don't get
+// their transfers retried.
+func retryPolicyFromHeader(md metadata.MD) *RetryPolicy {
+	v := md.Get(keyRetryAttempts)
+	if len(v) == 0 {
+		return nil
+	}
+	attempts, err := strconv.Atoi(v[0])
+	if err != nil || attempts <= 1 {
+		return nil
+	}
+	rp := &RetryPolicy{MaxAttempts: attempts}
+	if v := md.Get(keyRetryBackoff); len(v) > 0 {
+		rp.Backoff, _ = time.ParseDuration(v[0])
+	}
+	if v := md.Get(keyRetryMaxBackoff); len(v) > 0 {
+		rp.MaxBackoff, _ = time.ParseDuration(v[0])
+	}
+	return rp
+}
+
+// isTransientError returns true for errors caused by the connection to the
+// client that may not happen again if the transfer is retried. Errors of the
+// transfer itself, like a missing file, are not transient.
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	switch grpcerrors.Code(err) {
+	case codes.Unavailable, codes.Aborted:
+		return true
+	}
+	return false
 }
 
 // NewFSSyncProvider creates a new provider for sending files from client
@@ -94,23 +182,40 @@ func (sp *fsSyncProvider) handle(method string, stream grpc.ServerStream) (retEr
 
 	followPaths := opts[keyFollowPaths]
 
+	mapFn := dir.Map
+	if v := opts[keyPreserveOwnership]; len(v) > 0 && v[0] == "true" && mapFn != nil {
+		mapFn = keepOwnership(dir.Map)
+	}
+
+	walkOpt := &fsutil.WalkOpt{
+		ExcludePatterns: excludes,
+		IncludePatterns: includes,
+		FollowPaths:     followPaths,
+		Map:             mapFn,
+	}
+	fs := fsutil.NewFS(dir.Dir, walkOpt)
+	if v := opts[keySymlinkMode]; len(v) > 0 && SymlinkMode(v[0]) == SymlinkDereference {
+		fs = &dereferenceFS{FS: fs, root: dir.Dir, opt: walkOpt}
+	}
+
 	var progress progressCb
 	if sp.p != nil {
 		progress = sp.p
 		sp.p = nil
 	}
 
+	if dir.Retry != nil {
+		if err := stream.SendHeader(dir.Retry.header()); err != nil {
+			return err
+		}
+	}
+
 	var doneCh chan error
 	if sp.doneCh != nil {
 		doneCh = sp.doneCh
 		sp.doneCh = nil
 	}
-	err := pr.sendFn(stream, fsutil.NewFS(dir.Dir, &fsutil.WalkOpt{
-		ExcludePatterns: excludes,
-		IncludePatterns: includes,
-		FollowPaths:     followPaths,
-		Map:             dir.Map,
-	}), progress)
+	err := pr.sendFn(stream, fs, progress)
 	if doneCh != nil {
 		if err != nil {
 			doneCh <- err
@@ -120,6 +225,19 @@ func (sp *fsSyncProvider) handle(method string, stream grpc.ServerStream) (retEr
 	return err
 }
 
+// keepOwnership wraps a map function so that the original uid and gid of the
+// files are sent even if the map function resets them
+func keepOwnership(fn func(string, *fstypes.Stat) bool) func(string, *fstypes.Stat) bool {
+	return func(p string, st *fstypes.Stat) bool {
+		uid, gid := st.Uid, st.Gid
+		if !fn(p, st) {
+			return false
+		}
+		st.Uid, st.Gid = uid, gid
+		return true
+	}
+}
+
 func (sp *fsSyncProvider) SetNextProgressCallback(f func(int, bool), doneCh chan error) {
 	sp.p = f
 	sp.doneCh = doneCh
@@ -161,6 +279,15 @@ type FSSendRequestOpt struct {
 	ProgressCb       func(int, bool)
 	Filter           func(string, *fstypes.Stat) bool
 	Differ           fsutil.DiffType
+	// PreserveOwnership asks the client to send the uid and gid of the files
+	// instead of resetting them
+	PreserveOwnership bool
+	// SymlinkMode sets how the client sends symlinks
+	SymlinkMode SymlinkMode
+	// GetCaller returns the caller of the session for a retried transfer, so
+	// that the retry can use a new connection of the session. Transfers are
+	// retried with the caller of the first attempt if it is not set.
+	GetCaller func(ctx context.Context) (session.Caller, error)
 }
 
 // CacheUpdater is an object capable of sending notifications for the cache hash changes
@@ -172,17 +299,6 @@ type CacheUpdater interface {
 
 // FSSync initializes a transfer of files
 func FSSync(ctx context.Context, c session.Caller, opt FSSendRequestOpt) error {
-	var pr *protocol
-	for _, p := range supportedProtocols {
-		if isProtoSupported(p.name) && c.Supports(session.MethodURL(_FileSync_serviceDesc.ServiceName, p.name)) {
-			pr = &p
-			break
-		}
-	}
-	if pr == nil {
-		return errors.New("no local sources enabled")
-	}
-
 	opts := make(map[string][]string)
 	if opt.OverrideExcludes {
 		opts[keyOverrideExcludes] = []string{"true"}
@@ -200,8 +316,58 @@ func FSSync(ctx context.Context, c session.Caller, opt FSSendRequestOpt) error {
 		opts[keyFollowPaths] = opt.FollowPaths
 	}
 
+	if opt.PreserveOwnership {
+		opts[keyPreserveOwnership] = []string{"true"}
+	}
+
+	var symlinkErr error
+	if opt.SymlinkMode != "" && opt.SymlinkMode != SymlinkPreserve {
+		opts[keySymlinkMode] = []string{string(opt.SymlinkMode)}
+		// clients that don't know the symlink mode still send symlinks
+		opt.Filter = rejectSymlinks(opt.Filter, &symlinkErr)
+	}
+
 	opts[keyDirName] = []string{opt.Name}
 
+	var rp *RetryPolicy
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && opt.GetCaller != nil {
+			var err error
+			if c, err = opt.GetCaller(ctx); err != nil {
+				return err
+			}
+		}
+		err := fsSync(ctx, c, opts, opt, &rp)
+		if err == nil && symlinkErr != nil {
+			return symlinkErr
+		}
+		if err == nil || rp == nil || attempt >= rp.MaxAttempts || !isTransientError(err) || ctx.Err() != nil {
+			return err
+		}
+		d := rp.delay(attempt)
+		logrus.Warnf("transfer of %s failed, retrying in %v (attempt %d/%d): %v", opt.Name, d, attempt, rp.MaxAttempts, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(d):
+		}
+	}
+}
+
+// fsSync runs a single attempt of a transfer. The retry policy is read from
+// the header of the first stream that the client answers.
+func fsSync(ctx context.Context, c session.Caller, opts map[string][]string, opt FSSendRequestOpt, rp **RetryPolicy) error {
+	var pr *protocol
+	for _, p := range supportedProtocols {
+		if isProtoSupported(p.name) && c.Supports(session.MethodURL(_FileSync_serviceDesc.ServiceName, p.name)) {
+			pr = &p
+			break
+		}
+	}
+	if pr == nil {
+		return errors.New("no local sources enabled")
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -228,6 +394,14 @@ func FSSync(ctx context.Context, c session.Caller, opt FSSendRequestOpt) error {
 		panic(fmt.Sprintf("invalid protocol: %q", pr.name))
 	}
 
+	if *rp == nil {
+		md, err := stream.Header()
+		if err != nil {
+			return err
+		}
+		*rp = retryPolicyFromHeader(md)
+	}
+
 	return pr.recvFn(stream, opt.DestDir, opt.CacheUpdater, opt.ProgressCb, opt.Differ, opt.Filter)
 }
 
@@ -256,6 +430,63 @@ func (sp *fsSyncTarget) Register(server *grpc.Server) {
 	RegisterFileSendServer(server, sp)
 }
 
+// FSSyncTarget is the output of one of the exporters of a build with more
+// than one exporter. Exporters write either into the directory OutDir or into
+// the io.WriteCloser returned by Output.
+type FSSyncTarget struct {
+	OutDir string
+	Output func(map[string]string) (io.WriteCloser, error)
+}
+
+// NewFSSyncTargets allows the exporters of a build with more than one
+// exporter to write into their own outputs. The outputs are keyed by the IDs
+// the exporters are run with, see WithExporterID.
+func NewFSSyncTargets(targets map[string]FSSyncTarget) session.Attachable {
+	p := &fsSyncTargets{targets: make(map[string]*fsSyncTarget, len(targets))}
+	for id, t := range targets {
+		p.targets[id] = &fsSyncTarget{outdir: t.OutDir, f: t.Output}
+	}
+	return p
+}
+
+type fsSyncTargets struct {
+	targets map[string]*fsSyncTarget
+}
+
+func (sp *fsSyncTargets) Register(server *grpc.Server) {
+	RegisterFileSendServer(server, sp)
+}
+
+func (sp *fsSyncTargets) DiffCopy(stream FileSend_DiffCopyServer) error {
+	opts, _ := metadata.FromIncomingContext(stream.Context())
+	var id string
+	if v := opts.Get(keyExporterID); len(v) > 0 {
+		id = v[0]
+	}
+	t, ok := sp.targets[id]
+	if !ok {
+		return status.Errorf(codes.NotFound, "no output for exporter %q", id)
+	}
+	return t.DiffCopy(stream)
+}
+
+type exporterIDKey struct{}
+
+// WithExporterID returns a context for running the exporter with the id in a
+// build with more than one exporter. The files the exporter sends with
+// CopyToCaller and CopyFileWriter are written into the output of the client
+// with the same id.
+func WithExporterID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, exporterIDKey{}, id)
+}
+
+func withExporterIDMetadata(ctx context.Context) context.Context {
+	if id, ok := ctx.Value(exporterIDKey{}).(string); ok {
+		return metadata.AppendToOutgoingContext(ctx, keyExporterID, id)
+	}
+	return ctx
+}
+
 func (sp *fsSyncTarget) DiffCopy(stream FileSend_DiffCopyServer) (err error) {
 	if sp.outdir != "" {
 		return syncTargetDiffCopy(stream, sp.outdir)
@@ -295,7 +526,7 @@ func CopyToCaller(ctx context.Context, fs fsutil.FS, c session.Caller, progress
 
 	client := NewFileSendClient(c.Conn())
 
-	cc, err := client.DiffCopy(ctx)
+	cc, err := client.DiffCopy(withExporterIDMetadata(ctx))
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -316,7 +547,7 @@ func CopyFileWriter(ctx context.Context, md map[string]string, c session.Caller)
 		opts[keyExporterMetaPrefix+k] = []string{v}
 	}
 
-	ctx = metadata.NewOutgoingContext(ctx, opts)
+	ctx = withExporterIDMetadata(metadata.NewOutgoingContext(ctx, opts))
 
 	cc, err := client.DiffCopy(ctx)
 	if err != nil {
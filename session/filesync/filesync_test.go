@@ -2,15 +2,26 @@ package filesync
 
 import (
 	"context"
+	"io"
 	"io/ioutil"
+	"net"
+	"os"
 	"path/filepath"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/session/testutil"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tonistiigi/fsutil"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func TestFileSyncIncludePatterns(t *testing.T) {
@@ -72,3 +83,406 @@ func TestFileSyncIncludePatterns(t *testing.T) {
 	err = g.Wait()
 	require.NoError(t, err)
 }
+
+func TestFileSyncRetryCaller(t *testing.T) {
+	ctx := context.TODO()
+	t.Parallel()
+	tmpDir, err := ioutil.TempDir("", "fsynctest")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	destDir, err := ioutil.TempDir("", "fsynctest")
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+
+	err = ioutil.WriteFile(filepath.Join(tmpDir, "foo"), []byte("content1"), 0600)
+	require.NoError(t, err)
+
+	// the first attempt is made over a connection that fails after sending
+	// the retry policy
+	rp := &RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+	failing := newFailingCaller(t, rp)
+	defer failing.close()
+
+	s, err := session.NewSession(ctx, "foo", "bar")
+	require.NoError(t, err)
+
+	m, err := session.NewManager()
+	require.NoError(t, err)
+
+	fs := NewFSSyncProvider([]SyncedDir{{Name: "test0", Dir: tmpDir, Retry: rp}})
+	s.Allow(fs)
+
+	dialer := session.Dialer(testutil.TestStream(testutil.Handler(m.HandleConn)))
+
+	g, ctx := errgroup.WithContext(context.Background())
+
+	g.Go(func() error {
+		return s.Run(ctx, dialer)
+	})
+
+	g.Go(func() (reterr error) {
+		var calls int
+		if err := FSSync(ctx, failing, FSSendRequestOpt{
+			Name:    "test0",
+			DestDir: destDir,
+			GetCaller: func(ctx context.Context) (session.Caller, error) {
+				calls++
+				return m.Get(ctx, s.ID(), false)
+			},
+		}); err != nil {
+			return err
+		}
+		assert.Equal(t, 1, calls)
+
+		dt, err := ioutil.ReadFile(filepath.Join(destDir, "foo"))
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, "content1", string(dt))
+		return s.Close()
+	})
+
+	err = g.Wait()
+	require.NoError(t, err)
+}
+
+// failingCaller is a caller whose transfers fail with a transient error after
+// the retry policy was sent
+type failingCaller struct {
+	session.Caller
+	conn   *grpc.ClientConn
+	server *grpc.Server
+}
+
+func newFailingCaller(t *testing.T, rp *RetryPolicy) *failingCaller {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	RegisterFileSyncServer(server, &failingServer{rp: rp})
+	go server.Serve(l)
+	conn, err := grpc.Dial(l.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	return &failingCaller{conn: conn, server: server}
+}
+
+func (c *failingCaller) Supports(method string) bool {
+	return true
+}
+
+func (c *failingCaller) Conn() *grpc.ClientConn {
+	return c.conn
+}
+
+func (c *failingCaller) close() {
+	c.conn.Close()
+	c.server.Stop()
+}
+
+type failingServer struct {
+	rp *RetryPolicy
+}
+
+func (s *failingServer) DiffCopy(stream FileSync_DiffCopyServer) error {
+	if err := stream.SendHeader(s.rp.header()); err != nil {
+		return err
+	}
+	return status.Error(codes.Unavailable, "connection reset")
+}
+
+func (s *failingServer) TarStream(stream FileSync_TarStreamServer) error {
+	return s.DiffCopy(stream)
+}
+
+func TestFileSyncSymlinkMode(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := ioutil.TempDir("", "fsynctest")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	require.NoError(t, os.Mkdir(filepath.Join(tmpDir, "dir"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tmpDir, "dir", "foo"), []byte("content1"), 0600))
+	require.NoError(t, os.Symlink("dir/foo", filepath.Join(tmpDir, "filelink")))
+	require.NoError(t, os.Symlink("dir", filepath.Join(tmpDir, "dirlink")))
+
+	outside, err := ioutil.TempDir("", "fsynctest")
+	require.NoError(t, err)
+	defer os.RemoveAll(outside)
+
+	sync := func(dir string, mode SymlinkMode, opts ...func(*FSSendRequestOpt)) (string, error) {
+		destDir, err := ioutil.TempDir("", "fsynctest")
+		require.NoError(t, err)
+
+		s, err := session.NewSession(context.TODO(), "foo", "bar")
+		require.NoError(t, err)
+		m, err := session.NewManager()
+		require.NoError(t, err)
+		s.Allow(NewFSSyncProvider([]SyncedDir{{Name: "test0", Dir: dir}}))
+
+		dialer := session.Dialer(testutil.TestStream(testutil.Handler(m.HandleConn)))
+		g, ctx := errgroup.WithContext(context.Background())
+		g.Go(func() error {
+			return s.Run(ctx, dialer)
+		})
+		var syncErr error
+		g.Go(func() error {
+			c, err := m.Get(ctx, s.ID(), false)
+			if err != nil {
+				return err
+			}
+			opt := FSSendRequestOpt{
+				Name:        "test0",
+				DestDir:     destDir,
+				SymlinkMode: mode,
+			}
+			for _, o := range opts {
+				o(&opt)
+			}
+			syncErr = FSSync(ctx, c, opt)
+			return s.Close()
+		})
+		require.NoError(t, g.Wait())
+		return destDir, syncErr
+	}
+
+	destDir, err := sync(tmpDir, SymlinkPreserve)
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+	link, err := os.Readlink(filepath.Join(destDir, "filelink"))
+	require.NoError(t, err)
+	require.Equal(t, "dir/foo", link)
+
+	destDir, err = sync(tmpDir, SymlinkDereference)
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+	fi, err := os.Lstat(filepath.Join(destDir, "filelink"))
+	require.NoError(t, err)
+	require.True(t, fi.Mode().IsRegular())
+	fi, err = os.Lstat(filepath.Join(destDir, "dirlink"))
+	require.NoError(t, err)
+	require.True(t, fi.IsDir())
+	dt, err := ioutil.ReadFile(filepath.Join(destDir, "dirlink", "foo"))
+	require.NoError(t, err)
+	require.Equal(t, "content1", string(dt))
+
+	// the patterns of the walk apply to the paths under the symlinks
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tmpDir, "dir", "secret"), []byte("secret"), 0600))
+	destDir, err = sync(tmpDir, SymlinkDereference, func(opt *FSSendRequestOpt) {
+		opt.ExcludePatterns = []string{"dirlink/secret"}
+	})
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+	_, err = os.Lstat(filepath.Join(destDir, "dirlink", "secret"))
+	require.True(t, errors.Is(err, os.ErrNotExist))
+	_, err = os.Lstat(filepath.Join(destDir, "dirlink", "foo"))
+	require.NoError(t, err)
+	_, err = os.Lstat(filepath.Join(destDir, "dir", "secret"))
+	require.NoError(t, err)
+
+	destDir, err = sync(tmpDir, SymlinkDereference, func(opt *FSSendRequestOpt) {
+		opt.IncludePatterns = []string{"dirlink/foo"}
+	})
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+	_, err = os.Lstat(filepath.Join(destDir, "dirlink", "foo"))
+	require.NoError(t, err)
+	_, err = os.Lstat(filepath.Join(destDir, "dirlink", "secret"))
+	require.True(t, errors.Is(err, os.ErrNotExist))
+	_, err = os.Lstat(filepath.Join(destDir, "dir"))
+	require.True(t, errors.Is(err, os.ErrNotExist))
+	require.NoError(t, os.Remove(filepath.Join(tmpDir, "dir", "secret")))
+
+	destDir, err = sync(tmpDir, SymlinkError)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not allowed")
+	defer os.RemoveAll(destDir)
+	_, err = os.Lstat(filepath.Join(destDir, "filelink"))
+	require.True(t, errors.Is(err, os.ErrNotExist))
+
+	loopDir, err := ioutil.TempDir("", "fsynctest")
+	require.NoError(t, err)
+	defer os.RemoveAll(loopDir)
+	require.NoError(t, os.Symlink(outside, filepath.Join(loopDir, "outside")))
+	destDir, err = sync(loopDir, SymlinkDereference)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "outside of the local directory")
+	defer os.RemoveAll(destDir)
+
+	require.NoError(t, os.Remove(filepath.Join(loopDir, "outside")))
+	require.NoError(t, os.Symlink(".", filepath.Join(loopDir, "loop")))
+	destDir, err = sync(loopDir, SymlinkDereference)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "creates a loop")
+	defer os.RemoveAll(destDir)
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	rp := &RetryPolicy{MaxAttempts: 5, Backoff: time.Second, MaxBackoff: 3 * time.Second}
+	require.Equal(t, time.Second, rp.delay(1))
+	require.Equal(t, 2*time.Second, rp.delay(2))
+	require.Equal(t, 3*time.Second, rp.delay(3))
+	require.Equal(t, 3*time.Second, rp.delay(10))
+
+	require.Equal(t, rp, retryPolicyFromHeader(rp.header()))
+	require.Nil(t, retryPolicyFromHeader(metadata.MD{}))
+	require.Nil(t, retryPolicyFromHeader((&RetryPolicy{MaxAttempts: 1}).header()))
+}
+
+func TestIsTransientError(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, isTransientError(errors.WithStack(io.ErrUnexpectedEOF)))
+	require.True(t, isTransientError(errors.Wrap(syscall.ECONNRESET, "read")))
+	require.True(t, isTransientError(status.Error(codes.Unavailable, "transport is closing")))
+
+	require.False(t, isTransientError(status.Error(codes.NotFound, "no access allowed to dir")))
+	require.False(t, isTransientError(errors.WithStack(os.ErrNotExist)))
+	require.False(t, isTransientError(errors.WithStack(context.Canceled)))
+}
+
+func TestCopyToCallerResume(t *testing.T) {
+	ctx := context.TODO()
+	t.Parallel()
+	tmpDir, err := ioutil.TempDir("", "fsynctest")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	destDir, err := ioutil.TempDir("", "fsynctest")
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+
+	for name, dt := range map[string]string{"done": "content1", "partial": "content2", "changed": "content3", "new": "content4"} {
+		err = ioutil.WriteFile(filepath.Join(tmpDir, name), []byte(dt), 0600)
+		require.NoError(t, err)
+	}
+
+	// files left by an interrupted export
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for name, dt := range map[string]string{"done": "content1", "partial": "cont", "changed": "CONTENT3"} {
+		err = ioutil.WriteFile(filepath.Join(destDir, name), []byte(dt), 0600)
+		require.NoError(t, err)
+		err = os.Chtimes(filepath.Join(destDir, name), old, old)
+		require.NoError(t, err)
+	}
+
+	s, err := session.NewSession(ctx, "foo", "bar")
+	require.NoError(t, err)
+
+	m, err := session.NewManager()
+	require.NoError(t, err)
+
+	s.Allow(NewFSSyncTargetDir(destDir))
+
+	dialer := session.Dialer(testutil.TestStream(testutil.Handler(m.HandleConn)))
+
+	g, ctx := errgroup.WithContext(context.Background())
+
+	g.Go(func() error {
+		return s.Run(ctx, dialer)
+	})
+
+	g.Go(func() (reterr error) {
+		c, err := m.Get(ctx, s.ID(), false)
+		if err != nil {
+			return err
+		}
+		fs := fsutil.NewFS(tmpDir, &fsutil.WalkOpt{
+			Map: WithContentDigests(tmpDir, nil),
+		})
+		if err := CopyToCaller(ctx, fs, c, nil); err != nil {
+			return err
+		}
+		return s.Close()
+	})
+
+	err = g.Wait()
+	require.NoError(t, err)
+
+	for name, dt := range map[string]string{"done": "content1", "partial": "content2", "changed": "content3", "new": "content4"} {
+		got, err := ioutil.ReadFile(filepath.Join(destDir, name))
+		require.NoError(t, err)
+		require.Equal(t, dt, string(got), name)
+
+		fi, err := os.Stat(filepath.Join(destDir, name))
+		require.NoError(t, err)
+		// only the complete file is kept as it was
+		require.Equal(t, name == "done", fi.ModTime().Equal(old), name)
+	}
+}
+
+func TestFSSyncTargets(t *testing.T) {
+	ctx := context.TODO()
+	t.Parallel()
+	tmpDir, err := ioutil.TempDir("", "fsynctest")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	destDir, err := ioutil.TempDir("", "fsynctest")
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+
+	err = ioutil.WriteFile(filepath.Join(tmpDir, "foo"), []byte("content1"), 0600)
+	require.NoError(t, err)
+
+	s, err := session.NewSession(ctx, "foo", "bar")
+	require.NoError(t, err)
+
+	m, err := session.NewManager()
+	require.NoError(t, err)
+
+	outFile := filepath.Join(destDir, "out")
+	s.Allow(NewFSSyncTargets(map[string]FSSyncTarget{
+		"0": {OutDir: filepath.Join(destDir, "dir")},
+		"1": {Output: func(map[string]string) (io.WriteCloser, error) {
+			return os.Create(outFile)
+		}},
+	}))
+
+	dialer := session.Dialer(testutil.TestStream(testutil.Handler(m.HandleConn)))
+
+	g, ctx := errgroup.WithContext(context.Background())
+
+	g.Go(func() error {
+		return s.Run(ctx, dialer)
+	})
+
+	g.Go(func() (reterr error) {
+		c, err := m.Get(ctx, s.ID(), false)
+		if err != nil {
+			return err
+		}
+		if err := CopyToCaller(WithExporterID(ctx, "0"), fsutil.NewFS(tmpDir, nil), c, nil); err != nil {
+			return err
+		}
+		w, err := CopyFileWriter(WithExporterID(ctx, "1"), nil, c)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte("content2")); err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+
+		// exporters without an output of the client fail
+		w, err = CopyFileWriter(WithExporterID(ctx, "2"), nil, c)
+		if err == nil {
+			err = w.Close()
+		}
+		assert.Error(t, err)
+		return s.Close()
+	})
+
+	err = g.Wait()
+	require.NoError(t, err)
+
+	dt, err := ioutil.ReadFile(filepath.Join(destDir, "dir", "foo"))
+	require.NoError(t, err)
+	require.Equal(t, "content1", string(dt))
+	dt, err = ioutil.ReadFile(outFile)
+	require.NoError(t, err)
+	require.Equal(t, "content2", string(dt))
+}
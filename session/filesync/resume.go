@@ -0,0 +1,174 @@
+package filesync
+
+import (
+	"crypto/sha256"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/tonistiigi/fsutil"
+	fstypes "github.com/tonistiigi/fsutil/types"
+)
+
+// ContentDigestXattr is the key of the extended attribute that carries the
+// digest of a regular file in the stat sent to a target directory. The target
+// keeps files of an earlier, interrupted export that already have the same
+// content instead of receiving them again. The attribute is not written to
+// the exported files.
+const ContentDigestXattr = "user.buildkit.contentdigest"
+
+// WithContentDigests returns a walk map function that sets ContentDigestXattr
+// on the regular files under root before calling fn.
+func WithContentDigests(root string, fn func(string, *fstypes.Stat) bool) func(string, *fstypes.Stat) bool {
+	return func(p string, st *fstypes.Stat) bool {
+		if fn != nil && !fn(p, st) {
+			return false
+		}
+		if !os.FileMode(st.Mode).IsRegular() || st.Linkname != "" {
+			return true
+		}
+		dgst, err := fileDigest(filepath.Join(root, filepath.FromSlash(p)))
+		if err != nil {
+			// the file is sent without a digest and always written
+			return true
+		}
+		xattrs := make(map[string][]byte, len(st.Xattrs)+1)
+		for k, v := range st.Xattrs {
+			xattrs[k] = v
+		}
+		xattrs[ContentDigestXattr] = []byte(dgst)
+		st.Xattrs = xattrs
+		return true
+	}
+}
+
+// resumeTarget skips the files in the target directory that were already
+// written with the content of the file that is received and verifies and
+// syncs the files that are written again.
+type resumeTarget struct {
+	dest string
+
+	mu      sync.Mutex
+	keep    map[string]bool
+	digests map[string]digest.Digest
+}
+
+func newResumeTarget(dest string) *resumeTarget {
+	return &resumeTarget{
+		dest:    dest,
+		keep:    map[string]bool{},
+		digests: map[string]digest.Digest{},
+	}
+}
+
+// filter is called for every received path, possibly more than once
+func (rt *resumeTarget) filter(p string, st *fstypes.Stat) bool {
+	v, ok := st.Xattrs[ContentDigestXattr]
+	if !ok {
+		return true
+	}
+	// the map is shared with the stat of the sender
+	var xattrs map[string][]byte
+	for k, v := range st.Xattrs {
+		if k == ContentDigestXattr {
+			continue
+		}
+		if xattrs == nil {
+			xattrs = make(map[string][]byte, len(st.Xattrs)-1)
+		}
+		xattrs[k] = v
+	}
+	st.Xattrs = xattrs
+
+	rt.mu.Lock()
+	keep, ok := rt.keep[p]
+	rt.mu.Unlock()
+	if ok {
+		return !keep
+	}
+
+	dgst, err := digest.Parse(string(v))
+	if err != nil {
+		return true
+	}
+	keep = rt.isComplete(p, st.Size_, dgst)
+
+	rt.mu.Lock()
+	rt.keep[p] = keep
+	if !keep {
+		rt.digests[p] = dgst
+	}
+	rt.mu.Unlock()
+	return !keep
+}
+
+func (rt *resumeTarget) isComplete(p string, size int64, dgst digest.Digest) bool {
+	fp := filepath.Join(rt.dest, filepath.FromSlash(p))
+	fi, err := os.Lstat(fp)
+	if err != nil || !fi.Mode().IsRegular() || fi.Size() != size {
+		return false
+	}
+	d, err := fileDigest(fp)
+	return err == nil && d == dgst
+}
+
+func (rt *resumeTarget) expected(p string) (digest.Digest, bool) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	dgst, ok := rt.digests[p]
+	return dgst, ok
+}
+
+// contentHasher only hashes the data of the files that have a digest
+func (rt *resumeTarget) contentHasher(st *fstypes.Stat) (hash.Hash, error) {
+	if _, ok := rt.expected(st.Path); ok {
+		return sha256.New(), nil
+	}
+	return nopHash{}, nil
+}
+
+// handleChange is called after the data of a file has been written
+func (rt *resumeTarget) handleChange(kind fsutil.ChangeKind, p string, fi os.FileInfo, err error) error {
+	if err != nil || kind == fsutil.ChangeKindDelete {
+		return err
+	}
+	expected, ok := rt.expected(p)
+	if !ok {
+		return nil
+	}
+	if h, ok := fi.(interface{ Digest() digest.Digest }); ok && h.Digest() != expected {
+		return errors.Errorf("received content of %s does not match digest %s", p, expected)
+	}
+	fp := filepath.Join(rt.dest, filepath.FromSlash(p))
+	f, err := os.Open(fp)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %s for sync", fp)
+	}
+	defer f.Close()
+	return errors.Wrapf(f.Sync(), "failed to sync %s", fp)
+}
+
+func fileDigest(p string) (digest.Digest, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return digest.NewDigest(digest.SHA256, h), nil
+}
+
+type nopHash struct{}
+
+func (nopHash) Write(p []byte) (int, error) { return len(p), nil }
+func (nopHash) Sum(b []byte) []byte         { return b }
+func (nopHash) Reset()                      {}
+func (nopHash) Size() int                   { return 0 }
+func (nopHash) BlockSize() int              { return 1 }
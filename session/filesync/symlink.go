@@ -0,0 +1,236 @@
+package filesync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/pkg/fileutils"
+	"github.com/pkg/errors"
+	"github.com/tonistiigi/fsutil"
+	"github.com/tonistiigi/fsutil/prefix"
+	fstypes "github.com/tonistiigi/fsutil/types"
+)
+
+// SymlinkMode defines how the symlinks of a synced directory are sent
+type SymlinkMode string
+
+const (
+	// SymlinkPreserve sends symlinks as symlinks
+	SymlinkPreserve SymlinkMode = "preserve"
+	// SymlinkDereference sends the files and directories that symlinks point
+	// to in place of the symlinks
+	SymlinkDereference SymlinkMode = "dereference"
+	// SymlinkError fails the transfer if a symlink is received
+	SymlinkError SymlinkMode = "error"
+)
+
+// maxSymlinkDepth limits the number of nested symlinks to directories that
+// are dereferenced
+const maxSymlinkDepth = 40
+
+// rejectSymlinks wraps a filter function so that received symlinks are not
+// written and the first one is recorded as an error in errp
+func rejectSymlinks(fn func(string, *fstypes.Stat) bool, errp *error) func(string, *fstypes.Stat) bool {
+	return func(p string, st *fstypes.Stat) bool {
+		if os.FileMode(st.Mode)&os.ModeSymlink != 0 {
+			if *errp == nil {
+				*errp = errors.Errorf("symlink %s -> %s is not allowed in the local source", p, st.Linkname)
+			}
+			return false
+		}
+		if fn != nil {
+			return fn(p, st)
+		}
+		return true
+	}
+}
+
+// dereferenceFS sends the files and directories that symlinks point to in
+// place of the symlinks. Symlinks must point inside of root. The options of
+// the walk of root also apply to the contents of the dereferenced
+// directories, with their paths under the symlinks.
+type dereferenceFS struct {
+	fsutil.FS
+	root string
+	opt  *fsutil.WalkOpt
+}
+
+func (fs *dereferenceFS) Walk(ctx context.Context, fn filepath.WalkFunc) error {
+	root, err := filepath.EvalSymlinks(fs.root)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	f, err := newWalkFilter(fs.root, fs.opt)
+	if err != nil {
+		return err
+	}
+	return fs.FS.Walk(ctx, fs.walkFn(ctx, root, "", 0, f, fn))
+}
+
+// walkFn returns a walk function that dereferences the symlinks of a walk of
+// the directory at prefix before passing them to fn. The paths of the walks
+// of dereferenced directories are filtered with f.
+func (fs *dereferenceFS) walkFn(ctx context.Context, root, prefix string, depth int, f *walkFilter, fn filepath.WalkFunc) filepath.WalkFunc {
+	var includedDir string
+	if prefix != "" {
+		// the symlink to the directory was already matched by the parent walk
+		if _, err := f.match(prefix, true, &includedDir); err != nil && err != filepath.SkipDir {
+			return func(string, os.FileInfo, error) error { return err }
+		}
+	}
+	return func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return fn(p, fi, err)
+		}
+		p = filepath.Join(prefix, p)
+		st, ok := fi.Sys().(*fstypes.Stat)
+		if prefix != "" {
+			if ok, err := f.match(p, fi.IsDir(), &includedDir); !ok || err != nil {
+				return err
+			}
+		}
+		if !ok || os.FileMode(st.Mode)&os.ModeSymlink == 0 {
+			if ok && prefix != "" {
+				st.Path = filepath.ToSlash(p)
+				if st.Linkname != "" {
+					// hardlinks point to paths of the same walk
+					st.Linkname = filepath.ToSlash(filepath.Join(prefix, st.Linkname))
+				}
+				if fs.opt.Map != nil && !fs.opt.Map(st.Path, st) {
+					return nil
+				}
+			}
+			return fn(p, fi, nil)
+		}
+		if prefix != "" && fs.opt.Map != nil {
+			st.Path = filepath.ToSlash(p)
+			if !fs.opt.Map(st.Path, st) {
+				return nil
+			}
+		}
+
+		target, err := filepath.EvalSymlinks(filepath.Join(root, p))
+		if err != nil {
+			return errors.Wrapf(err, "failed to resolve symlink %s", p)
+		}
+		if !isSubpath(root, target) {
+			return errors.Errorf("symlink %s points outside of the local directory", p)
+		}
+		tst, err := fsutil.Stat(target)
+		if err != nil {
+			return err
+		}
+		// the map function was already applied to the symlink
+		tst.Path = filepath.ToSlash(p)
+		tst.Uid, tst.Gid = st.Uid, st.Gid
+		tst.Linkname = ""
+		if err := fn(p, &fsutil.StatInfo{Stat: tst}, nil); err != nil {
+			return err
+		}
+		if !os.FileMode(tst.Mode).IsDir() {
+			return nil
+		}
+		parent, err := filepath.EvalSymlinks(filepath.Dir(filepath.Join(root, p)))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if isSubpath(target, parent) || depth >= maxSymlinkDepth {
+			return errors.Errorf("symlink %s creates a loop", p)
+		}
+		return fsutil.Walk(ctx, target, nil, fs.walkFn(ctx, root, p, depth+1, f, fn))
+	}
+}
+
+// walkFilter applies the include and exclude patterns of a walk to the paths
+// of the walks of the directories that its symlinks point to, like
+// fsutil.Walk does for the paths of the walk itself
+type walkFilter struct {
+	includes []string
+	excludes *fileutils.PatternMatcher
+}
+
+func newWalkFilter(root string, opt *fsutil.WalkOpt) (*walkFilter, error) {
+	f := &walkFilter{}
+	if opt == nil {
+		return f, nil
+	}
+	if opt.ExcludePatterns != nil {
+		pm, err := fileutils.NewPatternMatcher(opt.ExcludePatterns)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid excludepatterns: %s", opt.ExcludePatterns)
+		}
+		f.excludes = pm
+	}
+	if opt.IncludePatterns != nil {
+		f.includes = make([]string, len(opt.IncludePatterns))
+		for i, p := range opt.IncludePatterns {
+			f.includes[i] = filepath.Clean(p)
+		}
+	}
+	if opt.FollowPaths != nil {
+		targets, err := fsutil.FollowLinks(root, opt.FollowPaths)
+		if err != nil {
+			return nil, err
+		}
+		f.includes = append(f.includes, targets...)
+	}
+	return f, nil
+}
+
+// match returns true if the path p relative to the root of the walk is sent.
+// filepath.SkipDir is returned for the directories whose contents are not
+// sent. includedDir is the last directory whose contents are all included.
+func (f *walkFilter) match(p string, isDir bool, includedDir *string) (bool, error) {
+	if f.includes != nil && (*includedDir == "" || !strings.HasPrefix(p, *includedDir+string(filepath.Separator))) {
+		matched := false
+		partial := true
+		for _, pattern := range f.includes {
+			if ok, isPartial := prefix.Match(pattern, p, false); ok {
+				matched = true
+				if !isPartial {
+					partial = false
+					break
+				}
+			}
+		}
+		if !matched {
+			if isDir {
+				return false, filepath.SkipDir
+			}
+			return false, nil
+		}
+		if !partial && isDir {
+			*includedDir = p
+		}
+	}
+	if f.excludes == nil {
+		return true, nil
+	}
+	m, err := f.excludes.Matches(p)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to match excludepatterns")
+	}
+	if !m {
+		return true, nil
+	}
+	if !isDir {
+		return false, nil
+	}
+	if f.excludes.Exclusions() {
+		// the directory is walked if a path inside of it is included again
+		dirSlash := p + string(filepath.Separator)
+		for _, pat := range f.excludes.Patterns() {
+			if pat.Exclusion() && strings.HasPrefix(pat.String()+string(filepath.Separator), dirSlash) {
+				return true, nil
+			}
+		}
+	}
+	return false, filepath.SkipDir
+}
+
+// isSubpath returns true if p is dir or a path inside of dir
+func isSubpath(dir, p string) bool {
+	return p == dir || strings.HasPrefix(p, dir+string(filepath.Separator))
+}
@@ -0,0 +1,3 @@
+package imagepolicy
+
+//go:generate protoc --gogoslick_out=plugins=grpc:. imagepolicy.proto
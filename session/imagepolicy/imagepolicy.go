@@ -0,0 +1,51 @@
+package imagepolicy
+
+import (
+	"context"
+
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/util/grpcerrors"
+	policy "github.com/moby/buildkit/util/imagepolicy"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+)
+
+// GetPolicy returns the image signature policy of the session of a build.
+// Sessions without an image policy provider have no policy. Other errors of
+// the session fail the build, so that its images are never pulled without the
+// policy it requested.
+func GetPolicy(ctx context.Context, sm *session.Manager, id string) (*policy.Policy, error) {
+	if id == "" {
+		return nil, nil
+	}
+	var resp *GetImagePolicyResponse
+	if err := sm.Any(ctx, session.NewGroup(id), func(ctx context.Context, _ string, c session.Caller) error {
+		var err error
+		resp, err = NewImagePolicyClient(c.Conn()).GetImagePolicy(ctx, &GetImagePolicyRequest{})
+		return err
+	}); err != nil {
+		if grpcerrors.Code(err) == codes.Unimplemented {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to get image policy of the build")
+	}
+	rules := make([]policy.PEMRule, len(resp.Rules))
+	for i, r := range resp.Rules {
+		rules[i] = policy.PEMRule{
+			Images:              r.Images,
+			Mode:                r.Mode,
+			Keys:                r.Keys,
+			Roots:               r.Roots,
+			TransparencyLogKeys: r.TransparencyLogKeys,
+			TimestampRoots:      r.TimestampRoots,
+		}
+		for _, id := range r.Identities {
+			rules[i].Identities = append(rules[i].Identities, policy.Identity{Issuer: id.Issuer, Subject: id.Subject})
+		}
+	}
+	p, err := policy.NewFromPEM(rules)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid image policy of the build")
+	}
+	return p, nil
+}
@@ -0,0 +1,1534 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: imagepolicy.proto
+
+package imagepolicy
+
+import (
+	bytes "bytes"
+	context "context"
+	fmt "fmt"
+	proto "github.com/gogo/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+	reflect "reflect"
+	strings "strings"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+type GetImagePolicyRequest struct {
+}
+
+func (m *GetImagePolicyRequest) Reset()      { *m = GetImagePolicyRequest{} }
+func (*GetImagePolicyRequest) ProtoMessage() {}
+func (*GetImagePolicyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_271157b3a732118e, []int{0}
+}
+func (m *GetImagePolicyRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GetImagePolicyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GetImagePolicyRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GetImagePolicyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetImagePolicyRequest.Merge(m, src)
+}
+func (m *GetImagePolicyRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *GetImagePolicyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetImagePolicyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetImagePolicyRequest proto.InternalMessageInfo
+
+type GetImagePolicyResponse struct {
+	Rules []*Rule `protobuf:"bytes,1,rep,name=Rules,proto3" json:"Rules,omitempty"`
+}
+
+func (m *GetImagePolicyResponse) Reset()      { *m = GetImagePolicyResponse{} }
+func (*GetImagePolicyResponse) ProtoMessage() {}
+func (*GetImagePolicyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_271157b3a732118e, []int{1}
+}
+func (m *GetImagePolicyResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GetImagePolicyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GetImagePolicyResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GetImagePolicyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetImagePolicyResponse.Merge(m, src)
+}
+func (m *GetImagePolicyResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *GetImagePolicyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetImagePolicyResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetImagePolicyResponse proto.InternalMessageInfo
+
+func (m *GetImagePolicyResponse) GetRules() []*Rule {
+	if m != nil {
+		return m.Rules
+	}
+	return nil
+}
+
+// Rule is a rule of the signature policy of the images of a build. Keys and
+// certificates are PEM encoded.
+type Rule struct {
+	Images              string      `protobuf:"bytes,1,opt,name=Images,proto3" json:"Images,omitempty"`
+	Mode                string      `protobuf:"bytes,2,opt,name=Mode,proto3" json:"Mode,omitempty"`
+	Keys                [][]byte    `protobuf:"bytes,3,rep,name=Keys,proto3" json:"Keys,omitempty"`
+	Roots               [][]byte    `protobuf:"bytes,4,rep,name=Roots,proto3" json:"Roots,omitempty"`
+	Identities          []*Identity `protobuf:"bytes,5,rep,name=Identities,proto3" json:"Identities,omitempty"`
+	TransparencyLogKeys [][]byte    `protobuf:"bytes,6,rep,name=TransparencyLogKeys,proto3" json:"TransparencyLogKeys,omitempty"`
+	TimestampRoots      [][]byte    `protobuf:"bytes,7,rep,name=TimestampRoots,proto3" json:"TimestampRoots,omitempty"`
+}
+
+func (m *Rule) Reset()      { *m = Rule{} }
+func (*Rule) ProtoMessage() {}
+func (*Rule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_271157b3a732118e, []int{2}
+}
+func (m *Rule) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Rule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Rule.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Rule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Rule.Merge(m, src)
+}
+func (m *Rule) XXX_Size() int {
+	return m.Size()
+}
+func (m *Rule) XXX_DiscardUnknown() {
+	xxx_messageInfo_Rule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Rule proto.InternalMessageInfo
+
+func (m *Rule) GetImages() string {
+	if m != nil {
+		return m.Images
+	}
+	return ""
+}
+
+func (m *Rule) GetMode() string {
+	if m != nil {
+		return m.Mode
+	}
+	return ""
+}
+
+func (m *Rule) GetKeys() [][]byte {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+func (m *Rule) GetRoots() [][]byte {
+	if m != nil {
+		return m.Roots
+	}
+	return nil
+}
+
+func (m *Rule) GetIdentities() []*Identity {
+	if m != nil {
+		return m.Identities
+	}
+	return nil
+}
+
+func (m *Rule) GetTransparencyLogKeys() [][]byte {
+	if m != nil {
+		return m.TransparencyLogKeys
+	}
+	return nil
+}
+
+func (m *Rule) GetTimestampRoots() [][]byte {
+	if m != nil {
+		return m.TimestampRoots
+	}
+	return nil
+}
+
+type Identity struct {
+	Issuer  string `protobuf:"bytes,1,opt,name=Issuer,proto3" json:"Issuer,omitempty"`
+	Subject string `protobuf:"bytes,2,opt,name=Subject,proto3" json:"Subject,omitempty"`
+}
+
+func (m *Identity) Reset()      { *m = Identity{} }
+func (*Identity) ProtoMessage() {}
+func (*Identity) Descriptor() ([]byte, []int) {
+	return fileDescriptor_271157b3a732118e, []int{3}
+}
+func (m *Identity) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Identity) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Identity.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Identity) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Identity.Merge(m, src)
+}
+func (m *Identity) XXX_Size() int {
+	return m.Size()
+}
+func (m *Identity) XXX_DiscardUnknown() {
+	xxx_messageInfo_Identity.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Identity proto.InternalMessageInfo
+
+func (m *Identity) GetIssuer() string {
+	if m != nil {
+		return m.Issuer
+	}
+	return ""
+}
+
+func (m *Identity) GetSubject() string {
+	if m != nil {
+		return m.Subject
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*GetImagePolicyRequest)(nil), "moby.buildkit.imagepolicy.v1.GetImagePolicyRequest")
+	proto.RegisterType((*GetImagePolicyResponse)(nil), "moby.buildkit.imagepolicy.v1.GetImagePolicyResponse")
+	proto.RegisterType((*Rule)(nil), "moby.buildkit.imagepolicy.v1.Rule")
+	proto.RegisterType((*Identity)(nil), "moby.buildkit.imagepolicy.v1.Identity")
+}
+
+func init() { proto.RegisterFile("imagepolicy.proto", fileDescriptor_271157b3a732118e) }
+
+var fileDescriptor_271157b3a732118e = []byte{
+	// 378 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x52, 0x3f, 0x4f, 0xfb, 0x30,
+	0x10, 0x8d, 0x7f, 0xfd, 0xf7, 0xe3, 0x8a, 0x2a, 0x61, 0xa0, 0x44, 0x08, 0x59, 0x55, 0x86, 0xaa,
+	0x53, 0x04, 0x2d, 0x03, 0x03, 0x0b, 0x0c, 0xa0, 0x0a, 0x90, 0x90, 0xe9, 0xc4, 0x96, 0xb4, 0x56,
+	0x15, 0x68, 0xe2, 0x10, 0x3b, 0x48, 0x11, 0x0b, 0x33, 0x13, 0x1f, 0x83, 0x8f, 0xc2, 0xd8, 0xb1,
+	0x23, 0x4d, 0x17, 0xc6, 0x7e, 0x02, 0x84, 0xe2, 0xb4, 0x28, 0x54, 0x55, 0x25, 0xb6, 0x7b, 0xcf,
+	0xef, 0xee, 0x9e, 0xef, 0x0e, 0x36, 0x1c, 0xd7, 0xea, 0x33, 0x9f, 0x0f, 0x9c, 0x6e, 0x64, 0xfa,
+	0x01, 0x97, 0x1c, 0xef, 0xb9, 0xdc, 0x8e, 0x4c, 0x3b, 0x74, 0x06, 0xbd, 0x7b, 0x47, 0x9a, 0x59,
+	0xc1, 0xe3, 0x81, 0xb1, 0x03, 0xdb, 0xe7, 0x4c, 0xb6, 0x13, 0xf2, 0x5a, 0x91, 0x94, 0x3d, 0x84,
+	0x4c, 0x48, 0x83, 0x42, 0x75, 0xf1, 0x41, 0xf8, 0xdc, 0x13, 0x0c, 0x1f, 0x41, 0x81, 0x86, 0x03,
+	0x26, 0x74, 0x54, 0xcb, 0x35, 0xca, 0x4d, 0xc3, 0x5c, 0xd5, 0xc0, 0x4c, 0xa4, 0x34, 0x4d, 0x30,
+	0xbe, 0x10, 0xe4, 0x93, 0x08, 0x57, 0xa1, 0xa8, 0x2a, 0x27, 0x35, 0x50, 0x63, 0x8d, 0xce, 0x10,
+	0xc6, 0x90, 0xbf, 0xe2, 0x3d, 0xa6, 0xff, 0x53, 0xac, 0x8a, 0x13, 0xee, 0x82, 0x45, 0x42, 0xcf,
+	0xd5, 0x72, 0x8d, 0x75, 0xaa, 0x62, 0xbc, 0x05, 0x05, 0xca, 0xb9, 0x14, 0x7a, 0x5e, 0x91, 0x29,
+	0xc0, 0x67, 0x00, 0xed, 0x1e, 0xf3, 0xa4, 0x23, 0x1d, 0x26, 0xf4, 0x82, 0x72, 0x57, 0x5f, 0xed,
+	0x6e, 0xa6, 0x8f, 0x68, 0x26, 0x13, 0xef, 0xc3, 0x66, 0x27, 0xb0, 0x3c, 0xe1, 0x5b, 0x01, 0xf3,
+	0xba, 0xd1, 0x25, 0xef, 0x2b, 0x03, 0x45, 0xd5, 0x6b, 0xd9, 0x13, 0xae, 0x43, 0xa5, 0xe3, 0xb8,
+	0x4c, 0x48, 0xcb, 0xf5, 0x53, 0x63, 0x25, 0x25, 0x5e, 0x60, 0x8d, 0x63, 0xf8, 0x3f, 0xef, 0xa8,
+	0x66, 0x20, 0x44, 0xc8, 0x82, 0x9f, 0x19, 0x28, 0x84, 0x75, 0x28, 0xdd, 0x84, 0xf6, 0x1d, 0xeb,
+	0xca, 0xd9, 0x18, 0xe6, 0xb0, 0xf9, 0x82, 0xa0, 0x9c, 0x59, 0x08, 0x7e, 0x82, 0xca, 0xef, 0x15,
+	0xe1, 0xd6, 0xea, 0xdf, 0x2e, 0xdd, 0xf4, 0xee, 0xe1, 0xdf, 0x92, 0xd2, 0x2b, 0x38, 0x3d, 0x19,
+	0x8e, 0x89, 0x36, 0x1a, 0x13, 0x6d, 0x3a, 0x26, 0xe8, 0x39, 0x26, 0xe8, 0x2d, 0x26, 0xe8, 0x3d,
+	0x26, 0x68, 0x18, 0x13, 0xf4, 0x11, 0x13, 0xf4, 0x19, 0x13, 0x6d, 0x1a, 0x13, 0xf4, 0x3a, 0x21,
+	0xda, 0x70, 0x42, 0xb4, 0xd1, 0x84, 0x68, 0xb7, 0xe5, 0x4c, 0x71, 0xbb, 0xa8, 0x0e, 0xb4, 0xf5,
+	0x3d, 0x00, 0x1a, 0x4a, 0xd4, 0xe7, 0xb5, 0x02, 0x00, 0x00,
+}
+
+func (this *GetImagePolicyRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*GetImagePolicyRequest)
+	if !ok {
+		that2, ok := that.(GetImagePolicyRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
+func (this *GetImagePolicyResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*GetImagePolicyResponse)
+	if !ok {
+		that2, ok := that.(GetImagePolicyResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Rules) != len(that1.Rules) {
+		return false
+	}
+	for i := range this.Rules {
+		if !this.Rules[i].Equal(that1.Rules[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *Rule) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Rule)
+	if !ok {
+		that2, ok := that.(Rule)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Images != that1.Images {
+		return false
+	}
+	if this.Mode != that1.Mode {
+		return false
+	}
+	if len(this.Keys) != len(that1.Keys) {
+		return false
+	}
+	for i := range this.Keys {
+		if !bytes.Equal(this.Keys[i], that1.Keys[i]) {
+			return false
+		}
+	}
+	if len(this.Roots) != len(that1.Roots) {
+		return false
+	}
+	for i := range this.Roots {
+		if !bytes.Equal(this.Roots[i], that1.Roots[i]) {
+			return false
+		}
+	}
+	if len(this.Identities) != len(that1.Identities) {
+		return false
+	}
+	for i := range this.Identities {
+		if !this.Identities[i].Equal(that1.Identities[i]) {
+			return false
+		}
+	}
+	if len(this.TransparencyLogKeys) != len(that1.TransparencyLogKeys) {
+		return false
+	}
+	for i := range this.TransparencyLogKeys {
+		if !bytes.Equal(this.TransparencyLogKeys[i], that1.TransparencyLogKeys[i]) {
+			return false
+		}
+	}
+	if len(this.TimestampRoots) != len(that1.TimestampRoots) {
+		return false
+	}
+	for i := range this.TimestampRoots {
+		if !bytes.Equal(this.TimestampRoots[i], that1.TimestampRoots[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *Identity) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Identity)
+	if !ok {
+		that2, ok := that.(Identity)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Issuer != that1.Issuer {
+		return false
+	}
+	if this.Subject != that1.Subject {
+		return false
+	}
+	return true
+}
+func (this *GetImagePolicyRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&imagepolicy.GetImagePolicyRequest{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *GetImagePolicyResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&imagepolicy.GetImagePolicyResponse{")
+	if this.Rules != nil {
+		s = append(s, "Rules: "+fmt.Sprintf("%#v", this.Rules)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Rule) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 11)
+	s = append(s, "&imagepolicy.Rule{")
+	s = append(s, "Images: "+fmt.Sprintf("%#v", this.Images)+",\n")
+	s = append(s, "Mode: "+fmt.Sprintf("%#v", this.Mode)+",\n")
+	s = append(s, "Keys: "+fmt.Sprintf("%#v", this.Keys)+",\n")
+	s = append(s, "Roots: "+fmt.Sprintf("%#v", this.Roots)+",\n")
+	if this.Identities != nil {
+		s = append(s, "Identities: "+fmt.Sprintf("%#v", this.Identities)+",\n")
+	}
+	s = append(s, "TransparencyLogKeys: "+fmt.Sprintf("%#v", this.TransparencyLogKeys)+",\n")
+	s = append(s, "TimestampRoots: "+fmt.Sprintf("%#v", this.TimestampRoots)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Identity) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&imagepolicy.Identity{")
+	s = append(s, "Issuer: "+fmt.Sprintf("%#v", this.Issuer)+",\n")
+	s = append(s, "Subject: "+fmt.Sprintf("%#v", this.Subject)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func valueToGoStringImagepolicy(v interface{}, typ string) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ImagePolicyClient is the client API for ImagePolicy service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ImagePolicyClient interface {
+	GetImagePolicy(ctx context.Context, in *GetImagePolicyRequest, opts ...grpc.CallOption) (*GetImagePolicyResponse, error)
+}
+
+type imagePolicyClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewImagePolicyClient(cc *grpc.ClientConn) ImagePolicyClient {
+	return &imagePolicyClient{cc}
+}
+
+func (c *imagePolicyClient) GetImagePolicy(ctx context.Context, in *GetImagePolicyRequest, opts ...grpc.CallOption) (*GetImagePolicyResponse, error) {
+	out := new(GetImagePolicyResponse)
+	err := c.cc.Invoke(ctx, "/moby.buildkit.imagepolicy.v1.ImagePolicy/GetImagePolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ImagePolicyServer is the server API for ImagePolicy service.
+type ImagePolicyServer interface {
+	GetImagePolicy(context.Context, *GetImagePolicyRequest) (*GetImagePolicyResponse, error)
+}
+
+// UnimplementedImagePolicyServer can be embedded to have forward compatible implementations.
+type UnimplementedImagePolicyServer struct {
+}
+
+func (*UnimplementedImagePolicyServer) GetImagePolicy(ctx context.Context, req *GetImagePolicyRequest) (*GetImagePolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetImagePolicy not implemented")
+}
+
+func RegisterImagePolicyServer(s *grpc.Server, srv ImagePolicyServer) {
+	s.RegisterService(&_ImagePolicy_serviceDesc, srv)
+}
+
+func _ImagePolicy_GetImagePolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetImagePolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImagePolicyServer).GetImagePolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/moby.buildkit.imagepolicy.v1.ImagePolicy/GetImagePolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImagePolicyServer).GetImagePolicy(ctx, req.(*GetImagePolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ImagePolicy_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "moby.buildkit.imagepolicy.v1.ImagePolicy",
+	HandlerType: (*ImagePolicyServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetImagePolicy",
+			Handler:    _ImagePolicy_GetImagePolicy_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "imagepolicy.proto",
+}
+
+func (m *GetImagePolicyRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetImagePolicyRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GetImagePolicyRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *GetImagePolicyResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetImagePolicyResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GetImagePolicyResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Rules) > 0 {
+		for iNdEx := len(m.Rules) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Rules[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintImagepolicy(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Rule) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Rule) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Rule) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.TimestampRoots) > 0 {
+		for iNdEx := len(m.TimestampRoots) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.TimestampRoots[iNdEx])
+			copy(dAtA[i:], m.TimestampRoots[iNdEx])
+			i = encodeVarintImagepolicy(dAtA, i, uint64(len(m.TimestampRoots[iNdEx])))
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if len(m.TransparencyLogKeys) > 0 {
+		for iNdEx := len(m.TransparencyLogKeys) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.TransparencyLogKeys[iNdEx])
+			copy(dAtA[i:], m.TransparencyLogKeys[iNdEx])
+			i = encodeVarintImagepolicy(dAtA, i, uint64(len(m.TransparencyLogKeys[iNdEx])))
+			i--
+			dAtA[i] = 0x32
+		}
+	}
+	if len(m.Identities) > 0 {
+		for iNdEx := len(m.Identities) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Identities[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintImagepolicy(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if len(m.Roots) > 0 {
+		for iNdEx := len(m.Roots) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Roots[iNdEx])
+			copy(dAtA[i:], m.Roots[iNdEx])
+			i = encodeVarintImagepolicy(dAtA, i, uint64(len(m.Roots[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.Keys) > 0 {
+		for iNdEx := len(m.Keys) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Keys[iNdEx])
+			copy(dAtA[i:], m.Keys[iNdEx])
+			i = encodeVarintImagepolicy(dAtA, i, uint64(len(m.Keys[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Mode) > 0 {
+		i -= len(m.Mode)
+		copy(dAtA[i:], m.Mode)
+		i = encodeVarintImagepolicy(dAtA, i, uint64(len(m.Mode)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Images) > 0 {
+		i -= len(m.Images)
+		copy(dAtA[i:], m.Images)
+		i = encodeVarintImagepolicy(dAtA, i, uint64(len(m.Images)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Identity) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Identity) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Identity) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Subject) > 0 {
+		i -= len(m.Subject)
+		copy(dAtA[i:], m.Subject)
+		i = encodeVarintImagepolicy(dAtA, i, uint64(len(m.Subject)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Issuer) > 0 {
+		i -= len(m.Issuer)
+		copy(dAtA[i:], m.Issuer)
+		i = encodeVarintImagepolicy(dAtA, i, uint64(len(m.Issuer)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintImagepolicy(dAtA []byte, offset int, v uint64) int {
+	offset -= sovImagepolicy(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *GetImagePolicyRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *GetImagePolicyResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Rules) > 0 {
+		for _, e := range m.Rules {
+			l = e.Size()
+			n += 1 + l + sovImagepolicy(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Rule) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Images)
+	if l > 0 {
+		n += 1 + l + sovImagepolicy(uint64(l))
+	}
+	l = len(m.Mode)
+	if l > 0 {
+		n += 1 + l + sovImagepolicy(uint64(l))
+	}
+	if len(m.Keys) > 0 {
+		for _, b := range m.Keys {
+			l = len(b)
+			n += 1 + l + sovImagepolicy(uint64(l))
+		}
+	}
+	if len(m.Roots) > 0 {
+		for _, b := range m.Roots {
+			l = len(b)
+			n += 1 + l + sovImagepolicy(uint64(l))
+		}
+	}
+	if len(m.Identities) > 0 {
+		for _, e := range m.Identities {
+			l = e.Size()
+			n += 1 + l + sovImagepolicy(uint64(l))
+		}
+	}
+	if len(m.TransparencyLogKeys) > 0 {
+		for _, b := range m.TransparencyLogKeys {
+			l = len(b)
+			n += 1 + l + sovImagepolicy(uint64(l))
+		}
+	}
+	if len(m.TimestampRoots) > 0 {
+		for _, b := range m.TimestampRoots {
+			l = len(b)
+			n += 1 + l + sovImagepolicy(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Identity) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Issuer)
+	if l > 0 {
+		n += 1 + l + sovImagepolicy(uint64(l))
+	}
+	l = len(m.Subject)
+	if l > 0 {
+		n += 1 + l + sovImagepolicy(uint64(l))
+	}
+	return n
+}
+
+func sovImagepolicy(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozImagepolicy(x uint64) (n int) {
+	return sovImagepolicy(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *GetImagePolicyRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&GetImagePolicyRequest{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *GetImagePolicyResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForRules := "[]*Rule{"
+	for _, f := range this.Rules {
+		repeatedStringForRules += strings.Replace(f.String(), "Rule", "Rule", 1) + ","
+	}
+	repeatedStringForRules += "}"
+	s := strings.Join([]string{`&GetImagePolicyResponse{`,
+		`Rules:` + repeatedStringForRules + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Rule) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForIdentities := "[]*Identity{"
+	for _, f := range this.Identities {
+		repeatedStringForIdentities += strings.Replace(f.String(), "Identity", "Identity", 1) + ","
+	}
+	repeatedStringForIdentities += "}"
+	s := strings.Join([]string{`&Rule{`,
+		`Images:` + fmt.Sprintf("%v", this.Images) + `,`,
+		`Mode:` + fmt.Sprintf("%v", this.Mode) + `,`,
+		`Keys:` + fmt.Sprintf("%v", this.Keys) + `,`,
+		`Roots:` + fmt.Sprintf("%v", this.Roots) + `,`,
+		`Identities:` + repeatedStringForIdentities + `,`,
+		`TransparencyLogKeys:` + fmt.Sprintf("%v", this.TransparencyLogKeys) + `,`,
+		`TimestampRoots:` + fmt.Sprintf("%v", this.TimestampRoots) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Identity) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Identity{`,
+		`Issuer:` + fmt.Sprintf("%v", this.Issuer) + `,`,
+		`Subject:` + fmt.Sprintf("%v", this.Subject) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringImagepolicy(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *GetImagePolicyRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowImagepolicy
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetImagePolicyRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetImagePolicyRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipImagepolicy(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetImagePolicyResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowImagepolicy
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetImagePolicyResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetImagePolicyResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rules", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowImagepolicy
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Rules = append(m.Rules, &Rule{})
+			if err := m.Rules[len(m.Rules)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipImagepolicy(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Rule) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowImagepolicy
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Rule: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Rule: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Images", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowImagepolicy
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Images = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mode", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowImagepolicy
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Mode = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Keys", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowImagepolicy
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Keys = append(m.Keys, make([]byte, postIndex-iNdEx))
+			copy(m.Keys[len(m.Keys)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Roots", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowImagepolicy
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Roots = append(m.Roots, make([]byte, postIndex-iNdEx))
+			copy(m.Roots[len(m.Roots)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Identities", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowImagepolicy
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Identities = append(m.Identities, &Identity{})
+			if err := m.Identities[len(m.Identities)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TransparencyLogKeys", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowImagepolicy
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TransparencyLogKeys = append(m.TransparencyLogKeys, make([]byte, postIndex-iNdEx))
+			copy(m.TransparencyLogKeys[len(m.TransparencyLogKeys)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimestampRoots", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowImagepolicy
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TimestampRoots = append(m.TimestampRoots, make([]byte, postIndex-iNdEx))
+			copy(m.TimestampRoots[len(m.TimestampRoots)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipImagepolicy(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Identity) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowImagepolicy
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Identity: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Identity: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Issuer", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowImagepolicy
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Issuer = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Subject", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowImagepolicy
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Subject = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipImagepolicy(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthImagepolicy
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipImagepolicy(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowImagepolicy
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowImagepolicy
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowImagepolicy
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthImagepolicy
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupImagepolicy
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthImagepolicy
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthImagepolicy        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowImagepolicy          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupImagepolicy = fmt.Errorf("proto: unexpected end of group")
+)
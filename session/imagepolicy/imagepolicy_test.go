@@ -0,0 +1,83 @@
+package imagepolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/testutil"
+	policy "github.com/moby/buildkit/util/imagepolicy"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestGetPolicy(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	sm, err := session.NewManager()
+	require.NoError(t, err)
+
+	s := newTestSession(ctx, t, sm, &testProvider{resp: &GetImagePolicyResponse{
+		Rules: []*Rule{{Images: "docker.io/library/*", Mode: policy.ModeNone}},
+	}})
+	defer s.Close()
+	p, err := GetPolicy(ctx, sm, s.ID())
+	require.NoError(t, err)
+	require.True(t, p.Enabled())
+
+	// the digest identifies the rules
+	expected, err := policy.NewFromPEM([]policy.PEMRule{{Images: "docker.io/library/*", Mode: policy.ModeNone}})
+	require.NoError(t, err)
+	require.Equal(t, expected.Digest(), p.Digest())
+
+	// sessions without a provider have no policy
+	s = newTestSession(ctx, t, sm, nil)
+	defer s.Close()
+	p, err = GetPolicy(ctx, sm, s.ID())
+	require.NoError(t, err)
+	require.False(t, p.Enabled())
+
+	// other errors fail the build
+	s = newTestSession(ctx, t, sm, &testProvider{err: errors.New("broken")})
+	defer s.Close()
+	_, err = GetPolicy(ctx, sm, s.ID())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "broken")
+
+	s = newTestSession(ctx, t, sm, &testProvider{resp: &GetImagePolicyResponse{
+		Rules: []*Rule{{Images: "docker.io/library/*", Mode: policy.ModeKeyed}},
+	}})
+	defer s.Close()
+	_, err = GetPolicy(ctx, sm, s.ID())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid image policy")
+}
+
+func newTestSession(ctx context.Context, t *testing.T, sm *session.Manager, a session.Attachable) *session.Session {
+	s, err := session.NewSession(ctx, "foo", "bar")
+	require.NoError(t, err)
+	if a != nil {
+		s.Allow(a)
+	}
+	go s.Run(ctx, session.Dialer(testutil.TestStream(testutil.Handler(sm.HandleConn))))
+	return s
+}
+
+type testProvider struct {
+	resp *GetImagePolicyResponse
+	err  error
+}
+
+func (p *testProvider) Register(server *grpc.Server) {
+	RegisterImagePolicyServer(server, p)
+}
+
+func (p *testProvider) GetImagePolicy(ctx context.Context, req *GetImagePolicyRequest) (*GetImagePolicyResponse, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.resp, nil
+}
@@ -0,0 +1,55 @@
+package imagepolicyprovider
+
+import (
+	"context"
+
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/imagepolicy"
+	policy "github.com/moby/buildkit/util/imagepolicy"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// NewImagePolicyProvider returns a session attachable that requires the images
+// pulled by the build to be signed according to the rules, in addition to the
+// image policy of the daemon. The keys and certificates of the rules are read
+// from the client.
+func NewImagePolicyProvider(rules []policy.Rule) (session.Attachable, error) {
+	resp := &imagepolicy.GetImagePolicyResponse{}
+	pemRules := make([]policy.PEMRule, len(rules))
+	for i, r := range rules {
+		pr, err := r.Load()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid rule for %s", r.Images)
+		}
+		pemRules[i] = pr
+		rule := &imagepolicy.Rule{
+			Images:              pr.Images,
+			Mode:                pr.Mode,
+			Keys:                pr.Keys,
+			Roots:               pr.Roots,
+			TransparencyLogKeys: pr.TransparencyLogKeys,
+			TimestampRoots:      pr.TimestampRoots,
+		}
+		for _, id := range pr.Identities {
+			rule.Identities = append(rule.Identities, &imagepolicy.Identity{Issuer: id.Issuer, Subject: id.Subject})
+		}
+		resp.Rules = append(resp.Rules, rule)
+	}
+	if _, err := policy.NewFromPEM(pemRules); err != nil {
+		return nil, err
+	}
+	return &imagePolicyProvider{resp: resp}, nil
+}
+
+type imagePolicyProvider struct {
+	resp *imagepolicy.GetImagePolicyResponse
+}
+
+func (p *imagePolicyProvider) Register(server *grpc.Server) {
+	imagepolicy.RegisterImagePolicyServer(server, p)
+}
+
+func (p *imagePolicyProvider) GetImagePolicy(ctx context.Context, req *imagepolicy.GetImagePolicyRequest) (*imagepolicy.GetImagePolicyResponse, error) {
+	return p.resp, nil
+}
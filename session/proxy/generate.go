@@ -0,0 +1,3 @@
+package proxy
+
+//go:generate protoc --gogoslick_out=plugins=grpc:. proxy.proto
@@ -0,0 +1,171 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/util/flightcontrol"
+	"github.com/moby/buildkit/util/grpcerrors"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/http/httpproxy"
+	"google.golang.org/grpc/codes"
+)
+
+// Config is the proxy configuration of a build. It replaces the proxy
+// environment variables of the daemon for the requests of the build.
+type Config struct {
+	HTTPProxy  string
+	HTTPSProxy string
+	NoProxy    string
+}
+
+// Env returns the configuration as environment variables for child processes
+func (c *Config) Env() []string {
+	return []string{
+		"http_proxy=" + c.HTTPProxy,
+		"HTTP_PROXY=" + c.HTTPProxy,
+		"https_proxy=" + c.HTTPSProxy,
+		"HTTPS_PROXY=" + c.HTTPSProxy,
+		"no_proxy=" + c.NoProxy,
+		"NO_PROXY=" + c.NoProxy,
+	}
+}
+
+// Digest returns the digest of the configuration
+func (c *Config) Digest() digest.Digest {
+	return digest.FromString(strings.Join([]string{c.HTTPProxy, c.HTTPSProxy, c.NoProxy}, "\n"))
+}
+
+// GetConfig returns the proxy configuration of the session group. The
+// configuration of a session is requested once and cached until the session
+// ends. If none of the sessions provides a configuration, or the sessions
+// can't be reached, nil is returned and the environment of the daemon is used.
+func GetConfig(ctx context.Context, sm *session.Manager, g session.Group) *Config {
+	var cfg *Config
+	err := sm.Any(ctx, g, func(ctx context.Context, id string, c session.Caller) error {
+		var err error
+		cfg, err = configs.get(ctx, id, c)
+		return err
+	})
+	if err != nil {
+		logrus.Warnf("failed to get proxy configuration of the build, using the daemon environment: %v", err)
+		return nil
+	}
+	return cfg
+}
+
+// configs caches the proxy configurations of the sessions
+var configs = &configCache{m: map[string]*Config{}}
+
+type configCache struct {
+	mu sync.Mutex
+	// m is the configuration of each session, nil if the session has none
+	m map[string]*Config
+	g flightcontrol.Group
+}
+
+func (cc *configCache) get(ctx context.Context, id string, c session.Caller) (*Config, error) {
+	cc.mu.Lock()
+	cfg, ok := cc.m[id]
+	cc.mu.Unlock()
+	if ok {
+		return cfg, nil
+	}
+	v, err := cc.g.Do(ctx, id, func(ctx context.Context) (interface{}, error) {
+		cc.mu.Lock()
+		cfg, ok := cc.m[id]
+		cc.mu.Unlock()
+		if ok {
+			return cfg, nil
+		}
+		cfg, err := requestConfig(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		cc.mu.Lock()
+		cc.m[id] = cfg
+		cc.mu.Unlock()
+		go func() {
+			<-c.Context().Done()
+			cc.mu.Lock()
+			delete(cc.m, id)
+			cc.mu.Unlock()
+		}()
+		return cfg, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Config), nil
+}
+
+func requestConfig(ctx context.Context, c session.Caller) (*Config, error) {
+	client := NewProxyClient(c.Conn())
+	resp, err := client.GetProxyConfig(ctx, &GetProxyConfigRequest{})
+	if err != nil {
+		if grpcerrors.Code(err) == codes.Unimplemented {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &Config{
+		HTTPProxy:  resp.HTTPProxy,
+		HTTPSProxy: resp.HTTPSProxy,
+		NoProxy:    resp.NoProxy,
+	}, nil
+}
+
+type configKey struct{}
+
+// WithConfig returns a context that makes ProxyFunc use cfg
+func WithConfig(ctx context.Context, cfg *Config) context.Context {
+	if cfg == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, configKey{}, cfg)
+}
+
+// FromContext returns the proxy configuration set with WithConfig
+func FromContext(ctx context.Context) *Config {
+	cfg, _ := ctx.Value(configKey{}).(*Config)
+	return cfg
+}
+
+// ProxyFunc can be used as the Proxy function of an http.Transport. It uses
+// the configuration of the context of the request and falls back to the
+// environment of the daemon.
+func ProxyFunc(req *http.Request) (*url.URL, error) {
+	cfg := FromContext(req.Context())
+	if cfg == nil {
+		return http.ProxyFromEnvironment(req)
+	}
+	c := httpproxy.Config{
+		HTTPProxy:  cfg.HTTPProxy,
+		HTTPSProxy: cfg.HTTPSProxy,
+		NoProxy:    cfg.NoProxy,
+	}
+	return c.ProxyFunc()(req.URL)
+}
+
+// NewTransport returns a transport that sends the requests with the proxy
+// configuration cfg. The Proxy function of rt must be ProxyFunc.
+func NewTransport(rt http.RoundTripper, cfg *Config) http.RoundTripper {
+	if cfg == nil {
+		return rt
+	}
+	return &transport{rt: rt, cfg: cfg}
+}
+
+type transport struct {
+	rt  http.RoundTripper
+	cfg *Config
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.rt.RoundTrip(req.WithContext(WithConfig(req.Context(), t.cfg)))
+}
@@ -0,0 +1,734 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: proxy.proto
+
+package proxy
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/gogo/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+	reflect "reflect"
+	strings "strings"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+type GetProxyConfigRequest struct {
+}
+
+func (m *GetProxyConfigRequest) Reset()      { *m = GetProxyConfigRequest{} }
+func (*GetProxyConfigRequest) ProtoMessage() {}
+func (*GetProxyConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_700b50b08ed8dbaf, []int{0}
+}
+func (m *GetProxyConfigRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GetProxyConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GetProxyConfigRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GetProxyConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetProxyConfigRequest.Merge(m, src)
+}
+func (m *GetProxyConfigRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *GetProxyConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetProxyConfigRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetProxyConfigRequest proto.InternalMessageInfo
+
+type GetProxyConfigResponse struct {
+	HTTPProxy  string `protobuf:"bytes,1,opt,name=HTTPProxy,proto3" json:"HTTPProxy,omitempty"`
+	HTTPSProxy string `protobuf:"bytes,2,opt,name=HTTPSProxy,proto3" json:"HTTPSProxy,omitempty"`
+	NoProxy    string `protobuf:"bytes,3,opt,name=NoProxy,proto3" json:"NoProxy,omitempty"`
+}
+
+func (m *GetProxyConfigResponse) Reset()      { *m = GetProxyConfigResponse{} }
+func (*GetProxyConfigResponse) ProtoMessage() {}
+func (*GetProxyConfigResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_700b50b08ed8dbaf, []int{1}
+}
+func (m *GetProxyConfigResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GetProxyConfigResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GetProxyConfigResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GetProxyConfigResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetProxyConfigResponse.Merge(m, src)
+}
+func (m *GetProxyConfigResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *GetProxyConfigResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetProxyConfigResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetProxyConfigResponse proto.InternalMessageInfo
+
+func (m *GetProxyConfigResponse) GetHTTPProxy() string {
+	if m != nil {
+		return m.HTTPProxy
+	}
+	return ""
+}
+
+func (m *GetProxyConfigResponse) GetHTTPSProxy() string {
+	if m != nil {
+		return m.HTTPSProxy
+	}
+	return ""
+}
+
+func (m *GetProxyConfigResponse) GetNoProxy() string {
+	if m != nil {
+		return m.NoProxy
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*GetProxyConfigRequest)(nil), "moby.buildkit.proxy.v1.GetProxyConfigRequest")
+	proto.RegisterType((*GetProxyConfigResponse)(nil), "moby.buildkit.proxy.v1.GetProxyConfigResponse")
+}
+
+func init() { proto.RegisterFile("proxy.proto", fileDescriptor_700b50b08ed8dbaf) }
+
+var fileDescriptor_700b50b08ed8dbaf = []byte{
+	// 227 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x2e, 0x28, 0xca, 0xaf,
+	0xa8, 0xd4, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x12, 0xcb, 0xcd, 0x4f, 0xaa, 0xd4, 0x4b, 0x2a,
+	0xcd, 0xcc, 0x49, 0xc9, 0xce, 0x2c, 0xd1, 0x83, 0x48, 0x95, 0x19, 0x2a, 0x89, 0x73, 0x89, 0xba,
+	0xa7, 0x96, 0x04, 0x80, 0xb8, 0xce, 0xf9, 0x79, 0x69, 0x99, 0xe9, 0x41, 0xa9, 0x85, 0xa5, 0xa9,
+	0xc5, 0x25, 0x4a, 0x05, 0x5c, 0x62, 0xe8, 0x12, 0xc5, 0x05, 0xf9, 0x79, 0xc5, 0xa9, 0x42, 0x32,
+	0x5c, 0x9c, 0x1e, 0x21, 0x21, 0x01, 0x60, 0x29, 0x09, 0x46, 0x05, 0x46, 0x0d, 0xce, 0x20, 0x84,
+	0x80, 0x90, 0x1c, 0x17, 0x17, 0x88, 0x13, 0x0c, 0x91, 0x66, 0x02, 0x4b, 0x23, 0x89, 0x08, 0x49,
+	0x70, 0xb1, 0xfb, 0xe5, 0x43, 0x24, 0x99, 0xc1, 0x92, 0x30, 0xae, 0x51, 0x05, 0x17, 0x2b, 0x44,
+	0x49, 0x3e, 0x17, 0x1f, 0xaa, 0xd5, 0x42, 0xba, 0x7a, 0xd8, 0x9d, 0xaf, 0x87, 0xd5, 0xed, 0x52,
+	0x7a, 0xc4, 0x2a, 0x87, 0xf8, 0xc8, 0xc9, 0xfa, 0xc2, 0x43, 0x39, 0x86, 0x1b, 0x0f, 0xe5, 0x18,
+	0x3e, 0x3c, 0x94, 0x63, 0x6c, 0x78, 0x24, 0xc7, 0xb8, 0xe2, 0x91, 0x1c, 0xe3, 0x89, 0x47, 0x72,
+	0x8c, 0x17, 0x1e, 0xc9, 0x31, 0x3e, 0x78, 0x24, 0xc7, 0xf8, 0xe2, 0x91, 0x1c, 0xc3, 0x87, 0x47,
+	0x72, 0x8c, 0x13, 0x1e, 0xcb, 0x31, 0x5c, 0x78, 0x2c, 0xc7, 0x70, 0xe3, 0xb1, 0x1c, 0x43, 0x14,
+	0x2b, 0xd8, 0xd8, 0x24, 0x36, 0x70, 0x00, 0x1b, 0x03, 0x06, 0x00, 0x13, 0x4d, 0xa3, 0x29, 0x6f,
+	0x01, 0x00, 0x00,
+}
+
+func (this *GetProxyConfigRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*GetProxyConfigRequest)
+	if !ok {
+		that2, ok := that.(GetProxyConfigRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
+func (this *GetProxyConfigResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*GetProxyConfigResponse)
+	if !ok {
+		that2, ok := that.(GetProxyConfigResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.HTTPProxy != that1.HTTPProxy {
+		return false
+	}
+	if this.HTTPSProxy != that1.HTTPSProxy {
+		return false
+	}
+	if this.NoProxy != that1.NoProxy {
+		return false
+	}
+	return true
+}
+func (this *GetProxyConfigRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&proxy.GetProxyConfigRequest{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *GetProxyConfigResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&proxy.GetProxyConfigResponse{")
+	s = append(s, "HTTPProxy: "+fmt.Sprintf("%#v", this.HTTPProxy)+",\n")
+	s = append(s, "HTTPSProxy: "+fmt.Sprintf("%#v", this.HTTPSProxy)+",\n")
+	s = append(s, "NoProxy: "+fmt.Sprintf("%#v", this.NoProxy)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func valueToGoStringProxy(v interface{}, typ string) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ProxyClient is the client API for Proxy service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ProxyClient interface {
+	GetProxyConfig(ctx context.Context, in *GetProxyConfigRequest, opts ...grpc.CallOption) (*GetProxyConfigResponse, error)
+}
+
+type proxyClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewProxyClient(cc *grpc.ClientConn) ProxyClient {
+	return &proxyClient{cc}
+}
+
+func (c *proxyClient) GetProxyConfig(ctx context.Context, in *GetProxyConfigRequest, opts ...grpc.CallOption) (*GetProxyConfigResponse, error) {
+	out := new(GetProxyConfigResponse)
+	err := c.cc.Invoke(ctx, "/moby.buildkit.proxy.v1.Proxy/GetProxyConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProxyServer is the server API for Proxy service.
+type ProxyServer interface {
+	GetProxyConfig(context.Context, *GetProxyConfigRequest) (*GetProxyConfigResponse, error)
+}
+
+// UnimplementedProxyServer can be embedded to have forward compatible implementations.
+type UnimplementedProxyServer struct {
+}
+
+func (*UnimplementedProxyServer) GetProxyConfig(ctx context.Context, req *GetProxyConfigRequest) (*GetProxyConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProxyConfig not implemented")
+}
+
+func RegisterProxyServer(s *grpc.Server, srv ProxyServer) {
+	s.RegisterService(&_Proxy_serviceDesc, srv)
+}
+
+func _Proxy_GetProxyConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProxyConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProxyServer).GetProxyConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/moby.buildkit.proxy.v1.Proxy/GetProxyConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProxyServer).GetProxyConfig(ctx, req.(*GetProxyConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Proxy_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "moby.buildkit.proxy.v1.Proxy",
+	HandlerType: (*ProxyServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetProxyConfig",
+			Handler:    _Proxy_GetProxyConfig_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proxy.proto",
+}
+
+func (m *GetProxyConfigRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetProxyConfigRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GetProxyConfigRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *GetProxyConfigResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetProxyConfigResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GetProxyConfigResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.NoProxy) > 0 {
+		i -= len(m.NoProxy)
+		copy(dAtA[i:], m.NoProxy)
+		i = encodeVarintProxy(dAtA, i, uint64(len(m.NoProxy)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.HTTPSProxy) > 0 {
+		i -= len(m.HTTPSProxy)
+		copy(dAtA[i:], m.HTTPSProxy)
+		i = encodeVarintProxy(dAtA, i, uint64(len(m.HTTPSProxy)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.HTTPProxy) > 0 {
+		i -= len(m.HTTPProxy)
+		copy(dAtA[i:], m.HTTPProxy)
+		i = encodeVarintProxy(dAtA, i, uint64(len(m.HTTPProxy)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintProxy(dAtA []byte, offset int, v uint64) int {
+	offset -= sovProxy(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *GetProxyConfigRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *GetProxyConfigResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.HTTPProxy)
+	if l > 0 {
+		n += 1 + l + sovProxy(uint64(l))
+	}
+	l = len(m.HTTPSProxy)
+	if l > 0 {
+		n += 1 + l + sovProxy(uint64(l))
+	}
+	l = len(m.NoProxy)
+	if l > 0 {
+		n += 1 + l + sovProxy(uint64(l))
+	}
+	return n
+}
+
+func sovProxy(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozProxy(x uint64) (n int) {
+	return sovProxy(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *GetProxyConfigRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&GetProxyConfigRequest{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *GetProxyConfigResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&GetProxyConfigResponse{`,
+		`HTTPProxy:` + fmt.Sprintf("%v", this.HTTPProxy) + `,`,
+		`HTTPSProxy:` + fmt.Sprintf("%v", this.HTTPSProxy) + `,`,
+		`NoProxy:` + fmt.Sprintf("%v", this.NoProxy) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringProxy(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *GetProxyConfigRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProxy
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetProxyConfigRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetProxyConfigRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProxy(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthProxy
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetProxyConfigResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProxy
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetProxyConfigResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetProxyConfigResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HTTPProxy", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProxy
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProxy
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProxy
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HTTPProxy = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HTTPSProxy", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProxy
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProxy
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProxy
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HTTPSProxy = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NoProxy", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProxy
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProxy
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProxy
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NoProxy = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProxy(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthProxy
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipProxy(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowProxy
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowProxy
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowProxy
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthProxy
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupProxy
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthProxy
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthProxy        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowProxy          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupProxy = fmt.Errorf("proto: unexpected end of group")
+)
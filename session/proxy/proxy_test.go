@@ -0,0 +1,147 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/testutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestProxyFunc(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{
+		HTTPProxy:  "http://proxy.example.com:3128",
+		HTTPSProxy: "http://secure-proxy.example.com:3128",
+		NoProxy:    "internal.example.com",
+	}
+	ctx := WithConfig(context.TODO(), cfg)
+
+	for _, tc := range []struct {
+		url      string
+		expected string
+	}{
+		{url: "http://example.com/foo", expected: "http://proxy.example.com:3128"},
+		{url: "https://example.com/foo", expected: "http://secure-proxy.example.com:3128"},
+		{url: "https://internal.example.com/foo"},
+	} {
+		req, err := http.NewRequest("GET", tc.url, nil)
+		require.NoError(t, err)
+		u, err := ProxyFunc(req.WithContext(ctx))
+		require.NoError(t, err)
+		if tc.expected == "" {
+			require.Nil(t, u, tc.url)
+			continue
+		}
+		require.NotNil(t, u, tc.url)
+		require.Equal(t, tc.expected, u.String())
+	}
+}
+
+func TestConfigDigest(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{HTTPProxy: "http://proxy.example.com:3128"}
+	require.Equal(t, cfg.Digest(), (&Config{HTTPProxy: "http://proxy.example.com:3128"}).Digest())
+	// the same value in another field is another configuration
+	require.NotEqual(t, cfg.Digest(), (&Config{HTTPSProxy: "http://proxy.example.com:3128"}).Digest())
+	require.NotEqual(t, cfg.Digest(), (&Config{NoProxy: "http://proxy.example.com:3128"}).Digest())
+	// an empty configuration doesn't use the proxy of the daemon either
+	require.NotEqual(t, "", (&Config{}).Digest().String())
+}
+
+func TestTransport(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{HTTPSProxy: "http://proxy.example.com:3128"}
+	var got *Config
+	rt := NewTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		got = FromContext(req.Context())
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}), cfg)
+
+	req, err := http.NewRequest("GET", "https://example.com/foo", nil)
+	require.NoError(t, err)
+	_, err = rt.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, cfg, got)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestGetConfig(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	sm, err := session.NewManager()
+	require.NoError(t, err)
+
+	provider := &countingProvider{cfg: &GetProxyConfigResponse{HTTPSProxy: "http://proxy.example.com:3128"}}
+	s := newTestSession(ctx, t, sm, provider)
+	g := session.NewGroup(s.ID())
+
+	for i := 0; i < 3; i++ {
+		cfg := GetConfig(ctx, sm, g)
+		require.Equal(t, &Config{HTTPSProxy: "http://proxy.example.com:3128"}, cfg)
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&provider.calls))
+
+	// the configuration of a session is removed when it ends
+	require.NoError(t, s.Close())
+	require.Eventually(t, func() bool {
+		configs.mu.Lock()
+		defer configs.mu.Unlock()
+		_, ok := configs.m[s.ID()]
+		return !ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// sessions without a provider use the daemon environment
+	s = newTestSession(ctx, t, sm, nil)
+	defer s.Close()
+	require.Nil(t, GetConfig(ctx, sm, session.NewGroup(s.ID())))
+
+	// errors of the session are not fatal
+	s = newTestSession(ctx, t, sm, &countingProvider{err: errors.New("broken")})
+	defer s.Close()
+	require.Nil(t, GetConfig(ctx, sm, session.NewGroup(s.ID())))
+}
+
+func newTestSession(ctx context.Context, t *testing.T, sm *session.Manager, a session.Attachable) *session.Session {
+	s, err := session.NewSession(ctx, "foo", "bar")
+	require.NoError(t, err)
+	if a != nil {
+		s.Allow(a)
+	}
+	go s.Run(ctx, session.Dialer(testutil.TestStream(testutil.Handler(sm.HandleConn))))
+	return s
+}
+
+type countingProvider struct {
+	cfg   *GetProxyConfigResponse
+	err   error
+	calls int32
+}
+
+func (p *countingProvider) Register(server *grpc.Server) {
+	RegisterProxyServer(server, p)
+}
+
+func (p *countingProvider) GetProxyConfig(ctx context.Context, req *GetProxyConfigRequest) (*GetProxyConfigResponse, error) {
+	atomic.AddInt32(&p.calls, 1)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.cfg, nil
+}
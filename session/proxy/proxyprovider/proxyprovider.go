@@ -0,0 +1,51 @@
+package proxyprovider
+
+import (
+	"context"
+	"os"
+
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/proxy"
+	"google.golang.org/grpc"
+)
+
+// NewProxyProvider returns a session attachable that sets the proxy of the
+// image, HTTP and Git sources of the build
+func NewProxyProvider(cfg proxy.Config) session.Attachable {
+	return &proxyProvider{cfg: cfg}
+}
+
+// FromEnvironment returns a proxy provider with the proxy environment
+// variables of the client
+func FromEnvironment() session.Attachable {
+	return NewProxyProvider(proxy.Config{
+		HTTPProxy:  getEnvAny("HTTP_PROXY", "http_proxy"),
+		HTTPSProxy: getEnvAny("HTTPS_PROXY", "https_proxy"),
+		NoProxy:    getEnvAny("NO_PROXY", "no_proxy"),
+	})
+}
+
+type proxyProvider struct {
+	cfg proxy.Config
+}
+
+func (pp *proxyProvider) Register(server *grpc.Server) {
+	proxy.RegisterProxyServer(server, pp)
+}
+
+func (pp *proxyProvider) GetProxyConfig(ctx context.Context, req *proxy.GetProxyConfigRequest) (*proxy.GetProxyConfigResponse, error) {
+	return &proxy.GetProxyConfigResponse{
+		HTTPProxy:  pp.cfg.HTTPProxy,
+		HTTPSProxy: pp.cfg.HTTPSProxy,
+		NoProxy:    pp.cfg.NoProxy,
+	}, nil
+}
+
+func getEnvAny(names ...string) string {
+	for _, n := range names {
+		if val := os.Getenv(n); val != "" {
+			return val
+		}
+	}
+	return ""
+}
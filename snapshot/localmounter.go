@@ -23,10 +23,17 @@ func LocalMounterWithMounts(mounts []mount.Mount) Mounter {
 	return &localMounter{mounts: mounts}
 }
 
+// LocalMounterWithMountsInDir is like LocalMounterWithMounts but creates the
+// temporary path in dir instead of the default directory for temporary files
+func LocalMounterWithMountsInDir(dir string, mounts []mount.Mount) Mounter {
+	return &localMounter{mounts: mounts, tmpDir: dir}
+}
+
 type localMounter struct {
 	mu        sync.Mutex
 	mounts    []mount.Mount
 	mountable Mountable
 	target    string
 	release   func() error
+	tmpDir    string
 }
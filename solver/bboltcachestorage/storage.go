@@ -360,6 +360,43 @@ func (s *Store) WalkLinks(id string, link solver.CacheInfoLink, fn func(id strin
 	return nil
 }
 
+// WalkAllLinks calls fn for every link of the key with the given ID
+func (s *Store) WalkAllLinks(id string, fn func(link solver.CacheInfoLink, target string) error) error {
+	var links []solver.CacheInfoLink
+	var targets []string
+	if err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(linksBucket))
+		if b == nil {
+			return nil
+		}
+		b = b.Bucket([]byte(id))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			parts := bytes.SplitN(k, []byte("@"), 2)
+			if v == nil || len(parts) != 2 {
+				return nil
+			}
+			var l solver.CacheInfoLink
+			if err := json.Unmarshal(parts[0], &l); err != nil {
+				return err
+			}
+			links = append(links, l)
+			targets = append(targets, string(parts[1]))
+			return nil
+		})
+	}); err != nil {
+		return err
+	}
+	for i := range links {
+		if err := fn(links[i], targets[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *Store) HasLink(id string, link solver.CacheInfoLink, target string) bool {
 	var v bool
 	if err := s.db.View(func(tx *bolt.Tx) error {
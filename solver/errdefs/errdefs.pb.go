@@ -360,6 +360,90 @@ func (m *ContentCache) GetIndex() int64 {
 	return 0
 }
 
+type FrontendOptions struct {
+	Invalid              []*FrontendOption `protobuf:"bytes,1,rep,name=invalid,proto3" json:"invalid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *FrontendOptions) Reset()         { *m = FrontendOptions{} }
+func (m *FrontendOptions) String() string { return proto.CompactTextString(m) }
+func (*FrontendOptions) ProtoMessage()    {}
+func (*FrontendOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_689dc58a5060aff5, []int{7}
+}
+func (m *FrontendOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FrontendOptions.Unmarshal(m, b)
+}
+func (m *FrontendOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FrontendOptions.Marshal(b, m, deterministic)
+}
+func (m *FrontendOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FrontendOptions.Merge(m, src)
+}
+func (m *FrontendOptions) XXX_Size() int {
+	return xxx_messageInfo_FrontendOptions.Size(m)
+}
+func (m *FrontendOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_FrontendOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FrontendOptions proto.InternalMessageInfo
+
+func (m *FrontendOptions) GetInvalid() []*FrontendOption {
+	if m != nil {
+		return m.Invalid
+	}
+	return nil
+}
+
+type FrontendOption struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Reason               string   `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FrontendOption) Reset()         { *m = FrontendOption{} }
+func (m *FrontendOption) String() string { return proto.CompactTextString(m) }
+func (*FrontendOption) ProtoMessage()    {}
+func (*FrontendOption) Descriptor() ([]byte, []int) {
+	return fileDescriptor_689dc58a5060aff5, []int{8}
+}
+func (m *FrontendOption) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FrontendOption.Unmarshal(m, b)
+}
+func (m *FrontendOption) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FrontendOption.Marshal(b, m, deterministic)
+}
+func (m *FrontendOption) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FrontendOption.Merge(m, src)
+}
+func (m *FrontendOption) XXX_Size() int {
+	return xxx_messageInfo_FrontendOption.Size(m)
+}
+func (m *FrontendOption) XXX_DiscardUnknown() {
+	xxx_messageInfo_FrontendOption.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FrontendOption proto.InternalMessageInfo
+
+func (m *FrontendOption) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *FrontendOption) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Vertex)(nil), "errdefs.Vertex")
 	proto.RegisterType((*Source)(nil), "errdefs.Source")
@@ -368,32 +452,37 @@ func init() {
 	proto.RegisterType((*Solve)(nil), "errdefs.Solve")
 	proto.RegisterType((*FileAction)(nil), "errdefs.FileAction")
 	proto.RegisterType((*ContentCache)(nil), "errdefs.ContentCache")
+	proto.RegisterType((*FrontendOptions)(nil), "errdefs.FrontendOptions")
+	proto.RegisterType((*FrontendOption)(nil), "errdefs.FrontendOption")
 }
 
 func init() { proto.RegisterFile("errdefs.proto", fileDescriptor_689dc58a5060aff5) }
 
 var fileDescriptor_689dc58a5060aff5 = []byte{
-	// 348 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0xcd, 0x8e, 0xd3, 0x30,
-	0x14, 0x85, 0x27, 0xbf, 0x43, 0x6e, 0x81, 0x85, 0x81, 0x51, 0x34, 0xab, 0x8c, 0xc5, 0xa2, 0x48,
-	0x90, 0x48, 0xc3, 0x13, 0x40, 0xd1, 0x68, 0x66, 0x55, 0xc9, 0x95, 0xd8, 0xc7, 0xc9, 0x4d, 0x6b,
-	0x48, 0x6c, 0xe3, 0xd8, 0xa8, 0xbc, 0x1b, 0x0f, 0x87, 0xe2, 0xa4, 0x65, 0x16, 0xdd, 0xe5, 0xe4,
-	0xfb, 0x7c, 0xed, 0x63, 0xc3, 0x2b, 0x34, 0xa6, 0xc5, 0x6e, 0x2c, 0xb5, 0x51, 0x56, 0x91, 0xeb,
-	0x25, 0xde, 0x7e, 0xdc, 0x0b, 0x7b, 0x70, 0xbc, 0x6c, 0xd4, 0x50, 0x0d, 0x8a, 0xff, 0xa9, 0xb8,
-	0x13, 0x7d, 0xfb, 0x53, 0xd8, 0x6a, 0x54, 0xfd, 0x6f, 0x34, 0x95, 0xe6, 0x95, 0xd2, 0xcb, 0x32,
-	0x5a, 0x40, 0xfa, 0x1d, 0x8d, 0xc5, 0x23, 0xb9, 0x81, 0xb4, 0x15, 0x7b, 0x1c, 0x6d, 0x1e, 0x14,
-	0xc1, 0x3a, 0x63, 0x4b, 0xa2, 0x5b, 0x48, 0x77, 0xca, 0x99, 0x06, 0x09, 0x85, 0x58, 0xc8, 0x4e,
-	0x79, 0xbe, 0xba, 0x7f, 0x5d, 0x6a, 0x5e, 0xce, 0xe4, 0x49, 0x76, 0x8a, 0x79, 0x46, 0xee, 0x20,
-	0x35, 0xb5, 0xdc, 0xe3, 0x98, 0x87, 0x45, 0xb4, 0x5e, 0xdd, 0x67, 0x93, 0xc5, 0xa6, 0x3f, 0x6c,
-	0x01, 0xf4, 0x0e, 0x56, 0x0f, 0x46, 0x49, 0x8b, 0xb2, 0xdd, 0xd4, 0x9a, 0x10, 0x88, 0x65, 0x3d,
-	0xe0, 0xb2, 0xab, 0xff, 0xa6, 0x05, 0xc0, 0xce, 0x71, 0x83, 0xbf, 0x1c, 0x8e, 0xf6, 0xa2, 0xf1,
-	0x37, 0x80, 0x64, 0x37, 0xf5, 0x21, 0xb7, 0xf0, 0x42, 0x48, 0xed, 0xec, 0xd3, 0xb7, 0x31, 0x0f,
-	0x8a, 0x68, 0x9d, 0xb1, 0x73, 0x9e, 0xd8, 0xa0, 0x9c, 0xf4, 0x2c, 0x9c, 0xd9, 0x29, 0x93, 0x1b,
-	0x08, 0x95, 0xce, 0x23, 0xdf, 0x25, 0x9d, 0x4e, 0xb9, 0xd5, 0x2c, 0x54, 0x9a, 0x7c, 0x80, 0xb8,
-	0x13, 0x3d, 0xe6, 0xb1, 0x27, 0x6f, 0xca, 0xd3, 0x35, 0x3f, 0x88, 0x1e, 0xbf, 0x34, 0x56, 0x28,
-	0xf9, 0x78, 0xc5, 0xbc, 0x42, 0x3e, 0x41, 0xd2, 0xd4, 0xcd, 0x01, 0xf3, 0xc4, 0xbb, 0xef, 0xce,
-	0xee, 0xc6, 0xd7, 0xb3, 0x9b, 0x09, 0x3e, 0x5e, 0xb1, 0xd9, 0xfa, 0x9a, 0xc1, 0xf5, 0xe8, 0xf8,
-	0x0f, 0x6c, 0x2c, 0xa5, 0x00, 0xff, 0xe7, 0x91, 0xb7, 0x90, 0x08, 0xd9, 0xe2, 0xd1, 0x37, 0x8c,
-	0xd8, 0x1c, 0xe8, 0x7b, 0x78, 0xf9, 0x7c, 0xce, 0x65, 0x8b, 0xa7, 0xfe, 0x1d, 0x3f, 0xff, 0x0b,
-	0x00, 0x00, 0xff, 0xff, 0x1e, 0xfa, 0x9c, 0x6f, 0x0f, 0x02, 0x00, 0x00,
+	// 397 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x92, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0x86, 0x1b, 0x27, 0x71, 0xc8, 0x04, 0x8a, 0xb4, 0x40, 0xb1, 0x7a, 0x72, 0x57, 0x1c, 0x82,
+	0x04, 0xb6, 0x08, 0x57, 0x2e, 0x90, 0xaa, 0x6a, 0x4f, 0x91, 0x36, 0x12, 0x77, 0xaf, 0x3d, 0x49,
+	0x17, 0x9c, 0xdd, 0x65, 0x3f, 0xaa, 0xf2, 0xdf, 0xf8, 0x71, 0x68, 0xd7, 0x76, 0x4b, 0xa5, 0x70,
+	0xcb, 0x9b, 0xe7, 0xf1, 0xd8, 0xef, 0x68, 0xe0, 0x05, 0x1a, 0xd3, 0xe0, 0xce, 0x16, 0xda, 0x28,
+	0xa7, 0xc8, 0xac, 0x8f, 0xe7, 0x1f, 0xf6, 0xc2, 0xdd, 0x7a, 0x5e, 0xd4, 0xea, 0x50, 0x1e, 0x14,
+	0xff, 0x5d, 0x72, 0x2f, 0xda, 0xe6, 0xa7, 0x70, 0xa5, 0x55, 0xed, 0x1d, 0x9a, 0x52, 0xf3, 0x52,
+	0xe9, 0xfe, 0x31, 0x9a, 0x43, 0xfa, 0x1d, 0x8d, 0xc3, 0x7b, 0x72, 0x06, 0x69, 0x23, 0xf6, 0x68,
+	0x5d, 0x36, 0xca, 0x47, 0xcb, 0x39, 0xeb, 0x13, 0xdd, 0x40, 0xba, 0x55, 0xde, 0xd4, 0x48, 0x28,
+	0x4c, 0x84, 0xdc, 0xa9, 0xc8, 0x17, 0xab, 0xd3, 0x42, 0xf3, 0xa2, 0x23, 0x37, 0x72, 0xa7, 0x58,
+	0x64, 0xe4, 0x02, 0x52, 0x53, 0xc9, 0x3d, 0xda, 0x2c, 0xc9, 0xc7, 0xcb, 0xc5, 0x6a, 0x1e, 0x2c,
+	0x16, 0xfe, 0x61, 0x3d, 0xa0, 0x17, 0xb0, 0xb8, 0x32, 0x4a, 0x3a, 0x94, 0xcd, 0xba, 0xd2, 0x84,
+	0xc0, 0x44, 0x56, 0x07, 0xec, 0xdf, 0x1a, 0x7f, 0xd3, 0x1c, 0x60, 0xeb, 0xb9, 0xc1, 0x5f, 0x1e,
+	0xad, 0x3b, 0x6a, 0xfc, 0x19, 0xc1, 0x74, 0x1b, 0xfa, 0x90, 0x73, 0x78, 0x26, 0xa4, 0xf6, 0xee,
+	0xe6, 0xd2, 0x66, 0xa3, 0x7c, 0xbc, 0x9c, 0xb3, 0x87, 0x1c, 0xd8, 0x41, 0x79, 0x19, 0x59, 0xd2,
+	0xb1, 0x21, 0x93, 0x33, 0x48, 0x94, 0xce, 0xc6, 0xb1, 0x4b, 0x1a, 0xbe, 0x72, 0xa3, 0x59, 0xa2,
+	0x34, 0x79, 0x0f, 0x93, 0x9d, 0x68, 0x31, 0x9b, 0x44, 0xf2, 0xaa, 0x18, 0xd6, 0x7c, 0x25, 0x5a,
+	0xfc, 0x5a, 0x3b, 0xa1, 0xe4, 0xf5, 0x09, 0x8b, 0x0a, 0xf9, 0x08, 0xd3, 0xba, 0xaa, 0x6f, 0x31,
+	0x9b, 0x46, 0xf7, 0xcd, 0x83, 0xbb, 0x8e, 0xf5, 0xdc, 0x3a, 0xc0, 0xeb, 0x13, 0xd6, 0x59, 0xdf,
+	0xe6, 0x30, 0xb3, 0x9e, 0xff, 0xc0, 0xda, 0x51, 0x0a, 0xf0, 0x38, 0x8f, 0xbc, 0x86, 0xa9, 0x90,
+	0x0d, 0xde, 0xc7, 0x86, 0x63, 0xd6, 0x05, 0xfa, 0x0e, 0x9e, 0xff, 0x3b, 0xe7, 0x3f, 0xd6, 0x25,
+	0xbc, 0x1c, 0xb6, 0xb9, 0xd1, 0x61, 0x9a, 0x25, 0x9f, 0x60, 0x26, 0xe4, 0x5d, 0xd5, 0x8a, 0x26,
+	0x2e, 0x64, 0xb1, 0x7a, 0xfb, 0x58, 0xe2, 0x89, 0xca, 0x06, 0x8f, 0x7e, 0x81, 0xd3, 0xa7, 0xe8,
+	0xd8, 0xd2, 0xc3, 0x89, 0x18, 0xac, 0xac, 0x92, 0x59, 0xd2, 0x9d, 0x48, 0x97, 0x78, 0x1a, 0x6f,
+	0xe9, 0xf3, 0xdf, 0x01, 0x00, 0x46, 0x5f, 0x1b, 0x94, 0x93, 0x02, 0x00, 0x00,
 }
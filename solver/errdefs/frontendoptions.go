@@ -0,0 +1,48 @@
+package errdefs
+
+import (
+	fmt "fmt"
+	"strings"
+
+	"github.com/containerd/typeurl"
+	"github.com/moby/buildkit/util/grpcerrors"
+)
+
+func init() {
+	typeurl.Register((*FrontendOptions)(nil), "github.com/moby/buildkit", "errdefs.FrontendOptions+json")
+}
+
+// InvalidFrontendOptionsError is returned when the options of a build don't
+// match the options schema of the frontend
+type InvalidFrontendOptionsError struct {
+	FrontendOptions
+	error
+}
+
+func (e *InvalidFrontendOptionsError) Error() string {
+	reasons := make([]string, 0, len(e.Invalid))
+	for _, o := range e.Invalid {
+		reasons = append(reasons, fmt.Sprintf("%s: %s", o.Name, o.Reason))
+	}
+	msg := fmt.Sprintf("invalid frontend options: %s", strings.Join(reasons, "; "))
+	if e.error != nil {
+		msg += ": " + e.error.Error()
+	}
+	return msg
+}
+
+func (e *InvalidFrontendOptionsError) Unwrap() error {
+	return e.error
+}
+
+func (e *InvalidFrontendOptionsError) ToProto() grpcerrors.TypedErrorProto {
+	return &e.FrontendOptions
+}
+
+func NewInvalidFrontendOptionsError(invalid []*FrontendOption) error {
+	return &InvalidFrontendOptionsError{FrontendOptions: FrontendOptions{Invalid: invalid}}
+}
+
+func (v *FrontendOptions) WrapError(err error) error {
+	return &InvalidFrontendOptionsError{error: err, FrontendOptions: *v}
+}
@@ -5,17 +5,21 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/solver/errdefs"
+	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/buildkit/util/flightcontrol"
+	"github.com/moby/buildkit/util/grpcerrors"
 	"github.com/moby/buildkit/util/progress"
 	"github.com/moby/buildkit/util/tracing"
 	digest "github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
 )
 
 // ResolveOpFunc finds an Op implementation for a Vertex
@@ -237,6 +241,15 @@ type Job struct {
 
 	progressCloser func()
 	SessionID      string
+	// Frontend is the frontend of the build of the job, empty for LLB
+	// definitions
+	Frontend string
+
+	// cancel cancels the context of the build of the job, nil if the job
+	// has no active build
+	cancel    context.CancelFunc
+	cancelled bool
+	startedAt time.Time
 }
 
 type SolverOpt struct {
@@ -331,6 +344,10 @@ func (jl *Solver) loadUnlocked(v, parent Vertex, j *Job, cache map[Vertex]Vertex
 	}
 
 	dgst := v.Digest()
+	if ns := v.Options().CacheNamespace; ns != "" {
+		// vertexes in different namespaces never share state
+		dgst = namespacedDigest(dgst, ns)
+	}
 
 	dgstWithoutCache := digest.FromBytes([]byte(fmt.Sprintf("%s-ignorecache", dgst)))
 
@@ -384,12 +401,17 @@ func (jl *Solver) loadUnlocked(v, parent Vertex, j *Job, cache map[Vertex]Vertex
 		}
 	}
 
+	var attached *sharedOp
 	if j != nil {
 		if _, ok := st.jobs[j]; !ok {
 			st.jobs[j] = struct{}{}
+			attached = st.op
 		}
 	}
 	st.mu.Unlock()
+	if attached != nil {
+		attached.attachJob(j)
+	}
 
 	if parent != nil {
 		if _, ok := st.parents[parent.Digest()]; !ok {
@@ -454,6 +476,45 @@ func (jl *Solver) NewJob(id string) (*Job, error) {
 	return j, nil
 }
 
+// Cancel cancels the build of the active job id and reports the cancellation
+// in the status stream of the job
+func (jl *Solver) Cancel(id string) error {
+	jl.mu.Lock()
+	defer jl.mu.Unlock()
+
+	j, ok := jl.jobs[id]
+	if !ok || j.cancel == nil {
+		return grpcerrors.WrapCode(errors.Errorf("no active build %s", id), codes.NotFound)
+	}
+	now := time.Now()
+	v := client.Vertex{
+		Digest:    digest.FromString("cancel:" + id),
+		Name:      "build cancelled",
+		Started:   &now,
+		Completed: &now,
+		Error:     "build cancelled by request",
+	}
+	j.pw.Write(v.Digest.String(), v)
+	j.cancel()
+	j.cancel = nil
+	j.cancelled = true
+	return nil
+}
+
+// ActiveJobs returns the jobs with an active build that wasn't cancelled
+func (jl *Solver) ActiveJobs() []*Job {
+	jl.mu.RLock()
+	defer jl.mu.RUnlock()
+
+	var out []*Job
+	for _, j := range jl.jobs {
+		if j.cancel != nil {
+			out = append(out, j)
+		}
+	}
+	return out
+}
+
 func (jl *Solver) Get(id string) (*Job, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
@@ -514,6 +575,7 @@ func (j *Job) Discard() error {
 	j.list.mu.Lock()
 	defer j.list.mu.Unlock()
 
+	j.cancel = nil
 	j.pw.Close()
 
 	for k, st := range j.list.actives {
@@ -538,6 +600,37 @@ func (j *Job) Discard() error {
 	return nil
 }
 
+// WithCancel returns a context for the build of the job that is cancelled by
+// Solver.Cancel
+func (j *Job) WithCancel(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	j.list.mu.Lock()
+	j.cancel = cancel
+	j.startedAt = time.Now()
+	j.list.mu.Unlock()
+	return ctx, cancel
+}
+
+// ID returns the ID of the job
+func (j *Job) ID() string {
+	return j.id
+}
+
+// StartedAt returns the time the build of the job started
+func (j *Job) StartedAt() time.Time {
+	j.list.mu.RLock()
+	defer j.list.mu.RUnlock()
+	return j.startedAt
+}
+
+// Cancelled returns true if the build of the job was cancelled by
+// Solver.Cancel
+func (j *Job) Cancelled() bool {
+	j.list.mu.RLock()
+	defer j.list.mu.RUnlock()
+	return j.cancelled
+}
+
 func (j *Job) InContext(ctx context.Context, f func(context.Context, session.Group) error) error {
 	return f(progress.WithProgress(ctx, j.pw), session.NewGroup(j.SessionID))
 }
@@ -592,6 +685,7 @@ type sharedOp struct {
 	op         Op
 	subBuilder *subBuilder
 	err        error
+	resolved   uint32 // set once op is resolved
 
 	execRes *execRes
 	execErr error
@@ -737,6 +831,13 @@ func (s *sharedOp) CacheMap(ctx context.Context, index int) (resp *cacheMapResp,
 			}()
 		}
 		res, done, err := op.CacheMap(ctx, s.st, len(s.cacheRes))
+		if err == nil {
+			if ns := s.st.vtx.Options().CacheNamespace; ns != "" {
+				cm := *res
+				cm.Digest = namespacedDigest(cm.Digest, ns)
+				res = &cm
+			}
+		}
 		complete := true
 		if err != nil {
 			select {
@@ -793,6 +894,7 @@ func (s *sharedOp) Exec(ctx context.Context, inputs []Result) (outputs []Result,
 			ctx = trace.ContextWithSpan(ctx, s.st.mspan)
 		}
 		ctx = withAncestorCacheOpts(ctx, s.st)
+		ctx = context.WithValue(ctx, exitCodeKey{}, &s.st.clientVertex)
 
 		// no cache hit. start evaluating the node
 		span, ctx := tracing.StartSpan(ctx, s.st.vtx.Name())
@@ -841,6 +943,9 @@ func (s *sharedOp) getOp() (Op, error) {
 	s.opOnce.Do(func() {
 		s.subBuilder = s.st.builder()
 		s.op, s.err = s.resolver(s.st.vtx, s.subBuilder)
+		if s.err == nil {
+			atomic.StoreUint32(&s.resolved, 1)
+		}
 	})
 	if s.err != nil {
 		return nil, s.err
@@ -848,6 +953,17 @@ func (s *sharedOp) getOp() (Op, error) {
 	return s.op, nil
 }
 
+// attachJob passes a job that loaded the vertex to the op if it was already
+// resolved
+func (s *sharedOp) attachJob(j *Job) {
+	if atomic.LoadUint32(&s.resolved) == 0 {
+		return
+	}
+	if ja, ok := s.op.(JobAttacher); ok {
+		ja.AttachJob(j)
+	}
+}
+
 func (s *sharedOp) release() {
 	if s.execRes != nil {
 		for _, r := range s.execRes.execRes {
@@ -862,12 +978,29 @@ func initClientVertex(v Vertex) client.Vertex {
 		inputDigests = append(inputDigests, inp.Vertex.Digest())
 	}
 	return client.Vertex{
-		Inputs: inputDigests,
-		Name:   v.Name(),
-		Digest: v.Digest(),
+		Inputs:        inputDigests,
+		Name:          v.Name(),
+		Digest:        v.Digest(),
+		ProgressGroup: v.Options().ProgressGroup,
+		Labels:        buildLabels(v.Options().Description),
 	}
 }
 
+// buildLabels returns the build labels in the description of a vertex
+func buildLabels(desc map[string]string) map[string]string {
+	var labels map[string]string
+	for k, v := range desc {
+		if !strings.HasPrefix(k, pb.BuildLabelPrefix) {
+			continue
+		}
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[strings.TrimPrefix(k, pb.BuildLabelPrefix)] = v
+	}
+	return labels
+}
+
 func wrapShared(inp []Result) []*SharedResult {
 	out := make([]*SharedResult, len(inp))
 	for i, r := range inp {
@@ -905,6 +1038,7 @@ func notifyStarted(ctx context.Context, v *client.Vertex, cached bool) {
 	v.Started = &now
 	v.Completed = nil
 	v.Cached = cached
+	v.ExitCode = 0
 	pw.Write(v.Digest.String(), *v)
 }
 
@@ -923,6 +1057,16 @@ func notifyCompleted(ctx context.Context, v *client.Vertex, err error, cached bo
 	pw.Write(v.Digest.String(), *v)
 }
 
+type exitCodeKey struct{}
+
+// SetExitCode records the exit code of the process run by the op executing
+// with ctx in the status of its vertex
+func SetExitCode(ctx context.Context, code int) {
+	if v, ok := ctx.Value(exitCodeKey{}).(*client.Vertex); ok {
+		v.ExitCode = code
+	}
+}
+
 type SlowCacheError struct {
 	error
 	Index  Index
@@ -959,3 +1103,7 @@ func releaseError(err error) {
 	}
 	releaseError(errors.Unwrap(err))
 }
+
+func namespacedDigest(dgst digest.Digest, ns string) digest.Digest {
+	return digest.FromBytes([]byte(fmt.Sprintf("%s-ns:%s", dgst, ns)))
+}
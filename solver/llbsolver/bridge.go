@@ -10,6 +10,7 @@ import (
 	"github.com/containerd/containerd/platforms"
 	"github.com/mitchellh/hashstructure"
 	"github.com/moby/buildkit/cache/remotecache"
+	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/client/llb"
 	"github.com/moby/buildkit/frontend"
 	gw "github.com/moby/buildkit/frontend/gateway/client"
@@ -45,6 +46,26 @@ func (b *llbBridge) loadResult(ctx context.Context, def *pb.Definition, cacheImp
 	if err != nil {
 		return nil, err
 	}
+	ns, err := loadCacheNamespace(b.builder)
+	if err != nil {
+		return nil, err
+	}
+	checkpointID, err := loadCheckpointID(b.builder)
+	if err != nil {
+		return nil, err
+	}
+	imagePolicy, err := loadImagePolicy(b.builder)
+	if err != nil {
+		return nil, err
+	}
+	caBundle, err := loadCABundle(b.builder)
+	if err != nil {
+		return nil, err
+	}
+	proxyConfig, err := loadProxyConfig(b.builder)
+	if err != nil {
+		return nil, err
+	}
 	var cms []solver.CacheManager
 	for _, im := range cacheImports {
 		cmID, err := cmKey(im)
@@ -84,7 +105,7 @@ func (b *llbBridge) loadResult(ctx context.Context, def *pb.Definition, cacheImp
 	}
 	dpc := &detectPrunedCacheID{}
 
-	edge, err := Load(def, dpc.Load, ValidateEntitlements(ent), WithCacheSources(cms), NormalizeRuntimePlatforms(), WithValidateCaps())
+	edge, err := Load(def, dpc.Load, ValidateEntitlements(ent), WithCacheSources(cms), WithCacheNamespace(ns), WithCheckpointID(checkpointID), WithImagePolicy(imagePolicy), WithCABundle(caBundle), WithProxyConfig(proxyConfig), NormalizeRuntimePlatforms(), WithValidateCaps())
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to load LLB")
 	}
@@ -257,6 +278,37 @@ func (rp *resultProxy) Result(ctx context.Context) (res solver.CachedResult, err
 	return nil, err
 }
 
+func (b *llbBridge) MetaSet(ctx context.Context, key string, value []byte) error {
+	s, err := loadMetadataStore(b.builder)
+	if err != nil {
+		return err
+	}
+	s.set(key, value)
+	return nil
+}
+
+func (b *llbBridge) MetaGet(ctx context.Context, key string) ([]byte, bool, error) {
+	s, err := loadMetadataStore(b.builder)
+	if err != nil {
+		return nil, false, err
+	}
+	v, ok := s.get(key)
+	return v, ok, nil
+}
+
+func (b *llbBridge) Warn(ctx context.Context, dgst digest.Digest, msg string, opts frontend.WarnOpts) error {
+	return addWarning(ctx, b.builder, client.Warning{
+		Code:       opts.Code,
+		Level:      opts.Level,
+		Message:    msg,
+		Detail:     opts.Detail,
+		URL:        opts.URL,
+		Vertex:     dgst,
+		SourceInfo: opts.SourceInfo,
+		Ranges:     opts.Range,
+	})
+}
+
 func (b *llbBridge) ResolveImageConfig(ctx context.Context, ref string, opt llb.ResolveImageConfigOpt) (dgst digest.Digest, config []byte, err error) {
 	w, err := b.resolveWorker()
 	if err != nil {
@@ -0,0 +1,36 @@
+package llbsolver
+
+import (
+	"strings"
+
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/source"
+)
+
+// WithCABundle isolates the image, HTTP and Git sources of a build with a CA
+// bundle from the builds with other bundles, so that the sources shared by
+// builds are fetched with a bundle that all of them provide
+func WithCABundle(dgst string) LoadOpt {
+	return func(op *pb.Op, _ *pb.OpMetadata, opt *solver.VertexOptions) error {
+		if dgst != "" && isRemoteSource(op) {
+			opt.CacheNamespace += "/cabundle:" + dgst
+		}
+		return nil
+	}
+}
+
+// isRemoteSource returns true for the image, HTTP and Git sources, which
+// fetch their content with the CA bundle and proxy configuration of the build
+func isRemoteSource(op *pb.Op) bool {
+	src := op.GetSource()
+	if src == nil {
+		return false
+	}
+	for _, scheme := range []string{source.DockerImageScheme, source.HTTPScheme, source.HTTPSScheme, source.GitScheme} {
+		if strings.HasPrefix(src.Identifier, scheme+"://") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package llbsolver
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCABundle(t *testing.T) {
+	t.Parallel()
+
+	dgst := digest.FromBytes([]byte("bundle")).String()
+	for _, id := range []string{
+		"docker-image://docker.io/library/busybox:latest",
+		"https://example.com/foo",
+		"http://example.com/foo",
+		"git://github.com/moby/buildkit",
+	} {
+		opt := solver.VertexOptions{CacheNamespace: "ns"}
+		op := &pb.Op{Op: &pb.Op_Source{Source: &pb.SourceOp{Identifier: id}}}
+		require.NoError(t, WithCABundle(dgst)(op, nil, &opt))
+		require.Equal(t, "ns/cabundle:"+dgst, opt.CacheNamespace, id)
+
+		// builds without a bundle are not affected
+		opt = solver.VertexOptions{}
+		require.NoError(t, WithCABundle("")(op, nil, &opt))
+		require.Equal(t, "", opt.CacheNamespace, id)
+	}
+
+	// other vertexes are not affected
+	opt := solver.VertexOptions{}
+	local := &pb.Op{Op: &pb.Op_Source{Source: &pb.SourceOp{Identifier: "local://context"}}}
+	require.NoError(t, WithCABundle(dgst)(local, nil, &opt))
+	require.NoError(t, WithCABundle(dgst)(&pb.Op{Op: &pb.Op_Exec{Exec: &pb.ExecOp{}}}, nil, &opt))
+	require.Equal(t, "", opt.CacheNamespace)
+
+	s := solver.NewSolver(solver.SolverOpt{DefaultCache: solver.NewInMemoryCacheManager()})
+	defer s.Close()
+	j, err := s.NewJob("job1")
+	require.NoError(t, err)
+	defer j.Discard()
+
+	v, err := loadCABundle(j)
+	require.NoError(t, err)
+	require.Equal(t, "", v)
+	j.SetValue(keyCABundle, dgst)
+	v, err = loadCABundle(j)
+	require.NoError(t, err)
+	require.Equal(t, dgst, v)
+}
@@ -0,0 +1,32 @@
+package llbsolver
+
+import (
+	"context"
+
+	"github.com/moby/buildkit/solver"
+)
+
+type cacheMountStatsKey struct{}
+
+// withCacheMountStats enables the cache mount stats of the exec running with
+// ctx if one of the builds sharing the vertex requested them
+func withCacheMountStats(ctx context.Context, b solver.Builder) context.Context {
+	var enabled bool
+	b.EachValue(ctx, keyCacheMountStats, func(v interface{}) error {
+		if v, ok := v.(bool); ok && v {
+			enabled = true
+		}
+		return nil
+	})
+	if !enabled {
+		return ctx
+	}
+	return context.WithValue(ctx, cacheMountStatsKey{}, true)
+}
+
+// CacheMountStats returns true if the exec running with ctx reports the stats
+// of its cache mounts
+func CacheMountStats(ctx context.Context) bool {
+	v, _ := ctx.Value(cacheMountStatsKey{}).(bool)
+	return v
+}
@@ -0,0 +1,26 @@
+package llbsolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/buildkit/solver"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCacheMountStats(t *testing.T) {
+	t.Parallel()
+
+	s := solver.NewSolver(solver.SolverOpt{DefaultCache: solver.NewInMemoryCacheManager()})
+	defer s.Close()
+
+	j, err := s.NewJob("job1")
+	require.NoError(t, err)
+	defer j.Discard()
+
+	require.False(t, CacheMountStats(context.TODO()))
+	require.False(t, CacheMountStats(withCacheMountStats(context.TODO(), j)))
+
+	j.SetValue(keyCacheMountStats, true)
+	require.True(t, CacheMountStats(withCacheMountStats(context.TODO(), j)))
+}
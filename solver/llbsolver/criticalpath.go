@@ -0,0 +1,139 @@
+package llbsolver
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/moby/buildkit/solver"
+)
+
+// setCriticalPath sets the length of the longest chain of vertexes from each
+// vertex of the graph to the target e, counting the vertex itself. Vertexes
+// with a longer chain are on the critical path of the build.
+func setCriticalPath(e solver.Edge) {
+	var order []*vertex
+	seen := map[*vertex]struct{}{}
+	var visit func(v *vertex)
+	visit = func(v *vertex) {
+		if _, ok := seen[v]; ok {
+			return
+		}
+		seen[v] = struct{}{}
+		for _, in := range v.inputs {
+			if iv, ok := in.Vertex.(*vertex); ok {
+				visit(iv)
+			}
+		}
+		order = append(order, v)
+	}
+	root, ok := e.Vertex.(*vertex)
+	if !ok {
+		return
+	}
+	visit(root)
+
+	// the reverse of the post order visits every vertex after the vertexes
+	// that depend on it
+	for i := len(order) - 1; i >= 0; i-- {
+		v := order[i]
+		if v.options.CriticalPath == 0 {
+			v.options.CriticalPath = 1
+		}
+		for _, in := range v.inputs {
+			if iv, ok := in.Vertex.(*vertex); ok && iv.options.CriticalPath < v.options.CriticalPath+1 {
+				iv.options.CriticalPath = v.options.CriticalPath + 1
+			}
+		}
+	}
+}
+
+// prioritySemaphore is a counting semaphore that wakes up the waiter with the
+// highest priority first. Waiters with the same priority are woken up in the
+// order they started waiting. A size of zero doesn't limit the holders.
+type prioritySemaphore struct {
+	mu      sync.Mutex
+	size    int
+	cur     int
+	waiters []*semWaiter
+}
+
+type semWaiter struct {
+	priority int
+	ready    chan struct{}
+}
+
+func newPrioritySemaphore(n int) *prioritySemaphore {
+	return &prioritySemaphore{size: n}
+}
+
+func (s *prioritySemaphore) Acquire(ctx context.Context, priority int) error {
+	s.mu.Lock()
+	if s.available() && len(s.waiters) == 0 {
+		s.cur++
+		s.mu.Unlock()
+		return nil
+	}
+	w := &semWaiter{priority: priority, ready: make(chan struct{})}
+	i := sort.Search(len(s.waiters), func(i int) bool {
+		return s.waiters[i].priority < priority
+	})
+	s.waiters = append(s.waiters, nil)
+	copy(s.waiters[i+1:], s.waiters[i:])
+	s.waiters[i] = w
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		select {
+		case <-w.ready:
+			// acquired while being canceled
+			s.cur--
+			s.notify()
+		default:
+			for i, w2 := range s.waiters {
+				if w2 == w {
+					s.waiters = append(s.waiters[:i], s.waiters[i+1:]...)
+					break
+				}
+			}
+		}
+		return ctx.Err()
+	}
+}
+
+func (s *prioritySemaphore) Release() {
+	s.mu.Lock()
+	s.cur--
+	s.notify()
+	s.mu.Unlock()
+}
+
+// resize changes the size of the semaphore and returns the previous size. The
+// holders are not affected by a smaller size, the waiters wait until there
+// are fewer holders than the new size.
+func (s *prioritySemaphore) resize(n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.size
+	s.size = n
+	s.notify()
+	return prev
+}
+
+func (s *prioritySemaphore) available() bool {
+	return s.size <= 0 || s.cur < s.size
+}
+
+func (s *prioritySemaphore) notify() {
+	for s.available() && len(s.waiters) > 0 {
+		w := s.waiters[0]
+		s.waiters = s.waiters[1:]
+		s.cur++
+		close(w.ready)
+	}
+}
@@ -0,0 +1,143 @@
+package llbsolver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/client/llb"
+	"github.com/moby/buildkit/solver"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetCriticalPath(t *testing.T) {
+	t.Parallel()
+
+	base := llb.Image("busybox")
+	long := base.Run(llb.Shlex("step1")).Root().
+		Run(llb.Shlex("step2")).Root().
+		Run(llb.Shlex("step3")).Root()
+	short := base.Run(llb.Shlex("short")).Root()
+	st := llb.Scratch().
+		File(llb.Copy(long, "/out", "/long")).
+		File(llb.Copy(short, "/out", "/short"))
+
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	e, err := Load(def.ToPB())
+	require.NoError(t, err)
+
+	paths := map[string]int{}
+	var walk func(v solver.Vertex)
+	walk = func(v solver.Vertex) {
+		paths[v.Name()] = v.Options().CriticalPath
+		for _, in := range v.Inputs() {
+			walk(in.Vertex)
+		}
+	}
+	walk(e.Vertex)
+
+	require.Equal(t, 5, paths["step1"])
+	require.Equal(t, 4, paths["step2"])
+	require.Equal(t, 2, paths["short"])
+	// the base is on the longest chain
+	require.Equal(t, 6, paths["docker-image://docker.io/library/busybox:latest"])
+}
+
+func TestPrioritySemaphore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	s := newPrioritySemaphore(1)
+	require.NoError(t, s.Acquire(ctx, 0))
+
+	waitQueued := func(n int) {
+		for {
+			s.mu.Lock()
+			l := len(s.waiters)
+			s.mu.Unlock()
+			if l == n {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	order := make(chan int, 2)
+	for i, p := range []int{1, 3} {
+		p := p
+		go func() {
+			if err := s.Acquire(ctx, p); err == nil {
+				order <- p
+				s.Release()
+			}
+		}()
+		waitQueued(i + 1)
+	}
+
+	// canceled waiters don't acquire the semaphore
+	cctx, cancel := context.WithCancel(ctx)
+	errCh := make(chan error)
+	go func() {
+		errCh <- s.Acquire(cctx, 5)
+	}()
+	waitQueued(3)
+	cancel()
+	require.Error(t, <-errCh)
+
+	s.Release()
+	require.Equal(t, 3, <-order)
+	require.Equal(t, 1, <-order)
+}
+
+func TestPrioritySemaphoreResize(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	s := newPrioritySemaphore(2)
+	require.NoError(t, s.Acquire(ctx, 0))
+	require.NoError(t, s.Acquire(ctx, 0))
+
+	acquired := make(chan struct{}, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			if err := s.Acquire(ctx, 0); err == nil {
+				acquired <- struct{}{}
+			}
+		}()
+	}
+	waitState := func(cur, waiters int) {
+		for {
+			s.mu.Lock()
+			c, w := s.cur, len(s.waiters)
+			s.mu.Unlock()
+			if c == cur && w == waiters {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	waitState(2, 3)
+
+	// the holders keep the semaphore when it shrinks, the waiters wait until
+	// there are fewer holders than the new size
+	require.Equal(t, 2, s.resize(1))
+	s.Release()
+	waitState(1, 3)
+	s.Release()
+	<-acquired
+	waitState(1, 2)
+
+	// growing wakes up the waiters
+	require.Equal(t, 1, s.resize(2))
+	<-acquired
+	waitState(2, 1)
+
+	// zero removes the limit
+	require.Equal(t, 2, s.resize(0))
+	<-acquired
+	waitState(3, 0)
+	require.NoError(t, s.Acquire(ctx, 0))
+	waitState(4, 0)
+}
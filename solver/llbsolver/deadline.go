@@ -0,0 +1,67 @@
+package llbsolver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/util/grpcerrors"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+)
+
+// buildProgress tracks the vertexes of a job to report how far the build got
+// when its deadline is exceeded
+type buildProgress struct {
+	mu       sync.Mutex
+	vertexes map[digest.Digest]*client.Vertex
+}
+
+func newBuildProgress(j *solver.Job) *buildProgress {
+	p := &buildProgress{vertexes: map[digest.Digest]*client.Vertex{}}
+	ch := make(chan *client.SolveStatus)
+	// the status stream ends when the job is discarded
+	go j.Status(context.TODO(), ch)
+	go func() {
+		for ss := range ch {
+			p.mu.Lock()
+			for _, v := range ss.Vertexes {
+				p.vertexes[v.Digest] = v
+			}
+			p.mu.Unlock()
+		}
+	}()
+	return p
+}
+
+// deadlineError returns the error of a build that was cancelled because its
+// deadline was exceeded, with the number of completed steps and the names of
+// the steps that were still running
+func (p *buildProgress) deadlineError(err error, d time.Duration) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var completed int
+	var running []string
+	for _, v := range p.vertexes {
+		switch {
+		case v.Completed != nil && v.Error == "":
+			completed++
+		case v.Started != nil:
+			running = append(running, v.Name)
+		}
+	}
+	sort.Strings(running)
+
+	msg := fmt.Sprintf("build deadline exceeded after %s: %d of %d steps completed", d.Round(time.Millisecond), completed, len(p.vertexes))
+	if len(running) > 0 {
+		msg += fmt.Sprintf(", running: %s", strings.Join(running, ", "))
+	}
+	return grpcerrors.WrapCode(errors.Wrap(err, msg), codes.DeadlineExceeded)
+}
@@ -0,0 +1,31 @@
+package llbsolver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/util/grpcerrors"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestDeadlineError(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	p := &buildProgress{vertexes: map[digest.Digest]*client.Vertex{
+		digest.FromString("a"): {Name: "pull", Started: &now, Completed: &now},
+		digest.FromString("b"): {Name: "cached", Started: &now, Completed: &now, Cached: true},
+		digest.FromString("c"): {Name: "run tests", Started: &now},
+		digest.FromString("d"): {Name: "export"},
+	}}
+
+	err := p.deadlineError(errors.WithStack(context.DeadlineExceeded), 90*time.Second)
+	require.Equal(t, codes.DeadlineExceeded, grpcerrors.Code(err))
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	require.Contains(t, err.Error(), "build deadline exceeded after 1m30s: 2 of 4 steps completed, running: run tests")
+}
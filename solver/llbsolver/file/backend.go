@@ -185,12 +185,28 @@ func docopy(ctx context.Context, src, dest string, action pb.FileActionCopy, u *
 		return err
 	}
 
+	utime := timestampToTime(action.Timestamp)
+	if action.TimestampNow {
+		now := time.Now()
+		utime = &now
+	}
+
+	dirMode := os.FileMode(0755)
+	if action.DirMode != 0 {
+		dirMode = os.FileMode(action.DirMode) & 0777
+		// create the parent directories before the copy does with the
+		// default mode
+		if err := mkdirDestPath(dest, destPath, dirMode, ch, utime); err != nil {
+			return err
+		}
+	}
+
 	opt := []copy.Opt{
 		func(ci *copy.CopyInfo) {
 			ci.IncludePatterns = action.IncludePatterns
 			ci.ExcludePatterns = action.ExcludePatterns
 			ci.Chown = ch
-			ci.Utime = timestampToTime(action.Timestamp)
+			ci.Utime = utime
 			if m := int(action.Mode); m != -1 {
 				ci.Mode = &m
 			}
@@ -202,7 +218,7 @@ func docopy(ctx context.Context, src, dest string, action pb.FileActionCopy, u *
 
 	if !action.AllowWildcard {
 		if action.AttemptUnpackDockerCompatibility {
-			if ok, err := unpack(ctx, src, srcPath, dest, destPath, ch, timestampToTime(action.Timestamp)); err != nil {
+			if ok, err := unpack(ctx, src, srcPath, dest, destPath, ch, utime, dirMode); err != nil {
 				return err
 			} else if ok {
 				return nil
@@ -225,7 +241,7 @@ func docopy(ctx context.Context, src, dest string, action pb.FileActionCopy, u *
 
 	for _, s := range m {
 		if action.AttemptUnpackDockerCompatibility {
-			if ok, err := unpack(ctx, src, s, dest, destPath, ch, timestampToTime(action.Timestamp)); err != nil {
+			if ok, err := unpack(ctx, src, s, dest, destPath, ch, utime, dirMode); err != nil {
 				return err
 			} else if ok {
 				continue
@@ -239,6 +255,19 @@ func docopy(ctx context.Context, src, dest string, action pb.FileActionCopy, u *
 	return nil
 }
 
+// mkdirDestPath creates the directories copy.Copy creates for destPath
+func mkdirDestPath(root, destPath string, mode os.FileMode, ch copy.Chowner, tm *time.Time) error {
+	p := destPath
+	if d, f := filepath.Split(destPath); f != "" && f != "." {
+		p = d
+	}
+	p, err := fs.RootPath(root, p)
+	if err != nil {
+		return err
+	}
+	return copy.MkdirAll(p, mode, ch, tm)
+}
+
 func cleanPath(s string) string {
 	s2 := filepath.Join("/", s)
 	if strings.HasSuffix(s, "/.") {
@@ -0,0 +1,53 @@
+package llbsolver
+
+import (
+	"context"
+
+	"github.com/moby/buildkit/cache/contenthash"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/solver"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// withHashConcurrency returns a copy of cm that computes the content based
+// cache keys of the inputs with the hash concurrency requested by the builds
+// sharing the vertex
+func withHashConcurrency(b solver.Builder, cm *solver.CacheMap) *solver.CacheMap {
+	cm2 := *cm
+	cm2.Deps = append(cm.Deps[:0:0], cm.Deps...)
+	for i, dep := range cm2.Deps {
+		if f := dep.ComputeDigestFunc; f != nil {
+			cm2.Deps[i].ComputeDigestFunc = func(ctx context.Context, res solver.Result, s session.Group) (digest.Digest, error) {
+				n, err := loadHashConcurrency(b)
+				if err != nil {
+					return "", err
+				}
+				if n > 1 {
+					ctx = contenthash.WithHashConcurrency(ctx, n)
+				}
+				return f(ctx, res, s)
+			}
+		}
+	}
+	return &cm2
+}
+
+// loadHashConcurrency returns the highest hash concurrency of the builds
+func loadHashConcurrency(b solver.Builder) (int, error) {
+	var max int
+	err := b.EachValue(context.TODO(), keyHashConcurrency, func(v interface{}) error {
+		n, ok := v.(int)
+		if !ok {
+			return errors.Errorf("invalid hash concurrency %T", v)
+		}
+		if n > max {
+			max = n
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return max, nil
+}
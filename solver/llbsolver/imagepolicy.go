@@ -0,0 +1,48 @@
+package llbsolver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/source"
+	"github.com/moby/buildkit/util/imagepolicy"
+)
+
+// WithImagePolicy isolates the image sources of a build with an image policy
+// from the builds with other policies, so that their images are always
+// verified with the policy of the build
+func WithImagePolicy(p *imagepolicy.Policy) LoadOpt {
+	return func(op *pb.Op, _ *pb.OpMetadata, opt *solver.VertexOptions) error {
+		if !p.Enabled() {
+			return nil
+		}
+		if src := op.GetSource(); src != nil && strings.HasPrefix(src.Identifier, source.DockerImageScheme+"://") {
+			opt.CacheNamespace += "/imagepolicy:" + p.Digest().String()
+		}
+		return nil
+	}
+}
+
+// withImagePolicy returns a context with the image policy of the builds
+// sharing the vertex. The image sources of builds with different policies
+// don't share vertexes.
+func withImagePolicy(ctx context.Context, b solver.Builder) context.Context {
+	p, err := loadImagePolicy(b)
+	if err != nil {
+		return ctx
+	}
+	return imagepolicy.WithPolicy(ctx, p)
+}
+
+func loadImagePolicy(b solver.Builder) (*imagepolicy.Policy, error) {
+	var p *imagepolicy.Policy
+	err := b.EachValue(context.TODO(), keyImagePolicy, func(v interface{}) error {
+		if pp, ok := v.(*imagepolicy.Policy); ok {
+			p = pp
+		}
+		return nil
+	})
+	return p, err
+}
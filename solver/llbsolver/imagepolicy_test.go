@@ -0,0 +1,44 @@
+package llbsolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/imagepolicy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithImagePolicy(t *testing.T) {
+	t.Parallel()
+
+	p, err := imagepolicy.NewFromPEM([]imagepolicy.PEMRule{{Images: "docker.io/library/*", Mode: imagepolicy.ModeNone}})
+	require.NoError(t, err)
+	image := &pb.Op{Op: &pb.Op_Source{Source: &pb.SourceOp{Identifier: "docker-image://docker.io/library/busybox:latest"}}}
+	git := &pb.Op{Op: &pb.Op_Source{Source: &pb.SourceOp{Identifier: "git://github.com/moby/buildkit"}}}
+
+	opt := solver.VertexOptions{CacheNamespace: "ns"}
+	require.NoError(t, WithImagePolicy(p)(image, nil, &opt))
+	require.Equal(t, "ns/imagepolicy:"+p.Digest().String(), opt.CacheNamespace)
+
+	// other vertexes are not affected
+	opt = solver.VertexOptions{}
+	require.NoError(t, WithImagePolicy(p)(git, nil, &opt))
+	require.NoError(t, WithImagePolicy(p)(&pb.Op{Op: &pb.Op_Exec{Exec: &pb.ExecOp{}}}, nil, &opt))
+	require.Equal(t, "", opt.CacheNamespace)
+
+	// builds without a policy are not affected
+	require.NoError(t, WithImagePolicy(nil)(image, nil, &opt))
+	require.Equal(t, "", opt.CacheNamespace)
+
+	s := solver.NewSolver(solver.SolverOpt{DefaultCache: solver.NewInMemoryCacheManager()})
+	defer s.Close()
+	j, err := s.NewJob("job1")
+	require.NoError(t, err)
+	defer j.Discard()
+
+	require.Nil(t, imagepolicy.FromContext(withImagePolicy(context.TODO(), j)))
+	j.SetValue(keyImagePolicy, p)
+	require.Equal(t, p, imagepolicy.FromContext(withImagePolicy(context.TODO(), j)))
+}
@@ -0,0 +1,59 @@
+package llbsolver
+
+import (
+	"context"
+	"sync"
+
+	"github.com/moby/buildkit/solver"
+	"github.com/pkg/errors"
+)
+
+const keyMetadataStore = "llb.metadatastore"
+
+// metadataStore is the key-value store shared by the frontends of a build.
+// It is a value of the job of the build and is released with it.
+type metadataStore struct {
+	mu sync.Mutex
+	m  map[string][]byte
+}
+
+func newMetadataStore() *metadataStore {
+	return &metadataStore{m: map[string][]byte{}}
+}
+
+func (s *metadataStore) set(key string, value []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[key] = append([]byte(nil), value...)
+}
+
+func (s *metadataStore) get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.m[key]
+	if !ok {
+		return nil, false
+	}
+	return append([]byte(nil), v...), true
+}
+
+// loadMetadataStore returns the metadata store of the build. Vertexes shared
+// by multiple builds have no store to not leak metadata between the builds.
+func loadMetadataStore(b solver.Builder) (*metadataStore, error) {
+	var stores []*metadataStore
+	err := b.EachValue(context.TODO(), keyMetadataStore, func(v interface{}) error {
+		s, ok := v.(*metadataStore)
+		if !ok {
+			return errors.Errorf("invalid metadata store %T", v)
+		}
+		stores = append(stores, s)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(stores) != 1 {
+		return nil, errors.New("build metadata is not available")
+	}
+	return stores[0], nil
+}
@@ -6,9 +6,11 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/containerd/containerd/errdefs"
 	"github.com/containerd/containerd/mount"
 	"github.com/containerd/containerd/pkg/userns"
 	"github.com/docker/docker/pkg/idtools"
@@ -29,13 +31,14 @@ import (
 	"google.golang.org/grpc/codes"
 )
 
-func NewMountManager(name string, cm cache.Manager, sm *session.Manager, md *metadata.Store) *MountManager {
+func NewMountManager(name string, cm cache.Manager, sm *session.Manager, md *metadata.Store, hostPaths map[string]string) *MountManager {
 	return &MountManager{
 		cm:          cm,
 		sm:          sm,
 		cacheMounts: map[string]*cacheRefShare{},
 		md:          md,
 		managerName: name,
+		hostPaths:   hostPaths,
 	}
 }
 
@@ -46,6 +49,7 @@ type MountManager struct {
 	cacheMounts   map[string]*cacheRefShare
 	md            *metadata.Store
 	managerName   string
+	hostPaths     map[string]string
 }
 
 func (mm *MountManager) getRefCacheDir(ctx context.Context, ref cache.ImmutableRef, id string, m *pb.Mount, sharing pb.CacheSharingOpt, s session.Group) (mref cache.MutableRef, err error) {
@@ -107,7 +111,7 @@ func (g *cacheRefGetter) getRefCacheDir(ctx context.Context, ref cache.Immutable
 
 func (g *cacheRefGetter) getRefCacheDirNoCache(ctx context.Context, key string, ref cache.ImmutableRef, id string, block bool) (cache.MutableRef, error) {
 	makeMutable := func(ref cache.ImmutableRef) (cache.MutableRef, error) {
-		return g.cm.New(ctx, ref, g.session, cache.WithRecordType(client.UsageRecordTypeCacheMount), cache.WithDescription(g.name), cache.CachePolicyRetain)
+		return g.cm.New(ctx, ref, g.session, cache.WithRecordType(client.UsageRecordTypeCacheMount), cache.WithDescription(g.name), cache.WithCacheMountID(id), cache.CachePolicyRetain)
 	}
 
 	cacheRefsLocker.Lock(key)
@@ -382,6 +386,47 @@ func (mm *MountManager) MountableCache(ctx context.Context, m *pb.Mount, ref cac
 	return mm.getRefCacheDir(ctx, ref, m.CacheOpt.ID, m, m.CacheOpt.Sharing, g)
 }
 
+// GetCacheMountRef returns a ref to the mutable ref backing the cache mount
+// with the given id. The ref is shared with the builds using the cache mount
+// with the shared sharing mode, so they keep using the same cache while it is
+// held instead of getting an empty one.
+func GetCacheMountRef(ctx context.Context, cm cache.Manager, md *metadata.Store, id string) (cache.MutableRef, error) {
+	return getCacheMountRef(ctx, cm, md, sharedCacheRefs, id)
+}
+
+func getCacheMountRef(ctx context.Context, cm cache.Manager, md *metadata.Store, shared *cacheRefs, id string) (cache.MutableRef, error) {
+	key := "cache-dir:" + id
+	sis, err := md.Search(key)
+	if err != nil {
+		return nil, err
+	}
+	locked := false
+	for _, si := range sis {
+		for _, k := range si.Indexes() {
+			if k != key && !strings.HasPrefix(k, key+":") {
+				continue
+			}
+			siID := si.ID()
+			mRef, err := shared.get(k, func() (cache.MutableRef, error) {
+				cacheRefsLocker.Lock(k)
+				defer cacheRefsLocker.Unlock(k)
+				return cm.GetMutable(ctx, siID)
+			})
+			if err == nil {
+				return mRef, nil
+			}
+			if errors.Is(err, cache.ErrLocked) {
+				locked = true
+			}
+			break
+		}
+	}
+	if locked {
+		return nil, errors.Errorf("cache mount %s is in use by a build that does not share it", id)
+	}
+	return nil, errors.Wrapf(errdefs.ErrNotFound, "cache mount %s", id)
+}
+
 func (mm *MountManager) MountableTmpFS() cache.Mountable {
 	return newTmpfs(mm.cm.IdentityMapping())
 }
@@ -394,6 +439,56 @@ func (mm *MountManager) MountableSSH(ctx context.Context, m *pb.Mount, g session
 	return mm.getSSHMountable(ctx, m, g)
 }
 
+// MountableHostPath returns a bind mount of the host path that the daemon
+// configuration allows under the name requested by the mount.
+func (mm *MountManager) MountableHostPath(m *pb.Mount) (cache.Mountable, error) {
+	if m.HostPathOpt == nil {
+		return nil, errors.Errorf("missing host path mount options")
+	}
+	p, ok := mm.hostPaths[m.HostPathOpt.Name]
+	if !ok {
+		return nil, errors.Errorf("host path %q is not allowed by the daemon configuration", m.HostPathOpt.Name)
+	}
+	return &hostPath{path: p, idmap: mm.cm.IdentityMapping()}, nil
+}
+
+// MountableHostFile returns a bind mount of a file of the host that is not
+// configured as a host path, like the zoneinfo file of a timezone
+func (mm *MountManager) MountableHostFile(p string) cache.Mountable {
+	return &hostPath{path: p, idmap: mm.cm.IdentityMapping()}
+}
+
+type hostPath struct {
+	path  string
+	idmap *idtools.IdentityMapping
+}
+
+func (h *hostPath) Mount(ctx context.Context, readonly bool, g session.Group) (snapshot.Mountable, error) {
+	return &hostPathMount{path: h.path, readonly: readonly, idmap: h.idmap}, nil
+}
+
+type hostPathMount struct {
+	path     string
+	readonly bool
+	idmap    *idtools.IdentityMapping
+}
+
+func (m *hostPathMount) Mount() ([]mount.Mount, func() error, error) {
+	opt := []string{"rbind"}
+	if m.readonly {
+		opt = append(opt, "ro")
+	}
+	return []mount.Mount{{
+		Type:    "bind",
+		Source:  m.path,
+		Options: opt,
+	}}, func() error { return nil }, nil
+}
+
+func (m *hostPathMount) IdentityMapping() *idtools.IdentityMapping {
+	return m.idmap
+}
+
 func newTmpfs(idmap *idtools.IdentityMapping) cache.Mountable {
 	return &tmpfs{idmap: idmap}
 }
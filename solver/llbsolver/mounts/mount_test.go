@@ -12,6 +12,7 @@ import (
 	"github.com/containerd/containerd/content"
 	"github.com/containerd/containerd/content/local"
 	"github.com/containerd/containerd/diff/apply"
+	"github.com/containerd/containerd/errdefs"
 	"github.com/containerd/containerd/leases"
 	ctdmetadata "github.com/containerd/containerd/metadata"
 	"github.com/containerd/containerd/namespaces"
@@ -385,3 +386,77 @@ func TestCacheMountSharedRefsDeadlock(t *testing.T) {
 		require.FailNow(t, "deadlock on releasing while getting new ref")
 	}
 }
+
+func TestGetCacheMountRef(t *testing.T) {
+	t.Parallel()
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+
+	tmpdir, err := ioutil.TempDir("", "cachemanager")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	snapshotter, err := native.NewSnapshotter(filepath.Join(tmpdir, "snapshots"))
+	require.NoError(t, err)
+
+	co, cleanup, err := newCacheManager(ctx, cmOpt{
+		snapshotter:     snapshotter,
+		snapshotterName: "native",
+	})
+	require.NoError(t, err)
+
+	defer cleanup()
+
+	shared := &cacheRefs{}
+
+	_, err = getCacheMountRef(ctx, co.manager, co.md, shared, "foo")
+	require.Error(t, err)
+	require.True(t, errdefs.IsNotFound(err))
+
+	g1 := newRefGetter(co.manager, co.md, shared)
+	ref, err := g1.getRefCacheDir(ctx, nil, "foo", pb.CacheSharingOpt_SHARED)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(cacheMountPath(ctx, t, ref), "data"), []byte("cached"), 0644))
+
+	// the cache used by a running build is shared with the inspection
+	iref, err := getCacheMountRef(ctx, co.manager, co.md, shared, "foo")
+	require.NoError(t, err)
+	require.Equal(t, ref.ID(), iref.ID())
+	dt, err := ioutil.ReadFile(filepath.Join(cacheMountPath(ctx, t, iref), "data"))
+	require.NoError(t, err)
+	require.Equal(t, "cached", string(dt))
+
+	require.NoError(t, ref.Release(context.TODO()))
+
+	// builds started during the inspection get the same cache instead of an
+	// empty one
+	g2 := newRefGetter(co.manager, co.md, shared)
+	ref2, err := g2.getRefCacheDir(ctx, nil, "foo", pb.CacheSharingOpt_SHARED)
+	require.NoError(t, err)
+	require.Equal(t, iref.ID(), ref2.ID())
+
+	require.NoError(t, iref.Release(context.TODO()))
+	require.NoError(t, ref2.Release(context.TODO()))
+
+	// a build holding the cache exclusively makes the inspection fail instead
+	// of waiting
+	g3 := newRefGetter(co.manager, co.md, shared)
+	ref3, err := g3.getRefCacheDir(ctx, nil, "foo", pb.CacheSharingOpt_LOCKED)
+	require.NoError(t, err)
+	_, err = getCacheMountRef(ctx, co.manager, co.md, shared, "foo")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "in use")
+	require.NoError(t, ref3.Release(context.TODO()))
+}
+
+func cacheMountPath(ctx context.Context, t *testing.T, ref cache.MutableRef) string {
+	mountable, err := ref.Mount(ctx, false, nil)
+	require.NoError(t, err)
+	mnts, release, err := mountable.Mount()
+	require.NoError(t, err)
+	if release != nil {
+		defer release()
+	}
+	require.Len(t, mnts, 1)
+	require.Equal(t, "bind", mnts[0].Type)
+	return mnts[0].Source
+}
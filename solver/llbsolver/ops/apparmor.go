@@ -0,0 +1,65 @@
+package ops
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const apparmorUnconfined = "unconfined"
+
+// apparmorProfilesPath lists the apparmor profiles loaded in the kernel
+const apparmorProfilesPath = "/sys/kernel/security/apparmor/profiles"
+
+// validateApparmorProfile checks that the apparmor profile of an exec can be
+// applied. The profile must be allowed by the daemon and, unless it is the
+// unconfined profile, loaded on the host. Profiles in complain mode don't
+// confine the process and are only allowed if the unconfined profile is.
+func validateApparmorProfile(name string, allowed []string, profilesPath string) error {
+	if !stringSliceContains(allowed, name) {
+		return errors.Errorf("apparmor profile %q is not allowed by the daemon configuration", name)
+	}
+	if name == apparmorUnconfined {
+		return nil
+	}
+	f, err := os.Open(profilesPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errors.Errorf("apparmor profile %q can't be applied: apparmor is not enabled on the host", name)
+		}
+		return errors.Wrap(err, "failed to read loaded apparmor profiles")
+	}
+	defer f.Close()
+
+	// lines have the format "<name> (<mode>)"
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := s.Text()
+		var mode string
+		if i := strings.LastIndex(line, " ("); i >= 0 {
+			line, mode = line[:i], strings.TrimSuffix(line[i+2:], ")")
+		}
+		if line != name {
+			continue
+		}
+		if mode == "complain" && !stringSliceContains(allowed, apparmorUnconfined) {
+			return errors.Errorf("apparmor profile %q is in complain mode, which requires the unconfined profile to be allowed by the daemon configuration", name)
+		}
+		return nil
+	}
+	if err := s.Err(); err != nil {
+		return errors.Wrap(err, "failed to read loaded apparmor profiles")
+	}
+	return errors.Errorf("apparmor profile %q is not loaded on the host", name)
+}
+
+func stringSliceContains(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
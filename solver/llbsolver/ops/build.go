@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"os"
+	"strings"
 
 	"github.com/containerd/continuity/fs"
 	"github.com/moby/buildkit/client/llb"
@@ -38,25 +39,55 @@ func NewBuildOp(v solver.Vertex, op *pb.Op_Build, b frontend.FrontendLLBBridge,
 }
 
 func (b *buildOp) CacheMap(ctx context.Context, g session.Group, index int) (*solver.CacheMap, bool, error) {
+	op := b.op
+	if op.Def != nil && op.Def.Source != nil {
+		// source maps don't change the result of the nested build
+		def := *op.Def
+		def.Source = nil
+		cp := *op
+		cp.Def = &def
+		op = &cp
+	}
 	dt, err := json.Marshal(struct {
-		Type string
-		Exec *pb.BuildOp
+		Type   string
+		Exec   *pb.BuildOp
+		Squash bool `json:",omitempty"`
 	}{
-		Type: buildCacheType,
-		Exec: b.op,
+		Type:   buildCacheType,
+		Exec:   op,
+		Squash: op.Def != nil,
 	})
 	if err != nil {
 		return nil, false, err
 	}
 
-	return &solver.CacheMap{
+	cm := &solver.CacheMap{
 		Digest: digest.FromBytes(dt),
 		Deps: make([]struct {
 			Selector          digest.Digest
 			ComputeDigestFunc solver.ResultBasedCacheFunc
 			PreprocessFunc    solver.PreprocessFunc
 		}, len(b.v.Inputs())),
-	}, true, nil
+	}
+	if op.Def != nil {
+		// the inputs of a squashed subgraph are its sources. The cache keys of
+		// the local sources don't change with their contents.
+		for i, inp := range b.v.Inputs() {
+			if isLocalSource(inp.Vertex) {
+				cm.Deps[i].ComputeDigestFunc = llbsolver.NewContentHashFunc(nil)
+			}
+		}
+	}
+	return cm, true, nil
+}
+
+func isLocalSource(v solver.Vertex) bool {
+	op, ok := v.Sys().(*pb.Op)
+	if !ok {
+		return false
+	}
+	src := op.GetSource()
+	return src != nil && strings.HasPrefix(src.Identifier, "local://")
 }
 
 func (b *buildOp) Exec(ctx context.Context, g session.Group, inputs []solver.Result) (outputs []solver.Result, retErr error) {
@@ -64,6 +95,10 @@ func (b *buildOp) Exec(ctx context.Context, g session.Group, inputs []solver.Res
 		return nil, errors.Errorf("only LLB builder is currently allowed")
 	}
 
+	if b.op.Def != nil {
+		return b.solve(ctx, g, b.op.Def)
+	}
+
 	builderInputs := b.op.Inputs
 	llbDef, ok := builderInputs[pb.LLBDefinitionInput]
 	if !ok {
@@ -123,8 +158,13 @@ func (b *buildOp) Exec(ctx context.Context, g session.Group, inputs []solver.Res
 	lm.Unmount()
 	lm = nil
 
+	return b.solve(ctx, g, def.ToPB())
+}
+
+// solve builds the nested definition and returns its result
+func (b *buildOp) solve(ctx context.Context, g session.Group, def *pb.Definition) ([]solver.Result, error) {
 	newRes, err := b.b.Solve(ctx, frontend.SolveRequest{
-		Definition: def.ToPB(),
+		Definition: def,
 	}, g.SessionIterator().NextSession())
 	if err != nil {
 		return nil, err
@@ -0,0 +1,50 @@
+package ops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/buildkit/client/llb"
+	"github.com/moby/buildkit/solver/llbsolver"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildOpSquashCacheMap(t *testing.T) {
+	t.Parallel()
+
+	sq := loadBuildOp(t, llb.Image("busybox").
+		File(llb.Copy(llb.Local("src"), "foo", "foo")).
+		Squash())
+	require.Equal(t, 2, len(sq.v.Inputs()))
+
+	cm, done, err := sq.CacheMap(context.TODO(), nil, 0)
+	require.NoError(t, err)
+	require.True(t, done)
+	require.Equal(t, 2, len(cm.Deps))
+	for i, inp := range sq.v.Inputs() {
+		src := inp.Vertex.Sys().(*pb.Op).GetSource()
+		require.NotNil(t, src)
+		// the contents of the local source are part of the cache key
+		if src.Identifier == "local://src" {
+			require.NotNil(t, cm.Deps[i].ComputeDigestFunc)
+		} else {
+			require.Nil(t, cm.Deps[i].ComputeDigestFunc)
+		}
+	}
+
+}
+
+func loadBuildOp(t *testing.T, st llb.State) *buildOp {
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+	e, err := llbsolver.Load(def.ToPB())
+	require.NoError(t, err)
+	op, ok := e.Vertex.Sys().(*pb.Op)
+	require.True(t, ok)
+	build, ok := op.Op.(*pb.Op_Build)
+	require.True(t, ok)
+	sop, err := NewBuildOp(e.Vertex, build, nil, nil)
+	require.NoError(t, err)
+	return sop.(*buildOp)
+}
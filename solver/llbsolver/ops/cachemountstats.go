@@ -0,0 +1,127 @@
+package ops
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/frontend/gateway"
+	"github.com/moby/buildkit/identity"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/solver/llbsolver"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/progress"
+	"github.com/sirupsen/logrus"
+)
+
+// cacheMountFiles are the regular files of a cache mount by path
+type cacheMountFiles map[string]cacheMountFile
+
+type cacheMountFile struct {
+	size  int64
+	mtime time.Time
+}
+
+// scanCacheMounts returns the files of the cache mounts of the process by
+// mount index if the build requested the stats of its cache mounts. Cache
+// mounts that can't be scanned have no stats.
+func (e *execOp) scanCacheMounts(ctx context.Context, g session.Group, actives []gateway.MountMutableRef) map[int]cacheMountFiles {
+	if !llbsolver.CacheMountStats(ctx) {
+		return nil
+	}
+	out := map[int]cacheMountFiles{}
+	for _, a := range actives {
+		if e.op.Mounts[a.MountIndex].MountType != pb.MountType_CACHE {
+			continue
+		}
+		files, err := scanCacheMount(ctx, a, g)
+		if err != nil {
+			logrus.Warnf("failed to scan cache mount %s: %v", e.op.Mounts[a.MountIndex].Dest, err)
+			continue
+		}
+		out[a.MountIndex] = files
+	}
+	return out
+}
+
+// writeCacheMountStats compares the files of the cache mounts with the files
+// before the process ran and writes the stats to the progress of the vertex
+func (e *execOp) writeCacheMountStats(ctx context.Context, g session.Group, actives []gateway.MountMutableRef, before map[int]cacheMountFiles) {
+	if len(before) == 0 {
+		return
+	}
+	pw, _, _ := progress.NewFromContext(ctx)
+	defer pw.Close()
+	for _, a := range actives {
+		prev, ok := before[a.MountIndex]
+		if !ok {
+			continue
+		}
+		m := e.op.Mounts[a.MountIndex]
+		files, err := scanCacheMount(ctx, a, g)
+		if err != nil {
+			logrus.Warnf("failed to scan cache mount %s: %v", m.Dest, err)
+			continue
+		}
+		unchanged, written := compareCacheMountFiles(prev, files)
+		var id string
+		if m.CacheOpt != nil {
+			id = m.CacheOpt.ID
+		}
+		pw.Write(identity.NewID(), client.CacheMountStats{
+			ID:             id,
+			Target:         m.Dest,
+			UnchangedBytes: unchanged,
+			WrittenBytes:   written,
+		})
+	}
+}
+
+func scanCacheMount(ctx context.Context, a gateway.MountMutableRef, g session.Group) (cacheMountFiles, error) {
+	mnt, err := a.Ref.Mount(ctx, true, g)
+	if err != nil {
+		return nil, err
+	}
+	lm := snapshot.LocalMounter(mnt)
+	dir, err := lm.Mount()
+	if err != nil {
+		return nil, err
+	}
+	defer lm.Unmount()
+
+	files := cacheMountFiles{}
+	err = filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		files[rel] = cacheMountFile{size: fi.Size(), mtime: fi.ModTime()}
+		return nil
+	})
+	return files, err
+}
+
+// compareCacheMountFiles returns the size of the files that are unchanged and
+// the size of the files that were created or modified
+func compareCacheMountFiles(before, after cacheMountFiles) (unchanged, written int64) {
+	for p, f := range after {
+		if prev, ok := before[p]; ok && prev.size == f.size && prev.mtime.Equal(f.mtime) {
+			unchanged += f.size
+		} else {
+			written += f.size
+		}
+	}
+	return unchanged, written
+}
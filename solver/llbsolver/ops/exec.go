@@ -5,23 +5,30 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"path"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/containerd/containerd/platforms"
 	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/cache/metadata"
+	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/executor"
 	"github.com/moby/buildkit/frontend/gateway"
+	gwerrdefs "github.com/moby/buildkit/frontend/gateway/errdefs"
 	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/secrets"
 	"github.com/moby/buildkit/solver"
 	"github.com/moby/buildkit/solver/llbsolver"
 	"github.com/moby/buildkit/solver/llbsolver/errdefs"
 	"github.com/moby/buildkit/solver/llbsolver/mounts"
 	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/entitlements"
+	"github.com/moby/buildkit/util/progress"
 	"github.com/moby/buildkit/util/progress/logs"
 	utilsystem "github.com/moby/buildkit/util/system"
 	"github.com/moby/buildkit/worker"
@@ -38,27 +45,34 @@ type execOp struct {
 	op          *pb.ExecOp
 	cm          cache.Manager
 	mm          *mounts.MountManager
+	sm          *session.Manager
 	exec        executor.Executor
 	w           worker.Worker
 	platform    *pb.Platform
 	numInputs   int
 	parallelism *semaphore.Weighted
+	// vtx and name are the digest and name of the vertex of the op
+	vtx  digest.Digest
+	name string
 }
 
 func NewExecOp(v solver.Vertex, op *pb.Op_Exec, platform *pb.Platform, cm cache.Manager, parallelism *semaphore.Weighted, sm *session.Manager, md *metadata.Store, exec executor.Executor, w worker.Worker) (solver.Op, error) {
 	if err := llbsolver.ValidateOp(&pb.Op{Op: op}); err != nil {
 		return nil, err
 	}
-	name := fmt.Sprintf("exec %s", strings.Join(op.Exec.Meta.Args, " "))
+	name := fmt.Sprintf("exec %s", strings.Join(op.Exec.Meta.ProcessArgs(), " "))
 	return &execOp{
 		op:          op.Exec,
-		mm:          mounts.NewMountManager(name, cm, sm, md),
+		mm:          mounts.NewMountManager(name, cm, sm, md, w.SecurityConfig().HostPaths),
+		sm:          sm,
 		cm:          cm,
 		exec:        exec,
 		numInputs:   len(v.Inputs()),
 		w:           w,
 		platform:    platform,
 		parallelism: parallelism,
+		vtx:         v.Digest(),
+		name:        v.Name(),
 	}, nil
 }
 
@@ -79,6 +93,22 @@ func cloneExecOp(old *pb.ExecOp) pb.ExecOp {
 	return n
 }
 
+// filterEnv returns the env without the variables named in keys
+func filterEnv(env []string, keys []string) []string {
+	out := make([]string, 0, len(env))
+loop:
+	for _, e := range env {
+		k := strings.SplitN(e, "=", 2)[0]
+		for _, key := range keys {
+			if k == key {
+				continue loop
+			}
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
 func (e *execOp) CacheMap(ctx context.Context, g session.Group, index int) (*solver.CacheMap, bool, error) {
 	op := cloneExecOp(e.op)
 	for i := range op.Meta.ExtraHosts {
@@ -90,6 +120,9 @@ func (e *execOp) CacheMap(ctx context.Context, g session.Group, index int) (*sol
 		op.Mounts[i].Selector = ""
 	}
 	op.Meta.ProxyEnv = nil
+	if len(op.Meta.CacheIgnoreEnv) > 0 {
+		op.Meta.Env = filterEnv(op.Meta.Env, op.Meta.CacheIgnoreEnv)
+	}
 
 	p := platforms.DefaultSpec()
 	if e.platform != nil {
@@ -148,6 +181,11 @@ func (e *execOp) CacheMap(ctx context.Context, g session.Group, index int) (*sol
 	}
 
 	for i, dep := range deps {
+		if dep.After {
+			// ordering only, the contents of the input don't affect the cache key
+			cm.Deps[i].ComputeDigestFunc = afterDigestFunc
+			continue
+		}
 		if len(dep.Selectors) != 0 {
 			dgsts := make([][]byte, 0, len(dep.Selectors))
 			for _, p := range dep.Selectors {
@@ -199,10 +237,12 @@ func toSelectors(p []string) []llbsolver.Selector {
 type dep struct {
 	Selectors          []string
 	NoContentBasedHash bool
+	After              bool
 }
 
 func (e *execOp) getMountDeps() ([]dep, error) {
 	deps := make([]dep, e.numInputs)
+	mounted := make([]bool, e.numInputs)
 	for _, m := range e.op.Mounts {
 		if m.Input == pb.Empty {
 			continue
@@ -210,6 +250,7 @@ func (e *execOp) getMountDeps() ([]dep, error) {
 		if int(m.Input) >= len(deps) {
 			return nil, errors.Errorf("invalid mountinput %v", m)
 		}
+		mounted[m.Input] = true
 
 		sel := m.Selector
 		if sel != "" {
@@ -221,9 +262,22 @@ func (e *execOp) getMountDeps() ([]dep, error) {
 			deps[m.Input].NoContentBasedHash = true
 		}
 	}
+	for _, i := range e.op.After {
+		if i < 0 || int(i) >= len(deps) {
+			return nil, errors.Errorf("invalid after input %d", i)
+		}
+		// inputs that are also mounted keep their content based cache keys
+		deps[i].After = !mounted[i]
+	}
 	return deps, nil
 }
 
+var afterDigest = digest.FromBytes([]byte("exec.after"))
+
+func afterDigestFunc(context.Context, solver.Result, session.Group) (digest.Digest, error) {
+	return afterDigest, nil
+}
+
 func addDefaultEnvvar(env []string, k, v string) []string {
 	for _, e := range env {
 		if strings.HasPrefix(e, k+"=") {
@@ -233,7 +287,34 @@ func addDefaultEnvvar(env []string, k, v string) []string {
 	return append(env, k+"="+v)
 }
 
+// passthroughEnv returns the env variables of the host with the given names
+// that are allowed, and the names that are not allowed. Allowed variables
+// that are not set on the host are skipped.
+func passthroughEnv(names, allowed []string, lookup func(string) (string, bool)) (env []string, ignored []string) {
+	for _, name := range names {
+		var ok bool
+		for _, a := range allowed {
+			if a == name {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			ignored = append(ignored, name)
+			continue
+		}
+		if v, ok := lookup(name); ok {
+			env = append(env, name+"="+v)
+		}
+	}
+	return env, ignored
+}
+
 func (e *execOp) Exec(ctx context.Context, g session.Group, inputs []solver.Result) (results []solver.Result, err error) {
+	if err := validateSecurityOpts(e.w.SecurityConfig(), e.op.ApparmorProfile, e.op.Seccomp, e.op.UserNSMapping, e.op.Sysctls, e.op.Network); err != nil {
+		return nil, err
+	}
+
 	refs := make([]*worker.WorkerRef, len(inputs))
 	for i, inp := range inputs {
 		var ok bool
@@ -241,10 +322,57 @@ func (e *execOp) Exec(ctx context.Context, g session.Group, inputs []solver.Resu
 		if !ok {
 			return nil, errors.Errorf("invalid reference for exec %T", inp.Sys())
 		}
+		// inputs produced by another worker need to be transferred first
+		ref, err := refs[i].ToWorker(ctx, e.w, g)
+		if err != nil {
+			return nil, err
+		}
+		if ref != refs[i] {
+			defer ref.ImmutableRef.Release(context.TODO())
+			refs[i] = ref
+		}
 	}
 
+	attempts := 1
+	if r := e.op.Retry; r != nil {
+		retries := int(r.Attempts)
+		if max := llbsolver.MaxRetries(ctx); retries > max {
+			llbsolver.Warn(ctx, client.Warning{
+				Code:    "RetriesLimited",
+				Message: fmt.Sprintf("%d retries were requested but the daemon allows at most %d", retries, max),
+			})
+			retries = max
+		}
+		attempts += retries
+	}
+	for attempt := 1; ; attempt++ {
+		if attempt == 1 {
+			results, err = e.run(ctx, g, inputs, refs)
+		} else {
+			actx, done := e.attemptProgress(ctx, attempt, attempts)
+			results, err = e.run(actx, g, inputs, refs)
+			done(err)
+		}
+		if err == nil || attempt >= attempts || ctx.Err() != nil {
+			return results, err
+		}
+		code, ok := retryExitCode(err, e.op.Retry.ExitCodes)
+		if !ok {
+			return results, err
+		}
+		// the next attempt starts again from the inputs
+		var ee *errdefs.ExecError
+		if errors.As(err, &ee) {
+			ee.Release()
+		}
+		logRetry(ctx, fmt.Sprintf("process exited with code %d, retrying (attempt %d/%d)\n", code, attempt+1, attempts))
+	}
+}
+
+// run runs the process of the op once with new mounts of the inputs
+func (e *execOp) run(ctx context.Context, g session.Group, inputs []solver.Result, refs []*worker.WorkerRef) (results []solver.Result, err error) {
 	p, err := gateway.PrepareMounts(ctx, e.mm, e.cm, g, e.op.Meta.Cwd, e.op.Mounts, refs, func(m *pb.Mount, ref cache.ImmutableRef) (cache.MutableRef, error) {
-		desc := fmt.Sprintf("mount %s from exec %s", m.Dest, strings.Join(e.op.Meta.Args, " "))
+		desc := fmt.Sprintf("mount %s from exec %s", m.Dest, strings.Join(e.op.Meta.ProcessArgs(), " "))
 		return e.cm.New(ctx, ref, g, cache.WithDescription(desc))
 	})
 	defer func() {
@@ -290,56 +418,69 @@ func (e *execOp) Exec(ctx context.Context, g session.Group, inputs []solver.Resu
 		return nil, err
 	}
 
-	extraHosts, err := parseExtraHosts(e.op.Meta.ExtraHosts)
-	if err != nil {
-		return nil, err
-	}
+	cacheFiles := e.scanCacheMounts(ctx, g, p.Actives)
 
-	emu, err := getEmulator(e.platform, e.cm.IdentityMapping())
-	if err == nil && emu != nil {
-		e.op.Meta.Args = append([]string{qemuMountName}, e.op.Meta.Args...)
+	stdout, stderr := logs.NewLogStreams(ctx, os.Getenv("BUILDKIT_DEBUG_EXEC_OUTPUT") == "1")
+	defer stdout.Close()
+	defer stderr.Close()
 
-		p.Mounts = append(p.Mounts, executor.Mount{
-			Readonly: true,
-			Src:      emu,
-			Dest:     qemuMountName,
-		})
-	}
+	meta, mounts, err := e.processMeta(ctx, g, e.op.Meta, e.op.Secretenv, stderr)
 	if err != nil {
-		logrus.Warn(err.Error()) // TODO: remove this with pull support
+		return nil, err
 	}
+	meta.ReadonlyRootFS = p.ReadonlyRootFS
+	meta.Resources = e.op.Resources
+	p.Mounts = append(p.Mounts, mounts...)
 
-	meta := executor.Meta{
-		Args:           e.op.Meta.Args,
-		Env:            e.op.Meta.Env,
-		Cwd:            e.op.Meta.Cwd,
-		User:           e.op.Meta.User,
-		Hostname:       e.op.Meta.Hostname,
-		ReadonlyRootFS: p.ReadonlyRootFS,
-		ExtraHosts:     extraHosts,
-		NetMode:        e.op.Network,
-		SecurityMode:   e.op.Security,
+	procStdout, procStderr := stdout, stderr
+	var redirect *outputRedirect
+	if e.op.Meta.RedirectStdout != "" || e.op.Meta.RedirectStderr != "" {
+		redirect, err = newOutputRedirect(e.op.Meta, stdout, stderr)
+		if err != nil {
+			return nil, err
+		}
+		defer redirect.Close()
+		procStdout, procStderr = redirect.stdout, redirect.stderr
 	}
 
-	if e.op.Meta.ProxyEnv != nil {
-		meta.Env = append(meta.Env, proxyEnvList(e.op.Meta.ProxyEnv)...)
-	}
-	var currentOS string
-	if e.platform != nil {
-		currentOS = e.platform.OS
+	var sharedPID *sharedPIDProcess
+	if e.op.SharedPID != nil {
+		sharedPID, err = e.startSharedPID(ctx, g, e.op.SharedPID, refs, stdout, stderr)
+		if err != nil {
+			return nil, err
+		}
+		defer sharedPID.stop()
+		meta.SharedPID = sharedPID.ns
 	}
-	meta.Env = addDefaultEnvvar(meta.Env, "PATH", utilsystem.DefaultPathEnv(currentOS))
-
-	stdout, stderr := logs.NewLogStreams(ctx, os.Getenv("BUILDKIT_DEBUG_EXEC_OUTPUT") == "1")
-	defer stdout.Close()
-	defer stderr.Close()
 
 	execErr := e.exec.Run(ctx, "", p.Root, p.Mounts, executor.ProcessInfo{
 		Meta:   meta,
 		Stdin:  nil,
-		Stdout: stdout,
-		Stderr: stderr,
+		Stdout: procStdout,
+		Stderr: procStderr,
 	}, nil)
+	e.writeCacheMountStats(ctx, g, p.Actives, cacheFiles)
+
+	if sharedPID != nil && execErr != nil {
+		if exited, err := sharedPID.exited(); exited {
+			execErr = errors.Wrapf(execErr, "shared pid process %q exited first: %v", strings.Join(e.op.SharedPID.Meta.ProcessArgs(), " "), err)
+		}
+	}
+
+	if redirect != nil {
+		if err := redirect.writeTo(ctx, p.Root, e.cm.IdentityMapping()); err != nil {
+			return nil, err
+		}
+	}
+
+	exitCode := processExitCode(execErr)
+	if exitCode != 0 {
+		solver.SetExitCode(ctx, exitCode)
+	}
+	if code, ok := allowedExitCode(execErr, e.op.AllowedExitCodes); ok {
+		fmt.Fprintf(stderr, "process exited with allowed exit code %d\n", code)
+		execErr = nil
+	}
 
 	for i, out := range p.OutputRefs {
 		if mutable, ok := out.Ref.(cache.MutableRef); ok {
@@ -347,6 +488,18 @@ func (e *execOp) Exec(ctx context.Context, g session.Group, inputs []solver.Resu
 			if err != nil {
 				return nil, errors.Wrapf(err, "error committing %s", mutable.ID())
 			}
+			if exitCode != 0 {
+				if err := cache.SetExitCode(ref, exitCode); err != nil {
+					ref.Release(context.TODO())
+					return nil, err
+				}
+			}
+			if paths := ignoreForCache(e.op.IgnoreForCache, e.op.Meta.Cwd, e.op.Mounts[out.MountIndex].Dest); len(paths) > 0 {
+				if err := cache.SetIgnoreForCache(ref, paths); err != nil {
+					ref.Release(context.TODO())
+					return nil, err
+				}
+			}
 			results = append(results, worker.NewWorkerRefResult(ref, e.w))
 		} else {
 			results = append(results, worker.NewWorkerRefResult(out.Ref.(cache.ImmutableRef), e.w))
@@ -354,7 +507,165 @@ func (e *execOp) Exec(ctx context.Context, g session.Group, inputs []solver.Resu
 		// Prevent the result from being released.
 		p.OutputRefs[i].Ref = nil
 	}
-	return results, errors.Wrapf(execErr, "process %q did not complete successfully", strings.Join(e.op.Meta.Args, " "))
+	return results, errors.Wrapf(execErr, "process %q did not complete successfully", strings.Join(meta.Args, " "))
+}
+
+// ignoreForCache returns the ignoreForCache paths of the process that are
+// inside the mount at dest, relative to the mount. A path can't exclude the
+// whole mount.
+func ignoreForCache(paths []string, cwd, dest string) []string {
+	dest = path.Join("/", dest)
+	var out []string
+	for _, p := range paths {
+		if !path.IsAbs(p) {
+			p = path.Join(cwd, p)
+		}
+		p = path.Join("/", p)
+		if dest == "/" {
+			if p != "/" {
+				out = append(out, p)
+			}
+		} else if strings.HasPrefix(p, dest+"/") {
+			out = append(out, strings.TrimPrefix(p, dest))
+		}
+	}
+	return out
+}
+
+// retryExitCode returns the exit code of err if the process exited with one
+// of the codes that are retried. Any nonzero exit code is retried if codes is
+// empty.
+func retryExitCode(err error, codes []int32) (uint32, bool) {
+	var exitErr *gwerrdefs.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode == 0 {
+		return 0, false
+	}
+	if len(codes) == 0 {
+		// the process may not have run at all
+		return exitErr.ExitCode, exitErr.ExitCode != gwerrdefs.UnknownExitStatus
+	}
+	for _, c := range codes {
+		if c > 0 && uint32(c) == exitErr.ExitCode {
+			return exitErr.ExitCode, true
+		}
+	}
+	return 0, false
+}
+
+// attemptProgress reports a retry of the process as a vertex of its own that
+// gets the logs of the attempt. The returned function completes the vertex
+// with the result of the attempt.
+func (e *execOp) attemptProgress(ctx context.Context, attempt, attempts int) (context.Context, func(error)) {
+	dgst := digest.FromString(fmt.Sprintf("%s:attempt:%d", e.vtx, attempt))
+	pw, _, ctx := progress.NewFromContext(ctx, progress.WithMetadata("vertex", dgst))
+	now := time.Now()
+	v := client.Vertex{
+		Digest:  dgst,
+		Name:    fmt.Sprintf("[attempt %d/%d] %s", attempt, attempts, e.name),
+		Started: &now,
+	}
+	pw.Write(dgst.String(), v)
+	return ctx, func(err error) {
+		now := time.Now()
+		v.Completed = &now
+		if err != nil {
+			v.Error = err.Error()
+			v.ExitCode = processExitCode(err)
+		}
+		pw.Write(dgst.String(), v)
+		pw.Close()
+	}
+}
+
+// logRetry writes msg to the logs of the vertex
+func logRetry(ctx context.Context, msg string) {
+	stdout, stderr := logs.NewLogStreams(ctx, false)
+	defer stdout.Close()
+	defer stderr.Close()
+	fmt.Fprint(stderr, msg)
+}
+
+// validateSecurityOpts checks that the daemon allows the security options of
+// a process
+func validateSecurityOpts(sc worker.SecurityConfig, apparmorProfile string, seccomp *pb.SeccompOpt, userns *pb.UserNSMapping, sysctls map[string]string, netMode pb.NetMode) error {
+	if apparmorProfile != "" {
+		if err := validateApparmorProfile(apparmorProfile, sc.AllowedApparmorProfiles, apparmorProfilesPath); err != nil {
+			return err
+		}
+	}
+	if err := validateSeccomp(seccomp, sc.AllowSeccompUnconfined); err != nil {
+		return err
+	}
+	if userns != nil {
+		if err := validateUserNSMapping(userns); err != nil {
+			return err
+		}
+	}
+	if len(sysctls) > 0 {
+		if err := validateSysctls(sysctls, sc.AllowedSysctls, netMode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateMeta checks a process that is not run for an exec op of this
+// daemon, e.g. a process of a remote worker of another daemon, the same way
+// as the exec ops are checked when the build is loaded and run. ent are the
+// entitlements the process may use.
+func ValidateMeta(meta executor.Meta, sc worker.SecurityConfig, ent entitlements.Set) error {
+	if meta.NetMode == pb.NetMode_HOST && !ent.Allowed(entitlements.EntitlementNetworkHost) {
+		return errors.Errorf("%s is not allowed", entitlements.EntitlementNetworkHost)
+	}
+	if meta.SecurityMode == pb.SecurityMode_INSECURE && !ent.Allowed(entitlements.EntitlementSecurityInsecure) {
+		return errors.Errorf("%s is not allowed", entitlements.EntitlementSecurityInsecure)
+	}
+	if meta.Seccomp != nil && (meta.Seccomp.Unconfined || meta.Seccomp.Profile != "") && !ent.Allowed(entitlements.EntitlementSecurityInsecure) {
+		return errors.Errorf("%s is not allowed", entitlements.EntitlementSecurityInsecure)
+	}
+	return validateSecurityOpts(sc, meta.ApparmorProfile, meta.Seccomp, meta.UserNSMapping, meta.Sysctls, meta.NetMode)
+}
+
+// validateSeccomp checks that the daemon allows the seccomp override of the
+// process. A custom profile can allow any syscall, e.g. with an allow default
+// action, so it is allowed like running without seccomp. The
+// security.insecure entitlement is checked when the build is loaded.
+func validateSeccomp(s *pb.SeccompOpt, allowUnconfined bool) error {
+	if s == nil || allowUnconfined {
+		return nil
+	}
+	if s.Unconfined {
+		return errors.New("running processes without seccomp is not allowed by the daemon")
+	}
+	if s.Profile != "" {
+		return errors.New("running processes with custom seccomp profiles is not allowed by the daemon")
+	}
+	return nil
+}
+
+// processExitCode returns the exit code of the process that failed with err.
+// It is zero if the process succeeded or didn't run.
+func processExitCode(err error) int {
+	var exitErr *gwerrdefs.ExitError
+	if !errors.As(err, &exitErr) {
+		return 0
+	}
+	return int(exitErr.ExitCode)
+}
+
+// allowedExitCode returns the exit code of err if the process exited with one
+// of the allowed codes.
+func allowedExitCode(err error, allowed []int32) (uint32, bool) {
+	var exitErr *gwerrdefs.ExitError
+	if len(allowed) == 0 || !errors.As(err, &exitErr) {
+		return 0, false
+	}
+	for _, c := range allowed {
+		if c > 0 && uint32(c) == exitErr.ExitCode {
+			return exitErr.ExitCode, true
+		}
+	}
+	return 0, false
 }
 
 func proxyEnvList(p *pb.ProxyEnv) []string {
@@ -404,3 +715,135 @@ func (e *execOp) Acquire(ctx context.Context) (solver.ReleaseFunc, error) {
 		e.parallelism.Release(1)
 	}, nil
 }
+
+// processMeta returns the meta of a process of the op and the mounts it
+// needs in addition to the mounts of the op. The process runs with the
+// security options of the op.
+func (e *execOp) processMeta(ctx context.Context, g session.Group, m *pb.Meta, secretenv []*pb.SecretEnv, stderr io.Writer) (executor.Meta, []executor.Mount, error) {
+	extraHosts, err := parseExtraHosts(m.ExtraHosts)
+	if err != nil {
+		return executor.Meta{}, nil, err
+	}
+
+	args := m.ProcessArgs()
+	var mounts []executor.Mount
+
+	var emu *emulator
+	if rw, ok := e.w.(remoteExecutor); !ok || !rw.RemoteExecutor() {
+		emu, err = getEmulator(e.platform, e.cm.IdentityMapping())
+	}
+	if err == nil && emu != nil {
+		args = append([]string{qemuMountName}, args...)
+
+		mounts = append(mounts, executor.Mount{
+			Readonly: true,
+			Src:      emu,
+			Dest:     qemuMountName,
+		})
+	}
+	if err != nil {
+		logrus.Warn(err.Error()) // TODO: remove this with pull support
+	}
+
+	umask, err := m.ParseUmask()
+	if err != nil {
+		return executor.Meta{}, nil, err
+	}
+
+	meta := executor.Meta{
+		Args:            args,
+		Env:             m.Env,
+		Cwd:             m.Cwd,
+		User:            m.User,
+		Hostname:        m.Hostname,
+		ExtraHosts:      extraHosts,
+		NetMode:         e.op.Network,
+		SecurityMode:    e.op.Security,
+		Seccomp:         e.op.Seccomp,
+		ApparmorProfile: e.op.ApparmorProfile,
+		Devices:         e.op.Devices,
+		Umask:           umask,
+		UserNSMapping:   e.op.UserNSMapping,
+		Sysctls:         e.op.Sysctls,
+	}
+
+	if m.ProxyEnv != nil {
+		meta.Env = append(meta.Env, proxyEnvList(m.ProxyEnv)...)
+	}
+	secretEnv, err := e.loadSecretEnv(ctx, g, secretenv)
+	if err != nil {
+		return executor.Meta{}, nil, err
+	}
+	meta.Env = append(meta.Env, secretEnv...)
+
+	hostEnv, ignored := passthroughEnv(m.PassthroughEnv, e.w.SecurityConfig().PassthroughEnv, os.LookupEnv)
+	if len(ignored) > 0 {
+		logrus.Warnf("ignoring env variables not allowed to be passed through from the host: %s", strings.Join(ignored, ", "))
+		fmt.Fprintf(stderr, "warning: env variables not allowed to be passed through from the host are ignored: %s\n", strings.Join(ignored, ", "))
+		llbsolver.Warn(ctx, client.Warning{
+			Code:    "PassthroughEnvNotAllowed",
+			Message: "env variables not allowed to be passed through from the host are ignored",
+			Detail:  strings.Join(ignored, ", "),
+		})
+	}
+	meta.Env = append(meta.Env, hostEnv...)
+
+	if tz := m.Timezone; tz != nil {
+		if err := validateTimezone(tz.Name, zoneinfoDir); err != nil {
+			return executor.Meta{}, nil, err
+		}
+		meta.Env = setEnvvar(meta.Env, "TZ", tz.Name)
+		if tz.MountZoneinfo {
+			mnts, err := e.timezoneMounts(tz, g)
+			if err != nil {
+				return executor.Meta{}, nil, err
+			}
+			mounts = append(mounts, mnts...)
+		}
+	}
+
+	var currentOS string
+	if e.platform != nil {
+		currentOS = e.platform.OS
+	}
+	meta.Env = addDefaultEnvvar(meta.Env, "PATH", utilsystem.DefaultPathEnv(currentOS))
+	return meta, mounts, nil
+}
+
+func (e *execOp) loadSecretEnv(ctx context.Context, g session.Group, secretenv []*pb.SecretEnv) ([]string, error) {
+	if len(secretenv) == 0 {
+		return nil, nil
+	}
+	out := make([]string, 0, len(secretenv))
+	for _, sopt := range secretenv {
+		id := sopt.ID
+		if id == "" {
+			return nil, errors.Errorf("secret ID missing for %q environment variable", sopt.Name)
+		}
+		if sopt.Name == "" {
+			return nil, errors.Errorf("environment variable name missing for secret %q", id)
+		}
+		var dt []byte
+		var found bool
+		err := e.sm.Any(ctx, g, func(ctx context.Context, _ string, caller session.Caller) error {
+			var err error
+			dt, err = secrets.GetSecret(ctx, caller, id)
+			if err != nil {
+				if errors.Is(err, secrets.ErrNotFound) && sopt.Optional {
+					return nil
+				}
+				return err
+			}
+			found = true
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+		out = append(out, fmt.Sprintf("%s=%s", sopt.Name, string(dt)))
+	}
+	return out, nil
+}
@@ -83,6 +83,12 @@ func (m *staticEmulatorMount) IdentityMapping() *idtools.IdentityMapping {
 	return m.idmap
 }
 
+// remoteExecutor is implemented by workers whose processes run on another
+// machine that emulates the platforms it doesn't support itself
+type remoteExecutor interface {
+	RemoteExecutor() bool
+}
+
 func getEmulator(p *pb.Platform, idmap *idtools.IdentityMapping) (*emulator, error) {
 	all := archutil.SupportedPlatforms(false)
 	m := make(map[string]struct{}, len(all))
@@ -1,8 +1,21 @@
 package ops
 
 import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/moby/buildkit/client"
+	gwerrdefs "github.com/moby/buildkit/frontend/gateway/errdefs"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/progress"
+	"github.com/moby/buildkit/util/progress/logs"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -25,3 +38,353 @@ func TestDedupPaths(t *testing.T) {
 	res = dedupePaths([]string{"foo/bar/baz", "foo/bara", "foo/bar/bax", "foo/bar"})
 	require.Equal(t, []string{"foo/bar", "foo/bara"}, res)
 }
+
+func TestAllowedExitCode(t *testing.T) {
+	err := errors.Wrap(&gwerrdefs.ExitError{ExitCode: 3}, "process failed")
+
+	code, ok := allowedExitCode(err, []int32{1, 3})
+	require.True(t, ok)
+	require.Equal(t, uint32(3), code)
+
+	_, ok = allowedExitCode(err, []int32{1})
+	require.False(t, ok)
+
+	_, ok = allowedExitCode(err, nil)
+	require.False(t, ok)
+
+	_, ok = allowedExitCode(errors.New("other"), []int32{1, 3})
+	require.False(t, ok)
+}
+
+func TestValidateSeccomp(t *testing.T) {
+	require.NoError(t, validateSeccomp(nil, false))
+	// custom profiles can allow everything, e.g. with an allow default action
+	require.Error(t, validateSeccomp(&pb.SeccompOpt{Profile: `{"defaultAction":"SCMP_ACT_ALLOW"}`}, false))
+	require.Error(t, validateSeccomp(&pb.SeccompOpt{Profile: "{}"}, false))
+	require.NoError(t, validateSeccomp(&pb.SeccompOpt{Profile: "{}"}, true))
+	require.Error(t, validateSeccomp(&pb.SeccompOpt{Unconfined: true}, false))
+	require.NoError(t, validateSeccomp(&pb.SeccompOpt{Unconfined: true}, true))
+}
+
+func TestProcessExitCode(t *testing.T) {
+	require.Equal(t, 3, processExitCode(errors.Wrap(&gwerrdefs.ExitError{ExitCode: 3}, "process failed")))
+	require.Equal(t, 0, processExitCode(errors.New("other")))
+	require.Equal(t, 0, processExitCode(nil))
+}
+
+func TestRetryExitCode(t *testing.T) {
+	err := errors.Wrap(&gwerrdefs.ExitError{ExitCode: 3}, "process failed")
+
+	code, ok := retryExitCode(err, []int32{1, 3})
+	require.True(t, ok)
+	require.Equal(t, uint32(3), code)
+
+	_, ok = retryExitCode(err, []int32{1})
+	require.False(t, ok)
+
+	code, ok = retryExitCode(err, nil)
+	require.True(t, ok)
+	require.Equal(t, uint32(3), code)
+
+	_, ok = retryExitCode(&gwerrdefs.ExitError{ExitCode: gwerrdefs.UnknownExitStatus}, nil)
+	require.False(t, ok)
+
+	_, ok = retryExitCode(errors.New("other"), nil)
+	require.False(t, ok)
+}
+
+func TestAttemptProgress(t *testing.T) {
+	t.Parallel()
+
+	pr, ctx, cancel := progress.NewContext(context.TODO())
+	e := &execOp{vtx: digest.FromString("vtx"), name: "make"}
+
+	actx, done := e.attemptProgress(ctx, 2, 3)
+	stdout, stderr := logs.NewLogStreams(actx, false)
+	stdout.Write([]byte("retrying\n"))
+	stdout.Close()
+	stderr.Close()
+	done(errors.Wrap(&gwerrdefs.ExitError{ExitCode: 3}, "process failed"))
+	cancel()
+
+	var vertexes []client.Vertex
+	var logVertexes []digest.Digest
+	for {
+		p, err := pr.Read(context.TODO())
+		if err != nil {
+			require.Equal(t, io.EOF, err)
+			break
+		}
+		for _, p := range p {
+			switch v := p.Sys.(type) {
+			case client.Vertex:
+				vertexes = append(vertexes, v)
+			case client.VertexLog:
+				vtx, _ := p.Meta("vertex")
+				logVertexes = append(logVertexes, vtx.(digest.Digest))
+			}
+		}
+	}
+
+	require.NotEmpty(t, vertexes)
+	last := vertexes[len(vertexes)-1]
+	require.Equal(t, "[attempt 2/3] make", last.Name)
+	require.NotEqual(t, e.vtx, last.Digest)
+	require.NotNil(t, last.Started)
+	require.NotNil(t, last.Completed)
+	require.Equal(t, 3, last.ExitCode)
+	require.Contains(t, last.Error, "process failed")
+
+	// the logs of the attempt belong to its vertex
+	require.Equal(t, []digest.Digest{last.Digest}, logVertexes)
+}
+
+func TestCompareCacheMountFiles(t *testing.T) {
+	now := time.Now()
+	before := cacheMountFiles{
+		"a": {size: 10, mtime: now},
+		"b": {size: 20, mtime: now},
+		"c": {size: 40, mtime: now},
+	}
+	after := cacheMountFiles{
+		"a": {size: 10, mtime: now},
+		"b": {size: 20, mtime: now.Add(time.Second)},
+		"d": {size: 5, mtime: now},
+	}
+	unchanged, written := compareCacheMountFiles(before, after)
+	require.Equal(t, int64(10), unchanged)
+	require.Equal(t, int64(25), written)
+
+	unchanged, written = compareCacheMountFiles(cacheMountFiles{}, after)
+	require.Equal(t, int64(0), unchanged)
+	require.Equal(t, int64(35), written)
+}
+
+func TestPassthroughEnv(t *testing.T) {
+	host := map[string]string{"HTTP_PROXY": "http://proxy:3128", "SECRET": "foo"}
+	lookup := func(k string) (string, bool) {
+		v, ok := host[k]
+		return v, ok
+	}
+
+	env, ignored := passthroughEnv([]string{"HTTP_PROXY", "NO_PROXY", "SECRET"}, []string{"HTTP_PROXY", "NO_PROXY"}, lookup)
+	require.Equal(t, []string{"HTTP_PROXY=http://proxy:3128"}, env)
+	require.Equal(t, []string{"SECRET"}, ignored)
+
+	env, ignored = passthroughEnv([]string{"HTTP_PROXY"}, nil, lookup)
+	require.Equal(t, 0, len(env))
+	require.Equal(t, []string{"HTTP_PROXY"}, ignored)
+}
+
+func TestCacheIgnoreEnv(t *testing.T) {
+	newOp := func(env ...string) *execOp {
+		return &execOp{
+			op: &pb.ExecOp{
+				Meta: &pb.Meta{
+					Args:           []string{"true"},
+					Env:            env,
+					CacheIgnoreEnv: []string{"BUILD_TIMESTAMP"},
+				},
+				Mounts: []*pb.Mount{{Dest: "/", Input: 0, Output: 0}},
+			},
+			numInputs: 1,
+		}
+	}
+
+	ctx := context.TODO()
+	cm1, _, err := newOp("PATH=/bin", "BUILD_TIMESTAMP=1").CacheMap(ctx, nil, 0)
+	require.NoError(t, err)
+	cm2, _, err := newOp("PATH=/bin", "BUILD_TIMESTAMP=2").CacheMap(ctx, nil, 0)
+	require.NoError(t, err)
+	require.Equal(t, cm1.Digest, cm2.Digest)
+
+	cm3, _, err := newOp("PATH=/usr/bin", "BUILD_TIMESTAMP=1").CacheMap(ctx, nil, 0)
+	require.NoError(t, err)
+	require.NotEqual(t, cm1.Digest, cm3.Digest)
+
+	// the op itself is not modified
+	op := newOp("PATH=/bin", "BUILD_TIMESTAMP=1")
+	_, _, err = op.CacheMap(ctx, nil, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"PATH=/bin", "BUILD_TIMESTAMP=1"}, op.op.Meta.Env)
+}
+
+func TestExecAfterMountDeps(t *testing.T) {
+	e := &execOp{
+		op: &pb.ExecOp{
+			Mounts: []*pb.Mount{
+				{Input: 0, Dest: pb.RootMount, Output: 0},
+				{Input: 1, Dest: "/src", Readonly: true, Output: -1},
+			},
+			After: []pb.InputIndex{1, 2},
+		},
+		numInputs: 3,
+	}
+	deps, err := e.getMountDeps()
+	require.NoError(t, err)
+	require.False(t, deps[0].After)
+	require.False(t, deps[1].After)
+	require.True(t, deps[2].After)
+
+	e.op.After = []pb.InputIndex{3}
+	_, err = e.getMountDeps()
+	require.Error(t, err)
+}
+
+func TestValidateTimezone(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "zoneinfo")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "Europe"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "UTC"), []byte("TZif"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "Europe/Berlin"), []byte("TZif"), 0644))
+
+	require.NoError(t, validateTimezone("UTC", dir))
+	require.NoError(t, validateTimezone("Europe/Berlin", dir))
+
+	for _, name := range []string{"", "Europe", "Europe/Paris", "../etc/passwd", "/UTC", "Europe//Berlin", "UTC "} {
+		err := validateTimezone(name, dir)
+		require.Error(t, err, name)
+		require.Contains(t, err.Error(), "invalid timezone")
+	}
+
+	// without a tz database only the format is checked
+	require.NoError(t, validateTimezone("Europe/Paris", filepath.Join(dir, "missing")))
+	require.Error(t, validateTimezone("../etc/passwd", filepath.Join(dir, "missing")))
+}
+
+func TestZoneinfoAllowed(t *testing.T) {
+	t.Parallel()
+
+	allowed := []string{"UTC", "Europe/*"}
+
+	require.True(t, zoneinfoAllowed("UTC", allowed))
+	require.True(t, zoneinfoAllowed("Europe/Berlin", allowed))
+	require.False(t, zoneinfoAllowed("America/New_York", allowed))
+	require.False(t, zoneinfoAllowed("UTC", nil))
+}
+
+func TestSetEnvvar(t *testing.T) {
+	t.Parallel()
+
+	env := setEnvvar([]string{"PATH=/bin", "TZ=UTC", "TZDIR=/foo"}, "TZ", "Europe/Berlin")
+	require.Equal(t, []string{"PATH=/bin", "TZDIR=/foo", "TZ=Europe/Berlin"}, env)
+}
+
+func TestValidateApparmorProfile(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "apparmor")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	profiles := filepath.Join(dir, "profiles")
+	require.NoError(t, ioutil.WriteFile(profiles, []byte("docker-default (enforce)\nbuild-restricted (complain)\n"), 0644))
+
+	allowed := []string{"docker-default", "build-restricted", "build"}
+	require.NoError(t, validateApparmorProfile("docker-default", allowed, profiles))
+
+	err = validateApparmorProfile("other", allowed, profiles)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not allowed by the daemon configuration")
+
+	err = validateApparmorProfile("build", allowed, profiles)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not loaded on the host")
+
+	// profiles in complain mode don't confine the process
+	err = validateApparmorProfile("build-restricted", allowed, profiles)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is in complain mode")
+
+	err = validateApparmorProfile("unconfined", allowed, profiles)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not allowed by the daemon configuration")
+
+	allowed = append(allowed, "unconfined")
+	require.NoError(t, validateApparmorProfile("unconfined", allowed, profiles))
+	require.NoError(t, validateApparmorProfile("build-restricted", allowed, profiles))
+
+	err = validateApparmorProfile("docker-default", allowed, filepath.Join(dir, "missing"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "apparmor is not enabled on the host")
+}
+
+func TestValidateUserNSMapping(t *testing.T) {
+	t.Parallel()
+
+	ids := func(m ...uint32) []*pb.IDMap {
+		var out []*pb.IDMap
+		for i := 0; i < len(m); i += 3 {
+			out = append(out, &pb.IDMap{ContainerID: m[i], HostID: m[i+1], Size_: m[i+2]})
+		}
+		return out
+	}
+
+	require.NoError(t, validateUserNSMapping(&pb.UserNSMapping{
+		UidMappings: ids(0, 1000, 1, 1, 100000, 65535),
+		GidMappings: ids(0, 1000, 1),
+	}))
+
+	err := validateUserNSMapping(&pb.UserNSMapping{UidMappings: ids(0, 1000, 1)})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no gid mappings")
+
+	err = validateUserNSMapping(&pb.UserNSMapping{UidMappings: ids(0, 1000, 0), GidMappings: ids(0, 1000, 1)})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "size must be greater than zero")
+
+	err = validateUserNSMapping(&pb.UserNSMapping{UidMappings: ids(0, 1000, 10, 5, 2000, 10), GidMappings: ids(0, 1000, 1)})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "overlapping container ranges")
+
+	err = validateUserNSMapping(&pb.UserNSMapping{UidMappings: ids(0, 1000, 1), GidMappings: ids(0, 1000, 10, 10, 1009, 1)})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "gid mappings 0:1000:10 and 10:1009:1 have overlapping host ranges")
+
+	err = validateUserNSMapping(&pb.UserNSMapping{UidMappings: ids(0, 4294967295, 2), GidMappings: ids(0, 1000, 1)})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "exceeds the maximum ID")
+}
+
+func TestValidateSysctls(t *testing.T) {
+	t.Parallel()
+
+	allowed := []string{"net.core.somaxconn", "net.ipv4.*"}
+
+	require.NoError(t, validateSysctls(map[string]string{
+		"net.core.somaxconn":                  "1024",
+		"net.ipv4.ip_unprivileged_port_start": "0",
+	}, allowed, pb.NetMode_UNSET))
+
+	err := validateSysctls(map[string]string{"kernel.shmmax": "1"}, allowed, pb.NetMode_UNSET)
+	require.EqualError(t, err, "setting sysctl kernel.shmmax is not allowed by the daemon configuration")
+
+	err = validateSysctls(map[string]string{"net.core.somaxconn": "1024"}, nil, pb.NetMode_UNSET)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not allowed")
+
+	err = validateSysctls(map[string]string{"net/core/somaxconn": "1024"}, []string{"*"}, pb.NetMode_UNSET)
+	require.EqualError(t, err, `invalid sysctl "net/core/somaxconn"`)
+
+	err = validateSysctls(map[string]string{"net.core.somaxconn": "1\n2"}, allowed, pb.NetMode_UNSET)
+	require.EqualError(t, err, "invalid value of sysctl net.core.somaxconn")
+
+	err = validateSysctls(map[string]string{"net.core.somaxconn": "1024"}, allowed, pb.NetMode_HOST)
+	require.EqualError(t, err, "sysctl net.core.somaxconn can't be set with the host network")
+}
+
+func TestIgnoreForCache(t *testing.T) {
+	t.Parallel()
+
+	paths := []string{"/out/log", "build.log", "/var/cache/", "/"}
+
+	require.Equal(t, []string{"/out/log", "/src/build.log", "/var/cache"}, ignoreForCache(paths, "/src", "/"))
+	require.Equal(t, []string{"/log"}, ignoreForCache(paths, "/src", "/out"))
+	require.Equal(t, []string{"/build.log"}, ignoreForCache(paths, "/src", "/src/"))
+	require.Nil(t, ignoreForCache(paths, "/src", "/var/cache"))
+	require.Nil(t, ignoreForCache(nil, "/", "/"))
+}
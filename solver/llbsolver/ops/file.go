@@ -166,7 +166,14 @@ func (f *fileOp) Exec(ctx context.Context, g session.Group, inputs []solver.Resu
 		if !ok {
 			return nil, errors.Errorf("invalid reference for exec %T", inp.Sys())
 		}
-		inpRefs = append(inpRefs, workerRef.ImmutableRef)
+		ref, err := workerRef.ToWorker(ctx, f.w, g)
+		if err != nil {
+			return nil, err
+		}
+		if ref != workerRef {
+			defer ref.ImmutableRef.Release(context.TODO())
+		}
+		inpRefs = append(inpRefs, ref.ImmutableRef)
 	}
 
 	outs, err := f.solver.Solve(ctx, inpRefs, f.op.Actions, g)
@@ -0,0 +1,145 @@
+package ops
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/containerd/continuity/fs"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/moby/buildkit/executor"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/pkg/errors"
+)
+
+// outputRedirect buffers the output of a process that is redirected to files
+// of its root filesystem. The files are written to the root filesystem after
+// the process has exited, so that it isn't mounted twice while the process
+// runs.
+type outputRedirect struct {
+	files          map[string]*redirectFile
+	stdout, stderr io.WriteCloser
+}
+
+// redirectFile is a temporary file shared by the streams redirected to the
+// same path. Writes of the streams are interleaved in the order they happen.
+type redirectFile struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+func (f *redirectFile) Write(dt []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.f.Write(dt)
+}
+
+func (f *redirectFile) Close() error {
+	return nil
+}
+
+// newOutputRedirect returns the redirect for the output streams of the process
+// described by meta. Streams that are not redirected keep writing to stdout
+// and stderr.
+func newOutputRedirect(meta *pb.Meta, stdout, stderr io.WriteCloser) (*outputRedirect, error) {
+	r := &outputRedirect{
+		files:  map[string]*redirectFile{},
+		stdout: stdout,
+		stderr: stderr,
+	}
+	open := func(p string) (*redirectFile, error) {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join("/", meta.Cwd, p)
+		}
+		p = filepath.Clean(p)
+		if f, ok := r.files[p]; ok {
+			return f, nil
+		}
+		tmp, err := ioutil.TempFile("", "buildkit-redirect")
+		if err != nil {
+			return nil, err
+		}
+		os.Remove(tmp.Name())
+		f := &redirectFile{f: tmp}
+		r.files[p] = f
+		return f, nil
+	}
+	if meta.RedirectStdout != "" {
+		f, err := open(meta.RedirectStdout)
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+		r.stdout = f
+	}
+	if meta.RedirectStderr != "" {
+		f, err := open(meta.RedirectStderr)
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+		r.stderr = f
+	}
+	return r, nil
+}
+
+// writeTo writes the buffered output to the files in the root filesystem.
+// The files are owned by the root user of the identity mapping.
+func (r *outputRedirect) writeTo(ctx context.Context, root executor.Mount, idmap *idtools.IdentityMapping) error {
+	m, err := root.Src.Mount(ctx, false)
+	if err != nil {
+		return err
+	}
+	lm := snapshot.LocalMounter(m)
+	dir, err := lm.Mount()
+	if err != nil {
+		return err
+	}
+	defer lm.Unmount()
+
+	for p, f := range r.files {
+		dest, err := fs.RootPath(filepath.Join(dir, root.Selector), p)
+		if err != nil {
+			return err
+		}
+		if err := writeRedirectFile(dest, f.f, idmap); err != nil {
+			return errors.Wrapf(err, "failed to write output to %s", p)
+		}
+	}
+	return nil
+}
+
+func writeRedirectFile(dest string, src *os.File, idmap *idtools.IdentityMapping) error {
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, src); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if idmap != nil {
+		identity := idmap.RootPair()
+		if err := os.Lchown(dest, identity.UID, identity.GID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *outputRedirect) Close() error {
+	for _, f := range r.files {
+		f.f.Close()
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package ops
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputRedirect(t *testing.T) {
+	t.Parallel()
+
+	r, err := newOutputRedirect(&pb.Meta{
+		Cwd:            "/work",
+		RedirectStdout: "out.log",
+		RedirectStderr: "/work/out.log",
+	}, nil, nil)
+	require.NoError(t, err)
+	defer r.Close()
+
+	require.Equal(t, 1, len(r.files))
+	_, ok := r.files["/work/out.log"]
+	require.True(t, ok)
+
+	_, err = r.stdout.Write([]byte("foo\n"))
+	require.NoError(t, err)
+	_, err = r.stderr.Write([]byte("bar\n"))
+	require.NoError(t, err)
+	_, err = r.stdout.Write([]byte("baz\n"))
+	require.NoError(t, err)
+
+	tmpdir, err := ioutil.TempDir("", "buildkit-redirect")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	dest := filepath.Join(tmpdir, "out.log")
+	require.NoError(t, writeRedirectFile(dest, r.files["/work/out.log"].f, nil))
+
+	dt, err := ioutil.ReadFile(dest)
+	require.NoError(t, err)
+	require.Equal(t, "foo\nbar\nbaz\n", string(dt))
+}
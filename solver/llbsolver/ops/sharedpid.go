@@ -0,0 +1,128 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/executor"
+	"github.com/moby/buildkit/frontend/gateway"
+	"github.com/moby/buildkit/identity"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/worker"
+	"github.com/pkg/errors"
+)
+
+// sharedPIDProcess is a running process that shares its PID namespace with
+// the process of an exec op. The namespace has an init process of its own, so
+// the processes are siblings and either of them can exit first. The shared
+// process is killed when the process of the op exits.
+type sharedPIDProcess struct {
+	// ns is the ID of the shared PID namespace
+	ns      string
+	cancel  func()
+	started chan struct{}
+	done    chan struct{}
+	err     error
+	p       gateway.PreparedMounts
+}
+
+// startSharedPID starts the shared pid process of the op in a new container
+// and waits until it has started. The process of the op joins its PID
+// namespace with executor.Meta.SharedPID.
+func (e *execOp) startSharedPID(ctx context.Context, g session.Group, sp *pb.SharedPID, refs []*worker.WorkerRef, stdout, stderr io.WriteCloser) (*sharedPIDProcess, error) {
+	mnts := make([]*pb.Mount, len(sp.Mounts))
+	for i, m := range sp.Mounts {
+		m := *m
+		// the writable rootfs needs a mutable ref that is released with the
+		// mounts, the changes are never committed
+		m.Output = pb.SkipOutput
+		if m.Dest == pb.RootMount && !m.Readonly {
+			m.Output = 0
+		}
+		mnts[i] = &m
+	}
+
+	p, err := gateway.PrepareMounts(ctx, e.mm, e.cm, g, sp.Meta.Cwd, mnts, refs, func(m *pb.Mount, ref cache.ImmutableRef) (cache.MutableRef, error) {
+		desc := fmt.Sprintf("mount %s from shared pid process %s", m.Dest, strings.Join(sp.Meta.ProcessArgs(), " "))
+		return e.cm.New(ctx, ref, g, cache.WithDescription(desc))
+	})
+	if err != nil {
+		releasePreparedMounts(p)
+		return nil, err
+	}
+
+	meta, mounts, err := e.processMeta(ctx, g, sp.Meta, sp.Secretenv, stderr)
+	if err != nil {
+		releasePreparedMounts(p)
+		return nil, err
+	}
+	meta.ReadonlyRootFS = p.ReadonlyRootFS
+	meta.SharedPID = identity.NewID()
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &sharedPIDProcess{
+		ns:      meta.SharedPID,
+		cancel:  cancel,
+		started: make(chan struct{}),
+		done:    make(chan struct{}),
+		p:       p,
+	}
+	go func() {
+		defer close(s.done)
+		s.err = e.exec.Run(ctx, "", p.Root, append(p.Mounts, mounts...), executor.ProcessInfo{
+			Meta:   meta,
+			Stdout: stdout,
+			Stderr: stderr,
+		}, s.started)
+	}()
+
+	select {
+	case <-s.started:
+		return s, nil
+	case <-s.done:
+		select {
+		case <-s.started:
+			return s, nil
+		default:
+		}
+		releasePreparedMounts(p)
+		cancel()
+		err := s.err
+		if err == nil {
+			err = errors.New("exited before it was started")
+		}
+		return nil, errors.Wrapf(err, "shared pid process %q failed to start", strings.Join(sp.Meta.ProcessArgs(), " "))
+	}
+}
+
+// exited returns the error of the process if it has exited
+func (s *sharedPIDProcess) exited() (bool, error) {
+	select {
+	case <-s.done:
+		return true, s.err
+	default:
+		return false, nil
+	}
+}
+
+// stop kills the process and releases its mounts
+func (s *sharedPIDProcess) stop() {
+	s.cancel()
+	<-s.done
+	releasePreparedMounts(s.p)
+}
+
+func releasePreparedMounts(p gateway.PreparedMounts) {
+	for i := len(p.Actives) - 1; i >= 0; i-- {
+		p.Actives[i].Ref.Release(context.TODO())
+	}
+	for _, o := range p.OutputRefs {
+		if o.Ref != nil {
+			o.Ref.Release(context.TODO())
+		}
+	}
+}
@@ -85,6 +85,20 @@ func (s *sourceOp) CacheMap(ctx context.Context, g session.Group, index int) (*s
 	}, done, nil
 }
 
+// ResolvedSource returns the identifier of the source and the digest that it
+// was resolved to by CacheMap. The digest is empty for sources that don't
+// resolve to a digest, like local sources.
+func (s *sourceOp) ResolvedSource() (string, digest.Digest) {
+	s.mu.Lock()
+	src := s.src
+	s.mu.Unlock()
+	dr, ok := src.(source.DigestResolver)
+	if !ok {
+		return "", ""
+	}
+	return s.op.Source.Identifier, dr.ResolvedDigest()
+}
+
 func (s *sourceOp) Exec(ctx context.Context, g session.Group, _ []solver.Result) (outputs []solver.Result, err error) {
 	src, err := s.instance(ctx)
 	if err != nil {
@@ -0,0 +1,50 @@
+package ops
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/pkg/errors"
+)
+
+var validSysctl = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)+$`)
+
+// validateSysctls checks that the sysctls of an exec are valid and match one
+// of the allowed patterns. The net sysctls can't be set with the host network
+// as they would change the network namespace of the host.
+func validateSysctls(sysctls map[string]string, allowed []string, netMode pb.NetMode) error {
+	keys := make([]string, 0, len(sysctls))
+	for k := range sysctls {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !validSysctl.MatchString(k) {
+			return errors.Errorf("invalid sysctl %q", k)
+		}
+		if strings.ContainsAny(sysctls[k], "\x00\n") {
+			return errors.Errorf("invalid value of sysctl %s", k)
+		}
+		if !sysctlAllowed(k, allowed) {
+			return errors.Errorf("setting sysctl %s is not allowed by the daemon configuration", k)
+		}
+		if netMode == pb.NetMode_HOST && strings.HasPrefix(k, "net.") {
+			return errors.Errorf("sysctl %s can't be set with the host network", k)
+		}
+	}
+	return nil
+}
+
+// sysctlAllowed returns true if the sysctl matches one of the allowed
+// patterns, e.g. "net.core.somaxconn" or "net.ipv4.*"
+func sysctlAllowed(key string, allowed []string) bool {
+	for _, pattern := range allowed {
+		if ok, err := path.Match(pattern, key); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package ops
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/executor"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/pkg/errors"
+)
+
+// zoneinfoDir is the directory of the tz database, on the host and in the
+// container
+const zoneinfoDir = "/usr/share/zoneinfo"
+
+var validTimezone = regexp.MustCompile(`^[A-Za-z0-9_+-]+(/[A-Za-z0-9_+-]+)*$`)
+
+// validateTimezone checks that name is a timezone of the tz database. If the
+// host has no tz database only the format of the name is checked.
+func validateTimezone(name, dir string) error {
+	if !validTimezone.MatchString(name) {
+		return errors.Errorf("invalid timezone %q", name)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return nil
+	}
+	fi, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name)))
+	if err != nil || !fi.Mode().IsRegular() {
+		return errors.Errorf("invalid timezone %q: not found in %s", name, dir)
+	}
+	return nil
+}
+
+// zoneinfoAllowed returns true if the zoneinfo file of the timezone matches
+// one of the allowed patterns
+func zoneinfoAllowed(name string, allowed []string) bool {
+	for _, pattern := range allowed {
+		if ok, err := path.Match(pattern, name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// setEnvvar sets the variable k in env, replacing an existing value
+func setEnvvar(env []string, k, v string) []string {
+	out := make([]string, 0, len(env)+1)
+	for _, e := range env {
+		if !strings.HasPrefix(e, k+"=") {
+			out = append(out, e)
+		}
+	}
+	return append(out, k+"="+v)
+}
+
+// timezoneMounts returns the mounts of the zoneinfo file of the timezone of
+// the host. The file is mounted at its path in the tz database, where TZ is
+// looked up, and as /etc/localtime.
+func (e *execOp) timezoneMounts(tz *pb.Timezone, g session.Group) ([]executor.Mount, error) {
+	if !zoneinfoAllowed(tz.Name, e.w.SecurityConfig().HostZoneinfo) {
+		return nil, errors.Errorf("mounting the zoneinfo of timezone %q is not allowed by the daemon configuration", tz.Name)
+	}
+	p := filepath.Join(zoneinfoDir, filepath.FromSlash(tz.Name))
+	if fi, err := os.Stat(p); err != nil || !fi.Mode().IsRegular() {
+		return nil, errors.Errorf("zoneinfo of timezone %q not found on the host", tz.Name)
+	}
+	src := &sessionMountable{m: e.mm.MountableHostFile(p), g: g}
+	return []executor.Mount{
+		{Src: src, Dest: path.Join(zoneinfoDir, tz.Name), Readonly: true},
+		{Src: src, Dest: "/etc/localtime", Readonly: true},
+	}, nil
+}
+
+type sessionMountable struct {
+	m cache.Mountable
+	g session.Group
+}
+
+func (m *sessionMountable) Mount(ctx context.Context, readonly bool) (snapshot.Mountable, error) {
+	return m.m.Mount(ctx, readonly, m.g)
+}
@@ -0,0 +1,47 @@
+package ops
+
+import (
+	"sort"
+
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/pkg/errors"
+)
+
+// validateUserNSMapping checks that the uid and gid mappings of an exec are
+// not empty and that their ranges don't overlap
+func validateUserNSMapping(m *pb.UserNSMapping) error {
+	if err := validateIDMaps("uid", m.UidMappings); err != nil {
+		return err
+	}
+	return validateIDMaps("gid", m.GidMappings)
+}
+
+func validateIDMaps(kind string, maps []*pb.IDMap) error {
+	if len(maps) == 0 {
+		return errors.Errorf("user namespace mapping has no %s mappings", kind)
+	}
+	for _, m := range maps {
+		if m.Size_ == 0 {
+			return errors.Errorf("invalid %s mapping %d:%d:%d: size must be greater than zero", kind, m.ContainerID, m.HostID, m.Size_)
+		}
+		if uint64(m.ContainerID)+uint64(m.Size_) > 1<<32 || uint64(m.HostID)+uint64(m.Size_) > 1<<32 {
+			return errors.Errorf("invalid %s mapping %d:%d:%d: range exceeds the maximum ID", kind, m.ContainerID, m.HostID, m.Size_)
+		}
+	}
+	if err := checkOverlap(kind, "container", maps, func(m *pb.IDMap) uint32 { return m.ContainerID }); err != nil {
+		return err
+	}
+	return checkOverlap(kind, "host", maps, func(m *pb.IDMap) uint32 { return m.HostID })
+}
+
+func checkOverlap(kind, side string, maps []*pb.IDMap, start func(*pb.IDMap) uint32) error {
+	sorted := append([]*pb.IDMap{}, maps...)
+	sort.Slice(sorted, func(i, j int) bool { return start(sorted[i]) < start(sorted[j]) })
+	for i := 1; i < len(sorted); i++ {
+		prev, cur := sorted[i-1], sorted[i]
+		if uint64(start(prev))+uint64(prev.Size_) > uint64(start(cur)) {
+			return errors.Errorf("%s mappings %d:%d:%d and %d:%d:%d have overlapping %s ranges", kind, prev.ContainerID, prev.HostID, prev.Size_, cur.ContainerID, cur.HostID, cur.Size_, side)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package provenance
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	distreference "github.com/docker/distribution/reference"
+	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+const (
+	StatementType = "https://in-toto.io/Statement/v0.1"
+	PredicateType = "https://slsa.dev/provenance/v0.2"
+	BuildType     = "https://mobyproject.org/buildkit@v1"
+)
+
+// Frontend options with buildArgPrefix set build arguments. Build arguments
+// can contain secrets, so their values are recorded as redactedValue.
+const (
+	buildArgPrefix = "build-arg:"
+	redactedValue  = "<redacted>"
+)
+
+// Statement is an in-toto statement with a SLSA provenance predicate
+type Statement struct {
+	Type          string    `json:"_type"`
+	PredicateType string    `json:"predicateType"`
+	Subject       []Subject `json:"subject"`
+	Predicate     Predicate `json:"predicate"`
+}
+
+type Subject struct {
+	Name   string    `json:"name"`
+	Digest DigestSet `json:"digest"`
+}
+
+// DigestSet maps digest algorithms to the hex encoded digests
+type DigestSet map[string]string
+
+type Predicate struct {
+	Builder    Builder    `json:"builder"`
+	BuildType  string     `json:"buildType"`
+	Invocation Invocation `json:"invocation"`
+	Metadata   *Metadata  `json:"metadata,omitempty"`
+	Materials  []Material `json:"materials"`
+}
+
+type Builder struct {
+	ID string `json:"id"`
+}
+
+type Invocation struct {
+	Parameters Parameters `json:"parameters"`
+}
+
+type Parameters struct {
+	Frontend    string            `json:"frontend,omitempty"`
+	Args        map[string]string `json:"args,omitempty"`
+	BuildLabels map[string]string `json:"buildLabels,omitempty"`
+}
+
+type Metadata struct {
+	BuildStartedOn  *time.Time `json:"buildStartedOn,omitempty"`
+	BuildFinishedOn *time.Time `json:"buildFinishedOn,omitempty"`
+}
+
+// Material is a source the build was fetched from. Local sources of the client
+// are not materials.
+type Material struct {
+	URI    string    `json:"uri"`
+	Digest DigestSet `json:"digest,omitempty"`
+}
+
+// NewPredicate returns the provenance predicate of a build of the definitions
+// with the frontend. Images, Git repositories and HTTP sources of the
+// definitions are listed as materials, with their digest if it is known.
+func NewPredicate(frontend string, attrs map[string]string, defs []*pb.Definition) (*Predicate, error) {
+	materials, err := findMaterials(defs)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := BuildLabels(defs)
+	if err != nil {
+		return nil, err
+	}
+	return &Predicate{
+		BuildType: BuildType,
+		Invocation: Invocation{
+			Parameters: Parameters{
+				Frontend:    frontend,
+				Args:        redactArgs(attrs),
+				BuildLabels: labels,
+			},
+		},
+		Materials: materials,
+	}, nil
+}
+
+func redactArgs(attrs map[string]string) map[string]string {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		if strings.HasPrefix(k, buildArgPrefix) {
+			v = redactedValue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// BuildLabels returns the build labels that were set on the vertices of the
+// definitions, including the definitions of nested builds. If a label is set
+// on more than one vertex the value of the last vertex is used.
+func BuildLabels(defs []*pb.Definition) (map[string]string, error) {
+	m := map[string]string{}
+	for _, def := range defs {
+		if err := addBuildLabels(m, def); err != nil {
+			return nil, err
+		}
+	}
+	if len(m) == 0 {
+		return nil, nil
+	}
+	return m, nil
+}
+
+func addBuildLabels(m map[string]string, def *pb.Definition) error {
+	if def == nil {
+		return nil
+	}
+	for _, dt := range def.Def {
+		var op pb.Op
+		if err := (&op).Unmarshal(dt); err != nil {
+			return errors.Wrap(err, "failed to parse llb proto op")
+		}
+		if b := op.GetBuild(); b != nil {
+			if err := addBuildLabels(m, b.Def); err != nil {
+				return err
+			}
+		}
+		md, ok := def.Metadata[digest.FromBytes(dt)]
+		if !ok {
+			continue
+		}
+		for k, v := range md.Description {
+			if !strings.HasPrefix(k, pb.BuildLabelPrefix) {
+				continue
+			}
+			m[strings.TrimPrefix(k, pb.BuildLabelPrefix)] = v
+		}
+	}
+	return nil
+}
+
+func findMaterials(defs []*pb.Definition) ([]Material, error) {
+	m := map[string]Material{}
+	for _, def := range defs {
+		if err := addMaterials(m, def); err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]Material, 0, len(m))
+	for _, mat := range m {
+		out = append(out, mat)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].URI < out[j].URI
+	})
+	return out, nil
+}
+
+// addMaterials adds the sources of the definition to m, including the
+// sources of the definitions of nested builds
+func addMaterials(m map[string]Material, def *pb.Definition) error {
+	if def == nil {
+		return nil
+	}
+	for _, dt := range def.Def {
+		var op pb.Op
+		if err := (&op).Unmarshal(dt); err != nil {
+			return errors.Wrap(err, "failed to parse llb proto op")
+		}
+		if b := op.GetBuild(); b != nil {
+			if err := addMaterials(m, b.Def); err != nil {
+				return err
+			}
+			continue
+		}
+		src := op.GetSource()
+		if src == nil {
+			continue
+		}
+		mat, ok, err := sourceMaterial(src)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if prev, ok := m[mat.URI]; ok && len(prev.Digest) > 0 {
+			continue
+		}
+		m[mat.URI] = mat
+	}
+	return nil
+}
+
+var gitCommitRegexp = regexp.MustCompile("^[0-9a-f]{40}$")
+
+func sourceMaterial(src *pb.SourceOp) (Material, bool, error) {
+	parts := strings.SplitN(src.Identifier, "://", 2)
+	if len(parts) != 2 {
+		return Material{}, false, errors.Errorf("invalid source identifier %q", src.Identifier)
+	}
+
+	switch parts[0] {
+	case "docker-image":
+		ref, err := distreference.ParseNormalizedNamed(parts[1])
+		if err != nil {
+			return Material{}, false, errors.Wrapf(err, "invalid image reference %q", parts[1])
+		}
+		mat := Material{URI: "docker-image://" + ref.Name()}
+		if t, ok := ref.(distreference.Tagged); ok {
+			mat.URI += ":" + t.Tag()
+		}
+		if c, ok := ref.(distreference.Canonical); ok {
+			mat.Digest = digestSet(c.Digest())
+		}
+		return mat, true, nil
+	case "git":
+		mat := Material{URI: src.Identifier}
+		if i := strings.LastIndex(parts[1], "#"); i != -1 {
+			if ref := parts[1][i+1:]; gitCommitRegexp.MatchString(ref) {
+				mat.Digest = DigestSet{"sha1": ref}
+			}
+		}
+		return mat, true, nil
+	case "http", "https":
+		mat := Material{URI: src.Identifier}
+		if v, ok := src.Attrs[pb.AttrHTTPChecksum]; ok {
+			dgst, err := digest.Parse(v)
+			if err != nil {
+				return Material{}, false, errors.Wrapf(err, "invalid checksum of %s", src.Identifier)
+			}
+			mat.Digest = digestSet(dgst)
+		}
+		return mat, true, nil
+	default:
+		return Material{}, false, nil
+	}
+}
+
+func digestSet(dgst digest.Digest) DigestSet {
+	return DigestSet{dgst.Algorithm().String(): dgst.Encoded()}
+}
@@ -0,0 +1,43 @@
+package provenance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/buildkit/client/llb"
+	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPredicate(t *testing.T) {
+	t.Parallel()
+
+	imgDgst := digest.FromString("image")
+	fileDgst := digest.FromString("file")
+	commit := "0123456789abcdef0123456789abcdef01234567"
+
+	st := llb.Image("busybox:1.33@"+imgDgst.String()).
+		File(llb.Copy(llb.Git("github.com/moby/buildkit", commit), "/", "/src")).
+		File(llb.Copy(llb.HTTP("https://example.com/file", llb.Checksum(fileDgst)), "/file", "/file")).
+		File(llb.Copy(llb.Git("github.com/moby/buildkit", "master"), "/", "/master")).
+		File(llb.Copy(llb.Local("context"), "/", "/context")).
+		WithBuildLabel("team", "build")
+	def, err := st.Marshal(context.TODO())
+	require.NoError(t, err)
+
+	p, err := NewPredicate("dockerfile.v0", map[string]string{"build-arg:foo": "bar", "target": "release"}, []*pb.Definition{def.ToPB(), nil})
+	require.NoError(t, err)
+
+	require.Equal(t, BuildType, p.BuildType)
+	require.Equal(t, "dockerfile.v0", p.Invocation.Parameters.Frontend)
+	// the values of build arguments are not recorded
+	require.Equal(t, map[string]string{"build-arg:foo": "<redacted>", "target": "release"}, p.Invocation.Parameters.Args)
+	require.Equal(t, map[string]string{"team": "build"}, p.Invocation.Parameters.BuildLabels)
+	require.Equal(t, []Material{
+		{URI: "docker-image://docker.io/library/busybox:1.33", Digest: DigestSet{"sha256": imgDgst.Encoded()}},
+		{URI: "git://github.com/moby/buildkit#" + commit, Digest: DigestSet{"sha1": commit}},
+		{URI: "git://github.com/moby/buildkit#master"},
+		{URI: "https://example.com/file", Digest: DigestSet{"sha256": fileDgst.Encoded()}},
+	}, p.Materials)
+}
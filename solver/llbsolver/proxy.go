@@ -0,0 +1,19 @@
+package llbsolver
+
+import (
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/solver/pb"
+)
+
+// WithProxyConfig isolates the image, HTTP and Git sources of a build with its
+// own proxy configuration from the builds with other configurations and the
+// builds using the proxy of the daemon, so that shared sources are fetched
+// through the proxy of all the builds sharing them
+func WithProxyConfig(dgst string) LoadOpt {
+	return func(op *pb.Op, _ *pb.OpMetadata, opt *solver.VertexOptions) error {
+		if dgst != "" && isRemoteSource(op) {
+			opt.CacheNamespace += "/proxy:" + dgst
+		}
+		return nil
+	}
+}
@@ -0,0 +1,43 @@
+package llbsolver
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/session/proxy"
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithProxyConfig(t *testing.T) {
+	t.Parallel()
+
+	dgst := (&proxy.Config{HTTPSProxy: "http://proxy.example.com:3128"}).Digest().String()
+	git := &pb.Op{Op: &pb.Op_Source{Source: &pb.SourceOp{Identifier: "git://github.com/moby/buildkit"}}}
+	http := &pb.Op{Op: &pb.Op_Source{Source: &pb.SourceOp{Identifier: "https://example.com/foo"}}}
+
+	opt := solver.VertexOptions{CacheNamespace: "ns"}
+	require.NoError(t, WithProxyConfig(dgst)(git, nil, &opt))
+	require.Equal(t, "ns/proxy:"+dgst, opt.CacheNamespace)
+
+	opt = solver.VertexOptions{}
+	require.NoError(t, WithProxyConfig(dgst)(http, nil, &opt))
+	require.Equal(t, "/proxy:"+dgst, opt.CacheNamespace)
+
+	// builds using the proxy of the daemon are not affected
+	opt = solver.VertexOptions{}
+	require.NoError(t, WithProxyConfig("")(git, nil, &opt))
+	require.NoError(t, WithProxyConfig(dgst)(&pb.Op{Op: &pb.Op_Exec{Exec: &pb.ExecOp{}}}, nil, &opt))
+	require.Equal(t, "", opt.CacheNamespace)
+
+	s := solver.NewSolver(solver.SolverOpt{DefaultCache: solver.NewInMemoryCacheManager()})
+	defer s.Close()
+	j, err := s.NewJob("job1")
+	require.NoError(t, err)
+	defer j.Discard()
+
+	j.SetValue(keyProxyConfig, dgst)
+	v, err := loadProxyConfig(j)
+	require.NoError(t, err)
+	require.Equal(t, dgst, v)
+}
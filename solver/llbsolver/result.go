@@ -5,6 +5,7 @@ import (
 	"context"
 	"path"
 
+	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/cache/contenthash"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/solver"
@@ -49,6 +50,11 @@ func NewContentHashFunc(selectors []Selector) solver.ResultBasedCacheFunc {
 			selectors = []Selector{{}}
 		}
 
+		var ignored []string
+		if ref.ImmutableRef != nil {
+			ignored = cache.GetIgnoreForCache(ref.ImmutableRef)
+		}
+
 		dgsts := make([][]byte, len(selectors))
 
 		eg, ctx := errgroup.WithContext(ctx)
@@ -62,7 +68,7 @@ func NewContentHashFunc(selectors []Selector) solver.ResultBasedCacheFunc {
 						Wildcard:        sel.Wildcard,
 						FollowLinks:     sel.FollowLinks,
 						IncludePatterns: sel.IncludePatterns,
-						ExcludePatterns: sel.ExcludePatterns,
+						ExcludePatterns: append(append([]string{}, sel.ExcludePatterns...), ignored...),
 					},
 					s,
 				)
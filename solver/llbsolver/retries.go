@@ -0,0 +1,23 @@
+package llbsolver
+
+import "context"
+
+// defaultMaxExecRetries is the number of times an exec can be retried if the
+// daemon doesn't set a limit
+const defaultMaxExecRetries = 5
+
+type maxRetriesKey struct{}
+
+// withMaxRetries limits the retries of the exec running with ctx to n
+func withMaxRetries(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, maxRetriesKey{}, n)
+}
+
+// MaxRetries returns the number of times the exec running with ctx can be
+// retried with llb.WithRetry
+func MaxRetries(ctx context.Context) int {
+	if n, ok := ctx.Value(maxRetriesKey{}).(int); ok {
+		return n
+	}
+	return defaultMaxExecRetries
+}
@@ -2,7 +2,11 @@ package llbsolver
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,20 +19,41 @@ import (
 	"github.com/moby/buildkit/frontend"
 	"github.com/moby/buildkit/frontend/gateway"
 	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/cabundle"
+	"github.com/moby/buildkit/session/filesync"
+	sessionimagepolicy "github.com/moby/buildkit/session/imagepolicy"
+	"github.com/moby/buildkit/session/proxy"
 	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/solver/llbsolver/provenance"
+	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/buildkit/util/compression"
 	"github.com/moby/buildkit/util/entitlements"
+	"github.com/moby/buildkit/util/grpcerrors"
 	"github.com/moby/buildkit/util/progress"
 	"github.com/moby/buildkit/worker"
 	digest "github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
 )
 
 const keyEntitlements = "llb.entitlements"
+const keyCacheNamespace = "llb.cachenamespace"
+const keyCheckpointID = "llb.checkpointid"
+const keyHashConcurrency = "llb.hashconcurrency"
+const keyCacheMountStats = "llb.cachemountstats"
+const keyImagePolicy = "llb.imagepolicy"
+const keyCABundle = "llb.cabundle"
+const keyProxyConfig = "llb.proxyconfig"
+
+// keyExportRef is the frontend option selecting the named result that is
+// exported when the frontend returns multiple results
+const keyExportRef = "export-ref"
 
 type ExporterRequest struct {
-	Exporter        exporter.ExporterInstance
+	// Exporters are run in order. Each exporter receives the responses of the
+	// exporters before it.
+	Exporters       []exporter.ExporterInstance
 	CacheExporter   remotecache.Exporter
 	CacheExportMode solver.CacheExportMode
 }
@@ -46,35 +71,186 @@ type Solver struct {
 	gatewayForwarder          *controlgateway.GatewayForwarder
 	sm                        *session.Manager
 	entitlements              []string
+	execParallelism           *prioritySemaphore
+	criticalPath              bool
+	maxHashConcurrency        int
+	maxExecRetries            int
 }
 
-func New(wc *worker.Controller, f map[string]frontend.Frontend, cache solver.CacheManager, resolveCI map[string]remotecache.ResolveCacheImporterFunc, gatewayForwarder *controlgateway.GatewayForwarder, sm *session.Manager, ents []string) (*Solver, error) {
+// Opt configures the solver of the daemon
+type Opt struct {
+	WorkerController          *worker.Controller
+	Frontends                 map[string]frontend.Frontend
+	CacheManager              solver.CacheManager
+	ResolveCacheImporterFuncs map[string]remotecache.ResolveCacheImporterFunc
+	GatewayForwarder          *controlgateway.GatewayForwarder
+	SessionManager            *session.Manager
+	Entitlements              []string
+	// MaxExecParallelism limits the exec operations running at the same time
+	// across all builds. Zero means no limit. It can be changed later with
+	// SetMaxExecParallelism.
+	MaxExecParallelism int
+	// CriticalPathScheduling makes the exec operations waiting for
+	// MaxExecParallelism start in the order of their critical path.
+	CriticalPathScheduling bool
+	// MaxHashConcurrency limits the hash concurrency that builds can request.
+	// Zero means the number of CPUs.
+	MaxHashConcurrency int
+	// MaxExecRetries limits the number of times an exec is retried with
+	// llb.WithRetry. Zero means 5, a negative value disables retries.
+	MaxExecRetries int
+}
+
+// SolveOpt has the optional settings of a build
+type SolveOpt struct {
+	CacheNamespace string
+	CheckpointID   string
+	// Deadline cancels the build if it didn't finish before
+	Deadline *time.Time
+	// HashConcurrency is the number of files hashed in parallel to compute
+	// the content based cache keys of the build. It is capped by
+	// Opt.MaxHashConcurrency.
+	HashConcurrency int
+	// CacheMountStats reports the stats of the cache mounts of the execs in
+	// the status stream
+	CacheMountStats bool
+}
+
+func New(opt Opt) (*Solver, error) {
 	s := &Solver{
-		workerController:          wc,
-		resolveWorker:             defaultResolver(wc),
-		eachWorker:                allWorkers(wc),
-		frontends:                 f,
-		resolveCacheImporterFuncs: resolveCI,
-		gatewayForwarder:          gatewayForwarder,
-		sm:                        sm,
-		entitlements:              ents,
+		workerController:          opt.WorkerController,
+		resolveWorker:             defaultResolver(opt.WorkerController),
+		eachWorker:                allWorkers(opt.WorkerController),
+		frontends:                 opt.Frontends,
+		resolveCacheImporterFuncs: opt.ResolveCacheImporterFuncs,
+		gatewayForwarder:          opt.GatewayForwarder,
+		sm:                        opt.SessionManager,
+		entitlements:              opt.Entitlements,
+		criticalPath:              opt.CriticalPathScheduling,
+		maxHashConcurrency:        opt.MaxHashConcurrency,
+		maxExecRetries:            opt.MaxExecRetries,
+	}
+	if s.maxHashConcurrency <= 0 {
+		s.maxHashConcurrency = runtime.NumCPU()
 	}
+	if s.maxExecRetries == 0 {
+		s.maxExecRetries = defaultMaxExecRetries
+	} else if s.maxExecRetries < 0 {
+		s.maxExecRetries = 0
+	}
+	// the semaphore is created without a limit too, the limit can be set
+	// while the daemon is running
+	s.execParallelism = newPrioritySemaphore(opt.MaxExecParallelism)
 
 	s.solver = solver.NewSolver(solver.SolverOpt{
 		ResolveOpFunc: s.resolver(),
-		DefaultCache:  cache,
+		DefaultCache:  opt.CacheManager,
 	})
 	return s, nil
 }
 
 func (s *Solver) resolver() solver.ResolveOpFunc {
 	return func(v solver.Vertex, b solver.Builder) (solver.Op, error) {
-		w, err := s.resolveWorker()
+		w, err := s.resolveVertexWorker(v)
 		if err != nil {
 			return nil, err
 		}
-		return w.ResolveOp(v, s.Bridge(b), s.sm)
+		op, err := w.ResolveOp(v, s.Bridge(b), s.sm)
+		if err != nil {
+			return nil, err
+		}
+		vop := &vertexOp{Op: op, b: b, vtx: v.Digest(), maxRetries: s.maxExecRetries}
+		if pop, ok := v.Sys().(*pb.Op); ok && pop.GetExec() != nil {
+			vop.sem = s.execParallelism
+			if s.criticalPath {
+				vop.priority = v.Options().CriticalPath
+			}
+		}
+		return vop, nil
+	}
+}
+
+// vertexOp wraps the op of a vertex with the state of the builds sharing the
+// vertex. It records the resolved sources, the warnings and the vertex of the
+// results in the builds, hashes the inputs with their hash concurrency, passes
+// their image policy to the image sources and makes execs wait for the daemon
+// wide exec limit and respect its retry limit.
+type vertexOp struct {
+	solver.Op
+	b        solver.Builder
+	vtx      digest.Digest
+	sem      *prioritySemaphore
+	priority int
+	// maxRetries limits the retries of execs
+	maxRetries int
+}
+
+func (o *vertexOp) CacheMap(ctx context.Context, g session.Group, index int) (*solver.CacheMap, bool, error) {
+	cm, done, err := o.Op.CacheMap(withImagePolicy(ctx, o.b), g, index)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := recordSource(ctx, o.b, o.Op); err != nil {
+		return nil, false, err
+	}
+	if cm != nil {
+		cm = withHashConcurrency(o.b, cm)
+	}
+	return cm, done, nil
+}
+
+// AttachJob records the resolved source of the op in a build that loaded the
+// vertex after its cache key was computed for another build
+func (o *vertexOp) AttachJob(j *solver.Job) {
+	recordJobSource(j, o.Op)
+}
+
+func (o *vertexOp) Exec(ctx context.Context, g session.Group, inputs []solver.Result) ([]solver.Result, error) {
+	ctx = withMaxRetries(withWarnings(ctx, o.b, o.vtx), o.maxRetries)
+	ctx = withCacheMountStats(ctx, o.b)
+	res, err := o.Op.Exec(ctx, g, inputs)
+	if err != nil {
+		return nil, err
+	}
+	recordVertex(res, o.vtx)
+	return res, nil
+}
+
+// Acquire waits for the daemon wide exec limit before acquiring the resources
+// of the op. Ops with a higher priority stop waiting first.
+func (o *vertexOp) Acquire(ctx context.Context) (solver.ReleaseFunc, error) {
+	if o.sem == nil {
+		return o.Op.Acquire(ctx)
+	}
+	if err := o.sem.Acquire(ctx, o.priority); err != nil {
+		return nil, err
+	}
+	release, err := o.Op.Acquire(ctx)
+	if err != nil {
+		o.sem.Release()
+		return nil, err
+	}
+	return func() {
+		release()
+		o.sem.Release()
+	}, nil
+}
+
+// SetMaxExecParallelism changes the limit of the exec operations running at
+// the same time across all builds and returns the previous limit. Zero means
+// no limit. Running operations are not stopped if the limit is lowered.
+func (s *Solver) SetMaxExecParallelism(n int) int {
+	return s.execParallelism.resize(n)
+}
+
+// resolveVertexWorker returns the worker that should run the vertex. Vertexes
+// with worker constraints are routed to the first worker whose ID or labels
+// match them, others run on the default worker.
+func (s *Solver) resolveVertexWorker(v solver.Vertex) (worker.Worker, error) {
+	if op, ok := v.Sys().(*pb.Op); ok && op.Constraints != nil && len(op.Constraints.Filter) > 0 {
+		return s.workerController.Select(op.Constraints.Filter...)
 	}
+	return s.resolveWorker()
 }
 
 func (s *Solver) Bridge(b solver.Builder) frontend.FrontendLLBBridge {
@@ -89,7 +265,8 @@ func (s *Solver) Bridge(b solver.Builder) frontend.FrontendLLBBridge {
 	}
 }
 
-func (s *Solver) Solve(ctx context.Context, id string, sessionID string, req frontend.SolveRequest, exp ExporterRequest, ent []entitlements.Entitlement) (*client.SolveResponse, error) {
+func (s *Solver) Solve(ctx context.Context, id string, sessionID string, req frontend.SolveRequest, exp ExporterRequest, ent []entitlements.Entitlement, opt SolveOpt) (_ *client.SolveResponse, err error) {
+	startedOn := time.Now()
 	j, err := s.solver.NewJob(id)
 	if err != nil {
 		return nil, err
@@ -97,11 +274,69 @@ func (s *Solver) Solve(ctx context.Context, id string, sessionID string, req fro
 
 	defer j.Discard()
 
+	// set before the build is listed by ListBuilds
+	j.Frontend = req.Frontend
+	ctx, cancel := j.WithCancel(ctx)
+	defer cancel()
+	defer func() {
+		if err != nil && j.Cancelled() {
+			err = grpcerrors.WrapCode(errors.Wrap(err, "build cancelled"), codes.Canceled)
+		}
+	}()
+
+	if opt.Deadline != nil {
+		var cancel func()
+		ctx, cancel = context.WithDeadline(ctx, *opt.Deadline)
+		defer cancel()
+
+		p := newBuildProgress(j)
+		defer func() {
+			if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				err = p.deadlineError(err, time.Since(startedOn))
+			}
+		}()
+	}
+
 	set, err := entitlements.WhiteList(ent, supportedEntitlements(s.entitlements))
 	if err != nil {
 		return nil, err
 	}
 	j.SetValue(keyEntitlements, set)
+	if opt.CacheNamespace != "" {
+		j.SetValue(keyCacheNamespace, opt.CacheNamespace)
+	}
+	if opt.CheckpointID != "" {
+		j.SetValue(keyCheckpointID, opt.CheckpointID)
+	}
+	if n := opt.HashConcurrency; n > 1 {
+		if n > s.maxHashConcurrency {
+			n = s.maxHashConcurrency
+		}
+		j.SetValue(keyHashConcurrency, n)
+	}
+	if opt.CacheMountStats {
+		j.SetValue(keyCacheMountStats, true)
+	}
+	imagePolicy, err := sessionimagepolicy.GetPolicy(ctx, s.sm, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if imagePolicy.Enabled() {
+		j.SetValue(keyImagePolicy, imagePolicy)
+	}
+	if sessionID != "" {
+		if bundle := cabundle.GetCABundle(ctx, s.sm, session.NewGroup(sessionID)); bundle != nil {
+			j.SetValue(keyCABundle, bundle.Digest().String())
+		}
+		if cfg := proxy.GetConfig(ctx, s.sm, session.NewGroup(sessionID)); cfg != nil {
+			j.SetValue(keyProxyConfig, cfg.Digest().String())
+		}
+	}
+	j.SetValue(keyMetadataStore, newMetadataStore())
+	sources := newSourcesRecorder()
+	j.SetValue(keySources, sources)
+	warnings := newWarningCollector()
+	j.SetValue(keyWarnings, warnings)
 
 	j.SessionID = sessionID
 
@@ -142,6 +377,12 @@ func (s *Solver) Solve(ctx context.Context, id string, sessionID string, req fro
 		})
 	}()
 
+	if name, ok := req.FrontendOpt[keyExportRef]; ok {
+		if err := selectExportRef(res, name); err != nil {
+			return nil, err
+		}
+	}
+
 	eg, ctx2 := errgroup.WithContext(ctx)
 	res.EachRef(func(ref solver.ResultProxy) error {
 		eg.Go(func() error {
@@ -154,14 +395,21 @@ func (s *Solver) Solve(ctx context.Context, id string, sessionID string, req fro
 		return nil, err
 	}
 
+	eg, ctx2 = errgroup.WithContext(ctx)
+
 	var exporterResponse map[string]string
-	if e := exp.Exporter; e != nil {
+	if len(exp.Exporters) > 0 {
 		inp := exporter.Source{
 			Metadata: res.Metadata,
 		}
 		if inp.Metadata == nil {
 			inp.Metadata = make(map[string][]byte)
 		}
+		dt, err := provenancePredicate(req, res, startedOn)
+		if err != nil {
+			return nil, err
+		}
+		inp.Metadata[exptypes.ExporterProvenanceKey] = dt
 		if res := res.Ref; res != nil {
 			r, err := res.Result(ctx)
 			if err != nil {
@@ -209,40 +457,71 @@ func (s *Solver) Solve(ctx context.Context, id string, sessionID string, req fro
 			inp.Refs = m
 		}
 
-		if err := inBuilderContext(ctx, j, e.Name(), "", func(ctx context.Context, _ session.Group) error {
-			exporterResponse, err = e.Export(ctx, inp, j.SessionID)
-			return err
-		}); err != nil {
-			return nil, err
-		}
+		// the image and the cache are exported concurrently. Pushes of blobs
+		// that are shared by both wait for each other so every blob is only
+		// uploaded once.
+		eg.Go(func() error {
+			exporterResponse = map[string]string{}
+			for i, e := range exp.Exporters {
+				if err := inBuilderContext(ctx2, j, e.Name(), "", func(ctx context.Context, _ session.Group) error {
+					if len(exp.Exporters) > 1 {
+						ctx = filesync.WithExporterID(ctx, strconv.Itoa(i))
+					}
+					ctx = withWarnings(ctx, j, "")
+					inp := inp
+					inp.ExporterResponses = exporterResponse
+					resp, err := e.Export(ctx, inp, j.SessionID)
+					if err != nil {
+						return err
+					}
+					merged := make(map[string]string, len(exporterResponse)+len(resp))
+					for k, v := range exporterResponse {
+						merged[k] = v
+					}
+					for k, v := range resp {
+						merged[k] = v
+					}
+					exporterResponse = merged
+					return nil
+				}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
 	}
 
 	g := session.NewGroup(j.SessionID)
 	var cacheExporterResponse map[string]string
 	if e := exp.CacheExporter; e != nil {
-		if err := inBuilderContext(ctx, j, "exporting cache", "", func(ctx context.Context, _ session.Group) error {
-			prepareDone := oneOffProgress(ctx, "preparing build cache for export")
-			if err := res.EachRef(func(res solver.ResultProxy) error {
-				r, err := res.Result(ctx)
-				if err != nil {
+		eg.Go(func() error {
+			return inBuilderContext(ctx2, j, "exporting cache", "", func(ctx context.Context, _ session.Group) error {
+				prepareDone := oneOffProgress(ctx, "preparing build cache for export")
+				if err := res.EachRef(func(res solver.ResultProxy) error {
+					r, err := res.Result(ctx)
+					if err != nil {
+						return err
+					}
+					// all keys have same export chain so exporting others is not needed
+					_, err = r.CacheKeys()[0].Exporter.ExportTo(ctx, e, solver.CacheExportOpt{
+						Convert: workerRefConverter(g),
+						Mode:    exp.CacheExportMode,
+						Session: g,
+					})
 					return err
+				}); err != nil {
+					return prepareDone(err)
 				}
-				// all keys have same export chain so exporting others is not needed
-				_, err = r.CacheKeys()[0].Exporter.ExportTo(ctx, e, solver.CacheExportOpt{
-					Convert: workerRefConverter(g),
-					Mode:    exp.CacheExportMode,
-					Session: g,
-				})
+				prepareDone(nil)
+				var err error
+				cacheExporterResponse, err = e.Finalize(ctx)
 				return err
-			}); err != nil {
-				return prepareDone(err)
-			}
-			prepareDone(nil)
-			cacheExporterResponse, err = e.Finalize(ctx)
-			return err
-		}); err != nil {
-			return nil, err
-		}
+			})
+		})
+	}
+
+	if err := eg.Wait(); err != nil {
+		return nil, err
 	}
 
 	if exporterResponse == nil {
@@ -259,12 +538,50 @@ func (s *Solver) Solve(ctx context.Context, id string, sessionID string, req fro
 			exporterResponse[k] = v
 		}
 	}
+	labels, err := provenance.BuildLabels(resultDefinitions(req, res))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range labels {
+		exporterResponse[pb.BuildLabelPrefix+k] = v
+	}
 
 	return &client.SolveResponse{
 		ExporterResponse: exporterResponse,
+		Sources:          sources.sources(),
+		Warnings:         warnings.all(),
 	}, nil
 }
 
+// resultDefinitions returns the definitions that were solved for the result
+// of the build
+func resultDefinitions(req frontend.SolveRequest, res *frontend.Result) []*pb.Definition {
+	var defs []*pb.Definition
+	if req.Definition != nil {
+		defs = append(defs, req.Definition)
+	}
+	res.EachRef(func(ref solver.ResultProxy) error {
+		defs = append(defs, ref.Definition())
+		return nil
+	})
+	return defs
+}
+
+// provenancePredicate returns the JSON encoded provenance predicate of the
+// build that is made available to the exporters
+func provenancePredicate(req frontend.SolveRequest, res *frontend.Result, startedOn time.Time) ([]byte, error) {
+	p, err := provenance.NewPredicate(req.Frontend, req.FrontendOpt, resultDefinitions(req, res))
+	if err != nil {
+		return nil, err
+	}
+	finishedOn := time.Now()
+	p.Metadata = &provenance.Metadata{
+		BuildStartedOn:  &startedOn,
+		BuildFinishedOn: &finishedOn,
+	}
+	return json.Marshal(p)
+}
+
 func inlineCache(ctx context.Context, e remotecache.Exporter, res solver.CachedResult, g session.Group) ([]byte, error) {
 	if efl, ok := e.(interface {
 		ExportForLayers([]digest.Digest) ([]byte, error)
@@ -306,6 +623,35 @@ func (s *Solver) Status(ctx context.Context, id string, statusChan chan *client.
 	return j.Status(ctx, statusChan)
 }
 
+// Cancel cancels the active build id
+func (s *Solver) Cancel(id string) error {
+	return s.solver.Cancel(id)
+}
+
+// BuildInfo describes an active build
+type BuildInfo struct {
+	Ref       string
+	StartedAt time.Time
+	Frontend  string
+}
+
+// ListBuilds returns the active builds by the time they started
+func (s *Solver) ListBuilds() []BuildInfo {
+	jobs := s.solver.ActiveJobs()
+	out := make([]BuildInfo, 0, len(jobs))
+	for _, j := range jobs {
+		out = append(out, BuildInfo{
+			Ref:       j.ID(),
+			StartedAt: j.StartedAt(),
+			Frontend:  j.Frontend,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].StartedAt.Before(out[j].StartedAt)
+	})
+	return out
+}
+
 func defaultResolver(wc *worker.Controller) ResolveWorkerFunc {
 	return func() (worker.Worker, error) {
 		return wc.GetDefault()
@@ -399,6 +745,75 @@ func supportedEntitlements(ents []string) []entitlements.Entitlement {
 	return out
 }
 
+func loadCacheNamespace(b solver.Builder) (string, error) {
+	return loadStringValue(b, keyCacheNamespace)
+}
+
+func loadCheckpointID(b solver.Builder) (string, error) {
+	return loadStringValue(b, keyCheckpointID)
+}
+
+func loadCABundle(b solver.Builder) (string, error) {
+	return loadStringValue(b, keyCABundle)
+}
+
+func loadProxyConfig(b solver.Builder) (string, error) {
+	return loadStringValue(b, keyProxyConfig)
+}
+
+func loadStringValue(b solver.Builder, key string) (string, error) {
+	var val string
+	err := b.EachValue(context.TODO(), key, func(v interface{}) error {
+		s, ok := v.(string)
+		if !ok {
+			return errors.Errorf("invalid value %T for %s", v, key)
+		}
+		val = s
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return val, nil
+}
+
+// selectExportRef replaces the refs of the result with the ref named name. The
+// metadata of the ref, e.g. containerimage.config/<name>, is used as the
+// metadata of the result.
+func selectExportRef(res *frontend.Result, name string) error {
+	ref, ok := res.Refs[name]
+	if !ok {
+		names := make([]string, 0, len(res.Refs))
+		for k := range res.Refs {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return errors.Errorf("result %q not found, the frontend returned no named results", name)
+		}
+		return errors.Errorf("result %q not found, available results: %s", name, strings.Join(names, ", "))
+	}
+
+	if res.Ref != nil {
+		go res.Ref.Release(context.TODO())
+	}
+	for k, r := range res.Refs {
+		if k != name && r != nil {
+			go r.Release(context.TODO())
+		}
+	}
+	res.Ref = ref
+	res.Refs = nil
+
+	suffix := "/" + name
+	for k, v := range res.Metadata {
+		if strings.HasSuffix(k, suffix) {
+			res.Metadata[strings.TrimSuffix(k, suffix)] = v
+		}
+	}
+	return nil
+}
+
 func loadEntitlements(b solver.Builder) (entitlements.Set, error) {
 	var ent entitlements.Set = map[entitlements.Entitlement]struct{}{}
 	err := b.EachValue(context.TODO(), keyEntitlements, func(v interface{}) error {
@@ -0,0 +1,63 @@
+package llbsolver
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/moby/buildkit/frontend"
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectExportRef(t *testing.T) {
+	t.Parallel()
+
+	var wg sync.WaitGroup
+	foo, bar := &testResultProxy{wg: &wg}, &testResultProxy{wg: &wg}
+	res := &frontend.Result{
+		Refs: map[string]solver.ResultProxy{"foo": foo, "bar": bar},
+		Metadata: map[string][]byte{
+			"containerimage.config/foo": []byte("fooconfig"),
+			"containerimage.config/bar": []byte("barconfig"),
+		},
+	}
+
+	err := selectExportRef(res, "baz")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `result "baz" not found, available results: bar, foo`)
+	require.Equal(t, 2, len(res.Refs))
+
+	wg.Add(1)
+	require.NoError(t, selectExportRef(res, "foo"))
+	wg.Wait()
+	require.Equal(t, foo, res.Ref)
+	require.Nil(t, res.Refs)
+	require.Equal(t, "fooconfig", string(res.Metadata["containerimage.config"]))
+	require.False(t, foo.released)
+	require.True(t, bar.released)
+
+	err = selectExportRef(&frontend.Result{}, "foo")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no named results")
+}
+
+type testResultProxy struct {
+	wg       *sync.WaitGroup
+	released bool
+}
+
+func (p *testResultProxy) Result(context.Context) (solver.CachedResult, error) {
+	return nil, nil
+}
+
+func (p *testResultProxy) Release(context.Context) error {
+	p.released = true
+	p.wg.Done()
+	return nil
+}
+
+func (p *testResultProxy) Definition() *pb.Definition {
+	return nil
+}
@@ -0,0 +1,81 @@
+package llbsolver
+
+import (
+	"context"
+	"sync"
+
+	"github.com/moby/buildkit/solver"
+	digest "github.com/opencontainers/go-digest"
+)
+
+const keySources = "llb.sources"
+
+// sourcesRecorder collects the digests that the sources of a build were
+// resolved to. It is a value of the job of the build.
+type sourcesRecorder struct {
+	mu sync.Mutex
+	m  map[string]digest.Digest
+}
+
+func newSourcesRecorder() *sourcesRecorder {
+	return &sourcesRecorder{m: map[string]digest.Digest{}}
+}
+
+func (r *sourcesRecorder) record(id string, dgst digest.Digest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.m[id] = dgst
+}
+
+func (r *sourcesRecorder) sources() map[string]digest.Digest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m := make(map[string]digest.Digest, len(r.m))
+	for k, v := range r.m {
+		m[k] = v
+	}
+	return m
+}
+
+// resolvedSource is implemented by the ops of sources that can report the
+// digest that they were resolved to
+type resolvedSource interface {
+	ResolvedSource() (string, digest.Digest)
+}
+
+// recordSource records the resolved digest of a source op in all the builds
+// that share the vertex once its cache key is known
+func recordSource(ctx context.Context, b solver.Builder, op solver.Op) error {
+	return eachResolvedSource(op, func(id string, dgst digest.Digest) error {
+		return b.EachValue(ctx, keySources, recordFunc(id, dgst))
+	})
+}
+
+// recordJobSource records the resolved digest of a source op in a build that
+// loaded the vertex after the op was resolved for another build
+func recordJobSource(j *solver.Job, op solver.Op) {
+	eachResolvedSource(op, func(id string, dgst digest.Digest) error {
+		return j.EachValue(context.TODO(), keySources, recordFunc(id, dgst))
+	})
+}
+
+func eachResolvedSource(op solver.Op, fn func(string, digest.Digest) error) error {
+	rs, ok := op.(resolvedSource)
+	if !ok {
+		return nil
+	}
+	id, dgst := rs.ResolvedSource()
+	if dgst == "" {
+		return nil
+	}
+	return fn(id, dgst)
+}
+
+func recordFunc(id string, dgst digest.Digest) func(interface{}) error {
+	return func(v interface{}) error {
+		if r, ok := v.(*sourcesRecorder); ok {
+			r.record(id, dgst)
+		}
+		return nil
+	}
+}
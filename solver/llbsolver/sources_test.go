@@ -0,0 +1,67 @@
+package llbsolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/solver"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordSource(t *testing.T) {
+	t.Parallel()
+
+	s := solver.NewSolver(solver.SolverOpt{DefaultCache: solver.NewInMemoryCacheManager()})
+	defer s.Close()
+
+	j, err := s.NewJob("job1")
+	require.NoError(t, err)
+	defer j.Discard()
+	r := newSourcesRecorder()
+	j.SetValue(keySources, r)
+
+	dgst := digest.FromString("manifest")
+	op := &vertexOp{Op: &testSourceOp{id: "docker-image://docker.io/library/busybox:latest", dgst: dgst}, b: j}
+	_, _, err = op.CacheMap(context.TODO(), nil, 0)
+	require.NoError(t, err)
+
+	op = &vertexOp{Op: &testSourceOp{id: "local://context"}, b: j}
+	_, _, err = op.CacheMap(context.TODO(), nil, 0)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]digest.Digest{"docker-image://docker.io/library/busybox:latest": dgst}, r.sources())
+
+	// builds attaching to the vertex later get the resolved source too
+	j2, err := s.NewJob("job2")
+	require.NoError(t, err)
+	defer j2.Discard()
+	r2 := newSourcesRecorder()
+	j2.SetValue(keySources, r2)
+
+	op = &vertexOp{Op: &testSourceOp{id: "git://github.com/moby/buildkit", dgst: dgst}, b: j}
+	op.AttachJob(j2)
+	require.Equal(t, map[string]digest.Digest{"git://github.com/moby/buildkit": dgst}, r2.sources())
+}
+
+type testSourceOp struct {
+	id   string
+	dgst digest.Digest
+}
+
+func (o *testSourceOp) CacheMap(context.Context, session.Group, int) (*solver.CacheMap, bool, error) {
+	return &solver.CacheMap{Digest: digest.FromString(o.id)}, true, nil
+}
+
+func (o *testSourceOp) Exec(context.Context, session.Group, []solver.Result) ([]solver.Result, error) {
+	return nil, nil
+}
+
+func (o *testSourceOp) Acquire(context.Context) (solver.ReleaseFunc, error) {
+	return func() {}, nil
+}
+
+func (o *testSourceOp) ResolvedSource() (string, digest.Digest) {
+	return o.id, o.dgst
+}
@@ -68,6 +68,26 @@ func WithCacheSources(cms []solver.CacheManager) LoadOpt {
 	}
 }
 
+func WithCacheNamespace(ns string) LoadOpt {
+	return func(_ *pb.Op, _ *pb.OpMetadata, opt *solver.VertexOptions) error {
+		opt.CacheNamespace = ns
+		return nil
+	}
+}
+
+// WithCheckpointID makes the results of the vertexes that ignore the cache
+// reusable by the builds that resume from the same checkpoint. Other builds
+// still don't share these results.
+func WithCheckpointID(id string) LoadOpt {
+	return func(_ *pb.Op, _ *pb.OpMetadata, opt *solver.VertexOptions) error {
+		if id != "" && opt.IgnoreCache {
+			opt.IgnoreCache = false
+			opt.CacheNamespace += "/checkpoint:" + id
+		}
+		return nil
+	}
+}
+
 func NormalizeRuntimePlatforms() LoadOpt {
 	var defaultPlatform *pb.Platform
 	return func(op *pb.Op, _ *pb.OpMetadata, opt *solver.VertexOptions) error {
@@ -110,6 +130,14 @@ func ValidateEntitlements(ent entitlements.Set) LoadOpt {
 					return errors.Errorf("%s is not allowed", entitlements.EntitlementSecurityInsecure)
 				}
 			}
+
+			// custom seccomp profiles can allow the same syscalls as
+			// running without seccomp
+			if s := op.Exec.Seccomp; s != nil && (s.Unconfined || s.Profile != "") {
+				if !ent.Allowed(entitlements.EntitlementSecurityInsecure) {
+					return errors.Errorf("%s is not allowed", entitlements.EntitlementSecurityInsecure)
+				}
+			}
 		}
 		return nil
 	}
@@ -144,7 +172,7 @@ func (dpc *detectPrunedCacheID) Load(op *pb.Op, md *pb.OpMetadata, opt *solver.V
 }
 
 func Load(def *pb.Definition, opts ...LoadOpt) (solver.Edge, error) {
-	return loadLLB(def, func(dgst digest.Digest, pbOp *pb.Op, load func(digest.Digest) (solver.Vertex, error)) (solver.Vertex, error) {
+	e, err := loadLLB(def, func(dgst digest.Digest, pbOp *pb.Op, load func(digest.Digest) (solver.Vertex, error)) (solver.Vertex, error) {
 		opMetadata := def.Metadata[dgst]
 		vtx, err := newVertex(dgst, pbOp, &opMetadata, load, opts...)
 		if err != nil {
@@ -152,6 +180,11 @@ func Load(def *pb.Definition, opts ...LoadOpt) (solver.Edge, error) {
 		}
 		return vtx, nil
 	})
+	if err != nil {
+		return e, err
+	}
+	setCriticalPath(e)
+	return e, nil
 }
 
 func newVertex(dgst digest.Digest, op *pb.Op, opMeta *pb.OpMetadata, load func(digest.Digest) (solver.Vertex, error), opts ...LoadOpt) (*vertex, error) {
@@ -162,6 +195,7 @@ func newVertex(dgst digest.Digest, op *pb.Op, opMeta *pb.OpMetadata, load func(d
 		if opMeta.ExportCache != nil {
 			opt.ExportCache = &opMeta.ExportCache.Value
 		}
+		opt.ProgressGroup = opMeta.ProgressGroup
 	}
 	for _, fn := range opts {
 		if err := fn(op, opMeta, &opt); err != nil {
@@ -254,7 +288,7 @@ func llbOpName(op *pb.Op) string {
 		}
 		return op.Source.Identifier
 	case *pb.Op_Exec:
-		return strings.Join(op.Exec.Meta.Args, " ")
+		return strings.Join(op.Exec.Meta.ProcessArgs(), " ")
 	case *pb.Op_File:
 		return fileOpName(op.File.Actions)
 	case *pb.Op_Build:
@@ -281,12 +315,15 @@ func ValidateOp(op *pb.Op) error {
 		if op.Exec.Meta == nil {
 			return errors.Errorf("invalid exec op with no meta")
 		}
-		if len(op.Exec.Meta.Args) == 0 {
+		if len(op.Exec.Meta.Args) == 0 && op.Exec.Meta.Entrypoint == "" {
 			return errors.Errorf("invalid exec op with no args")
 		}
 		if len(op.Exec.Mounts) == 0 {
 			return errors.Errorf("invalid exec op with no mounts")
 		}
+		if _, err := op.Exec.Meta.ParseUmask(); err != nil {
+			return err
+		}
 
 		isRoot := false
 		for _, m := range op.Exec.Mounts {
@@ -298,6 +335,23 @@ func ValidateOp(op *pb.Op) error {
 		if !isRoot {
 			return errors.Errorf("invalid exec op with no rootfs")
 		}
+		if sp := op.Exec.SharedPID; sp != nil {
+			if sp.Meta == nil || len(sp.Meta.Args) == 0 && sp.Meta.Entrypoint == "" {
+				return errors.Errorf("invalid shared pid process with no args")
+			}
+			isRoot := false
+			for _, m := range sp.Mounts {
+				if m.Dest == pb.RootMount {
+					isRoot = true
+				}
+				if m.Output != pb.SkipOutput {
+					return errors.Errorf("invalid shared pid process mount %s with output", m.Dest)
+				}
+			}
+			if !isRoot {
+				return errors.Errorf("invalid shared pid process with no rootfs")
+			}
+		}
 	case *pb.Op_File:
 		if op.File == nil {
 			return errors.Errorf("invalid nil file op")
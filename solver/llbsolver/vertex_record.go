@@ -0,0 +1,31 @@
+package llbsolver
+
+import (
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/worker"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/sirupsen/logrus"
+)
+
+// recordVertex records the vertex that created the refs of the results of an
+// op so that the layers of an export can be traced back to the vertex that
+// produced them. Refs that are only passed through by the op keep the vertex
+// that created them.
+func recordVertex(res []solver.Result, vtx digest.Digest) {
+	for _, r := range res {
+		if r == nil {
+			continue
+		}
+		workerRef, ok := r.Sys().(*worker.WorkerRef)
+		if !ok || workerRef.ImmutableRef == nil {
+			continue
+		}
+		if cache.GetVertex(workerRef.ImmutableRef) != "" {
+			continue
+		}
+		if err := cache.SetVertex(workerRef.ImmutableRef, vtx); err != nil {
+			logrus.Warnf("failed to record vertex of %s: %v", workerRef.ImmutableRef.ID(), err)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package llbsolver
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCheckpointID(t *testing.T) {
+	t.Parallel()
+
+	opt := solver.VertexOptions{IgnoreCache: true}
+	require.NoError(t, WithCheckpointID("")(&pb.Op{}, nil, &opt))
+	require.True(t, opt.IgnoreCache)
+	require.Equal(t, "", opt.CacheNamespace)
+
+	require.NoError(t, WithCheckpointID("ckpt1")(&pb.Op{}, nil, &opt))
+	require.False(t, opt.IgnoreCache)
+	require.Equal(t, "/checkpoint:ckpt1", opt.CacheNamespace)
+
+	// vertexes that use the cache are not affected
+	opt = solver.VertexOptions{CacheNamespace: "ns"}
+	require.NoError(t, WithCheckpointID("ckpt1")(&pb.Op{}, nil, &opt))
+	require.False(t, opt.IgnoreCache)
+	require.Equal(t, "ns", opt.CacheNamespace)
+}
@@ -0,0 +1,85 @@
+package llbsolver
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/solver"
+	digest "github.com/opencontainers/go-digest"
+)
+
+const keyWarnings = "llb.warnings"
+
+// warningCollector collects the warnings of a build. It is a value of the job
+// of the build.
+type warningCollector struct {
+	mu       sync.Mutex
+	seen     map[string]struct{}
+	warnings []client.Warning
+}
+
+func newWarningCollector() *warningCollector {
+	return &warningCollector{seen: map[string]struct{}{}}
+}
+
+// add records a warning. Frontends that are called multiple times, e.g. once
+// for every platform, report the same warning only once.
+func (c *warningCollector) add(w client.Warning) {
+	dt, err := json.Marshal(w)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.seen[string(dt)]; ok {
+		return
+	}
+	c.seen[string(dt)] = struct{}{}
+	c.warnings = append(c.warnings, w)
+}
+
+func (c *warningCollector) all() []client.Warning {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]client.Warning(nil), c.warnings...)
+}
+
+// addWarning records the warning in all the builds of the builder
+func addWarning(ctx context.Context, b solver.Builder, w client.Warning) error {
+	return b.EachValue(ctx, keyWarnings, func(v interface{}) error {
+		if c, ok := v.(*warningCollector); ok {
+			c.add(w)
+		}
+		return nil
+	})
+}
+
+type warningsKey struct{}
+
+type warningsSink struct {
+	b   solver.Builder
+	vtx digest.Digest
+}
+
+// Warn records a warning of the op that is running with ctx in all the builds
+// that share its vertex, or a warning of the build that is exporting with ctx.
+// The warning is dropped if ctx doesn't belong to an op that was run by the
+// solver or an exporter.
+func Warn(ctx context.Context, w client.Warning) {
+	s, ok := ctx.Value(warningsKey{}).(*warningsSink)
+	if !ok {
+		return
+	}
+	if w.Vertex == "" {
+		w.Vertex = s.vtx
+	}
+	addWarning(ctx, s.b, w)
+}
+
+// withWarnings makes Warn record the warnings of the op running with ctx in
+// the builds of the vertex
+func withWarnings(ctx context.Context, b solver.Builder, vtx digest.Digest) context.Context {
+	return context.WithValue(ctx, warningsKey{}, &warningsSink{b: b, vtx: vtx})
+}
@@ -0,0 +1,61 @@
+package llbsolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/solver"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarnings(t *testing.T) {
+	t.Parallel()
+
+	s := solver.NewSolver(solver.SolverOpt{DefaultCache: solver.NewInMemoryCacheManager()})
+	defer s.Close()
+
+	j, err := s.NewJob("job1")
+	require.NoError(t, err)
+	defer j.Discard()
+	c := newWarningCollector()
+	j.SetValue(keyWarnings, c)
+
+	vtx := digest.FromString("vertex")
+	op := &vertexOp{Op: &testWarnOp{}, b: j, vtx: vtx}
+	for i := 0; i < 2; i++ {
+		_, err = op.Exec(context.TODO(), nil, nil)
+		require.NoError(t, err)
+	}
+
+	// warnings of contexts that don't belong to an op are dropped
+	Warn(context.TODO(), client.Warning{Code: "Dropped"})
+
+	require.NoError(t, addWarning(context.TODO(), j, client.Warning{Code: "Frontend", Message: "frontend warning"}))
+
+	// exporters warn without a vertex
+	Warn(withWarnings(context.TODO(), j, ""), client.Warning{Code: "Export", Message: "export warning"})
+
+	require.Equal(t, []client.Warning{
+		{Code: "Test", Message: "op warning", Vertex: vtx},
+		{Code: "Frontend", Message: "frontend warning"},
+		{Code: "Export", Message: "export warning"},
+	}, c.all())
+}
+
+type testWarnOp struct{}
+
+func (o *testWarnOp) CacheMap(context.Context, session.Group, int) (*solver.CacheMap, bool, error) {
+	return &solver.CacheMap{Digest: digest.FromString("warn")}, true, nil
+}
+
+func (o *testWarnOp) Exec(ctx context.Context, _ session.Group, _ []solver.Result) ([]solver.Result, error) {
+	Warn(ctx, client.Warning{Code: "Test", Message: "op warning"})
+	return nil, nil
+}
+
+func (o *testWarnOp) Acquire(context.Context) (solver.ReleaseFunc, error) {
+	return func() {}, nil
+}
@@ -228,6 +228,32 @@ func (s *inMemoryStore) WalkLinks(id string, link CacheInfoLink, fn func(id stri
 	return nil
 }
 
+// WalkAllLinks calls fn for every link of the key with the given ID
+func (s *inMemoryStore) WalkAllLinks(id string, fn func(link CacheInfoLink, target string) error) error {
+	s.mu.RLock()
+	k, ok := s.byID[id]
+	if !ok {
+		s.mu.RUnlock()
+		return nil
+	}
+	var links []CacheInfoLink
+	var targets []string
+	for l, m := range k.links {
+		for target := range m {
+			links = append(links, l)
+			targets = append(targets, target)
+		}
+	}
+	s.mu.RUnlock()
+
+	for i := range links {
+		if err := fn(links[i], targets[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *inMemoryStore) HasLink(id string, link CacheInfoLink, target string) bool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
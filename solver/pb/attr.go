@@ -12,6 +12,9 @@ const AttrIncludePatterns = "local.includepattern"
 const AttrFollowPaths = "local.followpaths"
 const AttrExcludePatterns = "local.excludepatterns"
 const AttrSharedKeyHint = "local.sharedkeyhint"
+const AttrLocalPreserveOwnership = "local.preserveownership"
+const AttrLocalUIDMap = "local.uidmap"
+const AttrLocalGIDMap = "local.gidmap"
 
 const AttrLLBDefinitionFilename = "llbbuild.filename"
 
@@ -20,15 +23,38 @@ const AttrHTTPFilename = "http.filename"
 const AttrHTTPPerm = "http.perm"
 const AttrHTTPUID = "http.uid"
 const AttrHTTPGID = "http.gid"
+const AttrHTTPMirrors = "http.mirrors"
+
+// AttrHTTPHeaderPrefix is the prefix of the attributes that set the headers
+// of the request, e.g. "http.header.Accept"
+const AttrHTTPHeaderPrefix = "http.header."
+
+// AttrHTTPSecretHeaderPrefix is the prefix of the attributes that set the
+// headers of the request to the value of a session secret. The value of the
+// attribute is the ID of the secret, e.g. "http.secretheader.Authorization"
+const AttrHTTPSecretHeaderPrefix = "http.secretheader."
 
 const AttrImageResolveMode = "image.resolvemode"
 const AttrImageResolveModeDefault = "default"
 const AttrImageResolveModeForcePull = "pull"
 const AttrImageResolveModePreferLocal = "local"
+const AttrImageResolveModeLocalOnly = "local-only"
 const AttrImageRecordType = "image.recordtype"
+const AttrImageMirrorPreference = "image.mirrorpreference"
+
+const AttrOCILayoutStoreID = "oci.store"
+
+// BuildLabelPrefix is the prefix of the keys of the op metadata description
+// that hold the build labels of the vertex
+const BuildLabelPrefix = "llb.buildlabel."
 
 const AttrLocalDiffer = "local.differ"
 const AttrLocalDifferNone = "none"
 const AttrLocalDifferMetadata = "metadata"
 
+const AttrLocalSymlinkMode = "local.symlinkmode"
+const AttrLocalSymlinkModePreserve = "preserve"
+const AttrLocalSymlinkModeDereference = "dereference"
+const AttrLocalSymlinkModeError = "error"
+
 type IsFileAction = isFileAction_Action
@@ -9,16 +9,20 @@ var Caps apicaps.CapList
 // considered immutable. After a capability is marked stable it should not be disabled.
 
 const (
-	CapSourceImage                apicaps.CapID = "source.image"
-	CapSourceImageResolveMode     apicaps.CapID = "source.image.resolvemode"
-	CapSourceLocal                apicaps.CapID = "source.local"
-	CapSourceLocalUnique          apicaps.CapID = "source.local.unique"
-	CapSourceLocalSessionID       apicaps.CapID = "source.local.sessionid"
-	CapSourceLocalIncludePatterns apicaps.CapID = "source.local.includepatterns"
-	CapSourceLocalFollowPaths     apicaps.CapID = "source.local.followpaths"
-	CapSourceLocalExcludePatterns apicaps.CapID = "source.local.excludepatterns"
-	CapSourceLocalSharedKeyHint   apicaps.CapID = "source.local.sharedkeyhint"
-	CapSourceLocalDiffer          apicaps.CapID = "source.local.differ"
+	CapSourceImage                     apicaps.CapID = "source.image"
+	CapSourceImageResolveMode          apicaps.CapID = "source.image.resolvemode"
+	CapSourceImageResolveModeLocalOnly apicaps.CapID = "source.image.resolvemode.localonly"
+	CapSourceImageMirrorPreference     apicaps.CapID = "source.image.mirrorpreference"
+	CapSourceLocal                     apicaps.CapID = "source.local"
+	CapSourceLocalUnique               apicaps.CapID = "source.local.unique"
+	CapSourceLocalSessionID            apicaps.CapID = "source.local.sessionid"
+	CapSourceLocalIncludePatterns      apicaps.CapID = "source.local.includepatterns"
+	CapSourceLocalFollowPaths          apicaps.CapID = "source.local.followpaths"
+	CapSourceLocalExcludePatterns      apicaps.CapID = "source.local.excludepatterns"
+	CapSourceLocalSharedKeyHint        apicaps.CapID = "source.local.sharedkeyhint"
+	CapSourceLocalDiffer               apicaps.CapID = "source.local.differ"
+	CapSourceLocalPreserveOwnership    apicaps.CapID = "source.local.preserveownership"
+	CapSourceLocalSymlinkMode          apicaps.CapID = "source.local.symlinkmode"
 
 	CapSourceGit              apicaps.CapID = "source.git"
 	CapSourceGitKeepDir       apicaps.CapID = "source.git.keepgitdir"
@@ -28,18 +32,25 @@ const (
 	CapSourceGitMountSSHSock  apicaps.CapID = "source.git.mountsshsock"
 	CapSourceGitSubdir        apicaps.CapID = "source.git.subdir"
 
-	CapSourceHTTP         apicaps.CapID = "source.http"
-	CapSourceHTTPChecksum apicaps.CapID = "source.http.checksum"
-	CapSourceHTTPPerm     apicaps.CapID = "source.http.perm"
-	CapSourceHTTPUIDGID   apicaps.CapID = "soruce.http.uidgid"
+	CapSourceHTTP             apicaps.CapID = "source.http"
+	CapSourceHTTPChecksum     apicaps.CapID = "source.http.checksum"
+	CapSourceHTTPPerm         apicaps.CapID = "source.http.perm"
+	CapSourceHTTPUIDGID       apicaps.CapID = "soruce.http.uidgid"
+	CapSourceHTTPMirrors      apicaps.CapID = "source.http.mirrors"
+	CapSourceHTTPHeader       apicaps.CapID = "source.http.header"
+	CapSourceHTTPSecretHeader apicaps.CapID = "source.http.secretheader"
+
+	CapSourceOCILayout apicaps.CapID = "source.ocilayout"
 
 	CapBuildOpLLBFileName apicaps.CapID = "source.buildop.llbfilename"
+	CapBuildOpDefinition  apicaps.CapID = "source.buildop.definition"
 
 	CapExecMetaBase                  apicaps.CapID = "exec.meta.base"
 	CapExecMetaProxy                 apicaps.CapID = "exec.meta.proxyenv"
 	CapExecMetaNetwork               apicaps.CapID = "exec.meta.network"
 	CapExecMetaSecurity              apicaps.CapID = "exec.meta.security"
 	CapExecMetaSetsDefaultPath       apicaps.CapID = "exec.meta.setsdefaultpath"
+	CapExecMetaEntrypoint            apicaps.CapID = "exec.meta.entrypoint"
 	CapExecMountBind                 apicaps.CapID = "exec.mount.bind"
 	CapExecMountBindReadWriteNoOuput apicaps.CapID = "exec.mount.bind.readwrite-nooutput"
 	CapExecMountCache                apicaps.CapID = "exec.mount.cache"
@@ -48,20 +59,41 @@ const (
 	CapExecMountTmpfs                apicaps.CapID = "exec.mount.tmpfs"
 	CapExecMountSecret               apicaps.CapID = "exec.mount.secret"
 	CapExecMountSSH                  apicaps.CapID = "exec.mount.ssh"
+	CapExecSecretEnv                 apicaps.CapID = "exec.secretenv"
+	CapExecMountHostPath             apicaps.CapID = "exec.mount.hostpath"
 	CapExecCgroupsMounted            apicaps.CapID = "exec.cgroup"
+	CapExecAllowedExitCodes          apicaps.CapID = "exec.allowedexitcodes"
+	CapExecMetaSeccomp               apicaps.CapID = "exec.meta.seccomp"
+	CapExecMetaDevices               apicaps.CapID = "exec.meta.devices"
+	CapExecMetaCacheIgnoreEnv        apicaps.CapID = "exec.meta.cacheignoreenv"
+	CapExecMetaUmask                 apicaps.CapID = "exec.meta.umask"
+	CapExecMetaPassthroughEnv        apicaps.CapID = "exec.meta.passthroughenv"
+	CapExecMetaTimezone              apicaps.CapID = "exec.meta.timezone"
+	CapExecMetaRedirect              apicaps.CapID = "exec.meta.redirect"
+	CapExecMetaApparmor              apicaps.CapID = "exec.meta.apparmor"
+	CapExecIgnoreForCache            apicaps.CapID = "exec.ignoreforcache"
+	CapExecAfter                     apicaps.CapID = "exec.after"
+	CapExecMetaResources             apicaps.CapID = "exec.meta.resources"
+	CapExecSharedPID                 apicaps.CapID = "exec.sharedpid"
+	CapExecRetry                     apicaps.CapID = "exec.retry"
+	CapExecUserNSMapping             apicaps.CapID = "exec.usernsmapping"
+	CapExecSysctl                    apicaps.CapID = "exec.sysctl"
 
 	CapExecMetaSecurityDeviceWhitelistV1 apicaps.CapID = "exec.meta.security.devices.v1"
 
 	CapFileBase                       apicaps.CapID = "file.base"
 	CapFileRmWildcard                 apicaps.CapID = "file.rm.wildcard"
 	CapFileCopyIncludeExcludePatterns apicaps.CapID = "file.copy.includeexcludepatterns"
+	CapFileCopyTimestampNow           apicaps.CapID = "file.copy.timestampnow"
+	CapFileCopyDirMode                apicaps.CapID = "file.copy.dirmode"
 
 	CapConstraints apicaps.CapID = "constraints"
 	CapPlatform    apicaps.CapID = "platform"
 
-	CapMetaIgnoreCache apicaps.CapID = "meta.ignorecache"
-	CapMetaDescription apicaps.CapID = "meta.description"
-	CapMetaExportCache apicaps.CapID = "meta.exportcache"
+	CapMetaIgnoreCache   apicaps.CapID = "meta.ignorecache"
+	CapMetaDescription   apicaps.CapID = "meta.description"
+	CapMetaExportCache   apicaps.CapID = "meta.exportcache"
+	CapMetaProgressGroup apicaps.CapID = "meta.progress.group"
 )
 
 func init() {
@@ -78,6 +110,18 @@ func init() {
 		Status:  apicaps.CapStatusExperimental,
 	})
 
+	Caps.Init(apicaps.Cap{
+		ID:      CapSourceImageResolveModeLocalOnly,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapSourceImageMirrorPreference,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
 	Caps.Init(apicaps.Cap{
 		ID:      CapSourceLocal,
 		Enabled: true,
@@ -126,6 +170,18 @@ func init() {
 		Status:  apicaps.CapStatusExperimental,
 	})
 
+	Caps.Init(apicaps.Cap{
+		ID:      CapSourceLocalPreserveOwnership,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapSourceLocalSymlinkMode,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
 	Caps.Init(apicaps.Cap{
 		ID:      CapSourceGit,
 		Enabled: true,
@@ -192,12 +248,42 @@ func init() {
 		Status:  apicaps.CapStatusExperimental,
 	})
 
+	Caps.Init(apicaps.Cap{
+		ID:      CapSourceHTTPMirrors,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapSourceHTTPHeader,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapSourceHTTPSecretHeader,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapSourceOCILayout,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
 	Caps.Init(apicaps.Cap{
 		ID:      CapBuildOpLLBFileName,
 		Enabled: true,
 		Status:  apicaps.CapStatusExperimental,
 	})
 
+	Caps.Init(apicaps.Cap{
+		ID:      CapBuildOpDefinition,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
 	Caps.Init(apicaps.Cap{
 		ID:      CapExecMetaBase,
 		Enabled: true,
@@ -222,6 +308,12 @@ func init() {
 		Status:  apicaps.CapStatusExperimental,
 	})
 
+	Caps.Init(apicaps.Cap{
+		ID:      CapExecMetaEntrypoint,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
 	Caps.Init(apicaps.Cap{
 		ID:      CapExecMetaSecurity,
 		Enabled: true,
@@ -282,12 +374,120 @@ func init() {
 		Status:  apicaps.CapStatusExperimental,
 	})
 
+	Caps.Init(apicaps.Cap{
+		ID:      CapExecMountHostPath,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
 	Caps.Init(apicaps.Cap{
 		ID:      CapExecCgroupsMounted,
 		Enabled: true,
 		Status:  apicaps.CapStatusExperimental,
 	})
 
+	Caps.Init(apicaps.Cap{
+		ID:      CapExecAllowedExitCodes,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapExecMetaSeccomp,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapExecSecretEnv,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapExecMetaDevices,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapExecMetaCacheIgnoreEnv,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapExecMetaUmask,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapExecMetaPassthroughEnv,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapExecMetaTimezone,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapExecMetaApparmor,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapExecIgnoreForCache,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapExecMetaRedirect,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapExecAfter,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapExecMetaResources,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapExecSharedPID,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapExecRetry,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapExecUserNSMapping,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapExecSysctl,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
 	Caps.Init(apicaps.Cap{
 		ID:      CapFileBase,
 		Enabled: true,
@@ -310,6 +510,18 @@ func init() {
 		Status:  apicaps.CapStatusExperimental,
 	})
 
+	Caps.Init(apicaps.Cap{
+		ID:      CapFileCopyTimestampNow,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapFileCopyDirMode,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
+
 	Caps.Init(apicaps.Cap{
 		ID:      CapConstraints,
 		Enabled: true,
@@ -339,4 +551,10 @@ func init() {
 		Enabled: true,
 		Status:  apicaps.CapStatusExperimental,
 	})
+
+	Caps.Init(apicaps.Cap{
+		ID:      CapMetaProgressGroup,
+		Enabled: true,
+		Status:  apicaps.CapStatusExperimental,
+	})
 }
@@ -0,0 +1,30 @@
+package pb
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// ProcessArgs returns the argv of the process described by m, with the
+// entrypoint, if set, prepended to the args.
+func (m *Meta) ProcessArgs() []string {
+	if m.Entrypoint == "" {
+		return m.Args
+	}
+	return append([]string{m.Entrypoint}, m.Args...)
+}
+
+// ParseUmask returns the umask of the process described by m, or nil if the
+// default umask is used.
+func (m *Meta) ParseUmask() (*uint32, error) {
+	if m.Umask == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseUint(m.Umask, 8, 32)
+	if err != nil || v > 0777 {
+		return nil, errors.Errorf("invalid umask %q", m.Umask)
+	}
+	umask := uint32(v)
+	return &umask, nil
+}
@@ -86,11 +86,12 @@ func (SecurityMode) EnumDescriptor() ([]byte, []int) {
 type MountType int32
 
 const (
-	MountType_BIND   MountType = 0
-	MountType_SECRET MountType = 1
-	MountType_SSH    MountType = 2
-	MountType_CACHE  MountType = 3
-	MountType_TMPFS  MountType = 4
+	MountType_BIND     MountType = 0
+	MountType_SECRET   MountType = 1
+	MountType_SSH      MountType = 2
+	MountType_CACHE    MountType = 3
+	MountType_TMPFS    MountType = 4
+	MountType_HOSTPATH MountType = 5
 )
 
 var MountType_name = map[int32]string{
@@ -99,14 +100,16 @@ var MountType_name = map[int32]string{
 	2: "SSH",
 	3: "CACHE",
 	4: "TMPFS",
+	5: "HOSTPATH",
 }
 
 var MountType_value = map[string]int32{
-	"BIND":   0,
-	"SECRET": 1,
-	"SSH":    2,
-	"CACHE":  3,
-	"TMPFS":  4,
+	"BIND":     0,
+	"SECRET":   1,
+	"SSH":      2,
+	"CACHE":    3,
+	"TMPFS":    4,
+	"HOSTPATH": 5,
 }
 
 func (x MountType) String() string {
@@ -394,10 +397,37 @@ var xxx_messageInfo_Input proto.InternalMessageInfo
 
 // ExecOp executes a command in a container.
 type ExecOp struct {
-	Meta     *Meta        `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
-	Mounts   []*Mount     `protobuf:"bytes,2,rep,name=mounts,proto3" json:"mounts,omitempty"`
-	Network  NetMode      `protobuf:"varint,3,opt,name=network,proto3,enum=pb.NetMode" json:"network,omitempty"`
-	Security SecurityMode `protobuf:"varint,4,opt,name=security,proto3,enum=pb.SecurityMode" json:"security,omitempty"`
+	Meta             *Meta        `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	Mounts           []*Mount     `protobuf:"bytes,2,rep,name=mounts,proto3" json:"mounts,omitempty"`
+	Network          NetMode      `protobuf:"varint,3,opt,name=network,proto3,enum=pb.NetMode" json:"network,omitempty"`
+	Security         SecurityMode `protobuf:"varint,4,opt,name=security,proto3,enum=pb.SecurityMode" json:"security,omitempty"`
+	AllowedExitCodes []int32      `protobuf:"varint,5,rep,packed,name=allowedExitCodes,proto3" json:"allowedExitCodes,omitempty"`
+	Seccomp          *SeccompOpt  `protobuf:"bytes,6,opt,name=seccomp,proto3" json:"seccomp,omitempty"`
+	Devices          []*Device    `protobuf:"bytes,7,rep,name=devices,proto3" json:"devices,omitempty"`
+	Secretenv        []*SecretEnv `protobuf:"bytes,8,rep,name=secretenv,proto3" json:"secretenv,omitempty"`
+	// after lists the inputs that are not mounted and only need to complete
+	// before the process is started
+	After     []InputIndex `protobuf:"varint,9,rep,packed,name=after,proto3,customtype=InputIndex" json:"after"`
+	Resources *Resources   `protobuf:"bytes,10,opt,name=resources,proto3" json:"resources,omitempty"`
+	// sharedPID is started before the process of the op in a PID namespace
+	// that the process of the op joins
+	SharedPID *SharedPID `protobuf:"bytes,11,opt,name=sharedPID,proto3" json:"sharedPID,omitempty"`
+	// apparmorProfile is the name of the apparmor profile of the process
+	// instead of the profile of the daemon. The profile must be loaded on the
+	// host. "unconfined" must be allowed by the daemon.
+	ApparmorProfile string `protobuf:"bytes,12,opt,name=apparmorProfile,proto3" json:"apparmorProfile,omitempty"`
+	// ignoreForCache are paths of the outputs of the process that are
+	// excluded from the content based cache keys of the ops that use them.
+	// The files are still part of the outputs.
+	IgnoreForCache []string `protobuf:"bytes,13,rep,name=ignoreForCache,proto3" json:"ignoreForCache,omitempty"`
+	// retry re-runs the process from the same inputs if it fails
+	Retry *ExecRetry `protobuf:"bytes,14,opt,name=retry,proto3" json:"retry,omitempty"`
+	// userNSMapping runs the process in a new user namespace with the
+	// mappings instead of the user namespace of a rootless daemon
+	UserNSMapping *UserNSMapping `protobuf:"bytes,15,opt,name=userNSMapping,proto3" json:"userNSMapping,omitempty"`
+	// sysctls are the kernel parameters of the namespaces of the process,
+	// e.g. net.core.somaxconn
+	Sysctls map[string]string `protobuf:"bytes,16,rep,name=sysctls,proto3" json:"sysctls,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (m *ExecOp) Reset()         { *m = ExecOp{} }
@@ -457,121 +487,100 @@ func (m *ExecOp) GetSecurity() SecurityMode {
 	return SecurityMode_SANDBOX
 }
 
-// Meta is a set of arguments for ExecOp.
-// Meta is unrelated to LLB metadata.
-// FIXME: rename (ExecContext? ExecArgs?)
-type Meta struct {
-	Args       []string  `protobuf:"bytes,1,rep,name=args,proto3" json:"args,omitempty"`
-	Env        []string  `protobuf:"bytes,2,rep,name=env,proto3" json:"env,omitempty"`
-	Cwd        string    `protobuf:"bytes,3,opt,name=cwd,proto3" json:"cwd,omitempty"`
-	User       string    `protobuf:"bytes,4,opt,name=user,proto3" json:"user,omitempty"`
-	ProxyEnv   *ProxyEnv `protobuf:"bytes,5,opt,name=proxy_env,json=proxyEnv,proto3" json:"proxy_env,omitempty"`
-	ExtraHosts []*HostIP `protobuf:"bytes,6,rep,name=extraHosts,proto3" json:"extraHosts,omitempty"`
-	Hostname   string    `protobuf:"bytes,7,opt,name=hostname,proto3" json:"hostname,omitempty"`
+func (m *ExecOp) GetAllowedExitCodes() []int32 {
+	if m != nil {
+		return m.AllowedExitCodes
+	}
+	return nil
 }
 
-func (m *Meta) Reset()         { *m = Meta{} }
-func (m *Meta) String() string { return proto.CompactTextString(m) }
-func (*Meta) ProtoMessage()    {}
-func (*Meta) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{4}
-}
-func (m *Meta) XXX_Unmarshal(b []byte) error {
-	return m.Unmarshal(b)
-}
-func (m *Meta) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	b = b[:cap(b)]
-	n, err := m.MarshalToSizedBuffer(b)
-	if err != nil {
-		return nil, err
+func (m *ExecOp) GetSeccomp() *SeccompOpt {
+	if m != nil {
+		return m.Seccomp
 	}
-	return b[:n], nil
-}
-func (m *Meta) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Meta.Merge(m, src)
-}
-func (m *Meta) XXX_Size() int {
-	return m.Size()
+	return nil
 }
-func (m *Meta) XXX_DiscardUnknown() {
-	xxx_messageInfo_Meta.DiscardUnknown(m)
+
+func (m *ExecOp) GetDevices() []*Device {
+	if m != nil {
+		return m.Devices
+	}
+	return nil
 }
 
-var xxx_messageInfo_Meta proto.InternalMessageInfo
+func (m *ExecOp) GetSecretenv() []*SecretEnv {
+	if m != nil {
+		return m.Secretenv
+	}
+	return nil
+}
 
-func (m *Meta) GetArgs() []string {
+func (m *ExecOp) GetResources() *Resources {
 	if m != nil {
-		return m.Args
+		return m.Resources
 	}
 	return nil
 }
 
-func (m *Meta) GetEnv() []string {
+func (m *ExecOp) GetSharedPID() *SharedPID {
 	if m != nil {
-		return m.Env
+		return m.SharedPID
 	}
 	return nil
 }
 
-func (m *Meta) GetCwd() string {
+func (m *ExecOp) GetApparmorProfile() string {
 	if m != nil {
-		return m.Cwd
+		return m.ApparmorProfile
 	}
 	return ""
 }
 
-func (m *Meta) GetUser() string {
+func (m *ExecOp) GetIgnoreForCache() []string {
 	if m != nil {
-		return m.User
+		return m.IgnoreForCache
 	}
-	return ""
+	return nil
 }
 
-func (m *Meta) GetProxyEnv() *ProxyEnv {
+func (m *ExecOp) GetRetry() *ExecRetry {
 	if m != nil {
-		return m.ProxyEnv
+		return m.Retry
 	}
 	return nil
 }
 
-func (m *Meta) GetExtraHosts() []*HostIP {
+func (m *ExecOp) GetUserNSMapping() *UserNSMapping {
 	if m != nil {
-		return m.ExtraHosts
+		return m.UserNSMapping
 	}
 	return nil
 }
 
-func (m *Meta) GetHostname() string {
+func (m *ExecOp) GetSysctls() map[string]string {
 	if m != nil {
-		return m.Hostname
+		return m.Sysctls
 	}
-	return ""
+	return nil
 }
 
-// Mount specifies how to mount an input Op as a filesystem.
-type Mount struct {
-	Input     InputIndex  `protobuf:"varint,1,opt,name=input,proto3,customtype=InputIndex" json:"input"`
-	Selector  string      `protobuf:"bytes,2,opt,name=selector,proto3" json:"selector,omitempty"`
-	Dest      string      `protobuf:"bytes,3,opt,name=dest,proto3" json:"dest,omitempty"`
-	Output    OutputIndex `protobuf:"varint,4,opt,name=output,proto3,customtype=OutputIndex" json:"output"`
-	Readonly  bool        `protobuf:"varint,5,opt,name=readonly,proto3" json:"readonly,omitempty"`
-	MountType MountType   `protobuf:"varint,6,opt,name=mountType,proto3,enum=pb.MountType" json:"mountType,omitempty"`
-	CacheOpt  *CacheOpt   `protobuf:"bytes,20,opt,name=cacheOpt,proto3" json:"cacheOpt,omitempty"`
-	SecretOpt *SecretOpt  `protobuf:"bytes,21,opt,name=secretOpt,proto3" json:"secretOpt,omitempty"`
-	SSHOpt    *SSHOpt     `protobuf:"bytes,22,opt,name=SSHOpt,proto3" json:"SSHOpt,omitempty"`
-	ResultID  string      `protobuf:"bytes,23,opt,name=resultID,proto3" json:"resultID,omitempty"`
+// UserNSMapping is the uid and gid mappings of a user namespace. The host
+// IDs are IDs of the user namespace of the daemon.
+type UserNSMapping struct {
+	UidMappings []*IDMap `protobuf:"bytes,1,rep,name=uidMappings,proto3" json:"uidMappings,omitempty"`
+	GidMappings []*IDMap `protobuf:"bytes,2,rep,name=gidMappings,proto3" json:"gidMappings,omitempty"`
 }
 
-func (m *Mount) Reset()         { *m = Mount{} }
-func (m *Mount) String() string { return proto.CompactTextString(m) }
-func (*Mount) ProtoMessage()    {}
-func (*Mount) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{5}
+func (m *UserNSMapping) Reset()         { *m = UserNSMapping{} }
+func (m *UserNSMapping) String() string { return proto.CompactTextString(m) }
+func (*UserNSMapping) ProtoMessage()    {}
+func (*UserNSMapping) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{4}
 }
-func (m *Mount) XXX_Unmarshal(b []byte) error {
+func (m *UserNSMapping) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Mount) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *UserNSMapping) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -579,92 +588,106 @@ func (m *Mount) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	}
 	return b[:n], nil
 }
-func (m *Mount) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Mount.Merge(m, src)
+func (m *UserNSMapping) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UserNSMapping.Merge(m, src)
 }
-func (m *Mount) XXX_Size() int {
+func (m *UserNSMapping) XXX_Size() int {
 	return m.Size()
 }
-func (m *Mount) XXX_DiscardUnknown() {
-	xxx_messageInfo_Mount.DiscardUnknown(m)
+func (m *UserNSMapping) XXX_DiscardUnknown() {
+	xxx_messageInfo_UserNSMapping.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Mount proto.InternalMessageInfo
+var xxx_messageInfo_UserNSMapping proto.InternalMessageInfo
 
-func (m *Mount) GetSelector() string {
+func (m *UserNSMapping) GetUidMappings() []*IDMap {
 	if m != nil {
-		return m.Selector
+		return m.UidMappings
 	}
-	return ""
+	return nil
 }
 
-func (m *Mount) GetDest() string {
+func (m *UserNSMapping) GetGidMappings() []*IDMap {
 	if m != nil {
-		return m.Dest
+		return m.GidMappings
 	}
-	return ""
+	return nil
 }
 
-func (m *Mount) GetReadonly() bool {
-	if m != nil {
-		return m.Readonly
-	}
-	return false
+// IDMap maps a range of size IDs starting at containerID to the IDs starting
+// at hostID
+type IDMap struct {
+	ContainerID uint32 `protobuf:"varint,1,opt,name=containerID,proto3" json:"containerID,omitempty"`
+	HostID      uint32 `protobuf:"varint,2,opt,name=hostID,proto3" json:"hostID,omitempty"`
+	Size_       uint32 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
 }
 
-func (m *Mount) GetMountType() MountType {
-	if m != nil {
-		return m.MountType
-	}
-	return MountType_BIND
+func (m *IDMap) Reset()         { *m = IDMap{} }
+func (m *IDMap) String() string { return proto.CompactTextString(m) }
+func (*IDMap) ProtoMessage()    {}
+func (*IDMap) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{5}
 }
-
-func (m *Mount) GetCacheOpt() *CacheOpt {
-	if m != nil {
-		return m.CacheOpt
+func (m *IDMap) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *IDMap) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return b[:n], nil
+}
+func (m *IDMap) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IDMap.Merge(m, src)
+}
+func (m *IDMap) XXX_Size() int {
+	return m.Size()
+}
+func (m *IDMap) XXX_DiscardUnknown() {
+	xxx_messageInfo_IDMap.DiscardUnknown(m)
 }
 
-func (m *Mount) GetSecretOpt() *SecretOpt {
+var xxx_messageInfo_IDMap proto.InternalMessageInfo
+
+func (m *IDMap) GetContainerID() uint32 {
 	if m != nil {
-		return m.SecretOpt
+		return m.ContainerID
 	}
-	return nil
+	return 0
 }
 
-func (m *Mount) GetSSHOpt() *SSHOpt {
+func (m *IDMap) GetHostID() uint32 {
 	if m != nil {
-		return m.SSHOpt
+		return m.HostID
 	}
-	return nil
+	return 0
 }
 
-func (m *Mount) GetResultID() string {
+func (m *IDMap) GetSize_() uint32 {
 	if m != nil {
-		return m.ResultID
+		return m.Size_
 	}
-	return ""
+	return 0
 }
 
-// CacheOpt defines options specific to cache mounts
-type CacheOpt struct {
-	// ID is an optional namespace for the mount
-	ID string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	// Sharing is the sharing mode for the mount
-	Sharing CacheSharingOpt `protobuf:"varint,2,opt,name=sharing,proto3,enum=pb.CacheSharingOpt" json:"sharing,omitempty"`
+// ExecRetry defines when a failed process of an ExecOp is run again
+type ExecRetry struct {
+	Attempts  int64   `protobuf:"varint,1,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	ExitCodes []int32 `protobuf:"varint,2,rep,packed,name=exitCodes,proto3" json:"exitCodes,omitempty"`
 }
 
-func (m *CacheOpt) Reset()         { *m = CacheOpt{} }
-func (m *CacheOpt) String() string { return proto.CompactTextString(m) }
-func (*CacheOpt) ProtoMessage()    {}
-func (*CacheOpt) Descriptor() ([]byte, []int) {
+func (m *ExecRetry) Reset()         { *m = ExecRetry{} }
+func (m *ExecRetry) String() string { return proto.CompactTextString(m) }
+func (*ExecRetry) ProtoMessage()    {}
+func (*ExecRetry) Descriptor() ([]byte, []int) {
 	return fileDescriptor_8de16154b2733812, []int{6}
 }
-func (m *CacheOpt) XXX_Unmarshal(b []byte) error {
+func (m *ExecRetry) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *CacheOpt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *ExecRetry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -672,57 +695,52 @@ func (m *CacheOpt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	}
 	return b[:n], nil
 }
-func (m *CacheOpt) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CacheOpt.Merge(m, src)
+func (m *ExecRetry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecRetry.Merge(m, src)
 }
-func (m *CacheOpt) XXX_Size() int {
+func (m *ExecRetry) XXX_Size() int {
 	return m.Size()
 }
-func (m *CacheOpt) XXX_DiscardUnknown() {
-	xxx_messageInfo_CacheOpt.DiscardUnknown(m)
+func (m *ExecRetry) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecRetry.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CacheOpt proto.InternalMessageInfo
+var xxx_messageInfo_ExecRetry proto.InternalMessageInfo
 
-func (m *CacheOpt) GetID() string {
+func (m *ExecRetry) GetAttempts() int64 {
 	if m != nil {
-		return m.ID
+		return m.Attempts
 	}
-	return ""
+	return 0
 }
 
-func (m *CacheOpt) GetSharing() CacheSharingOpt {
+func (m *ExecRetry) GetExitCodes() []int32 {
 	if m != nil {
-		return m.Sharing
+		return m.ExitCodes
 	}
-	return CacheSharingOpt_SHARED
+	return nil
 }
 
-// SecretOpt defines options describing secret mounts
-type SecretOpt struct {
-	// ID of secret. Used for quering the value.
-	ID string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	// UID of secret file
-	Uid uint32 `protobuf:"varint,2,opt,name=uid,proto3" json:"uid,omitempty"`
-	// GID of secret file
-	Gid uint32 `protobuf:"varint,3,opt,name=gid,proto3" json:"gid,omitempty"`
-	// Mode is the filesystem mode of secret file
-	Mode uint32 `protobuf:"varint,4,opt,name=mode,proto3" json:"mode,omitempty"`
-	// Optional defines if secret value is required. Error is produced
-	// if value is not found and optional is false.
-	Optional bool `protobuf:"varint,5,opt,name=optional,proto3" json:"optional,omitempty"`
+// SharedPID is a process that shares its PID namespace with the process of
+// an ExecOp. Both processes are siblings under an init process of the
+// namespace. Its mounts use the inputs of the ExecOp and have no outputs. It
+// is killed when the process of the ExecOp exits.
+type SharedPID struct {
+	Meta      *Meta        `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	Mounts    []*Mount     `protobuf:"bytes,2,rep,name=mounts,proto3" json:"mounts,omitempty"`
+	Secretenv []*SecretEnv `protobuf:"bytes,3,rep,name=secretenv,proto3" json:"secretenv,omitempty"`
 }
 
-func (m *SecretOpt) Reset()         { *m = SecretOpt{} }
-func (m *SecretOpt) String() string { return proto.CompactTextString(m) }
-func (*SecretOpt) ProtoMessage()    {}
-func (*SecretOpt) Descriptor() ([]byte, []int) {
+func (m *SharedPID) Reset()         { *m = SharedPID{} }
+func (m *SharedPID) String() string { return proto.CompactTextString(m) }
+func (*SharedPID) ProtoMessage()    {}
+func (*SharedPID) Descriptor() ([]byte, []int) {
 	return fileDescriptor_8de16154b2733812, []int{7}
 }
-func (m *SecretOpt) XXX_Unmarshal(b []byte) error {
+func (m *SharedPID) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SecretOpt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SharedPID) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -730,78 +748,118 @@ func (m *SecretOpt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	}
 	return b[:n], nil
 }
-func (m *SecretOpt) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SecretOpt.Merge(m, src)
+func (m *SharedPID) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SharedPID.Merge(m, src)
 }
-func (m *SecretOpt) XXX_Size() int {
+func (m *SharedPID) XXX_Size() int {
 	return m.Size()
 }
-func (m *SecretOpt) XXX_DiscardUnknown() {
-	xxx_messageInfo_SecretOpt.DiscardUnknown(m)
+func (m *SharedPID) XXX_DiscardUnknown() {
+	xxx_messageInfo_SharedPID.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SecretOpt proto.InternalMessageInfo
+var xxx_messageInfo_SharedPID proto.InternalMessageInfo
 
-func (m *SecretOpt) GetID() string {
+func (m *SharedPID) GetMeta() *Meta {
 	if m != nil {
-		return m.ID
+		return m.Meta
 	}
-	return ""
+	return nil
 }
 
-func (m *SecretOpt) GetUid() uint32 {
+func (m *SharedPID) GetMounts() []*Mount {
 	if m != nil {
-		return m.Uid
+		return m.Mounts
 	}
-	return 0
+	return nil
 }
 
-func (m *SecretOpt) GetGid() uint32 {
+func (m *SharedPID) GetSecretenv() []*SecretEnv {
 	if m != nil {
-		return m.Gid
+		return m.Secretenv
+	}
+	return nil
+}
+
+// Resources are the cgroup limits of the process
+type Resources struct {
+	Memory    int64  `protobuf:"varint,1,opt,name=memory,proto3" json:"memory,omitempty"`
+	CpuQuota  int64  `protobuf:"varint,2,opt,name=cpuQuota,proto3" json:"cpuQuota,omitempty"`
+	CpuPeriod uint64 `protobuf:"varint,3,opt,name=cpuPeriod,proto3" json:"cpuPeriod,omitempty"`
+}
+
+func (m *Resources) Reset()         { *m = Resources{} }
+func (m *Resources) String() string { return proto.CompactTextString(m) }
+func (*Resources) ProtoMessage()    {}
+func (*Resources) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{8}
+}
+func (m *Resources) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Resources) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *Resources) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Resources.Merge(m, src)
+}
+func (m *Resources) XXX_Size() int {
+	return m.Size()
+}
+func (m *Resources) XXX_DiscardUnknown() {
+	xxx_messageInfo_Resources.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Resources proto.InternalMessageInfo
+
+func (m *Resources) GetMemory() int64 {
+	if m != nil {
+		return m.Memory
 	}
 	return 0
 }
 
-func (m *SecretOpt) GetMode() uint32 {
+func (m *Resources) GetCpuQuota() int64 {
 	if m != nil {
-		return m.Mode
+		return m.CpuQuota
 	}
 	return 0
 }
 
-func (m *SecretOpt) GetOptional() bool {
+func (m *Resources) GetCpuPeriod() uint64 {
 	if m != nil {
-		return m.Optional
+		return m.CpuPeriod
 	}
-	return false
+	return 0
 }
 
-// SSHOpt defines options describing secret mounts
-type SSHOpt struct {
-	// ID of exposed ssh rule. Used for quering the value.
+// SecretEnv is a secret that is set as an environment variable of the
+// process. The value is read from the session when the process is started.
+type SecretEnv struct {
+	// ID of secret. Used for quering the value.
 	ID string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	// UID of agent socket
-	Uid uint32 `protobuf:"varint,2,opt,name=uid,proto3" json:"uid,omitempty"`
-	// GID of agent socket
-	Gid uint32 `protobuf:"varint,3,opt,name=gid,proto3" json:"gid,omitempty"`
-	// Mode is the filesystem mode of agent socket
-	Mode uint32 `protobuf:"varint,4,opt,name=mode,proto3" json:"mode,omitempty"`
-	// Optional defines if ssh socket is required. Error is produced
-	// if client does not expose ssh.
-	Optional bool `protobuf:"varint,5,opt,name=optional,proto3" json:"optional,omitempty"`
+	// Name of the environment variable
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Optional defines if secret value is required. Error is produced
+	// if value is not found and optional is false.
+	Optional bool `protobuf:"varint,3,opt,name=optional,proto3" json:"optional,omitempty"`
 }
 
-func (m *SSHOpt) Reset()         { *m = SSHOpt{} }
-func (m *SSHOpt) String() string { return proto.CompactTextString(m) }
-func (*SSHOpt) ProtoMessage()    {}
-func (*SSHOpt) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{8}
+func (m *SecretEnv) Reset()         { *m = SecretEnv{} }
+func (m *SecretEnv) String() string { return proto.CompactTextString(m) }
+func (*SecretEnv) ProtoMessage()    {}
+func (*SecretEnv) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{9}
 }
-func (m *SSHOpt) XXX_Unmarshal(b []byte) error {
+func (m *SecretEnv) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SSHOpt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SecretEnv) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -809,72 +867,57 @@ func (m *SSHOpt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	}
 	return b[:n], nil
 }
-func (m *SSHOpt) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SSHOpt.Merge(m, src)
+func (m *SecretEnv) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SecretEnv.Merge(m, src)
 }
-func (m *SSHOpt) XXX_Size() int {
+func (m *SecretEnv) XXX_Size() int {
 	return m.Size()
 }
-func (m *SSHOpt) XXX_DiscardUnknown() {
-	xxx_messageInfo_SSHOpt.DiscardUnknown(m)
+func (m *SecretEnv) XXX_DiscardUnknown() {
+	xxx_messageInfo_SecretEnv.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SSHOpt proto.InternalMessageInfo
+var xxx_messageInfo_SecretEnv proto.InternalMessageInfo
 
-func (m *SSHOpt) GetID() string {
+func (m *SecretEnv) GetID() string {
 	if m != nil {
 		return m.ID
 	}
 	return ""
 }
 
-func (m *SSHOpt) GetUid() uint32 {
+func (m *SecretEnv) GetName() string {
 	if m != nil {
-		return m.Uid
+		return m.Name
 	}
-	return 0
+	return ""
 }
 
-func (m *SSHOpt) GetGid() uint32 {
+func (m *SecretEnv) GetOptional() bool {
 	if m != nil {
-		return m.Gid
+		return m.Optional
 	}
-	return 0
+	return false
 }
 
-func (m *SSHOpt) GetMode() uint32 {
-	if m != nil {
-		return m.Mode
-	}
-	return 0
-}
-
-func (m *SSHOpt) GetOptional() bool {
-	if m != nil {
-		return m.Optional
-	}
-	return false
-}
-
-// SourceOp specifies a source such as build contexts and images.
-type SourceOp struct {
-	// TODO: use source type or any type instead of URL protocol.
-	// identifier e.g. local://, docker-image://, git://, https://...
-	Identifier string `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
-	// attrs are defined in attr.go
-	Attrs map[string]string `protobuf:"bytes,2,rep,name=attrs,proto3" json:"attrs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+// Device is a host device that is made available to the process. The device
+// needs to be allowed in the configuration of the daemon.
+type Device struct {
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Permissions is a combination of r (read), w (write) and m (mknod)
+	Permissions string `protobuf:"bytes,2,opt,name=permissions,proto3" json:"permissions,omitempty"`
 }
 
-func (m *SourceOp) Reset()         { *m = SourceOp{} }
-func (m *SourceOp) String() string { return proto.CompactTextString(m) }
-func (*SourceOp) ProtoMessage()    {}
-func (*SourceOp) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{9}
+func (m *Device) Reset()         { *m = Device{} }
+func (m *Device) String() string { return proto.CompactTextString(m) }
+func (*Device) ProtoMessage()    {}
+func (*Device) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{10}
 }
-func (m *SourceOp) XXX_Unmarshal(b []byte) error {
+func (m *Device) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *SourceOp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *Device) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -882,51 +925,52 @@ func (m *SourceOp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	}
 	return b[:n], nil
 }
-func (m *SourceOp) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SourceOp.Merge(m, src)
+func (m *Device) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Device.Merge(m, src)
 }
-func (m *SourceOp) XXX_Size() int {
+func (m *Device) XXX_Size() int {
 	return m.Size()
 }
-func (m *SourceOp) XXX_DiscardUnknown() {
-	xxx_messageInfo_SourceOp.DiscardUnknown(m)
+func (m *Device) XXX_DiscardUnknown() {
+	xxx_messageInfo_Device.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SourceOp proto.InternalMessageInfo
+var xxx_messageInfo_Device proto.InternalMessageInfo
 
-func (m *SourceOp) GetIdentifier() string {
+func (m *Device) GetPath() string {
 	if m != nil {
-		return m.Identifier
+		return m.Path
 	}
 	return ""
 }
 
-func (m *SourceOp) GetAttrs() map[string]string {
+func (m *Device) GetPermissions() string {
 	if m != nil {
-		return m.Attrs
+		return m.Permissions
 	}
-	return nil
+	return ""
 }
 
-// BuildOp is used for nested build invocation.
-// BuildOp is experimental and can break without backwards compatibility
-type BuildOp struct {
-	Builder InputIndex             `protobuf:"varint,1,opt,name=builder,proto3,customtype=InputIndex" json:"builder"`
-	Inputs  map[string]*BuildInput `protobuf:"bytes,2,rep,name=inputs,proto3" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	Def     *Definition            `protobuf:"bytes,3,opt,name=def,proto3" json:"def,omitempty"`
-	Attrs   map[string]string      `protobuf:"bytes,4,rep,name=attrs,proto3" json:"attrs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+// SeccompOpt overrides the default seccomp profile of the process
+type SeccompOpt struct {
+	// Profile is a seccomp profile in the JSON format used by Docker. Like
+	// unconfined, requires the security.insecure entitlement.
+	Profile string `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	// Unconfined runs the process without seccomp. Requires the
+	// security.insecure entitlement.
+	Unconfined bool `protobuf:"varint,2,opt,name=unconfined,proto3" json:"unconfined,omitempty"`
 }
 
-func (m *BuildOp) Reset()         { *m = BuildOp{} }
-func (m *BuildOp) String() string { return proto.CompactTextString(m) }
-func (*BuildOp) ProtoMessage()    {}
-func (*BuildOp) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{10}
+func (m *SeccompOpt) Reset()         { *m = SeccompOpt{} }
+func (m *SeccompOpt) String() string { return proto.CompactTextString(m) }
+func (*SeccompOpt) ProtoMessage()    {}
+func (*SeccompOpt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{11}
 }
-func (m *BuildOp) XXX_Unmarshal(b []byte) error {
+func (m *SeccompOpt) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *BuildOp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SeccompOpt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -934,54 +978,62 @@ func (m *BuildOp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	}
 	return b[:n], nil
 }
-func (m *BuildOp) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_BuildOp.Merge(m, src)
+func (m *SeccompOpt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SeccompOpt.Merge(m, src)
 }
-func (m *BuildOp) XXX_Size() int {
+func (m *SeccompOpt) XXX_Size() int {
 	return m.Size()
 }
-func (m *BuildOp) XXX_DiscardUnknown() {
-	xxx_messageInfo_BuildOp.DiscardUnknown(m)
+func (m *SeccompOpt) XXX_DiscardUnknown() {
+	xxx_messageInfo_SeccompOpt.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_BuildOp proto.InternalMessageInfo
-
-func (m *BuildOp) GetInputs() map[string]*BuildInput {
-	if m != nil {
-		return m.Inputs
-	}
-	return nil
-}
+var xxx_messageInfo_SeccompOpt proto.InternalMessageInfo
 
-func (m *BuildOp) GetDef() *Definition {
+func (m *SeccompOpt) GetProfile() string {
 	if m != nil {
-		return m.Def
+		return m.Profile
 	}
-	return nil
+	return ""
 }
 
-func (m *BuildOp) GetAttrs() map[string]string {
+func (m *SeccompOpt) GetUnconfined() bool {
 	if m != nil {
-		return m.Attrs
+		return m.Unconfined
 	}
-	return nil
+	return false
 }
 
-// BuildInput is used for BuildOp.
-type BuildInput struct {
-	Input InputIndex `protobuf:"varint,1,opt,name=input,proto3,customtype=InputIndex" json:"input"`
+// Meta is a set of arguments for ExecOp.
+// Meta is unrelated to LLB metadata.
+// FIXME: rename (ExecContext? ExecArgs?)
+type Meta struct {
+	Args           []string  `protobuf:"bytes,1,rep,name=args,proto3" json:"args,omitempty"`
+	Env            []string  `protobuf:"bytes,2,rep,name=env,proto3" json:"env,omitempty"`
+	Cwd            string    `protobuf:"bytes,3,opt,name=cwd,proto3" json:"cwd,omitempty"`
+	User           string    `protobuf:"bytes,4,opt,name=user,proto3" json:"user,omitempty"`
+	ProxyEnv       *ProxyEnv `protobuf:"bytes,5,opt,name=proxy_env,json=proxyEnv,proto3" json:"proxy_env,omitempty"`
+	ExtraHosts     []*HostIP `protobuf:"bytes,6,rep,name=extraHosts,proto3" json:"extraHosts,omitempty"`
+	Hostname       string    `protobuf:"bytes,7,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Entrypoint     string    `protobuf:"bytes,8,opt,name=entrypoint,proto3" json:"entrypoint,omitempty"`
+	CacheIgnoreEnv []string  `protobuf:"bytes,9,rep,name=cacheIgnoreEnv,proto3" json:"cacheIgnoreEnv,omitempty"`
+	Umask          string    `protobuf:"bytes,10,opt,name=umask,proto3" json:"umask,omitempty"`
+	PassthroughEnv []string  `protobuf:"bytes,11,rep,name=passthroughEnv,proto3" json:"passthroughEnv,omitempty"`
+	RedirectStdout string    `protobuf:"bytes,12,opt,name=redirectStdout,proto3" json:"redirectStdout,omitempty"`
+	RedirectStderr string    `protobuf:"bytes,13,opt,name=redirectStderr,proto3" json:"redirectStderr,omitempty"`
+	Timezone       *Timezone `protobuf:"bytes,14,opt,name=timezone,proto3" json:"timezone,omitempty"`
 }
 
-func (m *BuildInput) Reset()         { *m = BuildInput{} }
-func (m *BuildInput) String() string { return proto.CompactTextString(m) }
-func (*BuildInput) ProtoMessage()    {}
-func (*BuildInput) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{11}
+func (m *Meta) Reset()         { *m = Meta{} }
+func (m *Meta) String() string { return proto.CompactTextString(m) }
+func (*Meta) ProtoMessage()    {}
+func (*Meta) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{12}
 }
-func (m *BuildInput) XXX_Unmarshal(b []byte) error {
+func (m *Meta) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *BuildInput) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *Meta) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -989,103 +1041,132 @@ func (m *BuildInput) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	}
 	return b[:n], nil
 }
-func (m *BuildInput) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_BuildInput.Merge(m, src)
+func (m *Meta) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Meta.Merge(m, src)
 }
-func (m *BuildInput) XXX_Size() int {
+func (m *Meta) XXX_Size() int {
 	return m.Size()
 }
-func (m *BuildInput) XXX_DiscardUnknown() {
-	xxx_messageInfo_BuildInput.DiscardUnknown(m)
+func (m *Meta) XXX_DiscardUnknown() {
+	xxx_messageInfo_Meta.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_BuildInput proto.InternalMessageInfo
+var xxx_messageInfo_Meta proto.InternalMessageInfo
 
-// OpMetadata is a per-vertex metadata entry, which can be defined for arbitrary Op vertex and overridable on the run time.
-type OpMetadata struct {
-	// ignore_cache specifies to ignore the cache for this Op.
-	IgnoreCache bool `protobuf:"varint,1,opt,name=ignore_cache,json=ignoreCache,proto3" json:"ignore_cache,omitempty"`
-	// Description can be used for keeping any text fields that builder doesn't parse
-	Description map[string]string `protobuf:"bytes,2,rep,name=description,proto3" json:"description,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	// index 3 reserved for WorkerConstraint in previous versions
-	// WorkerConstraint worker_constraint = 3;
-	ExportCache *ExportCache                                         `protobuf:"bytes,4,opt,name=export_cache,json=exportCache,proto3" json:"export_cache,omitempty"`
-	Caps        map[github_com_moby_buildkit_util_apicaps.CapID]bool `protobuf:"bytes,5,rep,name=caps,proto3,castkey=github.com/moby/buildkit/util/apicaps.CapID" json:"caps" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+func (m *Meta) GetArgs() []string {
+	if m != nil {
+		return m.Args
+	}
+	return nil
 }
 
-func (m *OpMetadata) Reset()         { *m = OpMetadata{} }
-func (m *OpMetadata) String() string { return proto.CompactTextString(m) }
-func (*OpMetadata) ProtoMessage()    {}
-func (*OpMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{12}
-}
-func (m *OpMetadata) XXX_Unmarshal(b []byte) error {
-	return m.Unmarshal(b)
+func (m *Meta) GetEnv() []string {
+	if m != nil {
+		return m.Env
+	}
+	return nil
 }
-func (m *OpMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	b = b[:cap(b)]
-	n, err := m.MarshalToSizedBuffer(b)
-	if err != nil {
-		return nil, err
+
+func (m *Meta) GetCwd() string {
+	if m != nil {
+		return m.Cwd
 	}
-	return b[:n], nil
+	return ""
 }
-func (m *OpMetadata) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_OpMetadata.Merge(m, src)
+
+func (m *Meta) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
 }
-func (m *OpMetadata) XXX_Size() int {
-	return m.Size()
+
+func (m *Meta) GetProxyEnv() *ProxyEnv {
+	if m != nil {
+		return m.ProxyEnv
+	}
+	return nil
 }
-func (m *OpMetadata) XXX_DiscardUnknown() {
-	xxx_messageInfo_OpMetadata.DiscardUnknown(m)
+
+func (m *Meta) GetExtraHosts() []*HostIP {
+	if m != nil {
+		return m.ExtraHosts
+	}
+	return nil
 }
 
-var xxx_messageInfo_OpMetadata proto.InternalMessageInfo
+func (m *Meta) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
 
-func (m *OpMetadata) GetIgnoreCache() bool {
+func (m *Meta) GetEntrypoint() string {
 	if m != nil {
-		return m.IgnoreCache
+		return m.Entrypoint
 	}
-	return false
+	return ""
 }
 
-func (m *OpMetadata) GetDescription() map[string]string {
+func (m *Meta) GetCacheIgnoreEnv() []string {
 	if m != nil {
-		return m.Description
+		return m.CacheIgnoreEnv
 	}
 	return nil
 }
 
-func (m *OpMetadata) GetExportCache() *ExportCache {
+func (m *Meta) GetUmask() string {
 	if m != nil {
-		return m.ExportCache
+		return m.Umask
+	}
+	return ""
+}
+
+func (m *Meta) GetPassthroughEnv() []string {
+	if m != nil {
+		return m.PassthroughEnv
 	}
 	return nil
 }
 
-func (m *OpMetadata) GetCaps() map[github_com_moby_buildkit_util_apicaps.CapID]bool {
+func (m *Meta) GetRedirectStdout() string {
 	if m != nil {
-		return m.Caps
+		return m.RedirectStdout
+	}
+	return ""
+}
+
+func (m *Meta) GetRedirectStderr() string {
+	if m != nil {
+		return m.RedirectStderr
+	}
+	return ""
+}
+
+func (m *Meta) GetTimezone() *Timezone {
+	if m != nil {
+		return m.Timezone
 	}
 	return nil
 }
 
-// Source is a source mapping description for a file
-type Source struct {
-	Locations map[string]*Locations `protobuf:"bytes,1,rep,name=locations,proto3" json:"locations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	Infos     []*SourceInfo         `protobuf:"bytes,2,rep,name=infos,proto3" json:"infos,omitempty"`
+// Timezone sets the timezone of the process
+type Timezone struct {
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	MountZoneinfo bool   `protobuf:"varint,2,opt,name=mountZoneinfo,proto3" json:"mountZoneinfo,omitempty"`
 }
 
-func (m *Source) Reset()         { *m = Source{} }
-func (m *Source) String() string { return proto.CompactTextString(m) }
-func (*Source) ProtoMessage()    {}
-func (*Source) Descriptor() ([]byte, []int) {
+func (m *Timezone) Reset()         { *m = Timezone{} }
+func (m *Timezone) String() string { return proto.CompactTextString(m) }
+func (*Timezone) ProtoMessage()    {}
+func (*Timezone) Descriptor() ([]byte, []int) {
 	return fileDescriptor_8de16154b2733812, []int{13}
 }
-func (m *Source) XXX_Unmarshal(b []byte) error {
+func (m *Timezone) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Source) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *Timezone) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -1093,47 +1174,57 @@ func (m *Source) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	}
 	return b[:n], nil
 }
-func (m *Source) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Source.Merge(m, src)
+func (m *Timezone) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Timezone.Merge(m, src)
 }
-func (m *Source) XXX_Size() int {
+func (m *Timezone) XXX_Size() int {
 	return m.Size()
 }
-func (m *Source) XXX_DiscardUnknown() {
-	xxx_messageInfo_Source.DiscardUnknown(m)
+func (m *Timezone) XXX_DiscardUnknown() {
+	xxx_messageInfo_Timezone.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Source proto.InternalMessageInfo
+var xxx_messageInfo_Timezone proto.InternalMessageInfo
 
-func (m *Source) GetLocations() map[string]*Locations {
+func (m *Timezone) GetName() string {
 	if m != nil {
-		return m.Locations
+		return m.Name
 	}
-	return nil
+	return ""
 }
 
-func (m *Source) GetInfos() []*SourceInfo {
+func (m *Timezone) GetMountZoneinfo() bool {
 	if m != nil {
-		return m.Infos
+		return m.MountZoneinfo
 	}
-	return nil
+	return false
 }
 
-// Locations is a list of ranges with a index to its source map.
-type Locations struct {
-	Locations []*Location `protobuf:"bytes,1,rep,name=locations,proto3" json:"locations,omitempty"`
+// Mount specifies how to mount an input Op as a filesystem.
+type Mount struct {
+	Input       InputIndex   `protobuf:"varint,1,opt,name=input,proto3,customtype=InputIndex" json:"input"`
+	Selector    string       `protobuf:"bytes,2,opt,name=selector,proto3" json:"selector,omitempty"`
+	Dest        string       `protobuf:"bytes,3,opt,name=dest,proto3" json:"dest,omitempty"`
+	Output      OutputIndex  `protobuf:"varint,4,opt,name=output,proto3,customtype=OutputIndex" json:"output"`
+	Readonly    bool         `protobuf:"varint,5,opt,name=readonly,proto3" json:"readonly,omitempty"`
+	MountType   MountType    `protobuf:"varint,6,opt,name=mountType,proto3,enum=pb.MountType" json:"mountType,omitempty"`
+	CacheOpt    *CacheOpt    `protobuf:"bytes,20,opt,name=cacheOpt,proto3" json:"cacheOpt,omitempty"`
+	SecretOpt   *SecretOpt   `protobuf:"bytes,21,opt,name=secretOpt,proto3" json:"secretOpt,omitempty"`
+	SSHOpt      *SSHOpt      `protobuf:"bytes,22,opt,name=SSHOpt,proto3" json:"SSHOpt,omitempty"`
+	ResultID    string       `protobuf:"bytes,23,opt,name=resultID,proto3" json:"resultID,omitempty"`
+	HostPathOpt *HostPathOpt `protobuf:"bytes,24,opt,name=hostPathOpt,proto3" json:"hostPathOpt,omitempty"`
 }
 
-func (m *Locations) Reset()         { *m = Locations{} }
-func (m *Locations) String() string { return proto.CompactTextString(m) }
-func (*Locations) ProtoMessage()    {}
-func (*Locations) Descriptor() ([]byte, []int) {
+func (m *Mount) Reset()         { *m = Mount{} }
+func (m *Mount) String() string { return proto.CompactTextString(m) }
+func (*Mount) ProtoMessage()    {}
+func (*Mount) Descriptor() ([]byte, []int) {
 	return fileDescriptor_8de16154b2733812, []int{14}
 }
-func (m *Locations) XXX_Unmarshal(b []byte) error {
+func (m *Mount) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Locations) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *Mount) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -1141,98 +1232,99 @@ func (m *Locations) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	}
 	return b[:n], nil
 }
-func (m *Locations) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Locations.Merge(m, src)
+func (m *Mount) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Mount.Merge(m, src)
 }
-func (m *Locations) XXX_Size() int {
+func (m *Mount) XXX_Size() int {
 	return m.Size()
 }
-func (m *Locations) XXX_DiscardUnknown() {
-	xxx_messageInfo_Locations.DiscardUnknown(m)
-}
+func (m *Mount) XXX_DiscardUnknown() {
+	xxx_messageInfo_Mount.DiscardUnknown(m)
+}
 
-var xxx_messageInfo_Locations proto.InternalMessageInfo
+var xxx_messageInfo_Mount proto.InternalMessageInfo
 
-func (m *Locations) GetLocations() []*Location {
+func (m *Mount) GetSelector() string {
 	if m != nil {
-		return m.Locations
+		return m.Selector
 	}
-	return nil
+	return ""
 }
 
-// Source info contains the shared metadata of a source mapping
-type SourceInfo struct {
-	Filename   string      `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
-	Data       []byte      `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
-	Definition *Definition `protobuf:"bytes,3,opt,name=definition,proto3" json:"definition,omitempty"`
+func (m *Mount) GetDest() string {
+	if m != nil {
+		return m.Dest
+	}
+	return ""
 }
 
-func (m *SourceInfo) Reset()         { *m = SourceInfo{} }
-func (m *SourceInfo) String() string { return proto.CompactTextString(m) }
-func (*SourceInfo) ProtoMessage()    {}
-func (*SourceInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{15}
-}
-func (m *SourceInfo) XXX_Unmarshal(b []byte) error {
-	return m.Unmarshal(b)
-}
-func (m *SourceInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	b = b[:cap(b)]
-	n, err := m.MarshalToSizedBuffer(b)
-	if err != nil {
-		return nil, err
+func (m *Mount) GetReadonly() bool {
+	if m != nil {
+		return m.Readonly
 	}
-	return b[:n], nil
-}
-func (m *SourceInfo) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SourceInfo.Merge(m, src)
-}
-func (m *SourceInfo) XXX_Size() int {
-	return m.Size()
+	return false
 }
-func (m *SourceInfo) XXX_DiscardUnknown() {
-	xxx_messageInfo_SourceInfo.DiscardUnknown(m)
+
+func (m *Mount) GetMountType() MountType {
+	if m != nil {
+		return m.MountType
+	}
+	return MountType_BIND
 }
 
-var xxx_messageInfo_SourceInfo proto.InternalMessageInfo
+func (m *Mount) GetCacheOpt() *CacheOpt {
+	if m != nil {
+		return m.CacheOpt
+	}
+	return nil
+}
 
-func (m *SourceInfo) GetFilename() string {
+func (m *Mount) GetSecretOpt() *SecretOpt {
 	if m != nil {
-		return m.Filename
+		return m.SecretOpt
 	}
-	return ""
+	return nil
 }
 
-func (m *SourceInfo) GetData() []byte {
+func (m *Mount) GetSSHOpt() *SSHOpt {
 	if m != nil {
-		return m.Data
+		return m.SSHOpt
 	}
 	return nil
 }
 
-func (m *SourceInfo) GetDefinition() *Definition {
+func (m *Mount) GetResultID() string {
 	if m != nil {
-		return m.Definition
+		return m.ResultID
+	}
+	return ""
+}
+
+func (m *Mount) GetHostPathOpt() *HostPathOpt {
+	if m != nil {
+		return m.HostPathOpt
 	}
 	return nil
 }
 
-// Location defines list of areas in to source file
-type Location struct {
-	SourceIndex int32    `protobuf:"varint,1,opt,name=sourceIndex,proto3" json:"sourceIndex,omitempty"`
-	Ranges      []*Range `protobuf:"bytes,2,rep,name=ranges,proto3" json:"ranges,omitempty"`
+// CacheOpt defines options specific to cache mounts
+type CacheOpt struct {
+	// ID is an optional namespace for the mount
+	ID string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	// Sharing is the sharing mode for the mount
+	Sharing CacheSharingOpt `protobuf:"varint,2,opt,name=sharing,proto3,enum=pb.CacheSharingOpt" json:"sharing,omitempty"`
 }
 
-func (m *Location) Reset()         { *m = Location{} }
-func (m *Location) String() string { return proto.CompactTextString(m) }
-func (*Location) ProtoMessage()    {}
-func (*Location) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{16}
+func (m *CacheOpt) Reset()         { *m = CacheOpt{} }
+func (m *CacheOpt) String() string { return proto.CompactTextString(m) }
+func (*CacheOpt) ProtoMessage()    {}
+func (*CacheOpt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{15}
 }
-func (m *Location) XXX_Unmarshal(b []byte) error {
+func (m *CacheOpt) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Location) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *CacheOpt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -1240,48 +1332,57 @@ func (m *Location) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	}
 	return b[:n], nil
 }
-func (m *Location) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Location.Merge(m, src)
+func (m *CacheOpt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CacheOpt.Merge(m, src)
 }
-func (m *Location) XXX_Size() int {
+func (m *CacheOpt) XXX_Size() int {
 	return m.Size()
 }
-func (m *Location) XXX_DiscardUnknown() {
-	xxx_messageInfo_Location.DiscardUnknown(m)
+func (m *CacheOpt) XXX_DiscardUnknown() {
+	xxx_messageInfo_CacheOpt.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Location proto.InternalMessageInfo
+var xxx_messageInfo_CacheOpt proto.InternalMessageInfo
 
-func (m *Location) GetSourceIndex() int32 {
+func (m *CacheOpt) GetID() string {
 	if m != nil {
-		return m.SourceIndex
+		return m.ID
 	}
-	return 0
+	return ""
 }
 
-func (m *Location) GetRanges() []*Range {
+func (m *CacheOpt) GetSharing() CacheSharingOpt {
 	if m != nil {
-		return m.Ranges
+		return m.Sharing
 	}
-	return nil
+	return CacheSharingOpt_SHARED
 }
 
-// Range is an area in the source file
-type Range struct {
-	Start Position `protobuf:"bytes,1,opt,name=start,proto3" json:"start"`
-	End   Position `protobuf:"bytes,2,opt,name=end,proto3" json:"end"`
+// SecretOpt defines options describing secret mounts
+type SecretOpt struct {
+	// ID of secret. Used for quering the value.
+	ID string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	// UID of secret file
+	Uid uint32 `protobuf:"varint,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	// GID of secret file
+	Gid uint32 `protobuf:"varint,3,opt,name=gid,proto3" json:"gid,omitempty"`
+	// Mode is the filesystem mode of secret file
+	Mode uint32 `protobuf:"varint,4,opt,name=mode,proto3" json:"mode,omitempty"`
+	// Optional defines if secret value is required. Error is produced
+	// if value is not found and optional is false.
+	Optional bool `protobuf:"varint,5,opt,name=optional,proto3" json:"optional,omitempty"`
 }
 
-func (m *Range) Reset()         { *m = Range{} }
-func (m *Range) String() string { return proto.CompactTextString(m) }
-func (*Range) ProtoMessage()    {}
-func (*Range) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{17}
+func (m *SecretOpt) Reset()         { *m = SecretOpt{} }
+func (m *SecretOpt) String() string { return proto.CompactTextString(m) }
+func (*SecretOpt) ProtoMessage()    {}
+func (*SecretOpt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{16}
 }
-func (m *Range) XXX_Unmarshal(b []byte) error {
+func (m *SecretOpt) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Range) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SecretOpt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -1289,48 +1390,78 @@ func (m *Range) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	}
 	return b[:n], nil
 }
-func (m *Range) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Range.Merge(m, src)
+func (m *SecretOpt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SecretOpt.Merge(m, src)
 }
-func (m *Range) XXX_Size() int {
+func (m *SecretOpt) XXX_Size() int {
 	return m.Size()
 }
-func (m *Range) XXX_DiscardUnknown() {
-	xxx_messageInfo_Range.DiscardUnknown(m)
+func (m *SecretOpt) XXX_DiscardUnknown() {
+	xxx_messageInfo_SecretOpt.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Range proto.InternalMessageInfo
+var xxx_messageInfo_SecretOpt proto.InternalMessageInfo
 
-func (m *Range) GetStart() Position {
+func (m *SecretOpt) GetID() string {
 	if m != nil {
-		return m.Start
+		return m.ID
 	}
-	return Position{}
+	return ""
 }
 
-func (m *Range) GetEnd() Position {
+func (m *SecretOpt) GetUid() uint32 {
 	if m != nil {
-		return m.End
+		return m.Uid
 	}
-	return Position{}
+	return 0
 }
 
-// Position is single location in a source file
-type Position struct {
-	Line      int32 `protobuf:"varint,1,opt,name=Line,proto3" json:"Line,omitempty"`
-	Character int32 `protobuf:"varint,2,opt,name=Character,proto3" json:"Character,omitempty"`
+func (m *SecretOpt) GetGid() uint32 {
+	if m != nil {
+		return m.Gid
+	}
+	return 0
 }
 
-func (m *Position) Reset()         { *m = Position{} }
-func (m *Position) String() string { return proto.CompactTextString(m) }
-func (*Position) ProtoMessage()    {}
-func (*Position) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{18}
+func (m *SecretOpt) GetMode() uint32 {
+	if m != nil {
+		return m.Mode
+	}
+	return 0
 }
-func (m *Position) XXX_Unmarshal(b []byte) error {
+
+func (m *SecretOpt) GetOptional() bool {
+	if m != nil {
+		return m.Optional
+	}
+	return false
+}
+
+// SSHOpt defines options describing secret mounts
+type SSHOpt struct {
+	// ID of exposed ssh rule. Used for quering the value.
+	ID string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	// UID of agent socket
+	Uid uint32 `protobuf:"varint,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	// GID of agent socket
+	Gid uint32 `protobuf:"varint,3,opt,name=gid,proto3" json:"gid,omitempty"`
+	// Mode is the filesystem mode of agent socket
+	Mode uint32 `protobuf:"varint,4,opt,name=mode,proto3" json:"mode,omitempty"`
+	// Optional defines if ssh socket is required. Error is produced
+	// if client does not expose ssh.
+	Optional bool `protobuf:"varint,5,opt,name=optional,proto3" json:"optional,omitempty"`
+}
+
+func (m *SSHOpt) Reset()         { *m = SSHOpt{} }
+func (m *SSHOpt) String() string { return proto.CompactTextString(m) }
+func (*SSHOpt) ProtoMessage()    {}
+func (*SSHOpt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{17}
+}
+func (m *SSHOpt) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Position) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SSHOpt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -1338,46 +1469,70 @@ func (m *Position) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	}
 	return b[:n], nil
 }
-func (m *Position) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Position.Merge(m, src)
+func (m *SSHOpt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SSHOpt.Merge(m, src)
 }
-func (m *Position) XXX_Size() int {
+func (m *SSHOpt) XXX_Size() int {
 	return m.Size()
 }
-func (m *Position) XXX_DiscardUnknown() {
-	xxx_messageInfo_Position.DiscardUnknown(m)
+func (m *SSHOpt) XXX_DiscardUnknown() {
+	xxx_messageInfo_SSHOpt.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Position proto.InternalMessageInfo
+var xxx_messageInfo_SSHOpt proto.InternalMessageInfo
 
-func (m *Position) GetLine() int32 {
+func (m *SSHOpt) GetID() string {
 	if m != nil {
-		return m.Line
+		return m.ID
 	}
-	return 0
+	return ""
 }
 
-func (m *Position) GetCharacter() int32 {
+func (m *SSHOpt) GetUid() uint32 {
 	if m != nil {
-		return m.Character
+		return m.Uid
 	}
 	return 0
 }
 
-type ExportCache struct {
-	Value bool `protobuf:"varint,1,opt,name=Value,proto3" json:"Value,omitempty"`
+func (m *SSHOpt) GetGid() uint32 {
+	if m != nil {
+		return m.Gid
+	}
+	return 0
 }
 
-func (m *ExportCache) Reset()         { *m = ExportCache{} }
-func (m *ExportCache) String() string { return proto.CompactTextString(m) }
-func (*ExportCache) ProtoMessage()    {}
-func (*ExportCache) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{19}
+func (m *SSHOpt) GetMode() uint32 {
+	if m != nil {
+		return m.Mode
+	}
+	return 0
 }
-func (m *ExportCache) XXX_Unmarshal(b []byte) error {
+
+func (m *SSHOpt) GetOptional() bool {
+	if m != nil {
+		return m.Optional
+	}
+	return false
+}
+
+// HostPathOpt defines options describing host path mounts
+type HostPathOpt struct {
+	// Name of the host path in the daemon configuration. The daemon maps
+	// the name to the allowed host path.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (m *HostPathOpt) Reset()         { *m = HostPathOpt{} }
+func (m *HostPathOpt) String() string { return proto.CompactTextString(m) }
+func (*HostPathOpt) ProtoMessage()    {}
+func (*HostPathOpt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{18}
+}
+func (m *HostPathOpt) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *ExportCache) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *HostPathOpt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -1385,43 +1540,44 @@ func (m *ExportCache) XXX_Marshal(b []byte, deterministic bool) ([]byte, error)
 	}
 	return b[:n], nil
 }
-func (m *ExportCache) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ExportCache.Merge(m, src)
+func (m *HostPathOpt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HostPathOpt.Merge(m, src)
 }
-func (m *ExportCache) XXX_Size() int {
+func (m *HostPathOpt) XXX_Size() int {
 	return m.Size()
 }
-func (m *ExportCache) XXX_DiscardUnknown() {
-	xxx_messageInfo_ExportCache.DiscardUnknown(m)
+func (m *HostPathOpt) XXX_DiscardUnknown() {
+	xxx_messageInfo_HostPathOpt.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ExportCache proto.InternalMessageInfo
+var xxx_messageInfo_HostPathOpt proto.InternalMessageInfo
 
-func (m *ExportCache) GetValue() bool {
+func (m *HostPathOpt) GetName() string {
 	if m != nil {
-		return m.Value
+		return m.Name
 	}
-	return false
+	return ""
 }
 
-type ProxyEnv struct {
-	HttpProxy  string `protobuf:"bytes,1,opt,name=http_proxy,json=httpProxy,proto3" json:"http_proxy,omitempty"`
-	HttpsProxy string `protobuf:"bytes,2,opt,name=https_proxy,json=httpsProxy,proto3" json:"https_proxy,omitempty"`
-	FtpProxy   string `protobuf:"bytes,3,opt,name=ftp_proxy,json=ftpProxy,proto3" json:"ftp_proxy,omitempty"`
-	NoProxy    string `protobuf:"bytes,4,opt,name=no_proxy,json=noProxy,proto3" json:"no_proxy,omitempty"`
-	AllProxy   string `protobuf:"bytes,5,opt,name=all_proxy,json=allProxy,proto3" json:"all_proxy,omitempty"`
+// SourceOp specifies a source such as build contexts and images.
+type SourceOp struct {
+	// TODO: use source type or any type instead of URL protocol.
+	// identifier e.g. local://, docker-image://, git://, https://...
+	Identifier string `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	// attrs are defined in attr.go
+	Attrs map[string]string `protobuf:"bytes,2,rep,name=attrs,proto3" json:"attrs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
-func (m *ProxyEnv) Reset()         { *m = ProxyEnv{} }
-func (m *ProxyEnv) String() string { return proto.CompactTextString(m) }
-func (*ProxyEnv) ProtoMessage()    {}
-func (*ProxyEnv) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{20}
+func (m *SourceOp) Reset()         { *m = SourceOp{} }
+func (m *SourceOp) String() string { return proto.CompactTextString(m) }
+func (*SourceOp) ProtoMessage()    {}
+func (*SourceOp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{19}
 }
-func (m *ProxyEnv) XXX_Unmarshal(b []byte) error {
+func (m *SourceOp) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *ProxyEnv) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *SourceOp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -1429,68 +1585,106 @@ func (m *ProxyEnv) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	}
 	return b[:n], nil
 }
-func (m *ProxyEnv) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ProxyEnv.Merge(m, src)
+func (m *SourceOp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SourceOp.Merge(m, src)
 }
-func (m *ProxyEnv) XXX_Size() int {
+func (m *SourceOp) XXX_Size() int {
 	return m.Size()
 }
-func (m *ProxyEnv) XXX_DiscardUnknown() {
-	xxx_messageInfo_ProxyEnv.DiscardUnknown(m)
+func (m *SourceOp) XXX_DiscardUnknown() {
+	xxx_messageInfo_SourceOp.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ProxyEnv proto.InternalMessageInfo
+var xxx_messageInfo_SourceOp proto.InternalMessageInfo
 
-func (m *ProxyEnv) GetHttpProxy() string {
+func (m *SourceOp) GetIdentifier() string {
 	if m != nil {
-		return m.HttpProxy
+		return m.Identifier
 	}
 	return ""
 }
 
-func (m *ProxyEnv) GetHttpsProxy() string {
+func (m *SourceOp) GetAttrs() map[string]string {
 	if m != nil {
-		return m.HttpsProxy
+		return m.Attrs
 	}
-	return ""
+	return nil
 }
 
-func (m *ProxyEnv) GetFtpProxy() string {
+// BuildOp is used for nested build invocation.
+// BuildOp is experimental and can break without backwards compatibility
+type BuildOp struct {
+	Builder InputIndex             `protobuf:"varint,1,opt,name=builder,proto3,customtype=InputIndex" json:"builder"`
+	Inputs  map[string]*BuildInput `protobuf:"bytes,2,rep,name=inputs,proto3" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Def     *Definition            `protobuf:"bytes,3,opt,name=def,proto3" json:"def,omitempty"`
+	Attrs   map[string]string      `protobuf:"bytes,4,rep,name=attrs,proto3" json:"attrs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *BuildOp) Reset()         { *m = BuildOp{} }
+func (m *BuildOp) String() string { return proto.CompactTextString(m) }
+func (*BuildOp) ProtoMessage()    {}
+func (*BuildOp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{20}
+}
+func (m *BuildOp) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *BuildOp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *BuildOp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BuildOp.Merge(m, src)
+}
+func (m *BuildOp) XXX_Size() int {
+	return m.Size()
+}
+func (m *BuildOp) XXX_DiscardUnknown() {
+	xxx_messageInfo_BuildOp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BuildOp proto.InternalMessageInfo
+
+func (m *BuildOp) GetInputs() map[string]*BuildInput {
 	if m != nil {
-		return m.FtpProxy
+		return m.Inputs
 	}
-	return ""
+	return nil
 }
 
-func (m *ProxyEnv) GetNoProxy() string {
+func (m *BuildOp) GetDef() *Definition {
 	if m != nil {
-		return m.NoProxy
+		return m.Def
 	}
-	return ""
+	return nil
 }
 
-func (m *ProxyEnv) GetAllProxy() string {
+func (m *BuildOp) GetAttrs() map[string]string {
 	if m != nil {
-		return m.AllProxy
+		return m.Attrs
 	}
-	return ""
+	return nil
 }
 
-// WorkerConstraints defines conditions for the worker
-type WorkerConstraints struct {
-	Filter []string `protobuf:"bytes,1,rep,name=filter,proto3" json:"filter,omitempty"`
+// BuildInput is used for BuildOp.
+type BuildInput struct {
+	Input InputIndex `protobuf:"varint,1,opt,name=input,proto3,customtype=InputIndex" json:"input"`
 }
 
-func (m *WorkerConstraints) Reset()         { *m = WorkerConstraints{} }
-func (m *WorkerConstraints) String() string { return proto.CompactTextString(m) }
-func (*WorkerConstraints) ProtoMessage()    {}
-func (*WorkerConstraints) Descriptor() ([]byte, []int) {
+func (m *BuildInput) Reset()         { *m = BuildInput{} }
+func (m *BuildInput) String() string { return proto.CompactTextString(m) }
+func (*BuildInput) ProtoMessage()    {}
+func (*BuildInput) Descriptor() ([]byte, []int) {
 	return fileDescriptor_8de16154b2733812, []int{21}
 }
-func (m *WorkerConstraints) XXX_Unmarshal(b []byte) error {
+func (m *BuildInput) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *WorkerConstraints) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *BuildInput) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -1498,46 +1692,41 @@ func (m *WorkerConstraints) XXX_Marshal(b []byte, deterministic bool) ([]byte, e
 	}
 	return b[:n], nil
 }
-func (m *WorkerConstraints) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_WorkerConstraints.Merge(m, src)
+func (m *BuildInput) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BuildInput.Merge(m, src)
 }
-func (m *WorkerConstraints) XXX_Size() int {
+func (m *BuildInput) XXX_Size() int {
 	return m.Size()
 }
-func (m *WorkerConstraints) XXX_DiscardUnknown() {
-	xxx_messageInfo_WorkerConstraints.DiscardUnknown(m)
+func (m *BuildInput) XXX_DiscardUnknown() {
+	xxx_messageInfo_BuildInput.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_WorkerConstraints proto.InternalMessageInfo
-
-func (m *WorkerConstraints) GetFilter() []string {
-	if m != nil {
-		return m.Filter
-	}
-	return nil
-}
+var xxx_messageInfo_BuildInput proto.InternalMessageInfo
 
-// Definition is the LLB definition structure with per-vertex metadata entries
-type Definition struct {
-	// def is a list of marshaled Op messages
-	Def [][]byte `protobuf:"bytes,1,rep,name=def,proto3" json:"def,omitempty"`
-	// metadata contains metadata for the each of the Op messages.
-	// A key must be an LLB op digest string. Currently, empty string is not expected as a key, but it may change in the future.
-	Metadata map[github_com_opencontainers_go_digest.Digest]OpMetadata `protobuf:"bytes,2,rep,name=metadata,proto3,castkey=github.com/opencontainers/go-digest.Digest" json:"metadata" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	// Source contains the source mapping information for the vertexes in the definition
-	Source *Source `protobuf:"bytes,3,opt,name=Source,proto3" json:"Source,omitempty"`
+// OpMetadata is a per-vertex metadata entry, which can be defined for arbitrary Op vertex and overridable on the run time.
+type OpMetadata struct {
+	// ignore_cache specifies to ignore the cache for this Op.
+	IgnoreCache bool `protobuf:"varint,1,opt,name=ignore_cache,json=ignoreCache,proto3" json:"ignore_cache,omitempty"`
+	// Description can be used for keeping any text fields that builder doesn't parse
+	Description map[string]string `protobuf:"bytes,2,rep,name=description,proto3" json:"description,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// index 3 reserved for WorkerConstraint in previous versions
+	// WorkerConstraint worker_constraint = 3;
+	ExportCache   *ExportCache                                         `protobuf:"bytes,4,opt,name=export_cache,json=exportCache,proto3" json:"export_cache,omitempty"`
+	Caps          map[github_com_moby_buildkit_util_apicaps.CapID]bool `protobuf:"bytes,5,rep,name=caps,proto3,castkey=github.com/moby/buildkit/util/apicaps.CapID" json:"caps" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	ProgressGroup *ProgressGroup                                       `protobuf:"bytes,6,opt,name=progress_group,json=progressGroup,proto3" json:"progress_group,omitempty"`
 }
 
-func (m *Definition) Reset()         { *m = Definition{} }
-func (m *Definition) String() string { return proto.CompactTextString(m) }
-func (*Definition) ProtoMessage()    {}
-func (*Definition) Descriptor() ([]byte, []int) {
+func (m *OpMetadata) Reset()         { *m = OpMetadata{} }
+func (m *OpMetadata) String() string { return proto.CompactTextString(m) }
+func (*OpMetadata) ProtoMessage()    {}
+func (*OpMetadata) Descriptor() ([]byte, []int) {
 	return fileDescriptor_8de16154b2733812, []int{22}
 }
-func (m *Definition) XXX_Unmarshal(b []byte) error {
+func (m *OpMetadata) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Definition) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *OpMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -1545,54 +1734,69 @@ func (m *Definition) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	}
 	return b[:n], nil
 }
-func (m *Definition) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Definition.Merge(m, src)
+func (m *OpMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OpMetadata.Merge(m, src)
 }
-func (m *Definition) XXX_Size() int {
+func (m *OpMetadata) XXX_Size() int {
 	return m.Size()
 }
-func (m *Definition) XXX_DiscardUnknown() {
-	xxx_messageInfo_Definition.DiscardUnknown(m)
+func (m *OpMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_OpMetadata.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Definition proto.InternalMessageInfo
+var xxx_messageInfo_OpMetadata proto.InternalMessageInfo
 
-func (m *Definition) GetDef() [][]byte {
+func (m *OpMetadata) GetIgnoreCache() bool {
 	if m != nil {
-		return m.Def
+		return m.IgnoreCache
+	}
+	return false
+}
+
+func (m *OpMetadata) GetDescription() map[string]string {
+	if m != nil {
+		return m.Description
 	}
 	return nil
 }
 
-func (m *Definition) GetMetadata() map[github_com_opencontainers_go_digest.Digest]OpMetadata {
+func (m *OpMetadata) GetExportCache() *ExportCache {
 	if m != nil {
-		return m.Metadata
+		return m.ExportCache
 	}
 	return nil
 }
 
-func (m *Definition) GetSource() *Source {
+func (m *OpMetadata) GetCaps() map[github_com_moby_buildkit_util_apicaps.CapID]bool {
 	if m != nil {
-		return m.Source
+		return m.Caps
 	}
 	return nil
 }
 
-type HostIP struct {
-	Host string `protobuf:"bytes,1,opt,name=Host,proto3" json:"Host,omitempty"`
-	IP   string `protobuf:"bytes,2,opt,name=IP,proto3" json:"IP,omitempty"`
+func (m *OpMetadata) GetProgressGroup() *ProgressGroup {
+	if m != nil {
+		return m.ProgressGroup
+	}
+	return nil
 }
 
-func (m *HostIP) Reset()         { *m = HostIP{} }
-func (m *HostIP) String() string { return proto.CompactTextString(m) }
-func (*HostIP) ProtoMessage()    {}
-func (*HostIP) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{23}
+// ProgressGroup groups the vertexes with the same id in the progress output
+type ProgressGroup struct {
+	Id   string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 }
-func (m *HostIP) XXX_Unmarshal(b []byte) error {
+
+func (m *ProgressGroup) Reset()         { *m = ProgressGroup{} }
+func (m *ProgressGroup) String() string { return proto.CompactTextString(m) }
+func (*ProgressGroup) ProtoMessage()    {}
+func (*ProgressGroup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{23}
+}
+func (m *ProgressGroup) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *HostIP) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *ProgressGroup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -1600,46 +1804,48 @@ func (m *HostIP) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	}
 	return b[:n], nil
 }
-func (m *HostIP) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_HostIP.Merge(m, src)
+func (m *ProgressGroup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProgressGroup.Merge(m, src)
 }
-func (m *HostIP) XXX_Size() int {
+func (m *ProgressGroup) XXX_Size() int {
 	return m.Size()
 }
-func (m *HostIP) XXX_DiscardUnknown() {
-	xxx_messageInfo_HostIP.DiscardUnknown(m)
+func (m *ProgressGroup) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProgressGroup.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_HostIP proto.InternalMessageInfo
+var xxx_messageInfo_ProgressGroup proto.InternalMessageInfo
 
-func (m *HostIP) GetHost() string {
+func (m *ProgressGroup) GetId() string {
 	if m != nil {
-		return m.Host
+		return m.Id
 	}
 	return ""
 }
 
-func (m *HostIP) GetIP() string {
+func (m *ProgressGroup) GetName() string {
 	if m != nil {
-		return m.IP
+		return m.Name
 	}
 	return ""
 }
 
-type FileOp struct {
-	Actions []*FileAction `protobuf:"bytes,2,rep,name=actions,proto3" json:"actions,omitempty"`
+// Source is a source mapping description for a file
+type Source struct {
+	Locations map[string]*Locations `protobuf:"bytes,1,rep,name=locations,proto3" json:"locations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Infos     []*SourceInfo         `protobuf:"bytes,2,rep,name=infos,proto3" json:"infos,omitempty"`
 }
 
-func (m *FileOp) Reset()         { *m = FileOp{} }
-func (m *FileOp) String() string { return proto.CompactTextString(m) }
-func (*FileOp) ProtoMessage()    {}
-func (*FileOp) Descriptor() ([]byte, []int) {
+func (m *Source) Reset()         { *m = Source{} }
+func (m *Source) String() string { return proto.CompactTextString(m) }
+func (*Source) ProtoMessage()    {}
+func (*Source) Descriptor() ([]byte, []int) {
 	return fileDescriptor_8de16154b2733812, []int{24}
 }
-func (m *FileOp) XXX_Unmarshal(b []byte) error {
+func (m *Source) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *FileOp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *Source) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -1647,47 +1853,47 @@ func (m *FileOp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	}
 	return b[:n], nil
 }
-func (m *FileOp) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_FileOp.Merge(m, src)
+func (m *Source) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Source.Merge(m, src)
 }
-func (m *FileOp) XXX_Size() int {
+func (m *Source) XXX_Size() int {
 	return m.Size()
 }
-func (m *FileOp) XXX_DiscardUnknown() {
-	xxx_messageInfo_FileOp.DiscardUnknown(m)
+func (m *Source) XXX_DiscardUnknown() {
+	xxx_messageInfo_Source.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_FileOp proto.InternalMessageInfo
+var xxx_messageInfo_Source proto.InternalMessageInfo
 
-func (m *FileOp) GetActions() []*FileAction {
+func (m *Source) GetLocations() map[string]*Locations {
 	if m != nil {
-		return m.Actions
+		return m.Locations
 	}
 	return nil
 }
 
-type FileAction struct {
-	Input          InputIndex  `protobuf:"varint,1,opt,name=input,proto3,customtype=InputIndex" json:"input"`
-	SecondaryInput InputIndex  `protobuf:"varint,2,opt,name=secondaryInput,proto3,customtype=InputIndex" json:"secondaryInput"`
-	Output         OutputIndex `protobuf:"varint,3,opt,name=output,proto3,customtype=OutputIndex" json:"output"`
-	// Types that are valid to be assigned to Action:
-	//	*FileAction_Copy
-	//	*FileAction_Mkfile
-	//	*FileAction_Mkdir
-	//	*FileAction_Rm
-	Action isFileAction_Action `protobuf_oneof:"action"`
+func (m *Source) GetInfos() []*SourceInfo {
+	if m != nil {
+		return m.Infos
+	}
+	return nil
 }
 
-func (m *FileAction) Reset()         { *m = FileAction{} }
-func (m *FileAction) String() string { return proto.CompactTextString(m) }
-func (*FileAction) ProtoMessage()    {}
-func (*FileAction) Descriptor() ([]byte, []int) {
+// Locations is a list of ranges with a index to its source map.
+type Locations struct {
+	Locations []*Location `protobuf:"bytes,1,rep,name=locations,proto3" json:"locations,omitempty"`
+}
+
+func (m *Locations) Reset()         { *m = Locations{} }
+func (m *Locations) String() string { return proto.CompactTextString(m) }
+func (*Locations) ProtoMessage()    {}
+func (*Locations) Descriptor() ([]byte, []int) {
 	return fileDescriptor_8de16154b2733812, []int{25}
 }
-func (m *FileAction) XXX_Unmarshal(b []byte) error {
+func (m *Locations) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *FileAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *Locations) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -1695,126 +1901,98 @@ func (m *FileAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	}
 	return b[:n], nil
 }
-func (m *FileAction) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_FileAction.Merge(m, src)
+func (m *Locations) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Locations.Merge(m, src)
 }
-func (m *FileAction) XXX_Size() int {
+func (m *Locations) XXX_Size() int {
 	return m.Size()
 }
-func (m *FileAction) XXX_DiscardUnknown() {
-	xxx_messageInfo_FileAction.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_FileAction proto.InternalMessageInfo
-
-type isFileAction_Action interface {
-	isFileAction_Action()
-	MarshalTo([]byte) (int, error)
-	Size() int
-}
-
-type FileAction_Copy struct {
-	Copy *FileActionCopy `protobuf:"bytes,4,opt,name=copy,proto3,oneof" json:"copy,omitempty"`
-}
-type FileAction_Mkfile struct {
-	Mkfile *FileActionMkFile `protobuf:"bytes,5,opt,name=mkfile,proto3,oneof" json:"mkfile,omitempty"`
-}
-type FileAction_Mkdir struct {
-	Mkdir *FileActionMkDir `protobuf:"bytes,6,opt,name=mkdir,proto3,oneof" json:"mkdir,omitempty"`
-}
-type FileAction_Rm struct {
-	Rm *FileActionRm `protobuf:"bytes,7,opt,name=rm,proto3,oneof" json:"rm,omitempty"`
+func (m *Locations) XXX_DiscardUnknown() {
+	xxx_messageInfo_Locations.DiscardUnknown(m)
 }
 
-func (*FileAction_Copy) isFileAction_Action()   {}
-func (*FileAction_Mkfile) isFileAction_Action() {}
-func (*FileAction_Mkdir) isFileAction_Action()  {}
-func (*FileAction_Rm) isFileAction_Action()     {}
+var xxx_messageInfo_Locations proto.InternalMessageInfo
 
-func (m *FileAction) GetAction() isFileAction_Action {
+func (m *Locations) GetLocations() []*Location {
 	if m != nil {
-		return m.Action
+		return m.Locations
 	}
 	return nil
 }
 
-func (m *FileAction) GetCopy() *FileActionCopy {
-	if x, ok := m.GetAction().(*FileAction_Copy); ok {
-		return x.Copy
-	}
-	return nil
+// Source info contains the shared metadata of a source mapping
+type SourceInfo struct {
+	Filename   string      `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Data       []byte      `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Definition *Definition `protobuf:"bytes,3,opt,name=definition,proto3" json:"definition,omitempty"`
 }
 
-func (m *FileAction) GetMkfile() *FileActionMkFile {
-	if x, ok := m.GetAction().(*FileAction_Mkfile); ok {
-		return x.Mkfile
+func (m *SourceInfo) Reset()         { *m = SourceInfo{} }
+func (m *SourceInfo) String() string { return proto.CompactTextString(m) }
+func (*SourceInfo) ProtoMessage()    {}
+func (*SourceInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{26}
+}
+func (m *SourceInfo) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SourceInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return b[:n], nil
+}
+func (m *SourceInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SourceInfo.Merge(m, src)
+}
+func (m *SourceInfo) XXX_Size() int {
+	return m.Size()
+}
+func (m *SourceInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_SourceInfo.DiscardUnknown(m)
 }
 
-func (m *FileAction) GetMkdir() *FileActionMkDir {
-	if x, ok := m.GetAction().(*FileAction_Mkdir); ok {
-		return x.Mkdir
+var xxx_messageInfo_SourceInfo proto.InternalMessageInfo
+
+func (m *SourceInfo) GetFilename() string {
+	if m != nil {
+		return m.Filename
 	}
-	return nil
+	return ""
 }
 
-func (m *FileAction) GetRm() *FileActionRm {
-	if x, ok := m.GetAction().(*FileAction_Rm); ok {
-		return x.Rm
+func (m *SourceInfo) GetData() []byte {
+	if m != nil {
+		return m.Data
 	}
 	return nil
 }
 
-// XXX_OneofWrappers is for the internal use of the proto package.
-func (*FileAction) XXX_OneofWrappers() []interface{} {
-	return []interface{}{
-		(*FileAction_Copy)(nil),
-		(*FileAction_Mkfile)(nil),
-		(*FileAction_Mkdir)(nil),
-		(*FileAction_Rm)(nil),
+func (m *SourceInfo) GetDefinition() *Definition {
+	if m != nil {
+		return m.Definition
 	}
+	return nil
 }
 
-type FileActionCopy struct {
-	// src is the source path
-	Src string `protobuf:"bytes,1,opt,name=src,proto3" json:"src,omitempty"`
-	// dest path
-	Dest string `protobuf:"bytes,2,opt,name=dest,proto3" json:"dest,omitempty"`
-	// optional owner override
-	Owner *ChownOpt `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"`
-	// optional permission bits override
-	Mode int32 `protobuf:"varint,4,opt,name=mode,proto3" json:"mode,omitempty"`
-	// followSymlink resolves symlinks in src
-	FollowSymlink bool `protobuf:"varint,5,opt,name=followSymlink,proto3" json:"followSymlink,omitempty"`
-	// dirCopyContents only copies contents if src is a directory
-	DirCopyContents bool `protobuf:"varint,6,opt,name=dirCopyContents,proto3" json:"dirCopyContents,omitempty"`
-	// attemptUnpackDockerCompatibility detects if src is an archive to unpack it instead
-	AttemptUnpackDockerCompatibility bool `protobuf:"varint,7,opt,name=attemptUnpackDockerCompatibility,proto3" json:"attemptUnpackDockerCompatibility,omitempty"`
-	// createDestPath creates dest path directories if needed
-	CreateDestPath bool `protobuf:"varint,8,opt,name=createDestPath,proto3" json:"createDestPath,omitempty"`
-	// allowWildcard allows filepath.Match wildcards in src path
-	AllowWildcard bool `protobuf:"varint,9,opt,name=allowWildcard,proto3" json:"allowWildcard,omitempty"`
-	// allowEmptyWildcard doesn't fail the whole copy if wildcard doesn't resolve to files
-	AllowEmptyWildcard bool `protobuf:"varint,10,opt,name=allowEmptyWildcard,proto3" json:"allowEmptyWildcard,omitempty"`
-	// optional created time override
-	Timestamp int64 `protobuf:"varint,11,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	// include only files/dirs matching at least one of these patterns
-	IncludePatterns []string `protobuf:"bytes,12,rep,name=include_patterns,json=includePatterns,proto3" json:"include_patterns,omitempty"`
-	// exclude files/dir matching any of these patterns (even if they match an include pattern)
-	ExcludePatterns []string `protobuf:"bytes,13,rep,name=exclude_patterns,json=excludePatterns,proto3" json:"exclude_patterns,omitempty"`
+// Location defines list of areas in to source file
+type Location struct {
+	SourceIndex int32    `protobuf:"varint,1,opt,name=sourceIndex,proto3" json:"sourceIndex,omitempty"`
+	Ranges      []*Range `protobuf:"bytes,2,rep,name=ranges,proto3" json:"ranges,omitempty"`
 }
 
-func (m *FileActionCopy) Reset()         { *m = FileActionCopy{} }
-func (m *FileActionCopy) String() string { return proto.CompactTextString(m) }
-func (*FileActionCopy) ProtoMessage()    {}
-func (*FileActionCopy) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{26}
+func (m *Location) Reset()         { *m = Location{} }
+func (m *Location) String() string { return proto.CompactTextString(m) }
+func (*Location) ProtoMessage()    {}
+func (*Location) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{27}
 }
-func (m *FileActionCopy) XXX_Unmarshal(b []byte) error {
+func (m *Location) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *FileActionCopy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *Location) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -1822,132 +2000,97 @@ func (m *FileActionCopy) XXX_Marshal(b []byte, deterministic bool) ([]byte, erro
 	}
 	return b[:n], nil
 }
-func (m *FileActionCopy) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_FileActionCopy.Merge(m, src)
+func (m *Location) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Location.Merge(m, src)
 }
-func (m *FileActionCopy) XXX_Size() int {
+func (m *Location) XXX_Size() int {
 	return m.Size()
 }
-func (m *FileActionCopy) XXX_DiscardUnknown() {
-	xxx_messageInfo_FileActionCopy.DiscardUnknown(m)
+func (m *Location) XXX_DiscardUnknown() {
+	xxx_messageInfo_Location.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_FileActionCopy proto.InternalMessageInfo
+var xxx_messageInfo_Location proto.InternalMessageInfo
 
-func (m *FileActionCopy) GetSrc() string {
+func (m *Location) GetSourceIndex() int32 {
 	if m != nil {
-		return m.Src
+		return m.SourceIndex
 	}
-	return ""
+	return 0
 }
 
-func (m *FileActionCopy) GetDest() string {
+func (m *Location) GetRanges() []*Range {
 	if m != nil {
-		return m.Dest
+		return m.Ranges
 	}
-	return ""
+	return nil
 }
 
-func (m *FileActionCopy) GetOwner() *ChownOpt {
-	if m != nil {
-		return m.Owner
-	}
-	return nil
-}
-
-func (m *FileActionCopy) GetMode() int32 {
-	if m != nil {
-		return m.Mode
-	}
-	return 0
+// Range is an area in the source file
+type Range struct {
+	Start Position `protobuf:"bytes,1,opt,name=start,proto3" json:"start"`
+	End   Position `protobuf:"bytes,2,opt,name=end,proto3" json:"end"`
 }
 
-func (m *FileActionCopy) GetFollowSymlink() bool {
-	if m != nil {
-		return m.FollowSymlink
-	}
-	return false
+func (m *Range) Reset()         { *m = Range{} }
+func (m *Range) String() string { return proto.CompactTextString(m) }
+func (*Range) ProtoMessage()    {}
+func (*Range) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{28}
 }
-
-func (m *FileActionCopy) GetDirCopyContents() bool {
-	if m != nil {
-		return m.DirCopyContents
-	}
-	return false
+func (m *Range) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-func (m *FileActionCopy) GetAttemptUnpackDockerCompatibility() bool {
-	if m != nil {
-		return m.AttemptUnpackDockerCompatibility
+func (m *Range) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
-	return false
+	return b[:n], nil
 }
-
-func (m *FileActionCopy) GetCreateDestPath() bool {
-	if m != nil {
-		return m.CreateDestPath
-	}
-	return false
+func (m *Range) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Range.Merge(m, src)
 }
-
-func (m *FileActionCopy) GetAllowWildcard() bool {
-	if m != nil {
-		return m.AllowWildcard
-	}
-	return false
+func (m *Range) XXX_Size() int {
+	return m.Size()
 }
-
-func (m *FileActionCopy) GetAllowEmptyWildcard() bool {
-	if m != nil {
-		return m.AllowEmptyWildcard
-	}
-	return false
+func (m *Range) XXX_DiscardUnknown() {
+	xxx_messageInfo_Range.DiscardUnknown(m)
 }
 
-func (m *FileActionCopy) GetTimestamp() int64 {
-	if m != nil {
-		return m.Timestamp
-	}
-	return 0
-}
+var xxx_messageInfo_Range proto.InternalMessageInfo
 
-func (m *FileActionCopy) GetIncludePatterns() []string {
+func (m *Range) GetStart() Position {
 	if m != nil {
-		return m.IncludePatterns
+		return m.Start
 	}
-	return nil
+	return Position{}
 }
 
-func (m *FileActionCopy) GetExcludePatterns() []string {
+func (m *Range) GetEnd() Position {
 	if m != nil {
-		return m.ExcludePatterns
+		return m.End
 	}
-	return nil
+	return Position{}
 }
 
-type FileActionMkFile struct {
-	// path for the new file
-	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	// permission bits
-	Mode int32 `protobuf:"varint,2,opt,name=mode,proto3" json:"mode,omitempty"`
-	// data is the new file contents
-	Data []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
-	// optional owner for the new file
-	Owner *ChownOpt `protobuf:"bytes,4,opt,name=owner,proto3" json:"owner,omitempty"`
-	// optional created time override
-	Timestamp int64 `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+// Position is single location in a source file
+type Position struct {
+	Line      int32 `protobuf:"varint,1,opt,name=Line,proto3" json:"Line,omitempty"`
+	Character int32 `protobuf:"varint,2,opt,name=Character,proto3" json:"Character,omitempty"`
 }
 
-func (m *FileActionMkFile) Reset()         { *m = FileActionMkFile{} }
-func (m *FileActionMkFile) String() string { return proto.CompactTextString(m) }
-func (*FileActionMkFile) ProtoMessage()    {}
-func (*FileActionMkFile) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{27}
+func (m *Position) Reset()         { *m = Position{} }
+func (m *Position) String() string { return proto.CompactTextString(m) }
+func (*Position) ProtoMessage()    {}
+func (*Position) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{29}
 }
-func (m *FileActionMkFile) XXX_Unmarshal(b []byte) error {
+func (m *Position) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *FileActionMkFile) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *Position) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -1955,76 +2098,90 @@ func (m *FileActionMkFile) XXX_Marshal(b []byte, deterministic bool) ([]byte, er
 	}
 	return b[:n], nil
 }
-func (m *FileActionMkFile) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_FileActionMkFile.Merge(m, src)
+func (m *Position) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Position.Merge(m, src)
 }
-func (m *FileActionMkFile) XXX_Size() int {
+func (m *Position) XXX_Size() int {
 	return m.Size()
 }
-func (m *FileActionMkFile) XXX_DiscardUnknown() {
-	xxx_messageInfo_FileActionMkFile.DiscardUnknown(m)
+func (m *Position) XXX_DiscardUnknown() {
+	xxx_messageInfo_Position.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_FileActionMkFile proto.InternalMessageInfo
+var xxx_messageInfo_Position proto.InternalMessageInfo
 
-func (m *FileActionMkFile) GetPath() string {
+func (m *Position) GetLine() int32 {
 	if m != nil {
-		return m.Path
+		return m.Line
 	}
-	return ""
+	return 0
 }
 
-func (m *FileActionMkFile) GetMode() int32 {
+func (m *Position) GetCharacter() int32 {
 	if m != nil {
-		return m.Mode
+		return m.Character
 	}
 	return 0
 }
 
-func (m *FileActionMkFile) GetData() []byte {
-	if m != nil {
-		return m.Data
-	}
-	return nil
+type ExportCache struct {
+	Value bool `protobuf:"varint,1,opt,name=Value,proto3" json:"Value,omitempty"`
 }
 
-func (m *FileActionMkFile) GetOwner() *ChownOpt {
-	if m != nil {
-		return m.Owner
+func (m *ExportCache) Reset()         { *m = ExportCache{} }
+func (m *ExportCache) String() string { return proto.CompactTextString(m) }
+func (*ExportCache) ProtoMessage()    {}
+func (*ExportCache) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{30}
+}
+func (m *ExportCache) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExportCache) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return b[:n], nil
+}
+func (m *ExportCache) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportCache.Merge(m, src)
+}
+func (m *ExportCache) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExportCache) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportCache.DiscardUnknown(m)
 }
 
-func (m *FileActionMkFile) GetTimestamp() int64 {
+var xxx_messageInfo_ExportCache proto.InternalMessageInfo
+
+func (m *ExportCache) GetValue() bool {
 	if m != nil {
-		return m.Timestamp
+		return m.Value
 	}
-	return 0
+	return false
 }
 
-type FileActionMkDir struct {
-	// path for the new directory
-	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	// permission bits
-	Mode int32 `protobuf:"varint,2,opt,name=mode,proto3" json:"mode,omitempty"`
-	// makeParents creates parent directories as well if needed
-	MakeParents bool `protobuf:"varint,3,opt,name=makeParents,proto3" json:"makeParents,omitempty"`
-	// optional owner for the new directory
-	Owner *ChownOpt `protobuf:"bytes,4,opt,name=owner,proto3" json:"owner,omitempty"`
-	// optional created time override
-	Timestamp int64 `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+type ProxyEnv struct {
+	HttpProxy  string `protobuf:"bytes,1,opt,name=http_proxy,json=httpProxy,proto3" json:"http_proxy,omitempty"`
+	HttpsProxy string `protobuf:"bytes,2,opt,name=https_proxy,json=httpsProxy,proto3" json:"https_proxy,omitempty"`
+	FtpProxy   string `protobuf:"bytes,3,opt,name=ftp_proxy,json=ftpProxy,proto3" json:"ftp_proxy,omitempty"`
+	NoProxy    string `protobuf:"bytes,4,opt,name=no_proxy,json=noProxy,proto3" json:"no_proxy,omitempty"`
+	AllProxy   string `protobuf:"bytes,5,opt,name=all_proxy,json=allProxy,proto3" json:"all_proxy,omitempty"`
 }
 
-func (m *FileActionMkDir) Reset()         { *m = FileActionMkDir{} }
-func (m *FileActionMkDir) String() string { return proto.CompactTextString(m) }
-func (*FileActionMkDir) ProtoMessage()    {}
-func (*FileActionMkDir) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{28}
+func (m *ProxyEnv) Reset()         { *m = ProxyEnv{} }
+func (m *ProxyEnv) String() string { return proto.CompactTextString(m) }
+func (*ProxyEnv) ProtoMessage()    {}
+func (*ProxyEnv) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{31}
 }
-func (m *FileActionMkDir) XXX_Unmarshal(b []byte) error {
+func (m *ProxyEnv) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *FileActionMkDir) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *ProxyEnv) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -2032,72 +2189,68 @@ func (m *FileActionMkDir) XXX_Marshal(b []byte, deterministic bool) ([]byte, err
 	}
 	return b[:n], nil
 }
-func (m *FileActionMkDir) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_FileActionMkDir.Merge(m, src)
+func (m *ProxyEnv) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProxyEnv.Merge(m, src)
 }
-func (m *FileActionMkDir) XXX_Size() int {
+func (m *ProxyEnv) XXX_Size() int {
 	return m.Size()
 }
-func (m *FileActionMkDir) XXX_DiscardUnknown() {
-	xxx_messageInfo_FileActionMkDir.DiscardUnknown(m)
+func (m *ProxyEnv) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProxyEnv.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_FileActionMkDir proto.InternalMessageInfo
+var xxx_messageInfo_ProxyEnv proto.InternalMessageInfo
 
-func (m *FileActionMkDir) GetPath() string {
+func (m *ProxyEnv) GetHttpProxy() string {
 	if m != nil {
-		return m.Path
+		return m.HttpProxy
 	}
 	return ""
 }
 
-func (m *FileActionMkDir) GetMode() int32 {
+func (m *ProxyEnv) GetHttpsProxy() string {
 	if m != nil {
-		return m.Mode
+		return m.HttpsProxy
 	}
-	return 0
+	return ""
 }
 
-func (m *FileActionMkDir) GetMakeParents() bool {
+func (m *ProxyEnv) GetFtpProxy() string {
 	if m != nil {
-		return m.MakeParents
+		return m.FtpProxy
 	}
-	return false
+	return ""
 }
 
-func (m *FileActionMkDir) GetOwner() *ChownOpt {
+func (m *ProxyEnv) GetNoProxy() string {
 	if m != nil {
-		return m.Owner
+		return m.NoProxy
 	}
-	return nil
+	return ""
 }
 
-func (m *FileActionMkDir) GetTimestamp() int64 {
+func (m *ProxyEnv) GetAllProxy() string {
 	if m != nil {
-		return m.Timestamp
+		return m.AllProxy
 	}
-	return 0
+	return ""
 }
 
-type FileActionRm struct {
-	// path to remove
-	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	// allowNotFound doesn't fail the rm if file is not found
-	AllowNotFound bool `protobuf:"varint,2,opt,name=allowNotFound,proto3" json:"allowNotFound,omitempty"`
-	// allowWildcard allows filepath.Match wildcards in path
-	AllowWildcard bool `protobuf:"varint,3,opt,name=allowWildcard,proto3" json:"allowWildcard,omitempty"`
+// WorkerConstraints defines conditions for the worker
+type WorkerConstraints struct {
+	Filter []string `protobuf:"bytes,1,rep,name=filter,proto3" json:"filter,omitempty"`
 }
 
-func (m *FileActionRm) Reset()         { *m = FileActionRm{} }
-func (m *FileActionRm) String() string { return proto.CompactTextString(m) }
-func (*FileActionRm) ProtoMessage()    {}
-func (*FileActionRm) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{29}
+func (m *WorkerConstraints) Reset()         { *m = WorkerConstraints{} }
+func (m *WorkerConstraints) String() string { return proto.CompactTextString(m) }
+func (*WorkerConstraints) ProtoMessage()    {}
+func (*WorkerConstraints) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{32}
 }
-func (m *FileActionRm) XXX_Unmarshal(b []byte) error {
+func (m *WorkerConstraints) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *FileActionRm) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *WorkerConstraints) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -2105,54 +2258,46 @@ func (m *FileActionRm) XXX_Marshal(b []byte, deterministic bool) ([]byte, error)
 	}
 	return b[:n], nil
 }
-func (m *FileActionRm) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_FileActionRm.Merge(m, src)
+func (m *WorkerConstraints) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorkerConstraints.Merge(m, src)
 }
-func (m *FileActionRm) XXX_Size() int {
+func (m *WorkerConstraints) XXX_Size() int {
 	return m.Size()
 }
-func (m *FileActionRm) XXX_DiscardUnknown() {
-	xxx_messageInfo_FileActionRm.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_FileActionRm proto.InternalMessageInfo
-
-func (m *FileActionRm) GetPath() string {
-	if m != nil {
-		return m.Path
-	}
-	return ""
+func (m *WorkerConstraints) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorkerConstraints.DiscardUnknown(m)
 }
 
-func (m *FileActionRm) GetAllowNotFound() bool {
-	if m != nil {
-		return m.AllowNotFound
-	}
-	return false
-}
+var xxx_messageInfo_WorkerConstraints proto.InternalMessageInfo
 
-func (m *FileActionRm) GetAllowWildcard() bool {
+func (m *WorkerConstraints) GetFilter() []string {
 	if m != nil {
-		return m.AllowWildcard
+		return m.Filter
 	}
-	return false
+	return nil
 }
 
-type ChownOpt struct {
-	User  *UserOpt `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
-	Group *UserOpt `protobuf:"bytes,2,opt,name=group,proto3" json:"group,omitempty"`
+// Definition is the LLB definition structure with per-vertex metadata entries
+type Definition struct {
+	// def is a list of marshaled Op messages
+	Def [][]byte `protobuf:"bytes,1,rep,name=def,proto3" json:"def,omitempty"`
+	// metadata contains metadata for the each of the Op messages.
+	// A key must be an LLB op digest string. Currently, empty string is not expected as a key, but it may change in the future.
+	Metadata map[github_com_opencontainers_go_digest.Digest]OpMetadata `protobuf:"bytes,2,rep,name=metadata,proto3,castkey=github.com/opencontainers/go-digest.Digest" json:"metadata" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Source contains the source mapping information for the vertexes in the definition
+	Source *Source `protobuf:"bytes,3,opt,name=Source,proto3" json:"Source,omitempty"`
 }
 
-func (m *ChownOpt) Reset()         { *m = ChownOpt{} }
-func (m *ChownOpt) String() string { return proto.CompactTextString(m) }
-func (*ChownOpt) ProtoMessage()    {}
-func (*ChownOpt) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{30}
+func (m *Definition) Reset()         { *m = Definition{} }
+func (m *Definition) String() string { return proto.CompactTextString(m) }
+func (*Definition) ProtoMessage()    {}
+func (*Definition) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{33}
 }
-func (m *ChownOpt) XXX_Unmarshal(b []byte) error {
+func (m *Definition) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *ChownOpt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *Definition) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -2160,49 +2305,54 @@ func (m *ChownOpt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	}
 	return b[:n], nil
 }
-func (m *ChownOpt) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ChownOpt.Merge(m, src)
+func (m *Definition) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Definition.Merge(m, src)
 }
-func (m *ChownOpt) XXX_Size() int {
+func (m *Definition) XXX_Size() int {
 	return m.Size()
 }
-func (m *ChownOpt) XXX_DiscardUnknown() {
-	xxx_messageInfo_ChownOpt.DiscardUnknown(m)
+func (m *Definition) XXX_DiscardUnknown() {
+	xxx_messageInfo_Definition.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ChownOpt proto.InternalMessageInfo
+var xxx_messageInfo_Definition proto.InternalMessageInfo
 
-func (m *ChownOpt) GetUser() *UserOpt {
+func (m *Definition) GetDef() [][]byte {
 	if m != nil {
-		return m.User
+		return m.Def
 	}
 	return nil
 }
 
-func (m *ChownOpt) GetGroup() *UserOpt {
+func (m *Definition) GetMetadata() map[github_com_opencontainers_go_digest.Digest]OpMetadata {
 	if m != nil {
-		return m.Group
+		return m.Metadata
 	}
 	return nil
 }
 
-type UserOpt struct {
-	// Types that are valid to be assigned to User:
-	//	*UserOpt_ByName
-	//	*UserOpt_ByID
-	User isUserOpt_User `protobuf_oneof:"user"`
+func (m *Definition) GetSource() *Source {
+	if m != nil {
+		return m.Source
+	}
+	return nil
 }
 
-func (m *UserOpt) Reset()         { *m = UserOpt{} }
-func (m *UserOpt) String() string { return proto.CompactTextString(m) }
-func (*UserOpt) ProtoMessage()    {}
-func (*UserOpt) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{31}
+type HostIP struct {
+	Host string `protobuf:"bytes,1,opt,name=Host,proto3" json:"Host,omitempty"`
+	IP   string `protobuf:"bytes,2,opt,name=IP,proto3" json:"IP,omitempty"`
 }
-func (m *UserOpt) XXX_Unmarshal(b []byte) error {
+
+func (m *HostIP) Reset()         { *m = HostIP{} }
+func (m *HostIP) String() string { return proto.CompactTextString(m) }
+func (*HostIP) ProtoMessage()    {}
+func (*HostIP) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{34}
+}
+func (m *HostIP) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *UserOpt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *HostIP) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -2210,78 +2360,94 @@ func (m *UserOpt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	}
 	return b[:n], nil
 }
-func (m *UserOpt) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_UserOpt.Merge(m, src)
+func (m *HostIP) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HostIP.Merge(m, src)
 }
-func (m *UserOpt) XXX_Size() int {
+func (m *HostIP) XXX_Size() int {
 	return m.Size()
 }
-func (m *UserOpt) XXX_DiscardUnknown() {
-	xxx_messageInfo_UserOpt.DiscardUnknown(m)
+func (m *HostIP) XXX_DiscardUnknown() {
+	xxx_messageInfo_HostIP.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_UserOpt proto.InternalMessageInfo
-
-type isUserOpt_User interface {
-	isUserOpt_User()
-	MarshalTo([]byte) (int, error)
-	Size() int
-}
+var xxx_messageInfo_HostIP proto.InternalMessageInfo
 
-type UserOpt_ByName struct {
-	ByName *NamedUserOpt `protobuf:"bytes,1,opt,name=byName,proto3,oneof" json:"byName,omitempty"`
-}
-type UserOpt_ByID struct {
-	ByID uint32 `protobuf:"varint,2,opt,name=byID,proto3,oneof" json:"byID,omitempty"`
+func (m *HostIP) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
 }
 
-func (*UserOpt_ByName) isUserOpt_User() {}
-func (*UserOpt_ByID) isUserOpt_User()   {}
-
-func (m *UserOpt) GetUser() isUserOpt_User {
+func (m *HostIP) GetIP() string {
 	if m != nil {
-		return m.User
+		return m.IP
 	}
-	return nil
+	return ""
 }
 
-func (m *UserOpt) GetByName() *NamedUserOpt {
-	if x, ok := m.GetUser().(*UserOpt_ByName); ok {
-		return x.ByName
-	}
-	return nil
+type FileOp struct {
+	Actions []*FileAction `protobuf:"bytes,2,rep,name=actions,proto3" json:"actions,omitempty"`
 }
 
-func (m *UserOpt) GetByID() uint32 {
-	if x, ok := m.GetUser().(*UserOpt_ByID); ok {
-		return x.ByID
+func (m *FileOp) Reset()         { *m = FileOp{} }
+func (m *FileOp) String() string { return proto.CompactTextString(m) }
+func (*FileOp) ProtoMessage()    {}
+func (*FileOp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{35}
+}
+func (m *FileOp) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FileOp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
-	return 0
+	return b[:n], nil
+}
+func (m *FileOp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FileOp.Merge(m, src)
+}
+func (m *FileOp) XXX_Size() int {
+	return m.Size()
+}
+func (m *FileOp) XXX_DiscardUnknown() {
+	xxx_messageInfo_FileOp.DiscardUnknown(m)
 }
 
-// XXX_OneofWrappers is for the internal use of the proto package.
-func (*UserOpt) XXX_OneofWrappers() []interface{} {
-	return []interface{}{
-		(*UserOpt_ByName)(nil),
-		(*UserOpt_ByID)(nil),
+var xxx_messageInfo_FileOp proto.InternalMessageInfo
+
+func (m *FileOp) GetActions() []*FileAction {
+	if m != nil {
+		return m.Actions
 	}
+	return nil
 }
 
-type NamedUserOpt struct {
-	Name  string     `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Input InputIndex `protobuf:"varint,2,opt,name=input,proto3,customtype=InputIndex" json:"input"`
+type FileAction struct {
+	Input          InputIndex  `protobuf:"varint,1,opt,name=input,proto3,customtype=InputIndex" json:"input"`
+	SecondaryInput InputIndex  `protobuf:"varint,2,opt,name=secondaryInput,proto3,customtype=InputIndex" json:"secondaryInput"`
+	Output         OutputIndex `protobuf:"varint,3,opt,name=output,proto3,customtype=OutputIndex" json:"output"`
+	// Types that are valid to be assigned to Action:
+	//	*FileAction_Copy
+	//	*FileAction_Mkfile
+	//	*FileAction_Mkdir
+	//	*FileAction_Rm
+	Action isFileAction_Action `protobuf_oneof:"action"`
 }
 
-func (m *NamedUserOpt) Reset()         { *m = NamedUserOpt{} }
-func (m *NamedUserOpt) String() string { return proto.CompactTextString(m) }
-func (*NamedUserOpt) ProtoMessage()    {}
-func (*NamedUserOpt) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8de16154b2733812, []int{32}
+func (m *FileAction) Reset()         { *m = FileAction{} }
+func (m *FileAction) String() string { return proto.CompactTextString(m) }
+func (*FileAction) ProtoMessage()    {}
+func (*FileAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{36}
 }
-func (m *NamedUserOpt) XXX_Unmarshal(b []byte) error {
+func (m *FileAction) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *NamedUserOpt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *FileAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	b = b[:cap(b)]
 	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
@@ -2289,1052 +2455,886 @@ func (m *NamedUserOpt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error)
 	}
 	return b[:n], nil
 }
-func (m *NamedUserOpt) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_NamedUserOpt.Merge(m, src)
+func (m *FileAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FileAction.Merge(m, src)
 }
-func (m *NamedUserOpt) XXX_Size() int {
+func (m *FileAction) XXX_Size() int {
 	return m.Size()
 }
-func (m *NamedUserOpt) XXX_DiscardUnknown() {
-	xxx_messageInfo_NamedUserOpt.DiscardUnknown(m)
+func (m *FileAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_FileAction.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_NamedUserOpt proto.InternalMessageInfo
+var xxx_messageInfo_FileAction proto.InternalMessageInfo
 
-func (m *NamedUserOpt) GetName() string {
-	if m != nil {
-		return m.Name
-	}
-	return ""
+type isFileAction_Action interface {
+	isFileAction_Action()
+	MarshalTo([]byte) (int, error)
+	Size() int
 }
 
-func init() {
-	proto.RegisterEnum("pb.NetMode", NetMode_name, NetMode_value)
-	proto.RegisterEnum("pb.SecurityMode", SecurityMode_name, SecurityMode_value)
-	proto.RegisterEnum("pb.MountType", MountType_name, MountType_value)
-	proto.RegisterEnum("pb.CacheSharingOpt", CacheSharingOpt_name, CacheSharingOpt_value)
-	proto.RegisterType((*Op)(nil), "pb.Op")
-	proto.RegisterType((*Platform)(nil), "pb.Platform")
-	proto.RegisterType((*Input)(nil), "pb.Input")
-	proto.RegisterType((*ExecOp)(nil), "pb.ExecOp")
-	proto.RegisterType((*Meta)(nil), "pb.Meta")
-	proto.RegisterType((*Mount)(nil), "pb.Mount")
-	proto.RegisterType((*CacheOpt)(nil), "pb.CacheOpt")
-	proto.RegisterType((*SecretOpt)(nil), "pb.SecretOpt")
-	proto.RegisterType((*SSHOpt)(nil), "pb.SSHOpt")
-	proto.RegisterType((*SourceOp)(nil), "pb.SourceOp")
-	proto.RegisterMapType((map[string]string)(nil), "pb.SourceOp.AttrsEntry")
-	proto.RegisterType((*BuildOp)(nil), "pb.BuildOp")
-	proto.RegisterMapType((map[string]string)(nil), "pb.BuildOp.AttrsEntry")
-	proto.RegisterMapType((map[string]*BuildInput)(nil), "pb.BuildOp.InputsEntry")
-	proto.RegisterType((*BuildInput)(nil), "pb.BuildInput")
-	proto.RegisterType((*OpMetadata)(nil), "pb.OpMetadata")
-	proto.RegisterMapType((map[github_com_moby_buildkit_util_apicaps.CapID]bool)(nil), "pb.OpMetadata.CapsEntry")
-	proto.RegisterMapType((map[string]string)(nil), "pb.OpMetadata.DescriptionEntry")
-	proto.RegisterType((*Source)(nil), "pb.Source")
-	proto.RegisterMapType((map[string]*Locations)(nil), "pb.Source.LocationsEntry")
-	proto.RegisterType((*Locations)(nil), "pb.Locations")
-	proto.RegisterType((*SourceInfo)(nil), "pb.SourceInfo")
-	proto.RegisterType((*Location)(nil), "pb.Location")
-	proto.RegisterType((*Range)(nil), "pb.Range")
-	proto.RegisterType((*Position)(nil), "pb.Position")
-	proto.RegisterType((*ExportCache)(nil), "pb.ExportCache")
-	proto.RegisterType((*ProxyEnv)(nil), "pb.ProxyEnv")
-	proto.RegisterType((*WorkerConstraints)(nil), "pb.WorkerConstraints")
-	proto.RegisterType((*Definition)(nil), "pb.Definition")
-	proto.RegisterMapType((map[github_com_opencontainers_go_digest.Digest]OpMetadata)(nil), "pb.Definition.MetadataEntry")
-	proto.RegisterType((*HostIP)(nil), "pb.HostIP")
-	proto.RegisterType((*FileOp)(nil), "pb.FileOp")
-	proto.RegisterType((*FileAction)(nil), "pb.FileAction")
-	proto.RegisterType((*FileActionCopy)(nil), "pb.FileActionCopy")
-	proto.RegisterType((*FileActionMkFile)(nil), "pb.FileActionMkFile")
-	proto.RegisterType((*FileActionMkDir)(nil), "pb.FileActionMkDir")
-	proto.RegisterType((*FileActionRm)(nil), "pb.FileActionRm")
-	proto.RegisterType((*ChownOpt)(nil), "pb.ChownOpt")
-	proto.RegisterType((*UserOpt)(nil), "pb.UserOpt")
-	proto.RegisterType((*NamedUserOpt)(nil), "pb.NamedUserOpt")
+type FileAction_Copy struct {
+	Copy *FileActionCopy `protobuf:"bytes,4,opt,name=copy,proto3,oneof" json:"copy,omitempty"`
+}
+type FileAction_Mkfile struct {
+	Mkfile *FileActionMkFile `protobuf:"bytes,5,opt,name=mkfile,proto3,oneof" json:"mkfile,omitempty"`
+}
+type FileAction_Mkdir struct {
+	Mkdir *FileActionMkDir `protobuf:"bytes,6,opt,name=mkdir,proto3,oneof" json:"mkdir,omitempty"`
+}
+type FileAction_Rm struct {
+	Rm *FileActionRm `protobuf:"bytes,7,opt,name=rm,proto3,oneof" json:"rm,omitempty"`
 }
 
-func init() { proto.RegisterFile("ops.proto", fileDescriptor_8de16154b2733812) }
+func (*FileAction_Copy) isFileAction_Action()   {}
+func (*FileAction_Mkfile) isFileAction_Action() {}
+func (*FileAction_Mkdir) isFileAction_Action()  {}
+func (*FileAction_Rm) isFileAction_Action()     {}
 
-var fileDescriptor_8de16154b2733812 = []byte{
-	// 2267 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x58, 0xcd, 0x6e, 0x1c, 0xc7,
-	0xf1, 0xe7, 0x7e, 0xef, 0xd6, 0x2e, 0xa9, 0xfd, 0xb7, 0x65, 0x7b, 0xcd, 0xbf, 0x42, 0xd2, 0x63,
-	0xc7, 0xa0, 0x28, 0x69, 0x09, 0xd0, 0x80, 0x65, 0x18, 0x41, 0x10, 0xee, 0x87, 0xc0, 0xb5, 0x25,
-	0x2e, 0xd1, 0x2b, 0xc9, 0xb9, 0x09, 0xc3, 0xd9, 0x26, 0x39, 0xe0, 0xec, 0xf4, 0xa0, 0xa7, 0x57,
-	0xe2, 0x5e, 0x72, 0xf0, 0x13, 0x18, 0x08, 0x90, 0x5b, 0x12, 0xe4, 0x1d, 0x72, 0xcd, 0x31, 0x80,
-	0x8f, 0x3e, 0xe4, 0x60, 0xe4, 0xe0, 0x04, 0xd2, 0x3d, 0x4f, 0x90, 0x00, 0x41, 0x55, 0xf7, 0x7c,
-	0x2c, 0x45, 0x41, 0x12, 0x12, 0xe4, 0x34, 0xdd, 0x55, 0xbf, 0xaa, 0xae, 0xae, 0xaa, 0xae, 0xae,
-	0x1e, 0x68, 0xc8, 0x28, 0xee, 0x46, 0x4a, 0x6a, 0xc9, 0x8a, 0xd1, 0xf1, 0xfa, 0x9d, 0x53, 0x5f,
-	0x9f, 0xcd, 0x8f, 0xbb, 0x9e, 0x9c, 0xed, 0x9e, 0xca, 0x53, 0xb9, 0x4b, 0xac, 0xe3, 0xf9, 0x09,
-	0xcd, 0x68, 0x42, 0x23, 0x23, 0xe2, 0xfc, 0xa1, 0x08, 0xc5, 0x71, 0xc4, 0x3e, 0x84, 0xaa, 0x1f,
-	0x46, 0x73, 0x1d, 0x77, 0x0a, 0x5b, 0xa5, 0xed, 0xe6, 0x5e, 0xa3, 0x1b, 0x1d, 0x77, 0x47, 0x48,
-	0xe1, 0x96, 0xc1, 0xb6, 0xa0, 0x2c, 0x2e, 0x84, 0xd7, 0x29, 0x6e, 0x15, 0xb6, 0x9b, 0x7b, 0x80,
-	0x80, 0xe1, 0x85, 0xf0, 0xc6, 0xd1, 0xc1, 0x0a, 0x27, 0x0e, 0xfb, 0x04, 0xaa, 0xb1, 0x9c, 0x2b,
-	0x4f, 0x74, 0x4a, 0x84, 0x69, 0x21, 0x66, 0x42, 0x14, 0x42, 0x59, 0x2e, 0x6a, 0x3a, 0xf1, 0x03,
-	0xd1, 0x29, 0x67, 0x9a, 0xee, 0xf9, 0x81, 0xc1, 0x10, 0x87, 0x7d, 0x04, 0x95, 0xe3, 0xb9, 0x1f,
-	0x4c, 0x3b, 0x15, 0x82, 0x34, 0x11, 0xd2, 0x43, 0x02, 0x61, 0x0c, 0x8f, 0x6d, 0x43, 0x3d, 0x0a,
-	0x5c, 0x7d, 0x22, 0xd5, 0xac, 0x03, 0xd9, 0x82, 0x47, 0x96, 0xc6, 0x53, 0x2e, 0xbb, 0x0b, 0x4d,
-	0x4f, 0x86, 0xb1, 0x56, 0xae, 0x1f, 0xea, 0xb8, 0xd3, 0x24, 0xf0, 0xbb, 0x08, 0xfe, 0x5a, 0xaa,
-	0x73, 0xa1, 0xfa, 0x19, 0x93, 0xe7, 0x91, 0xbd, 0x32, 0x14, 0x65, 0xe4, 0xfc, 0xa6, 0x00, 0xf5,
-	0x44, 0x2b, 0x73, 0xa0, 0xb5, 0xaf, 0xbc, 0x33, 0x5f, 0x0b, 0x4f, 0xcf, 0x95, 0xe8, 0x14, 0xb6,
-	0x0a, 0xdb, 0x0d, 0xbe, 0x44, 0x63, 0x6b, 0x50, 0x1c, 0x4f, 0xc8, 0x51, 0x0d, 0x5e, 0x1c, 0x4f,
-	0x58, 0x07, 0x6a, 0x8f, 0x5d, 0xe5, 0xbb, 0xa1, 0x26, 0xcf, 0x34, 0x78, 0x32, 0x65, 0x37, 0xa0,
-	0x31, 0x9e, 0x3c, 0x16, 0x2a, 0xf6, 0x65, 0x48, 0xfe, 0x68, 0xf0, 0x8c, 0xc0, 0x36, 0x00, 0xc6,
-	0x93, 0x7b, 0xc2, 0x45, 0xa5, 0x71, 0xa7, 0xb2, 0x55, 0xda, 0x6e, 0xf0, 0x1c, 0xc5, 0xf9, 0x15,
-	0x54, 0x28, 0x46, 0xec, 0x4b, 0xa8, 0x4e, 0xfd, 0x53, 0x11, 0x6b, 0x63, 0x4e, 0x6f, 0xef, 0xbb,
-	0x1f, 0x37, 0x57, 0xfe, 0xfa, 0xe3, 0xe6, 0x4e, 0x2e, 0x19, 0x64, 0x24, 0x42, 0x4f, 0x86, 0xda,
-	0xf5, 0x43, 0xa1, 0xe2, 0xdd, 0x53, 0x79, 0xc7, 0x88, 0x74, 0x07, 0xf4, 0xe1, 0x56, 0x03, 0xbb,
-	0x09, 0x15, 0x3f, 0x9c, 0x8a, 0x0b, 0xb2, 0xbf, 0xd4, 0x7b, 0xc7, 0xaa, 0x6a, 0x8e, 0xe7, 0x3a,
-	0x9a, 0xeb, 0x11, 0xb2, 0xb8, 0x41, 0x38, 0xbf, 0x2b, 0x40, 0xd5, 0xe4, 0x00, 0xbb, 0x01, 0xe5,
-	0x99, 0xd0, 0x2e, 0xad, 0xdf, 0xdc, 0xab, 0xa3, 0x6f, 0x1f, 0x08, 0xed, 0x72, 0xa2, 0x62, 0x7a,
-	0xcd, 0xe4, 0x1c, 0x7d, 0x5f, 0xcc, 0xd2, 0xeb, 0x01, 0x52, 0xb8, 0x65, 0xb0, 0x9f, 0x42, 0x2d,
-	0x14, 0xfa, 0x99, 0x54, 0xe7, 0xe4, 0xa3, 0x35, 0x13, 0xf4, 0x43, 0xa1, 0x1f, 0xc8, 0xa9, 0xe0,
-	0x09, 0x8f, 0xdd, 0x86, 0x7a, 0x2c, 0xbc, 0xb9, 0xf2, 0xf5, 0x82, 0xfc, 0xb5, 0xb6, 0xd7, 0xa6,
-	0x2c, 0xb3, 0x34, 0x02, 0xa7, 0x08, 0xe7, 0xcf, 0x05, 0x28, 0xa3, 0x19, 0x8c, 0x41, 0xd9, 0x55,
-	0xa7, 0x26, 0xbb, 0x1b, 0x9c, 0xc6, 0xac, 0x0d, 0x25, 0x11, 0x3e, 0x25, 0x8b, 0x1a, 0x1c, 0x87,
-	0x48, 0xf1, 0x9e, 0x4d, 0x6d, 0x8c, 0x70, 0x88, 0x72, 0xf3, 0x58, 0x28, 0x1b, 0x1a, 0x1a, 0xb3,
-	0x9b, 0xd0, 0x88, 0x94, 0xbc, 0x58, 0x3c, 0x41, 0xe9, 0x4a, 0x2e, 0xf1, 0x90, 0x38, 0x0c, 0x9f,
-	0xf2, 0x7a, 0x64, 0x47, 0x6c, 0x07, 0x40, 0x5c, 0x68, 0xe5, 0x1e, 0xc8, 0x58, 0xc7, 0x9d, 0x2a,
-	0xed, 0x9d, 0xf2, 0x1d, 0x09, 0xa3, 0x23, 0x9e, 0xe3, 0xb2, 0x75, 0xa8, 0x9f, 0xc9, 0x58, 0x87,
-	0xee, 0x4c, 0x74, 0x6a, 0xb4, 0x5c, 0x3a, 0x77, 0xfe, 0x51, 0x84, 0x0a, 0xb9, 0x8b, 0x6d, 0x63,
-	0x74, 0xa2, 0xb9, 0x09, 0x74, 0xa9, 0xc7, 0x6c, 0x74, 0x80, 0xf2, 0x20, 0x0d, 0x0e, 0xe6, 0xc4,
-	0x3a, 0x7a, 0x2a, 0x10, 0x9e, 0x96, 0xca, 0xa6, 0x62, 0x3a, 0xc7, 0x6d, 0x4d, 0x31, 0x5b, 0xcc,
-	0x4e, 0x69, 0xcc, 0x6e, 0x41, 0x55, 0x52, 0x88, 0x69, 0xb3, 0xaf, 0x08, 0xbc, 0x85, 0xa0, 0x72,
-	0x25, 0xdc, 0xa9, 0x0c, 0x83, 0x05, 0xb9, 0xa0, 0xce, 0xd3, 0x39, 0xbb, 0x05, 0x0d, 0x8a, 0xe9,
-	0xc3, 0x45, 0x24, 0x3a, 0x55, 0x8a, 0xd1, 0x6a, 0x1a, 0x6f, 0x24, 0xf2, 0x8c, 0x8f, 0x87, 0xd8,
-	0x73, 0xbd, 0x33, 0x31, 0x8e, 0x74, 0xe7, 0x7a, 0xe6, 0xcb, 0xbe, 0xa5, 0xf1, 0x94, 0x8b, 0x6a,
-	0x63, 0xe1, 0x29, 0xa1, 0x11, 0xfa, 0x2e, 0x41, 0x57, 0x6d, 0xe8, 0x0d, 0x91, 0x67, 0x7c, 0xe6,
-	0x40, 0x75, 0x32, 0x39, 0x40, 0xe4, 0x7b, 0x59, 0x91, 0x31, 0x14, 0x6e, 0x39, 0x66, 0x0f, 0xf1,
-	0x3c, 0xd0, 0xa3, 0x41, 0xe7, 0x7d, 0xe3, 0xa0, 0x64, 0xee, 0x8c, 0xa0, 0x9e, 0x98, 0x80, 0xa7,
-	0x79, 0x34, 0xb0, 0xe7, 0xbc, 0x38, 0x1a, 0xb0, 0x3b, 0x50, 0x8b, 0xcf, 0x5c, 0xe5, 0x87, 0xa7,
-	0xe4, 0xd7, 0xb5, 0xbd, 0x77, 0x52, 0x8b, 0x27, 0x86, 0x8e, 0xab, 0x24, 0x18, 0x47, 0x42, 0x23,
-	0x35, 0xf1, 0x25, 0x5d, 0x6d, 0x28, 0xcd, 0xfd, 0x29, 0xe9, 0x59, 0xe5, 0x38, 0x44, 0xca, 0xa9,
-	0x6f, 0x72, 0x70, 0x95, 0xe3, 0x10, 0x83, 0x35, 0x93, 0x53, 0x53, 0x2e, 0x57, 0x39, 0x8d, 0xd1,
-	0x76, 0x19, 0x69, 0x5f, 0x86, 0x6e, 0x90, 0xf8, 0x3f, 0x99, 0x3b, 0x41, 0xb2, 0xf7, 0xff, 0xc9,
-	0x6a, 0xbf, 0x2e, 0x40, 0x3d, 0xa9, 0xf1, 0x58, 0xb0, 0xfc, 0xa9, 0x08, 0xb5, 0x7f, 0xe2, 0x0b,
-	0x65, 0x17, 0xce, 0x51, 0xd8, 0x1d, 0xa8, 0xb8, 0x5a, 0xab, 0xa4, 0x0c, 0xbc, 0x9f, 0xbf, 0x20,
-	0xba, 0xfb, 0xc8, 0x19, 0x86, 0x5a, 0x2d, 0xb8, 0x41, 0xad, 0x7f, 0x0e, 0x90, 0x11, 0xd1, 0xd6,
-	0x73, 0xb1, 0xb0, 0x5a, 0x71, 0xc8, 0xae, 0x43, 0xe5, 0xa9, 0x1b, 0xcc, 0x85, 0xcd, 0x6f, 0x33,
-	0xf9, 0xa2, 0xf8, 0x79, 0xc1, 0xf9, 0x53, 0x11, 0x6a, 0xf6, 0xc2, 0x60, 0xb7, 0xa1, 0x46, 0x17,
-	0x86, 0xb5, 0xe8, 0xea, 0x43, 0x93, 0x40, 0xd8, 0x6e, 0x7a, 0x13, 0xe6, 0x6c, 0xb4, 0xaa, 0xcc,
-	0x8d, 0x68, 0x6d, 0xcc, 0xee, 0xc5, 0xd2, 0x54, 0x9c, 0xd8, 0x2b, 0x6f, 0x0d, 0xd1, 0x03, 0x71,
-	0xe2, 0x87, 0x3e, 0xfa, 0x87, 0x23, 0x8b, 0xdd, 0x4e, 0x76, 0x5d, 0x26, 0x8d, 0xef, 0xe5, 0x35,
-	0xbe, 0xbc, 0xe9, 0x11, 0x34, 0x73, 0xcb, 0x5c, 0xb1, 0xeb, 0x8f, 0xf3, 0xbb, 0xb6, 0x4b, 0x92,
-	0x3a, 0x73, 0x5f, 0x67, 0x5e, 0xf8, 0x0f, 0xfc, 0xf7, 0x19, 0x40, 0xa6, 0xf2, 0xcd, 0x8b, 0x8e,
-	0xf3, 0x4d, 0x09, 0x60, 0x1c, 0x61, 0xc9, 0x9d, 0xba, 0x54, 0xf7, 0x5b, 0xfe, 0x69, 0x28, 0x95,
-	0x78, 0x42, 0xc7, 0x98, 0xe4, 0xeb, 0xbc, 0x69, 0x68, 0x74, 0x62, 0xd8, 0x3e, 0x34, 0xa7, 0x22,
-	0xf6, 0x94, 0x4f, 0x09, 0x65, 0x9d, 0xbe, 0x89, 0x7b, 0xca, 0xf4, 0x74, 0x07, 0x19, 0xc2, 0xf8,
-	0x2a, 0x2f, 0xc3, 0xf6, 0xa0, 0x25, 0x2e, 0x22, 0xa9, 0xb4, 0x5d, 0xc5, 0xf4, 0x15, 0xd7, 0x4c,
-	0x87, 0x82, 0x74, 0x5a, 0x89, 0x37, 0x45, 0x36, 0x61, 0x2e, 0x94, 0x3d, 0x37, 0x32, 0x97, 0x6a,
-	0x73, 0xaf, 0x73, 0x69, 0xbd, 0xbe, 0x1b, 0x19, 0xa7, 0xf5, 0x3e, 0xc5, 0xbd, 0x7e, 0xf3, 0xb7,
-	0xcd, 0x5b, 0xb9, 0x9b, 0x74, 0x26, 0x8f, 0x17, 0xbb, 0x94, 0x2f, 0xe7, 0xbe, 0xde, 0x9d, 0x6b,
-	0x3f, 0xd8, 0x75, 0x23, 0x1f, 0xd5, 0xa1, 0xe0, 0x68, 0xc0, 0x49, 0xf5, 0xfa, 0xcf, 0xa1, 0x7d,
-	0xd9, 0xee, 0xb7, 0x89, 0xc1, 0xfa, 0x5d, 0x68, 0xa4, 0x76, 0xbc, 0x4e, 0xb0, 0x9e, 0x0f, 0xde,
-	0x1f, 0x0b, 0x50, 0x35, 0xa7, 0x8a, 0xdd, 0x85, 0x46, 0x20, 0x3d, 0x17, 0x0d, 0x48, 0x5a, 0xbb,
-	0x0f, 0xb2, 0x43, 0xd7, 0xbd, 0x9f, 0xf0, 0x8c, 0x57, 0x33, 0x2c, 0x26, 0x99, 0x1f, 0x9e, 0xc8,
-	0xe4, 0x14, 0xac, 0x65, 0x42, 0xa3, 0xf0, 0x44, 0x72, 0xc3, 0x5c, 0xff, 0x0a, 0xd6, 0x96, 0x55,
-	0x5c, 0x61, 0xe7, 0x47, 0xcb, 0xe9, 0x4a, 0x35, 0x3b, 0x15, 0xca, 0x9b, 0x7d, 0x17, 0x1a, 0x29,
-	0x9d, 0xed, 0xbc, 0x6c, 0x78, 0x2b, 0x2f, 0x99, 0xb3, 0xd5, 0x09, 0x00, 0x32, 0xd3, 0xb0, 0x58,
-	0x61, 0x0f, 0x49, 0xf7, 0xa8, 0x31, 0x23, 0x9d, 0xd3, 0xbd, 0xe7, 0x6a, 0x97, 0x4c, 0x69, 0x71,
-	0x1a, 0xb3, 0x2e, 0xc0, 0x34, 0x3d, 0xb0, 0xaf, 0x38, 0xc6, 0x39, 0x84, 0x33, 0x86, 0x7a, 0x62,
-	0x04, 0xdb, 0x82, 0x66, 0x6c, 0x57, 0xc6, 0x8e, 0x09, 0x97, 0xab, 0xf0, 0x3c, 0x09, 0x3b, 0x1f,
-	0xe5, 0x86, 0xa7, 0x62, 0xa9, 0xf3, 0xe1, 0x48, 0xe1, 0x96, 0xe1, 0x7c, 0x0d, 0x15, 0x22, 0xe0,
-	0x31, 0x8b, 0xb5, 0xab, 0xb4, 0x6d, 0xa2, 0x4c, 0x53, 0x21, 0x63, 0x5a, 0xb6, 0x57, 0xc6, 0x44,
-	0xe4, 0x06, 0xc0, 0x3e, 0xc6, 0xd6, 0x65, 0x6a, 0x3d, 0x7a, 0x15, 0x0e, 0xd9, 0xce, 0xcf, 0xa0,
-	0x9e, 0x90, 0x71, 0xe7, 0xf7, 0xfd, 0x50, 0x58, 0x13, 0x69, 0x8c, 0xcd, 0x67, 0xff, 0xcc, 0x55,
-	0xae, 0xa7, 0x85, 0x69, 0x11, 0x2a, 0x3c, 0x23, 0x38, 0x1f, 0x41, 0x33, 0x77, 0x7a, 0x30, 0xdd,
-	0x1e, 0x53, 0x18, 0xcd, 0x19, 0x36, 0x13, 0xe7, 0xf7, 0xd8, 0x1a, 0x27, 0xdd, 0xce, 0x4f, 0x00,
-	0xce, 0xb4, 0x8e, 0x9e, 0x50, 0xfb, 0x63, 0x7d, 0xdf, 0x40, 0x0a, 0x21, 0xd8, 0x26, 0x34, 0x71,
-	0x12, 0x5b, 0xbe, 0xc9, 0x77, 0x92, 0x88, 0x0d, 0xe0, 0xff, 0xa1, 0x71, 0x92, 0x8a, 0x97, 0x6c,
-	0xe8, 0x12, 0xe9, 0x0f, 0xa0, 0x1e, 0x4a, 0xcb, 0x33, 0xdd, 0x58, 0x2d, 0x94, 0xa9, 0x9c, 0x1b,
-	0x04, 0x96, 0x57, 0x31, 0x72, 0x6e, 0x10, 0x10, 0xd3, 0xb9, 0x05, 0xff, 0xf7, 0x52, 0x93, 0xcf,
-	0xde, 0x83, 0xea, 0x89, 0x1f, 0x68, 0xba, 0x11, 0xb0, 0xfb, 0xb3, 0x33, 0xe7, 0x5f, 0x05, 0x80,
-	0x2c, 0xec, 0x98, 0xcc, 0x58, 0xda, 0x11, 0xd3, 0x32, 0xa5, 0x3c, 0x80, 0xfa, 0xcc, 0x16, 0x09,
-	0x1b, 0xd0, 0x1b, 0xcb, 0xa9, 0xd2, 0x4d, 0x6a, 0x88, 0x29, 0x1f, 0x7b, 0xb6, 0x7c, 0xbc, 0x4d,
-	0x23, 0x9e, 0xae, 0x40, 0x5d, 0x4c, 0xfe, 0x41, 0x05, 0xd9, 0x29, 0xe4, 0x96, 0xb3, 0xfe, 0x15,
-	0xac, 0x2e, 0x2d, 0xf9, 0x86, 0x17, 0x46, 0x56, 0xec, 0xf2, 0x47, 0xf0, 0x36, 0x54, 0x4d, 0x67,
-	0x8a, 0xf9, 0x82, 0x23, 0xab, 0x86, 0xc6, 0xd4, 0x4e, 0x1c, 0x25, 0xcf, 0x9a, 0xd1, 0x91, 0xb3,
-	0x07, 0x55, 0xf3, 0x6e, 0x63, 0xdb, 0x50, 0x73, 0x3d, 0x73, 0x56, 0x73, 0xf5, 0x02, 0x99, 0xfb,
-	0x44, 0xe6, 0x09, 0xdb, 0xf9, 0x4b, 0x11, 0x20, 0xa3, 0xbf, 0x45, 0x3b, 0xfb, 0x05, 0xac, 0xc5,
-	0xc2, 0x93, 0xe1, 0xd4, 0x55, 0x0b, 0xe2, 0xda, 0xf7, 0xc9, 0x55, 0x22, 0x97, 0x90, 0xb9, 0xd6,
-	0xb6, 0xf4, 0xfa, 0xd6, 0x76, 0x1b, 0xca, 0x9e, 0x8c, 0x16, 0xf6, 0x16, 0x61, 0xcb, 0x1b, 0xe9,
-	0xcb, 0x68, 0x81, 0xaf, 0x54, 0x44, 0xb0, 0x2e, 0x54, 0x67, 0xe7, 0xf4, 0x92, 0x35, 0xaf, 0x80,
-	0xeb, 0xcb, 0xd8, 0x07, 0xe7, 0x38, 0xc6, 0x77, 0xaf, 0x41, 0xb1, 0x5b, 0x50, 0x99, 0x9d, 0x4f,
-	0x7d, 0x45, 0x4d, 0x71, 0xd3, 0xb4, 0x8d, 0x79, 0xf8, 0xc0, 0x57, 0xf8, 0xba, 0x25, 0x0c, 0x73,
-	0xa0, 0xa8, 0x66, 0xf4, 0x10, 0x68, 0x9a, 0x27, 0x4e, 0xce, 0x9b, 0xb3, 0x83, 0x15, 0x5e, 0x54,
-	0xb3, 0x5e, 0x1d, 0xaa, 0xc6, 0xaf, 0xce, 0x3f, 0x4b, 0xb0, 0xb6, 0x6c, 0x25, 0xe6, 0x41, 0xac,
-	0xbc, 0x24, 0x0f, 0x62, 0xe5, 0xa5, 0x5d, 0x7f, 0x31, 0xd7, 0xf5, 0x3b, 0x50, 0x91, 0xcf, 0x42,
-	0xa1, 0xf2, 0x4f, 0xf6, 0xfe, 0x99, 0x7c, 0x16, 0x62, 0x0f, 0x6b, 0x58, 0x4b, 0x2d, 0x61, 0xc5,
-	0xb6, 0x84, 0x1f, 0xc3, 0xea, 0x89, 0x0c, 0x02, 0xf9, 0x6c, 0xb2, 0x98, 0x05, 0x7e, 0x78, 0x6e,
-	0xfb, 0xc2, 0x65, 0x22, 0xdb, 0x86, 0x6b, 0x53, 0x5f, 0xa1, 0x39, 0x7d, 0x19, 0x6a, 0x11, 0xd2,
-	0x23, 0x08, 0x71, 0x97, 0xc9, 0xec, 0x4b, 0xd8, 0x72, 0xb5, 0x16, 0xb3, 0x48, 0x3f, 0x0a, 0x23,
-	0xd7, 0x3b, 0x1f, 0x48, 0x8f, 0xce, 0xec, 0x2c, 0x72, 0xb5, 0x7f, 0xec, 0x07, 0xf8, 0xde, 0xab,
-	0x91, 0xe8, 0x6b, 0x71, 0xec, 0x13, 0x58, 0xf3, 0x94, 0x70, 0xb5, 0x18, 0x88, 0x58, 0x1f, 0xb9,
-	0xfa, 0xac, 0x53, 0x27, 0xc9, 0x4b, 0x54, 0xdc, 0x83, 0x8b, 0xd6, 0x7e, 0xed, 0x07, 0x53, 0xcf,
-	0x55, 0xd3, 0x4e, 0xc3, 0xec, 0x61, 0x89, 0xc8, 0xba, 0xc0, 0x88, 0x30, 0x9c, 0x45, 0x7a, 0x91,
-	0x42, 0x81, 0xa0, 0x57, 0x70, 0xb0, 0xaa, 0x6a, 0x7f, 0x26, 0x62, 0xed, 0xce, 0x22, 0xfa, 0xd5,
-	0x50, 0xe2, 0x19, 0x81, 0xdd, 0x84, 0xb6, 0x1f, 0x7a, 0xc1, 0x7c, 0x2a, 0x9e, 0x44, 0xb8, 0x11,
-	0x15, 0xc6, 0x9d, 0x16, 0xd5, 0xa0, 0x6b, 0x96, 0x7e, 0x64, 0xc9, 0x08, 0x15, 0x17, 0x97, 0xa0,
-	0xab, 0x06, 0x6a, 0xe9, 0x09, 0xd4, 0xf9, 0xb6, 0x00, 0xed, 0xcb, 0x89, 0x87, 0x61, 0x8b, 0x70,
-	0xf3, 0xf6, 0x08, 0xe3, 0x38, 0x0d, 0x65, 0x31, 0x17, 0xca, 0xe4, 0x52, 0x2c, 0xe5, 0x2e, 0xc5,
-	0x34, 0x2d, 0xca, 0xaf, 0x4e, 0x8b, 0xa5, 0x8d, 0x56, 0x2e, 0x6d, 0xd4, 0xf9, 0x6d, 0x01, 0xae,
-	0x5d, 0x4a, 0xee, 0x37, 0xb6, 0x68, 0x0b, 0x9a, 0x33, 0xf7, 0x5c, 0x1c, 0xb9, 0x8a, 0x52, 0xa6,
-	0x64, 0xba, 0xc6, 0x1c, 0xe9, 0xbf, 0x60, 0x5f, 0x08, 0xad, 0xfc, 0x89, 0xba, 0xd2, 0xb6, 0x24,
-	0x41, 0x0e, 0xa5, 0xbe, 0x27, 0xe7, 0xf6, 0xc2, 0x4d, 0x12, 0x24, 0x21, 0xbe, 0x9c, 0x46, 0xa5,
-	0x2b, 0xd2, 0xc8, 0x39, 0x84, 0x7a, 0x62, 0x20, 0xdb, 0xb4, 0x7f, 0x15, 0x0a, 0xd9, 0xdf, 0xad,
-	0x47, 0xb1, 0x50, 0x68, 0xbb, 0xf9, 0xc5, 0xf0, 0x21, 0x54, 0x4e, 0x95, 0x9c, 0x47, 0xb6, 0x62,
-	0x2f, 0x21, 0x0c, 0xc7, 0x99, 0x40, 0xcd, 0x52, 0xd8, 0x0e, 0x54, 0x8f, 0x17, 0x87, 0x49, 0xbf,
-	0x63, 0xcb, 0x05, 0xce, 0xa7, 0x16, 0x81, 0x35, 0xc8, 0x20, 0xd8, 0x75, 0x28, 0x1f, 0x2f, 0x46,
-	0x03, 0xf3, 0x06, 0xc4, 0x4a, 0x86, 0xb3, 0x5e, 0xd5, 0x18, 0xe4, 0xdc, 0x87, 0x56, 0x5e, 0x0e,
-	0x9d, 0x92, 0xeb, 0xa3, 0x68, 0x9c, 0x95, 0xec, 0xe2, 0x6b, 0x4a, 0xf6, 0xce, 0x36, 0xd4, 0xec,
-	0xff, 0x1b, 0xd6, 0x80, 0xca, 0xa3, 0xc3, 0xc9, 0xf0, 0x61, 0x7b, 0x85, 0xd5, 0xa1, 0x7c, 0x30,
-	0x9e, 0x3c, 0x6c, 0x17, 0x70, 0x74, 0x38, 0x3e, 0x1c, 0xb6, 0x8b, 0x3b, 0x37, 0xa1, 0x95, 0xff,
-	0x83, 0xc3, 0x9a, 0x50, 0x9b, 0xec, 0x1f, 0x0e, 0x7a, 0xe3, 0x5f, 0xb6, 0x57, 0x58, 0x0b, 0xea,
-	0xa3, 0xc3, 0xc9, 0xb0, 0xff, 0x88, 0x0f, 0xdb, 0x85, 0x9d, 0x5f, 0x40, 0x23, 0xfd, 0x91, 0x80,
-	0x1a, 0x7a, 0xa3, 0xc3, 0x41, 0x7b, 0x85, 0x01, 0x54, 0x27, 0xc3, 0x3e, 0x1f, 0xa2, 0xde, 0x1a,
-	0x94, 0x26, 0x93, 0x83, 0x76, 0x11, 0x57, 0xed, 0xef, 0xf7, 0x0f, 0x86, 0xed, 0x12, 0x0e, 0x1f,
-	0x3e, 0x38, 0xba, 0x37, 0x69, 0x97, 0x77, 0x3e, 0x83, 0x6b, 0x97, 0x1e, 0xeb, 0x24, 0x7d, 0xb0,
-	0xcf, 0x87, 0xa8, 0xa9, 0x09, 0xb5, 0x23, 0x3e, 0x7a, 0xbc, 0xff, 0x70, 0xd8, 0x2e, 0x20, 0xe3,
-	0xfe, 0xb8, 0xff, 0xd5, 0x70, 0xd0, 0x2e, 0xf6, 0x6e, 0x7c, 0xf7, 0x7c, 0xa3, 0xf0, 0xfd, 0xf3,
-	0x8d, 0xc2, 0x0f, 0xcf, 0x37, 0x0a, 0x7f, 0x7f, 0xbe, 0x51, 0xf8, 0xf6, 0xc5, 0xc6, 0xca, 0xf7,
-	0x2f, 0x36, 0x56, 0x7e, 0x78, 0xb1, 0xb1, 0x72, 0x5c, 0xa5, 0xff, 0xa9, 0x9f, 0xfe, 0x3b, 0x00,
-	0x00, 0xff, 0xff, 0x3a, 0xd6, 0xef, 0x88, 0x8f, 0x15, 0x00, 0x00,
+func (m *FileAction) GetAction() isFileAction_Action {
+	if m != nil {
+		return m.Action
+	}
+	return nil
 }
 
-func (m *Op) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *FileAction) GetCopy() *FileActionCopy {
+	if x, ok := m.GetAction().(*FileAction_Copy); ok {
+		return x.Copy
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *Op) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *FileAction) GetMkfile() *FileActionMkFile {
+	if x, ok := m.GetAction().(*FileAction_Mkfile); ok {
+		return x.Mkfile
+	}
+	return nil
 }
 
-func (m *Op) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Constraints != nil {
-		{
-			size, err := m.Constraints.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintOps(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x5a
-	}
-	if m.Platform != nil {
-		{
-			size, err := m.Platform.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintOps(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x52
+func (m *FileAction) GetMkdir() *FileActionMkDir {
+	if x, ok := m.GetAction().(*FileAction_Mkdir); ok {
+		return x.Mkdir
 	}
-	if m.Op != nil {
-		{
-			size := m.Op.Size()
-			i -= size
-			if _, err := m.Op.MarshalTo(dAtA[i:]); err != nil {
-				return 0, err
-			}
-		}
+	return nil
+}
+
+func (m *FileAction) GetRm() *FileActionRm {
+	if x, ok := m.GetAction().(*FileAction_Rm); ok {
+		return x.Rm
 	}
-	if len(m.Inputs) > 0 {
-		for iNdEx := len(m.Inputs) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Inputs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintOps(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*FileAction) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*FileAction_Copy)(nil),
+		(*FileAction_Mkfile)(nil),
+		(*FileAction_Mkdir)(nil),
+		(*FileAction_Rm)(nil),
 	}
-	return len(dAtA) - i, nil
 }
 
-func (m *Op_Exec) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type FileActionCopy struct {
+	// src is the source path
+	Src string `protobuf:"bytes,1,opt,name=src,proto3" json:"src,omitempty"`
+	// dest path
+	Dest string `protobuf:"bytes,2,opt,name=dest,proto3" json:"dest,omitempty"`
+	// optional owner override
+	Owner *ChownOpt `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"`
+	// optional permission bits override
+	Mode int32 `protobuf:"varint,4,opt,name=mode,proto3" json:"mode,omitempty"`
+	// followSymlink resolves symlinks in src
+	FollowSymlink bool `protobuf:"varint,5,opt,name=followSymlink,proto3" json:"followSymlink,omitempty"`
+	// dirCopyContents only copies contents if src is a directory
+	DirCopyContents bool `protobuf:"varint,6,opt,name=dirCopyContents,proto3" json:"dirCopyContents,omitempty"`
+	// attemptUnpackDockerCompatibility detects if src is an archive to unpack it instead
+	AttemptUnpackDockerCompatibility bool `protobuf:"varint,7,opt,name=attemptUnpackDockerCompatibility,proto3" json:"attemptUnpackDockerCompatibility,omitempty"`
+	// createDestPath creates dest path directories if needed
+	CreateDestPath bool `protobuf:"varint,8,opt,name=createDestPath,proto3" json:"createDestPath,omitempty"`
+	// allowWildcard allows filepath.Match wildcards in src path
+	AllowWildcard bool `protobuf:"varint,9,opt,name=allowWildcard,proto3" json:"allowWildcard,omitempty"`
+	// allowEmptyWildcard doesn't fail the whole copy if wildcard doesn't resolve to files
+	AllowEmptyWildcard bool `protobuf:"varint,10,opt,name=allowEmptyWildcard,proto3" json:"allowEmptyWildcard,omitempty"`
+	// optional created time override
+	Timestamp int64 `protobuf:"varint,11,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// include only files/dirs matching at least one of these patterns
+	IncludePatterns []string `protobuf:"bytes,12,rep,name=include_patterns,json=includePatterns,proto3" json:"include_patterns,omitempty"`
+	// exclude files/dir matching any of these patterns (even if they match an include pattern)
+	ExcludePatterns []string `protobuf:"bytes,13,rep,name=exclude_patterns,json=excludePatterns,proto3" json:"exclude_patterns,omitempty"`
+	// timestampNow sets the created time of copied files to the time of the copy, overrides timestamp
+	TimestampNow bool `protobuf:"varint,14,opt,name=timestampNow,proto3" json:"timestampNow,omitempty"`
+	// optional permission bits of the parent directories created for dest, 0755 if not set
+	DirMode int32 `protobuf:"varint,15,opt,name=dirMode,proto3" json:"dirMode,omitempty"`
 }
 
-func (m *Op_Exec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	if m.Exec != nil {
-		{
-			size, err := m.Exec.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintOps(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x12
+func (m *FileActionCopy) Reset()         { *m = FileActionCopy{} }
+func (m *FileActionCopy) String() string { return proto.CompactTextString(m) }
+func (*FileActionCopy) ProtoMessage()    {}
+func (*FileActionCopy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{37}
+}
+func (m *FileActionCopy) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FileActionCopy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
-	return len(dAtA) - i, nil
+	return b[:n], nil
 }
-func (m *Op_Source) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *FileActionCopy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FileActionCopy.Merge(m, src)
+}
+func (m *FileActionCopy) XXX_Size() int {
+	return m.Size()
+}
+func (m *FileActionCopy) XXX_DiscardUnknown() {
+	xxx_messageInfo_FileActionCopy.DiscardUnknown(m)
 }
 
-func (m *Op_Source) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	if m.Source != nil {
-		{
-			size, err := m.Source.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintOps(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x1a
+var xxx_messageInfo_FileActionCopy proto.InternalMessageInfo
+
+func (m *FileActionCopy) GetSrc() string {
+	if m != nil {
+		return m.Src
 	}
-	return len(dAtA) - i, nil
+	return ""
 }
-func (m *Op_File) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+
+func (m *FileActionCopy) GetDest() string {
+	if m != nil {
+		return m.Dest
+	}
+	return ""
 }
 
-func (m *Op_File) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	if m.File != nil {
-		{
-			size, err := m.File.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintOps(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x22
+func (m *FileActionCopy) GetOwner() *ChownOpt {
+	if m != nil {
+		return m.Owner
 	}
-	return len(dAtA) - i, nil
-}
-func (m *Op_Build) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+	return nil
 }
 
-func (m *Op_Build) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	if m.Build != nil {
-		{
-			size, err := m.Build.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintOps(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x2a
+func (m *FileActionCopy) GetMode() int32 {
+	if m != nil {
+		return m.Mode
 	}
-	return len(dAtA) - i, nil
+	return 0
 }
-func (m *Platform) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+
+func (m *FileActionCopy) GetFollowSymlink() bool {
+	if m != nil {
+		return m.FollowSymlink
 	}
-	return dAtA[:n], nil
+	return false
 }
 
-func (m *Platform) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *FileActionCopy) GetDirCopyContents() bool {
+	if m != nil {
+		return m.DirCopyContents
+	}
+	return false
 }
 
-func (m *Platform) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.OSFeatures) > 0 {
-		for iNdEx := len(m.OSFeatures) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.OSFeatures[iNdEx])
-			copy(dAtA[i:], m.OSFeatures[iNdEx])
-			i = encodeVarintOps(dAtA, i, uint64(len(m.OSFeatures[iNdEx])))
-			i--
-			dAtA[i] = 0x2a
-		}
-	}
-	if len(m.OSVersion) > 0 {
-		i -= len(m.OSVersion)
-		copy(dAtA[i:], m.OSVersion)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.OSVersion)))
-		i--
-		dAtA[i] = 0x22
-	}
-	if len(m.Variant) > 0 {
-		i -= len(m.Variant)
-		copy(dAtA[i:], m.Variant)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.Variant)))
-		i--
-		dAtA[i] = 0x1a
-	}
-	if len(m.OS) > 0 {
-		i -= len(m.OS)
-		copy(dAtA[i:], m.OS)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.OS)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if len(m.Architecture) > 0 {
-		i -= len(m.Architecture)
-		copy(dAtA[i:], m.Architecture)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.Architecture)))
-		i--
-		dAtA[i] = 0xa
+func (m *FileActionCopy) GetAttemptUnpackDockerCompatibility() bool {
+	if m != nil {
+		return m.AttemptUnpackDockerCompatibility
 	}
-	return len(dAtA) - i, nil
+	return false
 }
 
-func (m *Input) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *FileActionCopy) GetCreateDestPath() bool {
+	if m != nil {
+		return m.CreateDestPath
 	}
-	return dAtA[:n], nil
+	return false
 }
 
-func (m *Input) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *FileActionCopy) GetAllowWildcard() bool {
+	if m != nil {
+		return m.AllowWildcard
+	}
+	return false
 }
 
-func (m *Input) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Index != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Index))
-		i--
-		dAtA[i] = 0x10
-	}
-	if len(m.Digest) > 0 {
-		i -= len(m.Digest)
-		copy(dAtA[i:], m.Digest)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.Digest)))
-		i--
-		dAtA[i] = 0xa
+func (m *FileActionCopy) GetAllowEmptyWildcard() bool {
+	if m != nil {
+		return m.AllowEmptyWildcard
 	}
-	return len(dAtA) - i, nil
+	return false
 }
 
-func (m *ExecOp) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *FileActionCopy) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *ExecOp) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *FileActionCopy) GetIncludePatterns() []string {
+	if m != nil {
+		return m.IncludePatterns
+	}
+	return nil
 }
 
-func (m *ExecOp) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Security != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Security))
-		i--
-		dAtA[i] = 0x20
-	}
-	if m.Network != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Network))
-		i--
-		dAtA[i] = 0x18
+func (m *FileActionCopy) GetExcludePatterns() []string {
+	if m != nil {
+		return m.ExcludePatterns
 	}
-	if len(m.Mounts) > 0 {
-		for iNdEx := len(m.Mounts) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Mounts[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintOps(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x12
-		}
+	return nil
+}
+
+func (m *FileActionCopy) GetTimestampNow() bool {
+	if m != nil {
+		return m.TimestampNow
 	}
-	if m.Meta != nil {
-		{
-			size, err := m.Meta.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintOps(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0xa
+	return false
+}
+
+func (m *FileActionCopy) GetDirMode() int32 {
+	if m != nil {
+		return m.DirMode
 	}
-	return len(dAtA) - i, nil
+	return 0
 }
 
-func (m *Meta) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+type FileActionMkFile struct {
+	// path for the new file
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// permission bits
+	Mode int32 `protobuf:"varint,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	// data is the new file contents
+	Data []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	// optional owner for the new file
+	Owner *ChownOpt `protobuf:"bytes,4,opt,name=owner,proto3" json:"owner,omitempty"`
+	// optional created time override
+	Timestamp int64 `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (m *FileActionMkFile) Reset()         { *m = FileActionMkFile{} }
+func (m *FileActionMkFile) String() string { return proto.CompactTextString(m) }
+func (*FileActionMkFile) ProtoMessage()    {}
+func (*FileActionMkFile) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{38}
+}
+func (m *FileActionMkFile) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FileActionMkFile) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return b[:n], nil
 }
-
-func (m *Meta) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *FileActionMkFile) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FileActionMkFile.Merge(m, src)
+}
+func (m *FileActionMkFile) XXX_Size() int {
+	return m.Size()
+}
+func (m *FileActionMkFile) XXX_DiscardUnknown() {
+	xxx_messageInfo_FileActionMkFile.DiscardUnknown(m)
 }
 
-func (m *Meta) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Hostname) > 0 {
-		i -= len(m.Hostname)
-		copy(dAtA[i:], m.Hostname)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.Hostname)))
-		i--
-		dAtA[i] = 0x3a
-	}
-	if len(m.ExtraHosts) > 0 {
-		for iNdEx := len(m.ExtraHosts) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.ExtraHosts[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintOps(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x32
-		}
-	}
-	if m.ProxyEnv != nil {
-		{
-			size, err := m.ProxyEnv.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintOps(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x2a
-	}
-	if len(m.User) > 0 {
-		i -= len(m.User)
-		copy(dAtA[i:], m.User)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.User)))
-		i--
-		dAtA[i] = 0x22
-	}
-	if len(m.Cwd) > 0 {
-		i -= len(m.Cwd)
-		copy(dAtA[i:], m.Cwd)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.Cwd)))
-		i--
-		dAtA[i] = 0x1a
-	}
-	if len(m.Env) > 0 {
-		for iNdEx := len(m.Env) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Env[iNdEx])
-			copy(dAtA[i:], m.Env[iNdEx])
-			i = encodeVarintOps(dAtA, i, uint64(len(m.Env[iNdEx])))
-			i--
-			dAtA[i] = 0x12
-		}
-	}
-	if len(m.Args) > 0 {
-		for iNdEx := len(m.Args) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Args[iNdEx])
-			copy(dAtA[i:], m.Args[iNdEx])
-			i = encodeVarintOps(dAtA, i, uint64(len(m.Args[iNdEx])))
-			i--
-			dAtA[i] = 0xa
-		}
+var xxx_messageInfo_FileActionMkFile proto.InternalMessageInfo
+
+func (m *FileActionMkFile) GetPath() string {
+	if m != nil {
+		return m.Path
 	}
-	return len(dAtA) - i, nil
+	return ""
 }
 
-func (m *Mount) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *FileActionMkFile) GetMode() int32 {
+	if m != nil {
+		return m.Mode
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *Mount) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *FileActionMkFile) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
 }
 
-func (m *Mount) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.ResultID) > 0 {
-		i -= len(m.ResultID)
-		copy(dAtA[i:], m.ResultID)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.ResultID)))
-		i--
-		dAtA[i] = 0x1
-		i--
-		dAtA[i] = 0xba
-	}
-	if m.SSHOpt != nil {
-		{
-			size, err := m.SSHOpt.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintOps(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x1
-		i--
-		dAtA[i] = 0xb2
-	}
-	if m.SecretOpt != nil {
-		{
-			size, err := m.SecretOpt.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintOps(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x1
-		i--
-		dAtA[i] = 0xaa
-	}
-	if m.CacheOpt != nil {
-		{
-			size, err := m.CacheOpt.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintOps(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x1
-		i--
-		dAtA[i] = 0xa2
-	}
-	if m.MountType != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.MountType))
-		i--
-		dAtA[i] = 0x30
-	}
-	if m.Readonly {
-		i--
-		if m.Readonly {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
-		i--
-		dAtA[i] = 0x28
-	}
-	if m.Output != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Output))
-		i--
-		dAtA[i] = 0x20
-	}
-	if len(m.Dest) > 0 {
-		i -= len(m.Dest)
-		copy(dAtA[i:], m.Dest)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.Dest)))
-		i--
-		dAtA[i] = 0x1a
-	}
-	if len(m.Selector) > 0 {
-		i -= len(m.Selector)
-		copy(dAtA[i:], m.Selector)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.Selector)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if m.Input != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Input))
-		i--
-		dAtA[i] = 0x8
+func (m *FileActionMkFile) GetOwner() *ChownOpt {
+	if m != nil {
+		return m.Owner
 	}
-	return len(dAtA) - i, nil
+	return nil
 }
 
-func (m *CacheOpt) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *FileActionMkFile) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *CacheOpt) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type FileActionMkDir struct {
+	// path for the new directory
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// permission bits
+	Mode int32 `protobuf:"varint,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	// makeParents creates parent directories as well if needed
+	MakeParents bool `protobuf:"varint,3,opt,name=makeParents,proto3" json:"makeParents,omitempty"`
+	// optional owner for the new directory
+	Owner *ChownOpt `protobuf:"bytes,4,opt,name=owner,proto3" json:"owner,omitempty"`
+	// optional created time override
+	Timestamp int64 `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 }
 
-func (m *CacheOpt) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Sharing != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Sharing))
-		i--
-		dAtA[i] = 0x10
-	}
-	if len(m.ID) > 0 {
-		i -= len(m.ID)
-		copy(dAtA[i:], m.ID)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.ID)))
-		i--
-		dAtA[i] = 0xa
-	}
-	return len(dAtA) - i, nil
+func (m *FileActionMkDir) Reset()         { *m = FileActionMkDir{} }
+func (m *FileActionMkDir) String() string { return proto.CompactTextString(m) }
+func (*FileActionMkDir) ProtoMessage()    {}
+func (*FileActionMkDir) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{39}
 }
-
-func (m *SecretOpt) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+func (m *FileActionMkDir) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FileActionMkDir) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return b[:n], nil
 }
-
-func (m *SecretOpt) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *FileActionMkDir) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FileActionMkDir.Merge(m, src)
+}
+func (m *FileActionMkDir) XXX_Size() int {
+	return m.Size()
+}
+func (m *FileActionMkDir) XXX_DiscardUnknown() {
+	xxx_messageInfo_FileActionMkDir.DiscardUnknown(m)
 }
 
-func (m *SecretOpt) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Optional {
-		i--
-		if m.Optional {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
-		i--
-		dAtA[i] = 0x28
-	}
-	if m.Mode != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Mode))
-		i--
-		dAtA[i] = 0x20
-	}
-	if m.Gid != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Gid))
-		i--
-		dAtA[i] = 0x18
-	}
-	if m.Uid != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Uid))
-		i--
-		dAtA[i] = 0x10
-	}
-	if len(m.ID) > 0 {
-		i -= len(m.ID)
-		copy(dAtA[i:], m.ID)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.ID)))
-		i--
-		dAtA[i] = 0xa
+var xxx_messageInfo_FileActionMkDir proto.InternalMessageInfo
+
+func (m *FileActionMkDir) GetPath() string {
+	if m != nil {
+		return m.Path
 	}
-	return len(dAtA) - i, nil
+	return ""
 }
 
-func (m *SSHOpt) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *FileActionMkDir) GetMode() int32 {
+	if m != nil {
+		return m.Mode
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *SSHOpt) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *FileActionMkDir) GetMakeParents() bool {
+	if m != nil {
+		return m.MakeParents
+	}
+	return false
 }
 
-func (m *SSHOpt) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Optional {
-		i--
-		if m.Optional {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
-		i--
-		dAtA[i] = 0x28
-	}
-	if m.Mode != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Mode))
-		i--
-		dAtA[i] = 0x20
-	}
-	if m.Gid != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Gid))
-		i--
-		dAtA[i] = 0x18
-	}
-	if m.Uid != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Uid))
-		i--
-		dAtA[i] = 0x10
+func (m *FileActionMkDir) GetOwner() *ChownOpt {
+	if m != nil {
+		return m.Owner
 	}
-	if len(m.ID) > 0 {
-		i -= len(m.ID)
-		copy(dAtA[i:], m.ID)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.ID)))
-		i--
-		dAtA[i] = 0xa
+	return nil
+}
+
+func (m *FileActionMkDir) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
 	}
-	return len(dAtA) - i, nil
+	return 0
 }
 
-func (m *SourceOp) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+type FileActionRm struct {
+	// path to remove
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// allowNotFound doesn't fail the rm if file is not found
+	AllowNotFound bool `protobuf:"varint,2,opt,name=allowNotFound,proto3" json:"allowNotFound,omitempty"`
+	// allowWildcard allows filepath.Match wildcards in path
+	AllowWildcard bool `protobuf:"varint,3,opt,name=allowWildcard,proto3" json:"allowWildcard,omitempty"`
+}
+
+func (m *FileActionRm) Reset()         { *m = FileActionRm{} }
+func (m *FileActionRm) String() string { return proto.CompactTextString(m) }
+func (*FileActionRm) ProtoMessage()    {}
+func (*FileActionRm) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{40}
+}
+func (m *FileActionRm) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FileActionRm) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return b[:n], nil
+}
+func (m *FileActionRm) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FileActionRm.Merge(m, src)
+}
+func (m *FileActionRm) XXX_Size() int {
+	return m.Size()
+}
+func (m *FileActionRm) XXX_DiscardUnknown() {
+	xxx_messageInfo_FileActionRm.DiscardUnknown(m)
 }
 
-func (m *SourceOp) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+var xxx_messageInfo_FileActionRm proto.InternalMessageInfo
+
+func (m *FileActionRm) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
 }
 
-func (m *SourceOp) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Attrs) > 0 {
-		keysForAttrs := make([]string, 0, len(m.Attrs))
-		for k := range m.Attrs {
-			keysForAttrs = append(keysForAttrs, string(k))
-		}
-		github_com_gogo_protobuf_sortkeys.Strings(keysForAttrs)
-		for iNdEx := len(keysForAttrs) - 1; iNdEx >= 0; iNdEx-- {
-			v := m.Attrs[string(keysForAttrs[iNdEx])]
-			baseI := i
-			i -= len(v)
-			copy(dAtA[i:], v)
-			i = encodeVarintOps(dAtA, i, uint64(len(v)))
-			i--
-			dAtA[i] = 0x12
-			i -= len(keysForAttrs[iNdEx])
-			copy(dAtA[i:], keysForAttrs[iNdEx])
-			i = encodeVarintOps(dAtA, i, uint64(len(keysForAttrs[iNdEx])))
-			i--
-			dAtA[i] = 0xa
-			i = encodeVarintOps(dAtA, i, uint64(baseI-i))
-			i--
-			dAtA[i] = 0x12
-		}
+func (m *FileActionRm) GetAllowNotFound() bool {
+	if m != nil {
+		return m.AllowNotFound
 	}
-	if len(m.Identifier) > 0 {
-		i -= len(m.Identifier)
-		copy(dAtA[i:], m.Identifier)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.Identifier)))
-		i--
-		dAtA[i] = 0xa
+	return false
+}
+
+func (m *FileActionRm) GetAllowWildcard() bool {
+	if m != nil {
+		return m.AllowWildcard
 	}
-	return len(dAtA) - i, nil
+	return false
 }
 
-func (m *BuildOp) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+type ChownOpt struct {
+	User  *UserOpt `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Group *UserOpt `protobuf:"bytes,2,opt,name=group,proto3" json:"group,omitempty"`
+}
+
+func (m *ChownOpt) Reset()         { *m = ChownOpt{} }
+func (m *ChownOpt) String() string { return proto.CompactTextString(m) }
+func (*ChownOpt) ProtoMessage()    {}
+func (*ChownOpt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{41}
+}
+func (m *ChownOpt) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ChownOpt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return b[:n], nil
+}
+func (m *ChownOpt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChownOpt.Merge(m, src)
+}
+func (m *ChownOpt) XXX_Size() int {
+	return m.Size()
+}
+func (m *ChownOpt) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChownOpt.DiscardUnknown(m)
 }
 
-func (m *BuildOp) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+var xxx_messageInfo_ChownOpt proto.InternalMessageInfo
+
+func (m *ChownOpt) GetUser() *UserOpt {
+	if m != nil {
+		return m.User
+	}
+	return nil
 }
 
-func (m *BuildOp) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Attrs) > 0 {
-		keysForAttrs := make([]string, 0, len(m.Attrs))
-		for k := range m.Attrs {
-			keysForAttrs = append(keysForAttrs, string(k))
-		}
-		github_com_gogo_protobuf_sortkeys.Strings(keysForAttrs)
-		for iNdEx := len(keysForAttrs) - 1; iNdEx >= 0; iNdEx-- {
-			v := m.Attrs[string(keysForAttrs[iNdEx])]
-			baseI := i
-			i -= len(v)
-			copy(dAtA[i:], v)
-			i = encodeVarintOps(dAtA, i, uint64(len(v)))
-			i--
-			dAtA[i] = 0x12
-			i -= len(keysForAttrs[iNdEx])
-			copy(dAtA[i:], keysForAttrs[iNdEx])
-			i = encodeVarintOps(dAtA, i, uint64(len(keysForAttrs[iNdEx])))
-			i--
-			dAtA[i] = 0xa
-			i = encodeVarintOps(dAtA, i, uint64(baseI-i))
-			i--
-			dAtA[i] = 0x22
-		}
+func (m *ChownOpt) GetGroup() *UserOpt {
+	if m != nil {
+		return m.Group
 	}
-	if m.Def != nil {
-		{
-			size, err := m.Def.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintOps(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x1a
+	return nil
+}
+
+type UserOpt struct {
+	// Types that are valid to be assigned to User:
+	//	*UserOpt_ByName
+	//	*UserOpt_ByID
+	User isUserOpt_User `protobuf_oneof:"user"`
+}
+
+func (m *UserOpt) Reset()         { *m = UserOpt{} }
+func (m *UserOpt) String() string { return proto.CompactTextString(m) }
+func (*UserOpt) ProtoMessage()    {}
+func (*UserOpt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{42}
+}
+func (m *UserOpt) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *UserOpt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
 	}
-	if len(m.Inputs) > 0 {
-		keysForInputs := make([]string, 0, len(m.Inputs))
-		for k := range m.Inputs {
-			keysForInputs = append(keysForInputs, string(k))
-		}
-		github_com_gogo_protobuf_sortkeys.Strings(keysForInputs)
-		for iNdEx := len(keysForInputs) - 1; iNdEx >= 0; iNdEx-- {
-			v := m.Inputs[string(keysForInputs[iNdEx])]
-			baseI := i
-			if v != nil {
-				{
-					size, err := v.MarshalToSizedBuffer(dAtA[:i])
-					if err != nil {
-						return 0, err
-					}
-					i -= size
-					i = encodeVarintOps(dAtA, i, uint64(size))
-				}
-				i--
-				dAtA[i] = 0x12
-			}
-			i -= len(keysForInputs[iNdEx])
-			copy(dAtA[i:], keysForInputs[iNdEx])
-			i = encodeVarintOps(dAtA, i, uint64(len(keysForInputs[iNdEx])))
-			i--
-			dAtA[i] = 0xa
-			i = encodeVarintOps(dAtA, i, uint64(baseI-i))
-			i--
-			dAtA[i] = 0x12
-		}
+	return b[:n], nil
+}
+func (m *UserOpt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UserOpt.Merge(m, src)
+}
+func (m *UserOpt) XXX_Size() int {
+	return m.Size()
+}
+func (m *UserOpt) XXX_DiscardUnknown() {
+	xxx_messageInfo_UserOpt.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UserOpt proto.InternalMessageInfo
+
+type isUserOpt_User interface {
+	isUserOpt_User()
+	MarshalTo([]byte) (int, error)
+	Size() int
+}
+
+type UserOpt_ByName struct {
+	ByName *NamedUserOpt `protobuf:"bytes,1,opt,name=byName,proto3,oneof" json:"byName,omitempty"`
+}
+type UserOpt_ByID struct {
+	ByID uint32 `protobuf:"varint,2,opt,name=byID,proto3,oneof" json:"byID,omitempty"`
+}
+
+func (*UserOpt_ByName) isUserOpt_User() {}
+func (*UserOpt_ByID) isUserOpt_User()   {}
+
+func (m *UserOpt) GetUser() isUserOpt_User {
+	if m != nil {
+		return m.User
 	}
-	if m.Builder != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Builder))
-		i--
-		dAtA[i] = 0x8
+	return nil
+}
+
+func (m *UserOpt) GetByName() *NamedUserOpt {
+	if x, ok := m.GetUser().(*UserOpt_ByName); ok {
+		return x.ByName
 	}
-	return len(dAtA) - i, nil
+	return nil
 }
 
-func (m *BuildInput) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+func (m *UserOpt) GetByID() uint32 {
+	if x, ok := m.GetUser().(*UserOpt_ByID); ok {
+		return x.ByID
+	}
+	return 0
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*UserOpt) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*UserOpt_ByName)(nil),
+		(*UserOpt_ByID)(nil),
+	}
+}
+
+type NamedUserOpt struct {
+	Name  string     `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Input InputIndex `protobuf:"varint,2,opt,name=input,proto3,customtype=InputIndex" json:"input"`
+}
+
+func (m *NamedUserOpt) Reset()         { *m = NamedUserOpt{} }
+func (m *NamedUserOpt) String() string { return proto.CompactTextString(m) }
+func (*NamedUserOpt) ProtoMessage()    {}
+func (*NamedUserOpt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8de16154b2733812, []int{43}
+}
+func (m *NamedUserOpt) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NamedUserOpt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return b[:n], nil
 }
-
-func (m *BuildInput) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *NamedUserOpt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NamedUserOpt.Merge(m, src)
+}
+func (m *NamedUserOpt) XXX_Size() int {
+	return m.Size()
+}
+func (m *NamedUserOpt) XXX_DiscardUnknown() {
+	xxx_messageInfo_NamedUserOpt.DiscardUnknown(m)
 }
 
-func (m *BuildInput) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Input != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Input))
-		i--
-		dAtA[i] = 0x8
+var xxx_messageInfo_NamedUserOpt proto.InternalMessageInfo
+
+func (m *NamedUserOpt) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	return len(dAtA) - i, nil
+	return ""
 }
 
-func (m *OpMetadata) Marshal() (dAtA []byte, err error) {
+func init() {
+	proto.RegisterEnum("pb.NetMode", NetMode_name, NetMode_value)
+	proto.RegisterEnum("pb.SecurityMode", SecurityMode_name, SecurityMode_value)
+	proto.RegisterEnum("pb.MountType", MountType_name, MountType_value)
+	proto.RegisterEnum("pb.CacheSharingOpt", CacheSharingOpt_name, CacheSharingOpt_value)
+	proto.RegisterType((*Op)(nil), "pb.Op")
+	proto.RegisterType((*Platform)(nil), "pb.Platform")
+	proto.RegisterType((*Input)(nil), "pb.Input")
+	proto.RegisterType((*ExecOp)(nil), "pb.ExecOp")
+	proto.RegisterMapType((map[string]string)(nil), "pb.ExecOp.SysctlsEntry")
+	proto.RegisterType((*UserNSMapping)(nil), "pb.UserNSMapping")
+	proto.RegisterType((*IDMap)(nil), "pb.IDMap")
+	proto.RegisterType((*ExecRetry)(nil), "pb.ExecRetry")
+	proto.RegisterType((*SharedPID)(nil), "pb.SharedPID")
+	proto.RegisterType((*Resources)(nil), "pb.Resources")
+	proto.RegisterType((*SecretEnv)(nil), "pb.SecretEnv")
+	proto.RegisterType((*Device)(nil), "pb.Device")
+	proto.RegisterType((*SeccompOpt)(nil), "pb.SeccompOpt")
+	proto.RegisterType((*Meta)(nil), "pb.Meta")
+	proto.RegisterType((*Timezone)(nil), "pb.Timezone")
+	proto.RegisterType((*Mount)(nil), "pb.Mount")
+	proto.RegisterType((*CacheOpt)(nil), "pb.CacheOpt")
+	proto.RegisterType((*SecretOpt)(nil), "pb.SecretOpt")
+	proto.RegisterType((*SSHOpt)(nil), "pb.SSHOpt")
+	proto.RegisterType((*HostPathOpt)(nil), "pb.HostPathOpt")
+	proto.RegisterType((*SourceOp)(nil), "pb.SourceOp")
+	proto.RegisterMapType((map[string]string)(nil), "pb.SourceOp.AttrsEntry")
+	proto.RegisterType((*BuildOp)(nil), "pb.BuildOp")
+	proto.RegisterMapType((map[string]string)(nil), "pb.BuildOp.AttrsEntry")
+	proto.RegisterMapType((map[string]*BuildInput)(nil), "pb.BuildOp.InputsEntry")
+	proto.RegisterType((*BuildInput)(nil), "pb.BuildInput")
+	proto.RegisterType((*OpMetadata)(nil), "pb.OpMetadata")
+	proto.RegisterMapType((map[github_com_moby_buildkit_util_apicaps.CapID]bool)(nil), "pb.OpMetadata.CapsEntry")
+	proto.RegisterMapType((map[string]string)(nil), "pb.OpMetadata.DescriptionEntry")
+	proto.RegisterType((*ProgressGroup)(nil), "pb.ProgressGroup")
+	proto.RegisterType((*Source)(nil), "pb.Source")
+	proto.RegisterMapType((map[string]*Locations)(nil), "pb.Source.LocationsEntry")
+	proto.RegisterType((*Locations)(nil), "pb.Locations")
+	proto.RegisterType((*SourceInfo)(nil), "pb.SourceInfo")
+	proto.RegisterType((*Location)(nil), "pb.Location")
+	proto.RegisterType((*Range)(nil), "pb.Range")
+	proto.RegisterType((*Position)(nil), "pb.Position")
+	proto.RegisterType((*ExportCache)(nil), "pb.ExportCache")
+	proto.RegisterType((*ProxyEnv)(nil), "pb.ProxyEnv")
+	proto.RegisterType((*WorkerConstraints)(nil), "pb.WorkerConstraints")
+	proto.RegisterType((*Definition)(nil), "pb.Definition")
+	proto.RegisterMapType((map[github_com_opencontainers_go_digest.Digest]OpMetadata)(nil), "pb.Definition.MetadataEntry")
+	proto.RegisterType((*HostIP)(nil), "pb.HostIP")
+	proto.RegisterType((*FileOp)(nil), "pb.FileOp")
+	proto.RegisterType((*FileAction)(nil), "pb.FileAction")
+	proto.RegisterType((*FileActionCopy)(nil), "pb.FileActionCopy")
+	proto.RegisterType((*FileActionMkFile)(nil), "pb.FileActionMkFile")
+	proto.RegisterType((*FileActionMkDir)(nil), "pb.FileActionMkDir")
+	proto.RegisterType((*FileActionRm)(nil), "pb.FileActionRm")
+	proto.RegisterType((*ChownOpt)(nil), "pb.ChownOpt")
+	proto.RegisterType((*UserOpt)(nil), "pb.UserOpt")
+	proto.RegisterType((*NamedUserOpt)(nil), "pb.NamedUserOpt")
+}
+
+func init() { proto.RegisterFile("ops.proto", fileDescriptor_8de16154b2733812) }
+
+var fileDescriptor_8de16154b2733812 = []byte{
+	// 2918 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x59, 0x4f, 0x6f, 0x1c, 0xc7,
+	0x95, 0xe7, 0xfc, 0x9f, 0x79, 0x43, 0x52, 0xe3, 0xb2, 0x6c, 0xb7, 0xb9, 0x5a, 0x8a, 0x6e, 0x6b,
+	0x0d, 0x8a, 0x92, 0x28, 0x98, 0x06, 0x2c, 0xc3, 0x58, 0x18, 0x20, 0x39, 0xa3, 0xe5, 0x58, 0x22,
+	0x87, 0x5b, 0x23, 0xc9, 0x8b, 0x05, 0x16, 0x42, 0xb3, 0xbb, 0x48, 0x36, 0x38, 0xd3, 0xd5, 0xa8,
+	0xae, 0x91, 0x38, 0x3e, 0xec, 0x21, 0x9f, 0xc0, 0x40, 0x80, 0xdc, 0x82, 0xc0, 0xdf, 0x21, 0xa7,
+	0x20, 0x39, 0x06, 0xf0, 0xd1, 0x87, 0x1c, 0x8c, 0x1c, 0x9c, 0x40, 0xbe, 0xe5, 0x3b, 0x04, 0x08,
+	0xde, 0xab, 0xea, 0x3f, 0x33, 0xa4, 0x2c, 0x0b, 0x0e, 0x72, 0x9a, 0xaa, 0xdf, 0xfb, 0xd5, 0xab,
+	0xaa, 0xd7, 0xef, 0xbd, 0x7a, 0x55, 0x03, 0x2d, 0x19, 0x27, 0x9b, 0xb1, 0x92, 0x5a, 0xb2, 0x72,
+	0x7c, 0xb4, 0x72, 0xe7, 0x24, 0xd4, 0xa7, 0x93, 0xa3, 0x4d, 0x5f, 0x8e, 0xef, 0x9e, 0xc8, 0x13,
+	0x79, 0x97, 0x44, 0x47, 0x93, 0x63, 0xea, 0x51, 0x87, 0x5a, 0x66, 0x88, 0xfb, 0x75, 0x19, 0xca,
+	0x83, 0x98, 0xbd, 0x07, 0xf5, 0x30, 0x8a, 0x27, 0x3a, 0x71, 0x4a, 0x6b, 0x95, 0xf5, 0xf6, 0x56,
+	0x6b, 0x33, 0x3e, 0xda, 0xec, 0x23, 0xc2, 0xad, 0x80, 0xad, 0x41, 0x55, 0x9c, 0x0b, 0xdf, 0x29,
+	0xaf, 0x95, 0xd6, 0xdb, 0x5b, 0x80, 0x84, 0xde, 0xb9, 0xf0, 0x07, 0xf1, 0xde, 0x02, 0x27, 0x09,
+	0xfb, 0x00, 0xea, 0x89, 0x9c, 0x28, 0x5f, 0x38, 0x15, 0xe2, 0x2c, 0x22, 0x67, 0x48, 0x08, 0xb1,
+	0xac, 0x14, 0x35, 0x1d, 0x87, 0x23, 0xe1, 0x54, 0x73, 0x4d, 0xf7, 0xc3, 0x91, 0xe1, 0x90, 0x84,
+	0xbd, 0x0f, 0xb5, 0xa3, 0x49, 0x38, 0x0a, 0x9c, 0x1a, 0x51, 0xda, 0x48, 0xd9, 0x41, 0x80, 0x38,
+	0x46, 0xc6, 0xd6, 0xa1, 0x19, 0x8f, 0x3c, 0x7d, 0x2c, 0xd5, 0xd8, 0x81, 0x7c, 0xc2, 0x43, 0x8b,
+	0xf1, 0x4c, 0xca, 0xee, 0x41, 0xdb, 0x97, 0x51, 0xa2, 0x95, 0x17, 0x46, 0x3a, 0x71, 0xda, 0x44,
+	0x7e, 0x0b, 0xc9, 0x5f, 0x48, 0x75, 0x26, 0xd4, 0x6e, 0x2e, 0xe4, 0x45, 0xe6, 0x4e, 0x15, 0xca,
+	0x32, 0x76, 0x7f, 0x55, 0x82, 0x66, 0xaa, 0x95, 0xb9, 0xb0, 0xb8, 0xad, 0xfc, 0xd3, 0x50, 0x0b,
+	0x5f, 0x4f, 0x94, 0x70, 0x4a, 0x6b, 0xa5, 0xf5, 0x16, 0x9f, 0xc1, 0xd8, 0x32, 0x94, 0x07, 0x43,
+	0x32, 0x54, 0x8b, 0x97, 0x07, 0x43, 0xe6, 0x40, 0xe3, 0x89, 0xa7, 0x42, 0x2f, 0xd2, 0x64, 0x99,
+	0x16, 0x4f, 0xbb, 0xec, 0x1a, 0xb4, 0x06, 0xc3, 0x27, 0x42, 0x25, 0xa1, 0x8c, 0xc8, 0x1e, 0x2d,
+	0x9e, 0x03, 0x6c, 0x15, 0x60, 0x30, 0xbc, 0x2f, 0x3c, 0x54, 0x9a, 0x38, 0xb5, 0xb5, 0xca, 0x7a,
+	0x8b, 0x17, 0x10, 0xf7, 0xff, 0xa1, 0x46, 0xdf, 0x88, 0x7d, 0x0e, 0xf5, 0x20, 0x3c, 0x11, 0x89,
+	0x36, 0xcb, 0xd9, 0xd9, 0xfa, 0xe6, 0xfb, 0xeb, 0x0b, 0x7f, 0xfe, 0xfe, 0xfa, 0x46, 0xc1, 0x19,
+	0x64, 0x2c, 0x22, 0x5f, 0x46, 0xda, 0x0b, 0x23, 0xa1, 0x92, 0xbb, 0x27, 0xf2, 0x8e, 0x19, 0xb2,
+	0xd9, 0xa5, 0x1f, 0x6e, 0x35, 0xb0, 0x9b, 0x50, 0x0b, 0xa3, 0x40, 0x9c, 0xd3, 0xfa, 0x2b, 0x3b,
+	0x6f, 0x5a, 0x55, 0xed, 0xc1, 0x44, 0xc7, 0x13, 0xdd, 0x47, 0x11, 0x37, 0x0c, 0xf7, 0x6f, 0x35,
+	0xa8, 0x1b, 0x1f, 0x60, 0xd7, 0xa0, 0x3a, 0x16, 0xda, 0xa3, 0xf9, 0xdb, 0x5b, 0x4d, 0xb4, 0xed,
+	0xbe, 0xd0, 0x1e, 0x27, 0x14, 0xdd, 0x6b, 0x2c, 0x27, 0x68, 0xfb, 0x72, 0xee, 0x5e, 0xfb, 0x88,
+	0x70, 0x2b, 0x60, 0xff, 0x01, 0x8d, 0x48, 0xe8, 0xe7, 0x52, 0x9d, 0x91, 0x8d, 0x96, 0xcd, 0x47,
+	0x3f, 0x10, 0x7a, 0x5f, 0x06, 0x82, 0xa7, 0x32, 0x76, 0x1b, 0x9a, 0x89, 0xf0, 0x27, 0x2a, 0xd4,
+	0x53, 0xb2, 0xd7, 0xf2, 0x56, 0x87, 0xbc, 0xcc, 0x62, 0x44, 0xce, 0x18, 0x6c, 0x03, 0x3a, 0xde,
+	0x68, 0x24, 0x9f, 0x8b, 0xa0, 0x77, 0x1e, 0xea, 0x5d, 0x19, 0x58, 0x33, 0xd6, 0xf8, 0x05, 0x9c,
+	0xad, 0x43, 0x23, 0x11, 0xbe, 0x2f, 0xc7, 0xb1, 0x53, 0xa7, 0x4d, 0x2c, 0x5b, 0xc5, 0x08, 0x0d,
+	0x62, 0xcd, 0x53, 0x31, 0xbb, 0x01, 0x8d, 0x40, 0x3c, 0x0b, 0x7d, 0x91, 0x38, 0x8d, 0xb5, 0x4a,
+	0xea, 0xc2, 0x5d, 0x82, 0x78, 0x2a, 0x62, 0xb7, 0xa0, 0x95, 0x08, 0x5f, 0x09, 0x2d, 0xa2, 0x67,
+	0x4e, 0x93, 0x78, 0x4b, 0x56, 0xa3, 0x12, 0xba, 0x17, 0x3d, 0xe3, 0xb9, 0x9c, 0xad, 0x43, 0xcd,
+	0x3b, 0xd6, 0x42, 0x39, 0xad, 0xb5, 0xca, 0x7a, 0x65, 0x87, 0x59, 0xa3, 0x43, 0x3f, 0xca, 0x6d,
+	0x4e, 0x04, 0x54, 0xab, 0x84, 0x09, 0xa4, 0xc4, 0xba, 0x3d, 0xa9, 0xe5, 0x29, 0xc8, 0x73, 0x39,
+	0xad, 0xe1, 0xd4, 0x53, 0x22, 0x38, 0xec, 0x77, 0x9d, 0x76, 0x4e, 0x1e, 0xa6, 0x20, 0xcf, 0xe5,
+	0x6c, 0x1d, 0xae, 0x78, 0x71, 0xec, 0xa9, 0xb1, 0x54, 0x87, 0x4a, 0x52, 0x84, 0x2e, 0x92, 0x47,
+	0xce, 0xc3, 0xec, 0x03, 0x58, 0x0e, 0x4f, 0x22, 0xa9, 0xc4, 0x7d, 0xa9, 0x76, 0x3d, 0xff, 0x54,
+	0x38, 0x4b, 0xe4, 0x9b, 0x73, 0x28, 0x86, 0xb1, 0x12, 0x5a, 0x4d, 0x9d, 0xe5, 0x7c, 0x6a, 0xf4,
+	0x17, 0x8e, 0x20, 0x37, 0x32, 0x76, 0x0f, 0x96, 0x26, 0x89, 0x50, 0x07, 0xc3, 0x7d, 0x2f, 0x8e,
+	0xc3, 0xe8, 0xc4, 0xb9, 0x42, 0xe4, 0x37, 0x90, 0xfc, 0xb8, 0x28, 0xe0, 0xb3, 0x3c, 0xf6, 0x21,
+	0x34, 0x92, 0x69, 0xe2, 0xeb, 0x51, 0xe2, 0x74, 0xc8, 0xbc, 0xef, 0xe4, 0x39, 0x69, 0x73, 0x68,
+	0x24, 0xbd, 0x08, 0x67, 0x4a, 0x79, 0x2b, 0x9f, 0xc2, 0x62, 0x51, 0xc0, 0x3a, 0x50, 0x39, 0x13,
+	0x53, 0x1b, 0xc3, 0xd8, 0x64, 0x57, 0xa1, 0xf6, 0xcc, 0x1b, 0x4d, 0x84, 0x8d, 0x5e, 0xd3, 0xf9,
+	0xb4, 0xfc, 0x49, 0xc9, 0x0d, 0x61, 0x69, 0x66, 0x39, 0xec, 0x16, 0xb4, 0x27, 0x61, 0x60, 0x7b,
+	0xb3, 0x89, 0xb3, 0xbb, 0xef, 0xc5, 0xbc, 0x28, 0x45, 0xf2, 0x49, 0x81, 0x5c, 0xbe, 0x40, 0x2e,
+	0x48, 0xdd, 0xc7, 0x50, 0x23, 0x94, 0xad, 0x41, 0x3b, 0x0b, 0xd9, 0x7e, 0x97, 0xd6, 0xb9, 0xc4,
+	0x8b, 0x10, 0x7b, 0x1b, 0xea, 0xa7, 0x32, 0xd1, 0xfd, 0x2e, 0x2d, 0x78, 0x89, 0xdb, 0x1e, 0x63,
+	0x50, 0x4d, 0xc2, 0x2f, 0x4d, 0x26, 0x5e, 0xe2, 0xd4, 0x76, 0x7b, 0xd0, 0xca, 0xac, 0xcf, 0x56,
+	0xa0, 0xe9, 0x69, 0x2d, 0xc6, 0x31, 0xe5, 0xfc, 0xd2, 0x7a, 0x85, 0x67, 0x7d, 0xcc, 0x4a, 0x22,
+	0x8b, 0x97, 0x32, 0xc5, 0x4b, 0x0e, 0xb8, 0x53, 0x68, 0x65, 0xfe, 0xf3, 0xf3, 0xe3, 0x7e, 0x26,
+	0x4c, 0x2a, 0x3f, 0x1e, 0x26, 0xee, 0xff, 0x41, 0x2b, 0xf3, 0x73, 0xdc, 0xfa, 0x58, 0x8c, 0xa5,
+	0x9a, 0xda, 0xf5, 0xdb, 0x1e, 0xee, 0xcc, 0x8f, 0x27, 0xff, 0x3d, 0x91, 0xda, 0x33, 0x39, 0x8c,
+	0x67, 0x7d, 0xdc, 0x99, 0x1f, 0x4f, 0x0e, 0x85, 0x0a, 0x65, 0x40, 0xb6, 0xa9, 0xf2, 0x1c, 0x70,
+	0x1f, 0x40, 0x2b, 0x9b, 0x16, 0x93, 0xb8, 0x35, 0x79, 0x8b, 0x97, 0x8d, 0x45, 0x23, 0x6f, 0x9c,
+	0x3a, 0x06, 0xb5, 0x71, 0x2a, 0x19, 0xeb, 0x50, 0x46, 0xde, 0x88, 0xb4, 0x35, 0x79, 0xd6, 0x77,
+	0x3f, 0x83, 0xba, 0x49, 0x09, 0x38, 0x32, 0xf6, 0xf4, 0xa9, 0xd5, 0x45, 0x6d, 0xfc, 0xb2, 0xb1,
+	0x50, 0xe3, 0x30, 0xc1, 0x44, 0x9f, 0x58, 0xa5, 0x45, 0xc8, 0xbd, 0x0f, 0x90, 0x27, 0x1f, 0x3c,
+	0x42, 0x62, 0x1b, 0x94, 0x46, 0x4d, 0xda, 0xc5, 0x43, 0x62, 0x82, 0x89, 0xfd, 0x38, 0x8c, 0x44,
+	0x40, 0x8a, 0x9a, 0xbc, 0x80, 0xb8, 0x7f, 0xac, 0x40, 0x15, 0x3f, 0x09, 0x2e, 0xc3, 0x53, 0xd6,
+	0x51, 0x5b, 0x9c, 0xda, 0x18, 0x00, 0x68, 0xf7, 0x32, 0x41, 0xd8, 0x44, 0xc4, 0x7f, 0x1e, 0xd8,
+	0x73, 0x0a, 0x9b, 0x38, 0x0e, 0x03, 0xcf, 0x1e, 0x4f, 0xd4, 0x66, 0x37, 0xa1, 0x15, 0x2b, 0x79,
+	0x3e, 0x7d, 0x8a, 0xa3, 0x6b, 0x85, 0xc3, 0x17, 0x41, 0xfc, 0x68, 0xcd, 0xd8, 0xb6, 0xd8, 0x06,
+	0x80, 0x38, 0xd7, 0xca, 0xdb, 0x93, 0x89, 0x4e, 0x9c, 0x7a, 0x9e, 0x30, 0x11, 0xe8, 0x1f, 0xf2,
+	0x82, 0x14, 0xed, 0x89, 0xfe, 0x4b, 0x76, 0x6e, 0xd0, 0x74, 0x59, 0x1f, 0xf7, 0x29, 0x30, 0x68,
+	0x63, 0x19, 0x46, 0xda, 0x69, 0x92, 0xb4, 0x80, 0x60, 0x52, 0xf2, 0x31, 0xeb, 0xf4, 0x29, 0x07,
+	0xf5, 0xa2, 0x67, 0x94, 0x4b, 0x5b, 0x7c, 0x0e, 0xc5, 0x08, 0x9f, 0x8c, 0xbd, 0xe4, 0x8c, 0x92,
+	0x67, 0x8b, 0x9b, 0x0e, 0x8e, 0x8e, 0xbd, 0x24, 0xd1, 0xa7, 0x4a, 0x4e, 0x4e, 0x4e, 0x71, 0x74,
+	0xdb, 0x8c, 0x9e, 0x45, 0x91, 0xa7, 0x44, 0x10, 0x2a, 0xe1, 0xeb, 0xa1, 0x0e, 0xe4, 0x44, 0xdb,
+	0x1c, 0x39, 0x87, 0xce, 0xf1, 0x84, 0x52, 0xce, 0xd2, 0x05, 0x9e, 0x50, 0x0a, 0x8b, 0x18, 0x1d,
+	0x8e, 0xc5, 0x97, 0x32, 0x12, 0xce, 0x72, 0x6e, 0xc7, 0x47, 0x16, 0xe3, 0x99, 0xd4, 0xed, 0x42,
+	0x33, 0x45, 0x33, 0x5f, 0x2c, 0x15, 0x7c, 0xf1, 0x06, 0x2c, 0x51, 0x48, 0xfd, 0xaf, 0x8c, 0x44,
+	0x18, 0x1d, 0x4b, 0xeb, 0x0a, 0xb3, 0xa0, 0xfb, 0x75, 0x05, 0x6a, 0x14, 0x80, 0x78, 0xe4, 0x50,
+	0x65, 0x67, 0xa2, 0xe7, 0xf2, 0x23, 0x87, 0x08, 0xf8, 0x55, 0x12, 0x31, 0x12, 0xbe, 0x96, 0xca,
+	0x3a, 0x6a, 0xd6, 0xc7, 0x95, 0x04, 0x58, 0x77, 0x18, 0x7f, 0xa1, 0x36, 0xbb, 0x05, 0x75, 0x49,
+	0xc5, 0x82, 0x53, 0x7d, 0x79, 0x09, 0x61, 0x29, 0xa8, 0x5c, 0x09, 0x2f, 0x90, 0xd1, 0x68, 0x4a,
+	0x8e, 0xd4, 0xe4, 0x59, 0x1f, 0x73, 0x03, 0xad, 0xfe, 0xd1, 0x34, 0x16, 0x74, 0x28, 0x2f, 0x9b,
+	0xdc, 0xb0, 0x9f, 0x82, 0x3c, 0x97, 0xa3, 0x25, 0xe9, 0x4b, 0x0f, 0x62, 0xed, 0x5c, 0xcd, 0x2d,
+	0xb9, 0x6b, 0x31, 0x9e, 0x49, 0xf3, 0x94, 0x83, 0xd4, 0xb7, 0x0a, 0xa7, 0x62, 0x0a, 0xf2, 0x5c,
+	0xce, 0x5c, 0xa8, 0x0f, 0x87, 0x7b, 0xc8, 0x7c, 0x3b, 0x2f, 0x57, 0x0d, 0xc2, 0xad, 0xc4, 0xec,
+	0x21, 0x99, 0x8c, 0x30, 0x0d, 0xbf, 0x63, 0x0c, 0x94, 0xf6, 0xd9, 0x87, 0xd0, 0x46, 0x17, 0x3e,
+	0xf4, 0xf4, 0x29, 0x2a, 0x71, 0x48, 0xc9, 0x95, 0xd4, 0xff, 0x2d, 0xcc, 0x8b, 0x1c, 0xb7, 0x0f,
+	0xcd, 0x74, 0xd5, 0x17, 0xb2, 0xd0, 0x1d, 0x68, 0xe0, 0x89, 0x8d, 0xe7, 0x64, 0x99, 0x0c, 0xf2,
+	0x66, 0xb6, 0xc9, 0xa1, 0xc1, 0x4d, 0xa9, 0x62, 0xda, 0xae, 0x4c, 0x33, 0xda, 0x65, 0xba, 0x3a,
+	0x50, 0x99, 0x84, 0x81, 0x3d, 0x38, 0xb0, 0x89, 0xc8, 0x49, 0x18, 0xd8, 0x43, 0x03, 0x9b, 0xf8,
+	0x7d, 0xc7, 0x32, 0x30, 0xb5, 0xfa, 0x12, 0xa7, 0xf6, 0x4c, 0xd6, 0xab, 0xcd, 0x65, 0xbd, 0x51,
+	0x6a, 0xae, 0x7f, 0xc9, 0x6c, 0xef, 0x41, 0xbb, 0x60, 0xc5, 0xcb, 0xc2, 0xc2, 0xfd, 0x65, 0x09,
+	0x9a, 0xe9, 0x1d, 0x04, 0x73, 0x48, 0x18, 0x88, 0x48, 0x87, 0xc7, 0xa1, 0x50, 0x96, 0x56, 0x40,
+	0xd8, 0x1d, 0xa8, 0x79, 0x5a, 0xab, 0xf4, 0xb8, 0x7a, 0xa7, 0x78, 0x81, 0xd9, 0xdc, 0x46, 0x89,
+	0x29, 0x28, 0x0c, 0x6b, 0xe5, 0x13, 0x80, 0x1c, 0x7c, 0xad, 0x62, 0xe2, 0x0f, 0x65, 0x68, 0xd8,
+	0x0b, 0x0d, 0xbb, 0x0d, 0x0d, 0xba, 0xd0, 0x08, 0xf5, 0x23, 0xa1, 0x98, 0x52, 0xd8, 0xdd, 0xec,
+	0xa6, 0x56, 0x58, 0xa3, 0x55, 0x65, 0x6e, 0x6c, 0x76, 0x8d, 0xf9, 0xbd, 0xad, 0x12, 0x88, 0x63,
+	0xa7, 0x92, 0xd7, 0xb4, 0x5d, 0x71, 0x1c, 0x46, 0x21, 0x9a, 0x90, 0xa3, 0x88, 0xdd, 0x4e, 0x77,
+	0x5d, 0x25, 0x8d, 0x6f, 0x17, 0x35, 0x5e, 0xdc, 0x74, 0x1f, 0xda, 0x85, 0x69, 0x2e, 0xd9, 0xf5,
+	0x8d, 0xe2, 0xae, 0xed, 0x94, 0xa4, 0x8e, 0x86, 0x15, 0xac, 0xf0, 0x33, 0xec, 0xf7, 0x31, 0x40,
+	0xae, 0xf2, 0xa7, 0xa7, 0x32, 0xf7, 0xf7, 0x15, 0x80, 0x41, 0x8c, 0xc7, 0x61, 0xe0, 0x51, 0x7d,
+	0xb2, 0x68, 0x4a, 0xd6, 0xa7, 0x94, 0x1c, 0x68, 0x7c, 0x93, 0xb7, 0x0d, 0x66, 0x6a, 0xd8, 0x6d,
+	0x68, 0x07, 0x22, 0xf1, 0x55, 0x48, 0x3e, 0x67, 0x8d, 0x7e, 0x1d, 0xf7, 0x94, 0xeb, 0xd9, 0xec,
+	0xe6, 0x0c, 0x63, 0xab, 0xe2, 0x18, 0xb6, 0x05, 0x8b, 0xe2, 0x3c, 0x96, 0x4a, 0xdb, 0x59, 0xaa,
+	0x79, 0x0e, 0xe8, 0x11, 0x4e, 0x33, 0xf1, 0xb6, 0xc8, 0x3b, 0xcc, 0x83, 0xaa, 0xef, 0xc5, 0xe6,
+	0xb6, 0xd2, 0xde, 0x72, 0xe6, 0xe6, 0xdb, 0xf5, 0x62, 0x63, 0xb4, 0x9d, 0x8f, 0x70, 0xaf, 0xbf,
+	0xf8, 0xcb, 0xf5, 0x5b, 0x85, 0x9b, 0xde, 0x58, 0x1e, 0x4d, 0xef, 0x92, 0xbf, 0x9c, 0x85, 0xfa,
+	0xee, 0x44, 0x87, 0xa3, 0xbb, 0x5e, 0x1c, 0xa2, 0x3a, 0x1c, 0xd8, 0xef, 0x72, 0x52, 0xcd, 0x3e,
+	0x81, 0xe5, 0x58, 0xc9, 0x13, 0x25, 0x92, 0xe4, 0xe9, 0x89, 0x92, 0x93, 0xf4, 0xde, 0xf3, 0x86,
+	0x3d, 0xc8, 0x49, 0xf2, 0x5f, 0x28, 0xe0, 0x4b, 0x71, 0xb1, 0xbb, 0xf2, 0x19, 0x74, 0xe6, 0x77,
+	0xfc, 0x3a, 0x5f, 0x6f, 0xe5, 0x1e, 0xb4, 0xb2, 0x1d, 0xbc, 0x6a, 0x60, 0xb3, 0xf8, 0xd9, 0x3f,
+	0x82, 0xa5, 0x99, 0x85, 0x61, 0x92, 0x09, 0x83, 0x34, 0xc9, 0x98, 0x04, 0x32, 0x5f, 0xa4, 0xb9,
+	0xbf, 0x2d, 0x41, 0xdd, 0x04, 0x31, 0xbb, 0x07, 0xad, 0x91, 0xf4, 0x3d, 0x4d, 0x35, 0x97, 0x29,
+	0xd8, 0xdf, 0xcd, 0x63, 0x7c, 0xf3, 0x61, 0x2a, 0x33, 0x1f, 0x31, 0xe7, 0xa2, 0x4f, 0xe3, 0xf1,
+	0x99, 0x06, 0xdd, 0x72, 0x3e, 0xa8, 0x1f, 0x1d, 0x4b, 0x6e, 0x84, 0x2b, 0x0f, 0x60, 0x79, 0x56,
+	0xc5, 0x25, 0x9b, 0x7b, 0x7f, 0x36, 0x3a, 0xe8, 0xe0, 0xc9, 0x06, 0x15, 0xf7, 0x7a, 0x0f, 0x5a,
+	0x19, 0xce, 0x36, 0x2e, 0x2e, 0x7c, 0xb1, 0x38, 0xb2, 0xb0, 0x56, 0x77, 0x04, 0x90, 0x2f, 0x0d,
+	0xd3, 0x27, 0x96, 0x89, 0x85, 0xbc, 0x98, 0xf5, 0xe9, 0xf0, 0xf6, 0x6c, 0x95, 0xbc, 0xc8, 0xa9,
+	0xcd, 0x36, 0x01, 0x82, 0x2c, 0x3f, 0xbc, 0x24, 0x6b, 0x14, 0x18, 0xee, 0x00, 0x9a, 0xe9, 0x22,
+	0xb0, 0xa8, 0x4d, 0xec, 0xcc, 0xf8, 0x80, 0x80, 0xd3, 0xd5, 0x78, 0x11, 0xc2, 0x0b, 0x81, 0xf2,
+	0xa2, 0x13, 0x31, 0x73, 0x21, 0xe0, 0x88, 0x70, 0x2b, 0x70, 0xbf, 0x80, 0x1a, 0x01, 0x18, 0xd5,
+	0x89, 0xf6, 0x94, 0xb6, 0x77, 0x0b, 0x53, 0x5f, 0xca, 0x84, 0xa6, 0xdd, 0xa9, 0xa2, 0xdf, 0x73,
+	0x43, 0x60, 0x37, 0xb0, 0x8a, 0x0d, 0x9c, 0xf2, 0x4b, 0x79, 0x28, 0x76, 0xff, 0x13, 0x9a, 0x29,
+	0x8c, 0x3b, 0x7f, 0x18, 0x46, 0xc2, 0x2e, 0x91, 0xda, 0x78, 0x37, 0xd8, 0x3d, 0xf5, 0x94, 0xe7,
+	0xe3, 0x3d, 0xbc, 0x4c, 0x82, 0x1c, 0x70, 0xdf, 0x87, 0x76, 0x21, 0x58, 0xd1, 0x47, 0x9f, 0xd0,
+	0x67, 0x34, 0x29, 0xc3, 0x74, 0xdc, 0xdf, 0xe0, 0x4b, 0x51, 0x5a, 0xf8, 0xfe, 0x3b, 0xc0, 0xa9,
+	0xd6, 0xf1, 0x53, 0xaa, 0x84, 0xad, 0xed, 0x5b, 0x88, 0x10, 0x83, 0x5d, 0x87, 0x36, 0x76, 0x12,
+	0x2b, 0x37, 0x1e, 0x4b, 0x23, 0x12, 0x43, 0xf8, 0x37, 0x68, 0x1d, 0x67, 0xc3, 0x2b, 0xf6, 0xd3,
+	0xa5, 0xa3, 0xdf, 0x85, 0x66, 0x24, 0xad, 0xcc, 0x14, 0xe6, 0x8d, 0x48, 0x66, 0xe3, 0xbc, 0xd1,
+	0xc8, 0xca, 0x6a, 0x66, 0x9c, 0x37, 0x1a, 0x91, 0xd0, 0xbd, 0x05, 0x6f, 0x5c, 0x78, 0xf3, 0xc2,
+	0x9b, 0xd4, 0x71, 0x38, 0xd2, 0x74, 0x00, 0x61, 0xd1, 0x6b, 0x7b, 0xee, 0xdf, 0x4b, 0x00, 0xf9,
+	0x67, 0x67, 0x1d, 0x73, 0x92, 0x20, 0x67, 0xd1, 0x9c, 0x1c, 0x23, 0x68, 0x8e, 0x6d, 0x4e, 0xb2,
+	0x1f, 0xf4, 0xda, 0xac, 0xab, 0x6c, 0xa6, 0x29, 0xcb, 0x64, 0xab, 0x2d, 0x9b, 0xad, 0x5e, 0xe7,
+	0x5d, 0x2a, 0x9b, 0x81, 0x4a, 0xb1, 0xe2, 0xfb, 0x22, 0xe4, 0x51, 0xc8, 0xad, 0x64, 0xe5, 0x01,
+	0x2c, 0xcd, 0x4c, 0xf9, 0x13, 0xcf, 0xa7, 0x3c, 0xb7, 0x16, 0x43, 0xf0, 0x36, 0xd4, 0xcd, 0x25,
+	0x05, 0xfd, 0x05, 0x5b, 0x69, 0x65, 0x81, 0x6d, 0x2a, 0x70, 0x0e, 0xd3, 0x57, 0xbe, 0xfe, 0xa1,
+	0xbb, 0x05, 0x75, 0xf3, 0x8c, 0x89, 0x4f, 0x49, 0x9e, 0xaf, 0xed, 0xc5, 0x2e, 0xcb, 0x17, 0x28,
+	0xdc, 0x26, 0x98, 0xa7, 0x62, 0xf7, 0x4f, 0x65, 0x80, 0x1c, 0x7f, 0x8d, 0x9a, 0xfc, 0x53, 0x58,
+	0x4e, 0x84, 0x2f, 0xa3, 0xc0, 0x53, 0x53, 0x92, 0x3a, 0xe5, 0x97, 0x0e, 0x99, 0x63, 0x16, 0xea,
+	0xf3, 0xca, 0xab, 0xeb, 0xf3, 0x75, 0xa8, 0xfa, 0x32, 0x9e, 0xda, 0x43, 0x8b, 0xcd, 0x6e, 0x64,
+	0x57, 0xc6, 0x53, 0x7c, 0xb4, 0x45, 0x06, 0xdb, 0x84, 0xfa, 0xf8, 0x8c, 0x6e, 0xa8, 0xe6, 0x42,
+	0x78, 0x75, 0x96, 0xbb, 0x7f, 0x86, 0x6d, 0x7c, 0x06, 0x36, 0x2c, 0x76, 0x0b, 0x6a, 0xe3, 0xb3,
+	0x20, 0x54, 0xf6, 0xd8, 0x79, 0x73, 0x9e, 0xde, 0x0d, 0x15, 0x3e, 0xf6, 0x12, 0x87, 0xb9, 0x50,
+	0x56, 0x63, 0xba, 0x13, 0xb6, 0xb7, 0x3a, 0xb3, 0x4c, 0x3e, 0xde, 0x5b, 0xe0, 0x65, 0x35, 0xde,
+	0x69, 0x42, 0xdd, 0xd8, 0xd5, 0xfd, 0x5d, 0x15, 0x96, 0x67, 0x57, 0x89, 0x7e, 0x90, 0x28, 0x3f,
+	0xf5, 0x83, 0x44, 0xf9, 0xd9, 0xd5, 0xa5, 0x5c, 0xb8, 0xba, 0xb8, 0x50, 0x93, 0xcf, 0x23, 0xa1,
+	0x8a, 0x2f, 0xd8, 0xbb, 0xa7, 0xf2, 0x79, 0x84, 0x55, 0xb5, 0x11, 0xcd, 0x14, 0xa9, 0x35, 0x5b,
+	0xa4, 0xde, 0x80, 0xa5, 0x63, 0x89, 0x2f, 0x8a, 0xc3, 0xe9, 0x78, 0x14, 0x46, 0x67, 0xb6, 0x52,
+	0x9d, 0x05, 0xf1, 0x85, 0x2d, 0x08, 0x15, 0x2e, 0x67, 0x57, 0x46, 0x5a, 0x44, 0x74, 0x1f, 0x46,
+	0xde, 0x3c, 0xcc, 0x3e, 0x87, 0x35, 0xfb, 0x1a, 0xf3, 0x38, 0x8a, 0x3d, 0xff, 0xac, 0x2b, 0x7d,
+	0x8a, 0xd9, 0x71, 0xec, 0xe9, 0xf0, 0x28, 0x1c, 0xe1, 0xf3, 0x67, 0x83, 0x86, 0xbe, 0x92, 0x47,
+	0x17, 0x63, 0x25, 0x3c, 0x2d, 0xba, 0xc2, 0x94, 0xca, 0x74, 0x79, 0x6e, 0xf2, 0x39, 0x14, 0xf7,
+	0x40, 0x8f, 0xa2, 0x5f, 0x84, 0xa3, 0xc0, 0xf7, 0x54, 0xe0, 0xb4, 0xcc, 0x1e, 0x66, 0x40, 0xb6,
+	0x09, 0x8c, 0x80, 0xde, 0x38, 0xd6, 0xd3, 0x8c, 0x0a, 0x44, 0xbd, 0x44, 0x82, 0x59, 0x15, 0xaf,
+	0xb0, 0x89, 0xf6, 0xc6, 0x31, 0x3d, 0x41, 0x56, 0x78, 0x0e, 0xb0, 0x9b, 0xd0, 0x09, 0x23, 0x7f,
+	0x34, 0x09, 0xc4, 0xd3, 0x18, 0x37, 0xa2, 0xa2, 0xc4, 0x59, 0xa4, 0x1c, 0x74, 0xc5, 0xe2, 0x87,
+	0x16, 0x46, 0xaa, 0x38, 0x9f, 0xa3, 0x9a, 0x67, 0xc7, 0x2b, 0xe2, 0x7c, 0x96, 0xea, 0xc2, 0x62,
+	0x36, 0xc5, 0x81, 0x7c, 0x4e, 0x17, 0xeb, 0x26, 0x9f, 0xc1, 0xf0, 0x41, 0x25, 0x08, 0x15, 0xbe,
+	0x17, 0xd3, 0x83, 0x63, 0x8d, 0xa7, 0x5d, 0xf7, 0xab, 0x12, 0x74, 0xe6, 0xdd, 0xf6, 0xd2, 0x37,
+	0x9c, 0xd4, 0x11, 0xca, 0x05, 0x47, 0x48, 0x8f, 0xd4, 0x4a, 0xe1, 0x48, 0xcd, 0x9c, 0xaa, 0xfa,
+	0x72, 0xa7, 0x9a, 0x31, 0x53, 0x6d, 0xce, 0x4c, 0xee, 0xaf, 0x4b, 0x70, 0x65, 0x2e, 0x34, 0x7e,
+	0xf2, 0x8a, 0xd6, 0xa0, 0x3d, 0xf6, 0xce, 0xc4, 0xa1, 0xa7, 0xc8, 0xe1, 0xcc, 0x33, 0x55, 0x11,
+	0xfa, 0x27, 0xac, 0x2f, 0x82, 0xc5, 0x62, 0x3c, 0x5e, 0xba, 0xb6, 0xd4, 0xbd, 0x0e, 0xa4, 0xbe,
+	0x2f, 0x27, 0x51, 0xfa, 0x54, 0x35, 0x0b, 0x5e, 0x74, 0xc2, 0xca, 0x25, 0x4e, 0xe8, 0x1e, 0x40,
+	0x33, 0x5d, 0x20, 0xbb, 0x6e, 0x9f, 0xa7, 0x4a, 0xf9, 0x5f, 0x45, 0xf8, 0x4e, 0x8b, 0x6b, 0x27,
+	0x01, 0x7b, 0x0f, 0x6a, 0xa6, 0xbc, 0x2d, 0x5f, 0x64, 0x18, 0x89, 0x3b, 0x84, 0x86, 0x45, 0xd8,
+	0x06, 0xd4, 0x8f, 0xa6, 0x07, 0x69, 0xb5, 0x64, 0x93, 0x0d, 0xf6, 0x03, 0xcb, 0xc0, 0x0c, 0x66,
+	0x18, 0xec, 0x2a, 0x54, 0x8f, 0xa6, 0xe9, 0xd3, 0x2b, 0xe6, 0x41, 0xec, 0xed, 0xd4, 0xcd, 0x82,
+	0xdc, 0x87, 0xb0, 0x58, 0x1c, 0x77, 0xe9, 0xa3, 0x4d, 0x96, 0xf0, 0xcb, 0xaf, 0x48, 0xf8, 0x1b,
+	0xeb, 0xd0, 0xb0, 0x7f, 0x86, 0xb0, 0x16, 0xd4, 0x1e, 0x1f, 0x0c, 0x7b, 0x8f, 0x3a, 0x0b, 0xac,
+	0x09, 0xd5, 0xbd, 0xc1, 0xf0, 0x51, 0xa7, 0x84, 0xad, 0x83, 0xc1, 0x41, 0xaf, 0x53, 0xde, 0xb8,
+	0x09, 0x8b, 0xc5, 0xbf, 0x43, 0x58, 0x1b, 0x1a, 0xc3, 0xed, 0x83, 0xee, 0xce, 0xe0, 0x7f, 0x3a,
+	0x0b, 0x6c, 0x11, 0x9a, 0xfd, 0x83, 0x61, 0x6f, 0xf7, 0x31, 0xef, 0x75, 0x4a, 0x1b, 0x07, 0xd0,
+	0xca, 0xde, 0x52, 0x50, 0xc3, 0x4e, 0xff, 0xa0, 0xdb, 0x59, 0x60, 0x00, 0xf5, 0x61, 0x6f, 0x97,
+	0xf7, 0x50, 0x6f, 0x03, 0x2a, 0xc3, 0xe1, 0x5e, 0xa7, 0x8c, 0xb3, 0xee, 0x6e, 0xef, 0xee, 0xf5,
+	0x3a, 0x15, 0x6c, 0x3e, 0xda, 0x3f, 0xbc, 0x3f, 0xec, 0x54, 0x51, 0x1f, 0x2e, 0xe0, 0x70, 0xfb,
+	0xd1, 0x5e, 0xa7, 0xb6, 0xf1, 0x31, 0x5c, 0x99, 0x7b, 0x8a, 0x20, 0x5d, 0x7b, 0xdb, 0xbc, 0x87,
+	0x7a, 0xdb, 0xd0, 0x38, 0xe4, 0xfd, 0x27, 0xdb, 0x8f, 0x7a, 0x9d, 0x12, 0x0a, 0x1e, 0x0e, 0x76,
+	0x1f, 0xf4, 0xba, 0x9d, 0xf2, 0xce, 0xb5, 0x6f, 0x5e, 0xac, 0x96, 0xbe, 0x7d, 0xb1, 0x5a, 0xfa,
+	0xee, 0xc5, 0x6a, 0xe9, 0xaf, 0x2f, 0x56, 0x4b, 0x5f, 0xfd, 0xb0, 0xba, 0xf0, 0xed, 0x0f, 0xab,
+	0x0b, 0xdf, 0xfd, 0xb0, 0xba, 0x70, 0x54, 0xa7, 0xbf, 0x2a, 0x3f, 0xfa, 0xc7, 0x00, 0x7f, 0x11,
+	0x76, 0x41, 0xea, 0x1c, 0x00, 0x00,
+}
+
+func (m *Op) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -3344,46 +3344,31 @@ func (m *OpMetadata) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *OpMetadata) MarshalTo(dAtA []byte) (int, error) {
+func (m *Op) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *OpMetadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Op) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Caps) > 0 {
-		keysForCaps := make([]string, 0, len(m.Caps))
-		for k := range m.Caps {
-			keysForCaps = append(keysForCaps, string(k))
-		}
-		github_com_gogo_protobuf_sortkeys.Strings(keysForCaps)
-		for iNdEx := len(keysForCaps) - 1; iNdEx >= 0; iNdEx-- {
-			v := m.Caps[github_com_moby_buildkit_util_apicaps.CapID(keysForCaps[iNdEx])]
-			baseI := i
-			i--
-			if v {
-				dAtA[i] = 1
-			} else {
-				dAtA[i] = 0
+	if m.Constraints != nil {
+		{
+			size, err := m.Constraints.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
 			}
-			i--
-			dAtA[i] = 0x10
-			i -= len(keysForCaps[iNdEx])
-			copy(dAtA[i:], keysForCaps[iNdEx])
-			i = encodeVarintOps(dAtA, i, uint64(len(keysForCaps[iNdEx])))
-			i--
-			dAtA[i] = 0xa
-			i = encodeVarintOps(dAtA, i, uint64(baseI-i))
-			i--
-			dAtA[i] = 0x2a
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
 		}
+		i--
+		dAtA[i] = 0x5a
 	}
-	if m.ExportCache != nil {
+	if m.Platform != nil {
 		{
-			size, err := m.ExportCache.MarshalToSizedBuffer(dAtA[:i])
+			size, err := m.Platform.MarshalToSizedBuffer(dAtA[:i])
 			if err != nil {
 				return 0, err
 			}
@@ -3391,69 +3376,21 @@ func (m *OpMetadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 			i = encodeVarintOps(dAtA, i, uint64(size))
 		}
 		i--
-		dAtA[i] = 0x22
-	}
-	if len(m.Description) > 0 {
-		keysForDescription := make([]string, 0, len(m.Description))
-		for k := range m.Description {
-			keysForDescription = append(keysForDescription, string(k))
-		}
-		github_com_gogo_protobuf_sortkeys.Strings(keysForDescription)
-		for iNdEx := len(keysForDescription) - 1; iNdEx >= 0; iNdEx-- {
-			v := m.Description[string(keysForDescription[iNdEx])]
-			baseI := i
-			i -= len(v)
-			copy(dAtA[i:], v)
-			i = encodeVarintOps(dAtA, i, uint64(len(v)))
-			i--
-			dAtA[i] = 0x12
-			i -= len(keysForDescription[iNdEx])
-			copy(dAtA[i:], keysForDescription[iNdEx])
-			i = encodeVarintOps(dAtA, i, uint64(len(keysForDescription[iNdEx])))
-			i--
-			dAtA[i] = 0xa
-			i = encodeVarintOps(dAtA, i, uint64(baseI-i))
-			i--
-			dAtA[i] = 0x12
-		}
+		dAtA[i] = 0x52
 	}
-	if m.IgnoreCache {
-		i--
-		if m.IgnoreCache {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
+	if m.Op != nil {
+		{
+			size := m.Op.Size()
+			i -= size
+			if _, err := m.Op.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
 		}
-		i--
-		dAtA[i] = 0x8
-	}
-	return len(dAtA) - i, nil
-}
-
-func (m *Source) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
 	}
-	return dAtA[:n], nil
-}
-
-func (m *Source) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *Source) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Infos) > 0 {
-		for iNdEx := len(m.Infos) - 1; iNdEx >= 0; iNdEx-- {
+	if len(m.Inputs) > 0 {
+		for iNdEx := len(m.Inputs) - 1; iNdEx >= 0; iNdEx-- {
 			{
-				size, err := m.Infos[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				size, err := m.Inputs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
 				if err != nil {
 					return 0, err
 				}
@@ -3461,103 +3398,85 @@ func (m *Source) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 				i = encodeVarintOps(dAtA, i, uint64(size))
 			}
 			i--
-			dAtA[i] = 0x12
-		}
-	}
-	if len(m.Locations) > 0 {
-		keysForLocations := make([]string, 0, len(m.Locations))
-		for k := range m.Locations {
-			keysForLocations = append(keysForLocations, string(k))
-		}
-		github_com_gogo_protobuf_sortkeys.Strings(keysForLocations)
-		for iNdEx := len(keysForLocations) - 1; iNdEx >= 0; iNdEx-- {
-			v := m.Locations[string(keysForLocations[iNdEx])]
-			baseI := i
-			if v != nil {
-				{
-					size, err := v.MarshalToSizedBuffer(dAtA[:i])
-					if err != nil {
-						return 0, err
-					}
-					i -= size
-					i = encodeVarintOps(dAtA, i, uint64(size))
-				}
-				i--
-				dAtA[i] = 0x12
-			}
-			i -= len(keysForLocations[iNdEx])
-			copy(dAtA[i:], keysForLocations[iNdEx])
-			i = encodeVarintOps(dAtA, i, uint64(len(keysForLocations[iNdEx])))
-			i--
-			dAtA[i] = 0xa
-			i = encodeVarintOps(dAtA, i, uint64(baseI-i))
-			i--
 			dAtA[i] = 0xa
 		}
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *Locations) Marshal() (dAtA []byte, err error) {
+func (m *Op_Exec) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Locations) MarshalTo(dAtA []byte) (int, error) {
+func (m *Op_Exec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Exec != nil {
+		{
+			size, err := m.Exec.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Op_Source) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Locations) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Op_Source) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Locations) > 0 {
-		for iNdEx := len(m.Locations) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Locations[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintOps(dAtA, i, uint64(size))
+	if m.Source != nil {
+		{
+			size, err := m.Source.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
 			}
-			i--
-			dAtA[i] = 0xa
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
 		}
+		i--
+		dAtA[i] = 0x1a
 	}
 	return len(dAtA) - i, nil
 }
-
-func (m *SourceInfo) Marshal() (dAtA []byte, err error) {
+func (m *Op_File) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SourceInfo) MarshalTo(dAtA []byte) (int, error) {
+func (m *Op_File) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.File != nil {
+		{
+			size, err := m.File.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Op_Build) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SourceInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Op_Build) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Definition != nil {
+	if m.Build != nil {
 		{
-			size, err := m.Definition.MarshalToSizedBuffer(dAtA[:i])
+			size, err := m.Build.MarshalToSizedBuffer(dAtA[:i])
 			if err != nil {
 				return 0, err
 			}
@@ -3565,26 +3484,11 @@ func (m *SourceInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 			i = encodeVarintOps(dAtA, i, uint64(size))
 		}
 		i--
-		dAtA[i] = 0x1a
-	}
-	if len(m.Data) > 0 {
-		i -= len(m.Data)
-		copy(dAtA[i:], m.Data)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.Data)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if len(m.Filename) > 0 {
-		i -= len(m.Filename)
-		copy(dAtA[i:], m.Filename)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.Filename)))
-		i--
-		dAtA[i] = 0xa
+		dAtA[i] = 0x2a
 	}
 	return len(dAtA) - i, nil
 }
-
-func (m *Location) Marshal() (dAtA []byte, err error) {
+func (m *Platform) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -3594,39 +3498,57 @@ func (m *Location) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Location) MarshalTo(dAtA []byte) (int, error) {
+func (m *Platform) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Location) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Platform) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Ranges) > 0 {
-		for iNdEx := len(m.Ranges) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Ranges[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintOps(dAtA, i, uint64(size))
-			}
+	if len(m.OSFeatures) > 0 {
+		for iNdEx := len(m.OSFeatures) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.OSFeatures[iNdEx])
+			copy(dAtA[i:], m.OSFeatures[iNdEx])
+			i = encodeVarintOps(dAtA, i, uint64(len(m.OSFeatures[iNdEx])))
 			i--
-			dAtA[i] = 0x12
+			dAtA[i] = 0x2a
 		}
 	}
-	if m.SourceIndex != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.SourceIndex))
+	if len(m.OSVersion) > 0 {
+		i -= len(m.OSVersion)
+		copy(dAtA[i:], m.OSVersion)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.OSVersion)))
 		i--
-		dAtA[i] = 0x8
+		dAtA[i] = 0x22
+	}
+	if len(m.Variant) > 0 {
+		i -= len(m.Variant)
+		copy(dAtA[i:], m.Variant)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Variant)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.OS) > 0 {
+		i -= len(m.OS)
+		copy(dAtA[i:], m.OS)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.OS)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Architecture) > 0 {
+		i -= len(m.Architecture)
+		copy(dAtA[i:], m.Architecture)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Architecture)))
+		i--
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *Range) Marshal() (dAtA []byte, err error) {
+func (m *Input) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -3636,40 +3558,32 @@ func (m *Range) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Range) MarshalTo(dAtA []byte) (int, error) {
+func (m *Input) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Range) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Input) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	{
-		size, err := m.End.MarshalToSizedBuffer(dAtA[:i])
-		if err != nil {
-			return 0, err
-		}
-		i -= size
-		i = encodeVarintOps(dAtA, i, uint64(size))
+	if m.Index != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Index))
+		i--
+		dAtA[i] = 0x10
 	}
-	i--
-	dAtA[i] = 0x12
-	{
-		size, err := m.Start.MarshalToSizedBuffer(dAtA[:i])
-		if err != nil {
-			return 0, err
-		}
-		i -= size
-		i = encodeVarintOps(dAtA, i, uint64(size))
+	if len(m.Digest) > 0 {
+		i -= len(m.Digest)
+		copy(dAtA[i:], m.Digest)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Digest)))
+		i--
+		dAtA[i] = 0xa
 	}
-	i--
-	dAtA[i] = 0xa
 	return len(dAtA) - i, nil
 }
 
-func (m *Position) Marshal() (dAtA []byte, err error) {
+func (m *ExecOp) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -3679,30 +3593,224 @@ func (m *Position) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Position) MarshalTo(dAtA []byte) (int, error) {
+func (m *ExecOp) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Position) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *ExecOp) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Character != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Character))
+	if len(m.Sysctls) > 0 {
+		keysForSysctls := make([]string, 0, len(m.Sysctls))
+		for k := range m.Sysctls {
+			keysForSysctls = append(keysForSysctls, string(k))
+		}
+		github_com_gogo_protobuf_sortkeys.Strings(keysForSysctls)
+		for iNdEx := len(keysForSysctls) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.Sysctls[string(keysForSysctls[iNdEx])]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintOps(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(keysForSysctls[iNdEx])
+			copy(dAtA[i:], keysForSysctls[iNdEx])
+			i = encodeVarintOps(dAtA, i, uint64(len(keysForSysctls[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintOps(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x1
+			i--
+			dAtA[i] = 0x82
+		}
+	}
+	if m.UserNSMapping != nil {
+		{
+			size, err := m.UserNSMapping.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
 		i--
-		dAtA[i] = 0x10
+		dAtA[i] = 0x7a
 	}
-	if m.Line != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Line))
+	if m.Retry != nil {
+		{
+			size, err := m.Retry.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
 		i--
-		dAtA[i] = 0x8
+		dAtA[i] = 0x72
+	}
+	if len(m.IgnoreForCache) > 0 {
+		for iNdEx := len(m.IgnoreForCache) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.IgnoreForCache[iNdEx])
+			copy(dAtA[i:], m.IgnoreForCache[iNdEx])
+			i = encodeVarintOps(dAtA, i, uint64(len(m.IgnoreForCache[iNdEx])))
+			i--
+			dAtA[i] = 0x6a
+		}
+	}
+	if len(m.ApparmorProfile) > 0 {
+		i -= len(m.ApparmorProfile)
+		copy(dAtA[i:], m.ApparmorProfile)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.ApparmorProfile)))
+		i--
+		dAtA[i] = 0x62
+	}
+	if m.SharedPID != nil {
+		{
+			size, err := m.SharedPID.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x5a
+	}
+	if m.Resources != nil {
+		{
+			size, err := m.Resources.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x52
+	}
+	if len(m.After) > 0 {
+		dAtA12 := make([]byte, len(m.After)*10)
+		var j11 int
+		for _, num1 := range m.After {
+			num := uint64(num1)
+			for num >= 1<<7 {
+				dAtA12[j11] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j11++
+			}
+			dAtA12[j11] = uint8(num)
+			j11++
+		}
+		i -= j11
+		copy(dAtA[i:], dAtA12[:j11])
+		i = encodeVarintOps(dAtA, i, uint64(j11))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.Secretenv) > 0 {
+		for iNdEx := len(m.Secretenv) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Secretenv[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintOps(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if len(m.Devices) > 0 {
+		for iNdEx := len(m.Devices) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Devices[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintOps(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if m.Seccomp != nil {
+		{
+			size, err := m.Seccomp.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.AllowedExitCodes) > 0 {
+		dAtA15 := make([]byte, len(m.AllowedExitCodes)*10)
+		var j14 int
+		for _, num1 := range m.AllowedExitCodes {
+			num := uint64(num1)
+			for num >= 1<<7 {
+				dAtA15[j14] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j14++
+			}
+			dAtA15[j14] = uint8(num)
+			j14++
+		}
+		i -= j14
+		copy(dAtA[i:], dAtA15[:j14])
+		i = encodeVarintOps(dAtA, i, uint64(j14))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Security != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Security))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Network != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Network))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Mounts) > 0 {
+		for iNdEx := len(m.Mounts) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Mounts[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintOps(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Meta != nil {
+		{
+			size, err := m.Meta.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *ExportCache) Marshal() (dAtA []byte, err error) {
+func (m *UserNSMapping) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -3712,30 +3820,48 @@ func (m *ExportCache) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *ExportCache) MarshalTo(dAtA []byte) (int, error) {
+func (m *UserNSMapping) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *ExportCache) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *UserNSMapping) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Value {
-		i--
-		if m.Value {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
+	if len(m.GidMappings) > 0 {
+		for iNdEx := len(m.GidMappings) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.GidMappings[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintOps(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.UidMappings) > 0 {
+		for iNdEx := len(m.UidMappings) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.UidMappings[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintOps(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
 		}
-		i--
-		dAtA[i] = 0x8
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *ProxyEnv) Marshal() (dAtA []byte, err error) {
+func (m *IDMap) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -3745,55 +3871,35 @@ func (m *ProxyEnv) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *ProxyEnv) MarshalTo(dAtA []byte) (int, error) {
+func (m *IDMap) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *ProxyEnv) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *IDMap) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.AllProxy) > 0 {
-		i -= len(m.AllProxy)
-		copy(dAtA[i:], m.AllProxy)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.AllProxy)))
+	if m.Size_ != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Size_))
 		i--
-		dAtA[i] = 0x2a
+		dAtA[i] = 0x18
 	}
-	if len(m.NoProxy) > 0 {
-		i -= len(m.NoProxy)
-		copy(dAtA[i:], m.NoProxy)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.NoProxy)))
+	if m.HostID != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.HostID))
 		i--
-		dAtA[i] = 0x22
+		dAtA[i] = 0x10
 	}
-	if len(m.FtpProxy) > 0 {
-		i -= len(m.FtpProxy)
-		copy(dAtA[i:], m.FtpProxy)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.FtpProxy)))
-		i--
-		dAtA[i] = 0x1a
-	}
-	if len(m.HttpsProxy) > 0 {
-		i -= len(m.HttpsProxy)
-		copy(dAtA[i:], m.HttpsProxy)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.HttpsProxy)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if len(m.HttpProxy) > 0 {
-		i -= len(m.HttpProxy)
-		copy(dAtA[i:], m.HttpProxy)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.HttpProxy)))
+	if m.ContainerID != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.ContainerID))
 		i--
-		dAtA[i] = 0xa
+		dAtA[i] = 0x8
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *WorkerConstraints) Marshal() (dAtA []byte, err error) {
+func (m *ExecRetry) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -3803,29 +3909,44 @@ func (m *WorkerConstraints) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *WorkerConstraints) MarshalTo(dAtA []byte) (int, error) {
+func (m *ExecRetry) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *WorkerConstraints) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *ExecRetry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Filter) > 0 {
-		for iNdEx := len(m.Filter) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Filter[iNdEx])
-			copy(dAtA[i:], m.Filter[iNdEx])
-			i = encodeVarintOps(dAtA, i, uint64(len(m.Filter[iNdEx])))
-			i--
-			dAtA[i] = 0xa
-		}
+	if len(m.ExitCodes) > 0 {
+		dAtA18 := make([]byte, len(m.ExitCodes)*10)
+		var j17 int
+		for _, num1 := range m.ExitCodes {
+			num := uint64(num1)
+			for num >= 1<<7 {
+				dAtA18[j17] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j17++
+			}
+			dAtA18[j17] = uint8(num)
+			j17++
+		}
+		i -= j17
+		copy(dAtA[i:], dAtA18[:j17])
+		i = encodeVarintOps(dAtA, i, uint64(j17))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Attempts != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Attempts))
+		i--
+		dAtA[i] = 0x8
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *Definition) Marshal() (dAtA []byte, err error) {
+func (m *SharedPID) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -3835,39 +3956,34 @@ func (m *Definition) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Definition) MarshalTo(dAtA []byte) (int, error) {
+func (m *SharedPID) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Definition) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *SharedPID) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Source != nil {
-		{
-			size, err := m.Source.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
+	if len(m.Secretenv) > 0 {
+		for iNdEx := len(m.Secretenv) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Secretenv[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintOps(dAtA, i, uint64(size))
 			}
-			i -= size
-			i = encodeVarintOps(dAtA, i, uint64(size))
+			i--
+			dAtA[i] = 0x1a
 		}
-		i--
-		dAtA[i] = 0x1a
 	}
-	if len(m.Metadata) > 0 {
-		keysForMetadata := make([]string, 0, len(m.Metadata))
-		for k := range m.Metadata {
-			keysForMetadata = append(keysForMetadata, string(k))
-		}
-		github_com_gogo_protobuf_sortkeys.Strings(keysForMetadata)
-		for iNdEx := len(keysForMetadata) - 1; iNdEx >= 0; iNdEx-- {
-			v := m.Metadata[github_com_opencontainers_go_digest.Digest(keysForMetadata[iNdEx])]
-			baseI := i
+	if len(m.Mounts) > 0 {
+		for iNdEx := len(m.Mounts) - 1; iNdEx >= 0; iNdEx-- {
 			{
-				size, err := (&v).MarshalToSizedBuffer(dAtA[:i])
+				size, err := m.Mounts[iNdEx].MarshalToSizedBuffer(dAtA[:i])
 				if err != nil {
 					return 0, err
 				}
@@ -3876,29 +3992,24 @@ func (m *Definition) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 			}
 			i--
 			dAtA[i] = 0x12
-			i -= len(keysForMetadata[iNdEx])
-			copy(dAtA[i:], keysForMetadata[iNdEx])
-			i = encodeVarintOps(dAtA, i, uint64(len(keysForMetadata[iNdEx])))
-			i--
-			dAtA[i] = 0xa
-			i = encodeVarintOps(dAtA, i, uint64(baseI-i))
-			i--
-			dAtA[i] = 0x12
 		}
 	}
-	if len(m.Def) > 0 {
-		for iNdEx := len(m.Def) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Def[iNdEx])
-			copy(dAtA[i:], m.Def[iNdEx])
-			i = encodeVarintOps(dAtA, i, uint64(len(m.Def[iNdEx])))
-			i--
-			dAtA[i] = 0xa
+	if m.Meta != nil {
+		{
+			size, err := m.Meta.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
 		}
+		i--
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *HostIP) Marshal() (dAtA []byte, err error) {
+func (m *Resources) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -3908,34 +4019,35 @@ func (m *HostIP) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *HostIP) MarshalTo(dAtA []byte) (int, error) {
+func (m *Resources) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *HostIP) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Resources) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.IP) > 0 {
-		i -= len(m.IP)
-		copy(dAtA[i:], m.IP)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.IP)))
+	if m.CpuPeriod != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.CpuPeriod))
 		i--
-		dAtA[i] = 0x12
+		dAtA[i] = 0x18
 	}
-	if len(m.Host) > 0 {
-		i -= len(m.Host)
-		copy(dAtA[i:], m.Host)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.Host)))
+	if m.CpuQuota != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.CpuQuota))
 		i--
-		dAtA[i] = 0xa
+		dAtA[i] = 0x10
+	}
+	if m.Memory != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Memory))
+		i--
+		dAtA[i] = 0x8
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *FileOp) Marshal() (dAtA []byte, err error) {
+func (m *SecretEnv) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -3945,34 +4057,44 @@ func (m *FileOp) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *FileOp) MarshalTo(dAtA []byte) (int, error) {
+func (m *SecretEnv) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *FileOp) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *SecretEnv) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Actions) > 0 {
-		for iNdEx := len(m.Actions) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Actions[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintOps(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x12
+	if m.Optional {
+		i--
+		if m.Optional {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
 		}
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ID) > 0 {
+		i -= len(m.ID)
+		copy(dAtA[i:], m.ID)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.ID)))
+		i--
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *FileAction) Marshal() (dAtA []byte, err error) {
+func (m *Device) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -3982,116 +4104,96 @@ func (m *FileAction) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *FileAction) MarshalTo(dAtA []byte) (int, error) {
+func (m *Device) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *FileAction) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Device) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Action != nil {
-		{
-			size := m.Action.Size()
-			i -= size
-			if _, err := m.Action.MarshalTo(dAtA[i:]); err != nil {
-				return 0, err
-			}
-		}
-	}
-	if m.Output != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Output))
+	if len(m.Permissions) > 0 {
+		i -= len(m.Permissions)
+		copy(dAtA[i:], m.Permissions)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Permissions)))
 		i--
-		dAtA[i] = 0x18
-	}
-	if m.SecondaryInput != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.SecondaryInput))
-		i--
-		dAtA[i] = 0x10
+		dAtA[i] = 0x12
 	}
-	if m.Input != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Input))
+	if len(m.Path) > 0 {
+		i -= len(m.Path)
+		copy(dAtA[i:], m.Path)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Path)))
 		i--
-		dAtA[i] = 0x8
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *FileAction_Copy) MarshalTo(dAtA []byte) (int, error) {
+func (m *SeccompOpt) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *FileAction_Copy) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	if m.Copy != nil {
-		{
-			size, err := m.Copy.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintOps(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x22
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return len(dAtA) - i, nil
+	return dAtA[:n], nil
 }
-func (m *FileAction_Mkfile) MarshalTo(dAtA []byte) (int, error) {
+
+func (m *SeccompOpt) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *FileAction_Mkfile) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *SeccompOpt) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
-	if m.Mkfile != nil {
-		{
-			size, err := m.Mkfile.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintOps(dAtA, i, uint64(size))
+	_ = i
+	var l int
+	_ = l
+	if m.Unconfined {
+		i--
+		if m.Unconfined {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
 		}
 		i--
-		dAtA[i] = 0x2a
+		dAtA[i] = 0x10
+	}
+	if len(m.Profile) > 0 {
+		i -= len(m.Profile)
+		copy(dAtA[i:], m.Profile)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Profile)))
+		i--
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
-func (m *FileAction_Mkdir) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
 
-func (m *FileAction_Mkdir) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	if m.Mkdir != nil {
-		{
-			size, err := m.Mkdir.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintOps(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x32
+func (m *Meta) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return len(dAtA) - i, nil
+	return dAtA[:n], nil
 }
-func (m *FileAction_Rm) MarshalTo(dAtA []byte) (int, error) {
+
+func (m *Meta) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *FileAction_Rm) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Meta) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
-	if m.Rm != nil {
+	_ = i
+	var l int
+	_ = l
+	if m.Timezone != nil {
 		{
-			size, err := m.Rm.MarshalToSizedBuffer(dAtA[:i])
+			size, err := m.Timezone.MarshalToSizedBuffer(dAtA[:i])
 			if err != nil {
 				return 0, err
 			}
@@ -4099,121 +4201,78 @@ func (m *FileAction_Rm) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 			i = encodeVarintOps(dAtA, i, uint64(size))
 		}
 		i--
-		dAtA[i] = 0x3a
-	}
-	return len(dAtA) - i, nil
-}
-func (m *FileActionCopy) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
-}
-
-func (m *FileActionCopy) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *FileActionCopy) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.ExcludePatterns) > 0 {
-		for iNdEx := len(m.ExcludePatterns) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.ExcludePatterns[iNdEx])
-			copy(dAtA[i:], m.ExcludePatterns[iNdEx])
-			i = encodeVarintOps(dAtA, i, uint64(len(m.ExcludePatterns[iNdEx])))
-			i--
-			dAtA[i] = 0x6a
-		}
+		dAtA[i] = 0x72
 	}
-	if len(m.IncludePatterns) > 0 {
-		for iNdEx := len(m.IncludePatterns) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.IncludePatterns[iNdEx])
-			copy(dAtA[i:], m.IncludePatterns[iNdEx])
-			i = encodeVarintOps(dAtA, i, uint64(len(m.IncludePatterns[iNdEx])))
-			i--
-			dAtA[i] = 0x62
-		}
-	}
-	if m.Timestamp != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Timestamp))
+	if len(m.RedirectStderr) > 0 {
+		i -= len(m.RedirectStderr)
+		copy(dAtA[i:], m.RedirectStderr)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.RedirectStderr)))
 		i--
-		dAtA[i] = 0x58
+		dAtA[i] = 0x6a
 	}
-	if m.AllowEmptyWildcard {
-		i--
-		if m.AllowEmptyWildcard {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
+	if len(m.RedirectStdout) > 0 {
+		i -= len(m.RedirectStdout)
+		copy(dAtA[i:], m.RedirectStdout)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.RedirectStdout)))
 		i--
-		dAtA[i] = 0x50
+		dAtA[i] = 0x62
 	}
-	if m.AllowWildcard {
-		i--
-		if m.AllowWildcard {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
+	if len(m.PassthroughEnv) > 0 {
+		for iNdEx := len(m.PassthroughEnv) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.PassthroughEnv[iNdEx])
+			copy(dAtA[i:], m.PassthroughEnv[iNdEx])
+			i = encodeVarintOps(dAtA, i, uint64(len(m.PassthroughEnv[iNdEx])))
+			i--
+			dAtA[i] = 0x5a
 		}
-		i--
-		dAtA[i] = 0x48
 	}
-	if m.CreateDestPath {
-		i--
-		if m.CreateDestPath {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
+	if len(m.Umask) > 0 {
+		i -= len(m.Umask)
+		copy(dAtA[i:], m.Umask)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Umask)))
 		i--
-		dAtA[i] = 0x40
+		dAtA[i] = 0x52
 	}
-	if m.AttemptUnpackDockerCompatibility {
-		i--
-		if m.AttemptUnpackDockerCompatibility {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
+	if len(m.CacheIgnoreEnv) > 0 {
+		for iNdEx := len(m.CacheIgnoreEnv) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.CacheIgnoreEnv[iNdEx])
+			copy(dAtA[i:], m.CacheIgnoreEnv[iNdEx])
+			i = encodeVarintOps(dAtA, i, uint64(len(m.CacheIgnoreEnv[iNdEx])))
+			i--
+			dAtA[i] = 0x4a
 		}
-		i--
-		dAtA[i] = 0x38
 	}
-	if m.DirCopyContents {
+	if len(m.Entrypoint) > 0 {
+		i -= len(m.Entrypoint)
+		copy(dAtA[i:], m.Entrypoint)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Entrypoint)))
 		i--
-		if m.DirCopyContents {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
-		i--
-		dAtA[i] = 0x30
+		dAtA[i] = 0x42
 	}
-	if m.FollowSymlink {
-		i--
-		if m.FollowSymlink {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
+	if len(m.Hostname) > 0 {
+		i -= len(m.Hostname)
+		copy(dAtA[i:], m.Hostname)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Hostname)))
 		i--
-		dAtA[i] = 0x28
+		dAtA[i] = 0x3a
 	}
-	if m.Mode != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Mode))
-		i--
-		dAtA[i] = 0x20
+	if len(m.ExtraHosts) > 0 {
+		for iNdEx := len(m.ExtraHosts) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.ExtraHosts[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintOps(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x32
+		}
 	}
-	if m.Owner != nil {
+	if m.ProxyEnv != nil {
 		{
-			size, err := m.Owner.MarshalToSizedBuffer(dAtA[:i])
+			size, err := m.ProxyEnv.MarshalToSizedBuffer(dAtA[:i])
 			if err != nil {
 				return 0, err
 			}
@@ -4221,26 +4280,44 @@ func (m *FileActionCopy) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 			i = encodeVarintOps(dAtA, i, uint64(size))
 		}
 		i--
-		dAtA[i] = 0x1a
+		dAtA[i] = 0x2a
 	}
-	if len(m.Dest) > 0 {
-		i -= len(m.Dest)
-		copy(dAtA[i:], m.Dest)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.Dest)))
+	if len(m.User) > 0 {
+		i -= len(m.User)
+		copy(dAtA[i:], m.User)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.User)))
 		i--
-		dAtA[i] = 0x12
+		dAtA[i] = 0x22
 	}
-	if len(m.Src) > 0 {
-		i -= len(m.Src)
-		copy(dAtA[i:], m.Src)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.Src)))
+	if len(m.Cwd) > 0 {
+		i -= len(m.Cwd)
+		copy(dAtA[i:], m.Cwd)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Cwd)))
 		i--
-		dAtA[i] = 0xa
+		dAtA[i] = 0x1a
+	}
+	if len(m.Env) > 0 {
+		for iNdEx := len(m.Env) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Env[iNdEx])
+			copy(dAtA[i:], m.Env[iNdEx])
+			i = encodeVarintOps(dAtA, i, uint64(len(m.Env[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Args) > 0 {
+		for iNdEx := len(m.Args) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Args[iNdEx])
+			copy(dAtA[i:], m.Args[iNdEx])
+			i = encodeVarintOps(dAtA, i, uint64(len(m.Args[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *FileActionMkFile) Marshal() (dAtA []byte, err error) {
+func (m *Timezone) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -4250,56 +4327,37 @@ func (m *FileActionMkFile) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *FileActionMkFile) MarshalTo(dAtA []byte) (int, error) {
+func (m *Timezone) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *FileActionMkFile) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Timezone) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Timestamp != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Timestamp))
+	if m.MountZoneinfo {
 		i--
-		dAtA[i] = 0x28
-	}
-	if m.Owner != nil {
-		{
-			size, err := m.Owner.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintOps(dAtA, i, uint64(size))
+		if m.MountZoneinfo {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
 		}
 		i--
-		dAtA[i] = 0x22
-	}
-	if len(m.Data) > 0 {
-		i -= len(m.Data)
-		copy(dAtA[i:], m.Data)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.Data)))
-		i--
-		dAtA[i] = 0x1a
-	}
-	if m.Mode != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Mode))
-		i--
 		dAtA[i] = 0x10
 	}
-	if len(m.Path) > 0 {
-		i -= len(m.Path)
-		copy(dAtA[i:], m.Path)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.Path)))
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Name)))
 		i--
 		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *FileActionMkDir) Marshal() (dAtA []byte, err error) {
+func (m *Mount) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -4309,24 +4367,19 @@ func (m *FileActionMkDir) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *FileActionMkDir) MarshalTo(dAtA []byte) (int, error) {
+func (m *Mount) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *FileActionMkDir) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Mount) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Timestamp != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Timestamp))
-		i--
-		dAtA[i] = 0x28
-	}
-	if m.Owner != nil {
+	if m.HostPathOpt != nil {
 		{
-			size, err := m.Owner.MarshalToSizedBuffer(dAtA[:i])
+			size, err := m.HostPathOpt.MarshalToSizedBuffer(dAtA[:i])
 			if err != nil {
 				return 0, err
 			}
@@ -4334,84 +4387,104 @@ func (m *FileActionMkDir) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 			i = encodeVarintOps(dAtA, i, uint64(size))
 		}
 		i--
-		dAtA[i] = 0x22
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xc2
 	}
-	if m.MakeParents {
+	if len(m.ResultID) > 0 {
+		i -= len(m.ResultID)
+		copy(dAtA[i:], m.ResultID)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.ResultID)))
 		i--
-		if m.MakeParents {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
+		dAtA[i] = 0x1
 		i--
-		dAtA[i] = 0x18
+		dAtA[i] = 0xba
 	}
-	if m.Mode != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Mode))
+	if m.SSHOpt != nil {
+		{
+			size, err := m.SSHOpt.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
 		i--
-		dAtA[i] = 0x10
-	}
-	if len(m.Path) > 0 {
-		i -= len(m.Path)
-		copy(dAtA[i:], m.Path)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.Path)))
+		dAtA[i] = 0x1
 		i--
-		dAtA[i] = 0xa
+		dAtA[i] = 0xb2
 	}
-	return len(dAtA) - i, nil
-}
-
-func (m *FileActionRm) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
-}
-
-func (m *FileActionRm) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *FileActionRm) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.AllowWildcard {
+	if m.SecretOpt != nil {
+		{
+			size, err := m.SecretOpt.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
 		i--
-		if m.AllowWildcard {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xaa
+	}
+	if m.CacheOpt != nil {
+		{
+			size, err := m.CacheOpt.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
 		}
 		i--
-		dAtA[i] = 0x18
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xa2
 	}
-	if m.AllowNotFound {
+	if m.MountType != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.MountType))
 		i--
-		if m.AllowNotFound {
+		dAtA[i] = 0x30
+	}
+	if m.Readonly {
+		i--
+		if m.Readonly {
 			dAtA[i] = 1
 		} else {
 			dAtA[i] = 0
 		}
 		i--
-		dAtA[i] = 0x10
+		dAtA[i] = 0x28
 	}
-	if len(m.Path) > 0 {
-		i -= len(m.Path)
-		copy(dAtA[i:], m.Path)
-		i = encodeVarintOps(dAtA, i, uint64(len(m.Path)))
+	if m.Output != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Output))
 		i--
-		dAtA[i] = 0xa
+		dAtA[i] = 0x20
+	}
+	if len(m.Dest) > 0 {
+		i -= len(m.Dest)
+		copy(dAtA[i:], m.Dest)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Dest)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Selector) > 0 {
+		i -= len(m.Selector)
+		copy(dAtA[i:], m.Selector)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Selector)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Input != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Input))
+		i--
+		dAtA[i] = 0x8
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *ChownOpt) Marshal() (dAtA []byte, err error) {
+func (m *CacheOpt) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -4421,44 +4494,32 @@ func (m *ChownOpt) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *ChownOpt) MarshalTo(dAtA []byte) (int, error) {
+func (m *CacheOpt) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *ChownOpt) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *CacheOpt) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Group != nil {
-		{
-			size, err := m.Group.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintOps(dAtA, i, uint64(size))
-		}
+	if m.Sharing != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Sharing))
 		i--
-		dAtA[i] = 0x12
+		dAtA[i] = 0x10
 	}
-	if m.User != nil {
-		{
-			size, err := m.User.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintOps(dAtA, i, uint64(size))
-		}
+	if len(m.ID) > 0 {
+		i -= len(m.ID)
+		copy(dAtA[i:], m.ID)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.ID)))
 		i--
 		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *UserOpt) Marshal() (dAtA []byte, err error) {
+func (m *SecretOpt) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -4468,62 +4529,107 @@ func (m *UserOpt) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *UserOpt) MarshalTo(dAtA []byte) (int, error) {
+func (m *SecretOpt) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *UserOpt) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *SecretOpt) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.User != nil {
-		{
-			size := m.User.Size()
-			i -= size
-			if _, err := m.User.MarshalTo(dAtA[i:]); err != nil {
-				return 0, err
-			}
+	if m.Optional {
+		i--
+		if m.Optional {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
 		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Mode != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Mode))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Gid != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Gid))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Uid != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Uid))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.ID) > 0 {
+		i -= len(m.ID)
+		copy(dAtA[i:], m.ID)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.ID)))
+		i--
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *UserOpt_ByName) MarshalTo(dAtA []byte) (int, error) {
+func (m *SSHOpt) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SSHOpt) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *UserOpt_ByName) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *SSHOpt) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
-	if m.ByName != nil {
-		{
-			size, err := m.ByName.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintOps(dAtA, i, uint64(size))
+	_ = i
+	var l int
+	_ = l
+	if m.Optional {
+		i--
+		if m.Optional {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
 		}
 		i--
+		dAtA[i] = 0x28
+	}
+	if m.Mode != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Mode))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Gid != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Gid))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Uid != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Uid))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.ID) > 0 {
+		i -= len(m.ID)
+		copy(dAtA[i:], m.ID)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.ID)))
+		i--
 		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
-func (m *UserOpt_ByID) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
 
-func (m *UserOpt_ByID) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	i = encodeVarintOps(dAtA, i, uint64(m.ByID))
-	i--
-	dAtA[i] = 0x10
-	return len(dAtA) - i, nil
-}
-func (m *NamedUserOpt) Marshal() (dAtA []byte, err error) {
+func (m *HostPathOpt) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -4533,21 +4639,16 @@ func (m *NamedUserOpt) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *NamedUserOpt) MarshalTo(dAtA []byte) (int, error) {
+func (m *HostPathOpt) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *NamedUserOpt) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *HostPathOpt) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Input != 0 {
-		i = encodeVarintOps(dAtA, i, uint64(m.Input))
-		i--
-		dAtA[i] = 0x10
-	}
 	if len(m.Name) > 0 {
 		i -= len(m.Name)
 		copy(dAtA[i:], m.Name)
@@ -4558,916 +4659,4272 @@ func (m *NamedUserOpt) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintOps(dAtA []byte, offset int, v uint64) int {
-	offset -= sovOps(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *SourceOp) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return dAtA[:n], nil
 }
-func (m *Op) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.Inputs) > 0 {
-		for _, e := range m.Inputs {
-			l = e.Size()
-			n += 1 + l + sovOps(uint64(l))
-		}
-	}
-	if m.Op != nil {
-		n += m.Op.Size()
-	}
-	if m.Platform != nil {
-		l = m.Platform.Size()
-		n += 1 + l + sovOps(uint64(l))
-	}
-	if m.Constraints != nil {
-		l = m.Constraints.Size()
-		n += 1 + l + sovOps(uint64(l))
-	}
-	return n
+
+func (m *SourceOp) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Op_Exec) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *SourceOp) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.Exec != nil {
-		l = m.Exec.Size()
-		n += 1 + l + sovOps(uint64(l))
-	}
-	return n
-}
-func (m *Op_Source) Size() (n int) {
-	if m == nil {
-		return 0
+	if len(m.Attrs) > 0 {
+		keysForAttrs := make([]string, 0, len(m.Attrs))
+		for k := range m.Attrs {
+			keysForAttrs = append(keysForAttrs, string(k))
+		}
+		github_com_gogo_protobuf_sortkeys.Strings(keysForAttrs)
+		for iNdEx := len(keysForAttrs) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.Attrs[string(keysForAttrs[iNdEx])]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintOps(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(keysForAttrs[iNdEx])
+			copy(dAtA[i:], keysForAttrs[iNdEx])
+			i = encodeVarintOps(dAtA, i, uint64(len(keysForAttrs[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintOps(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x12
+		}
 	}
-	var l int
-	_ = l
-	if m.Source != nil {
-		l = m.Source.Size()
-		n += 1 + l + sovOps(uint64(l))
+	if len(m.Identifier) > 0 {
+		i -= len(m.Identifier)
+		copy(dAtA[i:], m.Identifier)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Identifier)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
-func (m *Op_File) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.File != nil {
-		l = m.File.Size()
-		n += 1 + l + sovOps(uint64(l))
+
+func (m *BuildOp) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
-func (m *Op_Build) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Build != nil {
-		l = m.Build.Size()
-		n += 1 + l + sovOps(uint64(l))
-	}
-	return n
+
+func (m *BuildOp) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
-func (m *Platform) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+
+func (m *BuildOp) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Architecture)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
-	}
-	l = len(m.OS)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
-	}
-	l = len(m.Variant)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+	if len(m.Attrs) > 0 {
+		keysForAttrs := make([]string, 0, len(m.Attrs))
+		for k := range m.Attrs {
+			keysForAttrs = append(keysForAttrs, string(k))
+		}
+		github_com_gogo_protobuf_sortkeys.Strings(keysForAttrs)
+		for iNdEx := len(keysForAttrs) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.Attrs[string(keysForAttrs[iNdEx])]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintOps(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(keysForAttrs[iNdEx])
+			copy(dAtA[i:], keysForAttrs[iNdEx])
+			i = encodeVarintOps(dAtA, i, uint64(len(keysForAttrs[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintOps(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x22
+		}
 	}
-	l = len(m.OSVersion)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+	if m.Def != nil {
+		{
+			size, err := m.Def.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
 	}
-	if len(m.OSFeatures) > 0 {
-		for _, s := range m.OSFeatures {
-			l = len(s)
-			n += 1 + l + sovOps(uint64(l))
+	if len(m.Inputs) > 0 {
+		keysForInputs := make([]string, 0, len(m.Inputs))
+		for k := range m.Inputs {
+			keysForInputs = append(keysForInputs, string(k))
+		}
+		github_com_gogo_protobuf_sortkeys.Strings(keysForInputs)
+		for iNdEx := len(keysForInputs) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.Inputs[string(keysForInputs[iNdEx])]
+			baseI := i
+			if v != nil {
+				{
+					size, err := v.MarshalToSizedBuffer(dAtA[:i])
+					if err != nil {
+						return 0, err
+					}
+					i -= size
+					i = encodeVarintOps(dAtA, i, uint64(size))
+				}
+				i--
+				dAtA[i] = 0x12
+			}
+			i -= len(keysForInputs[iNdEx])
+			copy(dAtA[i:], keysForInputs[iNdEx])
+			i = encodeVarintOps(dAtA, i, uint64(len(keysForInputs[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintOps(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x12
 		}
 	}
-	return n
+	if m.Builder != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Builder))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *Input) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *BuildInput) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *BuildInput) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *BuildInput) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Digest)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
-	}
-	if m.Index != 0 {
-		n += 1 + sovOps(uint64(m.Index))
+	if m.Input != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Input))
+		i--
+		dAtA[i] = 0x8
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *ExecOp) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *OpMetadata) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *OpMetadata) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *OpMetadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.Meta != nil {
-		l = m.Meta.Size()
-		n += 1 + l + sovOps(uint64(l))
-	}
-	if len(m.Mounts) > 0 {
-		for _, e := range m.Mounts {
-			l = e.Size()
-			n += 1 + l + sovOps(uint64(l))
+	if m.ProgressGroup != nil {
+		{
+			size, err := m.ProgressGroup.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
 		}
+		i--
+		dAtA[i] = 0x32
 	}
-	if m.Network != 0 {
-		n += 1 + sovOps(uint64(m.Network))
-	}
-	if m.Security != 0 {
-		n += 1 + sovOps(uint64(m.Security))
-	}
-	return n
-}
-
-func (m *Meta) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.Args) > 0 {
-		for _, s := range m.Args {
-			l = len(s)
-			n += 1 + l + sovOps(uint64(l))
+	if len(m.Caps) > 0 {
+		keysForCaps := make([]string, 0, len(m.Caps))
+		for k := range m.Caps {
+			keysForCaps = append(keysForCaps, string(k))
 		}
-	}
-	if len(m.Env) > 0 {
-		for _, s := range m.Env {
-			l = len(s)
-			n += 1 + l + sovOps(uint64(l))
+		github_com_gogo_protobuf_sortkeys.Strings(keysForCaps)
+		for iNdEx := len(keysForCaps) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.Caps[github_com_moby_buildkit_util_apicaps.CapID(keysForCaps[iNdEx])]
+			baseI := i
+			i--
+			if v {
+				dAtA[i] = 1
+			} else {
+				dAtA[i] = 0
+			}
+			i--
+			dAtA[i] = 0x10
+			i -= len(keysForCaps[iNdEx])
+			copy(dAtA[i:], keysForCaps[iNdEx])
+			i = encodeVarintOps(dAtA, i, uint64(len(keysForCaps[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintOps(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x2a
 		}
 	}
-	l = len(m.Cwd)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
-	}
-	l = len(m.User)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
-	}
-	if m.ProxyEnv != nil {
-		l = m.ProxyEnv.Size()
-		n += 1 + l + sovOps(uint64(l))
+	if m.ExportCache != nil {
+		{
+			size, err := m.ExportCache.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
 	}
-	if len(m.ExtraHosts) > 0 {
-		for _, e := range m.ExtraHosts {
-			l = e.Size()
-			n += 1 + l + sovOps(uint64(l))
+	if len(m.Description) > 0 {
+		keysForDescription := make([]string, 0, len(m.Description))
+		for k := range m.Description {
+			keysForDescription = append(keysForDescription, string(k))
+		}
+		github_com_gogo_protobuf_sortkeys.Strings(keysForDescription)
+		for iNdEx := len(keysForDescription) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.Description[string(keysForDescription[iNdEx])]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintOps(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(keysForDescription[iNdEx])
+			copy(dAtA[i:], keysForDescription[iNdEx])
+			i = encodeVarintOps(dAtA, i, uint64(len(keysForDescription[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintOps(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x12
 		}
 	}
-	l = len(m.Hostname)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+	if m.IgnoreCache {
+		i--
+		if m.IgnoreCache {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x8
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *Mount) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Input != 0 {
-		n += 1 + sovOps(uint64(m.Input))
-	}
-	l = len(m.Selector)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
-	}
-	l = len(m.Dest)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
-	}
-	if m.Output != 0 {
-		n += 1 + sovOps(uint64(m.Output))
-	}
-	if m.Readonly {
-		n += 2
-	}
-	if m.MountType != 0 {
-		n += 1 + sovOps(uint64(m.MountType))
-	}
-	if m.CacheOpt != nil {
-		l = m.CacheOpt.Size()
-		n += 2 + l + sovOps(uint64(l))
-	}
-	if m.SecretOpt != nil {
-		l = m.SecretOpt.Size()
-		n += 2 + l + sovOps(uint64(l))
-	}
-	if m.SSHOpt != nil {
-		l = m.SSHOpt.Size()
-		n += 2 + l + sovOps(uint64(l))
-	}
-	l = len(m.ResultID)
-	if l > 0 {
-		n += 2 + l + sovOps(uint64(l))
+func (m *ProgressGroup) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *CacheOpt) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.ID)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
-	}
-	if m.Sharing != 0 {
-		n += 1 + sovOps(uint64(m.Sharing))
-	}
-	return n
+func (m *ProgressGroup) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SecretOpt) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *ProgressGroup) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.ID)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
-	}
-	if m.Uid != 0 {
-		n += 1 + sovOps(uint64(m.Uid))
-	}
-	if m.Gid != 0 {
-		n += 1 + sovOps(uint64(m.Gid))
-	}
-	if m.Mode != 0 {
-		n += 1 + sovOps(uint64(m.Mode))
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0x12
 	}
-	if m.Optional {
-		n += 2
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SSHOpt) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.ID)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
-	}
-	if m.Uid != 0 {
-		n += 1 + sovOps(uint64(m.Uid))
-	}
-	if m.Gid != 0 {
-		n += 1 + sovOps(uint64(m.Gid))
-	}
-	if m.Mode != 0 {
-		n += 1 + sovOps(uint64(m.Mode))
-	}
-	if m.Optional {
-		n += 2
+func (m *Source) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *SourceOp) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *Source) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Source) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Identifier)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+	if len(m.Infos) > 0 {
+		for iNdEx := len(m.Infos) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Infos[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintOps(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
 	}
-	if len(m.Attrs) > 0 {
-		for k, v := range m.Attrs {
-			_ = k
-			_ = v
-			mapEntrySize := 1 + len(k) + sovOps(uint64(len(k))) + 1 + len(v) + sovOps(uint64(len(v)))
-			n += mapEntrySize + 1 + sovOps(uint64(mapEntrySize))
+	if len(m.Locations) > 0 {
+		keysForLocations := make([]string, 0, len(m.Locations))
+		for k := range m.Locations {
+			keysForLocations = append(keysForLocations, string(k))
+		}
+		github_com_gogo_protobuf_sortkeys.Strings(keysForLocations)
+		for iNdEx := len(keysForLocations) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.Locations[string(keysForLocations[iNdEx])]
+			baseI := i
+			if v != nil {
+				{
+					size, err := v.MarshalToSizedBuffer(dAtA[:i])
+					if err != nil {
+						return 0, err
+					}
+					i -= size
+					i = encodeVarintOps(dAtA, i, uint64(size))
+				}
+				i--
+				dAtA[i] = 0x12
+			}
+			i -= len(keysForLocations[iNdEx])
+			copy(dAtA[i:], keysForLocations[iNdEx])
+			i = encodeVarintOps(dAtA, i, uint64(len(keysForLocations[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintOps(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *BuildOp) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *Locations) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *Locations) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Locations) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.Builder != 0 {
-		n += 1 + sovOps(uint64(m.Builder))
-	}
-	if len(m.Inputs) > 0 {
-		for k, v := range m.Inputs {
-			_ = k
-			_ = v
-			l = 0
-			if v != nil {
-				l = v.Size()
-				l += 1 + sovOps(uint64(l))
+	if len(m.Locations) > 0 {
+		for iNdEx := len(m.Locations) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Locations[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintOps(dAtA, i, uint64(size))
 			}
-			mapEntrySize := 1 + len(k) + sovOps(uint64(len(k))) + l
-			n += mapEntrySize + 1 + sovOps(uint64(mapEntrySize))
-		}
-	}
-	if m.Def != nil {
-		l = m.Def.Size()
-		n += 1 + l + sovOps(uint64(l))
-	}
-	if len(m.Attrs) > 0 {
-		for k, v := range m.Attrs {
-			_ = k
-			_ = v
-			mapEntrySize := 1 + len(k) + sovOps(uint64(len(k))) + 1 + len(v) + sovOps(uint64(len(v)))
-			n += mapEntrySize + 1 + sovOps(uint64(mapEntrySize))
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *BuildInput) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Input != 0 {
-		n += 1 + sovOps(uint64(m.Input))
+func (m *SourceInfo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *OpMetadata) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *SourceInfo) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SourceInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.IgnoreCache {
-		n += 2
-	}
-	if len(m.Description) > 0 {
-		for k, v := range m.Description {
-			_ = k
-			_ = v
-			mapEntrySize := 1 + len(k) + sovOps(uint64(len(k))) + 1 + len(v) + sovOps(uint64(len(v)))
-			n += mapEntrySize + 1 + sovOps(uint64(mapEntrySize))
+	if m.Definition != nil {
+		{
+			size, err := m.Definition.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
 		}
+		i--
+		dAtA[i] = 0x1a
 	}
-	if m.ExportCache != nil {
-		l = m.ExportCache.Size()
-		n += 1 + l + sovOps(uint64(l))
+	if len(m.Data) > 0 {
+		i -= len(m.Data)
+		copy(dAtA[i:], m.Data)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Data)))
+		i--
+		dAtA[i] = 0x12
 	}
-	if len(m.Caps) > 0 {
-		for k, v := range m.Caps {
-			_ = k
-			_ = v
-			mapEntrySize := 1 + len(k) + sovOps(uint64(len(k))) + 1 + 1
-			n += mapEntrySize + 1 + sovOps(uint64(mapEntrySize))
-		}
+	if len(m.Filename) > 0 {
+		i -= len(m.Filename)
+		copy(dAtA[i:], m.Filename)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Filename)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *Source) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *Location) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *Location) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Location) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.Locations) > 0 {
-		for k, v := range m.Locations {
-			_ = k
-			_ = v
-			l = 0
-			if v != nil {
-				l = v.Size()
-				l += 1 + sovOps(uint64(l))
+	if len(m.Ranges) > 0 {
+		for iNdEx := len(m.Ranges) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Ranges[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintOps(dAtA, i, uint64(size))
 			}
-			mapEntrySize := 1 + len(k) + sovOps(uint64(len(k))) + l
-			n += mapEntrySize + 1 + sovOps(uint64(mapEntrySize))
+			i--
+			dAtA[i] = 0x12
 		}
 	}
-	if len(m.Infos) > 0 {
-		for _, e := range m.Infos {
-			l = e.Size()
-			n += 1 + l + sovOps(uint64(l))
-		}
+	if m.SourceIndex != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.SourceIndex))
+		i--
+		dAtA[i] = 0x8
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *Locations) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.Locations) > 0 {
-		for _, e := range m.Locations {
-			l = e.Size()
-			n += 1 + l + sovOps(uint64(l))
-		}
+func (m *Range) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *SourceInfo) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Filename)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
-	}
-	l = len(m.Data)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
-	}
-	if m.Definition != nil {
-		l = m.Definition.Size()
-		n += 1 + l + sovOps(uint64(l))
-	}
-	return n
+func (m *Range) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Location) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *Range) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.SourceIndex != 0 {
-		n += 1 + sovOps(uint64(m.SourceIndex))
+	{
+		size, err := m.End.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintOps(dAtA, i, uint64(size))
 	}
-	if len(m.Ranges) > 0 {
-		for _, e := range m.Ranges {
-			l = e.Size()
-			n += 1 + l + sovOps(uint64(l))
+	i--
+	dAtA[i] = 0x12
+	{
+		size, err := m.Start.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
 		}
+		i -= size
+		i = encodeVarintOps(dAtA, i, uint64(size))
 	}
-	return n
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
 }
 
-func (m *Range) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *Position) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	var l int
-	_ = l
-	l = m.Start.Size()
-	n += 1 + l + sovOps(uint64(l))
-	l = m.End.Size()
-	n += 1 + l + sovOps(uint64(l))
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *Position) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *Position) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Position) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.Line != 0 {
-		n += 1 + sovOps(uint64(m.Line))
-	}
 	if m.Character != 0 {
-		n += 1 + sovOps(uint64(m.Character))
+		i = encodeVarintOps(dAtA, i, uint64(m.Character))
+		i--
+		dAtA[i] = 0x10
 	}
-	return n
+	if m.Line != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Line))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *ExportCache) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *ExportCache) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *ExportCache) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExportCache) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
 	if m.Value {
-		n += 2
+		i--
+		if m.Value {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x8
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *ProxyEnv) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *ProxyEnv) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *ProxyEnv) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ProxyEnv) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.HttpProxy)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+	if len(m.AllProxy) > 0 {
+		i -= len(m.AllProxy)
+		copy(dAtA[i:], m.AllProxy)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.AllProxy)))
+		i--
+		dAtA[i] = 0x2a
 	}
-	l = len(m.HttpsProxy)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+	if len(m.NoProxy) > 0 {
+		i -= len(m.NoProxy)
+		copy(dAtA[i:], m.NoProxy)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.NoProxy)))
+		i--
+		dAtA[i] = 0x22
 	}
-	l = len(m.FtpProxy)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+	if len(m.FtpProxy) > 0 {
+		i -= len(m.FtpProxy)
+		copy(dAtA[i:], m.FtpProxy)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.FtpProxy)))
+		i--
+		dAtA[i] = 0x1a
 	}
-	l = len(m.NoProxy)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+	if len(m.HttpsProxy) > 0 {
+		i -= len(m.HttpsProxy)
+		copy(dAtA[i:], m.HttpsProxy)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.HttpsProxy)))
+		i--
+		dAtA[i] = 0x12
 	}
-	l = len(m.AllProxy)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+	if len(m.HttpProxy) > 0 {
+		i -= len(m.HttpProxy)
+		copy(dAtA[i:], m.HttpProxy)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.HttpProxy)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *WorkerConstraints) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *WorkerConstraints) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *WorkerConstraints) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *WorkerConstraints) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
 	if len(m.Filter) > 0 {
-		for _, s := range m.Filter {
-			l = len(s)
-			n += 1 + l + sovOps(uint64(l))
+		for iNdEx := len(m.Filter) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Filter[iNdEx])
+			copy(dAtA[i:], m.Filter[iNdEx])
+			i = encodeVarintOps(dAtA, i, uint64(len(m.Filter[iNdEx])))
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *Definition) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *Definition) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *Definition) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Definition) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.Def) > 0 {
-		for _, b := range m.Def {
-			l = len(b)
-			n += 1 + l + sovOps(uint64(l))
+	if m.Source != nil {
+		{
+			size, err := m.Source.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
 		}
-	}
+		i--
+		dAtA[i] = 0x1a
+	}
 	if len(m.Metadata) > 0 {
-		for k, v := range m.Metadata {
-			_ = k
-			_ = v
-			l = v.Size()
-			mapEntrySize := 1 + len(k) + sovOps(uint64(len(k))) + 1 + l + sovOps(uint64(l))
-			n += mapEntrySize + 1 + sovOps(uint64(mapEntrySize))
+		keysForMetadata := make([]string, 0, len(m.Metadata))
+		for k := range m.Metadata {
+			keysForMetadata = append(keysForMetadata, string(k))
+		}
+		github_com_gogo_protobuf_sortkeys.Strings(keysForMetadata)
+		for iNdEx := len(keysForMetadata) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.Metadata[github_com_opencontainers_go_digest.Digest(keysForMetadata[iNdEx])]
+			baseI := i
+			{
+				size, err := (&v).MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintOps(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+			i -= len(keysForMetadata[iNdEx])
+			copy(dAtA[i:], keysForMetadata[iNdEx])
+			i = encodeVarintOps(dAtA, i, uint64(len(keysForMetadata[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintOps(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x12
 		}
 	}
-	if m.Source != nil {
-		l = m.Source.Size()
-		n += 1 + l + sovOps(uint64(l))
+	if len(m.Def) > 0 {
+		for iNdEx := len(m.Def) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Def[iNdEx])
+			copy(dAtA[i:], m.Def[iNdEx])
+			i = encodeVarintOps(dAtA, i, uint64(len(m.Def[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *HostIP) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *HostIP) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *HostIP) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *HostIP) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Host)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+	if len(m.IP) > 0 {
+		i -= len(m.IP)
+		copy(dAtA[i:], m.IP)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.IP)))
+		i--
+		dAtA[i] = 0x12
 	}
-	l = len(m.IP)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+	if len(m.Host) > 0 {
+		i -= len(m.Host)
+		copy(dAtA[i:], m.Host)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Host)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *FileOp) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *FileOp) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *FileOp) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FileOp) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
 	if len(m.Actions) > 0 {
-		for _, e := range m.Actions {
-			l = e.Size()
-			n += 1 + l + sovOps(uint64(l))
+		for iNdEx := len(m.Actions) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Actions[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintOps(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
 		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *FileAction) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *FileAction) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *FileAction) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FileAction) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.Input != 0 {
-		n += 1 + sovOps(uint64(m.Input))
-	}
-	if m.SecondaryInput != 0 {
-		n += 1 + sovOps(uint64(m.SecondaryInput))
+	if m.Action != nil {
+		{
+			size := m.Action.Size()
+			i -= size
+			if _, err := m.Action.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+		}
 	}
 	if m.Output != 0 {
-		n += 1 + sovOps(uint64(m.Output))
+		i = encodeVarintOps(dAtA, i, uint64(m.Output))
+		i--
+		dAtA[i] = 0x18
 	}
-	if m.Action != nil {
-		n += m.Action.Size()
+	if m.SecondaryInput != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.SecondaryInput))
+		i--
+		dAtA[i] = 0x10
 	}
-	return n
+	if m.Input != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Input))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *FileAction_Copy) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
+func (m *FileAction_Copy) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FileAction_Copy) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	if m.Copy != nil {
-		l = m.Copy.Size()
-		n += 1 + l + sovOps(uint64(l))
+		{
+			size, err := m.Copy.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
 	}
-	return n
+	return len(dAtA) - i, nil
 }
-func (m *FileAction_Mkfile) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
+func (m *FileAction_Mkfile) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FileAction_Mkfile) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	if m.Mkfile != nil {
-		l = m.Mkfile.Size()
-		n += 1 + l + sovOps(uint64(l))
+		{
+			size, err := m.Mkfile.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
 	}
-	return n
+	return len(dAtA) - i, nil
 }
-func (m *FileAction_Mkdir) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
+func (m *FileAction_Mkdir) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FileAction_Mkdir) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	if m.Mkdir != nil {
-		l = m.Mkdir.Size()
-		n += 1 + l + sovOps(uint64(l))
+		{
+			size, err := m.Mkdir.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x32
 	}
-	return n
+	return len(dAtA) - i, nil
 }
-func (m *FileAction_Rm) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
+func (m *FileAction_Rm) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FileAction_Rm) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
 	if m.Rm != nil {
-		l = m.Rm.Size()
-		n += 1 + l + sovOps(uint64(l))
+		{
+			size, err := m.Rm.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x3a
 	}
-	return n
+	return len(dAtA) - i, nil
 }
-func (m *FileActionCopy) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *FileActionCopy) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *FileActionCopy) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FileActionCopy) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Src)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
-	}
-	l = len(m.Dest)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
-	}
-	if m.Owner != nil {
-		l = m.Owner.Size()
-		n += 1 + l + sovOps(uint64(l))
-	}
-	if m.Mode != 0 {
-		n += 1 + sovOps(uint64(m.Mode))
-	}
-	if m.FollowSymlink {
-		n += 2
+	if m.DirMode != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.DirMode))
+		i--
+		dAtA[i] = 0x78
 	}
-	if m.DirCopyContents {
-		n += 2
+	if m.TimestampNow {
+		i--
+		if m.TimestampNow {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x70
 	}
-	if m.AttemptUnpackDockerCompatibility {
-		n += 2
+	if len(m.ExcludePatterns) > 0 {
+		for iNdEx := len(m.ExcludePatterns) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ExcludePatterns[iNdEx])
+			copy(dAtA[i:], m.ExcludePatterns[iNdEx])
+			i = encodeVarintOps(dAtA, i, uint64(len(m.ExcludePatterns[iNdEx])))
+			i--
+			dAtA[i] = 0x6a
+		}
 	}
-	if m.CreateDestPath {
-		n += 2
+	if len(m.IncludePatterns) > 0 {
+		for iNdEx := len(m.IncludePatterns) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.IncludePatterns[iNdEx])
+			copy(dAtA[i:], m.IncludePatterns[iNdEx])
+			i = encodeVarintOps(dAtA, i, uint64(len(m.IncludePatterns[iNdEx])))
+			i--
+			dAtA[i] = 0x62
+		}
 	}
-	if m.AllowWildcard {
-		n += 2
+	if m.Timestamp != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Timestamp))
+		i--
+		dAtA[i] = 0x58
 	}
 	if m.AllowEmptyWildcard {
-		n += 2
+		i--
+		if m.AllowEmptyWildcard {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x50
 	}
-	if m.Timestamp != 0 {
-		n += 1 + sovOps(uint64(m.Timestamp))
+	if m.AllowWildcard {
+		i--
+		if m.AllowWildcard {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x48
 	}
-	if len(m.IncludePatterns) > 0 {
-		for _, s := range m.IncludePatterns {
-			l = len(s)
-			n += 1 + l + sovOps(uint64(l))
+	if m.CreateDestPath {
+		i--
+		if m.CreateDestPath {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
 		}
+		i--
+		dAtA[i] = 0x40
 	}
-	if len(m.ExcludePatterns) > 0 {
-		for _, s := range m.ExcludePatterns {
-			l = len(s)
-			n += 1 + l + sovOps(uint64(l))
+	if m.AttemptUnpackDockerCompatibility {
+		i--
+		if m.AttemptUnpackDockerCompatibility {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
 		}
+		i--
+		dAtA[i] = 0x38
 	}
-	return n
-}
-
-func (m *FileActionMkFile) Size() (n int) {
-	if m == nil {
-		return 0
+	if m.DirCopyContents {
+		i--
+		if m.DirCopyContents {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
 	}
-	var l int
-	_ = l
-	l = len(m.Path)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+	if m.FollowSymlink {
+		i--
+		if m.FollowSymlink {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
 	}
 	if m.Mode != 0 {
-		n += 1 + sovOps(uint64(m.Mode))
-	}
-	l = len(m.Data)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+		i = encodeVarintOps(dAtA, i, uint64(m.Mode))
+		i--
+		dAtA[i] = 0x20
 	}
 	if m.Owner != nil {
-		l = m.Owner.Size()
-		n += 1 + l + sovOps(uint64(l))
+		{
+			size, err := m.Owner.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
 	}
-	if m.Timestamp != 0 {
-		n += 1 + sovOps(uint64(m.Timestamp))
+	if len(m.Dest) > 0 {
+		i -= len(m.Dest)
+		copy(dAtA[i:], m.Dest)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Dest)))
+		i--
+		dAtA[i] = 0x12
 	}
-	return n
+	if len(m.Src) > 0 {
+		i -= len(m.Src)
+		copy(dAtA[i:], m.Src)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Src)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *FileActionMkDir) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *FileActionMkFile) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *FileActionMkFile) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FileActionMkFile) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Path)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
-	}
-	if m.Mode != 0 {
-		n += 1 + sovOps(uint64(m.Mode))
-	}
-	if m.MakeParents {
-		n += 2
+	if m.Timestamp != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Timestamp))
+		i--
+		dAtA[i] = 0x28
 	}
 	if m.Owner != nil {
-		l = m.Owner.Size()
-		n += 1 + l + sovOps(uint64(l))
-	}
-	if m.Timestamp != 0 {
-		n += 1 + sovOps(uint64(m.Timestamp))
+		{
+			size, err := m.Owner.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
 	}
-	return n
+	if len(m.Data) > 0 {
+		i -= len(m.Data)
+		copy(dAtA[i:], m.Data)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Data)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Mode != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Mode))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Path) > 0 {
+		i -= len(m.Path)
+		copy(dAtA[i:], m.Path)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Path)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *FileActionRm) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *FileActionMkDir) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *FileActionMkDir) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FileActionMkDir) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Path)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+	if m.Timestamp != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Timestamp))
+		i--
+		dAtA[i] = 0x28
 	}
-	if m.AllowNotFound {
-		n += 2
+	if m.Owner != nil {
+		{
+			size, err := m.Owner.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
 	}
-	if m.AllowWildcard {
-		n += 2
+	if m.MakeParents {
+		i--
+		if m.MakeParents {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
 	}
-	return n
+	if m.Mode != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Mode))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Path) > 0 {
+		i -= len(m.Path)
+		copy(dAtA[i:], m.Path)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Path)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *ChownOpt) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *FileActionRm) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *FileActionRm) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FileActionRm) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.User != nil {
-		l = m.User.Size()
-		n += 1 + l + sovOps(uint64(l))
+	if m.AllowWildcard {
+		i--
+		if m.AllowWildcard {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
 	}
-	if m.Group != nil {
-		l = m.Group.Size()
-		n += 1 + l + sovOps(uint64(l))
+	if m.AllowNotFound {
+		i--
+		if m.AllowNotFound {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
 	}
-	return n
+	if len(m.Path) > 0 {
+		i -= len(m.Path)
+		copy(dAtA[i:], m.Path)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Path)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *UserOpt) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *ChownOpt) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *ChownOpt) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ChownOpt) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
+	if m.Group != nil {
+		{
+			size, err := m.Group.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
 	if m.User != nil {
-		n += m.User.Size()
+		{
+			size, err := m.User.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *UserOpt_ByName) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *UserOpt) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *UserOpt) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UserOpt) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.ByName != nil {
-		l = m.ByName.Size()
-		n += 1 + l + sovOps(uint64(l))
+	if m.User != nil {
+		{
+			size := m.User.Size()
+			i -= size
+			if _, err := m.User.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
-func (m *UserOpt_ByID) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	n += 1 + sovOps(uint64(m.ByID))
-	return n
+
+func (m *UserOpt_ByName) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
-func (m *NamedUserOpt) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+
+func (m *UserOpt_ByName) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.ByName != nil {
+		{
+			size, err := m.ByName.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOps(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
 	}
-	if m.Input != 0 {
-		n += 1 + sovOps(uint64(m.Input))
+	return len(dAtA) - i, nil
+}
+func (m *UserOpt_ByID) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UserOpt_ByID) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	i = encodeVarintOps(dAtA, i, uint64(m.ByID))
+	i--
+	dAtA[i] = 0x10
+	return len(dAtA) - i, nil
+}
+func (m *NamedUserOpt) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NamedUserOpt) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NamedUserOpt) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Input != 0 {
+		i = encodeVarintOps(dAtA, i, uint64(m.Input))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintOps(dAtA []byte, offset int, v uint64) int {
+	offset -= sovOps(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *Op) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Inputs) > 0 {
+		for _, e := range m.Inputs {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	if m.Op != nil {
+		n += m.Op.Size()
+	}
+	if m.Platform != nil {
+		l = m.Platform.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Constraints != nil {
+		l = m.Constraints.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+
+func (m *Op_Exec) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Exec != nil {
+		l = m.Exec.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+func (m *Op_Source) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Source != nil {
+		l = m.Source.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+func (m *Op_File) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.File != nil {
+		l = m.File.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+func (m *Op_Build) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Build != nil {
+		l = m.Build.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+func (m *Platform) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Architecture)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.OS)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.Variant)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.OSVersion)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if len(m.OSFeatures) > 0 {
+		for _, s := range m.OSFeatures {
+			l = len(s)
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Input) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Digest)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Index != 0 {
+		n += 1 + sovOps(uint64(m.Index))
+	}
+	return n
+}
+
+func (m *ExecOp) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Meta != nil {
+		l = m.Meta.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if len(m.Mounts) > 0 {
+		for _, e := range m.Mounts {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	if m.Network != 0 {
+		n += 1 + sovOps(uint64(m.Network))
+	}
+	if m.Security != 0 {
+		n += 1 + sovOps(uint64(m.Security))
+	}
+	if len(m.AllowedExitCodes) > 0 {
+		l = 0
+		for _, e := range m.AllowedExitCodes {
+			l += sovOps(uint64(e))
+		}
+		n += 1 + sovOps(uint64(l)) + l
+	}
+	if m.Seccomp != nil {
+		l = m.Seccomp.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if len(m.Devices) > 0 {
+		for _, e := range m.Devices {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	if len(m.Secretenv) > 0 {
+		for _, e := range m.Secretenv {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	if len(m.After) > 0 {
+		l = 0
+		for _, e := range m.After {
+			l += sovOps(uint64(e))
+		}
+		n += 1 + sovOps(uint64(l)) + l
+	}
+	if m.Resources != nil {
+		l = m.Resources.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.SharedPID != nil {
+		l = m.SharedPID.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.ApparmorProfile)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if len(m.IgnoreForCache) > 0 {
+		for _, s := range m.IgnoreForCache {
+			l = len(s)
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	if m.Retry != nil {
+		l = m.Retry.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.UserNSMapping != nil {
+		l = m.UserNSMapping.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if len(m.Sysctls) > 0 {
+		for k, v := range m.Sysctls {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovOps(uint64(len(k))) + 1 + len(v) + sovOps(uint64(len(v)))
+			n += mapEntrySize + 2 + sovOps(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *UserNSMapping) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.UidMappings) > 0 {
+		for _, e := range m.UidMappings {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	if len(m.GidMappings) > 0 {
+		for _, e := range m.GidMappings {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *IDMap) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ContainerID != 0 {
+		n += 1 + sovOps(uint64(m.ContainerID))
+	}
+	if m.HostID != 0 {
+		n += 1 + sovOps(uint64(m.HostID))
+	}
+	if m.Size_ != 0 {
+		n += 1 + sovOps(uint64(m.Size_))
+	}
+	return n
+}
+
+func (m *ExecRetry) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Attempts != 0 {
+		n += 1 + sovOps(uint64(m.Attempts))
+	}
+	if len(m.ExitCodes) > 0 {
+		l = 0
+		for _, e := range m.ExitCodes {
+			l += sovOps(uint64(e))
+		}
+		n += 1 + sovOps(uint64(l)) + l
+	}
+	return n
+}
+
+func (m *SharedPID) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Meta != nil {
+		l = m.Meta.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if len(m.Mounts) > 0 {
+		for _, e := range m.Mounts {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	if len(m.Secretenv) > 0 {
+		for _, e := range m.Secretenv {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Resources) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Memory != 0 {
+		n += 1 + sovOps(uint64(m.Memory))
+	}
+	if m.CpuQuota != 0 {
+		n += 1 + sovOps(uint64(m.CpuQuota))
+	}
+	if m.CpuPeriod != 0 {
+		n += 1 + sovOps(uint64(m.CpuPeriod))
+	}
+	return n
+}
+
+func (m *SecretEnv) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ID)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Optional {
+		n += 2
+	}
+	return n
+}
+
+func (m *Device) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.Permissions)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+
+func (m *SeccompOpt) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Profile)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Unconfined {
+		n += 2
+	}
+	return n
+}
+
+func (m *Meta) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Args) > 0 {
+		for _, s := range m.Args {
+			l = len(s)
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	if len(m.Env) > 0 {
+		for _, s := range m.Env {
+			l = len(s)
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	l = len(m.Cwd)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.User)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.ProxyEnv != nil {
+		l = m.ProxyEnv.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if len(m.ExtraHosts) > 0 {
+		for _, e := range m.ExtraHosts {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	l = len(m.Hostname)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.Entrypoint)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if len(m.CacheIgnoreEnv) > 0 {
+		for _, s := range m.CacheIgnoreEnv {
+			l = len(s)
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	l = len(m.Umask)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if len(m.PassthroughEnv) > 0 {
+		for _, s := range m.PassthroughEnv {
+			l = len(s)
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	l = len(m.RedirectStdout)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.RedirectStderr)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Timezone != nil {
+		l = m.Timezone.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+
+func (m *Timezone) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.MountZoneinfo {
+		n += 2
+	}
+	return n
+}
+
+func (m *Mount) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Input != 0 {
+		n += 1 + sovOps(uint64(m.Input))
+	}
+	l = len(m.Selector)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.Dest)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Output != 0 {
+		n += 1 + sovOps(uint64(m.Output))
+	}
+	if m.Readonly {
+		n += 2
+	}
+	if m.MountType != 0 {
+		n += 1 + sovOps(uint64(m.MountType))
+	}
+	if m.CacheOpt != nil {
+		l = m.CacheOpt.Size()
+		n += 2 + l + sovOps(uint64(l))
+	}
+	if m.SecretOpt != nil {
+		l = m.SecretOpt.Size()
+		n += 2 + l + sovOps(uint64(l))
+	}
+	if m.SSHOpt != nil {
+		l = m.SSHOpt.Size()
+		n += 2 + l + sovOps(uint64(l))
+	}
+	l = len(m.ResultID)
+	if l > 0 {
+		n += 2 + l + sovOps(uint64(l))
+	}
+	if m.HostPathOpt != nil {
+		l = m.HostPathOpt.Size()
+		n += 2 + l + sovOps(uint64(l))
+	}
+	return n
+}
+
+func (m *CacheOpt) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ID)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Sharing != 0 {
+		n += 1 + sovOps(uint64(m.Sharing))
+	}
+	return n
+}
+
+func (m *SecretOpt) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ID)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Uid != 0 {
+		n += 1 + sovOps(uint64(m.Uid))
+	}
+	if m.Gid != 0 {
+		n += 1 + sovOps(uint64(m.Gid))
+	}
+	if m.Mode != 0 {
+		n += 1 + sovOps(uint64(m.Mode))
+	}
+	if m.Optional {
+		n += 2
+	}
+	return n
+}
+
+func (m *SSHOpt) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ID)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Uid != 0 {
+		n += 1 + sovOps(uint64(m.Uid))
+	}
+	if m.Gid != 0 {
+		n += 1 + sovOps(uint64(m.Gid))
+	}
+	if m.Mode != 0 {
+		n += 1 + sovOps(uint64(m.Mode))
+	}
+	if m.Optional {
+		n += 2
+	}
+	return n
+}
+
+func (m *HostPathOpt) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+
+func (m *SourceOp) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Identifier)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if len(m.Attrs) > 0 {
+		for k, v := range m.Attrs {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovOps(uint64(len(k))) + 1 + len(v) + sovOps(uint64(len(v)))
+			n += mapEntrySize + 1 + sovOps(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *BuildOp) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Builder != 0 {
+		n += 1 + sovOps(uint64(m.Builder))
+	}
+	if len(m.Inputs) > 0 {
+		for k, v := range m.Inputs {
+			_ = k
+			_ = v
+			l = 0
+			if v != nil {
+				l = v.Size()
+				l += 1 + sovOps(uint64(l))
+			}
+			mapEntrySize := 1 + len(k) + sovOps(uint64(len(k))) + l
+			n += mapEntrySize + 1 + sovOps(uint64(mapEntrySize))
+		}
+	}
+	if m.Def != nil {
+		l = m.Def.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if len(m.Attrs) > 0 {
+		for k, v := range m.Attrs {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovOps(uint64(len(k))) + 1 + len(v) + sovOps(uint64(len(v)))
+			n += mapEntrySize + 1 + sovOps(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *BuildInput) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Input != 0 {
+		n += 1 + sovOps(uint64(m.Input))
+	}
+	return n
+}
+
+func (m *OpMetadata) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.IgnoreCache {
+		n += 2
+	}
+	if len(m.Description) > 0 {
+		for k, v := range m.Description {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovOps(uint64(len(k))) + 1 + len(v) + sovOps(uint64(len(v)))
+			n += mapEntrySize + 1 + sovOps(uint64(mapEntrySize))
+		}
+	}
+	if m.ExportCache != nil {
+		l = m.ExportCache.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if len(m.Caps) > 0 {
+		for k, v := range m.Caps {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovOps(uint64(len(k))) + 1 + 1
+			n += mapEntrySize + 1 + sovOps(uint64(mapEntrySize))
+		}
+	}
+	if m.ProgressGroup != nil {
+		l = m.ProgressGroup.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+
+func (m *ProgressGroup) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+
+func (m *Source) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Locations) > 0 {
+		for k, v := range m.Locations {
+			_ = k
+			_ = v
+			l = 0
+			if v != nil {
+				l = v.Size()
+				l += 1 + sovOps(uint64(l))
+			}
+			mapEntrySize := 1 + len(k) + sovOps(uint64(len(k))) + l
+			n += mapEntrySize + 1 + sovOps(uint64(mapEntrySize))
+		}
+	}
+	if len(m.Infos) > 0 {
+		for _, e := range m.Infos {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Locations) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Locations) > 0 {
+		for _, e := range m.Locations {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SourceInfo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Filename)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Definition != nil {
+		l = m.Definition.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+
+func (m *Location) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.SourceIndex != 0 {
+		n += 1 + sovOps(uint64(m.SourceIndex))
+	}
+	if len(m.Ranges) > 0 {
+		for _, e := range m.Ranges {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Range) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Start.Size()
+	n += 1 + l + sovOps(uint64(l))
+	l = m.End.Size()
+	n += 1 + l + sovOps(uint64(l))
+	return n
+}
+
+func (m *Position) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Line != 0 {
+		n += 1 + sovOps(uint64(m.Line))
+	}
+	if m.Character != 0 {
+		n += 1 + sovOps(uint64(m.Character))
+	}
+	return n
+}
+
+func (m *ExportCache) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Value {
+		n += 2
+	}
+	return n
+}
+
+func (m *ProxyEnv) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.HttpProxy)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.HttpsProxy)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.FtpProxy)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.NoProxy)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.AllProxy)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+
+func (m *WorkerConstraints) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Filter) > 0 {
+		for _, s := range m.Filter {
+			l = len(s)
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Definition) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Def) > 0 {
+		for _, b := range m.Def {
+			l = len(b)
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	if len(m.Metadata) > 0 {
+		for k, v := range m.Metadata {
+			_ = k
+			_ = v
+			l = v.Size()
+			mapEntrySize := 1 + len(k) + sovOps(uint64(len(k))) + 1 + l + sovOps(uint64(l))
+			n += mapEntrySize + 1 + sovOps(uint64(mapEntrySize))
+		}
+	}
+	if m.Source != nil {
+		l = m.Source.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+
+func (m *HostIP) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Host)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.IP)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+
+func (m *FileOp) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Actions) > 0 {
+		for _, e := range m.Actions {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *FileAction) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Input != 0 {
+		n += 1 + sovOps(uint64(m.Input))
+	}
+	if m.SecondaryInput != 0 {
+		n += 1 + sovOps(uint64(m.SecondaryInput))
+	}
+	if m.Output != 0 {
+		n += 1 + sovOps(uint64(m.Output))
+	}
+	if m.Action != nil {
+		n += m.Action.Size()
+	}
+	return n
+}
+
+func (m *FileAction_Copy) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Copy != nil {
+		l = m.Copy.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+func (m *FileAction_Mkfile) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Mkfile != nil {
+		l = m.Mkfile.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+func (m *FileAction_Mkdir) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Mkdir != nil {
+		l = m.Mkdir.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+func (m *FileAction_Rm) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Rm != nil {
+		l = m.Rm.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+func (m *FileActionCopy) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Src)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.Dest)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Owner != nil {
+		l = m.Owner.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Mode != 0 {
+		n += 1 + sovOps(uint64(m.Mode))
+	}
+	if m.FollowSymlink {
+		n += 2
+	}
+	if m.DirCopyContents {
+		n += 2
+	}
+	if m.AttemptUnpackDockerCompatibility {
+		n += 2
+	}
+	if m.CreateDestPath {
+		n += 2
+	}
+	if m.AllowWildcard {
+		n += 2
+	}
+	if m.AllowEmptyWildcard {
+		n += 2
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovOps(uint64(m.Timestamp))
+	}
+	if len(m.IncludePatterns) > 0 {
+		for _, s := range m.IncludePatterns {
+			l = len(s)
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	if len(m.ExcludePatterns) > 0 {
+		for _, s := range m.ExcludePatterns {
+			l = len(s)
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	if m.TimestampNow {
+		n += 2
+	}
+	if m.DirMode != 0 {
+		n += 1 + sovOps(uint64(m.DirMode))
+	}
+	return n
+}
+
+func (m *FileActionMkFile) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Mode != 0 {
+		n += 1 + sovOps(uint64(m.Mode))
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Owner != nil {
+		l = m.Owner.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovOps(uint64(m.Timestamp))
+	}
+	return n
+}
+
+func (m *FileActionMkDir) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Mode != 0 {
+		n += 1 + sovOps(uint64(m.Mode))
+	}
+	if m.MakeParents {
+		n += 2
+	}
+	if m.Owner != nil {
+		l = m.Owner.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovOps(uint64(m.Timestamp))
+	}
+	return n
+}
+
+func (m *FileActionRm) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.AllowNotFound {
+		n += 2
+	}
+	if m.AllowWildcard {
+		n += 2
+	}
+	return n
+}
+
+func (m *ChownOpt) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.User != nil {
+		l = m.User.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Group != nil {
+		l = m.Group.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+
+func (m *UserOpt) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.User != nil {
+		n += m.User.Size()
+	}
+	return n
+}
+
+func (m *UserOpt_ByName) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ByName != nil {
+		l = m.ByName.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+func (m *UserOpt_ByID) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 1 + sovOps(uint64(m.ByID))
+	return n
+}
+func (m *NamedUserOpt) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Input != 0 {
+		n += 1 + sovOps(uint64(m.Input))
+	}
+	return n
+}
+
+func sovOps(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozOps(x uint64) (n int) {
+	return sovOps(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *Op) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Op: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Op: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Inputs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Inputs = append(m.Inputs, &Input{})
+			if err := m.Inputs[len(m.Inputs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exec", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &ExecOp{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Op = &Op_Exec{v}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Source", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &SourceOp{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Op = &Op_Source{v}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &FileOp{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Op = &Op_File{v}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Build", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &BuildOp{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Op = &Op_Build{v}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Platform", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Platform == nil {
+				m.Platform = &Platform{}
+			}
+			if err := m.Platform.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Constraints", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Constraints == nil {
+				m.Constraints = &WorkerConstraints{}
+			}
+			if err := m.Constraints.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Platform) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Platform: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Platform: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Architecture", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Architecture = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OS", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OS = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Variant", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Variant = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OSVersion", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OSVersion = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OSFeatures", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OSFeatures = append(m.OSFeatures, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Input) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Input: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Input: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Digest", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Digest = github_com_opencontainers_go_digest.Digest(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= OutputIndex(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ExecOp) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExecOp: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExecOp: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Meta", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Meta == nil {
+				m.Meta = &Meta{}
+			}
+			if err := m.Meta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mounts", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Mounts = append(m.Mounts, &Mount{})
+			if err := m.Mounts[len(m.Mounts)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Network", wireType)
+			}
+			m.Network = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Network |= NetMode(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Security", wireType)
+			}
+			m.Security = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Security |= SecurityMode(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType == 0 {
+				var v int32
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowOps
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= int32(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.AllowedExitCodes = append(m.AllowedExitCodes, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowOps
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthOps
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthOps
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.AllowedExitCodes) == 0 {
+					m.AllowedExitCodes = make([]int32, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v int32
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowOps
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= int32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.AllowedExitCodes = append(m.AllowedExitCodes, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field AllowedExitCodes", wireType)
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Seccomp", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Seccomp == nil {
+				m.Seccomp = &SeccompOpt{}
+			}
+			if err := m.Seccomp.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Devices", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Devices = append(m.Devices, &Device{})
+			if err := m.Devices[len(m.Devices)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Secretenv", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Secretenv = append(m.Secretenv, &SecretEnv{})
+			if err := m.Secretenv[len(m.Secretenv)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType == 0 {
+				var v InputIndex
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowOps
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= InputIndex(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.After = append(m.After, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowOps
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthOps
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthOps
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.After) == 0 {
+					m.After = make([]InputIndex, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v InputIndex
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowOps
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= InputIndex(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.After = append(m.After, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field After", wireType)
+			}
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Resources", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Resources == nil {
+				m.Resources = &Resources{}
+			}
+			if err := m.Resources.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SharedPID", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.SharedPID == nil {
+				m.SharedPID = &SharedPID{}
+			}
+			if err := m.SharedPID.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ApparmorProfile", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ApparmorProfile = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IgnoreForCache", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.IgnoreForCache = append(m.IgnoreForCache, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Retry", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Retry == nil {
+				m.Retry = &ExecRetry{}
+			}
+			if err := m.Retry.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UserNSMapping", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.UserNSMapping == nil {
+				m.UserNSMapping = &UserNSMapping{}
+			}
+			if err := m.UserNSMapping.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sysctls", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Sysctls == nil {
+				m.Sysctls = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowOps
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowOps
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthOps
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthOps
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowOps
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthOps
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthOps
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipOps(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthOps
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Sysctls[mapkey] = mapvalue
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *UserNSMapping) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UserNSMapping: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UserNSMapping: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UidMappings", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.UidMappings = append(m.UidMappings, &IDMap{})
+			if err := m.UidMappings[len(m.UidMappings)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GidMappings", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GidMappings = append(m.GidMappings, &IDMap{})
+			if err := m.GidMappings[len(m.GidMappings)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *IDMap) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: IDMap: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: IDMap: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContainerID", wireType)
+			}
+			m.ContainerID = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ContainerID |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HostID", wireType)
+			}
+			m.HostID = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HostID |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Size_", wireType)
+			}
+			m.Size_ = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Size_ |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
 	}
-	return n
-}
 
-func sovOps(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozOps(x uint64) (n int) {
-	return sovOps(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
 }
-func (m *Op) Unmarshal(dAtA []byte) error {
+func (m *ExecRetry) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5490,17 +8947,17 @@ func (m *Op) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Op: wiretype end group for non-group")
+			return fmt.Errorf("proto: ExecRetry: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Op: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ExecRetry: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Inputs", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attempts", wireType)
 			}
-			var msglen int
+			m.Attempts = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -5510,29 +8967,140 @@ func (m *Op) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.Attempts |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthOps
+		case 2:
+			if wireType == 0 {
+				var v int32
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowOps
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= int32(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.ExitCodes = append(m.ExitCodes, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowOps
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthOps
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthOps
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.ExitCodes) == 0 {
+					m.ExitCodes = make([]int32, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v int32
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowOps
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= int32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.ExitCodes = append(m.ExitCodes, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExitCodes", wireType)
 			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthOps
 			}
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Inputs = append(m.Inputs, &Input{})
-			if err := m.Inputs[len(m.Inputs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SharedPID) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
 			}
-			iNdEx = postIndex
-		case 2:
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SharedPID: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SharedPID: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Exec", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Meta", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -5559,15 +9127,16 @@ func (m *Op) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &ExecOp{}
-			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Meta == nil {
+				m.Meta = &Meta{}
+			}
+			if err := m.Meta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
-			m.Op = &Op_Exec{v}
 			iNdEx = postIndex
-		case 3:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Source", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Mounts", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -5594,15 +9163,14 @@ func (m *Op) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &SourceOp{}
-			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Mounts = append(m.Mounts, &Mount{})
+			if err := m.Mounts[len(m.Mounts)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
-			m.Op = &Op_Source{v}
 			iNdEx = postIndex
-		case 4:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Secretenv", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -5629,52 +9197,66 @@ func (m *Op) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &FileOp{}
-			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Secretenv = append(m.Secretenv, &SecretEnv{})
+			if err := m.Secretenv[len(m.Secretenv)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
-			m.Op = &Op_File{v}
 			iNdEx = postIndex
-		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Build", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowOps
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			if msglen < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthOps
 			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthOps
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
 			}
-			if postIndex > l {
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Resources) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &BuildOp{}
-			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
 			}
-			m.Op = &Op_Build{v}
-			iNdEx = postIndex
-		case 10:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Platform", wireType)
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Resources: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Resources: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Memory", wireType)
 			}
-			var msglen int
+			m.Memory = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -5684,33 +9266,16 @@ func (m *Op) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.Memory |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthOps
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthOps
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Platform == nil {
-				m.Platform = &Platform{}
-			}
-			if err := m.Platform.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 11:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Constraints", wireType)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CpuQuota", wireType)
 			}
-			var msglen int
+			m.CpuQuota = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -5720,28 +9285,30 @@ func (m *Op) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.CpuQuota |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthOps
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthOps
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Constraints == nil {
-				m.Constraints = &WorkerConstraints{}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CpuPeriod", wireType)
 			}
-			if err := m.Constraints.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.CpuPeriod = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CpuPeriod |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipOps(dAtA[iNdEx:])
@@ -5763,7 +9330,7 @@ func (m *Op) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Platform) Unmarshal(dAtA []byte) error {
+func (m *SecretEnv) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5786,15 +9353,15 @@ func (m *Platform) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Platform: wiretype end group for non-group")
+			return fmt.Errorf("proto: SecretEnv: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Platform: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SecretEnv: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Architecture", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5822,11 +9389,11 @@ func (m *Platform) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Architecture = string(dAtA[iNdEx:postIndex])
+			m.ID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OS", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5854,13 +9421,13 @@ func (m *Platform) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.OS = string(dAtA[iNdEx:postIndex])
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Variant", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Optional", wireType)
 			}
-			var stringLen uint64
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -5870,27 +9437,65 @@ func (m *Platform) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthOps
+			m.Optional = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthOps
 			}
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Variant = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 4:
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Device) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Device: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Device: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OSVersion", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5918,11 +9523,11 @@ func (m *Platform) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.OSVersion = string(dAtA[iNdEx:postIndex])
+			m.Path = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 5:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OSFeatures", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Permissions", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -5950,7 +9555,7 @@ func (m *Platform) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.OSFeatures = append(m.OSFeatures, string(dAtA[iNdEx:postIndex]))
+			m.Permissions = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -5973,7 +9578,7 @@ func (m *Platform) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Input) Unmarshal(dAtA []byte) error {
+func (m *SeccompOpt) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -5996,15 +9601,15 @@ func (m *Input) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Input: wiretype end group for non-group")
+			return fmt.Errorf("proto: SeccompOpt: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Input: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SeccompOpt: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Digest", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Profile", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -6032,13 +9637,13 @@ func (m *Input) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Digest = github_com_opencontainers_go_digest.Digest(dAtA[iNdEx:postIndex])
+			m.Profile = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Unconfined", wireType)
 			}
-			m.Index = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -6048,11 +9653,12 @@ func (m *Input) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Index |= OutputIndex(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			m.Unconfined = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipOps(dAtA[iNdEx:])
@@ -6074,7 +9680,7 @@ func (m *Input) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ExecOp) Unmarshal(dAtA []byte) error {
+func (m *Meta) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -6093,19 +9699,147 @@ func (m *ExecOp) Unmarshal(dAtA []byte) error {
 			if b < 0x80 {
 				break
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: ExecOp: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ExecOp: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Meta: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Meta: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Args", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Args = append(m.Args, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Env", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Env = append(m.Env, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cwd", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Cwd = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field User", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.User = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Meta", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ProxyEnv", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -6132,16 +9866,16 @@ func (m *ExecOp) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Meta == nil {
-				m.Meta = &Meta{}
+			if m.ProxyEnv == nil {
+				m.ProxyEnv = &ProxyEnv{}
 			}
-			if err := m.Meta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.ProxyEnv.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 2:
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Mounts", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ExtraHosts", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -6168,16 +9902,16 @@ func (m *ExecOp) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Mounts = append(m.Mounts, &Mount{})
-			if err := m.Mounts[len(m.Mounts)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.ExtraHosts = append(m.ExtraHosts, &HostIP{})
+			if err := m.ExtraHosts[len(m.ExtraHosts)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Network", wireType)
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hostname", wireType)
 			}
-			m.Network = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -6187,16 +9921,29 @@ func (m *ExecOp) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Network |= NetMode(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Security", wireType)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
 			}
-			m.Security = 0
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hostname = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Entrypoint", wireType)
+			}
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -6206,64 +9953,27 @@ func (m *ExecOp) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Security |= SecurityMode(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipOps(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthOps
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Meta) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowOps
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
 			}
-			if iNdEx >= l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Meta: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Meta: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			m.Entrypoint = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Args", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field CacheIgnoreEnv", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -6291,11 +10001,11 @@ func (m *Meta) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Args = append(m.Args, string(dAtA[iNdEx:postIndex]))
+			m.CacheIgnoreEnv = append(m.CacheIgnoreEnv, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
-		case 2:
+		case 10:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Env", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Umask", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -6323,11 +10033,11 @@ func (m *Meta) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Env = append(m.Env, string(dAtA[iNdEx:postIndex]))
+			m.Umask = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
+		case 11:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Cwd", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PassthroughEnv", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -6355,11 +10065,11 @@ func (m *Meta) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Cwd = string(dAtA[iNdEx:postIndex])
+			m.PassthroughEnv = append(m.PassthroughEnv, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
-		case 4:
+		case 12:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field User", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field RedirectStdout", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -6387,13 +10097,13 @@ func (m *Meta) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.User = string(dAtA[iNdEx:postIndex])
+			m.RedirectStdout = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 5:
+		case 13:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ProxyEnv", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field RedirectStderr", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -6403,31 +10113,27 @@ func (m *Meta) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthOps
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthOps
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.ProxyEnv == nil {
-				m.ProxyEnv = &ProxyEnv{}
-			}
-			if err := m.ProxyEnv.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.RedirectStderr = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 6:
+		case 14:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ExtraHosts", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Timezone", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -6454,14 +10160,66 @@ func (m *Meta) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ExtraHosts = append(m.ExtraHosts, &HostIP{})
-			if err := m.ExtraHosts[len(m.ExtraHosts)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Timezone == nil {
+				m.Timezone = &Timezone{}
+			}
+			if err := m.Timezone.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 7:
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Timezone) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Timezone: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Timezone: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Hostname", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -6489,8 +10247,28 @@ func (m *Meta) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Hostname = string(dAtA[iNdEx:postIndex])
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MountZoneinfo", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.MountZoneinfo = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipOps(dAtA[iNdEx:])
@@ -6820,7 +10598,43 @@ func (m *Mount) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ResultID = string(dAtA[iNdEx:postIndex])
+			m.ResultID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 24:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HostPathOpt", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.HostPathOpt == nil {
+				m.HostPathOpt = &HostPathOpt{}
+			}
+			if err := m.HostPathOpt.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -7262,6 +11076,88 @@ func (m *SSHOpt) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *HostPathOpt) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HostPathOpt: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HostPathOpt: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *SourceOp) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -8228,6 +12124,156 @@ func (m *OpMetadata) Unmarshal(dAtA []byte) error {
 			}
 			m.Caps[github_com_moby_buildkit_util_apicaps.CapID(mapkey)] = mapvalue
 			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProgressGroup", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ProgressGroup == nil {
+				m.ProgressGroup = &ProgressGroup{}
+			}
+			if err := m.ProgressGroup.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ProgressGroup) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ProgressGroup: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ProgressGroup: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOps
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipOps(dAtA[iNdEx:])
@@ -10410,6 +14456,45 @@ func (m *FileActionCopy) Unmarshal(dAtA []byte) error {
 			}
 			m.ExcludePatterns = append(m.ExcludePatterns, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimestampNow", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.TimestampNow = bool(v != 0)
+		case 15:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DirMode", wireType)
+			}
+			m.DirMode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DirMode |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipOps(dAtA[iNdEx:])
@@ -61,6 +61,15 @@ func (j *Job) Status(ctx context.Context, ch chan *client.SolveStatus) error {
 				v.Vertex = vtx.(digest.Digest)
 				v.Timestamp = p.Timestamp
 				ss.Logs = append(ss.Logs, &v)
+			case client.CacheMountStats:
+				vtx, ok := p.Meta("vertex")
+				if !ok {
+					logrus.Warnf("progress %s cache mount stats without vertex info", p.ID)
+					continue
+				}
+				v.Vertex = vtx.(digest.Digest)
+				v.Timestamp = p.Timestamp
+				ss.CacheMounts = append(ss.CacheMounts, &v)
 			}
 		}
 		select {
@@ -7,18 +7,22 @@ import (
 	"math"
 	"math/rand"
 	"os"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/identity"
 	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/util/grpcerrors"
 	digest "github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
 )
 
 func init() {
@@ -304,6 +308,58 @@ func TestSingleLevelCache(t *testing.T) {
 
 }
 
+func TestCacheNamespace(t *testing.T) {
+	t.Parallel()
+	ctx := context.TODO()
+
+	s := NewSolver(SolverOpt{
+		ResolveOpFunc: testOpResolver,
+	})
+	defer s.Close()
+
+	build := func(name, ns, value string) (*vertex, string) {
+		j, err := s.NewJob(identity.NewID())
+		require.NoError(t, err)
+		defer j.Discard()
+
+		g := Edge{
+			Vertex: vtx(vtxOpt{
+				name:           name,
+				cacheKeySeed:   "seed0",
+				value:          value,
+				cacheNamespace: ns,
+			}),
+		}
+		g.Vertex.(*vertex).setupCallCounters()
+
+		res, err := j.Build(ctx, g)
+		require.NoError(t, err)
+		return g.Vertex.(*vertex), unwrap(res)
+	}
+
+	v, res := build("v0", "", "result0")
+	require.Equal(t, "result0", res)
+	require.Equal(t, int64(1), *v.execCallCount)
+
+	// same cache key in a namespace doesn't match the global cache
+	v, res = build("v1", "ns1", "result1")
+	require.Equal(t, "result1", res)
+	require.Equal(t, int64(1), *v.execCallCount)
+
+	v, res = build("v2", "ns2", "result2")
+	require.Equal(t, "result2", res)
+	require.Equal(t, int64(1), *v.execCallCount)
+
+	// same namespace matches
+	v, res = build("v3", "ns1", "result3")
+	require.Equal(t, "result1", res)
+	require.Equal(t, int64(0), *v.execCallCount)
+
+	v, res = build("v4", "", "result4")
+	require.Equal(t, "result0", res)
+	require.Equal(t, int64(0), *v.execCallCount)
+}
+
 func TestSingleLevelCacheParallel(t *testing.T) {
 	t.Parallel()
 	ctx := context.TODO()
@@ -557,6 +613,71 @@ func TestSingleCancelExec(t *testing.T) {
 	j1 = nil
 }
 
+func TestCancelJob(t *testing.T) {
+	t.Parallel()
+	ctx := context.TODO()
+
+	s := NewSolver(SolverOpt{
+		ResolveOpFunc: testOpResolver,
+	})
+	defer s.Close()
+
+	err := s.Cancel("job1")
+	require.Error(t, err)
+	require.Equal(t, codes.NotFound, grpcerrors.Code(err))
+
+	j1, err := s.NewJob("job1")
+	require.NoError(t, err)
+
+	defer func() {
+		if j1 != nil {
+			j1.Discard()
+		}
+	}()
+
+	// jobs without a build context can't be cancelled
+	err = s.Cancel("job1")
+	require.Error(t, err)
+	require.Equal(t, codes.NotFound, grpcerrors.Code(err))
+	require.Equal(t, 0, len(s.ActiveJobs()))
+
+	ctx, cancel := j1.WithCancel(ctx)
+	defer cancel()
+
+	jobs := s.ActiveJobs()
+	require.Equal(t, 1, len(jobs))
+	require.Equal(t, "job1", jobs[0].ID())
+	require.False(t, jobs[0].StartedAt().IsZero())
+
+	g1 := Edge{
+		Vertex: vtx(vtxOpt{
+			name: "v1",
+			execPreFunc: func(ctx context.Context) error {
+				if err := s.Cancel("job1"); err != nil {
+					return err
+				}
+				<-ctx.Done()
+				return nil // error should still come from context
+			},
+		}),
+	}
+
+	_, err = j1.Build(ctx, g1)
+	require.Error(t, err)
+	require.Equal(t, true, errors.Is(err, context.Canceled))
+	require.True(t, j1.Cancelled())
+
+	// cancelled builds are not listed
+	require.Equal(t, 0, len(s.ActiveJobs()))
+
+	err = s.Cancel("job1")
+	require.Error(t, err)
+	require.Equal(t, codes.NotFound, grpcerrors.Code(err))
+
+	require.NoError(t, j1.Discard())
+	j1 = nil
+}
+
 func TestSingleCancelParallel(t *testing.T) {
 	t.Parallel()
 	ctx := context.TODO()
@@ -3330,6 +3451,102 @@ func TestInputRequestDeadlock(t *testing.T) {
 	j2 = nil
 }
 
+func TestExitCodeStatus(t *testing.T) {
+	t.Parallel()
+	ctx := context.TODO()
+
+	s := NewSolver(SolverOpt{
+		ResolveOpFunc: testOpResolver,
+	})
+	defer s.Close()
+
+	j0, err := s.NewJob("job0")
+	require.NoError(t, err)
+
+	ch := make(chan *client.SolveStatus)
+	statusDone := make(chan struct{})
+	var vertexes []*client.Vertex
+	go func() {
+		defer close(statusDone)
+		for ss := range ch {
+			vertexes = append(vertexes, ss.Vertexes...)
+		}
+	}()
+	go j0.Status(ctx, ch)
+
+	g0 := Edge{
+		Vertex: vtx(vtxOpt{
+			name: "v0",
+			execPreFunc: func(ctx context.Context) error {
+				SetExitCode(ctx, 3)
+				return errors.New("process exited with code 3")
+			},
+		}),
+	}
+	g0.Vertex.(*vertex).setupCallCounters()
+
+	_, err = j0.Build(ctx, g0)
+	require.Error(t, err)
+	require.NoError(t, j0.Discard())
+	<-statusDone
+
+	var completed *client.Vertex
+	for _, v := range vertexes {
+		if v.Digest == g0.Vertex.Digest() && v.Completed != nil {
+			completed = v
+		}
+	}
+	require.NotNil(t, completed)
+	require.NotEmpty(t, completed.Error)
+	require.Equal(t, 3, completed.ExitCode)
+}
+
+func TestBuildLabelsStatus(t *testing.T) {
+	t.Parallel()
+	ctx := context.TODO()
+
+	s := NewSolver(SolverOpt{
+		ResolveOpFunc: testOpResolver,
+	})
+	defer s.Close()
+
+	j0, err := s.NewJob("job0")
+	require.NoError(t, err)
+
+	ch := make(chan *client.SolveStatus)
+	statusDone := make(chan struct{})
+	var vertexes []*client.Vertex
+	go func() {
+		defer close(statusDone)
+		for ss := range ch {
+			vertexes = append(vertexes, ss.Vertexes...)
+		}
+	}()
+	go j0.Status(ctx, ch)
+
+	g0 := Edge{
+		Vertex: vtx(vtxOpt{
+			name:  "v0",
+			value: "result0",
+			description: map[string]string{
+				"llb.customname":      "build",
+				"llb.buildlabel.team": "core",
+			},
+		}),
+	}
+
+	_, err = j0.Build(ctx, g0)
+	require.NoError(t, err)
+	require.NoError(t, j0.Discard())
+	<-statusDone
+
+	require.NotEmpty(t, vertexes)
+	for _, v := range vertexes {
+		require.Equal(t, g0.Vertex.Digest(), v.Digest)
+		require.Equal(t, map[string]string{"team": "core"}, v.Labels)
+	}
+}
+
 func generateSubGraph(nodes int) (Edge, int) {
 	if nodes == 1 {
 		value := rand.Int() % 500
@@ -3362,6 +3579,37 @@ func generateSubGraph(nodes int) (Edge, int) {
 	return Edge{Vertex: vtxSum(extra, vtxOpt{inputs: inputs})}, value
 }
 
+func TestJobAttacher(t *testing.T) {
+	t.Parallel()
+	ctx := context.TODO()
+
+	s := NewSolver(SolverOpt{
+		ResolveOpFunc: testOpResolver,
+	})
+	defer s.Close()
+
+	v := &vertexAttacher{vertex: vtx(vtxOpt{name: "v0", value: "result0"})}
+
+	j0, err := s.NewJob("job0")
+	require.NoError(t, err)
+	defer j0.Discard()
+
+	res, err := j0.Build(ctx, Edge{Vertex: v})
+	require.NoError(t, err)
+	require.Equal(t, unwrap(res), "result0")
+	require.Empty(t, v.attached)
+
+	// jobs loading the vertex after the op was resolved are passed to it
+	j1, err := s.NewJob("job1")
+	require.NoError(t, err)
+	defer j1.Discard()
+
+	res, err = j1.Build(ctx, Edge{Vertex: &vertexAttacher{vertex: vtx(vtxOpt{name: "v0", value: "result0"})}})
+	require.NoError(t, err)
+	require.Equal(t, unwrap(res), "result0")
+	require.Equal(t, []*Job{j1}, v.attached)
+}
+
 type vtxOpt struct {
 	name             string
 	cacheKeySeed     string
@@ -3376,6 +3624,8 @@ type vtxOpt struct {
 	selectors        map[int]digest.Digest
 	cacheSource      CacheManager
 	ignoreCache      bool
+	cacheNamespace   string
+	description      map[string]string
 }
 
 func vtx(opt vtxOpt) *vertex {
@@ -3413,8 +3663,10 @@ func (v *vertex) Options() VertexOptions {
 		cache = append(cache, v.opt.cacheSource)
 	}
 	return VertexOptions{
-		CacheSources: cache,
-		IgnoreCache:  v.opt.ignoreCache,
+		CacheSources:   cache,
+		IgnoreCache:    v.opt.ignoreCache,
+		CacheNamespace: v.opt.cacheNamespace,
+		Description:    v.opt.description,
 	}
 }
 
@@ -3534,6 +3786,22 @@ func (v *vertex) makeCacheMap() *CacheMap {
 }
 
 // vtxConst returns a vertex that outputs a constant integer
+type vertexAttacher struct {
+	*vertex
+	mu       sync.Mutex
+	attached []*Job
+}
+
+func (v *vertexAttacher) Sys() interface{} {
+	return v
+}
+
+func (v *vertexAttacher) AttachJob(j *Job) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.attached = append(v.attached, j)
+}
+
 func vtxConst(v int, opt vtxOpt) *vertexConst {
 	if opt.cacheKeySeed == "" {
 		opt.cacheKeySeed = fmt.Sprintf("const-%d", v)
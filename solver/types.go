@@ -53,6 +53,14 @@ type VertexOptions struct {
 	CacheSources []CacheManager
 	Description  map[string]string // text values with no special meaning for solver
 	ExportCache  *bool
+	// CacheNamespace separates the cache keys of the vertex from the same
+	// vertex in other namespaces
+	CacheNamespace string
+	// ProgressGroup is the group the vertex is shown in by the progress output
+	ProgressGroup *pb.ProgressGroup
+	// CriticalPath is the length of the longest chain of vertexes from the
+	// vertex to the target of the build that loaded it
+	CriticalPath int
 	// WorkerConstraint
 }
 
@@ -153,6 +161,13 @@ type Op interface {
 	Acquire(ctx context.Context) (release ReleaseFunc, err error)
 }
 
+// JobAttacher can be implemented by an `Op` that keeps state in the jobs using
+// it. AttachJob is called when a job loads the vertex of the op after the op
+// was resolved for another job.
+type JobAttacher interface {
+	AttachJob(*Job)
+}
+
 type ResultBasedCacheFunc func(context.Context, Result, session.Group) (digest.Digest, error)
 type PreprocessFunc func(context.Context, Result, session.Group) error
 
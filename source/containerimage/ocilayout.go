@@ -0,0 +1,175 @@
+package containerimage
+
+import (
+	"context"
+	"io"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/platforms"
+	"github.com/containerd/containerd/remotes"
+	"github.com/moby/buildkit/session"
+	sessioncontent "github.com/moby/buildkit/session/content"
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/source"
+	"github.com/moby/buildkit/util/imageutil"
+	"github.com/moby/buildkit/util/pull"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// ociStoreIDPrefix is the prefix of the IDs of the content stores the client
+// attaches for oci-layout sources
+const ociStoreIDPrefix = "oci:"
+
+type ociLayoutSource struct {
+	*Source
+}
+
+// NewOCILayoutSource returns a source for images in a content store of the
+// client, e.g. an OCI layout directory or an image saved with docker save.
+// The blobs of the image are fetched from the session of the build.
+func NewOCILayoutSource(opt SourceOpt) (source.Source, error) {
+	is, err := NewSource(opt)
+	if err != nil {
+		return nil, err
+	}
+	return &ociLayoutSource{Source: is}, nil
+}
+
+func (is *ociLayoutSource) ID() string {
+	return source.OCILayoutScheme
+}
+
+func (is *ociLayoutSource) Resolve(ctx context.Context, id source.Identifier, sm *session.Manager, vtx solver.Vertex) (source.SourceInstance, error) {
+	ociIdentifier, ok := id.(*source.OCIIdentifier)
+	if !ok {
+		return nil, errors.Errorf("invalid oci-layout identifier %v", id)
+	}
+
+	platform := platforms.DefaultSpec()
+	if ociIdentifier.Platform != nil {
+		platform = *ociIdentifier.Platform
+	}
+
+	pullerUtil := &pull.Puller{
+		ContentStore: is.ContentStore,
+		Platform:     platform,
+		Src:          ociIdentifier.Reference,
+	}
+	p := &puller{
+		CacheAccessor: is.CacheAccessor,
+		LeaseManager:  is.LeaseManager,
+		Puller:        pullerUtil,
+		id: &source.ImageIdentifier{
+			Reference: ociIdentifier.Reference,
+			Platform:  ociIdentifier.Platform,
+		},
+		Ref:            ociIdentifier.Reference.String(),
+		SessionManager: sm,
+		vtx:            vtx,
+	}
+	p.newResolver = func(g session.Group) remotes.Resolver {
+		return &ociLayoutResolver{sm: sm, g: g, storeID: ociStoreIDPrefix + ociIdentifier.StoreID}
+	}
+	return p, nil
+}
+
+// ociLayoutResolver resolves and fetches images from a content store attached
+// to the session by the client
+type ociLayoutResolver struct {
+	sm      *session.Manager
+	g       session.Group
+	storeID string
+}
+
+var _ remotes.Resolver = &ociLayoutResolver{}
+
+func (r *ociLayoutResolver) WithSession(g session.Group) remotes.Resolver {
+	return &ociLayoutResolver{sm: r.sm, g: g, storeID: r.storeID}
+}
+
+func (r *ociLayoutResolver) Resolve(ctx context.Context, ref string) (string, specs.Descriptor, error) {
+	spec, err := source.NewOCIIdentifier(ref)
+	if err != nil {
+		return "", specs.Descriptor{}, err
+	}
+	desc := specs.Descriptor{
+		Digest: spec.Reference.Digest(),
+	}
+	err = r.withStore(ctx, func(ctx context.Context, cs content.Store) error {
+		info, err := cs.Info(ctx, desc.Digest)
+		if err != nil {
+			return err
+		}
+		desc.Size = info.Size
+		ra, err := cs.ReaderAt(ctx, desc)
+		if err != nil {
+			return err
+		}
+		defer ra.Close()
+		mt, err := imageutil.DetectManifestMediaType(ra)
+		if err != nil {
+			return err
+		}
+		desc.MediaType = mt
+		return nil
+	})
+	if err != nil {
+		return "", specs.Descriptor{}, errors.Wrapf(err, "failed to resolve %s in oci store %q", ref, r.storeID)
+	}
+	return ref, desc, nil
+}
+
+func (r *ociLayoutResolver) Fetcher(ctx context.Context, ref string) (remotes.Fetcher, error) {
+	return r, nil
+}
+
+func (r *ociLayoutResolver) Fetch(ctx context.Context, desc specs.Descriptor) (io.ReadCloser, error) {
+	var rc io.ReadCloser
+	err := r.withStore(ctx, func(ctx context.Context, cs content.Store) error {
+		ra, err := cs.ReaderAt(ctx, desc)
+		if err != nil {
+			return err
+		}
+		rc = &readerAtCloser{Reader: content.NewReader(ra), ra: ra}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rc, nil
+}
+
+func (r *ociLayoutResolver) Pusher(ctx context.Context, ref string) (remotes.Pusher, error) {
+	return nil, errors.Errorf("pushing to oci-layout sources is not supported")
+}
+
+// withStore calls f with the content store of the first session of the group
+// that accepts the request
+func (r *ociLayoutResolver) withStore(ctx context.Context, f func(context.Context, content.Store) error) error {
+	var found bool
+	err := r.sm.Any(ctx, r.g, func(ctx context.Context, _ string, c session.Caller) error {
+		if err := f(ctx, sessioncontent.NewCallerStore(c, r.storeID)); err != nil {
+			return err
+		}
+		found = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.Wrap(errdefs.ErrNotFound, "no session with the oci store")
+	}
+	return nil
+}
+
+type readerAtCloser struct {
+	io.Reader
+	ra content.ReaderAt
+}
+
+func (r *readerAtCloser) Close() error {
+	return r.ra.Close()
+}
@@ -15,6 +15,7 @@ import (
 	ctdlabels "github.com/containerd/containerd/labels"
 	"github.com/containerd/containerd/leases"
 	"github.com/containerd/containerd/platforms"
+	"github.com/containerd/containerd/remotes"
 	"github.com/containerd/containerd/remotes/docker"
 	"github.com/containerd/containerd/snapshots"
 	"github.com/moby/buildkit/cache"
@@ -25,6 +26,7 @@ import (
 	"github.com/moby/buildkit/solver/errdefs"
 	"github.com/moby/buildkit/source"
 	"github.com/moby/buildkit/util/flightcontrol"
+	"github.com/moby/buildkit/util/imagepolicy"
 	"github.com/moby/buildkit/util/imageutil"
 	"github.com/moby/buildkit/util/leaseutil"
 	"github.com/moby/buildkit/util/progress"
@@ -48,6 +50,7 @@ type SourceOpt struct {
 	ImageStore    images.Store // optional
 	RegistryHosts docker.RegistryHosts
 	LeaseManager  leases.Manager
+	ImagePolicy   *imagepolicy.Policy // optional
 }
 
 type Source struct {
@@ -85,7 +88,7 @@ func (is *Source) ResolveImageConfig(ctx context.Context, ref string, opt llb.Re
 	}
 
 	res, err := is.g.Do(ctx, key, func(ctx context.Context) (interface{}, error) {
-		res := resolver.DefaultPool.GetResolver(is.RegistryHosts, ref, "pull", sm, g).WithImageStore(is.ImageStore, rm)
+		res := resolver.DefaultPool.GetResolver(is.RegistryHosts, ref, "pull", sm, g).WithImageStore(is.ImageStore, rm).WithContentStore(is.ContentStore)
 		dgst, dt, err := imageutil.Config(ctx, ref, res, is.ContentStore, is.LeaseManager, opt.Platform)
 		if err != nil {
 			return nil, err
@@ -125,8 +128,12 @@ func (is *Source) Resolve(ctx context.Context, id source.Identifier, sm *session
 		Mode:           imageIdentifier.ResolveMode,
 		Ref:            imageIdentifier.Reference.String(),
 		SessionManager: sm,
+		ImagePolicy:    is.ImagePolicy,
 		vtx:            vtx,
 	}
+	p.newResolver = func(g session.Group) remotes.Resolver {
+		return resolver.DefaultPool.GetResolver(p.RegistryHosts, p.Ref, "pull", p.SessionManager, g).WithImageStore(p.ImageStore, p.id.ResolveMode).WithContentStore(p.ContentStore).WithMirrorPreference(p.id.MirrorPreference)
+	}
 	return p, nil
 }
 
@@ -138,8 +145,10 @@ type puller struct {
 	Mode           source.ResolveMode
 	Ref            string
 	SessionManager *session.Manager
+	ImagePolicy    *imagepolicy.Policy
 	id             *source.ImageIdentifier
 	vtx            solver.Vertex
+	newResolver    func(session.Group) remotes.Resolver
 
 	g                flightcontrol.Group
 	cacheKeyErr      error
@@ -170,8 +179,17 @@ func mainManifestKey(ctx context.Context, desc specs.Descriptor, platform specs.
 	return digest.FromBytes(dt), nil
 }
 
+// ResolvedDigest returns the digest of the manifest that the reference was
+// resolved to
+func (p *puller) ResolvedDigest() digest.Digest {
+	if p.manifest == nil {
+		return ""
+	}
+	return p.manifest.MainManifestDesc.Digest
+}
+
 func (p *puller) CacheKey(ctx context.Context, g session.Group, index int) (cacheKey string, cacheOpts solver.CacheOpts, cacheDone bool, err error) {
-	p.Puller.Resolver = resolver.DefaultPool.GetResolver(p.RegistryHosts, p.Ref, "pull", p.SessionManager, g).WithImageStore(p.ImageStore, p.id.ResolveMode)
+	p.Puller.Resolver = p.newResolver(g)
 
 	_, err = p.g.Do(ctx, "", func(ctx context.Context) (_ interface{}, err error) {
 		if p.cacheKeyErr != nil || p.cacheKeyDone == true {
@@ -199,6 +217,10 @@ func (p *puller) CacheKey(ctx context.Context, g session.Group, index int) (cach
 			return nil, err
 		}
 
+		if err := p.verifySignatures(ctx); err != nil {
+			return nil, err
+		}
+
 		if len(p.manifest.Descriptors) > 0 {
 			progressController := &controller.Controller{
 				WriterFactory: progress.FromContext(ctx),
@@ -272,8 +294,27 @@ func (p *puller) CacheKey(ctx context.Context, g session.Group, index int) (cach
 	return p.configKey, cacheOpts, cacheDone, nil
 }
 
+// verifySignatures checks that the image is signed according to the image
+// policy of the daemon and the image policy of the build. Signatures of the
+// index and of the manifest of the platform are accepted.
+func (p *puller) verifySignatures(ctx context.Context) error {
+	policies := []*imagepolicy.Policy{p.ImagePolicy, imagepolicy.FromContext(ctx)}
+	var dgsts []digest.Digest
+	for _, desc := range p.manifest.Nonlayers {
+		if desc.Digest != p.manifest.ConfigDesc.Digest {
+			dgsts = append(dgsts, desc.Digest)
+		}
+	}
+	for _, pol := range policies {
+		if err := pol.Verify(ctx, p.Src.Locator, dgsts, imagepolicy.FetchSignatures(p.Puller.Resolver)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (p *puller) Snapshot(ctx context.Context, g session.Group) (ir cache.ImmutableRef, err error) {
-	p.Puller.Resolver = resolver.DefaultPool.GetResolver(p.RegistryHosts, p.Ref, "pull", p.SessionManager, g).WithImageStore(p.ImageStore, p.id.ResolveMode)
+	p.Puller.Resolver = p.newResolver(g)
 
 	if len(p.manifest.Descriptors) == 0 {
 		return nil, nil
@@ -0,0 +1,79 @@
+package git
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/cabundle/cabundleprovider"
+	sessiontestutil "github.com/moby/buildkit/session/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCABundle(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	tmpdir, err := ioutil.TempDir("", "buildkit-git-ca")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	// the bundles left by a previous daemon are removed
+	tempDir := filepath.Join(tmpdir, "tmp")
+	require.NoError(t, os.MkdirAll(tempDir, 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "ca-bundle-old"), nil, 0600))
+	src, err := NewSource(Opt{TempDir: tempDir})
+	require.NoError(t, err)
+	gs := src.(*gitSource)
+	fis, err := ioutil.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(fis))
+
+	sm, err := session.NewManager()
+	require.NoError(t, err)
+	bundle := []byte("-----BEGIN CERTIFICATE-----\ntest\n-----END CERTIFICATE-----\n")
+	s, err := session.NewSession(ctx, "foo", "bar")
+	require.NoError(t, err)
+	s.Allow(cabundleprovider.NewCABundleProvider(bundle))
+	go s.Run(ctx, session.Dialer(sessiontestutil.TestStream(sessiontestutil.Handler(sm.HandleConn))))
+	defer s.Close()
+	g := session.NewGroup(s.ID())
+
+	ctx2, cleanup, err := gs.withCABundle(ctx, sm, g)
+	require.NoError(t, err)
+	p, ok := ctx2.Value(caBundleKey{}).(string)
+	require.True(t, ok)
+	require.Equal(t, tempDir, filepath.Dir(p))
+	fi, err := os.Stat(p)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+	dt, err := ioutil.ReadFile(p)
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(string(dt), string(bundle)))
+
+	require.NoError(t, cleanup())
+	_, err = os.Stat(p)
+	require.True(t, os.IsNotExist(err))
+
+	// sources without a private directory don't write the bundle anywhere
+	src, err = NewSource(Opt{})
+	require.NoError(t, err)
+	_, _, err = src.(*gitSource).withCABundle(ctx, sm, g)
+	require.Error(t, err)
+
+	// builds without a bundle use the roots of the system
+	s2, err := session.NewSession(ctx, "foo", "bar")
+	require.NoError(t, err)
+	go s2.Run(ctx, session.Dialer(sessiontestutil.TestStream(sessiontestutil.Handler(sm.HandleConn))))
+	defer s2.Close()
+	ctx2, cleanup, err = gs.withCABundle(ctx, sm, session.NewGroup(s2.ID()))
+	require.NoError(t, err)
+	require.NoError(t, cleanup())
+	_, ok = ctx2.Value(caBundleKey{}).(string)
+	require.False(t, ok)
+}
@@ -22,6 +22,8 @@ import (
 	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/identity"
 	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/cabundle"
+	"github.com/moby/buildkit/session/proxy"
 	"github.com/moby/buildkit/session/secrets"
 	"github.com/moby/buildkit/session/sshforward"
 	"github.com/moby/buildkit/snapshot"
@@ -29,6 +31,7 @@ import (
 	"github.com/moby/buildkit/source"
 	"github.com/moby/buildkit/util/progress/logs"
 	"github.com/moby/locker"
+	digest "github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	bolt "go.etcd.io/bbolt"
@@ -42,12 +45,16 @@ var defaultBranch = regexp.MustCompile(`refs/heads/(\S+)`)
 type Opt struct {
 	CacheAccessor cache.Accessor
 	MetadataStore *metadata.Store
+	// TempDir is the private directory for the CA bundles of the git
+	// commands. Its contents are removed by NewSource.
+	TempDir string
 }
 
 type gitSource struct {
-	md     *metadata.Store
-	cache  cache.Accessor
-	locker *locker.Locker
+	md      *metadata.Store
+	cache   cache.Accessor
+	locker  *locker.Locker
+	tempDir string
 }
 
 // Supported returns nil if the system supports Git source
@@ -59,10 +66,20 @@ func Supported() error {
 }
 
 func NewSource(opt Opt) (source.Source, error) {
+	if opt.TempDir != "" {
+		// the CA bundles left by a previous daemon are removed
+		if err := os.RemoveAll(opt.TempDir); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if err := os.MkdirAll(opt.TempDir, 0700); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
 	gs := &gitSource{
-		md:     opt.MetadataStore,
-		cache:  opt.CacheAccessor,
-		locker: locker.New(),
+		md:      opt.MetadataStore,
+		cache:   opt.CacheAccessor,
+		locker:  locker.New(),
+		tempDir: opt.TempDir,
 	}
 	return gs, nil
 }
@@ -163,6 +180,7 @@ type gitSourceHandler struct {
 	*gitSource
 	src      source.GitIdentifier
 	cacheKey string
+	sha      string
 	sm       *session.Manager
 	auth     []string
 }
@@ -312,6 +330,7 @@ func (gs *gitSourceHandler) CacheKey(ctx context.Context, g session.Group, index
 	defer gs.locker.Unlock(remote)
 
 	if ref := gs.src.Ref; ref != "" && isCommitSHA(ref) {
+		gs.sha = ref
 		ref = gs.shaToCacheKey(ref)
 		gs.cacheKey = ref
 		return ref, nil, true, nil
@@ -319,6 +338,14 @@ func (gs *gitSourceHandler) CacheKey(ctx context.Context, g session.Group, index
 
 	gs.getAuthToken(ctx, g)
 
+	ctx = proxy.WithConfig(ctx, proxy.GetConfig(ctx, gs.sm, g))
+
+	ctx, unmountCABundle, err := gs.withCABundle(ctx, gs.sm, g)
+	if err != nil {
+		return "", nil, false, err
+	}
+	defer unmountCABundle()
+
 	gitDir, unmountGitDir, err := gs.mountRemote(ctx, remote, gs.auth, g)
 	if err != nil {
 		return "", nil, false, err
@@ -369,11 +396,20 @@ func (gs *gitSourceHandler) CacheKey(ctx context.Context, g session.Group, index
 	if !isCommitSHA(sha) {
 		return "", nil, false, errors.Errorf("invalid commit sha %q", sha)
 	}
+	gs.sha = sha
 	sha = gs.shaToCacheKey(sha)
 	gs.cacheKey = sha
 	return sha, nil, true, nil
 }
 
+// ResolvedDigest returns the commit that the reference was resolved to
+func (gs *gitSourceHandler) ResolvedDigest() digest.Digest {
+	if gs.sha == "" {
+		return ""
+	}
+	return digest.NewDigestFromEncoded("sha1", gs.sha)
+}
+
 func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out cache.ImmutableRef, retErr error) {
 	cacheKey := gs.cacheKey
 	if cacheKey == "" {
@@ -386,6 +422,14 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 
 	gs.getAuthToken(ctx, g)
 
+	ctx = proxy.WithConfig(ctx, proxy.GetConfig(ctx, gs.sm, g))
+
+	ctx, unmountCABundle, err := gs.withCABundle(ctx, gs.sm, g)
+	if err != nil {
+		return nil, err
+	}
+	defer unmountCABundle()
+
 	snapshotKey := "git-snapshot::" + cacheKey + ":" + gs.src.Subdir
 	gs.locker.Lock(snapshotKey)
 	defer gs.locker.Unlock(snapshotKey)
@@ -620,6 +664,57 @@ func isCommitSHA(str string) bool {
 	return validHex.MatchString(str)
 }
 
+type caBundleKey struct{}
+
+// systemCABundles are the locations of the CA certificates of the system that
+// git trusts by default
+var systemCABundles = []string{
+	"/etc/ssl/certs/ca-certificates.crt",
+	"/etc/pki/tls/certs/ca-bundle.crt",
+	"/etc/ssl/ca-bundle.pem",
+	"/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem",
+	"/etc/ssl/cert.pem",
+}
+
+// withCABundle returns a context that makes the git commands trust the CA
+// bundle of the session group. git only reads the certificates of one file,
+// so the bundle is written with the certificates of the system to a file in
+// the private temporary directory of the source that is removed by the
+// returned function.
+func (gs *gitSource) withCABundle(ctx context.Context, sm *session.Manager, g session.Group) (context.Context, func() error, error) {
+	b := cabundle.GetCABundle(ctx, sm, g)
+	if b == nil {
+		return ctx, func() error { return nil }, nil
+	}
+	if gs.tempDir == "" {
+		return nil, nil, errors.New("CA bundles are not supported by the git source without a temporary directory")
+	}
+	f, err := ioutil.TempFile(gs.tempDir, "ca-bundle-")
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	cleanup := func() error {
+		return os.Remove(f.Name())
+	}
+	bundle := b.PEM
+	for _, p := range systemCABundles {
+		if dt, err := ioutil.ReadFile(p); err == nil {
+			bundle = append(append(dt, '\n'), bundle...)
+			break
+		}
+	}
+	if _, err := f.Write(bundle); err != nil {
+		f.Close()
+		cleanup()
+		return nil, nil, err
+	}
+	if err := f.Close(); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return context.WithValue(ctx, caBundleKey{}, f.Name()), cleanup, nil
+}
+
 func gitWithinDir(ctx context.Context, gitDir, workDir, sshAuthSock, knownHosts string, auth []string, args ...string) (*bytes.Buffer, error) {
 	a := append([]string{"--git-dir", gitDir}, auth...)
 	if workDir != "" {
@@ -659,6 +754,12 @@ func git(ctx context.Context, dir, sshAuthSock, knownHosts string, args ...strin
 		if sshAuthSock != "" {
 			cmd.Env = append(cmd.Env, "SSH_AUTH_SOCK="+sshAuthSock)
 		}
+		if cfg := proxy.FromContext(ctx); cfg != nil {
+			cmd.Env = append(cmd.Env, cfg.Env()...)
+		}
+		if p, ok := ctx.Value(caBundleKey{}).(string); ok {
+			cmd.Env = append(cmd.Env, "GIT_SSL_CAINFO="+p)
+		}
 		// remote git commands spawn helper processes that inherit FDs and don't
 		// handle parent death signal so exec.CommandContext can't be used
 		err := runProcessGroup(ctx, cmd)
@@ -19,6 +19,9 @@ import (
 	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/cache/metadata"
 	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/cabundle"
+	"github.com/moby/buildkit/session/proxy"
+	"github.com/moby/buildkit/session/secrets"
 	"github.com/moby/buildkit/snapshot"
 	"github.com/moby/buildkit/solver"
 	"github.com/moby/buildkit/source"
@@ -32,9 +35,20 @@ import (
 type Opt struct {
 	CacheAccessor cache.Accessor
 	MetadataStore *metadata.Store
-	Transport     http.RoundTripper
+	// Transport sends the requests of the source. The proxy configuration of
+	// the build is only used if the transport uses proxy.ProxyFunc. Builds
+	// with a CA bundle require an *http.Transport.
+	Transport http.RoundTripper
 }
 
+// defaultTransport is http.DefaultTransport with the proxy configuration of
+// the build
+var defaultTransport = func() http.RoundTripper {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = proxy.ProxyFunc
+	return tracing.NewTransport(t)
+}()
+
 type httpSource struct {
 	md        *metadata.Store
 	cache     cache.Accessor
@@ -45,7 +59,7 @@ type httpSource struct {
 func NewSource(opt Opt) (source.Source, error) {
 	transport := opt.Transport
 	if transport == nil {
-		transport = tracing.DefaultTransport
+		transport = defaultTransport
 	}
 	hs := &httpSource{
 		md:        opt.MetadataStore,
@@ -65,6 +79,7 @@ type httpSourceHandler struct {
 	src      source.HTTPIdentifier
 	refID    string
 	cacheKey digest.Digest
+	checksum digest.Digest
 	sm       *session.Manager
 }
 
@@ -81,21 +96,52 @@ func (hs *httpSource) Resolve(ctx context.Context, id source.Identifier, sm *ses
 	}, nil
 }
 
-func (hs *httpSourceHandler) client(g session.Group) *http.Client {
-	return &http.Client{Transport: newTransport(hs.transport, hs.sm, g)}
+// client returns a client for the requests of the session group g that uses
+// the proxy configuration and trusts the CA bundle of the group
+func (hs *httpSourceHandler) client(ctx context.Context, g session.Group) (*http.Client, error) {
+	cfg := proxy.GetConfig(ctx, hs.sm, g)
+	rt, err := cabundle.GetCABundle(ctx, hs.sm, g).Transport(hs.transport)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{
+		Transport:     proxy.NewTransport(newTransport(rt, hs.sm, g), cfg),
+		CheckRedirect: hs.checkRedirect,
+	}, nil
+}
+
+// checkRedirect removes the headers of the source from the requests that are
+// redirected to another host
+func (hs *httpSourceHandler) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	if req.URL.Host != via[0].URL.Host {
+		for k := range hs.src.Headers {
+			req.Header.Del(k)
+		}
+		for k := range hs.src.SecretHeaders {
+			req.Header.Del(k)
+		}
+	}
+	return nil
 }
 
 // urlHash is internal hash the etag is stored by that doesn't leak outside
-// this package.
+// this package. The values of the secret headers are not part of it.
 func (hs *httpSourceHandler) urlHash() (digest.Digest, error) {
 	dt, err := json.Marshal(struct {
 		Filename       string
 		Perm, UID, GID int
+		Headers        map[string]string `json:",omitempty"`
+		SecretHeaders  map[string]string `json:",omitempty"`
 	}{
-		Filename: getFileName(hs.src.URL, hs.src.Filename, nil),
-		Perm:     hs.src.Perm,
-		UID:      hs.src.UID,
-		GID:      hs.src.GID,
+		Filename:      getFileName(hs.src.URL, hs.src.Filename, nil),
+		Perm:          hs.src.Perm,
+		UID:           hs.src.UID,
+		GID:           hs.src.GID,
+		Headers:       hs.src.Headers,
+		SecretHeaders: hs.src.SecretHeaders,
 	})
 	if err != nil {
 		return "", err
@@ -126,6 +172,7 @@ func (hs *httpSourceHandler) formatCacheKey(filename string, dgst digest.Digest,
 func (hs *httpSourceHandler) CacheKey(ctx context.Context, g session.Group, index int) (string, solver.CacheOpts, bool, error) {
 	if hs.src.Checksum != "" {
 		hs.cacheKey = hs.src.Checksum
+		hs.checksum = hs.src.Checksum
 		return hs.formatCacheKey(getFileName(hs.src.URL, hs.src.Filename, nil), hs.src.Checksum, "").String(), nil, true, nil
 	}
 
@@ -140,11 +187,10 @@ func (hs *httpSourceHandler) CacheKey(ctx context.Context, g session.Group, inde
 		return "", nil, false, errors.Wrapf(err, "failed to search metadata for %s", uh)
 	}
 
-	req, err := http.NewRequest("GET", hs.src.URL, nil)
+	req, err := hs.newRequest(ctx, hs.src.URL, g)
 	if err != nil {
 		return "", nil, false, err
 	}
-	req = req.WithContext(ctx)
 	m := map[string]*metadata.StorageItem{}
 
 	// If we request a single ETag in 'If-None-Match', some servers omit the
@@ -175,7 +221,10 @@ func (hs *httpSourceHandler) CacheKey(ctx context.Context, g session.Group, inde
 		}
 	}
 
-	client := hs.client(g)
+	client, err := hs.client(ctx, g)
+	if err != nil {
+		return "", nil, false, err
+	}
 
 	// Some servers seem to have trouble supporting If-None-Match properly even
 	// though they return ETag-s. So first, optionally try a HEAD request with
@@ -197,6 +246,7 @@ func (hs *httpSourceHandler) CacheKey(ctx context.Context, g session.Group, inde
 					hs.refID = si.ID()
 					dgst := getChecksum(si)
 					if dgst != "" {
+						hs.checksum = dgst
 						modTime := getModTime(si)
 						resp.Body.Close()
 						return hs.formatCacheKey(getFileName(hs.src.URL, hs.src.Filename, resp), dgst, modTime).String(), nil, true, nil
@@ -233,6 +283,7 @@ func (hs *httpSourceHandler) CacheKey(ctx context.Context, g session.Group, inde
 		if dgst == "" {
 			return "", nil, false, errors.Errorf("invalid metadata change")
 		}
+		hs.checksum = dgst
 		modTime := getModTime(si)
 		resp.Body.Close()
 		return hs.formatCacheKey(getFileName(hs.src.URL, hs.src.Filename, resp), dgst, modTime).String(), nil, true, nil
@@ -245,10 +296,16 @@ func (hs *httpSourceHandler) CacheKey(ctx context.Context, g session.Group, inde
 	ref.Release(context.TODO())
 
 	hs.cacheKey = dgst
+	hs.checksum = dgst
 
 	return hs.formatCacheKey(getFileName(hs.src.URL, hs.src.Filename, resp), dgst, resp.Header.Get("Last-Modified")).String(), nil, true, nil
 }
 
+// ResolvedDigest returns the digest of the content of the URL
+func (hs *httpSourceHandler) ResolvedDigest() digest.Digest {
+	return hs.checksum
+}
+
 func (hs *httpSourceHandler) save(ctx context.Context, resp *http.Response, s session.Group) (ref cache.ImmutableRef, dgst digest.Digest, retErr error) {
 	newRef, err := hs.cache.New(ctx, nil, s, cache.CachePolicyRetain, cache.WithDescription(fmt.Sprintf("http url %s", hs.src.URL)))
 	if err != nil {
@@ -379,18 +436,86 @@ func (hs *httpSourceHandler) Snapshot(ctx context.Context, g session.Group) (cac
 		}
 	}
 
-	req, err := http.NewRequest("GET", hs.src.URL, nil)
+	if len(hs.src.Mirrors) == 0 {
+		return hs.fetch(ctx, hs.src.URL, g)
+	}
+
+	// the content was requested by checksum, so any URL that serves the
+	// matching content can be used
+	var errs []string
+	for _, u := range append([]string{hs.src.URL}, hs.src.Mirrors...) {
+		ref, err := hs.fetch(ctx, u, g)
+		if err == nil {
+			return ref, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", u, err))
+	}
+	return nil, errors.Errorf("failed to fetch %s from any mirror: %s", hs.cacheKey, strings.Join(errs, "; "))
+}
+
+// newRequest returns a request for u. The headers of the source are only set
+// on requests to the URL of the source.
+func (hs *httpSourceHandler) newRequest(ctx context.Context, u string, g session.Group) (*http.Request, error) {
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if u != hs.src.URL {
+		return req.WithContext(ctx), nil
+	}
+	for k, v := range hs.src.Headers {
+		req.Header.Set(k, v)
+	}
+	for k, id := range hs.src.SecretHeaders {
+		v, err := hs.secret(ctx, id, g)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load secret %s for header %s", id, k)
+		}
+		req.Header.Set(k, v)
+	}
+	return req.WithContext(ctx), nil
+}
+
+// secret returns the value of a secret of the session group g
+func (hs *httpSourceHandler) secret(ctx context.Context, id string, g session.Group) (string, error) {
+	if hs.sm == nil {
+		return "", errors.New("no session")
+	}
+	var dt []byte
+	err := hs.sm.Any(ctx, g, func(ctx context.Context, _ string, caller session.Caller) error {
+		var err error
+		dt, err = secrets.GetSecret(ctx, caller, id)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(dt), nil
+}
+
+// fetch downloads the content of u and verifies it against the cache key
+func (hs *httpSourceHandler) fetch(ctx context.Context, u string, g session.Group) (cache.ImmutableRef, error) {
+	req, err := hs.newRequest(ctx, u, g)
 	if err != nil {
 		return nil, err
 	}
-	req = req.WithContext(ctx)
 
-	client := hs.client(g)
+	client, err := hs.client(ctx, g)
+	if err != nil {
+		return nil, err
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return nil, errors.Errorf("invalid response status %d", resp.StatusCode)
+	}
 
 	ref, dgst, err := hs.save(ctx, resp, g)
 	if err != nil {
@@ -2,7 +2,10 @@ package http
 
 import (
 	"context"
+	"encoding/pem"
 	"io/ioutil"
+	gohttp "net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -15,6 +18,10 @@ import (
 	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/cache/metadata"
 	"github.com/moby/buildkit/identity"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/cabundle/cabundleprovider"
+	"github.com/moby/buildkit/session/secrets/secretsprovider"
+	sessiontestutil "github.com/moby/buildkit/session/testutil"
 	"github.com/moby/buildkit/snapshot"
 	containerdsnapshot "github.com/moby/buildkit/snapshot/containerd"
 	"github.com/moby/buildkit/source"
@@ -90,6 +97,9 @@ func TestHTTPSource(t *testing.T) {
 	require.Equal(t, server.Stats("/foo").AllRequests, 2)
 	require.Equal(t, server.Stats("/foo").CachedRequests, 1)
 
+	// the digest of the content is known without downloading it again
+	require.Equal(t, digest.FromBytes([]byte("content1")), h.(source.DigestResolver).ResolvedDigest())
+
 	ref, err = h.Snapshot(ctx, nil)
 	require.NoError(t, err)
 	defer func() {
@@ -300,6 +310,294 @@ func TestHTTPChecksum(t *testing.T) {
 
 }
 
+func TestHTTPMirrors(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Depends on unimplemented containerd bind-mount support on Windows")
+	}
+
+	t.Parallel()
+	ctx := context.TODO()
+
+	tmpdir, err := ioutil.TempDir("", "buildkit-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	hs, err := newHTTPSource(tmpdir)
+	require.NoError(t, err)
+
+	primary := httpserver.NewTestServer(map[string]httpserver.Response{})
+	defer primary.Close()
+	mirror1 := httpserver.NewTestServer(map[string]httpserver.Response{
+		"/foo": {Content: []byte("content-different")},
+	})
+	defer mirror1.Close()
+	mirror2 := httpserver.NewTestServer(map[string]httpserver.Response{
+		"/foo": {Content: []byte("content-correct")},
+	})
+	defer mirror2.Close()
+
+	checksum := digest.FromBytes([]byte("content-correct"))
+
+	h, err := hs.Resolve(ctx, &source.HTTPIdentifier{URL: primary.URL + "/foo", Checksum: checksum}, nil, nil)
+	require.NoError(t, err)
+	expected, _, _, err := h.CacheKey(ctx, nil, 0)
+	require.NoError(t, err)
+
+	id := &source.HTTPIdentifier{
+		URL:      primary.URL + "/foo",
+		Checksum: checksum,
+		Mirrors:  []string{mirror1.URL + "/foo", mirror2.URL + "/foo"},
+	}
+	h, err = hs.Resolve(ctx, id, nil, nil)
+	require.NoError(t, err)
+
+	k, _, _, err := h.CacheKey(ctx, nil, 0)
+	require.NoError(t, err)
+	require.Equal(t, expected, k)
+
+	ref, err := h.Snapshot(ctx, nil)
+	require.NoError(t, err)
+	defer ref.Release(context.TODO())
+
+	dt, err := readFile(ctx, ref, "foo")
+	require.NoError(t, err)
+	require.Equal(t, []byte("content-correct"), dt)
+
+	require.Equal(t, 1, mirror1.Stats("/foo").AllRequests)
+	require.Equal(t, 1, mirror2.Stats("/foo").AllRequests)
+
+	id.Mirrors = []string{mirror1.URL + "/foo"}
+	h, err = hs.Resolve(ctx, id, nil, nil)
+	require.NoError(t, err)
+	_, _, _, err = h.CacheKey(ctx, nil, 0)
+	require.NoError(t, err)
+	_, err = h.Snapshot(ctx, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid response status 404")
+	require.Contains(t, err.Error(), "digest mismatch")
+}
+
+func TestHTTPHeaders(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Depends on unimplemented containerd bind-mount support on Windows")
+	}
+
+	t.Parallel()
+	ctx := context.TODO()
+
+	tmpdir, err := ioutil.TempDir("", "buildkit-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	hs, err := newHTTPSource(tmpdir)
+	require.NoError(t, err)
+
+	server := httpserver.NewTestServer(map[string]httpserver.Response{
+		"/foo": {Content: []byte("content1")},
+	})
+	defer server.Close()
+
+	id := &source.HTTPIdentifier{
+		URL:     server.URL + "/foo",
+		Headers: map[string]string{"Accept": "application/octet-stream", "x-custom": "bar"},
+	}
+	h, err := hs.Resolve(ctx, id, nil, nil)
+	require.NoError(t, err)
+
+	_, _, _, err = h.CacheKey(ctx, nil, 0)
+	require.NoError(t, err)
+	require.Equal(t, "application/octet-stream", server.Stats("/foo").LastHeader.Get("Accept"))
+	require.Equal(t, "bar", server.Stats("/foo").LastHeader.Get("X-Custom"))
+
+	ref, err := h.Snapshot(ctx, nil)
+	require.NoError(t, err)
+	defer ref.Release(context.TODO())
+
+	dt, err := readFile(ctx, ref, "foo")
+	require.NoError(t, err)
+	require.Equal(t, []byte("content1"), dt)
+}
+
+func TestHTTPHeadersMirrorsAndRedirects(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Depends on unimplemented containerd bind-mount support on Windows")
+	}
+
+	t.Parallel()
+	ctx := context.TODO()
+
+	tmpdir, err := ioutil.TempDir("", "buildkit-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	hs, err := newHTTPSource(tmpdir)
+	require.NoError(t, err)
+
+	other := httpserver.NewTestServer(map[string]httpserver.Response{
+		"/foo": {Content: []byte("content-correct")},
+	})
+	defer other.Close()
+	primary := httpserver.NewTestServer(map[string]httpserver.Response{
+		"/foo":   {Content: []byte("content-different")},
+		"/redir": {Redirect: other.URL + "/foo"},
+	})
+	defer primary.Close()
+
+	checksum := digest.FromBytes([]byte("content-correct"))
+	headers := map[string]string{"x-custom": "bar"}
+
+	// the headers are not sent to the mirrors
+	h, err := hs.Resolve(ctx, &source.HTTPIdentifier{
+		URL:      primary.URL + "/foo",
+		Checksum: checksum,
+		Mirrors:  []string{other.URL + "/foo"},
+		Headers:  headers,
+	}, nil, nil)
+	require.NoError(t, err)
+	_, _, _, err = h.CacheKey(ctx, nil, 0)
+	require.NoError(t, err)
+	ref, err := h.Snapshot(ctx, nil)
+	require.NoError(t, err)
+	ref.Release(context.TODO())
+	require.Equal(t, "bar", primary.Stats("/foo").LastHeader.Get("X-Custom"))
+	require.Equal(t, 1, other.Stats("/foo").AllRequests)
+	require.Equal(t, "", other.Stats("/foo").LastHeader.Get("X-Custom"))
+
+	// the headers are not sent to the host a request is redirected to
+	h, err = hs.Resolve(ctx, &source.HTTPIdentifier{
+		URL:     primary.URL + "/redir",
+		Headers: headers,
+	}, nil, nil)
+	require.NoError(t, err)
+	_, _, _, err = h.CacheKey(ctx, nil, 0)
+	require.NoError(t, err)
+	require.Equal(t, "bar", primary.Stats("/redir").LastHeader.Get("X-Custom"))
+	require.Equal(t, 2, other.Stats("/foo").AllRequests)
+	require.Equal(t, "", other.Stats("/foo").LastHeader.Get("X-Custom"))
+}
+
+func TestHTTPSecretHeaders(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Depends on unimplemented containerd bind-mount support on Windows")
+	}
+
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	tmpdir, err := ioutil.TempDir("", "buildkit-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	hs, err := newHTTPSource(tmpdir)
+	require.NoError(t, err)
+
+	s, err := session.NewSession(ctx, "foo", "bar")
+	require.NoError(t, err)
+	s.Allow(secretsprovider.FromMap(map[string][]byte{"token": []byte("Bearer abc")}))
+	sm, err := session.NewManager()
+	require.NoError(t, err)
+	go s.Run(ctx, session.Dialer(sessiontestutil.TestStream(sessiontestutil.Handler(sm.HandleConn))))
+	defer s.Close()
+	g := session.NewGroup(s.ID())
+
+	server := httpserver.NewTestServer(map[string]httpserver.Response{
+		"/foo": {Content: []byte("content1")},
+	})
+	defer server.Close()
+
+	id := &source.HTTPIdentifier{
+		URL:           server.URL + "/foo",
+		SecretHeaders: map[string]string{"Authorization": "token"},
+	}
+	h, err := hs.Resolve(ctx, id, sm, nil)
+	require.NoError(t, err)
+
+	_, _, _, err = h.CacheKey(ctx, g, 0)
+	require.NoError(t, err)
+	require.Equal(t, "Bearer abc", server.Stats("/foo").LastHeader.Get("Authorization"))
+
+	ref, err := h.Snapshot(ctx, g)
+	require.NoError(t, err)
+	defer ref.Release(context.TODO())
+	dt, err := readFile(ctx, ref, "foo")
+	require.NoError(t, err)
+	require.Equal(t, []byte("content1"), dt)
+
+	// a missing secret fails the request
+	id = &source.HTTPIdentifier{
+		URL:           server.URL + "/foo",
+		SecretHeaders: map[string]string{"Authorization": "missing"},
+	}
+	h, err = hs.Resolve(ctx, id, sm, nil)
+	require.NoError(t, err)
+	_, _, _, err = h.CacheKey(ctx, g, 0)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to load secret missing")
+}
+
+func TestHTTPCABundle(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Depends on unimplemented containerd bind-mount support on Windows")
+	}
+
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	tmpdir, err := ioutil.TempDir("", "buildkit-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	hs, err := newHTTPSource(tmpdir)
+	require.NoError(t, err)
+
+	server := httptest.NewTLSServer(gohttp.HandlerFunc(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		w.Write([]byte("content1"))
+	}))
+	defer server.Close()
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	sm, err := session.NewManager()
+	require.NoError(t, err)
+	var sessions []*session.Session
+	defer func() {
+		for _, s := range sessions {
+			s.Close()
+		}
+	}()
+	newGroup := func(attachables ...session.Attachable) session.Group {
+		s, err := session.NewSession(ctx, "foo", "bar")
+		require.NoError(t, err)
+		for _, a := range attachables {
+			s.Allow(a)
+		}
+		go s.Run(ctx, session.Dialer(sessiontestutil.TestStream(sessiontestutil.Handler(sm.HandleConn))))
+		sessions = append(sessions, s)
+		return session.NewGroup(s.ID())
+	}
+
+	id := &source.HTTPIdentifier{URL: server.URL + "/foo"}
+	h, err := hs.Resolve(ctx, id, sm, nil)
+	require.NoError(t, err)
+
+	// the certificate of the server is only trusted by builds with the bundle
+	_, _, _, err = h.CacheKey(ctx, newGroup(), 0)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "certificate")
+
+	g := newGroup(cabundleprovider.NewCABundleProvider(bundle))
+	_, _, _, err = h.CacheKey(ctx, g, 0)
+	require.NoError(t, err)
+
+	ref, err := h.Snapshot(ctx, g)
+	require.NoError(t, err)
+	defer ref.Release(context.TODO())
+	dt, err := readFile(ctx, ref, "foo")
+	require.NoError(t, err)
+	require.Equal(t, []byte("content1"), dt)
+}
+
 func readFile(ctx context.Context, ref cache.ImmutableRef, fp string) ([]byte, error) {
 	mount, err := ref.Mount(ctx, false, nil)
 	if err != nil {
@@ -2,6 +2,7 @@ package source
 
 import (
 	"encoding/json"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -25,6 +26,7 @@ const (
 	ResolveModeDefault ResolveMode = iota
 	ResolveModeForcePull
 	ResolveModePreferLocal
+	ResolveModeLocalOnly
 )
 
 const (
@@ -33,6 +35,7 @@ const (
 	LocalScheme       = "local"
 	HTTPScheme        = "http"
 	HTTPSScheme       = "https"
+	OCILayoutScheme   = "oci-layout"
 )
 
 type Identifier interface {
@@ -57,6 +60,8 @@ func FromString(s string) (Identifier, error) {
 		return NewHTTPIdentifier(parts[1], true)
 	case HTTPScheme:
 		return NewHTTPIdentifier(parts[1], false)
+	case OCILayoutScheme:
+		return NewOCIIdentifier(parts[1])
 	default:
 		return nil, errors.Wrapf(errNotFound, "unknown schema %s", parts[0])
 	}
@@ -92,6 +97,29 @@ func FromLLB(op *pb.Op_Source, platform *pb.Platform) (Identifier, error) {
 					return nil, err
 				}
 				id.RecordType = rt
+			case pb.AttrImageMirrorPreference:
+				var mirrors []string
+				if err := json.Unmarshal([]byte(v), &mirrors); err != nil {
+					return nil, err
+				}
+				id.MirrorPreference = mirrors
+			}
+		}
+	}
+	if id, ok := id.(*OCIIdentifier); ok {
+		if platform != nil {
+			id.Platform = &specs.Platform{
+				OS:           platform.OS,
+				Architecture: platform.Architecture,
+				Variant:      platform.Variant,
+				OSVersion:    platform.OSVersion,
+				OSFeatures:   platform.OSFeatures,
+			}
+		}
+		for k, v := range op.Source.Attrs {
+			switch k {
+			case pb.AttrOCILayoutStoreID:
+				id.StoreID = v
 			}
 		}
 	}
@@ -154,6 +182,23 @@ func FromLLB(op *pb.Op_Source, platform *pb.Platform) (Identifier, error) {
 				case pb.AttrLocalDifferNone:
 					id.Differ = fsutil.DiffNone
 				}
+			case pb.AttrLocalPreserveOwnership:
+				id.PreserveOwnership = v == "true"
+			case pb.AttrLocalUIDMap:
+				if err := json.Unmarshal([]byte(v), &id.UIDMap); err != nil {
+					return nil, errors.Wrapf(err, "invalid uid map %q", v)
+				}
+			case pb.AttrLocalGIDMap:
+				if err := json.Unmarshal([]byte(v), &id.GIDMap); err != nil {
+					return nil, errors.Wrapf(err, "invalid gid map %q", v)
+				}
+			case pb.AttrLocalSymlinkMode:
+				switch v {
+				case pb.AttrLocalSymlinkModePreserve, pb.AttrLocalSymlinkModeDereference, pb.AttrLocalSymlinkModeError:
+					id.SymlinkMode = v
+				default:
+					return nil, errors.Errorf("invalid symlink mode %q", v)
+				}
 			}
 		}
 	}
@@ -186,8 +231,44 @@ func FromLLB(op *pb.Op_Source, platform *pb.Platform) (Identifier, error) {
 					return nil, err
 				}
 				id.GID = int(i)
+			case pb.AttrHTTPMirrors:
+				var mirrors []string
+				if err := json.Unmarshal([]byte(v), &mirrors); err != nil {
+					return nil, errors.Wrapf(err, "invalid http mirrors %q", v)
+				}
+				for _, m := range mirrors {
+					u, err := url.Parse(m)
+					if err != nil {
+						return nil, errors.Wrapf(err, "invalid http mirror %q", m)
+					}
+					if u.Scheme != "http" && u.Scheme != "https" {
+						return nil, errors.Errorf("invalid http mirror %q", m)
+					}
+				}
+				id.Mirrors = mirrors
+			default:
+				if name := strings.TrimPrefix(k, pb.AttrHTTPHeaderPrefix); name != k {
+					if name == "" {
+						return nil, errors.Errorf("invalid empty http header name")
+					}
+					if id.Headers == nil {
+						id.Headers = map[string]string{}
+					}
+					id.Headers[name] = v
+				} else if name := strings.TrimPrefix(k, pb.AttrHTTPSecretHeaderPrefix); name != k {
+					if name == "" || v == "" {
+						return nil, errors.Errorf("invalid http secret header %q", k)
+					}
+					if id.SecretHeaders == nil {
+						id.SecretHeaders = map[string]string{}
+					}
+					id.SecretHeaders[name] = v
+				}
 			}
 		}
+		if len(id.Mirrors) > 0 && id.Checksum == "" {
+			return nil, errors.Errorf("http mirrors of %s require a checksum", id.URL)
+		}
 	}
 	return id, nil
 }
@@ -197,6 +278,9 @@ type ImageIdentifier struct {
 	Platform    *specs.Platform
 	ResolveMode ResolveMode
 	RecordType  client.UsageRecordType
+	// MirrorPreference lists registry hosts that are tried first, in order,
+	// before the remaining configured mirrors and the upstream registry.
+	MirrorPreference []string
 }
 
 func NewImageIdentifier(str string) (*ImageIdentifier, error) {
@@ -215,6 +299,30 @@ func (*ImageIdentifier) ID() string {
 	return DockerImageScheme
 }
 
+// OCIIdentifier identifies an image in a content store of the client. The
+// reference must contain the digest of the image.
+type OCIIdentifier struct {
+	Reference reference.Spec
+	Platform  *specs.Platform
+	StoreID   string
+}
+
+func NewOCIIdentifier(str string) (*OCIIdentifier, error) {
+	ref, err := reference.Parse(str)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if ref.Digest() == "" {
+		return nil, errors.Errorf("oci-layout reference %q must contain a digest", str)
+	}
+	return &OCIIdentifier{Reference: ref}, nil
+}
+
+func (*OCIIdentifier) ID() string {
+	return OCILayoutScheme
+}
+
 type LocalIdentifier struct {
 	Name            string
 	SessionID       string
@@ -223,6 +331,12 @@ type LocalIdentifier struct {
 	FollowPaths     []string
 	SharedKeyHint   string
 	Differ          fsutil.DiffType
+
+	PreserveOwnership bool
+	UIDMap            map[int]int
+	GIDMap            map[int]int
+
+	SymlinkMode string
 }
 
 func NewLocalIdentifier(str string) (*LocalIdentifier, error) {
@@ -249,6 +363,15 @@ type HTTPIdentifier struct {
 	Perm     int
 	UID      int
 	GID      int
+	// Mirrors are tried in order when the content of URL can't be fetched or
+	// doesn't match Checksum
+	Mirrors []string
+	// Headers are set on the requests to URL. They are not sent to the
+	// mirrors or to the hosts that URL redirects to.
+	Headers map[string]string
+	// SecretHeaders are set on the requests to URL like Headers, to the
+	// values of the session secrets with the IDs
+	SecretHeaders map[string]string
 }
 
 func (*HTTPIdentifier) ID() string {
@@ -263,6 +386,8 @@ func (r ResolveMode) String() string {
 		return pb.AttrImageResolveModeForcePull
 	case ResolveModePreferLocal:
 		return pb.AttrImageResolveModePreferLocal
+	case ResolveModeLocalOnly:
+		return pb.AttrImageResolveModeLocalOnly
 	default:
 		return ""
 	}
@@ -276,6 +401,8 @@ func ParseImageResolveMode(v string) (ResolveMode, error) {
 		return ResolveModeForcePull, nil
 	case pb.AttrImageResolveModePreferLocal:
 		return ResolveModePreferLocal, nil
+	case pb.AttrImageResolveModeLocalOnly:
+		return ResolveModeLocalOnly, nil
 	default:
 		return 0, errors.Errorf("invalid resolvemode: %s", v)
 	}
@@ -82,11 +82,15 @@ func (ls *localSourceHandler) CacheKey(ctx context.Context, g session.Group, ind
 		sessionID = id
 	}
 	dt, err := json.Marshal(struct {
-		SessionID       string
-		IncludePatterns []string
-		ExcludePatterns []string
-		FollowPaths     []string
-	}{SessionID: sessionID, IncludePatterns: ls.src.IncludePatterns, ExcludePatterns: ls.src.ExcludePatterns, FollowPaths: ls.src.FollowPaths})
+		SessionID         string
+		IncludePatterns   []string
+		ExcludePatterns   []string
+		FollowPaths       []string
+		PreserveOwnership bool        `json:",omitempty"`
+		UIDMap            map[int]int `json:",omitempty"`
+		GIDMap            map[int]int `json:",omitempty"`
+		SymlinkMode       string      `json:",omitempty"`
+	}{SessionID: sessionID, IncludePatterns: ls.src.IncludePatterns, ExcludePatterns: ls.src.ExcludePatterns, FollowPaths: ls.src.FollowPaths, PreserveOwnership: ls.src.PreserveOwnership, UIDMap: ls.src.UIDMap, GIDMap: ls.src.GIDMap, SymlinkMode: ls.src.SymlinkMode})
 	if err != nil {
 		return "", nil, false, err
 	}
@@ -95,8 +99,8 @@ func (ls *localSourceHandler) CacheKey(ctx context.Context, g session.Group, ind
 
 func (ls *localSourceHandler) Snapshot(ctx context.Context, g session.Group) (cache.ImmutableRef, error) {
 	var ref cache.ImmutableRef
-	err := ls.sm.Any(ctx, g, func(ctx context.Context, _ string, c session.Caller) error {
-		r, err := ls.snapshot(ctx, g, c)
+	err := ls.sm.Any(ctx, g, func(ctx context.Context, id string, c session.Caller) error {
+		r, err := ls.snapshot(ctx, g, id, c)
 		if err != nil {
 			return err
 		}
@@ -109,8 +113,12 @@ func (ls *localSourceHandler) Snapshot(ctx context.Context, g session.Group) (ca
 	return ref, nil
 }
 
-func (ls *localSourceHandler) snapshot(ctx context.Context, s session.Group, caller session.Caller) (out cache.ImmutableRef, retErr error) {
+func (ls *localSourceHandler) snapshot(ctx context.Context, s session.Group, sessionID string, caller session.Caller) (out cache.ImmutableRef, retErr error) {
 	sharedKey := keySharedKey + ":" + ls.src.Name + ":" + ls.src.SharedKeyHint + ":" + caller.SharedKey() // TODO: replace caller.SharedKey() with source based hint from client(absolute-path+nodeid)
+	if ls.src.PreserveOwnership {
+		// don't reuse a directory that was synced with reset ownership
+		sharedKey += ":preserve-ownership"
+	}
 
 	var mutable cache.MutableRef
 	sis, err := ls.md.Search(sharedKey)
@@ -179,10 +187,31 @@ func (ls *localSourceHandler) snapshot(ctx context.Context, s session.Group, cal
 		CacheUpdater:     &cacheUpdater{cc, mount.IdentityMapping()},
 		ProgressCb:       newProgressHandler(ctx, "transferring "+ls.src.Name+":"),
 		Differ:           ls.src.Differ,
+
+		PreserveOwnership: ls.src.PreserveOwnership,
+		SymlinkMode:       filesync.SymlinkMode(ls.src.SymlinkMode),
+		// retries wait for the session to reconnect for as long as the
+		// session group does for the first attempt
+		GetCaller: func(ctx context.Context) (session.Caller, error) {
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			return ls.sm.Get(ctx, sessionID, false)
+		},
 	}
 
-	if idmap := mount.IdentityMapping(); idmap != nil {
+	idmap := mount.IdentityMapping()
+	uids, gids := ls.src.UIDMap, ls.src.GIDMap
+	if idmap != nil || len(uids) > 0 || len(gids) > 0 {
 		opt.Filter = func(p string, stat *fstypes.Stat) bool {
+			if id, ok := uids[int(stat.Uid)]; ok {
+				stat.Uid = uint32(id)
+			}
+			if id, ok := gids[int(stat.Gid)]; ok {
+				stat.Gid = uint32(id)
+			}
+			if idmap == nil {
+				return true
+			}
 			identity, err := idmap.ToHost(idtools.Identity{
 				UID: int(stat.Uid),
 				GID: int(stat.Gid),
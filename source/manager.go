@@ -7,6 +7,7 @@ import (
 	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/solver"
+	digest "github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 )
 
@@ -20,6 +21,13 @@ type SourceInstance interface {
 	Snapshot(ctx context.Context, g session.Group) (cache.ImmutableRef, error)
 }
 
+// DigestResolver is implemented by the source instances that resolve their
+// identifier to a digest, like an image tag to a manifest digest or a Git
+// reference to a commit. The digest is known after CacheKey has returned.
+type DigestResolver interface {
+	ResolvedDigest() digest.Digest
+}
+
 type Manager struct {
 	mu      sync.Mutex
 	sources map[string]Source
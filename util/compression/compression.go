@@ -99,28 +99,61 @@ func detectCompressionType(cr io.Reader) (Type, error) {
 }
 
 var toDockerLayerType = map[string]string{
-	ocispec.MediaTypeImageLayer:                   images.MediaTypeDockerSchema2Layer,
-	images.MediaTypeDockerSchema2Layer:            images.MediaTypeDockerSchema2Layer,
-	ocispec.MediaTypeImageLayerGzip:               images.MediaTypeDockerSchema2LayerGzip,
-	images.MediaTypeDockerSchema2LayerGzip:        images.MediaTypeDockerSchema2LayerGzip,
-	images.MediaTypeDockerSchema2LayerForeign:     images.MediaTypeDockerSchema2Layer,
-	images.MediaTypeDockerSchema2LayerForeignGzip: images.MediaTypeDockerSchema2LayerGzip,
+	ocispec.MediaTypeImageLayer:                     images.MediaTypeDockerSchema2Layer,
+	images.MediaTypeDockerSchema2Layer:              images.MediaTypeDockerSchema2Layer,
+	ocispec.MediaTypeImageLayerGzip:                 images.MediaTypeDockerSchema2LayerGzip,
+	images.MediaTypeDockerSchema2LayerGzip:          images.MediaTypeDockerSchema2LayerGzip,
+	images.MediaTypeDockerSchema2LayerForeign:       images.MediaTypeDockerSchema2Layer,
+	images.MediaTypeDockerSchema2LayerForeignGzip:   images.MediaTypeDockerSchema2LayerGzip,
+	ocispec.MediaTypeImageLayerNonDistributable:     images.MediaTypeDockerSchema2Layer,
+	ocispec.MediaTypeImageLayerNonDistributableGzip: images.MediaTypeDockerSchema2LayerGzip,
 }
 
 var toOCILayerType = map[string]string{
-	ocispec.MediaTypeImageLayer:                   ocispec.MediaTypeImageLayer,
-	images.MediaTypeDockerSchema2Layer:            ocispec.MediaTypeImageLayer,
-	ocispec.MediaTypeImageLayerGzip:               ocispec.MediaTypeImageLayerGzip,
-	images.MediaTypeDockerSchema2LayerGzip:        ocispec.MediaTypeImageLayerGzip,
-	images.MediaTypeDockerSchema2LayerForeign:     ocispec.MediaTypeImageLayer,
-	images.MediaTypeDockerSchema2LayerForeignGzip: ocispec.MediaTypeImageLayerGzip,
+	ocispec.MediaTypeImageLayer:                     ocispec.MediaTypeImageLayer,
+	images.MediaTypeDockerSchema2Layer:              ocispec.MediaTypeImageLayer,
+	ocispec.MediaTypeImageLayerGzip:                 ocispec.MediaTypeImageLayerGzip,
+	images.MediaTypeDockerSchema2LayerGzip:          ocispec.MediaTypeImageLayerGzip,
+	images.MediaTypeDockerSchema2LayerForeign:       ocispec.MediaTypeImageLayer,
+	images.MediaTypeDockerSchema2LayerForeignGzip:   ocispec.MediaTypeImageLayerGzip,
+	ocispec.MediaTypeImageLayerNonDistributable:     ocispec.MediaTypeImageLayer,
+	ocispec.MediaTypeImageLayerNonDistributableGzip: ocispec.MediaTypeImageLayerGzip,
 }
 
-func convertLayerMediaType(mediaType string, oci bool) string {
+// toDockerForeignLayerType and toOCINonDistributableLayerType are used for
+// layers that have urls set and can't be pushed to registries
+var toDockerForeignLayerType = map[string]string{
+	ocispec.MediaTypeImageLayer:                     images.MediaTypeDockerSchema2LayerForeign,
+	images.MediaTypeDockerSchema2Layer:              images.MediaTypeDockerSchema2LayerForeign,
+	ocispec.MediaTypeImageLayerGzip:                 images.MediaTypeDockerSchema2LayerForeignGzip,
+	images.MediaTypeDockerSchema2LayerGzip:          images.MediaTypeDockerSchema2LayerForeignGzip,
+	images.MediaTypeDockerSchema2LayerForeign:       images.MediaTypeDockerSchema2LayerForeign,
+	images.MediaTypeDockerSchema2LayerForeignGzip:   images.MediaTypeDockerSchema2LayerForeignGzip,
+	ocispec.MediaTypeImageLayerNonDistributable:     images.MediaTypeDockerSchema2LayerForeign,
+	ocispec.MediaTypeImageLayerNonDistributableGzip: images.MediaTypeDockerSchema2LayerForeignGzip,
+}
+
+var toOCINonDistributableLayerType = map[string]string{
+	ocispec.MediaTypeImageLayer:                     ocispec.MediaTypeImageLayerNonDistributable,
+	images.MediaTypeDockerSchema2Layer:              ocispec.MediaTypeImageLayerNonDistributable,
+	ocispec.MediaTypeImageLayerGzip:                 ocispec.MediaTypeImageLayerNonDistributableGzip,
+	images.MediaTypeDockerSchema2LayerGzip:          ocispec.MediaTypeImageLayerNonDistributableGzip,
+	images.MediaTypeDockerSchema2LayerForeign:       ocispec.MediaTypeImageLayerNonDistributable,
+	images.MediaTypeDockerSchema2LayerForeignGzip:   ocispec.MediaTypeImageLayerNonDistributableGzip,
+	ocispec.MediaTypeImageLayerNonDistributable:     ocispec.MediaTypeImageLayerNonDistributable,
+	ocispec.MediaTypeImageLayerNonDistributableGzip: ocispec.MediaTypeImageLayerNonDistributableGzip,
+}
+
+func convertLayerMediaType(mediaType string, oci, foreign bool) string {
 	var converted string
-	if oci {
+	switch {
+	case oci && foreign:
+		converted = toOCINonDistributableLayerType[mediaType]
+	case oci:
 		converted = toOCILayerType[mediaType]
-	} else {
+	case foreign:
+		converted = toDockerForeignLayerType[mediaType]
+	default:
 		converted = toDockerLayerType[mediaType]
 	}
 	if converted == "" {
@@ -133,7 +166,7 @@ func convertLayerMediaType(mediaType string, oci bool) string {
 func ConvertAllLayerMediaTypes(oci bool, descs ...ocispec.Descriptor) []ocispec.Descriptor {
 	var converted []ocispec.Descriptor
 	for _, desc := range descs {
-		desc.MediaType = convertLayerMediaType(desc.MediaType, oci)
+		desc.MediaType = convertLayerMediaType(desc.MediaType, oci, len(desc.URLs) > 0)
 		converted = append(converted, desc)
 	}
 	return converted
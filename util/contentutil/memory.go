@@ -0,0 +1,313 @@
+package contentutil
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/filters"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// NewMemoryStore returns a content store that keeps the blobs and their
+// labels in memory, e.g. for receiving an exported image in tests
+func NewMemoryStore() content.Store {
+	return &memoryStore{
+		blobs:   map[digest.Digest][]byte{},
+		infos:   map[digest.Digest]content.Info{},
+		writers: map[string]*memoryWriter{},
+	}
+}
+
+type memoryStore struct {
+	mu      sync.Mutex
+	blobs   map[digest.Digest][]byte
+	infos   map[digest.Digest]content.Info
+	writers map[string]*memoryWriter
+}
+
+func (s *memoryStore) Info(ctx context.Context, dgst digest.Digest) (content.Info, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, ok := s.infos[dgst]
+	if !ok {
+		return content.Info{}, errors.Wrapf(errdefs.ErrNotFound, "content %v", dgst)
+	}
+	return copyInfo(info), nil
+}
+
+func (s *memoryStore) Update(ctx context.Context, info content.Info, fieldpaths ...string) (content.Info, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur, ok := s.infos[info.Digest]
+	if !ok {
+		return content.Info{}, errors.Wrapf(errdefs.ErrNotFound, "content %v", info.Digest)
+	}
+	cur = copyInfo(cur)
+	if len(fieldpaths) == 0 {
+		cur.Labels = info.Labels
+	}
+	for _, path := range fieldpaths {
+		switch {
+		case path == "labels":
+			cur.Labels = info.Labels
+		case strings.HasPrefix(path, "labels."):
+			k := strings.TrimPrefix(path, "labels.")
+			if cur.Labels == nil {
+				cur.Labels = map[string]string{}
+			}
+			if v, ok := info.Labels[k]; ok && v != "" {
+				cur.Labels[k] = v
+			} else {
+				delete(cur.Labels, k)
+			}
+		default:
+			return content.Info{}, errors.Wrapf(errdefs.ErrInvalidArgument, "cannot update %q field on content info %q", path, info.Digest)
+		}
+	}
+	cur.UpdatedAt = time.Now()
+	s.infos[info.Digest] = cur
+	return copyInfo(cur), nil
+}
+
+func (s *memoryStore) Walk(ctx context.Context, fn content.WalkFunc, fs ...string) error {
+	filter, err := filters.ParseAll(fs...)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	var infos []content.Info
+	for _, info := range s.infos {
+		if filter.Match(content.AdaptInfo(info)) {
+			infos = append(infos, copyInfo(info))
+		}
+	}
+	s.mu.Unlock()
+	for _, info := range infos {
+		if err := fn(info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, dgst digest.Digest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.infos[dgst]; !ok {
+		return errors.Wrapf(errdefs.ErrNotFound, "content %v", dgst)
+	}
+	delete(s.infos, dgst)
+	delete(s.blobs, dgst)
+	return nil
+}
+
+func (s *memoryStore) ReaderAt(ctx context.Context, desc ocispec.Descriptor) (content.ReaderAt, error) {
+	s.mu.Lock()
+	dt, ok := s.blobs[desc.Digest]
+	s.mu.Unlock()
+	if !ok {
+		return nil, errors.Wrapf(errdefs.ErrNotFound, "content %v", desc.Digest)
+	}
+	r := bytes.NewReader(dt)
+	return &readerAt{Reader: r, Closer: ioutil.NopCloser(r), size: int64(r.Len())}, nil
+}
+
+func (s *memoryStore) Status(ctx context.Context, ref string) (content.Status, error) {
+	s.mu.Lock()
+	w, ok := s.writers[ref]
+	s.mu.Unlock()
+	if !ok {
+		return content.Status{}, errors.Wrapf(errdefs.ErrNotFound, "ref %s", ref)
+	}
+	return w.Status()
+}
+
+func (s *memoryStore) ListStatuses(ctx context.Context, fs ...string) ([]content.Status, error) {
+	s.mu.Lock()
+	var writers []*memoryWriter
+	for _, w := range s.writers {
+		writers = append(writers, w)
+	}
+	s.mu.Unlock()
+	var statuses []content.Status
+	for _, w := range writers {
+		st, err := w.Status()
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses, nil
+}
+
+func (s *memoryStore) Abort(ctx context.Context, ref string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.writers[ref]; !ok {
+		return errors.Wrapf(errdefs.ErrNotFound, "ref %s", ref)
+	}
+	delete(s.writers, ref)
+	return nil
+}
+
+func (s *memoryStore) Writer(ctx context.Context, opts ...content.WriterOpt) (content.Writer, error) {
+	var wOpts content.WriterOpts
+	for _, opt := range opts {
+		if err := opt(&wOpts); err != nil {
+			return nil, err
+		}
+	}
+	if wOpts.Ref == "" {
+		return nil, errors.Wrap(errdefs.ErrInvalidArgument, "ref must not be empty")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if dgst := wOpts.Desc.Digest; dgst != "" {
+		if _, ok := s.infos[dgst]; ok {
+			return nil, errors.Wrapf(errdefs.ErrAlreadyExists, "content %v", dgst)
+		}
+	}
+	if _, ok := s.writers[wOpts.Ref]; ok {
+		return nil, errors.Wrapf(errdefs.ErrUnavailable, "ref %s locked", wOpts.Ref)
+	}
+	now := time.Now()
+	w := &memoryWriter{
+		store:     s,
+		ref:       wOpts.Ref,
+		total:     wOpts.Desc.Size,
+		expected:  wOpts.Desc.Digest,
+		digester:  digest.Canonical.Digester(),
+		startedAt: now,
+		updatedAt: now,
+	}
+	s.writers[wOpts.Ref] = w
+	return w, nil
+}
+
+type memoryWriter struct {
+	store     *memoryStore
+	mu        sync.Mutex
+	ref       string
+	buf       bytes.Buffer
+	total     int64
+	expected  digest.Digest
+	digester  digest.Digester
+	startedAt time.Time
+	updatedAt time.Time
+	closed    bool
+}
+
+func (w *memoryWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, errors.Errorf("write to closed writer %s", w.ref)
+	}
+	n, err := w.buf.Write(p)
+	w.digester.Hash().Write(p[:n])
+	w.updatedAt = time.Now()
+	return n, err
+}
+
+func (w *memoryWriter) Status() (content.Status, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return content.Status{
+		Ref:       w.ref,
+		Offset:    int64(w.buf.Len()),
+		Total:     w.total,
+		Expected:  w.expected,
+		StartedAt: w.startedAt,
+		UpdatedAt: w.updatedAt,
+	}, nil
+}
+
+func (w *memoryWriter) Digest() digest.Digest {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.digester.Digest()
+}
+
+func (w *memoryWriter) Truncate(size int64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if size != 0 {
+		return errors.New("Truncate: unsupported size")
+	}
+	w.buf.Reset()
+	w.digester.Hash().Reset()
+	return nil
+}
+
+func (w *memoryWriter) Commit(ctx context.Context, size int64, expected digest.Digest, opts ...content.Opt) error {
+	var base content.Info
+	for _, opt := range opts {
+		if err := opt(&base); err != nil {
+			return err
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return errors.Errorf("can't commit already committed or closed writer %s", w.ref)
+	}
+	if s := int64(w.buf.Len()); size > 0 && size != s {
+		return errors.Wrapf(errdefs.ErrFailedPrecondition, "unexpected commit size %d, expected %d", s, size)
+	}
+	dgst := w.digester.Digest()
+	if expected != "" && expected != dgst {
+		return errors.Wrapf(errdefs.ErrFailedPrecondition, "unexpected commit digest %s, expected %s", dgst, expected)
+	}
+
+	s := w.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w.closed = true
+	delete(s.writers, w.ref)
+	if _, ok := s.infos[dgst]; ok {
+		return errors.Wrapf(errdefs.ErrAlreadyExists, "content %v", dgst)
+	}
+	now := time.Now()
+	s.blobs[dgst] = append([]byte(nil), w.buf.Bytes()...)
+	s.infos[dgst] = content.Info{
+		Digest:    dgst,
+		Size:      int64(w.buf.Len()),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Labels:    base.Labels,
+	}
+	return nil
+}
+
+func (w *memoryWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	w.store.mu.Lock()
+	delete(w.store.writers, w.ref)
+	w.store.mu.Unlock()
+	return nil
+}
+
+func copyInfo(info content.Info) content.Info {
+	if info.Labels != nil {
+		labels := make(map[string]string, len(info.Labels))
+		for k, v := range info.Labels {
+			labels[k] = v
+		}
+		info.Labels = labels
+	}
+	return info
+}
@@ -0,0 +1,70 @@
+package contentutil
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.TODO()
+
+	s := NewMemoryStore()
+
+	dgst := digest.FromBytes([]byte("foo"))
+	err := content.WriteBlob(ctx, s, "foo", bytes.NewBuffer([]byte("foo")), ocispec.Descriptor{Size: 3, Digest: dgst}, content.WithLabels(map[string]string{"a": "b"}))
+	require.NoError(t, err)
+
+	// writing existing content is a no-op
+	err = content.WriteBlob(ctx, s, "foo", bytes.NewBuffer([]byte("foo")), ocispec.Descriptor{Size: 3, Digest: dgst})
+	require.NoError(t, err)
+
+	err = content.WriteBlob(ctx, s, "bar", bytes.NewBuffer([]byte("bar")), ocispec.Descriptor{Size: 3, Digest: digest.FromBytes([]byte("baz"))})
+	require.Error(t, err)
+
+	dt, err := content.ReadBlob(ctx, s, ocispec.Descriptor{Digest: dgst})
+	require.NoError(t, err)
+	require.Equal(t, "foo", string(dt))
+
+	info, err := s.Info(ctx, dgst)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), info.Size)
+	require.Equal(t, map[string]string{"a": "b"}, info.Labels)
+
+	info, err = s.Update(ctx, content.Info{Digest: dgst, Labels: map[string]string{"c": "d"}}, "labels.c", "labels.a")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"c": "d"}, info.Labels)
+
+	var walked []digest.Digest
+	err = s.Walk(ctx, func(info content.Info) error {
+		walked = append(walked, info.Digest)
+		return nil
+	}, "labels.c==d")
+	require.NoError(t, err)
+	require.Equal(t, []digest.Digest{dgst}, walked)
+
+	statuses, err := s.ListStatuses(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(statuses))
+
+	w, err := s.Writer(ctx, content.WithRef("baz"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("ba"))
+	require.NoError(t, err)
+	st, err := s.Status(ctx, "baz")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), st.Offset)
+	require.NoError(t, s.Abort(ctx, "baz"))
+
+	require.NoError(t, s.Delete(ctx, dgst))
+	_, err = content.ReadBlob(ctx, s, ocispec.Descriptor{Digest: dgst})
+	require.True(t, errors.Is(err, errdefs.ErrNotFound))
+}
@@ -0,0 +1,86 @@
+package contentutil
+
+import (
+	"bufio"
+	"context"
+
+	"github.com/containerd/containerd/content"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// MaxWriteBufferSize is the largest write buffer size of WithWriteBuffer
+const MaxWriteBufferSize = 64 << 20
+
+// WithWriteBuffer returns a content store that buffers the writes of its
+// writers in memory up to size bytes before passing them to store. Zero size
+// returns store unchanged.
+func WithWriteBuffer(store content.Store, size int) (content.Store, error) {
+	if size < 0 || size > MaxWriteBufferSize {
+		return nil, errors.Errorf("invalid write buffer size %d, must be between 0 and %d", size, MaxWriteBufferSize)
+	}
+	if size == 0 {
+		return store, nil
+	}
+	return &writeBufferStore{Store: store, size: size}, nil
+}
+
+type writeBufferStore struct {
+	content.Store
+	size int
+}
+
+func (s *writeBufferStore) Writer(ctx context.Context, opts ...content.WriterOpt) (content.Writer, error) {
+	w, err := s.Store.Writer(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &writeBufferWriter{Writer: w, buf: bufio.NewWriterSize(w, s.size)}, nil
+}
+
+// writeBufferWriter flushes the buffer before every call that depends on the
+// data written so far
+type writeBufferWriter struct {
+	content.Writer
+	buf *bufio.Writer
+}
+
+func (w *writeBufferWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *writeBufferWriter) Digest() digest.Digest {
+	if err := w.buf.Flush(); err != nil {
+		return ""
+	}
+	return w.Writer.Digest()
+}
+
+func (w *writeBufferWriter) Status() (content.Status, error) {
+	if err := w.buf.Flush(); err != nil {
+		return content.Status{}, err
+	}
+	return w.Writer.Status()
+}
+
+func (w *writeBufferWriter) Truncate(size int64) error {
+	if err := w.buf.Flush(); err != nil {
+		return err
+	}
+	return w.Writer.Truncate(size)
+}
+
+func (w *writeBufferWriter) Commit(ctx context.Context, size int64, expected digest.Digest, opts ...content.Opt) error {
+	if err := w.buf.Flush(); err != nil {
+		return err
+	}
+	return w.Writer.Commit(ctx, size, expected, opts...)
+}
+
+func (w *writeBufferWriter) Close() error {
+	err := w.buf.Flush()
+	if err1 := w.Writer.Close(); err == nil {
+		err = err1
+	}
+	return err
+}
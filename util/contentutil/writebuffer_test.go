@@ -0,0 +1,68 @@
+package contentutil
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/content/local"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteBuffer(t *testing.T) {
+	t.Parallel()
+	ctx := context.TODO()
+
+	tmpdir, err := ioutil.TempDir("", "writebuffer")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	store, err := local.NewStore(tmpdir)
+	require.NoError(t, err)
+
+	_, err = WithWriteBuffer(store, -1)
+	require.Error(t, err)
+	_, err = WithWriteBuffer(store, MaxWriteBufferSize+1)
+	require.Error(t, err)
+
+	s, err := WithWriteBuffer(store, 0)
+	require.NoError(t, err)
+	require.Equal(t, store, s)
+
+	s, err = WithWriteBuffer(store, 16)
+	require.NoError(t, err)
+
+	dt := bytes.Repeat([]byte("0123456789"), 10)
+	dgst := digest.FromBytes(dt)
+
+	w, err := s.Writer(ctx, content.WithRef("foo"))
+	require.NoError(t, err)
+	_, err = w.Write(dt[:5])
+	require.NoError(t, err)
+
+	// short writes are buffered until the status is read
+	st, err := w.Status()
+	require.NoError(t, err)
+	require.Equal(t, int64(5), st.Offset)
+
+	_, err = w.Write(dt[5:])
+	require.NoError(t, err)
+	require.Equal(t, dgst, w.Digest())
+	require.NoError(t, w.Commit(ctx, int64(len(dt)), dgst))
+	require.NoError(t, w.Close())
+
+	out, err := content.ReadBlob(ctx, store, ocispec.Descriptor{Digest: dgst})
+	require.NoError(t, err)
+	require.Equal(t, dt, out)
+
+	err = content.WriteBlob(ctx, s, "bar", bytes.NewBuffer([]byte("bar")), ocispec.Descriptor{Size: 3, Digest: digest.FromBytes([]byte("bar"))})
+	require.NoError(t, err)
+	out, err = content.ReadBlob(ctx, store, ocispec.Descriptor{Digest: digest.FromBytes([]byte("bar"))})
+	require.NoError(t, err)
+	require.Equal(t, "bar", string(out))
+}
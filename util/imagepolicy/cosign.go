@@ -0,0 +1,104 @@
+package imagepolicy
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/remotes"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	annotationSignature   = "dev.cosignproject.cosign/signature"
+	annotationCertificate = "dev.sigstore.cosign/certificate"
+	annotationChain       = "dev.sigstore.cosign/chain"
+	annotationBundle      = "dev.sigstore.cosign/bundle"
+	annotationTimestamp   = "dev.sigstore.cosign/rfc3161timestamp"
+
+	// maxSignatureSize limits the size of the signature manifest and payloads
+	maxSignatureSize = 1 << 20
+)
+
+// FetchSignatures returns a FetchFunc that reads the cosign signatures of a
+// manifest from the "<algorithm>-<hex>.sig" tag of the repository. A manifest
+// without the tag has no signatures.
+func FetchSignatures(resolver remotes.Resolver) FetchFunc {
+	return func(ctx context.Context, name string, dgst digest.Digest) ([]*Signature, error) {
+		ref := fmt.Sprintf("%s:%s-%s.sig", name, dgst.Algorithm(), dgst.Hex())
+		n, desc, err := resolver.Resolve(ctx, ref)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		fetcher, err := resolver.Fetcher(ctx, n)
+		if err != nil {
+			return nil, err
+		}
+		dt, err := fetch(ctx, fetcher, desc)
+		if err != nil {
+			return nil, err
+		}
+		var mfst ocispec.Manifest
+		if err := json.Unmarshal(dt, &mfst); err != nil {
+			return nil, errors.Wrapf(err, "invalid signature manifest %s", ref)
+		}
+
+		var sigs []*Signature
+		for _, l := range mfst.Layers {
+			b64, ok := l.Annotations[annotationSignature]
+			if !ok {
+				continue
+			}
+			sig, err := base64.StdEncoding.DecodeString(b64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid signature in %s", ref)
+			}
+			dt, err := fetch(ctx, fetcher, l)
+			if err != nil {
+				return nil, err
+			}
+			sigs = append(sigs, &Signature{
+				Payload:     dt,
+				Signature:   sig,
+				Certificate: []byte(l.Annotations[annotationCertificate]),
+				Chain:       []byte(l.Annotations[annotationChain]),
+				Bundle:      []byte(l.Annotations[annotationBundle]),
+				Timestamp:   []byte(l.Annotations[annotationTimestamp]),
+			})
+		}
+		return sigs, nil
+	}
+}
+
+func fetch(ctx context.Context, fetcher remotes.Fetcher, desc ocispec.Descriptor) ([]byte, error) {
+	// the digest of a descriptor of the registry is only trusted once it
+	// was validated, unknown algorithms can't be verified
+	if err := desc.Digest.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid digest of signature blob %s", desc.Digest)
+	}
+	if desc.Size > maxSignatureSize {
+		return nil, errors.Errorf("signature blob %s is too big", desc.Digest)
+	}
+	rc, err := fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	dt, err := ioutil.ReadAll(io.LimitReader(rc, maxSignatureSize))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if desc.Digest.Algorithm().FromBytes(dt) != desc.Digest {
+		return nil, errors.Errorf("digest mismatch of signature blob %s", desc.Digest)
+	}
+	return dt, nil
+}
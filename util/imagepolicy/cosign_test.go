@@ -0,0 +1,44 @@
+package imagepolicy
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchDigest(t *testing.T) {
+	t.Parallel()
+
+	dt := []byte("signature")
+	fetcher := testFetcher(dt)
+	ctx := context.TODO()
+
+	out, err := fetch(ctx, fetcher, ocispec.Descriptor{Digest: digest.FromBytes(dt), Size: int64(len(dt))})
+	require.NoError(t, err)
+	require.Equal(t, dt, out)
+
+	_, err = fetch(ctx, fetcher, ocispec.Descriptor{Digest: digest.FromString("other"), Size: int64(len(dt))})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "digest mismatch")
+
+	// digests with unknown algorithms are rejected instead of panicking
+	_, err = fetch(ctx, fetcher, ocispec.Descriptor{Digest: digest.Digest("bogus:0123456789abcdef"), Size: int64(len(dt))})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid digest")
+
+	_, err = fetch(ctx, fetcher, ocispec.Descriptor{Digest: digest.Digest("sha256:abc"), Size: int64(len(dt))})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid digest")
+}
+
+type testFetcher []byte
+
+func (f testFetcher) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(f)), nil
+}
@@ -0,0 +1,247 @@
+package imagepolicy
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path"
+
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ModeKeyed requires a signature made with one of the keys of the rule
+	ModeKeyed = "keyed"
+	// ModeKeyless requires a signature made with a certificate issued by one
+	// of the roots of the rule to one of the identities of the rule, while
+	// the certificate was valid. The time of the signature is proven by an
+	// entry of a transparency log or by a timestamp authority.
+	ModeKeyless = "keyless"
+	// ModeNone doesn't verify the images of the rule
+	ModeNone = "none"
+)
+
+// Rule is the signature policy of the images with names matching Images
+type Rule struct {
+	// Images is a pattern of image names, e.g. "docker.io/library/*".
+	// Patterns without wildcards are normalized like image names.
+	Images string `toml:"images"`
+	// Mode is keyed, keyless or none
+	Mode string `toml:"mode"`
+	// Keys are the paths of the PEM encoded public keys of keyed rules
+	Keys []string `toml:"keys"`
+	// Roots are the paths of the PEM encoded CA certificates of keyless
+	// rules
+	Roots []string `toml:"roots"`
+	// Identities are the signers allowed by keyless rules
+	Identities []Identity `toml:"identities"`
+	// TransparencyLogKeys are the paths of the PEM encoded public keys of
+	// the transparency logs (Rekor) trusted to prove the time of keyless
+	// signatures
+	TransparencyLogKeys []string `toml:"transparencyLogKeys"`
+	// TimestampRoots are the paths of the PEM encoded CA certificates of the
+	// RFC 3161 timestamp authorities trusted to prove the time of keyless
+	// signatures
+	TimestampRoots []string `toml:"timestampRoots"`
+}
+
+// PEMRule is a Rule with the contents of the keys and certificates instead of
+// their paths. It is the form of the rules sent by the clients.
+type PEMRule struct {
+	Images              string
+	Mode                string
+	Keys                [][]byte
+	Roots               [][]byte
+	Identities          []Identity
+	TransparencyLogKeys [][]byte
+	TimestampRoots      [][]byte
+}
+
+// Load reads the keys and certificates of the rule
+func (r Rule) Load() (PEMRule, error) {
+	out := PEMRule{Images: r.Images, Mode: r.Mode, Identities: r.Identities}
+	for _, f := range []struct {
+		paths []string
+		out   *[][]byte
+	}{
+		{r.Keys, &out.Keys},
+		{r.Roots, &out.Roots},
+		{r.TransparencyLogKeys, &out.TransparencyLogKeys},
+		{r.TimestampRoots, &out.TimestampRoots},
+	} {
+		for _, p := range f.paths {
+			dt, err := ioutil.ReadFile(p)
+			if err != nil {
+				return PEMRule{}, errors.WithStack(err)
+			}
+			*f.out = append(*f.out, dt)
+		}
+	}
+	return out, nil
+}
+
+// Identity is a signer of keyless signatures. Issuer and Subject are patterns
+// matched against the OIDC issuer and the email or URI of the certificate.
+type Identity struct {
+	Issuer  string `toml:"issuer"`
+	Subject string `toml:"subject"`
+}
+
+// Signature is a signature of an image in the format of cosign
+type Signature struct {
+	// Payload is the signed payload that contains the digest of the image
+	Payload []byte
+	// Signature is the raw signature of the payload
+	Signature []byte
+	// Certificate is the PEM encoded certificate of keyless signatures
+	Certificate []byte
+	// Chain is the PEM encoded intermediate certificates of Certificate
+	Chain []byte
+	// Bundle is the JSON encoded entry of the signature in a transparency
+	// log
+	Bundle []byte
+	// Timestamp is the JSON encoded RFC 3161 timestamp of the signature
+	Timestamp []byte
+}
+
+// Verifier checks the signature of a payload
+type Verifier interface {
+	Verify(sig *Signature) error
+}
+
+// FetchFunc returns the signatures of the manifest dgst of the image name
+type FetchFunc func(ctx context.Context, name string, dgst digest.Digest) ([]*Signature, error)
+
+// Policy is the signature policy of the daemon or of a build. The first rule
+// that matches an image applies. Images that match no rule are not verified.
+type Policy struct {
+	rules []*rule
+	dgst  digest.Digest
+}
+
+type rule struct {
+	pattern  string
+	verifier Verifier
+}
+
+// New returns the policy of the rules
+func New(rules []Rule) (*Policy, error) {
+	pemRules := make([]PEMRule, len(rules))
+	for i, r := range rules {
+		pr, err := r.Load()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid rule for %s", r.Images)
+		}
+		pemRules[i] = pr
+	}
+	return NewFromPEM(pemRules)
+}
+
+// NewFromPEM returns the policy of rules with the contents of their keys and
+// certificates
+func NewFromPEM(rules []PEMRule) (*Policy, error) {
+	dt, err := json.Marshal(rules)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	p := &Policy{dgst: digest.FromBytes(dt)}
+	for _, r := range rules {
+		pattern := r.Images
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return nil, errors.Errorf("invalid image pattern %q", pattern)
+		}
+		if named, err := reference.ParseNormalizedNamed(pattern); err == nil {
+			pattern = named.Name()
+		}
+		var v Verifier
+		switch r.Mode {
+		case ModeKeyed:
+			kv, err := newKeyedVerifier(r.Keys)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid rule for %s", r.Images)
+			}
+			v = kv
+		case ModeKeyless:
+			kv, err := newKeylessVerifier(r.Roots, r.Identities, r.TransparencyLogKeys, r.TimestampRoots)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid rule for %s", r.Images)
+			}
+			v = kv
+		case ModeNone:
+		default:
+			return nil, errors.Errorf("invalid mode %q of rule for %s", r.Mode, r.Images)
+		}
+		p.rules = append(p.rules, &rule{pattern: pattern, verifier: v})
+	}
+	return p, nil
+}
+
+// Enabled returns true if the policy has rules
+func (p *Policy) Enabled() bool {
+	return p != nil && len(p.rules) > 0
+}
+
+// Digest identifies the rules of the policy
+func (p *Policy) Digest() digest.Digest {
+	return p.dgst
+}
+
+type policyKey struct{}
+
+// WithPolicy returns a context that makes the image sources verify the images
+// with the policy of the build in addition to the policy of the daemon
+func WithPolicy(ctx context.Context, p *Policy) context.Context {
+	if !p.Enabled() {
+		return ctx
+	}
+	return context.WithValue(ctx, policyKey{}, p)
+}
+
+// FromContext returns the policy set with WithPolicy
+func FromContext(ctx context.Context) *Policy {
+	p, _ := ctx.Value(policyKey{}).(*Policy)
+	return p
+}
+
+// Verify checks that one of the manifests dgsts of the image name has a
+// signature allowed by the policy
+func (p *Policy) Verify(ctx context.Context, name string, dgsts []digest.Digest, fetch FetchFunc) error {
+	if !p.Enabled() {
+		return nil
+	}
+	var r *rule
+	for _, rr := range p.rules {
+		if ok, _ := path.Match(rr.pattern, name); ok {
+			r = rr
+			break
+		}
+	}
+	if r == nil || r.verifier == nil {
+		return nil
+	}
+
+	var lastErr error
+	for _, dgst := range dgsts {
+		sigs, err := fetch(ctx, name, dgst)
+		if err != nil {
+			return errors.Wrapf(err, "failed to fetch signatures of %s@%s", name, dgst)
+		}
+		for _, sig := range sigs {
+			if err := checkPayload(sig.Payload, dgst); err != nil {
+				lastErr = err
+				continue
+			}
+			if err := r.verifier.Verify(sig); err != nil {
+				lastErr = err
+				continue
+			}
+			return nil
+		}
+	}
+	if lastErr != nil {
+		return errors.Wrapf(lastErr, "image %s has no valid signature", name)
+	}
+	return errors.Errorf("image %s is not signed", name)
+}
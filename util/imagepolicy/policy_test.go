@@ -0,0 +1,428 @@
+package imagepolicy
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyKeyed(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "imagepolicy")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	key := newKey(t)
+	other := newKey(t)
+	keyPath := writePEM(t, dir, "key.pub", publicKeyPEM(t, key))
+
+	p, err := New([]Rule{
+		{Images: "docker.io/library/busybox", Mode: ModeNone},
+		{Images: "docker.io/library/*", Mode: ModeKeyed, Keys: []string{keyPath}},
+	})
+	require.NoError(t, err)
+	require.True(t, p.Enabled())
+
+	dgst := digest.FromString("manifest")
+	ctx := context.TODO()
+
+	err = p.Verify(ctx, "docker.io/library/alpine", []digest.Digest{dgst}, fetchFunc(sign(t, key, dgst)))
+	require.NoError(t, err)
+
+	err = p.Verify(ctx, "docker.io/library/alpine", []digest.Digest{dgst}, fetchFunc(sign(t, other, dgst)))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "doesn't match any of the keys")
+
+	err = p.Verify(ctx, "docker.io/library/alpine", []digest.Digest{dgst}, fetchFunc(sign(t, key, digest.FromString("other"))))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "signature is for")
+
+	err = p.Verify(ctx, "docker.io/library/alpine", []digest.Digest{dgst}, fetchFunc())
+	require.EqualError(t, err, "image docker.io/library/alpine is not signed")
+
+	// one of the manifests of an index is enough
+	err = p.Verify(ctx, "docker.io/library/alpine", []digest.Digest{digest.FromString("index"), dgst}, func(ctx context.Context, name string, d digest.Digest) ([]*Signature, error) {
+		if d != dgst {
+			return nil, nil
+		}
+		return []*Signature{sign(t, key, dgst)}, nil
+	})
+	require.NoError(t, err)
+
+	// first matching rule applies
+	err = p.Verify(ctx, "docker.io/library/busybox", []digest.Digest{dgst}, fetchFunc())
+	require.NoError(t, err)
+
+	// images matching no rule are not verified
+	err = p.Verify(ctx, "docker.io/myorg/app", []digest.Digest{dgst}, fetchFunc())
+	require.NoError(t, err)
+
+	var nilPolicy *Policy
+	require.False(t, nilPolicy.Enabled())
+	require.NoError(t, nilPolicy.Verify(ctx, "docker.io/library/alpine", []digest.Digest{dgst}, fetchFunc()))
+}
+
+func TestPolicyKeyless(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "imagepolicy")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	caKey := newKey(t)
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, ca, ca, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err = x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+	rootPath := writePEM(t, dir, "root.pem", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}))
+	logKey := newKey(t)
+	logKeyPath := writePEM(t, dir, "rekor.pub", publicKeyPEM(t, logKey))
+
+	p, err := New([]Rule{{
+		Images: "example.com/myorg/*",
+		Mode:   ModeKeyless,
+		Roots:  []string{rootPath},
+		Identities: []Identity{
+			{Issuer: "https://issuer.example.com", Subject: "https://example.com/myorg/*"},
+		},
+		TransparencyLogKeys: []string{logKeyPath},
+	}})
+	require.NoError(t, err)
+
+	// the certificate is expired, signatures are verified at the time they
+	// were added to the log
+	notBefore := time.Now().Add(-30 * time.Minute)
+	notAfter := notBefore.Add(10 * time.Minute)
+	signedAt := notBefore.Add(time.Minute)
+
+	newCert := func(subject, issuer string) (*ecdsa.PrivateKey, []byte) {
+		key := newKey(t)
+		u, err := url.Parse(subject)
+		require.NoError(t, err)
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(2),
+			NotBefore:    notBefore,
+			NotAfter:     notAfter,
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+			URIs:         []*url.URL{u},
+			ExtraExtensions: []pkix.Extension{
+				{Id: oidcIssuerOID, Value: []byte(issuer)},
+			},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		return key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+
+	dgst := digest.FromString("manifest")
+	ctx := context.TODO()
+
+	signKeyless := func(key *ecdsa.PrivateKey, cert []byte, at time.Time) *Signature {
+		sig := sign(t, key, dgst)
+		sig.Certificate = cert
+		sig.Bundle = newBundle(t, logKey, sig, at)
+		return sig
+	}
+
+	key, cert := newCert("https://example.com/myorg/repo", "https://issuer.example.com")
+	err = p.Verify(ctx, "example.com/myorg/app", []digest.Digest{dgst}, fetchFunc(signKeyless(key, cert, signedAt)))
+	require.NoError(t, err)
+
+	// signatures made after the certificate expired are rejected
+	err = p.Verify(ctx, "example.com/myorg/app", []digest.Digest{dgst}, fetchFunc(signKeyless(key, cert, time.Now())))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid signature certificate")
+
+	err = p.Verify(ctx, "example.com/myorg/app", []digest.Digest{dgst}, fetchFunc(signKeyless(key, cert, notBefore.Add(-time.Minute))))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid signature certificate")
+
+	// the time of the signature must be proven
+	sig := signKeyless(key, cert, signedAt)
+	sig.Bundle = nil
+	err = p.Verify(ctx, "example.com/myorg/app", []digest.Digest{dgst}, fetchFunc(sig))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "signature time is not proven")
+
+	sig = signKeyless(key, cert, signedAt)
+	sig.Bundle = newBundle(t, newKey(t), sig, signedAt)
+	err = p.Verify(ctx, "example.com/myorg/app", []digest.Digest{dgst}, fetchFunc(sig))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not signed by a trusted log")
+
+	sig = signKeyless(key, cert, signedAt)
+	sig.Bundle = signKeyless(key, cert, signedAt).Bundle
+	err = p.Verify(ctx, "example.com/myorg/app", []digest.Digest{dgst}, fetchFunc(sig))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "entry is for another signature")
+
+	key, cert = newCert("https://example.com/other/repo", "https://issuer.example.com")
+	err = p.Verify(ctx, "example.com/myorg/app", []digest.Digest{dgst}, fetchFunc(signKeyless(key, cert, signedAt)))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not allowed")
+
+	key, cert = newCert("https://example.com/myorg/repo", "https://other.example.com")
+	err = p.Verify(ctx, "example.com/myorg/app", []digest.Digest{dgst}, fetchFunc(signKeyless(key, cert, signedAt)))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not allowed")
+
+	// signature made with another key than the one of the certificate
+	_, cert = newCert("https://example.com/myorg/repo", "https://issuer.example.com")
+	err = p.Verify(ctx, "example.com/myorg/app", []digest.Digest{dgst}, fetchFunc(signKeyless(newKey(t), cert, signedAt)))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid signature")
+
+	sig = sign(t, key, dgst)
+	err = p.Verify(ctx, "example.com/myorg/app", []digest.Digest{dgst}, fetchFunc(sig))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "has no certificate")
+
+	// timestamps of a timestamp authority prove the time too
+	tsaKey := newKey(t)
+	tsa := &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "test tsa"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping},
+	}
+	tsaDER, err := x509.CreateCertificate(rand.Reader, tsa, ca, &tsaKey.PublicKey, caKey)
+	require.NoError(t, err)
+	tsa, err = x509.ParseCertificate(tsaDER)
+	require.NoError(t, err)
+
+	p, err = New([]Rule{{
+		Images: "example.com/myorg/*",
+		Mode:   ModeKeyless,
+		Roots:  []string{rootPath},
+		Identities: []Identity{
+			{Issuer: "https://issuer.example.com", Subject: "https://example.com/myorg/*"},
+		},
+		TimestampRoots: []string{rootPath},
+	}})
+	require.NoError(t, err)
+
+	key, cert = newCert("https://example.com/myorg/repo", "https://issuer.example.com")
+	sig = sign(t, key, dgst)
+	sig.Certificate = cert
+	sig.Timestamp = newTimestamp(t, tsaKey, tsa, sig.Signature, signedAt)
+	err = p.Verify(ctx, "example.com/myorg/app", []digest.Digest{dgst}, fetchFunc(sig))
+	require.NoError(t, err)
+
+	sig.Timestamp = newTimestamp(t, tsaKey, tsa, sig.Signature, time.Now())
+	err = p.Verify(ctx, "example.com/myorg/app", []digest.Digest{dgst}, fetchFunc(sig))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid signature certificate")
+
+	sig.Timestamp = newTimestamp(t, tsaKey, tsa, []byte("other"), signedAt)
+	err = p.Verify(ctx, "example.com/myorg/app", []digest.Digest{dgst}, fetchFunc(sig))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "timestamp is for another signature")
+
+	// timestamps signed by another key than the one of the certificate
+	sig.Timestamp = newTimestamp(t, newKey(t), tsa, sig.Signature, signedAt)
+	err = p.Verify(ctx, "example.com/myorg/app", []digest.Digest{dgst}, fetchFunc(sig))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid timestamp signature")
+}
+
+func TestPolicyInvalidRules(t *testing.T) {
+	t.Parallel()
+
+	_, err := New([]Rule{{Images: "docker.io/library/*", Mode: "signed"}})
+	require.Error(t, err)
+	_, err = New([]Rule{{Images: "", Mode: ModeNone}})
+	require.Error(t, err)
+	_, err = New([]Rule{{Images: "[", Mode: ModeNone}})
+	require.Error(t, err)
+	_, err = New([]Rule{{Images: "docker.io/library/*", Mode: ModeKeyed}})
+	require.Error(t, err)
+	_, err = New([]Rule{{Images: "docker.io/library/*", Mode: ModeKeyed, Keys: []string{"/does/not/exist"}}})
+	require.Error(t, err)
+	_, err = New([]Rule{{Images: "docker.io/library/*", Mode: ModeKeyless, Roots: []string{"/does/not/exist"}}})
+	require.Error(t, err)
+
+	// keyless rules need a proof of the time of the signatures
+	_, err = NewFromPEM([]PEMRule{{
+		Images:     "docker.io/library/*",
+		Mode:       ModeKeyless,
+		Roots:      [][]byte{[]byte("root")},
+		Identities: []Identity{{Issuer: "*", Subject: "*"}},
+	}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "requires transparency log keys or timestamp roots")
+
+	p, err := New(nil)
+	require.NoError(t, err)
+	require.False(t, p.Enabled())
+
+	// names without wildcards are normalized
+	p, err = New([]Rule{{Images: "alpine", Mode: ModeNone}})
+	require.NoError(t, err)
+	require.Equal(t, "docker.io/library/alpine", p.rules[0].pattern)
+}
+
+func fetchFunc(sigs ...*Signature) FetchFunc {
+	return func(ctx context.Context, name string, dgst digest.Digest) ([]*Signature, error) {
+		return sigs, nil
+	}
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func publicKeyPEM(t *testing.T, key *ecdsa.PrivateKey) []byte {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func writePEM(t *testing.T, dir, name string, dt []byte) string {
+	p := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(p, dt, 0600))
+	return p
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, dgst digest.Digest) *Signature {
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"example"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, dgst))
+	h := sha256.Sum256(payload)
+	r, s, err := ecdsa.Sign(rand.Reader, key, h[:])
+	require.NoError(t, err)
+	sig, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	require.NoError(t, err)
+	return &Signature{Payload: payload, Signature: sig}
+}
+
+// newBundle returns the entry of a transparency log with the key for the
+// keyless signature sig added at the time at
+func newBundle(t *testing.T, key *ecdsa.PrivateKey, sig *Signature, at time.Time) []byte {
+	h := sha256.Sum256(sig.Payload)
+	body, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "0.0.1",
+		"kind":       "hashedrekord",
+		"spec": map[string]interface{}{
+			"data": map[string]interface{}{
+				"hash": map[string]string{"algorithm": "sha256", "value": hex.EncodeToString(h[:])},
+			},
+			"signature": map[string]interface{}{
+				"content":   sig.Signature,
+				"publicKey": map[string]interface{}{"content": sig.Certificate},
+			},
+		},
+	})
+	require.NoError(t, err)
+	id, err := logID(&key.PublicKey)
+	require.NoError(t, err)
+	b := bundle{Payload: bundlePayload{
+		Body:           base64.StdEncoding.EncodeToString(body),
+		IntegratedTime: at.Unix(),
+		LogID:          id,
+		LogIndex:       1,
+	}}
+	dt, err := json.Marshal(b.Payload)
+	require.NoError(t, err)
+	b.SignedEntryTimestamp = signBytes(t, key, crypto.SHA256, dt)
+	dt, err = json.Marshal(b)
+	require.NoError(t, err)
+	return dt
+}
+
+// newTimestamp returns an RFC 3161 timestamp of sig at the time at signed with
+// the key of the certificate of a timestamp authority
+func newTimestamp(t *testing.T, key *ecdsa.PrivateKey, cert *x509.Certificate, sig []byte, at time.Time) []byte {
+	sha256Alg := pkix.AlgorithmIdentifier{Algorithm: oidSHA256}
+	h := sha256.Sum256(sig)
+	info, err := asn1.Marshal(tstInfo{
+		Version:        1,
+		Policy:         asn1.ObjectIdentifier{1, 2, 3},
+		MessageImprint: messageImprint{HashAlgorithm: sha256Alg, HashedMessage: h[:]},
+		SerialNumber:   big.NewInt(1),
+		GenTime:        at.UTC().Truncate(time.Second),
+	})
+	require.NoError(t, err)
+
+	set := func(v interface{}) asn1.RawValue {
+		dt, err := asn1.Marshal(v)
+		require.NoError(t, err)
+		return asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: dt}
+	}
+	infoDigest := sha256.Sum256(info)
+	attrs, err := asn1.MarshalWithParams([]attribute{
+		{Type: oidContentType, Values: set(oidTSTInfo)},
+		{Type: oidMessageDigest, Values: set(infoDigest[:])},
+	}, "set")
+	require.NoError(t, err)
+	signature := signBytes(t, key, crypto.SHA256, attrs)
+	signedAttrs := append([]byte{}, attrs...)
+	signedAttrs[0] = 0xa0
+
+	sid, err := asn1.Marshal(issuerAndSerial{Issuer: asn1.RawValue{FullBytes: cert.RawIssuer}, Serial: cert.SerialNumber})
+	require.NoError(t, err)
+	sd, err := asn1.Marshal(signedData{
+		Version:          3,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{sha256Alg},
+		EncapContentInfo: encapContentInfo{EContentType: oidTSTInfo, EContent: info},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: cert.Raw},
+		SignerInfos: []signerInfo{{
+			Version:            1,
+			SID:                asn1.RawValue{FullBytes: sid},
+			DigestAlgorithm:    sha256Alg,
+			SignedAttrs:        asn1.RawValue{FullBytes: signedAttrs},
+			SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}},
+			Signature:          signature,
+		}},
+	})
+	require.NoError(t, err)
+	ci, err := asn1.Marshal(contentInfo{
+		ContentType: oidSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: sd},
+	})
+	require.NoError(t, err)
+	dt, err := json.Marshal(timestampAnnotation{SignedRFC3161Timestamp: ci})
+	require.NoError(t, err)
+	return dt
+}
+
+func signBytes(t *testing.T, key *ecdsa.PrivateKey, hash crypto.Hash, dt []byte) []byte {
+	r, s, err := ecdsa.Sign(rand.Reader, key, hashBytes(hash, dt))
+	require.NoError(t, err)
+	sig, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	require.NoError(t, err)
+	return sig
+}
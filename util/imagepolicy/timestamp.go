@@ -0,0 +1,231 @@
+package imagepolicy
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	oidSignedData      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidTSTInfo         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4}
+	oidContentType     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidMessageDigest   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidSHA256          = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidSHA384          = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	oidSHA512          = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+	timestampAlgorithm = map[string]crypto.Hash{
+		oidSHA256.String(): crypto.SHA256,
+		oidSHA384.String(): crypto.SHA384,
+		oidSHA512.String(): crypto.SHA512,
+	}
+)
+
+// timestampAnnotation is the format of the RFC 3161 timestamps attached to
+// signatures by cosign
+type timestampAnnotation struct {
+	SignedRFC3161Timestamp []byte
+}
+
+// The types below are the parts of the CMS SignedData (RFC 5652) holding a
+// TSTInfo (RFC 3161) that are needed to verify the timestamp.
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,tag:0"`
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	EncapContentInfo encapContentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      []signerInfo  `asn1:"set"`
+}
+
+type encapContentInfo struct {
+	EContentType asn1.ObjectIdentifier
+	EContent     []byte `asn1:"explicit,tag:0"`
+}
+
+type signerInfo struct {
+	Version            int
+	SID                asn1.RawValue
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignedAttrs        asn1.RawValue `asn1:"optional,tag:0"`
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+	UnsignedAttrs      asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+type issuerAndSerial struct {
+	Issuer asn1.RawValue
+	Serial *big.Int
+}
+
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue `asn1:"set"`
+}
+
+type tstInfo struct {
+	Version        int
+	Policy         asn1.ObjectIdentifier
+	MessageImprint messageImprint
+	SerialNumber   *big.Int
+	GenTime        time.Time `asn1:"generalized"`
+}
+
+type messageImprint struct {
+	HashAlgorithm pkix.AlgorithmIdentifier
+	HashedMessage []byte
+}
+
+// verifyTimestamp checks that the timestamp of sig is for its signature and is
+// signed by a timestamp authority with a certificate issued by one of the
+// roots. It returns the time of the timestamp.
+func verifyTimestamp(sig *Signature, roots *x509.CertPool) (time.Time, error) {
+	var ta timestampAnnotation
+	if err := json.Unmarshal(sig.Timestamp, &ta); err != nil {
+		return time.Time{}, errors.Wrap(err, "invalid timestamp")
+	}
+	var ci contentInfo
+	if _, err := asn1.Unmarshal(ta.SignedRFC3161Timestamp, &ci); err != nil {
+		return time.Time{}, errors.Wrap(err, "invalid timestamp")
+	}
+	if !ci.ContentType.Equal(oidSignedData) {
+		return time.Time{}, errors.Errorf("invalid timestamp content type %s", ci.ContentType)
+	}
+	var sd signedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		return time.Time{}, errors.Wrap(err, "invalid timestamp")
+	}
+	if !sd.EncapContentInfo.EContentType.Equal(oidTSTInfo) {
+		return time.Time{}, errors.Errorf("invalid timestamp content type %s", sd.EncapContentInfo.EContentType)
+	}
+	var info tstInfo
+	if _, err := asn1.Unmarshal(sd.EncapContentInfo.EContent, &info); err != nil {
+		return time.Time{}, errors.Wrap(err, "invalid timestamp")
+	}
+
+	hash, ok := timestampAlgorithm[info.MessageImprint.HashAlgorithm.Algorithm.String()]
+	if !ok {
+		return time.Time{}, errors.Errorf("unsupported timestamp hash algorithm %s", info.MessageImprint.HashAlgorithm.Algorithm)
+	}
+	if !bytes.Equal(hashBytes(hash, sig.Signature), info.MessageImprint.HashedMessage) {
+		return time.Time{}, errors.New("timestamp is for another signature")
+	}
+
+	certs, err := x509.ParseCertificates(sd.Certificates.Bytes)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "invalid timestamp certificates")
+	}
+	if len(sd.SignerInfos) != 1 {
+		return time.Time{}, errors.Errorf("timestamp has %d signers", len(sd.SignerInfos))
+	}
+	si := sd.SignerInfos[0]
+	signer, err := findSigner(si, certs)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := verifySignerInfo(si, signer, sd.EncapContentInfo.EContent); err != nil {
+		return time.Time{}, err
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, c := range certs {
+		if c != signer {
+			intermediates.AddCert(c)
+		}
+	}
+	if _, err := signer.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   info.GenTime,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping},
+	}); err != nil {
+		return time.Time{}, errors.Wrap(err, "invalid timestamp certificate")
+	}
+	return info.GenTime, nil
+}
+
+// findSigner returns the certificate of the signer of a timestamp
+func findSigner(si signerInfo, certs []*x509.Certificate) (*x509.Certificate, error) {
+	switch {
+	case si.SID.Class == asn1.ClassUniversal && si.SID.Tag == asn1.TagSequence:
+		var ias issuerAndSerial
+		if _, err := asn1.Unmarshal(si.SID.FullBytes, &ias); err != nil {
+			return nil, errors.Wrap(err, "invalid timestamp signer")
+		}
+		for _, c := range certs {
+			if c.SerialNumber.Cmp(ias.Serial) == 0 && bytes.Equal(c.RawIssuer, ias.Issuer.FullBytes) {
+				return c, nil
+			}
+		}
+	case si.SID.Class == asn1.ClassContextSpecific && si.SID.Tag == 0:
+		for _, c := range certs {
+			if bytes.Equal(c.SubjectKeyId, si.SID.Bytes) {
+				return c, nil
+			}
+		}
+	}
+	return nil, errors.New("timestamp has no certificate of its signer")
+}
+
+// verifySignerInfo checks that the signed attributes of the signer are for
+// the content and are signed by the certificate
+func verifySignerInfo(si signerInfo, cert *x509.Certificate, content []byte) error {
+	hash, ok := timestampAlgorithm[si.DigestAlgorithm.Algorithm.String()]
+	if !ok {
+		return errors.Errorf("unsupported timestamp digest algorithm %s", si.DigestAlgorithm.Algorithm)
+	}
+	if len(si.SignedAttrs.FullBytes) == 0 {
+		return errors.New("timestamp has no signed attributes")
+	}
+	// the signature is computed over the DER encoding of the attributes as a
+	// SET OF instead of the implicit tag
+	attrsDER := append([]byte{}, si.SignedAttrs.FullBytes...)
+	attrsDER[0] = asn1.TagSet | 0x20
+	var attrs []attribute
+	if _, err := asn1.UnmarshalWithParams(attrsDER, &attrs, "set"); err != nil {
+		return errors.Wrap(err, "invalid timestamp signed attributes")
+	}
+	var digestOK, contentTypeOK bool
+	for _, a := range attrs {
+		switch {
+		case a.Type.Equal(oidMessageDigest):
+			var d []byte
+			if _, err := asn1.Unmarshal(a.Values.Bytes, &d); err != nil {
+				return errors.Wrap(err, "invalid timestamp message digest")
+			}
+			digestOK = bytes.Equal(d, hashBytes(hash, content))
+		case a.Type.Equal(oidContentType):
+			var ct asn1.ObjectIdentifier
+			if _, err := asn1.Unmarshal(a.Values.Bytes, &ct); err != nil {
+				return errors.Wrap(err, "invalid timestamp content type")
+			}
+			contentTypeOK = ct.Equal(oidTSTInfo)
+		}
+	}
+	if !digestOK || !contentTypeOK {
+		return errors.New("timestamp signed attributes are not for its content")
+	}
+	if err := verifySignatureHash(cert.PublicKey, hash, attrsDER, si.Signature); err != nil {
+		return errors.Wrap(err, "invalid timestamp signature")
+	}
+	return nil
+}
+
+func hashBytes(hash crypto.Hash, dt []byte) []byte {
+	h := hash.New()
+	h.Write(dt)
+	return h.Sum(nil)
+}
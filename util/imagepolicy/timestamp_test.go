@@ -0,0 +1,131 @@
+package imagepolicy
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyTimestamp(t *testing.T) {
+	t.Parallel()
+
+	caKey, ca := newCA(t, "test ca")
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	now := time.Now()
+	tsaKey, tsa := newTSA(t, caKey, ca, now.Add(-time.Hour), now.Add(time.Hour), x509.ExtKeyUsageTimeStamping)
+	sig := &Signature{Signature: []byte("signature")}
+
+	sig.Timestamp = newTimestamp(t, tsaKey, tsa, sig.Signature, now)
+	at, err := verifyTimestamp(sig, roots)
+	require.NoError(t, err)
+	require.Equal(t, now.UTC().Truncate(time.Second), at.UTC())
+
+	// certificate of a timestamp authority issued by an untrusted root
+	otherKey, other := newCA(t, "other ca")
+	untrustedKey, untrusted := newTSA(t, otherKey, other, now.Add(-time.Hour), now.Add(time.Hour), x509.ExtKeyUsageTimeStamping)
+	sig.Timestamp = newTimestamp(t, untrustedKey, untrusted, sig.Signature, now)
+	_, err = verifyTimestamp(sig, roots)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid timestamp certificate")
+
+	// certificate that is not for timestamping
+	codeKey, code := newTSA(t, caKey, ca, now.Add(-time.Hour), now.Add(time.Hour), x509.ExtKeyUsageCodeSigning)
+	sig.Timestamp = newTimestamp(t, codeKey, code, sig.Signature, now)
+	_, err = verifyTimestamp(sig, roots)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid timestamp certificate")
+
+	// time of the timestamp outside of the validity of the certificate
+	sig.Timestamp = newTimestamp(t, tsaKey, tsa, sig.Signature, now.Add(-2*time.Hour))
+	_, err = verifyTimestamp(sig, roots)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid timestamp certificate")
+
+	sig.Timestamp = newTimestamp(t, tsaKey, tsa, sig.Signature, now.Add(2*time.Hour))
+	_, err = verifyTimestamp(sig, roots)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid timestamp certificate")
+
+	// time changed after the timestamp was signed
+	sig.Timestamp = tamperTimestamp(t, newTimestamp(t, tsaKey, tsa, sig.Signature, now), now.Add(-30*time.Minute))
+	_, err = verifyTimestamp(sig, roots)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "signed attributes are not for its content")
+
+	sig.Timestamp = []byte(`{"SignedRFC3161Timestamp":"aW52YWxpZA=="}`)
+	_, err = verifyTimestamp(sig, roots)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid timestamp")
+}
+
+func newCA(t *testing.T, name string) (*ecdsa.PrivateKey, *x509.Certificate) {
+	key := newKey(t)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return key, cert
+}
+
+func newTSA(t *testing.T, caKey *ecdsa.PrivateKey, ca *x509.Certificate, notBefore, notAfter time.Time, usage x509.ExtKeyUsage) (*ecdsa.PrivateKey, *x509.Certificate) {
+	key := newKey(t)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test tsa"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return key, cert
+}
+
+// tamperTimestamp changes the time of a timestamp without signing it again
+func tamperTimestamp(t *testing.T, dt []byte, at time.Time) []byte {
+	var ta timestampAnnotation
+	require.NoError(t, json.Unmarshal(dt, &ta))
+	var ci contentInfo
+	_, err := asn1.Unmarshal(ta.SignedRFC3161Timestamp, &ci)
+	require.NoError(t, err)
+	var sd signedData
+	_, err = asn1.Unmarshal(ci.Content.Bytes, &sd)
+	require.NoError(t, err)
+	var info tstInfo
+	_, err = asn1.Unmarshal(sd.EncapContentInfo.EContent, &info)
+	require.NoError(t, err)
+
+	info.GenTime = at.UTC().Truncate(time.Second)
+	sd.EncapContentInfo.EContent, err = asn1.Marshal(info)
+	require.NoError(t, err)
+	content, err := asn1.Marshal(sd)
+	require.NoError(t, err)
+	ci.Content = asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: content}
+	ta.SignedRFC3161Timestamp, err = asn1.Marshal(ci)
+	require.NoError(t, err)
+	dt, err = json.Marshal(ta)
+	require.NoError(t, err)
+	return dt
+}
@@ -0,0 +1,117 @@
+package imagepolicy
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// bundle is the entry of a signature in a Rekor transparency log as attached
+// by cosign. The signed entry timestamp is the promise of the log that the
+// entry was included in the log at the integrated time.
+type bundle struct {
+	SignedEntryTimestamp []byte
+	Payload              bundlePayload
+}
+
+// bundlePayload is the signed part of a bundle. The fields are in the order
+// of the canonical JSON encoding that is signed by the log.
+type bundlePayload struct {
+	Body           string `json:"body"`
+	IntegratedTime int64  `json:"integratedTime"`
+	LogID          string `json:"logID"`
+	LogIndex       int64  `json:"logIndex"`
+}
+
+// rekordEntry is the body of the log entries of the rekord and hashedrekord
+// kinds
+type rekordEntry struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Data struct {
+			Hash struct {
+				Algorithm string `json:"algorithm"`
+				Value     string `json:"value"`
+			} `json:"hash"`
+		} `json:"data"`
+		Signature struct {
+			Content   []byte `json:"content"`
+			PublicKey struct {
+				Content []byte `json:"content"`
+			} `json:"publicKey"`
+		} `json:"signature"`
+	} `json:"spec"`
+}
+
+// verifyBundle checks that the bundle of sig is signed by one of the keys of
+// the logs and that its entry is for the payload, signature and certificate
+// of sig. It returns the time the entry was added to the log.
+func verifyBundle(sig *Signature, cert *x509.Certificate, keys []crypto.PublicKey) (time.Time, error) {
+	var b bundle
+	if err := json.Unmarshal(sig.Bundle, &b); err != nil {
+		return time.Time{}, errors.Wrap(err, "invalid transparency log entry")
+	}
+	dt, err := json.Marshal(b.Payload)
+	if err != nil {
+		return time.Time{}, errors.WithStack(err)
+	}
+	var trusted bool
+	for _, k := range keys {
+		id, err := logID(k)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if id != b.Payload.LogID {
+			continue
+		}
+		if err := verifySignature(k, dt, b.SignedEntryTimestamp); err == nil {
+			trusted = true
+			break
+		}
+	}
+	if !trusted {
+		return time.Time{}, errors.New("transparency log entry is not signed by a trusted log")
+	}
+
+	body, err := base64.StdEncoding.DecodeString(b.Payload.Body)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "invalid transparency log entry body")
+	}
+	var e rekordEntry
+	if err := json.Unmarshal(body, &e); err != nil {
+		return time.Time{}, errors.Wrap(err, "invalid transparency log entry body")
+	}
+	if e.Kind != "hashedrekord" && e.Kind != "rekord" {
+		return time.Time{}, errors.Errorf("unsupported transparency log entry kind %q", e.Kind)
+	}
+	h := sha256.Sum256(sig.Payload)
+	if e.Spec.Data.Hash.Algorithm != "sha256" || e.Spec.Data.Hash.Value != hex.EncodeToString(h[:]) {
+		return time.Time{}, errors.New("transparency log entry is for another payload")
+	}
+	if !bytes.Equal(e.Spec.Signature.Content, sig.Signature) {
+		return time.Time{}, errors.New("transparency log entry is for another signature")
+	}
+	block, _ := pem.Decode(e.Spec.Signature.PublicKey.Content)
+	if block == nil || !bytes.Equal(block.Bytes, cert.Raw) {
+		return time.Time{}, errors.New("transparency log entry is for another certificate")
+	}
+	return time.Unix(b.Payload.IntegratedTime, 0), nil
+}
+
+// logID returns the ID of the log with the public key k
+func logID(k crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(k)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	h := sha256.Sum256(der)
+	return hex.EncodeToString(h[:]), nil
+}
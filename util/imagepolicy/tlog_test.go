@@ -0,0 +1,98 @@
+package imagepolicy
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyBundle(t *testing.T) {
+	t.Parallel()
+
+	caKey, ca := newCA(t, "test ca")
+	now := time.Now()
+	_, cert := newTSA(t, caKey, ca, now.Add(-time.Hour), now.Add(time.Hour), x509.ExtKeyUsageCodeSigning)
+	logKey := newKey(t)
+	keys := []crypto.PublicKey{&logKey.PublicKey}
+
+	sig := &Signature{
+		Payload:     []byte("payload"),
+		Signature:   []byte("signature"),
+		Certificate: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}),
+	}
+	at := now.Add(-time.Minute)
+	sig.Bundle = newBundle(t, logKey, sig, at)
+	out, err := verifyBundle(sig, cert, keys)
+	require.NoError(t, err)
+	require.Equal(t, at.Unix(), out.Unix())
+
+	tamper := func(f func(b *bundle)) []byte {
+		var b bundle
+		require.NoError(t, json.Unmarshal(newBundle(t, logKey, sig, at), &b))
+		f(&b)
+		dt, err := json.Marshal(b)
+		require.NoError(t, err)
+		return dt
+	}
+
+	// tampered signed entry timestamp
+	orig := sig.Bundle
+	sig.Bundle = tamper(func(b *bundle) {
+		b.SignedEntryTimestamp[len(b.SignedEntryTimestamp)-1] ^= 0xff
+	})
+	_, err = verifyBundle(sig, cert, keys)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not signed by a trusted log")
+
+	// integrated time changed after the entry was signed
+	sig.Bundle = tamper(func(b *bundle) {
+		b.Payload.IntegratedTime = now.Add(-30 * time.Minute).Unix()
+	})
+	_, err = verifyBundle(sig, cert, keys)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not signed by a trusted log")
+
+	// entry signed by another log with the ID of the trusted one
+	other := newKey(t)
+	sig.Bundle = newBundle(t, other, sig, at)
+	sig.Bundle = func() []byte {
+		var b bundle
+		require.NoError(t, json.Unmarshal(sig.Bundle, &b))
+		id, err := logID(&logKey.PublicKey)
+		require.NoError(t, err)
+		b.Payload.LogID = id
+		dt, err := json.Marshal(b.Payload)
+		require.NoError(t, err)
+		b.SignedEntryTimestamp = signBytes(t, other, crypto.SHA256, dt)
+		dt, err = json.Marshal(b)
+		require.NoError(t, err)
+		return dt
+	}()
+	_, err = verifyBundle(sig, cert, keys)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not signed by a trusted log")
+
+	// valid entry of another payload
+	sig.Bundle = orig
+	tampered := *sig
+	tampered.Payload = []byte("other payload")
+	_, err = verifyBundle(&tampered, cert, keys)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "entry is for another payload")
+
+	// valid entry of another certificate
+	_, otherCert := newTSA(t, caKey, ca, now.Add(-time.Hour), now.Add(time.Hour), x509.ExtKeyUsageCodeSigning)
+	_, err = verifyBundle(sig, otherCert, keys)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "entry is for another certificate")
+
+	sig.Bundle = []byte("{")
+	_, err = verifyBundle(sig, cert, keys)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid transparency log entry")
+}
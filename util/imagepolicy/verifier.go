@@ -0,0 +1,253 @@
+package imagepolicy
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"path"
+	"strings"
+	"time"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// oidcIssuerOID is the certificate extension of keyless signatures that holds
+// the OIDC issuer of the identity of the signer
+var oidcIssuerOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+
+// payload is the signed payload of a cosign signature
+type payload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest digest.Digest `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// checkPayload checks that the payload of a signature is for the manifest
+// dgst
+func checkPayload(dt []byte, dgst digest.Digest) error {
+	var p payload
+	if err := json.Unmarshal(dt, &p); err != nil {
+		return errors.Wrap(err, "invalid signature payload")
+	}
+	if p.Critical.Image.DockerManifestDigest != dgst {
+		return errors.Errorf("signature is for %s instead of %s", p.Critical.Image.DockerManifestDigest, dgst)
+	}
+	return nil
+}
+
+type keyedVerifier struct {
+	keys []crypto.PublicKey
+}
+
+func newKeyedVerifier(keys [][]byte) (*keyedVerifier, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("keyed verification requires keys")
+	}
+	v := &keyedVerifier{}
+	for i, dt := range keys {
+		key, err := parsePublicKey(dt)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid key %d", i)
+		}
+		v.keys = append(v.keys, key)
+	}
+	return v, nil
+}
+
+func (v *keyedVerifier) Verify(sig *Signature) error {
+	for _, k := range v.keys {
+		if err := verifySignature(k, sig.Payload, sig.Signature); err == nil {
+			return nil
+		}
+	}
+	return errors.New("signature doesn't match any of the keys")
+}
+
+type keylessVerifier struct {
+	roots      *x509.CertPool
+	identities []Identity
+	tlogKeys   []crypto.PublicKey
+	tsaRoots   *x509.CertPool
+}
+
+func newKeylessVerifier(roots [][]byte, identities []Identity, tlogKeys, tsaRoots [][]byte) (*keylessVerifier, error) {
+	if len(roots) == 0 {
+		return nil, errors.New("keyless verification requires roots")
+	}
+	if len(identities) == 0 {
+		return nil, errors.New("keyless verification requires identities")
+	}
+	if len(tlogKeys) == 0 && len(tsaRoots) == 0 {
+		return nil, errors.New("keyless verification requires transparency log keys or timestamp roots")
+	}
+	v := &keylessVerifier{roots: x509.NewCertPool(), identities: identities}
+	for i, dt := range roots {
+		if !v.roots.AppendCertsFromPEM(dt) {
+			return nil, errors.Errorf("no certificates in root %d", i)
+		}
+	}
+	for i, dt := range tlogKeys {
+		key, err := parsePublicKey(dt)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid transparency log key %d", i)
+		}
+		v.tlogKeys = append(v.tlogKeys, key)
+	}
+	if len(tsaRoots) > 0 {
+		v.tsaRoots = x509.NewCertPool()
+		for i, dt := range tsaRoots {
+			if !v.tsaRoots.AppendCertsFromPEM(dt) {
+				return nil, errors.Errorf("no certificates in timestamp root %d", i)
+			}
+		}
+	}
+	return v, nil
+}
+
+// Verify checks that the certificate of the signature was issued by one of
+// the roots to one of the identities. The certificates of keyless signatures
+// expire shortly after the signature is made, so the chain is verified at the
+// time of the signature. The time must be proven by an entry of a trusted
+// transparency log or by a timestamp of a trusted timestamp authority.
+func (v *keylessVerifier) Verify(sig *Signature) error {
+	if len(sig.Certificate) == 0 {
+		return errors.New("signature has no certificate")
+	}
+	block, _ := pem.Decode(sig.Certificate)
+	if block == nil {
+		return errors.New("invalid signature certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return errors.Wrap(err, "invalid signature certificate")
+	}
+	signedAt, err := v.signedTime(sig, cert)
+	if err != nil {
+		return err
+	}
+	intermediates := x509.NewCertPool()
+	if len(sig.Chain) > 0 {
+		intermediates.AppendCertsFromPEM(sig.Chain)
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:         v.roots,
+		Intermediates: intermediates,
+		CurrentTime:   signedAt,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return errors.Wrap(err, "invalid signature certificate")
+	}
+	if !v.allowed(cert) {
+		return errors.Errorf("signer %v is not allowed", certSubjects(cert))
+	}
+	return verifySignature(cert.PublicKey, sig.Payload, sig.Signature)
+}
+
+// signedTime returns the time of the signature proven by a transparency log
+// entry or a timestamp that the verifier trusts
+func (v *keylessVerifier) signedTime(sig *Signature, cert *x509.Certificate) (time.Time, error) {
+	var errs []string
+	if len(v.tlogKeys) > 0 {
+		if len(sig.Bundle) == 0 {
+			errs = append(errs, "no transparency log entry")
+		} else {
+			t, err := verifyBundle(sig, cert, v.tlogKeys)
+			if err == nil {
+				return t, nil
+			}
+			errs = append(errs, err.Error())
+		}
+	}
+	if v.tsaRoots != nil {
+		if len(sig.Timestamp) == 0 {
+			errs = append(errs, "no timestamp")
+		} else {
+			t, err := verifyTimestamp(sig, v.tsaRoots)
+			if err == nil {
+				return t, nil
+			}
+			errs = append(errs, err.Error())
+		}
+	}
+	return time.Time{}, errors.Errorf("signature time is not proven: %s", strings.Join(errs, ", "))
+}
+
+func (v *keylessVerifier) allowed(cert *x509.Certificate) bool {
+	var issuer string
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(oidcIssuerOID) {
+			issuer = string(ext.Value)
+		}
+	}
+	for _, id := range v.identities {
+		if ok, _ := path.Match(id.Issuer, issuer); !ok {
+			continue
+		}
+		for _, s := range certSubjects(cert) {
+			if ok, _ := path.Match(id.Subject, s); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func certSubjects(cert *x509.Certificate) []string {
+	out := append([]string{}, cert.EmailAddresses...)
+	for _, u := range cert.URIs {
+		out = append(out, u.String())
+	}
+	return out
+}
+
+func parsePublicKey(dt []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(dt)
+	if block == nil {
+		return nil, errors.New("no PEM data")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return key, nil
+}
+
+func verifySignature(key crypto.PublicKey, dt, sig []byte) error {
+	return verifySignatureHash(key, crypto.SHA256, dt, sig)
+}
+
+func verifySignatureHash(key crypto.PublicKey, hash crypto.Hash, dt, sig []byte) error {
+	h := hashBytes(hash, dt)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		var rs struct {
+			R, S *big.Int
+		}
+		if _, err := asn1.Unmarshal(sig, &rs); err != nil {
+			return errors.Wrap(err, "invalid signature")
+		}
+		if !ecdsa.Verify(k, h, rs.R, rs.S) {
+			return errors.New("invalid signature")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(k, hash, h, sig); err != nil {
+			return errors.Wrap(err, "invalid signature")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, dt, sig) {
+			return errors.New("invalid signature")
+		}
+	default:
+		return errors.Errorf("unsupported key type %T", key)
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 	digest "github.com/opencontainers/go-digest"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 type ContentCache interface {
@@ -107,6 +108,11 @@ func Config(ctx context.Context, str string, resolver remotes.Resolver, cache Co
 	if err := images.Dispatch(ctx, images.Handlers(handlers...), nil, desc); err != nil {
 		return "", nil, err
 	}
+	if cm, ok := cache.(content.Manager); ok && ref.Digest() == "" {
+		if err := SetResolved(ctx, cm, ref.String(), desc.Digest); err != nil {
+			logrus.Warnf("failed to record resolved digest of %s: %v", ref.String(), err)
+		}
+	}
 	config, err := images.Config(ctx, cache, desc, platform)
 	if err != nil {
 		return "", nil, err
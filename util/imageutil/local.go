@@ -0,0 +1,88 @@
+package imageutil
+
+import (
+	"context"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/reference"
+	digest "github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// labelResolvedPrefix is the prefix of the labels of the manifests in the
+// content store that record the references they were resolved from. The value
+// is the time of the resolution.
+const labelResolvedPrefix = "buildkit/resolved."
+
+// SetResolved records that the reference ref was resolved to the manifest dgst
+// in the content store, so that ResolveLocal can resolve it without the
+// registry.
+func SetResolved(ctx context.Context, cs content.Manager, ref string, dgst digest.Digest) error {
+	key := labelResolvedPrefix + ref
+	_, err := cs.Update(ctx, content.Info{
+		Digest: dgst,
+		Labels: map[string]string{key: time.Now().UTC().Format(time.RFC3339Nano)},
+	}, "labels."+key)
+	return errors.WithStack(err)
+}
+
+// ResolveLocal resolves the reference ref to a manifest in the content store.
+// References with a digest resolve to the manifest if it is in the store.
+// References with a tag resolve to the manifest they were last resolved to by
+// a pull of the daemon.
+func ResolveLocal(ctx context.Context, cs content.Store, ref string) (specs.Descriptor, error) {
+	spec, err := reference.Parse(ref)
+	if err != nil {
+		return specs.Descriptor{}, errors.WithStack(err)
+	}
+	if dgst := spec.Digest(); dgst != "" {
+		return localDescriptor(ctx, cs, dgst)
+	}
+
+	key := labelResolvedPrefix + ref
+	var latest digest.Digest
+	var latestTime time.Time
+	if err := cs.Walk(ctx, func(info content.Info) error {
+		v, ok := info.Labels[key]
+		if !ok {
+			return nil
+		}
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return nil
+		}
+		if latest == "" || t.After(latestTime) {
+			latest = info.Digest
+			latestTime = t
+		}
+		return nil
+	}); err != nil {
+		return specs.Descriptor{}, errors.WithStack(err)
+	}
+	if latest == "" {
+		return specs.Descriptor{}, errors.Wrapf(errdefs.ErrNotFound, "%s is not available locally", ref)
+	}
+	return localDescriptor(ctx, cs, latest)
+}
+
+func localDescriptor(ctx context.Context, cs content.Store, dgst digest.Digest) (specs.Descriptor, error) {
+	info, err := cs.Info(ctx, dgst)
+	if err != nil {
+		return specs.Descriptor{}, errors.Wrapf(err, "%s is not available locally", dgst)
+	}
+	desc := specs.Descriptor{Digest: info.Digest, Size: info.Size}
+	ra, err := cs.ReaderAt(ctx, desc)
+	if err != nil {
+		return specs.Descriptor{}, errors.WithStack(err)
+	}
+	defer ra.Close()
+	mt, err := DetectManifestMediaType(ra)
+	if err != nil {
+		return specs.Descriptor{}, errors.Wrapf(err, "failed to detect media type of %s", dgst)
+	}
+	desc.MediaType = mt
+	return desc, nil
+}
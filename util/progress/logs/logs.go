@@ -1,6 +1,7 @@
 package logs
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -9,6 +10,7 @@ import (
 	"strconv"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/identity"
@@ -19,6 +21,7 @@ import (
 
 var defaultMaxLogSize = 2 * 1024 * 1024
 var defaultMaxLogSpeed = 200 * 1024 // per second
+var defaultMaxLogLineSize = -1
 
 const (
 	stdout = 1
@@ -49,9 +52,11 @@ type streamWriter struct {
 	size            int
 	clipping        bool
 	clipReasonSpeed bool
+	lineSize        int
+	lineClipped     bool
 }
 
-func (sw *streamWriter) checkLimit(n int) int {
+func loadConfig() {
 	configCheckOnce.Do(func() {
 		maxLogSize, err := strconv.ParseInt(os.Getenv("BUILDKIT_STEP_LOG_MAX_SIZE"), 10, 32)
 		if err == nil {
@@ -61,8 +66,69 @@ func (sw *streamWriter) checkLimit(n int) int {
 		if err == nil {
 			defaultMaxLogSpeed = int(maxLogSpeed)
 		}
+		maxLogLineSize, err := strconv.ParseInt(os.Getenv("BUILDKIT_STEP_LOG_MAX_LINE_SIZE"), 10, 32)
+		if err == nil {
+			defaultMaxLogLineSize = int(maxLogLineSize)
+		}
 	})
+}
+
+// SetMaxLineSize sets the limit of the line length of the logs of the steps,
+// overriding BUILDKIT_STEP_LOG_MAX_LINE_SIZE. Longer lines are clipped. A
+// negative size means no limit.
+func SetMaxLineSize(n int) {
+	loadConfig()
+	defaultMaxLogLineSize = n
+}
+
+// clipLines truncates the lines that are longer than the line size limit.
+// The truncated lines end with a marker. A line is never cut in the middle
+// of a multi-byte UTF-8 character.
+func (sw *streamWriter) clipLines(dt []byte) []byte {
+	if defaultMaxLogLineSize < 0 {
+		return dt
+	}
+	var out []byte
+	for len(dt) > 0 {
+		line := dt
+		var eol bool
+		if i := bytes.IndexByte(dt, '\n'); i != -1 {
+			line = dt[:i]
+			eol = true
+		}
+		dt = dt[len(line):]
+		if eol {
+			dt = dt[1:]
+		}
 
+		if !sw.lineClipped {
+			if n := defaultMaxLogLineSize - sw.lineSize; len(line) > n {
+				if n < 0 {
+					n = 0
+				}
+				// include the rest of the character at the limit
+				for n < len(line) && !utf8.RuneStart(line[n]) {
+					n++
+				}
+				out = append(out, line[:n]...)
+				out = append(out, []byte(fmt.Sprintf("[line clipped, line limit %#g reached]", units.Bytes(defaultMaxLogLineSize)))...)
+				sw.lineClipped = true
+			} else {
+				out = append(out, line...)
+				sw.lineSize += len(line)
+			}
+		}
+
+		if eol {
+			out = append(out, '\n')
+			sw.lineSize = 0
+			sw.lineClipped = false
+		}
+	}
+	return out
+}
+
+func (sw *streamWriter) checkLimit(n int) int {
 	oldSize := sw.size
 	sw.size += n
 
@@ -96,13 +162,17 @@ func (sw *streamWriter) clipLimitMessage() string {
 }
 
 func (sw *streamWriter) Write(dt []byte) (int, error) {
+	loadConfig()
+
 	oldSize := len(dt)
+	dt = sw.clipLines(dt)
+	lineSize := len(dt)
 	dt = append([]byte{}, dt[:sw.checkLimit(len(dt))]...)
 
-	if sw.clipping && oldSize == len(dt) {
+	if sw.clipping && lineSize == len(dt) {
 		sw.clipping = false
 	}
-	if !sw.clipping && oldSize != len(dt) {
+	if !sw.clipping && lineSize != len(dt) {
 		dt = append(dt, []byte(fmt.Sprintf("\n[output clipped, log limit %s reached]\n", sw.clipLimitMessage()))...)
 		sw.clipping = true
 	}
@@ -0,0 +1,38 @@
+package logs
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClipLines(t *testing.T) {
+	old := defaultMaxLogLineSize
+	defaultMaxLogLineSize = 4
+	defer func() {
+		defaultMaxLogLineSize = old
+	}()
+
+	marker := "[line clipped, line limit 4B reached]"
+
+	sw := &streamWriter{}
+	require.Equal(t, "abc\nabcd\n", string(sw.clipLines([]byte("abc\nabcd\n"))))
+	require.Equal(t, "abcd"+marker+"\nab", string(sw.clipLines([]byte("abcdefgh\nab"))))
+
+	// the limit applies to lines split over multiple writes
+	require.Equal(t, "cd"+marker, string(sw.clipLines([]byte("cdef"))))
+	require.Equal(t, "\nx\n", string(sw.clipLines([]byte("ghi\nx\n"))))
+
+	// multi-byte characters are not split
+	sw = &streamWriter{}
+	out := sw.clipLines([]byte("abcéé\n"))
+	require.True(t, utf8.Valid(out))
+	require.Equal(t, "abcé"+marker+"\n", string(out))
+
+	sw = &streamWriter{}
+	out = sw.clipLines([]byte(strings.Repeat("世", 3)))
+	require.True(t, utf8.Valid(out))
+	require.Equal(t, "世世"+marker, string(out))
+}
@@ -51,6 +51,25 @@ func NewFromContext(ctx context.Context, opts ...WriterOption) (Writer, bool, co
 	return FromContext(ctx, opts...)(ctx)
 }
 
+// OneOff writes a started status with the id to the Writer of the Context
+// and returns a function that completes the status and passes through err.
+func OneOff(ctx context.Context, id string) func(err error) error {
+	pw, _, _ := NewFromContext(ctx)
+	now := time.Now()
+	st := Status{
+		Started: &now,
+	}
+	pw.Write(id, st)
+	return func(err error) error {
+		// TODO: set error on status
+		now := time.Now()
+		st.Completed = &now
+		pw.Write(id, st)
+		pw.Close()
+		return err
+	}
+}
+
 type WriterOption func(Writer)
 
 // NewContext returns a new context and a progress reader that captures all
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/sync/errgroup"
 )
@@ -65,6 +66,32 @@ func TestProgressNested(t *testing.T) {
 	assert.True(t, len(trace.items) <= 15)
 }
 
+func TestOneOff(t *testing.T) {
+	t.Parallel()
+	eg, ctx := errgroup.WithContext(context.Background())
+	pr, ctx, cancelProgress := NewContext(ctx)
+	var trace trace
+	eg.Go(func() error {
+		return saveProgress(ctx, pr, &trace)
+	})
+
+	done := OneOff(ctx, "export")
+	errFailed := errors.New("failed")
+	assert.Equal(t, errFailed, done(errFailed))
+
+	cancelProgress()
+	err := eg.Wait()
+	assert.NoError(t, err)
+
+	assert.True(t, len(trace.items) > 0)
+	last := trace.items[len(trace.items)-1]
+	assert.Equal(t, "export", last.ID)
+	st, ok := last.Sys.(Status)
+	assert.True(t, ok)
+	assert.NotNil(t, st.Started)
+	assert.NotNil(t, st.Completed)
+}
+
 func calc(ctx context.Context, total int, name string) (int, error) {
 	pw, _, ctx := NewFromContext(ctx)
 	defer pw.Close()
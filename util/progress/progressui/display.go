@@ -14,6 +14,7 @@ import (
 
 	"github.com/containerd/console"
 	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/solver/pb"
 	"github.com/morikuni/aec"
 	digest "github.com/opencontainers/go-digest"
 	"github.com/tonistiigi/units"
@@ -70,6 +71,7 @@ func DisplaySolveStatus(ctx context.Context, phase string, c console.Console, w
 			if done {
 				disp.print(t.displayInfo(), width, height, true)
 				t.printErrorLogs(c)
+				t.printCacheMountStats(c)
 				return nil
 			} else if displayLimiter.Allow() {
 				ticker.Stop()
@@ -81,6 +83,7 @@ func DisplaySolveStatus(ctx context.Context, phase string, c console.Console, w
 				printer.print(t)
 				if done {
 					t.printErrorLogs(w)
+					t.printCacheMountStats(w)
 					return nil
 				}
 				ticker.Stop()
@@ -119,6 +122,15 @@ type trace struct {
 	nextIndex     int
 	updates       map[digest.Digest]struct{}
 	modeConsole   bool
+	cacheMounts   []*cacheMountSummary
+}
+
+// cacheMountSummary accumulates the stats of a cache mount over the build
+type cacheMountSummary struct {
+	id             string
+	target         string
+	unchangedBytes int64
+	writtenBytes   int64
 }
 
 type vertex struct {
@@ -232,6 +244,9 @@ func (t *trace) update(s *client.SolveStatus, termWidth int) {
 		// allow a duplicate initial vertex that shouldn't reset state
 		if !(prev != nil && prev.Started != nil && v.Started == nil) {
 			t.byDigest[v.Digest].Vertex = v
+			if v.ProgressGroup != nil {
+				t.byDigest[v.Digest].indent = "=> "
+			}
 		}
 		t.byDigest[v.Digest].jobCached = false
 	}
@@ -290,6 +305,35 @@ func (t *trace) update(s *client.SolveStatus, termWidth int) {
 		t.updates[v.Digest] = struct{}{}
 		v.update(1)
 	}
+	for _, c := range s.CacheMounts {
+		t.addCacheMountStats(c)
+	}
+}
+
+func (t *trace) addCacheMountStats(c *client.CacheMountStats) {
+	for _, s := range t.cacheMounts {
+		if s.id == c.ID && s.target == c.Target {
+			s.unchangedBytes += c.UnchangedBytes
+			s.writtenBytes += c.WrittenBytes
+			return
+		}
+	}
+	t.cacheMounts = append(t.cacheMounts, &cacheMountSummary{
+		id:             c.ID,
+		target:         c.Target,
+		unchangedBytes: c.UnchangedBytes,
+		writtenBytes:   c.WrittenBytes,
+	})
+}
+
+func (t *trace) printCacheMountStats(f io.Writer) {
+	if len(t.cacheMounts) == 0 {
+		return
+	}
+	fmt.Fprintln(f, "cache mounts:")
+	for _, s := range t.cacheMounts {
+		fmt.Fprintf(f, " %s (id %s): %.2f unchanged, %.2f written\n", s.target, s.id, units.Bytes(s.unchangedBytes), units.Bytes(s.writtenBytes))
+	}
 }
 
 func (t *trace) printErrorLogs(f io.Writer) {
@@ -328,51 +372,122 @@ func (t *trace) displayInfo() (d displayInfo) {
 		}
 	}
 
+	groups := map[string][]*vertex{}
+	for _, v := range t.vertexes {
+		if pg := v.ProgressGroup; pg != nil {
+			groups[pg.Id] = append(groups[pg.Id], v)
+		}
+	}
+
 	for _, v := range t.vertexes {
-		if v.jobCached {
-			d.jobs = append(d.jobs, v.jobs...)
+		if pg := v.ProgressGroup; pg != nil {
+			// grouped vertexes are shown under the group when its first
+			// vertex starts
+			vtxs, ok := groups[pg.Id]
+			if !ok {
+				continue
+			}
+			delete(groups, pg.Id)
+			d.jobs = append(d.jobs, t.groupJob(pg, vtxs))
+			for _, v := range vtxs {
+				d.jobs = append(d.jobs, t.vertexJobs(v)...)
+			}
 			continue
 		}
-		var jobs []*job
-		j := &job{
-			startTime:     addTime(v.Started, t.localTimeDiff),
-			completedTime: addTime(v.Completed, t.localTimeDiff),
-			name:          strings.Replace(v.Name, "\t", " ", -1),
-			vertex:        v,
+		d.jobs = append(d.jobs, t.vertexJobs(v)...)
+	}
+
+	return d
+}
+
+// groupJob returns the job that summarizes the vertexes of a progress group
+func (t *trace) groupJob(pg *pb.ProgressGroup, vtxs []*vertex) *job {
+	j := &job{
+		name: pg.Name,
+	}
+	if j.name == "" {
+		j.name = pg.Id
+	}
+	var started, completed *time.Time
+	done, cached := true, true
+	for _, v := range vtxs {
+		if v.Started != nil && (started == nil || v.Started.Before(*started)) {
+			started = v.Started
+		}
+		if v.Completed == nil {
+			done = false
+		} else if completed == nil || v.Completed.After(*completed) {
+			completed = v.Completed
 		}
 		if v.Error != "" {
 			if strings.HasSuffix(v.Error, context.Canceled.Error()) {
 				j.isCanceled = true
-				j.name = "CANCELED " + j.name
 			} else {
 				j.hasError = true
-				j.name = "ERROR " + j.name
 			}
 		}
-		if v.Cached {
-			j.name = "CACHED " + j.name
-		}
-		j.name = v.indent + j.name
-		jobs = append(jobs, j)
-		for _, s := range v.statuses {
-			j := &job{
-				startTime:     addTime(s.Started, t.localTimeDiff),
-				completedTime: addTime(s.Completed, t.localTimeDiff),
-				name:          v.indent + "=> " + s.ID,
-			}
-			if s.Total != 0 {
-				j.status = fmt.Sprintf("%.2f / %.2f", units.Bytes(s.Current), units.Bytes(s.Total))
-			} else if s.Current != 0 {
-				j.status = fmt.Sprintf("%.2f", units.Bytes(s.Current))
-			}
-			jobs = append(jobs, j)
+		if !v.Cached {
+			cached = false
 		}
-		d.jobs = append(d.jobs, jobs...)
-		v.jobs = jobs
-		v.jobCached = true
 	}
+	if !done {
+		completed = nil
+	}
+	j.startTime = addTime(started, t.localTimeDiff)
+	j.completedTime = addTime(completed, t.localTimeDiff)
+	if j.hasError {
+		j.name = "ERROR " + j.name
+	} else if j.isCanceled {
+		j.name = "CANCELED " + j.name
+	}
+	if cached && done {
+		j.name = "CACHED " + j.name
+	}
+	return j
+}
 
-	return d
+// vertexJobs returns the jobs for the vertex and its statuses
+func (t *trace) vertexJobs(v *vertex) []*job {
+	if v.jobCached {
+		return v.jobs
+	}
+	var jobs []*job
+	j := &job{
+		startTime:     addTime(v.Started, t.localTimeDiff),
+		completedTime: addTime(v.Completed, t.localTimeDiff),
+		name:          strings.Replace(v.Name, "\t", " ", -1),
+		vertex:        v,
+	}
+	if v.Error != "" {
+		if strings.HasSuffix(v.Error, context.Canceled.Error()) {
+			j.isCanceled = true
+			j.name = "CANCELED " + j.name
+		} else {
+			j.hasError = true
+			j.name = "ERROR " + j.name
+		}
+	}
+	if v.Cached {
+		j.name = "CACHED " + j.name
+	}
+	j.name = v.indent + j.name
+	jobs = append(jobs, j)
+	for _, s := range v.statuses {
+		j := &job{
+			startTime:     addTime(s.Started, t.localTimeDiff),
+			completedTime: addTime(s.Completed, t.localTimeDiff),
+			name:          v.indent + "=> " + s.ID,
+		}
+		if s.Total != 0 {
+			j.status = fmt.Sprintf("%.2f / %.2f", units.Bytes(s.Current), units.Bytes(s.Total))
+		} else if s.Current != 0 {
+			j.status = fmt.Sprintf("%.2f", units.Bytes(s.Current))
+		}
+		jobs = append(jobs, j)
+	}
+	v.jobs = jobs
+	v.jobCached = true
+	return jobs
 }
 
 func split(dt []byte, sep byte, fn func([]byte)) bool {
@@ -2,11 +2,14 @@ package progresswriter
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/containerd/console"
 	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/util/progress/progressui"
+	digest "github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 )
 
@@ -66,8 +69,20 @@ func NewPrinter(ctx context.Context, out console.File, mode string) (Writer, err
 		done:   doneCh,
 	}
 
-	if v := os.Getenv("BUILDKIT_PROGRESS"); v != "" && mode == "auto" {
-		mode = v
+	if v := os.Getenv("BUILDKIT_PROGRESS"); v != "" && (mode == "auto" || strings.HasPrefix(mode, "auto,")) {
+		mode = v + strings.TrimPrefix(mode, "auto")
+	}
+
+	var hideCached bool
+	parts := strings.Split(mode, ",")
+	mode = parts[0]
+	for _, opt := range parts[1:] {
+		switch opt {
+		case "hide-cached":
+			hideCached = true
+		default:
+			return nil, errors.Errorf("invalid progress option %s", opt)
+		}
 	}
 
 	var c console.Console
@@ -85,10 +100,128 @@ func NewPrinter(ctx context.Context, out console.File, mode string) (Writer, err
 		return nil, errors.Errorf("invalid progress mode %s", mode)
 	}
 
+	displayCh := statusCh
+	var hidden *cachedFilter
+	if hideCached {
+		hidden = &cachedFilter{}
+		displayCh = hidden.filter(statusCh)
+	}
+
 	go func() {
 		// not using shared context to not disrupt display but let is finish reporting errors
-		pw.err = progressui.DisplaySolveStatus(ctx, "", c, out, statusCh)
+		pw.err = progressui.DisplaySolveStatus(ctx, "", c, out, displayCh)
+		if hidden != nil && hidden.count > 0 {
+			fmt.Fprintf(out, "%d cached steps hidden\n", hidden.count)
+		}
 		close(doneCh)
 	}()
 	return pw, nil
 }
+
+// cachedFilter removes the vertexes that are loaded from the cache from the
+// status stream. Vertexes are first reported before the solver knows if they
+// are cached, so the updates of a vertex are held back until it is started
+// without the cache or reported as cached. Vertexes that were already shown
+// are kept when they become cached so that the display doesn't show them as
+// incomplete.
+type cachedFilter struct {
+	hidden  map[digest.Digest]struct{}
+	shown   map[digest.Digest]struct{}
+	pending map[digest.Digest]*client.SolveStatus
+	count   int
+}
+
+func (f *cachedFilter) filter(in chan *client.SolveStatus) chan *client.SolveStatus {
+	f.hidden = map[digest.Digest]struct{}{}
+	f.shown = map[digest.Digest]struct{}{}
+	f.pending = map[digest.Digest]*client.SolveStatus{}
+	out := make(chan *client.SolveStatus)
+	go func() {
+		defer close(out)
+		for ss := range in {
+			out <- f.apply(ss)
+		}
+	}()
+	return out
+}
+
+func (f *cachedFilter) apply(ss *client.SolveStatus) *client.SolveStatus {
+	out := &client.SolveStatus{}
+	for _, v := range ss.Vertexes {
+		if _, ok := f.hidden[v.Digest]; ok {
+			continue
+		}
+		if _, ok := f.shown[v.Digest]; ok {
+			out.Vertexes = append(out.Vertexes, v)
+			continue
+		}
+		if v.Cached {
+			f.hidden[v.Digest] = struct{}{}
+			delete(f.pending, v.Digest)
+			f.count++
+			continue
+		}
+		if v.Started == nil {
+			// only the latest state of the vertex needs to be shown
+			p := f.pendingStatus(v.Digest)
+			p.Vertexes = []*client.Vertex{v}
+			continue
+		}
+		f.shown[v.Digest] = struct{}{}
+		out.Vertexes = append(out.Vertexes, v)
+		if p, ok := f.pending[v.Digest]; ok {
+			out.Statuses = append(out.Statuses, p.Statuses...)
+			out.Logs = append(out.Logs, p.Logs...)
+			out.CacheMounts = append(out.CacheMounts, p.CacheMounts...)
+			delete(f.pending, v.Digest)
+		}
+	}
+	for _, s := range ss.Statuses {
+		if p, ok := f.held(s.Vertex); ok {
+			if p != nil {
+				p.Statuses = append(p.Statuses, s)
+			}
+			continue
+		}
+		out.Statuses = append(out.Statuses, s)
+	}
+	for _, l := range ss.Logs {
+		if p, ok := f.held(l.Vertex); ok {
+			if p != nil {
+				p.Logs = append(p.Logs, l)
+			}
+			continue
+		}
+		out.Logs = append(out.Logs, l)
+	}
+	for _, c := range ss.CacheMounts {
+		if p, ok := f.held(c.Vertex); ok {
+			if p != nil {
+				p.CacheMounts = append(p.CacheMounts, c)
+			}
+			continue
+		}
+		out.CacheMounts = append(out.CacheMounts, c)
+	}
+	return out
+}
+
+func (f *cachedFilter) pendingStatus(dgst digest.Digest) *client.SolveStatus {
+	p, ok := f.pending[dgst]
+	if !ok {
+		p = &client.SolveStatus{}
+		f.pending[dgst] = p
+	}
+	return p
+}
+
+// held returns true if the updates of the vertex are not shown. The updates
+// of pending vertexes are returned to be buffered, the ones of hidden vertexes
+// are dropped.
+func (f *cachedFilter) held(dgst digest.Digest) (*client.SolveStatus, bool) {
+	if _, ok := f.hidden[dgst]; ok {
+		return nil, true
+	}
+	p, ok := f.pending[dgst]
+	return p, ok
+}
@@ -0,0 +1,79 @@
+package progresswriter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/client"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedFilter(t *testing.T) {
+	t.Parallel()
+
+	cached := digest.FromBytes([]byte("cached"))
+	run := digest.FromBytes([]byte("run"))
+	now := time.Now()
+
+	in := make(chan *client.SolveStatus)
+	f := &cachedFilter{}
+	out := f.filter(in)
+
+	// the solver reports the vertexes before it knows if they are cached
+	go func() {
+		in <- &client.SolveStatus{
+			Vertexes: []*client.Vertex{
+				{Digest: cached, Name: "cached"},
+				{Digest: run, Name: "run"},
+			},
+		}
+		in <- &client.SolveStatus{
+			Vertexes: []*client.Vertex{
+				{Digest: cached, Name: "cached", Started: &now, Completed: &now, Cached: true},
+			},
+			Statuses: []*client.VertexStatus{
+				{Vertex: cached, ID: "load"},
+				{Vertex: run, ID: "resolve"},
+			},
+		}
+		in <- &client.SolveStatus{
+			Vertexes: []*client.Vertex{
+				{Digest: run, Name: "run", Started: &now},
+			},
+			Logs: []*client.VertexLog{
+				{Vertex: run, Data: []byte("bar")},
+			},
+		}
+		// vertexes that were shown are kept when they become cached
+		in <- &client.SolveStatus{
+			Vertexes: []*client.Vertex{
+				{Digest: run, Name: "run", Started: &now, Completed: &now, Cached: true},
+			},
+		}
+		close(in)
+	}()
+
+	ss := <-out
+	require.Equal(t, 0, len(ss.Vertexes))
+
+	ss = <-out
+	require.Equal(t, 0, len(ss.Vertexes))
+	require.Equal(t, 0, len(ss.Statuses))
+
+	ss = <-out
+	require.Equal(t, 1, len(ss.Vertexes))
+	require.Equal(t, run, ss.Vertexes[0].Digest)
+	require.Equal(t, 1, len(ss.Statuses))
+	require.Equal(t, "resolve", ss.Statuses[0].ID)
+	require.Equal(t, 1, len(ss.Logs))
+	require.Equal(t, "bar", string(ss.Logs[0].Data))
+
+	ss = <-out
+	require.Equal(t, 1, len(ss.Vertexes))
+	require.Equal(t, run, ss.Vertexes[0].Digest)
+
+	_, ok := <-out
+	require.False(t, ok)
+	require.Equal(t, 1, f.count)
+}
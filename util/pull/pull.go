@@ -22,11 +22,12 @@ import (
 	digest "github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 type Puller struct {
 	ContentStore content.Store
-	Resolver     *resolver.Resolver
+	Resolver     remotes.Resolver
 	Src          reference.Spec
 	Platform     ocispec.Platform
 
@@ -158,6 +159,12 @@ func (p *Puller) PullManifests(ctx context.Context) (*PulledManifests, error) {
 		return nil, err
 	}
 
+	if p.Src.Digest() == "" {
+		if err := imageutil.SetResolved(ctx, p.ContentStore, p.Src.String(), p.desc.Digest); err != nil {
+			logrus.Warnf("failed to record resolved digest of %s: %v", p.Src.String(), err)
+		}
+	}
+
 	if schema1Converter != nil {
 		p.desc, err = schema1Converter.Convert(ctx)
 		if err != nil {
@@ -195,11 +202,24 @@ func (p *Puller) PullManifests(ctx context.Context) (*PulledManifests, error) {
 		Nonlayers:        p.nonlayers,
 		Descriptors:      p.layers,
 		Provider: func(g session.Group) content.Provider {
-			return &provider{puller: p, resolver: p.Resolver.WithSession(g)}
+			return &provider{puller: p, resolver: withSession(p.Resolver, g)}
 		},
 	}, nil
 }
 
+// withSession returns the resolver for fetching blobs with the session group
+func withSession(r remotes.Resolver, g session.Group) remotes.Resolver {
+	switch r := r.(type) {
+	case *resolver.Resolver:
+		return r.WithSession(g)
+	case interface {
+		WithSession(session.Group) remotes.Resolver
+	}:
+		return r.WithSession(g)
+	}
+	return r
+}
+
 type provider struct {
 	puller   *Puller
 	resolver remotes.Resolver
@@ -14,6 +14,16 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// Actions of the statuses reported for a blob. Clients receive them as the
+// name of the vertex status.
+const (
+	actionDownloading = "downloading"
+	actionDone        = "done"
+	// actionFailed is reported when the reader of the blob is closed before
+	// the blob was written to the content store
+	actionFailed = "failed"
+)
+
 type PullManager interface {
 	content.IngestManager
 	content.Manager
@@ -102,6 +112,7 @@ func trackProgress(ctx context.Context, desc ocispec.Descriptor, manager PullMan
 	ingestRef := remotes.MakeRefKey(ctx, desc)
 
 	started := time.Now()
+	current, total := 0, int(desc.Size)
 	onFinalStatus := false
 	for !onFinalStatus {
 		select {
@@ -112,9 +123,17 @@ func trackProgress(ctx context.Context, desc ocispec.Descriptor, manager PullMan
 
 		status, err := manager.Status(ctx, ingestRef)
 		if err == nil {
+			current = int(status.Offset)
+			if status.Total > 0 {
+				total = int(status.Total)
+			}
+			if onFinalStatus {
+				break
+			}
 			pw.Write(desc.Digest.String(), progress.Status{
-				Current: int(status.Offset),
-				Total:   int(status.Total),
+				Action:  actionDownloading,
+				Current: current,
+				Total:   total,
 				Started: &started,
 			})
 			continue
@@ -126,6 +145,7 @@ func trackProgress(ctx context.Context, desc ocispec.Descriptor, manager PullMan
 		info, err := manager.Info(ctx, desc.Digest)
 		if err == nil {
 			pw.Write(desc.Digest.String(), progress.Status{
+				Action:    actionDone,
 				Current:   int(info.Size),
 				Total:     int(info.Size),
 				Started:   &started,
@@ -133,6 +153,16 @@ func trackProgress(ctx context.Context, desc ocispec.Descriptor, manager PullMan
 			})
 			return
 		}
-
 	}
+
+	// the reader was closed before the blob was committed, complete the
+	// status so that it is not left downloading
+	completed := time.Now()
+	pw.Write(desc.Digest.String(), progress.Status{
+		Action:    actionFailed,
+		Current:   current,
+		Total:     total,
+		Started:   &started,
+		Completed: &completed,
+	})
 }
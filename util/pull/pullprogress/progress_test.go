@@ -0,0 +1,193 @@
+package pullprogress
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/content/local"
+	"github.com/containerd/containerd/remotes"
+	"github.com/moby/buildkit/util/contentutil"
+	"github.com/moby/buildkit/util/progress"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+)
+
+func TestFetcherWithProgress(t *testing.T) {
+	t.Parallel()
+
+	cs, cleanup := newTestStore(t)
+	defer cleanup()
+
+	dt := bytes.Repeat([]byte("layer"), 1000)
+	desc := testDescriptor(dt)
+
+	statuses := collectStatuses(t, desc, func(ctx context.Context) {
+		f := &FetcherWithProgress{Fetcher: testFetcher{dt: dt}, Manager: cs}
+		rc, err := f.Fetch(ctx, desc)
+		require.NoError(t, err)
+		err = content.WriteBlob(ctx, cs, remotes.MakeRefKey(ctx, desc), rc, desc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+	})
+
+	require.True(t, len(statuses) > 0)
+	last := statuses[len(statuses)-1]
+	require.Equal(t, actionDone, last.Action)
+	require.Equal(t, len(dt), last.Current)
+	require.Equal(t, len(dt), last.Total)
+	require.NotNil(t, last.Completed)
+}
+
+func TestFetcherWithProgressTruncated(t *testing.T) {
+	t.Parallel()
+
+	cs, cleanup := newTestStore(t)
+	defer cleanup()
+
+	dt := bytes.Repeat([]byte("layer"), 1000)
+	desc := testDescriptor(dt)
+
+	statuses := collectStatuses(t, desc, func(ctx context.Context) {
+		f := &FetcherWithProgress{Fetcher: testFetcher{dt: dt[:len(dt)/2], readErr: io.ErrUnexpectedEOF}, Manager: cs}
+		rc, err := f.Fetch(ctx, desc)
+		require.NoError(t, err)
+		err = content.WriteBlob(ctx, cs, remotes.MakeRefKey(ctx, desc), rc, desc)
+		require.Error(t, err)
+		require.NoError(t, rc.Close())
+	})
+
+	// the status is completed as failed instead of being left downloading
+	require.True(t, len(statuses) > 0)
+	last := statuses[len(statuses)-1]
+	require.Equal(t, actionFailed, last.Action)
+	require.True(t, last.Current < len(dt), "current %d", last.Current)
+	require.Equal(t, len(dt), last.Total)
+	require.NotNil(t, last.Completed)
+}
+
+func TestFetcherWithProgressFetchError(t *testing.T) {
+	t.Parallel()
+
+	cs, cleanup := newTestStore(t)
+	defer cleanup()
+
+	dt := []byte("layer")
+	desc := testDescriptor(dt)
+
+	statuses := collectStatuses(t, desc, func(ctx context.Context) {
+		f := &FetcherWithProgress{Fetcher: testFetcher{fetchErr: errors.New("unauthorized")}, Manager: cs}
+		_, err := f.Fetch(ctx, desc)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unauthorized")
+	})
+
+	// blobs that were never fetched are not reported
+	require.Len(t, statuses, 0)
+}
+
+func TestProviderWithProgress(t *testing.T) {
+	t.Parallel()
+
+	src, cleanup := newTestStore(t)
+	defer cleanup()
+	cs, cleanup2 := newTestStore(t)
+	defer cleanup2()
+
+	dt := bytes.Repeat([]byte("layer"), 1000)
+	desc := testDescriptor(dt)
+	require.NoError(t, content.WriteBlob(context.TODO(), src, "src", bytes.NewReader(dt), desc))
+
+	statuses := collectStatuses(t, desc, func(ctx context.Context) {
+		p := &ProviderWithProgress{Provider: src, Manager: cs}
+		require.NoError(t, contentutil.Copy(ctx, cs, p, desc, nil))
+	})
+
+	require.True(t, len(statuses) > 0)
+	last := statuses[len(statuses)-1]
+	require.Equal(t, actionDone, last.Action)
+	require.Equal(t, len(dt), last.Current)
+	require.NotNil(t, last.Completed)
+}
+
+type testFetcher struct {
+	dt       []byte
+	readErr  error
+	fetchErr error
+}
+
+func (f testFetcher) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	if f.fetchErr != nil {
+		return nil, f.fetchErr
+	}
+	var r io.Reader = bytes.NewReader(f.dt)
+	if f.readErr != nil {
+		r = io.MultiReader(r, &errReader{err: f.readErr})
+	}
+	return ioutil.NopCloser(r), nil
+}
+
+type errReader struct {
+	err error
+}
+
+func (r *errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+func newTestStore(t *testing.T) (content.Store, func()) {
+	tmpdir, err := ioutil.TempDir("", "pullprogress")
+	require.NoError(t, err)
+	cs, err := local.NewStore(tmpdir)
+	if err != nil {
+		os.RemoveAll(tmpdir)
+	}
+	require.NoError(t, err)
+	return cs, func() { os.RemoveAll(tmpdir) }
+}
+
+func testDescriptor(dt []byte) ocispec.Descriptor {
+	return ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageLayerGzip,
+		Digest:    digest.FromBytes(dt),
+		Size:      int64(len(dt)),
+	}
+}
+
+// collectStatuses runs f with a progress context and returns the statuses
+// reported for the blob of desc
+func collectStatuses(t *testing.T, desc ocispec.Descriptor, f func(ctx context.Context)) []*progress.Status {
+	eg, ctx := errgroup.WithContext(context.Background())
+	pr, ctx, cancel := progress.NewContext(ctx)
+
+	var statuses []*progress.Status
+	eg.Go(func() error {
+		for {
+			p, err := pr.Read(context.Background())
+			if err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+			for _, p := range p {
+				if s, ok := p.Sys.(progress.Status); ok && p.ID == desc.Digest.String() {
+					statuses = append(statuses, &s)
+				}
+			}
+		}
+	})
+
+	f(ctx)
+
+	cancel()
+	require.NoError(t, eg.Wait())
+	return statuses
+}
@@ -0,0 +1,452 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/reference"
+	"github.com/containerd/containerd/remotes/docker"
+	remoteserrors "github.com/containerd/containerd/remotes/errors"
+	"github.com/moby/buildkit/util/progress"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// uploadChunkSize is the size of the chunks of a chunked blob upload. Blobs
+// that are smaller than a single chunk are uploaded with one request.
+const uploadChunkSize = 16 << 20
+
+// Actions of the statuses reported for the blobs pushed in chunks
+const (
+	actionPushing = "pushing"
+	actionExists  = "exists"
+	actionDone    = "done"
+)
+
+var errChunkedUploadUnsupported = errors.New("registry does not support chunked uploads")
+
+// chunkedPusher uploads blobs to a registry in chunks so that an upload that
+// fails midway can be resumed from the last offset acknowledged by the
+// registry instead of starting again. Uploads that fail are kept until the
+// blob is pushed again, so retrying the push of a blob resumes its upload.
+type chunkedPusher struct {
+	hosts     docker.RegistryHosts
+	refspec   reference.Spec
+	chunkSize int64
+	// lock waits for the other pushes of a blob to the repository
+	lock func(context.Context, digest.Digest) (func(), error)
+
+	mu          sync.Mutex
+	uploads     map[digest.Digest]*blobUpload
+	unsupported bool
+}
+
+type blobUpload struct {
+	host     docker.RegistryHost
+	location *url.URL
+	offset   int64
+}
+
+func newChunkedPusher(hosts docker.RegistryHosts, ref string, lock func(context.Context, digest.Digest) (func(), error)) (*chunkedPusher, error) {
+	refspec, err := reference.Parse(ref)
+	if err != nil {
+		return nil, err
+	}
+	return &chunkedPusher{
+		hosts:     hosts,
+		refspec:   refspec,
+		chunkSize: uploadChunkSize,
+		lock:      lock,
+		uploads:   map[digest.Digest]*blobUpload{},
+	}, nil
+}
+
+// handler returns a handler that pushes the blobs in chunks and falls back
+// to f for manifests, small blobs, blobs that can be mounted from another
+// repository and registries that don't support chunked uploads
+func (p *chunkedPusher) handler(provider content.Provider, f images.HandlerFunc) images.HandlerFunc {
+	return func(ctx context.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+		if !p.supports(desc) {
+			return f(ctx, desc)
+		}
+		release, err := p.lock(ctx, desc.Digest)
+		if err != nil {
+			return nil, err
+		}
+		err = p.push(ctx, provider, desc)
+		release()
+		if errors.Is(err, errChunkedUploadUnsupported) {
+			logrus.Debugf("falling back to monolithic upload of %s: %v", desc.Digest, err)
+			p.mu.Lock()
+			p.unsupported = true
+			p.mu.Unlock()
+			return f(ctx, desc)
+		}
+		return nil, err
+	}
+}
+
+func (p *chunkedPusher) supports(desc ocispec.Descriptor) bool {
+	switch desc.MediaType {
+	case images.MediaTypeDockerSchema2Manifest, ocispec.MediaTypeImageManifest,
+		images.MediaTypeDockerSchema2ManifestList, ocispec.MediaTypeImageIndex:
+		return false
+	}
+	if desc.Size <= p.chunkSize {
+		return false
+	}
+	if _, ok := desc.Annotations["containerd.io/distribution.source."+p.refspec.Hostname()]; ok {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return !p.unsupported
+}
+
+func (p *chunkedPusher) push(ctx context.Context, provider content.Provider, desc ocispec.Descriptor) error {
+	ctx, err := docker.ContextWithRepositoryScope(ctx, p.refspec, true)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	up, ok := p.uploads[desc.Digest]
+	p.mu.Unlock()
+
+	if ok {
+		if err := p.resume(ctx, up); err != nil {
+			var st remoteserrors.ErrUnexpectedStatus
+			if !errors.As(err, &st) || st.StatusCode != http.StatusNotFound {
+				return err
+			}
+			// the upload has expired in the registry, start again
+			ok = false
+		}
+	}
+	pw, _, _ := progress.NewFromContext(ctx)
+	defer pw.Close()
+	started := time.Now()
+	st := progress.Status{
+		Action:  actionPushing,
+		Total:   int(desc.Size),
+		Started: &started,
+	}
+
+	if !ok {
+		up, err = p.start(ctx, desc)
+		if err != nil {
+			return err
+		}
+		if up == nil {
+			st.Action = actionExists
+			st.Current = st.Total
+			st.Completed = &started
+			pw.Write(desc.Digest.String(), st)
+			return nil
+		}
+		p.mu.Lock()
+		p.uploads[desc.Digest] = up
+		p.mu.Unlock()
+	}
+
+	ra, err := provider.ReaderAt(ctx, desc)
+	if err != nil {
+		return err
+	}
+	defer ra.Close()
+
+	// the status is written once the registry acknowledged a chunk so that no
+	// status is left pending for the uploads that fall back to monolithic
+	// uploads
+	if up.offset > 0 {
+		st.Current = int(up.offset)
+		pw.Write(desc.Digest.String(), st)
+	}
+	for first := up.offset == 0; up.offset < desc.Size; first = false {
+		if err := p.patch(ctx, up, ra, desc.Size, first); err != nil {
+			return err
+		}
+		st.Current = int(up.offset)
+		pw.Write(desc.Digest.String(), st)
+	}
+
+	if err := p.commit(ctx, up, desc.Digest); err != nil {
+		return err
+	}
+	completed := time.Now()
+	st.Action = actionDone
+	st.Current = st.Total
+	st.Completed = &completed
+	pw.Write(desc.Digest.String(), st)
+
+	p.mu.Lock()
+	delete(p.uploads, desc.Digest)
+	p.mu.Unlock()
+	return nil
+}
+
+// start checks if the blob already exists and otherwise starts a new upload
+func (p *chunkedPusher) start(ctx context.Context, desc ocispec.Descriptor) (*blobUpload, error) {
+	hosts, err := p.hosts(p.refspec.Hostname())
+	if err != nil {
+		return nil, err
+	}
+	var host *docker.RegistryHost
+	for i := range hosts {
+		if hosts[i].Capabilities.Has(docker.HostCapabilityPush) {
+			host = &hosts[i]
+			break
+		}
+	}
+	if host == nil {
+		return nil, errors.Errorf("no push hosts for %s", p.refspec.Hostname())
+	}
+
+	u := &url.URL{
+		Scheme: host.Scheme,
+		Host:   host.Host,
+		Path:   path.Join(host.Path, p.repository(), "blobs", desc.Digest.String()),
+	}
+	resp, err := doRequest(ctx, *host, func() (*http.Request, error) {
+		return http.NewRequest(http.MethodHead, u.String(), nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil, nil
+	case http.StatusNotFound:
+	default:
+		return nil, remoteserrors.NewUnexpectedStatusErr(resp)
+	}
+
+	u.Path = path.Join(host.Path, p.repository(), "blobs", "uploads") + "/"
+	resp, err = doRequest(ctx, *host, func() (*http.Request, error) {
+		return http.NewRequest(http.MethodPost, u.String(), nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return nil, remoteserrors.NewUnexpectedStatusErr(resp)
+	}
+	up := &blobUpload{host: *host}
+	if err := up.setLocation(resp); err != nil {
+		return nil, err
+	}
+	return up, nil
+}
+
+// resume updates the offset of an upload that failed before from the status
+// of the upload in the registry
+func (p *chunkedPusher) resume(ctx context.Context, up *blobUpload) error {
+	resp, err := doRequest(ctx, up.host, func() (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, up.location.String(), nil)
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		return remoteserrors.NewUnexpectedStatusErr(resp)
+	}
+	if err := up.setLocation(resp); err != nil {
+		return err
+	}
+	return up.setOffset(resp)
+}
+
+func (p *chunkedPusher) patch(ctx context.Context, up *blobUpload, ra content.ReaderAt, size int64, first bool) error {
+	n := p.chunkSize
+	if size-up.offset < n {
+		n = size - up.offset
+	}
+	offset := up.offset
+	resp, err := doRequest(ctx, up.host, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPatch, up.location.String(), io.NewSectionReader(ra, offset, n))
+		if err != nil {
+			return nil, err
+		}
+		req.ContentLength = n
+		req.Header.Set("Content-Type", "application/octet-stream")
+		req.Header.Set("Content-Range", fmt.Sprintf("%d-%d", offset, offset+n-1))
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+	case http.StatusRequestedRangeNotSatisfiable:
+		if !first {
+			// the registry has a different offset, continue from there
+			return p.resume(ctx, up)
+		}
+		fallthrough
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		if first {
+			p.cancel(ctx, up)
+			return errors.Wrapf(errChunkedUploadUnsupported, "unexpected status %s", resp.Status)
+		}
+		return remoteserrors.NewUnexpectedStatusErr(resp)
+	default:
+		return remoteserrors.NewUnexpectedStatusErr(resp)
+	}
+
+	if err := up.setLocation(resp); err != nil {
+		return err
+	}
+	if resp.Header.Get("Range") == "" {
+		up.offset = offset + n
+		return nil
+	}
+	if err := up.setOffset(resp); err != nil {
+		return err
+	}
+	if first && up.offset != offset+n {
+		// the registry ignored the range of the chunk
+		p.cancel(ctx, up)
+		return errors.Wrapf(errChunkedUploadUnsupported, "unexpected offset %d after chunk", up.offset)
+	}
+	return nil
+}
+
+func (p *chunkedPusher) commit(ctx context.Context, up *blobUpload, dgst digest.Digest) error {
+	u := *up.location
+	q := u.Query()
+	q.Set("digest", dgst.String())
+	u.RawQuery = q.Encode()
+
+	resp, err := doRequest(ctx, up.host, func() (*http.Request, error) {
+		return http.NewRequest(http.MethodPut, u.String(), nil)
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		return remoteserrors.NewUnexpectedStatusErr(resp)
+	}
+	if actual := resp.Header.Get("Docker-Content-Digest"); actual != "" && actual != dgst.String() {
+		return errors.Errorf("got digest %s, expected %s", actual, dgst)
+	}
+	return nil
+}
+
+// cancel deletes an upload that is not going to be completed
+func (p *chunkedPusher) cancel(ctx context.Context, up *blobUpload) {
+	resp, err := doRequest(ctx, up.host, func() (*http.Request, error) {
+		return http.NewRequest(http.MethodDelete, up.location.String(), nil)
+	})
+	if err != nil {
+		logrus.Debugf("failed to cancel upload: %v", err)
+		return
+	}
+	resp.Body.Close()
+}
+
+func (p *chunkedPusher) repository() string {
+	return strings.TrimPrefix(p.refspec.Locator, p.refspec.Hostname()+"/")
+}
+
+// doRequest sends a request built by newReq to the registry. The request is
+// built again if it needs to be retried with authorization.
+func doRequest(ctx context.Context, host docker.RegistryHost, newReq func() (*http.Request, error)) (*http.Response, error) {
+	client := host.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	for retry := false; ; retry = true {
+		req, err := newReq()
+		if err != nil {
+			return nil, err
+		}
+		req = req.WithContext(ctx)
+		for k, v := range host.Header {
+			req.Header[k] = v
+		}
+		if host.Authorizer != nil {
+			if err := host.Authorizer.Authorize(ctx, req); err != nil {
+				return nil, errors.Wrap(err, "failed to authorize")
+			}
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusUnauthorized || host.Authorizer == nil || retry {
+			return resp, nil
+		}
+		err = host.Authorizer.AddResponses(ctx, []*http.Response{resp})
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (up *blobUpload) setLocation(resp *http.Response) error {
+	location := resp.Header.Get("Location")
+	if location == "" {
+		if up.location == nil {
+			return errors.New("missing location of upload")
+		}
+		return nil
+	}
+	u, err := resp.Request.URL.Parse(location)
+	if err != nil {
+		return errors.Wrapf(err, "unable to parse location %v", location)
+	}
+	if u.Host != up.host.Host || u.Scheme != up.host.Scheme {
+		// don't send the credentials to a different host
+		up.host.Host = u.Host
+		up.host.Scheme = u.Scheme
+		up.host.Authorizer = nil
+	}
+	up.location = u
+	return nil
+}
+
+// setOffset sets the offset of the upload from the "0-<end>" range of the
+// data received by the registry
+func (up *blobUpload) setOffset(resp *http.Response) error {
+	r := resp.Header.Get("Range")
+	if r == "" {
+		up.offset = 0
+		return nil
+	}
+	parts := strings.SplitN(strings.TrimPrefix(r, "bytes="), "-", 2)
+	if len(parts) != 2 {
+		return errors.Errorf("invalid upload range %q", r)
+	}
+	end, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "invalid upload range %q", r)
+	}
+	if end == 0 {
+		// registries report an empty upload as "0-0"
+		up.offset = 0
+		return nil
+	}
+	up.offset = end + 1
+	return nil
+}
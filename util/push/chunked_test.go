@@ -0,0 +1,281 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/moby/buildkit/util/contentutil"
+	"github.com/moby/buildkit/util/progress"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkedPush(t *testing.T) {
+	t.Parallel()
+
+	reg := newTestRegistry()
+	srv := httptest.NewServer(reg)
+	defer srv.Close()
+
+	p, provider, desc := newTestChunkedPusher(t, srv.URL, bytes.Repeat([]byte("0123456789"), 5))
+
+	fallback := false
+	h := p.handler(provider, func(ctx context.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+		fallback = true
+		return nil, nil
+	})
+
+	statuses := collectStatuses(t, desc, func(ctx context.Context) {
+		_, err := h(ctx, desc)
+		require.NoError(t, err)
+	})
+	require.False(t, fallback)
+	require.Equal(t, bytes.Repeat([]byte("0123456789"), 5), reg.blobs[desc.Digest])
+	require.Equal(t, 4, reg.patches)
+
+	// the pushed bytes are reported after each chunk, the reader of the
+	// progress may skip the statuses that it didn't read in time
+	require.True(t, len(statuses) > 0)
+	prev := 0
+	for _, st := range statuses {
+		require.Contains(t, []int{15, 30, 45, 50}, st.Current)
+		require.True(t, st.Current >= prev)
+		prev = st.Current
+	}
+	last := statuses[len(statuses)-1]
+	require.Equal(t, actionDone, last.Action)
+	require.Equal(t, 50, last.Total)
+	require.NotNil(t, last.Completed)
+
+	// pushing an existing blob doesn't upload it again
+	statuses = collectStatuses(t, desc, func(ctx context.Context) {
+		_, err := h(ctx, desc)
+		require.NoError(t, err)
+	})
+	require.Equal(t, 4, reg.patches)
+	require.Len(t, statuses, 1)
+	require.Equal(t, actionExists, statuses[0].Action)
+	require.NotNil(t, statuses[0].Completed)
+}
+
+func TestChunkedPushResume(t *testing.T) {
+	t.Parallel()
+
+	reg := newTestRegistry()
+	reg.failPatch = 3
+	srv := httptest.NewServer(reg)
+	defer srv.Close()
+
+	p, provider, desc := newTestChunkedPusher(t, srv.URL, bytes.Repeat([]byte("0123456789"), 5))
+
+	err := p.push(context.TODO(), provider, desc)
+	require.Error(t, err)
+	require.Equal(t, 3, reg.patches)
+
+	// the retry continues after the two chunks that were acknowledged
+	statuses := collectStatuses(t, desc, func(ctx context.Context) {
+		require.NoError(t, p.push(ctx, provider, desc))
+	})
+	require.True(t, len(statuses) > 0)
+	for _, st := range statuses {
+		require.True(t, st.Current >= 30, "current %d", st.Current)
+	}
+	require.Equal(t, bytes.Repeat([]byte("0123456789"), 5), reg.blobs[desc.Digest])
+	require.Equal(t, 5, reg.patches)
+	require.Equal(t, 0, len(p.uploads))
+}
+
+func TestChunkedPushFallback(t *testing.T) {
+	t.Parallel()
+
+	reg := newTestRegistry()
+	reg.noChunks = true
+	srv := httptest.NewServer(reg)
+	defer srv.Close()
+
+	p, provider, desc := newTestChunkedPusher(t, srv.URL, bytes.Repeat([]byte("0123456789"), 5))
+
+	fallback := 0
+	h := p.handler(provider, func(ctx context.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+		fallback++
+		return nil, nil
+	})
+
+	_, err := h(context.TODO(), desc)
+	require.NoError(t, err)
+	require.Equal(t, 1, fallback)
+	require.Equal(t, 1, reg.patches)
+	require.Equal(t, 0, len(reg.uploads))
+
+	// the registry isn't asked again for the next blob
+	_, err = h(context.TODO(), desc)
+	require.NoError(t, err)
+	require.Equal(t, 2, fallback)
+	require.Equal(t, 1, reg.patches)
+}
+
+// collectStatuses runs f with a progress context and returns the statuses
+// reported for the blob of desc
+func collectStatuses(t *testing.T, desc ocispec.Descriptor, f func(ctx context.Context)) []progress.Status {
+	pr, ctx, cancel := progress.NewContext(context.Background())
+	done := make(chan error, 1)
+	var statuses []progress.Status
+	go func() {
+		for {
+			p, err := pr.Read(context.Background())
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+				done <- err
+				return
+			}
+			for _, p := range p {
+				if st, ok := p.Sys.(progress.Status); ok && p.ID == desc.Digest.String() {
+					statuses = append(statuses, st)
+				}
+			}
+		}
+	}()
+
+	f(ctx)
+
+	cancel()
+	require.NoError(t, <-done)
+	return statuses
+}
+
+func newTestChunkedPusher(t *testing.T, srvURL string, dt []byte) (*chunkedPusher, content.Provider, ocispec.Descriptor) {
+	u, err := url.Parse(srvURL)
+	require.NoError(t, err)
+
+	p, err := newChunkedPusher(func(string) ([]docker.RegistryHost, error) {
+		return []docker.RegistryHost{{
+			Client:       http.DefaultClient,
+			Host:         u.Host,
+			Scheme:       u.Scheme,
+			Path:         "/v2",
+			Capabilities: docker.HostCapabilityPush,
+		}}, nil
+	}, u.Host+"/foo/bar:latest", func(context.Context, digest.Digest) (func(), error) {
+		return func() {}, nil
+	})
+	require.NoError(t, err)
+	p.chunkSize = 15
+
+	desc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageLayerGzip,
+		Digest:    digest.FromBytes(dt),
+		Size:      int64(len(dt)),
+	}
+	buf := contentutil.NewBuffer()
+	err = content.WriteBlob(context.TODO(), buf, "test", bytes.NewReader(dt), desc)
+	require.NoError(t, err)
+
+	return p, buf, desc
+}
+
+// testRegistry implements the blob upload API of a registry for the
+// repository foo/bar
+type testRegistry struct {
+	mu        sync.Mutex
+	blobs     map[digest.Digest][]byte
+	uploads   map[string][]byte
+	patches   int
+	failPatch int
+	noChunks  bool
+}
+
+func newTestRegistry() *testRegistry {
+	return &testRegistry{
+		blobs:   map[digest.Digest][]byte{},
+		uploads: map[string][]byte{},
+	}
+}
+
+func (r *testRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	const prefix = "/v2/foo/bar/blobs/"
+	if !strings.HasPrefix(req.URL.Path, prefix) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	p := strings.TrimPrefix(req.URL.Path, prefix)
+
+	switch {
+	case req.Method == http.MethodHead:
+		if _, ok := r.blobs[digest.Digest(p)]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case req.Method == http.MethodPost && p == "uploads/":
+		id := fmt.Sprintf("%d", len(r.uploads)+len(r.blobs))
+		r.uploads[id] = nil
+		w.Header().Set("Location", "/v2/foo/bar/blobs/uploads/"+id)
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		id := strings.TrimPrefix(p, "uploads/")
+		dt, ok := r.uploads[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch req.Method {
+		case http.MethodGet:
+			end := len(dt) - 1
+			if end < 0 {
+				end = 0
+			}
+			w.Header().Set("Range", fmt.Sprintf("0-%d", end))
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodPatch:
+			r.patches++
+			if r.noChunks {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			if r.patches == r.failPatch {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if req.Header.Get("Content-Range") != fmt.Sprintf("%d-%d", len(dt), len(dt)+int(req.ContentLength)-1) {
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+				return
+			}
+			chunk, _ := ioutil.ReadAll(req.Body)
+			dt = append(dt, chunk...)
+			r.uploads[id] = dt
+			w.Header().Set("Location", req.URL.Path)
+			w.Header().Set("Range", fmt.Sprintf("0-%d", len(dt)-1))
+			w.WriteHeader(http.StatusAccepted)
+		case http.MethodPut:
+			dgst := digest.Digest(req.URL.Query().Get("digest"))
+			if dgst != digest.FromBytes(dt) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			delete(r.uploads, id)
+			r.blobs[dgst] = dt
+			w.Header().Set("Docker-Content-Digest", dgst.String())
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodDelete:
+			delete(r.uploads, id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
@@ -82,11 +82,22 @@ func Push(ctx context.Context, sm *session.Manager, sid string, provider content
 			m.Unlock()
 			return nil, images.ErrStopHandler
 		default:
+			// foreign layers are fetched from their urls and not pushed
+			if images.IsNonDistributable(desc.MediaType) {
+				return nil, images.ErrStopHandler
+			}
 			return nil, nil
 		}
 	})
 
-	pushHandler := retryhandler.New(remotes.PushHandler(pusher, provider), logs.LoggerFromContext(ctx))
+	chunked, err := newChunkedPusher(resolver.HostsFunc, ref, func(ctx context.Context, dgst digest.Digest) (func(), error) {
+		return resolver.LockPush(ctx, ref, dgst)
+	})
+	if err != nil {
+		return err
+	}
+
+	pushHandler := retryhandler.New(chunked.handler(provider, remotes.PushHandler(pusher, provider)), logs.LoggerFromContext(ctx))
 	pushUpdateSourceHandler, err := updateDistributionSourceHandler(manager, pushHandler, ref)
 	if err != nil {
 		return err
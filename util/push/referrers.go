@@ -0,0 +1,365 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/containerd/containerd/content"
+	ctdreference "github.com/containerd/containerd/reference"
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	remoteserrors "github.com/containerd/containerd/remotes/errors"
+	"github.com/docker/distribution/reference"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/util/contentutil"
+	"github.com/moby/buildkit/util/resolver"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MediaTypeInToto is the media type of in-toto statements
+	MediaTypeInToto = "application/vnd.in-toto+json"
+
+	mediaTypeEmptyJSON      = "application/vnd.oci.empty.v1+json"
+	annotationPredicateType = "in-toto.io/predicate-type"
+
+	// maxReferrersIndexSize limits the size of the fallback referrers index
+	maxReferrersIndexSize = 4 << 20
+)
+
+// Attestation is an in-toto statement about an image
+type Attestation struct {
+	PredicateType string
+	Statement     []byte
+}
+
+// referrerManifest is an OCI image manifest with the artifactType and
+// subject fields of the referrers API
+type referrerManifest struct {
+	specs.Versioned
+	MediaType    string               `json:"mediaType"`
+	ArtifactType string               `json:"artifactType"`
+	Config       ocispec.Descriptor   `json:"config"`
+	Layers       []ocispec.Descriptor `json:"layers"`
+	Subject      *ocispec.Descriptor  `json:"subject,omitempty"`
+}
+
+// referrersIndex is the image index of the referrers of a manifest that is
+// tagged "<algorithm>-<hex>" for registries without the referrers API
+type referrersIndex struct {
+	specs.Versioned
+	MediaType string               `json:"mediaType"`
+	Manifests []referrerDescriptor `json:"manifests"`
+}
+
+type referrerDescriptor struct {
+	ocispec.Descriptor
+	ArtifactType string `json:"artifactType,omitempty"`
+}
+
+// PushReferrers pushes every attestation as a separate manifest whose subject
+// is the manifest subject of the repository of ref. Registries without the
+// referrers API get the manifests added to the index tagged with the digest
+// of subject instead. The updates of the index of a subject are serialized
+// within the daemon.
+func PushReferrers(ctx context.Context, sm *session.Manager, sid string, subject ocispec.Descriptor, ref string, insecure bool, hosts docker.RegistryHosts, attestations []Attestation) error {
+	parsed, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return err
+	}
+	name := reference.TrimNamed(parsed).String()
+
+	scope := "push"
+	if insecure {
+		insecureTrue := true
+		httpTrue := true
+		hosts = resolver.NewRegistryConfig(map[string]resolver.RegistryConfig{
+			reference.Domain(parsed): {
+				Insecure:  &insecureTrue,
+				PlainHTTP: &httpTrue,
+			},
+		})
+		scope += ":insecure"
+	}
+
+	res := resolver.DefaultPool.GetResolver(hosts, name, scope, sm, session.NewGroup(sid))
+	pusher, err := res.Pusher(ctx, name)
+	if err != nil {
+		return err
+	}
+	rp, err := newReferrersPusher(res.HostsFunc, name)
+	if err != nil {
+		return err
+	}
+
+	done := oneOffProgress(ctx, fmt.Sprintf("pushing attestations for %s@%s", name, subject.Digest))
+	return done(rp.pushAll(ctx, pusher, subject, attestations))
+}
+
+// indexLocks serializes the updates of the fallback referrers indexes so that
+// concurrent pushes for the same subject don't drop each other's manifests
+var indexLocks = &keyedLocks{m: map[string]*keyedLock{}}
+
+type keyedLocks struct {
+	mu sync.Mutex
+	m  map[string]*keyedLock
+}
+
+type keyedLock struct {
+	sync.Mutex
+	waiters int
+}
+
+func (l *keyedLocks) lock(key string) func() {
+	l.mu.Lock()
+	kl, ok := l.m[key]
+	if !ok {
+		kl = &keyedLock{}
+		l.m[key] = kl
+	}
+	kl.waiters++
+	l.mu.Unlock()
+
+	kl.Lock()
+	return func() {
+		kl.Unlock()
+		l.mu.Lock()
+		kl.waiters--
+		if kl.waiters == 0 {
+			delete(l.m, key)
+		}
+		l.mu.Unlock()
+	}
+}
+
+type referrersPusher struct {
+	hosts   docker.RegistryHosts
+	refspec ctdreference.Spec
+}
+
+func newReferrersPusher(hosts docker.RegistryHosts, name string) (*referrersPusher, error) {
+	refspec, err := ctdreference.Parse(name)
+	if err != nil {
+		return nil, err
+	}
+	return &referrersPusher{hosts: hosts, refspec: refspec}, nil
+}
+
+// pushAll pushes the manifests of the attestations and adds the ones the
+// registry didn't index by their subject to the fallback index in a single
+// update
+func (p *referrersPusher) pushAll(ctx context.Context, pusher remotes.Pusher, subject ocispec.Descriptor, attestations []Attestation) error {
+	ctx, err := docker.ContextWithRepositoryScope(ctx, p.refspec, true)
+	if err != nil {
+		return err
+	}
+	host, err := p.host()
+	if err != nil {
+		return err
+	}
+	var unindexed []referrerDescriptor
+	for _, a := range attestations {
+		desc, indexed, err := p.push(ctx, host, pusher, subject, a)
+		if err != nil {
+			return err
+		}
+		if !indexed {
+			unindexed = append(unindexed, desc)
+		}
+	}
+	if len(unindexed) == 0 {
+		return nil
+	}
+	unlock := indexLocks.lock(p.url(host, "manifests", fallbackTag(subject.Digest)))
+	defer unlock()
+	return p.addToIndex(ctx, host, subject.Digest, unindexed)
+}
+
+// push pushes the manifest of the attestation a and returns its descriptor
+// and whether the registry indexed it by its subject
+func (p *referrersPusher) push(ctx context.Context, host docker.RegistryHost, pusher remotes.Pusher, subject ocispec.Descriptor, a Attestation) (referrerDescriptor, bool, error) {
+	buf := contentutil.NewBuffer()
+	config, err := writeBlob(ctx, buf, mediaTypeEmptyJSON, []byte("{}"), nil)
+	if err != nil {
+		return referrerDescriptor{}, false, err
+	}
+	layer, err := writeBlob(ctx, buf, MediaTypeInToto, a.Statement, map[string]string{
+		annotationPredicateType: a.PredicateType,
+	})
+	if err != nil {
+		return referrerDescriptor{}, false, err
+	}
+	for _, desc := range []ocispec.Descriptor{config, layer} {
+		if _, err := remotes.PushHandler(pusher, buf)(ctx, desc); err != nil {
+			return referrerDescriptor{}, false, errors.Wrapf(err, "failed to push %s", desc.Digest)
+		}
+	}
+
+	subject = ocispec.Descriptor{
+		MediaType: subject.MediaType,
+		Digest:    subject.Digest,
+		Size:      subject.Size,
+	}
+	dt, err := json.Marshal(referrerManifest{
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: MediaTypeInToto,
+		Config:       config,
+		Layers:       []ocispec.Descriptor{layer},
+		Subject:      &subject,
+	})
+	if err != nil {
+		return referrerDescriptor{}, false, errors.WithStack(err)
+	}
+	mfst := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Digest:    digest.FromBytes(dt),
+		Size:      int64(len(dt)),
+		Annotations: map[string]string{
+			annotationPredicateType: a.PredicateType,
+		},
+	}
+
+	resp, err := p.putManifest(ctx, host, mfst.Digest.String(), mfst.MediaType, dt)
+	if err != nil {
+		return referrerDescriptor{}, false, err
+	}
+	// registries with the referrers API index the manifest by its subject
+	indexed := resp.Header.Get("OCI-Subject") == subject.Digest.String()
+	return referrerDescriptor{Descriptor: mfst, ArtifactType: MediaTypeInToto}, indexed, nil
+}
+
+// addToIndex adds descs to the referrers index tagged with the digest of the
+// subject. The index is created if it doesn't exist.
+func (p *referrersPusher) addToIndex(ctx context.Context, host docker.RegistryHost, subject digest.Digest, descs []referrerDescriptor) error {
+	tag := fallbackTag(subject)
+	u := p.url(host, "manifests", tag)
+	resp, err := doRequest(ctx, host, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", ocispec.MediaTypeImageIndex)
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	idx := referrersIndex{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		dt, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxReferrersIndexSize))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if err := json.Unmarshal(dt, &idx); err != nil {
+			return errors.Wrapf(err, "invalid referrers index %s", tag)
+		}
+	case http.StatusNotFound:
+	default:
+		return remoteserrors.NewUnexpectedStatusErr(resp)
+	}
+
+	existing := map[digest.Digest]struct{}{}
+	for _, m := range idx.Manifests {
+		existing[m.Digest] = struct{}{}
+	}
+	var added bool
+	for _, desc := range descs {
+		if _, ok := existing[desc.Digest]; ok {
+			continue
+		}
+		existing[desc.Digest] = struct{}{}
+		idx.Manifests = append(idx.Manifests, desc)
+		added = true
+	}
+	if !added {
+		return nil
+	}
+
+	dt, err := json.Marshal(idx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = p.putManifest(ctx, host, tag, ocispec.MediaTypeImageIndex, dt)
+	return err
+}
+
+func (p *referrersPusher) putManifest(ctx context.Context, host docker.RegistryHost, object, mediaType string, dt []byte) (*http.Response, error) {
+	u := p.url(host, "manifests", object)
+	resp, err := doRequest(ctx, host, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPut, u, bytes.NewReader(dt))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", mediaType)
+		return req, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return nil, errors.Wrapf(remoteserrors.NewUnexpectedStatusErr(resp), "failed to push manifest %s", object)
+	}
+	return resp, nil
+}
+
+func (p *referrersPusher) host() (docker.RegistryHost, error) {
+	hosts, err := p.hosts(p.refspec.Hostname())
+	if err != nil {
+		return docker.RegistryHost{}, err
+	}
+	for _, h := range hosts {
+		if h.Capabilities.Has(docker.HostCapabilityPush) {
+			return h, nil
+		}
+	}
+	return docker.RegistryHost{}, errors.Errorf("no push hosts for %s", p.refspec.Hostname())
+}
+
+func (p *referrersPusher) url(host docker.RegistryHost, elem ...string) string {
+	repo := strings.TrimPrefix(p.refspec.Locator, p.refspec.Hostname()+"/")
+	u := &url.URL{
+		Scheme: host.Scheme,
+		Host:   host.Host,
+		Path:   path.Join(append([]string{host.Path, repo}, elem...)...),
+	}
+	return u.String()
+}
+
+// fallbackTag returns the tag of the referrers index of dgst
+func fallbackTag(dgst digest.Digest) string {
+	return fmt.Sprintf("%s-%s", dgst.Algorithm(), dgst.Hex())
+}
+
+func writeBlob(ctx context.Context, buf contentutil.Buffer, mediaType string, dt []byte, annotations map[string]string) (ocispec.Descriptor, error) {
+	desc := ocispec.Descriptor{
+		MediaType:   mediaType,
+		Digest:      digest.FromBytes(dt),
+		Size:        int64(len(dt)),
+		Annotations: annotations,
+	}
+	if err := content.WriteBlob(ctx, buf, desc.Digest.String(), bytes.NewReader(dt), desc); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return desc, nil
+}
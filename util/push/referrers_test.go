@@ -0,0 +1,204 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/moby/buildkit/util/contentutil"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushReferrers(t *testing.T) {
+	t.Parallel()
+
+	for _, referrersAPI := range []bool{true, false} {
+		reg := &testManifestRegistry{manifests: map[string][]byte{}, referrersAPI: referrersAPI}
+		srv := httptest.NewServer(reg)
+		defer srv.Close()
+
+		u, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+		p, err := newReferrersPusher(func(string) ([]docker.RegistryHost, error) {
+			return []docker.RegistryHost{{
+				Client:       http.DefaultClient,
+				Host:         u.Host,
+				Scheme:       u.Scheme,
+				Path:         "/v2",
+				Capabilities: docker.HostCapabilityPush,
+			}}, nil
+		}, u.Host+"/foo/bar")
+		require.NoError(t, err)
+
+		subject := ocispec.Descriptor{
+			MediaType: ocispec.MediaTypeImageManifest,
+			Digest:    digest.FromString("image"),
+			Size:      5,
+		}
+		blobs := contentutil.NewBuffer()
+		pusher := &bufferPusher{blobs}
+
+		var attestations []Attestation
+		for _, pt := range []string{"https://slsa.dev/provenance/v0.2", "https://spdx.dev/Document"} {
+			attestations = append(attestations, Attestation{
+				PredicateType: pt,
+				Statement:     []byte(`{"predicateType":"` + pt + `"}`),
+			})
+		}
+		err = p.pushAll(context.TODO(), pusher, subject, attestations)
+		require.NoError(t, err)
+
+		// the statements are pushed as layers of manifests with the image
+		// as subject
+		var mfsts []referrerManifest
+		for k, dt := range reg.manifests {
+			if !strings.HasPrefix(k, "sha256:") {
+				continue
+			}
+			var m referrerManifest
+			require.NoError(t, json.Unmarshal(dt, &m))
+			require.Equal(t, MediaTypeInToto, m.ArtifactType)
+			require.Equal(t, subject, *m.Subject)
+			require.Equal(t, 1, len(m.Layers))
+			_, err := content.ReadBlob(context.TODO(), blobs, m.Layers[0])
+			require.NoError(t, err)
+			_, err = content.ReadBlob(context.TODO(), blobs, m.Config)
+			require.NoError(t, err)
+			mfsts = append(mfsts, m)
+		}
+		require.Equal(t, 2, len(mfsts))
+
+		dt, ok := reg.manifests["sha256-"+subject.Digest.Hex()]
+		if referrersAPI {
+			require.False(t, ok)
+			continue
+		}
+		require.True(t, ok)
+		var idx referrersIndex
+		require.NoError(t, json.Unmarshal(dt, &idx))
+		require.Equal(t, ocispec.MediaTypeImageIndex, idx.MediaType)
+		require.Equal(t, 2, len(idx.Manifests))
+		require.Equal(t, "https://slsa.dev/provenance/v0.2", idx.Manifests[0].Annotations[annotationPredicateType])
+		require.Equal(t, "https://spdx.dev/Document", idx.Manifests[1].Annotations[annotationPredicateType])
+		for _, m := range idx.Manifests {
+			require.Equal(t, MediaTypeInToto, m.ArtifactType)
+			_, ok := reg.manifests[m.Digest.String()]
+			require.True(t, ok)
+		}
+	}
+}
+
+func TestPushReferrersConcurrent(t *testing.T) {
+	t.Parallel()
+
+	reg := &testManifestRegistry{manifests: map[string][]byte{}}
+	srv := httptest.NewServer(reg)
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	p, err := newReferrersPusher(func(string) ([]docker.RegistryHost, error) {
+		return []docker.RegistryHost{{
+			Client:       http.DefaultClient,
+			Host:         u.Host,
+			Scheme:       u.Scheme,
+			Path:         "/v2",
+			Capabilities: docker.HostCapabilityPush,
+		}}, nil
+	}, u.Host+"/foo/bar")
+	require.NoError(t, err)
+
+	subject := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Digest:    digest.FromString("image"),
+		Size:      5,
+	}
+	pusher := &bufferPusher{contentutil.NewBuffer()}
+
+	// concurrent builds of the same image don't drop each other's
+	// manifests from the fallback index
+	const n = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- p.pushAll(context.TODO(), pusher, subject, []Attestation{{
+				PredicateType: "https://spdx.dev/Document",
+				Statement:     []byte(`{"build":` + strconv.Itoa(i) + `}`),
+			}})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	var idx referrersIndex
+	require.NoError(t, json.Unmarshal(reg.manifests["sha256-"+subject.Digest.Hex()], &idx))
+	require.Equal(t, n, len(idx.Manifests))
+}
+
+type bufferPusher struct {
+	buf contentutil.Buffer
+}
+
+func (p *bufferPusher) Push(ctx context.Context, desc ocispec.Descriptor) (content.Writer, error) {
+	return p.buf.Writer(ctx, content.WithRef(desc.Digest.String()), content.WithDescriptor(desc))
+}
+
+// testManifestRegistry implements the manifest API of a registry for the
+// repository foo/bar
+type testManifestRegistry struct {
+	mu           sync.Mutex
+	manifests    map[string][]byte
+	referrersAPI bool
+}
+
+func (r *testManifestRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	const prefix = "/v2/foo/bar/manifests/"
+	if !strings.HasPrefix(req.URL.Path, prefix) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	object := strings.TrimPrefix(req.URL.Path, prefix)
+	switch req.Method {
+	case http.MethodGet:
+		dt, ok := r.manifests[object]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(dt)
+	case http.MethodPut:
+		dt, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.manifests[object] = dt
+		var m referrerManifest
+		if r.referrersAPI && json.Unmarshal(dt, &m) == nil && m.Subject != nil {
+			w.Header().Set("OCI-Subject", m.Subject.Digest.String())
+		}
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
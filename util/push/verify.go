@@ -0,0 +1,160 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/docker/distribution/reference"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/util/resolver"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+)
+
+// verifyConcurrency is the number of blobs that are checked in parallel
+const verifyConcurrency = 8
+
+// maxManifestSize is the largest manifest that is fetched for verification
+const maxManifestSize = 4 << 20
+
+// Verify checks that the image with the root manifest dgst that was pushed to
+// ref can be pulled. The manifests are fetched again from the registry and
+// all the blobs that they reference must exist in the repository with the
+// expected sizes.
+func Verify(ctx context.Context, sm *session.Manager, sid string, dgst digest.Digest, ref string, insecure bool, hosts docker.RegistryHosts) error {
+	parsed, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return err
+	}
+	name := reference.TrimNamed(parsed).String()
+	ref = name + "@" + dgst.String()
+
+	scope := "pull"
+	if insecure {
+		insecureTrue := true
+		httpTrue := true
+		hosts = resolver.NewRegistryConfig(map[string]resolver.RegistryConfig{
+			reference.Domain(parsed): {
+				Insecure:  &insecureTrue,
+				PlainHTTP: &httpTrue,
+			},
+		})
+		scope += ":insecure"
+	}
+
+	res := resolver.DefaultPool.GetResolver(hosts, ref, scope, sm, session.NewGroup(sid))
+
+	verifyDone := oneOffProgress(ctx, fmt.Sprintf("verifying %s", ref))
+	return verifyDone(verify(ctx, res, name, dgst))
+}
+
+// verify fetches the manifests of the image dgst in the repository name and
+// checks that the blobs that they reference exist
+func verify(ctx context.Context, r remotes.Resolver, name string, dgst digest.Digest) error {
+	ref := name + "@" + dgst.String()
+	_, desc, err := r.Resolve(ctx, ref)
+	if err != nil {
+		return errors.Wrapf(err, "failed to resolve pushed manifest %s", ref)
+	}
+	fetcher, err := r.Fetcher(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	blobs := map[digest.Digest]ocispec.Descriptor{}
+	parents := map[digest.Digest]digest.Digest{}
+	manifests := []ocispec.Descriptor{desc}
+	seen := map[digest.Digest]struct{}{}
+	for len(manifests) > 0 {
+		desc := manifests[0]
+		manifests = manifests[1:]
+		if _, ok := seen[desc.Digest]; ok {
+			continue
+		}
+		seen[desc.Digest] = struct{}{}
+
+		children, err := fetchChildren(ctx, fetcher, desc)
+		if err != nil {
+			return err
+		}
+		for _, c := range children {
+			switch c.MediaType {
+			case images.MediaTypeDockerSchema2Manifest, ocispec.MediaTypeImageManifest,
+				images.MediaTypeDockerSchema2ManifestList, ocispec.MediaTypeImageIndex:
+				manifests = append(manifests, c)
+			default:
+				// foreign layers are fetched from their urls and not pushed
+				if images.IsNonDistributable(c.MediaType) {
+					continue
+				}
+				blobs[c.Digest] = c
+				parents[c.Digest] = desc.Digest
+			}
+		}
+	}
+
+	sem := semaphore.NewWeighted(verifyConcurrency)
+	eg, ctx := errgroup.WithContext(ctx)
+	for _, desc := range blobs {
+		desc := desc
+		eg.Go(func() error {
+			if err := sem.Acquire(ctx, 1); err != nil {
+				return err
+			}
+			defer sem.Release(1)
+			_, found, err := r.Resolve(ctx, name+"@"+desc.Digest.String())
+			if err != nil {
+				return errors.Wrapf(err, "blob %s referenced by %s is not available in the registry", desc.Digest, parents[desc.Digest])
+			}
+			if found.Size != desc.Size {
+				return errors.Errorf("blob %s referenced by %s has size %d in the registry, expected %d", desc.Digest, parents[desc.Digest], found.Size, desc.Size)
+			}
+			return nil
+		})
+	}
+	return eg.Wait()
+}
+
+// fetchChildren returns the descriptors referenced by a manifest or an index
+func fetchChildren(ctx context.Context, fetcher remotes.Fetcher, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+	rc, err := fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch manifest %s", desc.Digest)
+	}
+	defer rc.Close()
+	dt, err := ioutil.ReadAll(io.LimitReader(rc, maxManifestSize+1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read manifest %s", desc.Digest)
+	}
+	if len(dt) > maxManifestSize {
+		return nil, errors.Errorf("manifest %s is too large", desc.Digest)
+	}
+	if desc.Digest.Algorithm().Available() && desc.Digest.Algorithm().FromBytes(dt) != desc.Digest {
+		return nil, errors.Errorf("manifest %s in the registry doesn't match its digest", desc.Digest)
+	}
+
+	var m struct {
+		Config    *ocispec.Descriptor  `json:"config,omitempty"`
+		Layers    []ocispec.Descriptor `json:"layers,omitempty"`
+		Manifests []ocispec.Descriptor `json:"manifests,omitempty"`
+	}
+	if err := json.Unmarshal(dt, &m); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse manifest %s", desc.Digest)
+	}
+	var out []ocispec.Descriptor
+	if m.Config != nil {
+		out = append(out, *m.Config)
+	}
+	out = append(out, m.Layers...)
+	out = append(out, m.Manifests...)
+	return out, nil
+}
@@ -0,0 +1,106 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/remotes/docker"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	reg := &testPullRegistry{manifests: map[digest.Digest]testBlob{}, blobs: map[digest.Digest]testBlob{}}
+	srv := httptest.NewServer(reg)
+	defer srv.Close()
+
+	config := reg.addBlob(images.MediaTypeDockerSchema2Config, []byte("{}"))
+	layer1 := reg.addBlob(images.MediaTypeDockerSchema2LayerGzip, []byte("layer1"))
+	layer2 := reg.addBlob(images.MediaTypeDockerSchema2LayerGzip, []byte("layer2"))
+	foreign := ocispec.Descriptor{MediaType: images.MediaTypeDockerSchema2LayerForeignGzip, Digest: digest.FromString("foreign"), Size: 7}
+
+	mfst := reg.addManifest(t, images.MediaTypeDockerSchema2Manifest, map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     images.MediaTypeDockerSchema2Manifest,
+		"config":        config,
+		"layers":        []ocispec.Descriptor{layer1, layer2, foreign},
+	})
+	idx := reg.addManifest(t, images.MediaTypeDockerSchema2ManifestList, map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     images.MediaTypeDockerSchema2ManifestList,
+		"manifests":     []ocispec.Descriptor{mfst},
+	})
+
+	r := docker.NewResolver(docker.ResolverOptions{
+		Hosts: docker.ConfigureDefaultRegistries(docker.WithPlainHTTP(docker.MatchAllHosts)),
+	})
+	name := strings.TrimPrefix(srv.URL, "http://") + "/foo/bar"
+
+	require.NoError(t, verify(context.TODO(), r, name, idx.Digest))
+
+	reg.blobs[layer2.Digest] = testBlob{mediaType: layer2.MediaType, data: []byte("layer")}
+	err := verify(context.TODO(), r, name, idx.Digest)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "has size 5 in the registry, expected 6")
+
+	delete(reg.blobs, layer2.Digest)
+	err = verify(context.TODO(), r, name, idx.Digest)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "blob "+layer2.Digest.String()+" referenced by "+mfst.Digest.String()+" is not available")
+}
+
+type testBlob struct {
+	mediaType string
+	data      []byte
+}
+
+// testPullRegistry serves the manifests and blobs of the repository foo/bar
+type testPullRegistry struct {
+	manifests map[digest.Digest]testBlob
+	blobs     map[digest.Digest]testBlob
+}
+
+func (r *testPullRegistry) addBlob(mediaType string, dt []byte) ocispec.Descriptor {
+	dgst := digest.FromBytes(dt)
+	r.blobs[dgst] = testBlob{mediaType: mediaType, data: dt}
+	return ocispec.Descriptor{MediaType: mediaType, Digest: dgst, Size: int64(len(dt))}
+}
+
+func (r *testPullRegistry) addManifest(t *testing.T, mediaType string, v interface{}) ocispec.Descriptor {
+	dt, err := json.Marshal(v)
+	require.NoError(t, err)
+	dgst := digest.FromBytes(dt)
+	r.manifests[dgst] = testBlob{mediaType: mediaType, data: dt}
+	return ocispec.Descriptor{MediaType: mediaType, Digest: dgst, Size: int64(len(dt))}
+}
+
+func (r *testPullRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var b testBlob
+	var ok bool
+	switch p := req.URL.Path; {
+	case strings.HasPrefix(p, "/v2/foo/bar/manifests/"):
+		b, ok = r.manifests[digest.Digest(strings.TrimPrefix(p, "/v2/foo/bar/manifests/"))]
+	case strings.HasPrefix(p, "/v2/foo/bar/blobs/"):
+		b, ok = r.blobs[digest.Digest(strings.TrimPrefix(p, "/v2/foo/bar/blobs/"))]
+	}
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", b.mediaType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(b.data)))
+	w.Header().Set("Docker-Content-Digest", digest.FromBytes(b.data).String())
+	w.WriteHeader(http.StatusOK)
+	if req.Method == http.MethodGet {
+		w.Write(b.data)
+	}
+}
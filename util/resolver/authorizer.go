@@ -30,13 +30,17 @@ type authHandlerNS struct {
 	hosts    map[string][]docker.RegistryHost
 	sm       *session.Manager
 	g        flightcontrol.Group
+	// role is sent with the credential requests so that the client can use
+	// different credentials for pulling and pushing
+	role string
 }
 
-func newAuthHandlerNS(sm *session.Manager) *authHandlerNS {
+func newAuthHandlerNS(sm *session.Manager, role string) *authHandlerNS {
 	return &authHandlerNS{
 		handlers: map[string]*authHandler{},
 		hosts:    map[string][]docker.RegistryHost{},
 		sm:       sm,
+		role:     role,
 	}
 }
 
@@ -65,14 +69,14 @@ func (a *authHandlerNS) get(ctx context.Context, host string, sm *session.Manage
 		}
 		if parts[0] == host {
 			if h.authority != nil {
-				session, ok, err := sessionauth.VerifyTokenAuthority(ctx, host, h.authority, sm, g)
+				session, ok, err := sessionauth.VerifyTokenAuthority(ctx, host, a.role, h.authority, sm, g)
 				if err == nil && ok {
 					a.handlers[host+"/"+session] = h
 					h.lastUsed = time.Now()
 					return h
 				}
 			} else {
-				session, username, password, err := sessionauth.CredentialsFunc(sm, g)(host)
+				session, username, password, err := sessionauth.CredentialsFunc(sm, g, a.role)(host)
 				if err == nil {
 					if username == h.common.Username && password == h.common.Secret {
 						a.handlers[host+"/"+session] = h
@@ -137,7 +141,7 @@ func (a *dockerAuthorizer) Authorize(ctx context.Context, req *http.Request) err
 }
 
 func (a *dockerAuthorizer) getCredentials(host string) (sessionID, username, secret string, err error) {
-	return sessionauth.CredentialsFunc(a.sm, a.session)(host)
+	return sessionauth.CredentialsFunc(a.sm, a.session, a.handlers.role)(host)
 }
 
 func (a *dockerAuthorizer) AddResponses(ctx context.Context, responses []*http.Response) error {
@@ -177,7 +181,7 @@ func (a *dockerAuthorizer) AddResponses(ctx context.Context, responses []*http.R
 			}
 
 			var username, secret string
-			session, pubKey, err := sessionauth.GetTokenAuthority(ctx, host, a.sm, a.session)
+			session, pubKey, err := sessionauth.GetTokenAuthority(ctx, host, a.handlers.role, a.sm, a.session)
 			if err != nil {
 				return err
 			}
@@ -194,7 +198,7 @@ func (a *dockerAuthorizer) AddResponses(ctx context.Context, responses []*http.R
 			}
 			common.Scopes = parseScopes(append(common.Scopes, oldScopes...)).normalize()
 
-			a.handlers.set(host, session, newAuthHandler(host, a.client, c.Scheme, pubKey, common))
+			a.handlers.set(host, session, newAuthHandler(host, a.handlers.role, a.client, c.Scheme, pubKey, common))
 
 			return nil
 		} else if c.Scheme == auth.BasicAuth {
@@ -209,7 +213,7 @@ func (a *dockerAuthorizer) AddResponses(ctx context.Context, responses []*http.R
 					Secret:   secret,
 				}
 
-				a.handlers.set(host, session, newAuthHandler(host, a.client, c.Scheme, nil, common))
+				a.handlers.set(host, session, newAuthHandler(host, a.handlers.role, a.client, c.Scheme, nil, common))
 
 				return nil
 			}
@@ -244,13 +248,15 @@ type authHandler struct {
 	lastUsed time.Time
 
 	host string
+	role string
 
 	authority *[32]byte
 }
 
-func newAuthHandler(host string, client *http.Client, scheme auth.AuthenticationScheme, authority *[32]byte, opts auth.TokenOptions) *authHandler {
+func newAuthHandler(host, role string, client *http.Client, scheme auth.AuthenticationScheme, authority *[32]byte, opts auth.TokenOptions) *authHandler {
 	return &authHandler{
 		host:         host,
+		role:         role,
 		client:       client,
 		scheme:       scheme,
 		common:       opts,
@@ -345,6 +351,7 @@ func (ah *authHandler) fetchToken(ctx context.Context, sm *session.Manager, g se
 			Realm:    to.Realm,
 			Service:  to.Service,
 			Scopes:   to.Scopes,
+			Role:     ah.role,
 		}, sm, g)
 		if err != nil {
 			return nil, err
@@ -3,18 +3,26 @@ package resolver
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
 	"github.com/containerd/containerd/images"
 	"github.com/containerd/containerd/remotes"
 	"github.com/containerd/containerd/remotes/docker"
 	distreference "github.com/docker/distribution/reference"
 	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/cabundle"
+	"github.com/moby/buildkit/session/proxy"
 	"github.com/moby/buildkit/source"
+	"github.com/moby/buildkit/util/imageutil"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
 )
 
 // DefaultPool is the default shared resolver pool instance
@@ -71,7 +79,8 @@ func (p *Pool) Clear() {
 	p.m = map[string]*authHandlerNS{}
 }
 
-// GetResolver gets a resolver for a specified scope from the pool
+// GetResolver gets a resolver for a specified scope from the pool. The scope,
+// like "pull" or "push", is also the role the credentials are requested for.
 func (p *Pool) GetResolver(hosts docker.RegistryHosts, ref, scope string, sm *session.Manager, g session.Group) *Resolver {
 	name := ref
 	named, err := distreference.ParseNormalizedNamed(ref)
@@ -85,7 +94,7 @@ func (p *Pool) GetResolver(hosts docker.RegistryHosts, ref, scope string, sm *se
 	defer p.mu.Unlock()
 	h, ok := p.m[key]
 	if !ok {
-		h = newAuthHandlerNS(sm)
+		h = newAuthHandlerNS(sm, strings.SplitN(scope, ":", 2)[0])
 		p.m[key] = h
 	}
 	return newResolver(hosts, h, sm, g)
@@ -119,8 +128,10 @@ type Resolver struct {
 	handler *authHandlerNS
 	auth    *dockerAuthorizer
 
-	is   images.Store
-	mode source.ResolveMode
+	is      images.Store
+	cs      content.Store
+	mode    source.ResolveMode
+	mirrors []string
 }
 
 // HostsFunc implements registry configuration of this Resolver
@@ -150,6 +161,17 @@ func (r *Resolver) HostsFunc(host string) ([]docker.RegistryHost, error) {
 		if len(res) == 0 {
 			return nil, nil
 		}
+		res = orderHosts(res, r.mirrors)
+		if bundle := cabundle.GetCABundle(context.TODO(), r.sm, r.g); bundle != nil {
+			var err error
+			if res, err = withCABundle(res, bundle); err != nil {
+				return nil, err
+			}
+		}
+		if cfg := proxy.GetConfig(context.TODO(), r.sm, r.g); cfg != nil {
+			// the hosts are shared by the builds of the pool
+			res = withProxy(res, cfg)
+		}
 		auth := newDockerAuthorizer(res[0].Client, r.handler, r.sm, r.g)
 		for i := range res {
 			res[i].Authorizer = auth
@@ -158,6 +180,45 @@ func (r *Resolver) HostsFunc(host string) ([]docker.RegistryHost, error) {
 	}(host)
 }
 
+// withCABundle returns copies of the hosts with clients that trust the
+// certificates of bundle. The transports of the clients are shared by the
+// requests of the session of the bundle.
+func withCABundle(hosts []docker.RegistryHost, bundle *cabundle.Bundle) ([]docker.RegistryHost, error) {
+	out := make([]docker.RegistryHost, len(hosts))
+	for i, h := range hosts {
+		if h.Client != nil {
+			c := *h.Client
+			rt, err := bundle.Transport(c.Transport)
+			if err != nil {
+				return nil, err
+			}
+			c.Transport = rt
+			h.Client = &c
+		}
+		out[i] = h
+	}
+	return out, nil
+}
+
+// withProxy returns copies of the hosts with clients that use the proxy
+// configuration cfg
+func withProxy(hosts []docker.RegistryHost, cfg *proxy.Config) []docker.RegistryHost {
+	out := make([]docker.RegistryHost, len(hosts))
+	for i, h := range hosts {
+		if h.Client != nil {
+			c := *h.Client
+			rt := c.Transport
+			if rt == nil {
+				rt = http.DefaultTransport
+			}
+			c.Transport = proxy.NewTransport(rt, cfg)
+			h.Client = &c
+		}
+		out[i] = h
+	}
+	return out
+}
+
 // WithSession returns a new resolver that works with new session group
 func (r *Resolver) WithSession(s session.Group) *Resolver {
 	r2 := *r
@@ -175,8 +236,33 @@ func (r *Resolver) WithImageStore(is images.Store, mode source.ResolveMode) *Res
 	return &r2
 }
 
+// WithContentStore returns new resolver that resolves the references from the
+// content store if the resolve mode doesn't allow contacting the registry
+func (r *Resolver) WithContentStore(cs content.Store) *Resolver {
+	r2 := *r
+	r2.cs = cs
+	return &r2
+}
+
+// WithMirrorPreference returns new resolver that tries the passed registry
+// hosts first, in order, before falling through to the other configured hosts
+func (r *Resolver) WithMirrorPreference(mirrors []string) *Resolver {
+	if len(mirrors) == 0 {
+		return r
+	}
+	r2 := *r
+	r2.mirrors = mirrors
+	r2.Resolver = docker.NewResolver(docker.ResolverOptions{
+		Hosts: r2.HostsFunc,
+	})
+	return &r2
+}
+
 // Fetcher returns a new fetcher for the provided reference.
 func (r *Resolver) Fetcher(ctx context.Context, ref string) (remotes.Fetcher, error) {
+	if r.mode == source.ResolveModeLocalOnly {
+		return localOnlyFetcher{}, nil
+	}
 	if atomic.LoadInt64(&r.handler.counter) == 0 {
 		r.Resolve(ctx, ref)
 	}
@@ -185,9 +271,17 @@ func (r *Resolver) Fetcher(ctx context.Context, ref string) (remotes.Fetcher, er
 
 // Resolve attempts to resolve the reference into a name and descriptor.
 func (r *Resolver) Resolve(ctx context.Context, ref string) (string, ocispec.Descriptor, error) {
-	if r.mode == source.ResolveModePreferLocal && r.is != nil {
-		if img, err := r.is.Get(ctx, ref); err == nil {
-			return ref, img.Target, nil
+	if r.mode == source.ResolveModeLocalOnly {
+		desc, err := r.resolveLocal(ctx, ref)
+		if err != nil {
+			return "", ocispec.Descriptor{}, err
+		}
+		return ref, desc, nil
+	}
+
+	if r.mode == source.ResolveModePreferLocal {
+		if desc, err := r.resolveLocal(ctx, ref); err == nil {
+			return ref, desc, nil
 		}
 	}
 
@@ -205,3 +299,34 @@ func (r *Resolver) Resolve(ctx context.Context, ref string) (string, ocispec.Des
 
 	return "", ocispec.Descriptor{}, err
 }
+
+// resolveLocal resolves ref from the content that was pulled before. Images
+// that are only in the image store, e.g. the ones exported with a name, are
+// local too.
+func (r *Resolver) resolveLocal(ctx context.Context, ref string) (ocispec.Descriptor, error) {
+	if r.cs != nil {
+		desc, err := imageutil.ResolveLocal(ctx, r.cs, ref)
+		if err == nil {
+			return desc, nil
+		}
+		if r.is == nil || !errdefs.IsNotFound(err) {
+			return ocispec.Descriptor{}, err
+		}
+	}
+	if r.is == nil {
+		return ocispec.Descriptor{}, errors.Errorf("can't resolve %s without the registry: no local content", ref)
+	}
+	img, err := r.is.Get(ctx, ref)
+	if err != nil {
+		return ocispec.Descriptor{}, errors.Wrapf(err, "%s is not available locally", ref)
+	}
+	return img.Target, nil
+}
+
+// localOnlyFetcher is used when the pull policy doesn't allow contacting the
+// registry. Content that is already in the content store is never fetched.
+type localOnlyFetcher struct{}
+
+func (localOnlyFetcher) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	return nil, errors.Errorf("content %s is not available locally", desc.Digest)
+}
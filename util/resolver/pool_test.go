@@ -0,0 +1,86 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/content/local"
+	"github.com/containerd/containerd/images"
+	ctdmetadata "github.com/containerd/containerd/metadata"
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/containerd/snapshots"
+	"github.com/moby/buildkit/source"
+	"github.com/moby/buildkit/util/imageutil"
+	digest "github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestResolveLocalOnly(t *testing.T) {
+	t.Parallel()
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+
+	tmpdir, err := ioutil.TempDir("", "resolver")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	store, err := local.NewStore(filepath.Join(tmpdir, "content"))
+	require.NoError(t, err)
+	db, err := bolt.Open(filepath.Join(tmpdir, "containerdmeta.db"), 0644, nil)
+	require.NoError(t, err)
+	defer db.Close()
+	mdb := ctdmetadata.NewDB(db, store, map[string]snapshots.Snapshotter{})
+	require.NoError(t, mdb.Init(context.TODO()))
+	cs := mdb.ContentStore()
+
+	writeManifest := func(config string) digest.Digest {
+		dt, err := json.Marshal(specs.Manifest{
+			Config: specs.Descriptor{MediaType: specs.MediaTypeImageConfig, Digest: digest.FromString(config)},
+		})
+		require.NoError(t, err)
+		dgst := digest.FromBytes(dt)
+		require.NoError(t, content.WriteBlob(ctx, cs, dgst.String(), bytes.NewReader(dt), specs.Descriptor{Digest: dgst, Size: int64(len(dt))}))
+		return dgst
+	}
+	old := writeManifest("old")
+	latest := writeManifest("latest")
+
+	r := (&Resolver{}).WithImageStore(nil, source.ResolveModeLocalOnly).WithContentStore(cs)
+
+	// tags that were never pulled are not resolved from the registry
+	_, _, err = r.Resolve(ctx, "docker.io/library/busybox:latest")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not available locally")
+
+	require.NoError(t, imageutil.SetResolved(ctx, cs, "docker.io/library/busybox:latest", old))
+	time.Sleep(time.Millisecond)
+	require.NoError(t, imageutil.SetResolved(ctx, cs, "docker.io/library/busybox:latest", latest))
+
+	// the tag resolves to the manifest of the last pull
+	_, desc, err := r.Resolve(ctx, "docker.io/library/busybox:latest")
+	require.NoError(t, err)
+	require.Equal(t, latest, desc.Digest)
+	require.Equal(t, images.MediaTypeDockerSchema2Manifest, desc.MediaType)
+
+	// references with a digest resolve to the content with the digest
+	_, desc, err = r.Resolve(ctx, "docker.io/library/busybox:latest@"+old.String())
+	require.NoError(t, err)
+	require.Equal(t, old, desc.Digest)
+
+	_, _, err = r.Resolve(ctx, "docker.io/library/busybox@"+digest.FromString("missing").String())
+	require.Error(t, err)
+
+	// content is never fetched from the registry
+	f, err := r.Fetcher(ctx, "docker.io/library/busybox:latest")
+	require.NoError(t, err)
+	_, err = f.Fetch(ctx, specs.Descriptor{Digest: digest.FromString("layer")})
+	require.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package resolver
+
+import (
+	"context"
+	"sync"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/remotes"
+	distreference "github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// pushLocks serializes the pushes of the same blob to the same repository so
+// that concurrent exports, like of an image and of its cache, don't upload a
+// shared blob twice. The push that waited finds the blob in the registry and
+// skips the upload.
+var pushLocks = &pushLocker{active: map[string]chan struct{}{}}
+
+type pushLocker struct {
+	mu     sync.Mutex
+	active map[string]chan struct{}
+}
+
+func (l *pushLocker) lock(ctx context.Context, key string) (func(), error) {
+	for {
+		l.mu.Lock()
+		ch, ok := l.active[key]
+		if !ok {
+			ch = make(chan struct{})
+			l.active[key] = ch
+			l.mu.Unlock()
+			var once sync.Once
+			return func() {
+				once.Do(func() {
+					l.mu.Lock()
+					delete(l.active, key)
+					l.mu.Unlock()
+					close(ch)
+				})
+			}, nil
+		}
+		l.mu.Unlock()
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func pushLockKey(ref string, dgst digest.Digest) string {
+	if named, err := distreference.ParseNormalizedNamed(ref); err == nil {
+		ref = named.Name()
+	}
+	return ref + "@" + dgst.String()
+}
+
+// LockPush waits until no other push of the blob to the repository of ref is
+// in progress. The returned function needs to be called after the blob has
+// been pushed.
+func (r *Resolver) LockPush(ctx context.Context, ref string, dgst digest.Digest) (func(), error) {
+	return pushLocks.lock(ctx, pushLockKey(ref, dgst))
+}
+
+// Pusher returns a new pusher for the provided reference. Pushes of a blob
+// wait for the other pushes of the same blob to the repository to complete.
+func (r *Resolver) Pusher(ctx context.Context, ref string) (remotes.Pusher, error) {
+	p, err := r.Resolver.Pusher(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return &lockingPusher{Pusher: p, ref: ref}, nil
+}
+
+type lockingPusher struct {
+	remotes.Pusher
+	ref string
+}
+
+func (p *lockingPusher) Push(ctx context.Context, desc ocispec.Descriptor) (content.Writer, error) {
+	release, err := pushLocks.lock(ctx, pushLockKey(p.ref, desc.Digest))
+	if err != nil {
+		return nil, err
+	}
+	w, err := p.Pusher.Push(ctx, desc)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	return &lockedWriter{Writer: w, release: release}, nil
+}
+
+// lockedWriter releases the push lock of the blob when it is committed or
+// closed
+type lockedWriter struct {
+	content.Writer
+	release func()
+}
+
+func (w *lockedWriter) Commit(ctx context.Context, size int64, expected digest.Digest, opts ...content.Opt) error {
+	defer w.release()
+	return w.Writer.Commit(ctx, size, expected, opts...)
+}
+
+func (w *lockedWriter) Close() error {
+	defer w.release()
+	return w.Writer.Close()
+}
@@ -0,0 +1,61 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushLock(t *testing.T) {
+	t.Parallel()
+
+	l := &pushLocker{active: map[string]chan struct{}{}}
+	dgst := digest.FromString("foo")
+
+	require.Equal(t, pushLockKey("docker.io/library/busybox:latest", dgst), pushLockKey("busybox", dgst))
+
+	ctx := context.TODO()
+	release, err := l.lock(ctx, pushLockKey("busybox", dgst))
+	require.NoError(t, err)
+
+	// other blobs and repositories are not blocked
+	release2, err := l.lock(ctx, pushLockKey("busybox", digest.FromString("bar")))
+	require.NoError(t, err)
+	release2()
+	release2, err = l.lock(ctx, pushLockKey("alpine", dgst))
+	require.NoError(t, err)
+	release2()
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = l.lock(timeoutCtx, pushLockKey("busybox", dgst))
+	require.Equal(t, context.DeadlineExceeded, err)
+
+	locked := make(chan struct{})
+	go func() {
+		release, err := l.lock(ctx, pushLockKey("busybox", dgst))
+		if err == nil {
+			release()
+		}
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("lock acquired before release")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+	release()
+
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("lock not acquired after release")
+	}
+	require.Equal(t, 0, len(l.active))
+}
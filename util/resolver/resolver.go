@@ -9,10 +9,12 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/containerd/containerd/remotes/docker"
+	"github.com/moby/buildkit/session/proxy"
 	"github.com/moby/buildkit/util/tracing"
 	"github.com/pkg/errors"
 )
@@ -183,6 +185,27 @@ func NewRegistryConfig(m map[string]RegistryConfig) docker.RegistryHosts {
 	)
 }
 
+// orderHosts moves the hosts listed in preference to the front, in the order
+// of preference. Other hosts keep their configured order.
+func orderHosts(hosts []docker.RegistryHost, preference []string) []docker.RegistryHost {
+	if len(preference) == 0 {
+		return hosts
+	}
+	rank := func(h docker.RegistryHost) int {
+		for i, p := range preference {
+			if h.Host == p {
+				return i
+			}
+		}
+		return len(preference)
+	}
+	out := append([]docker.RegistryHost(nil), hosts...)
+	sort.SliceStable(out, func(i, j int) bool {
+		return rank(out[i]) < rank(out[j])
+	})
+	return out
+}
+
 func newDefaultClient() *http.Client {
 	return &http.Client{
 		Transport: tracing.NewTransport(newDefaultTransport()),
@@ -198,7 +221,7 @@ func newDefaultClient() *http.Client {
 // REF: https://github.com/golang/go/issues/14077
 func newDefaultTransport() *http.Transport {
 	return &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
+		Proxy: proxy.ProxyFunc,
 		DialContext: (&net.Dialer{
 			Timeout:   30 * time.Second,
 			KeepAlive: 60 * time.Second,
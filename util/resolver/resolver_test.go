@@ -0,0 +1,74 @@
+package resolver
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/moby/buildkit/session/cabundle"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderHosts(t *testing.T) {
+	t.Parallel()
+
+	hosts := []docker.RegistryHost{
+		{Host: "mirror-a.example.com"},
+		{Host: "mirror-b.example.com"},
+		{Host: "mirror-c.example.com"},
+		{Host: "registry-1.docker.io"},
+	}
+
+	names := func(hosts []docker.RegistryHost) []string {
+		out := make([]string, 0, len(hosts))
+		for _, h := range hosts {
+			out = append(out, h.Host)
+		}
+		return out
+	}
+
+	require.Equal(t, names(hosts), names(orderHosts(hosts, nil)))
+
+	out := orderHosts(hosts, []string{"mirror-c.example.com", "mirror-b.example.com"})
+	require.Equal(t, []string{"mirror-c.example.com", "mirror-b.example.com", "mirror-a.example.com", "registry-1.docker.io"}, names(out))
+
+	// unknown hosts in preference are ignored
+	out = orderHosts(hosts, []string{"unknown.example.com", "registry-1.docker.io"})
+	require.Equal(t, []string{"registry-1.docker.io", "mirror-a.example.com", "mirror-b.example.com", "mirror-c.example.com"}, names(out))
+
+	// original slice is not modified
+	require.Equal(t, "mirror-a.example.com", hosts[0].Host)
+}
+
+func TestWithCABundle(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	bundle := &cabundle.Bundle{PEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})}
+
+	client := newDefaultClient()
+	hosts := []docker.RegistryHost{{Host: "registry.example.com", Client: client}, {Host: "other.example.com"}}
+
+	res, err := withCABundle(hosts, bundle)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(res))
+	require.Nil(t, res[1].Client)
+	require.False(t, res[0].Client == client)
+	resp, err := res[0].Client.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// the hosts of the daemon keep their transport
+	_, err = client.Get(srv.URL)
+	require.Error(t, err)
+
+	// the requests of the session reuse the transport and its connections
+	res2, err := withCABundle(hosts, bundle)
+	require.NoError(t, err)
+	require.True(t, res[0].Client.Transport == res2[0].Client.Transport)
+}
@@ -1,9 +1,13 @@
 package specconv
 
 import (
+	"bufio"
+	"fmt"
+	"os"
 	"strings"
 
 	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
 )
 
 // ToRootless converts spec to be compatible with "rootless" runc.
@@ -38,3 +42,74 @@ func ToRootless(spec *specs.Spec) error {
 	spec.Linux.CgroupsPath = ""
 	return nil
 }
+
+// WithUserNSMapping makes the container run in a new user namespace with the
+// uid and gid mappings. The host IDs of the mappings must be mapped in the
+// user namespace of the daemon.
+func WithUserNSMapping(spec *specs.Spec, uidMappings, gidMappings []specs.LinuxIDMapping) error {
+	if err := checkMapped("uid", uidMappings, "/proc/self/uid_map"); err != nil {
+		return err
+	}
+	if err := checkMapped("gid", gidMappings, "/proc/self/gid_map"); err != nil {
+		return err
+	}
+	var hasUserNS bool
+	for _, ns := range spec.Linux.Namespaces {
+		if ns.Type == specs.UserNamespace {
+			hasUserNS = true
+		}
+	}
+	if !hasUserNS {
+		spec.Linux.Namespaces = append(spec.Linux.Namespaces, specs.LinuxNamespace{Type: specs.UserNamespace})
+	}
+	spec.Linux.UIDMappings = uidMappings
+	spec.Linux.GIDMappings = gidMappings
+	return nil
+}
+
+// checkMapped checks that the host IDs of the mappings are IDs of the user
+// namespace of the daemon listed in mapPath.
+func checkMapped(kind string, mappings []specs.LinuxIDMapping, mapPath string) error {
+	parent, err := readIDMap(mapPath)
+	if err != nil {
+		return err
+	}
+	for _, m := range mappings {
+		if !contains(parent, m) {
+			return errors.Errorf("%s mapping %d:%d:%d uses host IDs that are not mapped in the user namespace of the daemon", kind, m.ContainerID, m.HostID, m.Size)
+		}
+	}
+	return nil
+}
+
+func contains(parent []specs.LinuxIDMapping, m specs.LinuxIDMapping) bool {
+	for _, p := range parent {
+		// the IDs of the daemon are the container IDs of its own mappings
+		if uint64(m.HostID) >= uint64(p.ContainerID) && uint64(m.HostID)+uint64(m.Size) <= uint64(p.ContainerID)+uint64(p.Size) {
+			return true
+		}
+	}
+	return false
+}
+
+func readIDMap(p string) ([]specs.LinuxIDMapping, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer f.Close()
+
+	var out []specs.LinuxIDMapping
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		var m specs.LinuxIDMapping
+		if _, err := fmt.Sscanf(s.Text(), "%d %d %d", &m.ContainerID, &m.HostID, &m.Size); err != nil {
+			return nil, errors.Wrapf(err, "invalid line %q in %s", s.Text(), p)
+		}
+		out = append(out, m)
+	}
+	if err := s.Err(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return out, nil
+}
@@ -17,3 +17,9 @@ import (
 func ToRootless(spec *specs.Spec) error {
 	return errors.Errorf("not implemented on on %s", runtime.GOOS)
 }
+
+// WithUserNSMapping makes the container run in a new user namespace with the
+// uid and gid mappings.
+func WithUserNSMapping(spec *specs.Spec, uidMappings, gidMappings []specs.LinuxIDMapping) error {
+	return errors.Errorf("not implemented on %s", runtime.GOOS)
+}
@@ -45,6 +45,13 @@ func (s *TestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	s.stats[r.URL.Path].AllRequests++
+	s.stats[r.URL.Path].LastHeader = r.Header.Clone()
+
+	if resp.Redirect != "" {
+		s.mu.Unlock()
+		http.Redirect(w, r, resp.Redirect, http.StatusFound)
+		return
+	}
 
 	if resp.LastModified != nil {
 		w.Header().Set("Last-Modified", resp.LastModified.Format(time.RFC850))
@@ -77,8 +84,12 @@ type Response struct {
 	Content      []byte
 	Etag         string
 	LastModified *time.Time
+	// Redirect is the location the request is redirected to
+	Redirect string
 }
 
 type Stat struct {
 	AllRequests, CachedRequests int
+	// LastHeader is the header of the last request
+	LastHeader http.Header
 }
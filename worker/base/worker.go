@@ -24,8 +24,11 @@ import (
 	"github.com/moby/buildkit/executor"
 	"github.com/moby/buildkit/exporter"
 	imageexporter "github.com/moby/buildkit/exporter/containerimage"
+	fsimageexporter "github.com/moby/buildkit/exporter/fsimage"
 	localexporter "github.com/moby/buildkit/exporter/local"
+	merkleexporter "github.com/moby/buildkit/exporter/merkle"
 	ociexporter "github.com/moby/buildkit/exporter/oci"
+	provenanceexporter "github.com/moby/buildkit/exporter/provenance"
 	tarexporter "github.com/moby/buildkit/exporter/tar"
 	"github.com/moby/buildkit/frontend"
 	"github.com/moby/buildkit/identity"
@@ -42,6 +45,7 @@ import (
 	"github.com/moby/buildkit/source/http"
 	"github.com/moby/buildkit/source/local"
 	"github.com/moby/buildkit/util/archutil"
+	"github.com/moby/buildkit/util/imagepolicy"
 	"github.com/moby/buildkit/util/progress"
 	"github.com/moby/buildkit/util/progress/controller"
 	"github.com/moby/buildkit/worker"
@@ -76,6 +80,19 @@ type WorkerOpt struct {
 	LeaseManager    leases.Manager
 	GarbageCollect  func(context.Context) (gc.Stats, error)
 	ParallelismSem  *semaphore.Weighted
+	// Security has the settings that allow builds to access the host or to
+	// relax the isolation of their containers
+	Security worker.SecurityConfig
+	// ImagePolicy verifies the signatures of the images pulled by image
+	// sources
+	ImagePolicy *imagepolicy.Policy
+	// BatchSnapshotCommits adds committed snapshots to their leases in the
+	// transaction of the commit
+	BatchSnapshotCommits bool
+	// SourceTempDir is the private directory for the temporary files of the
+	// sources, e.g. the CA bundles of the builds. Its contents are removed
+	// when the worker is created.
+	SourceTempDir string
 }
 
 // Worker is a local worker instance with dedicated snapshotter, cache, and so on.
@@ -104,6 +121,8 @@ func NewWorker(opt WorkerOpt) (*Worker, error) {
 		LeaseManager:    opt.LeaseManager,
 		ContentStore:    opt.ContentStore,
 		Differ:          opt.Differ,
+
+		BatchSnapshotCommits: opt.BatchSnapshotCommits,
 	})
 	if err != nil {
 		return nil, err
@@ -122,6 +141,7 @@ func NewWorker(opt WorkerOpt) (*Worker, error) {
 		CacheAccessor: cm,
 		RegistryHosts: opt.RegistryHosts,
 		LeaseManager:  opt.LeaseManager,
+		ImagePolicy:   opt.ImagePolicy,
 	})
 	if err != nil {
 		return nil, err
@@ -129,10 +149,28 @@ func NewWorker(opt WorkerOpt) (*Worker, error) {
 
 	sm.Register(is)
 
+	ois, err := containerimage.NewOCILayoutSource(containerimage.SourceOpt{
+		Snapshotter:   opt.Snapshotter,
+		ContentStore:  opt.ContentStore,
+		Applier:       opt.Applier,
+		CacheAccessor: cm,
+		LeaseManager:  opt.LeaseManager,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sm.Register(ois)
+
 	if err := git.Supported(); err == nil {
+		var tempDir string
+		if opt.SourceTempDir != "" {
+			tempDir = filepath.Join(opt.SourceTempDir, "git")
+		}
 		gs, err := git.NewSource(git.Opt{
 			CacheAccessor: cm,
 			MetadataStore: opt.MetadataStore,
+			TempDir:       tempDir,
 		})
 		if err != nil {
 			return nil, err
@@ -192,6 +230,10 @@ func (w *Worker) ContentStore() content.Store {
 	return w.WorkerOpt.ContentStore
 }
 
+func (w *Worker) LeaseManager() leases.Manager {
+	return w.WorkerOpt.LeaseManager
+}
+
 func (w *Worker) ID() string {
 	return w.WorkerOpt.ID
 }
@@ -259,6 +301,16 @@ func (w *Worker) MetadataStore() *metadata.Store {
 	return w.WorkerOpt.MetadataStore
 }
 
+// ParallelismSemaphore returns the semaphore that limits the ops of the
+// worker running at the same time, nil if they are not limited
+func (w *Worker) ParallelismSemaphore() *semaphore.Weighted {
+	return w.ParallelismSem
+}
+
+func (w *Worker) SecurityConfig() worker.SecurityConfig {
+	return w.WorkerOpt.Security
+}
+
 func (w *Worker) ResolveOp(v solver.Vertex, s frontend.FrontendLLBBridge, sm *session.Manager) (solver.Op, error) {
 	if baseOp, ok := v.Sys().(*pb.Op); ok {
 		switch op := baseOp.Op.(type) {
@@ -343,7 +395,7 @@ func (w *Worker) Exporter(name string, sm *session.Manager) (exporter.Exporter,
 			SessionManager: sm,
 			ImageWriter:    w.imageWriter,
 			RegistryHosts:  w.RegistryHosts,
-			LeaseManager:   w.LeaseManager,
+			LeaseManager:   w.WorkerOpt.LeaseManager,
 		})
 	case client.ExporterLocal:
 		return localexporter.New(localexporter.Opt{
@@ -353,19 +405,31 @@ func (w *Worker) Exporter(name string, sm *session.Manager) (exporter.Exporter,
 		return tarexporter.New(tarexporter.Opt{
 			SessionManager: sm,
 		})
+	case client.ExporterMerkle:
+		return merkleexporter.New(merkleexporter.Opt{
+			SessionManager: sm,
+		})
+	case client.ExporterProvenance:
+		return provenanceexporter.New(provenanceexporter.Opt{
+			SessionManager: sm,
+		})
+	case client.ExporterFSImage:
+		return fsimageexporter.New(fsimageexporter.Opt{
+			SessionManager: sm,
+		})
 	case client.ExporterOCI:
 		return ociexporter.New(ociexporter.Opt{
 			SessionManager: sm,
 			ImageWriter:    w.imageWriter,
 			Variant:        ociexporter.VariantOCI,
-			LeaseManager:   w.LeaseManager,
+			LeaseManager:   w.WorkerOpt.LeaseManager,
 		})
 	case client.ExporterDocker:
 		return ociexporter.New(ociexporter.Opt{
 			SessionManager: sm,
 			ImageWriter:    w.imageWriter,
 			Variant:        ociexporter.VariantDocker,
-			LeaseManager:   w.LeaseManager,
+			LeaseManager:   w.WorkerOpt.LeaseManager,
 		})
 	default:
 		return nil, errors.Errorf("exporter %q could not be found", name)
@@ -0,0 +1,258 @@
+package remote
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/containerd/continuity/fs"
+	"github.com/moby/buildkit/executor"
+	"github.com/moby/buildkit/snapshot"
+	pb "github.com/moby/buildkit/worker/remote/pb"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// NewExecutor returns an executor that runs its processes with the remote
+// executor of conn. The contents of the mounts are sent to the remote before
+// the process starts and the contents of the writable mounts are replaced
+// with the ones of the remote after the process exited.
+func NewExecutor(conn *grpc.ClientConn) executor.Executor {
+	return &remoteExecutor{client: pb.NewExecutorClient(conn)}
+}
+
+type remoteExecutor struct {
+	client pb.ExecutorClient
+}
+
+func (e *remoteExecutor) Run(ctx context.Context, id string, root executor.Mount, mounts []executor.Mount, process executor.ProcessInfo, started chan<- struct{}) error {
+	meta, err := toPBMeta(process.Meta)
+	if err != nil {
+		return err
+	}
+	init := &pb.InitMessage{Meta: meta, Stdin: process.Stdin != nil}
+
+	all := append([]executor.Mount{root}, mounts...)
+	lms := make([]*localMount, 0, len(all))
+	defer func() {
+		for _, lm := range lms {
+			lm.unmount()
+		}
+	}()
+	for i, m := range all {
+		readonly := m.Readonly || (i == 0 && process.Meta.ReadonlyRootFS)
+		lm, err := mountLocal(ctx, m, readonly)
+		if err != nil {
+			return errors.Wrapf(err, "failed to mount %s", m.Dest)
+		}
+		lms = append(lms, lm)
+		init.Mounts = append(init.Mounts, &pb.Mount{
+			Dest:         m.Dest,
+			Readonly:     readonly,
+			File:         lm.file,
+			Mode:         uint32(lm.mode),
+			Tmpfs:        lm.tmpfs,
+			TmpfsOptions: lm.tmpfsOptions,
+		})
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := e.client.Run(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	s := &sender{stream: stream}
+	if err := s.send(&pb.RunMessage{Input: &pb.RunMessage_Init{Init: init}}); err != nil {
+		return err
+	}
+	for i, lm := range lms {
+		if lm.tmpfs {
+			continue
+		}
+		if err := sendMount(ctx, s, uint32(i), lm.path, lm.file); err != nil {
+			return errors.Wrapf(err, "failed to send mount %s", all[i].Dest)
+		}
+	}
+	if process.Stdin != nil {
+		go forwardStdin(s, process.Stdin)
+	}
+
+	var exited bool
+	var exitErr error
+	receivers := map[uint32]*mountReceiver{}
+	defer func() {
+		for _, r := range receivers {
+			r.abort(errors.New("run ended"))
+		}
+	}()
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		switch input := msg.Input.(type) {
+		case *pb.RunMessage_Started:
+			if started != nil {
+				close(started)
+				started = nil
+			}
+		case *pb.RunMessage_File:
+			if err := writeOutput(process, input.File); err != nil {
+				return err
+			}
+		case *pb.RunMessage_Exit:
+			exited = true
+			exitErr = fromPBExit(input.Exit)
+		case *pb.RunMessage_Mount:
+			md := input.Mount
+			if !exited || int(md.Index) >= len(lms) || init.Mounts[md.Index].Readonly || lms[md.Index].tmpfs {
+				return errors.Errorf("unexpected contents of mount %d", md.Index)
+			}
+			r, ok := receivers[md.Index]
+			if !ok {
+				lm := lms[md.Index]
+				if !lm.file {
+					if err := clearDir(lm.path); err != nil {
+						return err
+					}
+				}
+				r = newMountReceiver(ctx, lm.path, lm.file)
+				receivers[md.Index] = r
+			}
+			if err := r.write(md.Data); err != nil {
+				return errors.Wrapf(err, "failed to write mount %s", all[md.Index].Dest)
+			}
+			if md.EOF {
+				delete(receivers, md.Index)
+				if err := r.close(); err != nil {
+					return errors.Wrapf(err, "failed to write mount %s", all[md.Index].Dest)
+				}
+			}
+		default:
+			return errors.Errorf("unexpected message %T", msg.Input)
+		}
+	}
+	if !exited {
+		return errors.New("remote executor closed the stream before the process exited")
+	}
+	if len(receivers) > 0 {
+		return errors.New("remote executor closed the stream before sending all mounts")
+	}
+	return exitErr
+}
+
+func (e *remoteExecutor) Exec(ctx context.Context, id string, process executor.ProcessInfo) error {
+	return errors.New("exec in a running container is not supported by remote workers")
+}
+
+func writeOutput(process executor.ProcessInfo, f *pb.FdMessage) error {
+	var w io.Writer
+	switch f.Fd {
+	case 1:
+		w = process.Stdout
+	case 2:
+		w = process.Stderr
+	default:
+		return errors.Errorf("unexpected output fd %d", f.Fd)
+	}
+	if w == nil || len(f.Data) == 0 {
+		return nil
+	}
+	_, err := w.Write(f.Data)
+	return errors.WithStack(err)
+}
+
+func forwardStdin(s *sender, stdin io.Reader) {
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := stdin.Read(buf)
+		if n > 0 {
+			if err := s.send(&pb.RunMessage{Input: &pb.RunMessage_File{File: &pb.FdMessage{Fd: 0, Data: buf[:n]}}}); err != nil {
+				return
+			}
+		}
+		if err != nil {
+			s.send(&pb.RunMessage{Input: &pb.RunMessage_File{File: &pb.FdMessage{Fd: 0, EOF: true}}})
+			return
+		}
+	}
+}
+
+// localMount is a mount of a process mounted on the daemon so that its
+// contents can be sent to the remote
+type localMount struct {
+	path         string
+	file         bool
+	mode         os.FileMode
+	tmpfs        bool
+	tmpfsOptions []string
+	mounter      snapshot.Mounter
+	release      func() error
+}
+
+func mountLocal(ctx context.Context, m executor.Mount, readonly bool) (*localMount, error) {
+	mountable, err := m.Src.Mount(ctx, readonly)
+	if err != nil {
+		return nil, err
+	}
+	mnts, release, err := mountable.Mount()
+	if err != nil {
+		return nil, err
+	}
+	lm := &localMount{release: release}
+	if len(mnts) == 1 && mnts[0].Type == "tmpfs" {
+		lm.tmpfs = true
+		lm.tmpfsOptions = mnts[0].Options
+		return lm, nil
+	}
+	// binds are read from their source, the contents of read-only mounts
+	// are only read
+	var dir string
+	if len(mnts) == 1 && (mnts[0].Type == "bind" || mnts[0].Type == "rbind") {
+		dir = mnts[0].Source
+	} else {
+		lm.mounter = snapshot.LocalMounterWithMounts(mnts)
+		dir, err = lm.mounter.Mount()
+		if err != nil {
+			lm.unmount()
+			return nil, err
+		}
+	}
+	lm.path = dir
+	if m.Selector != "" {
+		lm.path, err = fs.RootPath(dir, m.Selector)
+		if err != nil {
+			lm.unmount()
+			return nil, errors.WithStack(err)
+		}
+	}
+	fi, err := os.Stat(lm.path)
+	if err != nil {
+		lm.unmount()
+		return nil, errors.WithStack(err)
+	}
+	switch {
+	case fi.IsDir():
+	case fi.Mode().IsRegular():
+		lm.file = true
+		lm.mode = fi.Mode().Perm()
+	default:
+		lm.unmount()
+		return nil, errors.Errorf("mounts of files with mode %s are not supported by remote workers", fi.Mode())
+	}
+	return lm, nil
+}
+
+func (lm *localMount) unmount() {
+	if lm.mounter != nil {
+		lm.mounter.Unmount()
+	}
+	if lm.release != nil {
+		lm.release()
+	}
+}
@@ -0,0 +1,137 @@
+package remote
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/gogo/googleapis/google/rpc"
+	gogotypes "github.com/gogo/protobuf/types"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/moby/buildkit/executor"
+	gwerrdefs "github.com/moby/buildkit/frontend/gateway/errdefs"
+	solverpb "github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/grpcerrors"
+	pb "github.com/moby/buildkit/worker/remote/pb"
+	"github.com/pkg/errors"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/status"
+)
+
+func toPBMeta(m executor.Meta) (*pb.Meta, error) {
+	if m.Tty {
+		return nil, errors.New("tty is not supported by remote workers")
+	}
+	if m.SharedPID != "" {
+		return nil, errors.New("sharing the PID namespace is not supported by remote workers")
+	}
+	meta := &pb.Meta{
+		Args:            m.Args,
+		Env:             m.Env,
+		User:            m.User,
+		Cwd:             m.Cwd,
+		Hostname:        m.Hostname,
+		ReadonlyRootFS:  m.ReadonlyRootFS,
+		NetMode:         m.NetMode,
+		SecurityMode:    m.SecurityMode,
+		Seccomp:         m.Seccomp,
+		ApparmorProfile: m.ApparmorProfile,
+		Devices:         m.Devices,
+		Resources:       m.Resources,
+		UserNSMapping:   m.UserNSMapping,
+		Sysctls:         m.Sysctls,
+	}
+	for _, h := range m.ExtraHosts {
+		meta.ExtraHosts = append(meta.ExtraHosts, &solverpb.HostIP{Host: h.Host, IP: h.IP.String()})
+	}
+	if m.Umask != nil {
+		meta.Umask = fmt.Sprintf("%04o", *m.Umask)
+	}
+	return meta, nil
+}
+
+func fromPBMeta(m *pb.Meta) (executor.Meta, error) {
+	if m == nil {
+		return executor.Meta{}, errors.New("missing meta")
+	}
+	meta := executor.Meta{
+		Args:            m.Args,
+		Env:             m.Env,
+		User:            m.User,
+		Cwd:             m.Cwd,
+		Hostname:        m.Hostname,
+		ReadonlyRootFS:  m.ReadonlyRootFS,
+		NetMode:         m.NetMode,
+		SecurityMode:    m.SecurityMode,
+		Seccomp:         m.Seccomp,
+		ApparmorProfile: m.ApparmorProfile,
+		Devices:         m.Devices,
+		Resources:       m.Resources,
+		UserNSMapping:   m.UserNSMapping,
+		Sysctls:         m.Sysctls,
+	}
+	for _, h := range m.ExtraHosts {
+		ip := net.ParseIP(h.IP)
+		if ip == nil {
+			return executor.Meta{}, errors.Errorf("invalid IP %q of host %s", h.IP, h.Host)
+		}
+		meta.ExtraHosts = append(meta.ExtraHosts, executor.HostIP{Host: h.Host, IP: ip})
+	}
+	if m.Umask != "" {
+		v, err := strconv.ParseUint(m.Umask, 8, 32)
+		if err != nil {
+			return executor.Meta{}, errors.Wrapf(err, "invalid umask %q", m.Umask)
+		}
+		umask := uint32(v)
+		meta.Umask = &umask
+	}
+	return meta, nil
+}
+
+// toPBExit converts the error returned by the executor to the exit message of
+// the process
+func toPBExit(err error) *pb.ExitMessage {
+	if err == nil {
+		return &pb.ExitMessage{}
+	}
+	exit := &pb.ExitMessage{Code: gwerrdefs.UnknownExitStatus}
+	var exitErr *gwerrdefs.ExitError
+	if errors.As(err, &exitErr) {
+		exit.Code = exitErr.ExitCode
+	}
+	st, _ := status.FromError(grpcerrors.ToGRPC(err))
+	stp := st.Proto()
+	exit.Error = &rpc.Status{
+		Code:    stp.Code,
+		Message: stp.Message,
+		Details: make([]*gogotypes.Any, len(stp.Details)),
+	}
+	for i, d := range stp.Details {
+		exit.Error.Details[i] = &gogotypes.Any{TypeUrl: d.TypeUrl, Value: d.Value}
+	}
+	return exit
+}
+
+// fromPBExit returns the error of the exit message of the process, an
+// ExitError for non-zero exit codes
+func fromPBExit(exit *pb.ExitMessage) error {
+	if exit.Error == nil {
+		if exit.Code == 0 {
+			return nil
+		}
+		return &gwerrdefs.ExitError{ExitCode: exit.Code}
+	}
+	st := &spb.Status{
+		Code:    exit.Error.Code,
+		Message: exit.Error.Message,
+		Details: make([]*any.Any, len(exit.Error.Details)),
+	}
+	for i, d := range exit.Error.Details {
+		st.Details[i] = &any.Any{TypeUrl: d.TypeUrl, Value: d.Value}
+	}
+	err := grpcerrors.FromGRPC(status.ErrorProto(st))
+	if exit.Code != gwerrdefs.UnknownExitStatus {
+		err = &gwerrdefs.ExitError{ExitCode: exit.Code, Err: err}
+	}
+	return err
+}
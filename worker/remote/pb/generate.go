@@ -0,0 +1,3 @@
+package moby_buildkit_v1_remoteworker //nolint:golint
+
+//go:generate protoc -I=. -I=../../../vendor/ -I=../../../../../../ --gogo_out=plugins=grpc:. remote.proto
@@ -0,0 +1,449 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/mount"
+	"github.com/docker/docker/pkg/idtools"
+	apitypes "github.com/moby/buildkit/api/types"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/metadata"
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/executor"
+	gwerrdefs "github.com/moby/buildkit/frontend/gateway/errdefs"
+	"github.com/moby/buildkit/solver"
+	solverpb "github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/worker"
+	pb "github.com/moby/buildkit/worker/remote/pb"
+	digest "github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/grpc"
+)
+
+func TestRemoteWorker(t *testing.T) {
+	t.Parallel()
+
+	w, exec, cleanup := newTestRemote(t)
+	defer cleanup()
+	require.Equal(t, "remote-arm64", w.ID())
+	require.Equal(t, map[string]string{"arch": "arm64"}, w.Labels())
+	require.Equal(t, []specs.Platform{{OS: "linux", Architecture: "arm64"}}, w.Platforms(false))
+
+	exec.run = func(root executor.Mount, mounts []executor.Mount, process executor.ProcessInfo) error {
+		rootDir := mountPath(t, root)
+		dt, err := ioutil.ReadFile(filepath.Join(rootDir, "input"))
+		if err != nil {
+			return err
+		}
+		require.Len(t, mounts, 2)
+		require.Equal(t, "/src", mounts[0].Dest)
+		require.True(t, mounts[0].Readonly)
+		src, err := ioutil.ReadFile(filepath.Join(mountPath(t, mounts[0]), "main.go"))
+		if err != nil {
+			return err
+		}
+		secret, err := ioutil.ReadFile(mountPath(t, mounts[1]))
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(rootDir, "output"), []byte(string(dt)+string(src)+string(secret)), 0644); err != nil {
+			return err
+		}
+		if err := os.Remove(filepath.Join(rootDir, "removed")); err != nil {
+			return err
+		}
+		_, err = process.Stdout.Write([]byte(strings.Join(process.Meta.Args, " ")))
+		return err
+	}
+
+	rootDir := tempDir(t)
+	defer os.RemoveAll(rootDir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(rootDir, "input"), []byte("in-"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(rootDir, "removed"), nil, 0644))
+	srcDir := tempDir(t)
+	defer os.RemoveAll(srcDir)
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "sub"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(srcDir, "sub", "main.go"), []byte("src-"), 0644))
+	secretDir := tempDir(t)
+	defer os.RemoveAll(secretDir)
+	secretFile := filepath.Join(secretDir, "secret")
+	require.NoError(t, ioutil.WriteFile(secretFile, []byte("secret"), 0400))
+
+	var stdout bytes.Buffer
+	started := make(chan struct{})
+	err := w.Executor().Run(context.TODO(), "", bindMount(rootDir, "/", false), []executor.Mount{
+		{Src: bindMount(srcDir, "", true).Src, Selector: "sub", Dest: "/src", Readonly: true},
+		bindMount(secretFile, "/run/secrets/secret", true),
+	}, executor.ProcessInfo{
+		Meta:   executor.Meta{Args: []string{"build", "arm64"}, Cwd: "/"},
+		Stdout: nopWriteCloser{&stdout},
+		Stderr: nopWriteCloser{ioutil.Discard},
+	}, started)
+	require.NoError(t, err)
+
+	select {
+	case <-started:
+	default:
+		t.Fatal("started was not closed")
+	}
+	require.Equal(t, "build arm64", stdout.String())
+	dt, err := ioutil.ReadFile(filepath.Join(rootDir, "output"))
+	require.NoError(t, err)
+	require.Equal(t, "in-src-secret", string(dt))
+	_, err = os.Stat(filepath.Join(rootDir, "removed"))
+	require.True(t, os.IsNotExist(err))
+
+	// read-only mounts are not written back
+	_, err = os.Stat(filepath.Join(srcDir, "sub", "output"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestRemoteWorkerExitCode(t *testing.T) {
+	t.Parallel()
+
+	w, exec, cleanup := newTestRemote(t)
+	defer cleanup()
+	exec.run = func(root executor.Mount, mounts []executor.Mount, process executor.ProcessInfo) error {
+		if err := ioutil.WriteFile(filepath.Join(mountPath(t, root), "partial"), nil, 0644); err != nil {
+			return err
+		}
+		return &gwerrdefs.ExitError{ExitCode: 3}
+	}
+
+	rootDir := tempDir(t)
+	defer os.RemoveAll(rootDir)
+	err := w.Executor().Run(context.TODO(), "", bindMount(rootDir, "/", false), nil, executor.ProcessInfo{
+		Meta: executor.Meta{Args: []string{"false"}},
+	}, nil)
+	require.Error(t, err)
+	var exitErr *gwerrdefs.ExitError
+	require.True(t, errors.As(err, &exitErr), "%+v", err)
+	require.Equal(t, uint32(3), exitErr.ExitCode)
+
+	// the writable mounts are sent back for failed processes too
+	_, err = os.Stat(filepath.Join(rootDir, "partial"))
+	require.NoError(t, err)
+}
+
+func TestRemoteWorkerUnsupported(t *testing.T) {
+	t.Parallel()
+
+	w, _, cleanup := newTestRemote(t)
+	defer cleanup()
+	rootDir := tempDir(t)
+	defer os.RemoveAll(rootDir)
+	err := w.Executor().Run(context.TODO(), "", bindMount(rootDir, "/", false), nil, executor.ProcessInfo{
+		Meta: executor.Meta{Args: []string{"sh"}, Tty: true},
+	}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "tty is not supported")
+
+	err = w.Executor().Exec(context.TODO(), "id", executor.ProcessInfo{})
+	require.Error(t, err)
+}
+
+func TestRemoteWorkerInsecure(t *testing.T) {
+	t.Parallel()
+
+	w, exec, cleanup := newTestRemote(t)
+	defer cleanup()
+	exec.run = func(root executor.Mount, mounts []executor.Mount, process executor.ProcessInfo) error {
+		return errors.New("process should not run")
+	}
+	rootDir := tempDir(t)
+	defer os.RemoveAll(rootDir)
+
+	for _, tc := range []struct {
+		name string
+		meta executor.Meta
+		err  string
+	}{
+		{
+			name: "insecure",
+			meta: executor.Meta{SecurityMode: solverpb.SecurityMode_INSECURE},
+			err:  "security.insecure is not allowed",
+		},
+		{
+			name: "hostnet",
+			meta: executor.Meta{NetMode: solverpb.NetMode_HOST},
+			err:  "network.host is not allowed",
+		},
+		{
+			name: "seccomp",
+			meta: executor.Meta{Seccomp: &solverpb.SeccompOpt{Unconfined: true}},
+			err:  "security.insecure is not allowed",
+		},
+		{
+			name: "seccompprofile",
+			meta: executor.Meta{Seccomp: &solverpb.SeccompOpt{Profile: `{"defaultAction":"SCMP_ACT_ALLOW"}`}},
+			err:  "security.insecure is not allowed",
+		},
+		{
+			name: "sysctl",
+			meta: executor.Meta{Sysctls: map[string]string{"kernel.shm_rmid_forced": "1"}},
+			err:  "setting sysctl kernel.shm_rmid_forced is not allowed",
+		},
+		{
+			name: "apparmor",
+			meta: executor.Meta{ApparmorProfile: "unconfined"},
+			err:  "not allowed",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			tc.meta.Args = []string{"true"}
+			err := w.Executor().Run(context.TODO(), "", bindMount(rootDir, "/", false), nil, executor.ProcessInfo{Meta: tc.meta}, nil)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+			require.NotContains(t, err.Error(), "process should not run")
+		})
+	}
+}
+
+func TestRemoteWorkerLocalCache(t *testing.T) {
+	t.Parallel()
+
+	sem := semaphore.NewWeighted(1)
+	local := &testLocalWorker{cm: &testCacheManager{}, sem: sem}
+	w := &Worker{
+		Worker:   local,
+		info:     &pb.InfoResponse{Worker: &apitypes.WorkerRecord{ID: "remote"}},
+		executor: &testExecutor{},
+	}
+	ctx := context.TODO()
+
+	// the results of the local worker are used without a transfer, the
+	// local worker doesn't implement FromRemote
+	ref := &worker.WorkerRef{ImmutableRef: &testRef{id: "ref"}, Worker: local}
+	out, err := ref.ToWorker(ctx, w, nil)
+	require.NoError(t, err)
+	require.Equal(t, ref, out)
+	ref = &worker.WorkerRef{ImmutableRef: &testRef{id: "ref"}, Worker: w}
+	out, err = ref.ToWorker(ctx, local, nil)
+	require.NoError(t, err)
+	require.Equal(t, ref, out)
+
+	// the disk usage and pruning are the ones of the local cache
+	require.Equal(t, local.GCPolicy(), w.GCPolicy())
+	du, err := w.DiskUsage(ctx, client.DiskUsageInfo{})
+	require.NoError(t, err)
+	require.Len(t, du, 1)
+	require.Equal(t, "local-record", du[0].ID)
+
+	// the exec ops share the parallelism of the local worker
+	op, err := w.ResolveOp(&testVertex{op: &solverpb.Op{Op: &solverpb.Op_Exec{Exec: &solverpb.ExecOp{
+		Meta:   &solverpb.Meta{Args: []string{"true"}},
+		Mounts: []*solverpb.Mount{{Dest: "/", Input: solverpb.Empty, Output: 0}},
+	}}}}, nil, nil)
+	require.NoError(t, err)
+	acquirer, ok := op.(interface {
+		Acquire(context.Context) (solver.ReleaseFunc, error)
+	})
+	require.True(t, ok)
+	require.NoError(t, sem.Acquire(ctx, 1))
+	tctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	_, err = acquirer.Acquire(tctx)
+	cancel()
+	require.Error(t, err)
+	sem.Release(1)
+	release, err := acquirer.Acquire(ctx)
+	require.NoError(t, err)
+	require.False(t, sem.TryAcquire(1))
+	release()
+}
+
+// newTestRemote serves the remote executor of a test worker and returns the
+// remote worker of a daemon connected to it
+func newTestRemote(t *testing.T) (*Worker, *testExecutor, func()) {
+	tmpdir := tempDir(t)
+	exec := &testExecutor{}
+	s, err := NewServer(&testWorker{exec: exec}, filepath.Join(tmpdir, "executor"), nil)
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	s.Register(server)
+	sockPath := filepath.Join(tmpdir, "remote.sock")
+	l, err := net.Listen("unix", sockPath)
+	require.NoError(t, err)
+	go server.Serve(l)
+
+	conn, err := grpc.Dial("unix://"+sockPath, grpc.WithInsecure())
+	require.NoError(t, err)
+	cleanup := func() {
+		conn.Close()
+		server.Stop()
+		os.RemoveAll(tmpdir)
+	}
+
+	w, err := NewWorker(context.TODO(), conn, &testWorker{})
+	if err != nil {
+		cleanup()
+	}
+	require.NoError(t, err)
+	return w, exec, cleanup
+}
+
+type testWorker struct {
+	worker.Worker
+	exec executor.Executor
+}
+
+func (w *testWorker) ID() string {
+	return "remote-arm64"
+}
+
+func (w *testWorker) Labels() map[string]string {
+	return map[string]string{"arch": "arm64"}
+}
+
+func (w *testWorker) Platforms(bool) []specs.Platform {
+	return []specs.Platform{{OS: "linux", Architecture: "arm64"}}
+}
+
+func (w *testWorker) Executor() executor.Executor {
+	return w.exec
+}
+
+func (w *testWorker) SecurityConfig() worker.SecurityConfig {
+	return worker.SecurityConfig{}
+}
+
+func (w *testWorker) CacheManager() cache.Manager {
+	return &testCacheManager{}
+}
+
+type testCacheManager struct {
+	cache.Manager
+}
+
+type testLocalWorker struct {
+	worker.Worker
+	cm  cache.Manager
+	sem *semaphore.Weighted
+}
+
+func (w *testLocalWorker) ID() string {
+	return "local"
+}
+
+func (w *testLocalWorker) CacheManager() cache.Manager {
+	return w.cm
+}
+
+func (w *testLocalWorker) MetadataStore() *metadata.Store {
+	return nil
+}
+
+func (w *testLocalWorker) SecurityConfig() worker.SecurityConfig {
+	return worker.SecurityConfig{}
+}
+
+func (w *testLocalWorker) ParallelismSemaphore() *semaphore.Weighted {
+	return w.sem
+}
+
+func (w *testLocalWorker) GCPolicy() []client.PruneInfo {
+	return []client.PruneInfo{{KeepBytes: 1024}}
+}
+
+func (w *testLocalWorker) DiskUsage(ctx context.Context, opt client.DiskUsageInfo) ([]*client.UsageInfo, error) {
+	return []*client.UsageInfo{{ID: "local-record"}}, nil
+}
+
+type testRef struct {
+	cache.ImmutableRef
+	id string
+}
+
+func (r *testRef) ID() string {
+	return r.id
+}
+
+type testVertex struct {
+	solver.Vertex
+	op *solverpb.Op
+}
+
+func (v *testVertex) Sys() interface{} {
+	return v.op
+}
+
+func (v *testVertex) Digest() digest.Digest {
+	return digest.FromString("exec")
+}
+
+func (v *testVertex) Inputs() []solver.Edge {
+	return nil
+}
+
+func (v *testVertex) Name() string {
+	return "exec"
+}
+
+func (cm *testCacheManager) IdentityMapping() *idtools.IdentityMapping {
+	return nil
+}
+
+type testExecutor struct {
+	run func(executor.Mount, []executor.Mount, executor.ProcessInfo) error
+}
+
+func (e *testExecutor) Run(ctx context.Context, id string, root executor.Mount, mounts []executor.Mount, process executor.ProcessInfo, started chan<- struct{}) error {
+	if started != nil {
+		close(started)
+	}
+	return e.run(root, mounts, process)
+}
+
+func (e *testExecutor) Exec(ctx context.Context, id string, process executor.ProcessInfo) error {
+	return errors.New("not implemented")
+}
+
+func bindMount(p, dest string, readonly bool) executor.Mount {
+	opts := []string{"rbind"}
+	if readonly {
+		opts = append(opts, "ro")
+	}
+	return executor.Mount{
+		Src:      &mountable{mounts: []mount.Mount{{Type: "bind", Source: p, Options: opts}}},
+		Dest:     dest,
+		Readonly: readonly,
+	}
+}
+
+// mountPath returns the path of a bind mount of the remote executor
+func mountPath(t *testing.T, m executor.Mount) string {
+	mountable, err := m.Src.Mount(context.TODO(), m.Readonly)
+	require.NoError(t, err)
+	mnts, _, err := mountable.Mount()
+	require.NoError(t, err)
+	require.Len(t, mnts, 1)
+	require.Equal(t, "bind", mnts[0].Type)
+	return mnts[0].Source
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "buildkit-remote")
+	require.NoError(t, err)
+	return dir
+}
@@ -0,0 +1,268 @@
+package remote
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/containerd/containerd/mount"
+	"github.com/docker/docker/pkg/idtools"
+	apitypes "github.com/moby/buildkit/api/types"
+	"github.com/moby/buildkit/executor"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/solver/llbsolver/ops"
+	solverpb "github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/entitlements"
+	"github.com/moby/buildkit/worker"
+	pb "github.com/moby/buildkit/worker/remote/pb"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// Server runs the processes of the remote workers of other daemons with the
+// executor of a local worker
+type Server struct {
+	w    worker.Worker
+	root string
+	ent  entitlements.Set
+}
+
+// NewServer returns the remote executor service of w. The contents of the
+// mounts of the processes are extracted to directories in root. The processes
+// are checked against the security config of w and can only use the
+// entitlements in ent.
+func NewServer(w worker.Worker, root string, ent entitlements.Set) (*Server, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &Server{w: w, root: root, ent: ent}, nil
+}
+
+func (s *Server) Register(server *grpc.Server) {
+	pb.RegisterExecutorServer(server, s)
+}
+
+func (s *Server) Info(ctx context.Context, req *pb.InfoRequest) (*pb.InfoResponse, error) {
+	return &pb.InfoResponse{
+		Worker: &apitypes.WorkerRecord{
+			ID:        s.w.ID(),
+			Labels:    s.w.Labels(),
+			Platforms: solverpb.PlatformsFromSpec(s.w.Platforms(true)),
+		},
+	}, nil
+}
+
+func (s *Server) Run(stream pb.Executor_RunServer) error {
+	ctx := stream.Context()
+
+	msg, err := stream.Recv()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	init := msg.GetInit()
+	if init == nil {
+		return errors.Errorf("expected init message, got %T", msg.Input)
+	}
+	if len(init.Mounts) == 0 {
+		return errors.New("missing root mount")
+	}
+	meta, err := fromPBMeta(init.Meta)
+	if err != nil {
+		return err
+	}
+	if err := ops.ValidateMeta(meta, s.w.SecurityConfig(), s.ent); err != nil {
+		return err
+	}
+
+	dir, err := ioutil.TempDir(s.root, "run-")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer os.RemoveAll(dir)
+
+	idmap := s.w.CacheManager().IdentityMapping()
+	mounts := make([]executor.Mount, len(init.Mounts))
+	paths := make([]string, len(init.Mounts))
+	var pending int
+	for i, m := range init.Mounts {
+		mounts[i] = executor.Mount{Dest: m.Dest, Readonly: m.Readonly}
+		if m.Tmpfs {
+			mounts[i].Src = &mountable{mounts: []mount.Mount{{Type: "tmpfs", Source: "tmpfs", Options: m.TmpfsOptions}}, idmap: idmap}
+			continue
+		}
+		p := filepath.Join(dir, strconv.Itoa(i))
+		if m.File {
+			if err := ioutil.WriteFile(p, nil, os.FileMode(m.Mode).Perm()); err != nil {
+				return errors.WithStack(err)
+			}
+		} else if err := os.Mkdir(p, 0755); err != nil {
+			return errors.WithStack(err)
+		}
+		paths[i] = p
+		opts := []string{"rbind"}
+		if m.Readonly {
+			opts = append(opts, "ro")
+		}
+		mounts[i].Src = &mountable{mounts: []mount.Mount{{Type: "bind", Source: p, Options: opts}}, idmap: idmap}
+		pending++
+	}
+
+	receivers := map[uint32]*mountReceiver{}
+	defer func() {
+		for _, r := range receivers {
+			r.abort(errors.New("run ended"))
+		}
+	}()
+	for pending > 0 {
+		msg, err := stream.Recv()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		md := msg.GetMount()
+		if md == nil {
+			return errors.Errorf("expected mount data, got %T", msg.Input)
+		}
+		if int(md.Index) >= len(paths) || paths[md.Index] == "" {
+			return errors.Errorf("unexpected contents of mount %d", md.Index)
+		}
+		r, ok := receivers[md.Index]
+		if !ok {
+			r = newMountReceiver(ctx, paths[md.Index], init.Mounts[md.Index].File)
+			receivers[md.Index] = r
+		}
+		if err := r.write(md.Data); err != nil {
+			return errors.Wrapf(err, "failed to write mount %s", init.Mounts[md.Index].Dest)
+		}
+		if md.EOF {
+			delete(receivers, md.Index)
+			if err := r.close(); err != nil {
+				return errors.Wrapf(err, "failed to write mount %s", init.Mounts[md.Index].Dest)
+			}
+			pending--
+		}
+	}
+
+	snd := &sender{stream: stream}
+	process := executor.ProcessInfo{
+		Meta:   meta,
+		Stdout: &fdWriter{s: snd, fd: 1},
+		Stderr: &fdWriter{s: snd, fd: 2},
+	}
+	if init.Stdin {
+		pr, pw := io.Pipe()
+		defer pr.Close()
+		process.Stdin = pr
+		go receiveStdin(stream, pw)
+	}
+
+	// Started is always sent before Exit
+	started := make(chan struct{})
+	runDone := make(chan struct{})
+	startedSent := make(chan struct{})
+	go func() {
+		defer close(startedSent)
+		select {
+		case <-started:
+		case <-runDone:
+			select {
+			case <-started:
+			default:
+				return
+			}
+		}
+		snd.send(&pb.RunMessage{Input: &pb.RunMessage_Started{Started: &pb.StartedMessage{}}})
+	}()
+
+	runErr := s.w.Executor().Run(ctx, "", mounts[0], mounts[1:], process, started)
+	close(runDone)
+	<-startedSent
+	if err := snd.send(&pb.RunMessage{Input: &pb.RunMessage_Exit{Exit: toPBExit(runErr)}}); err != nil {
+		return err
+	}
+	for i, m := range init.Mounts {
+		if m.Readonly || m.Tmpfs {
+			continue
+		}
+		if err := sendMount(ctx, snd, uint32(i), paths[i], m.File); err != nil {
+			return errors.Wrapf(err, "failed to send mount %s", m.Dest)
+		}
+	}
+	return nil
+}
+
+func receiveStdin(stream pb.Executor_RunServer, pw *io.PipeWriter) {
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		f := msg.GetFile()
+		if f == nil || f.Fd != 0 {
+			pw.CloseWithError(errors.Errorf("unexpected message %T", msg.Input))
+			return
+		}
+		if len(f.Data) > 0 {
+			if _, err := pw.Write(f.Data); err != nil {
+				return
+			}
+		}
+		if f.EOF {
+			pw.Close()
+			return
+		}
+	}
+}
+
+// fdWriter sends the output of the process to the client
+type fdWriter struct {
+	s  *sender
+	fd uint32
+}
+
+func (w *fdWriter) Write(dt []byte) (int, error) {
+	var n int
+	for len(dt) > 0 {
+		chunk := dt
+		if len(chunk) > chunkSize {
+			chunk = chunk[:chunkSize]
+		}
+		if err := w.s.send(&pb.RunMessage{Input: &pb.RunMessage_File{File: &pb.FdMessage{Fd: w.fd, Data: chunk}}}); err != nil {
+			return n, err
+		}
+		n += len(chunk)
+		dt = dt[len(chunk):]
+	}
+	return n, nil
+}
+
+func (w *fdWriter) Close() error {
+	return nil
+}
+
+// mountable mounts the directories the contents of the mounts of a process
+// were extracted to
+type mountable struct {
+	mounts []mount.Mount
+	idmap  *idtools.IdentityMapping
+}
+
+func (m *mountable) Mount(ctx context.Context, readonly bool) (snapshot.Mountable, error) {
+	return &staticMountable{mounts: m.mounts, idmap: m.idmap}, nil
+}
+
+type staticMountable struct {
+	mounts []mount.Mount
+	idmap  *idtools.IdentityMapping
+}
+
+func (m *staticMountable) Mount() ([]mount.Mount, func() error, error) {
+	return m.mounts, func() error { return nil }, nil
+}
+
+func (m *staticMountable) IdentityMapping() *idtools.IdentityMapping {
+	return m.idmap
+}
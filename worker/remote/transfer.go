@@ -0,0 +1,149 @@
+package remote
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/containerd/containerd/archive"
+	pb "github.com/moby/buildkit/worker/remote/pb"
+	"github.com/pkg/errors"
+)
+
+// chunkSize is the maximum size of the data of a message
+const chunkSize = 32 * 1024
+
+// messageSender is implemented by both ends of a Run stream
+type messageSender interface {
+	Send(*pb.RunMessage) error
+}
+
+// sender serializes the messages sent by the goroutines of a Run stream
+type sender struct {
+	mu     sync.Mutex
+	stream messageSender
+}
+
+func (s *sender) send(msg *pb.RunMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return errors.WithStack(s.stream.Send(msg))
+}
+
+// mountWriter sends the data written to it as the contents of a mount
+type mountWriter struct {
+	s     *sender
+	index uint32
+}
+
+func (w *mountWriter) Write(dt []byte) (int, error) {
+	var n int
+	for len(dt) > 0 {
+		chunk := dt
+		if len(chunk) > chunkSize {
+			chunk = chunk[:chunkSize]
+		}
+		if err := w.s.send(&pb.RunMessage{Input: &pb.RunMessage_Mount{Mount: &pb.MountData{Index: w.index, Data: chunk}}}); err != nil {
+			return n, err
+		}
+		n += len(chunk)
+		dt = dt[len(chunk):]
+	}
+	return n, nil
+}
+
+// sendMount sends the contents of the directory or file at p as the mount with
+// the index
+func sendMount(ctx context.Context, s *sender, index uint32, p string, file bool) error {
+	bw := bufio.NewWriterSize(&mountWriter{s: s, index: index}, chunkSize)
+	if file {
+		f, err := os.Open(p)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		_, err = io.Copy(bw, f)
+		f.Close()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	} else if err := archive.WriteDiff(ctx, bw, "", p); err != nil {
+		return errors.Wrapf(err, "failed to archive mount %d", index)
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return s.send(&pb.RunMessage{Input: &pb.RunMessage_Mount{Mount: &pb.MountData{Index: index, EOF: true}}})
+}
+
+// mountReceiver writes the received contents of a mount to its directory or
+// file
+type mountReceiver struct {
+	pw   *io.PipeWriter
+	done chan error
+}
+
+func newMountReceiver(ctx context.Context, p string, file bool) *mountReceiver {
+	pr, pw := io.Pipe()
+	r := &mountReceiver{pw: pw, done: make(chan error, 1)}
+	go func() {
+		var err error
+		if file {
+			err = writeFile(p, pr)
+		} else {
+			_, err = archive.Apply(ctx, p, pr)
+		}
+		if err == nil {
+			// the padding after the end of the archive is not read by Apply
+			_, err = io.Copy(ioutil.Discard, pr)
+		}
+		pr.CloseWithError(err)
+		r.done <- err
+	}()
+	return r
+}
+
+func writeFile(p string, r io.Reader) error {
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(f.Close())
+}
+
+func (r *mountReceiver) write(dt []byte) error {
+	_, err := r.pw.Write(dt)
+	return err
+}
+
+func (r *mountReceiver) close() error {
+	r.pw.Close()
+	return <-r.done
+}
+
+func (r *mountReceiver) abort(err error) {
+	r.pw.CloseWithError(err)
+	<-r.done
+}
+
+// clearDir removes the contents of the directory so that the contents
+// received from the remote replace them
+func clearDir(p string) error {
+	fis, err := ioutil.ReadDir(p)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, fi := range fis {
+		if err := os.RemoveAll(filepath.Join(p, fi.Name())); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
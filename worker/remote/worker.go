@@ -0,0 +1,88 @@
+package remote
+
+import (
+	"context"
+
+	"github.com/moby/buildkit/executor"
+	"github.com/moby/buildkit/frontend"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/solver/llbsolver/ops"
+	solverpb "github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/worker"
+	pb "github.com/moby/buildkit/worker/remote/pb"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/grpc"
+)
+
+// Worker runs the exec ops routed to it with the remote executor of another
+// machine. Everything else, including the cache and the snapshots of the
+// mounts, stays with the local worker that it wraps. The disk usage, pruning
+// and garbage collection of the worker are the ones of the shared cache of
+// the local worker.
+type Worker struct {
+	worker.Worker
+	info     *pb.InfoResponse
+	executor executor.Executor
+}
+
+// NewWorker returns the remote worker of the executor of conn. It has the ID,
+// labels and platforms reported by the remote.
+func NewWorker(ctx context.Context, conn *grpc.ClientConn, local worker.Worker) (*Worker, error) {
+	info, err := pb.NewExecutorClient(conn).Info(ctx, &pb.InfoRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get remote worker info")
+	}
+	if info.Worker == nil || info.Worker.ID == "" {
+		return nil, errors.New("remote worker did not report its ID")
+	}
+	return &Worker{
+		Worker:   local,
+		info:     info,
+		executor: NewExecutor(conn),
+	}, nil
+}
+
+func (w *Worker) ID() string {
+	return w.info.Worker.ID
+}
+
+func (w *Worker) Labels() map[string]string {
+	return w.info.Worker.Labels
+}
+
+func (w *Worker) Platforms(noCache bool) []specs.Platform {
+	return solverpb.ToSpecPlatforms(w.info.Worker.Platforms)
+}
+
+// RemoteExecutor returns true as the platforms of the worker are emulated by
+// the remote instead of the daemon
+func (w *Worker) RemoteExecutor() bool {
+	return true
+}
+
+func (w *Worker) Executor() executor.Executor {
+	return w.executor
+}
+
+func (w *Worker) ResolveOp(v solver.Vertex, s frontend.FrontendLLBBridge, sm *session.Manager) (solver.Op, error) {
+	if baseOp, ok := v.Sys().(*solverpb.Op); ok {
+		if op, ok := baseOp.Op.(*solverpb.Op_Exec); ok {
+			// the exec ops share the parallelism limit of the local worker
+			var parallelism *semaphore.Weighted
+			if pw, ok := w.Worker.(parallelismWorker); ok {
+				parallelism = pw.ParallelismSemaphore()
+			}
+			return ops.NewExecOp(v, op, baseOp.Platform, w.CacheManager(), parallelism, sm, w.MetadataStore(), w.executor, w)
+		}
+	}
+	return w.Worker.ResolveOp(v, s, sm)
+}
+
+// parallelismWorker is implemented by the local workers that limit the number
+// of ops running at the same time
+type parallelismWorker interface {
+	ParallelismSemaphore() *semaphore.Weighted
+}
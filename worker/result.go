@@ -7,6 +7,7 @@ import (
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/solver"
 	"github.com/moby/buildkit/util/compression"
+	"github.com/pkg/errors"
 )
 
 func NewWorkerRefResult(ref cache.ImmutableRef, worker Worker) solver.Result {
@@ -38,6 +39,25 @@ func (wr *WorkerRef) GetRemote(ctx context.Context, createIfNeeded bool, compres
 	return wr.ImmutableRef.GetRemote(ctx, createIfNeeded, compressionType, forceCompression, g)
 }
 
+// ToWorker returns a reference that can be mounted by w. A reference owned by
+// a worker with another cache manager is transferred through the content store: its layers are
+// exported from the owning worker and imported into w.
+// If a new reference is created, it is owned by the caller and must be released.
+func (wr *WorkerRef) ToWorker(ctx context.Context, w Worker, g session.Group) (*WorkerRef, error) {
+	if wr.ImmutableRef == nil || wr.Worker == nil || wr.Worker.ID() == w.ID() || wr.Worker.CacheManager() == w.CacheManager() {
+		return wr, nil
+	}
+	remote, err := wr.GetRemote(ctx, true, compression.Default, false, g)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to export %s from worker %s", wr.ImmutableRef.ID(), wr.Worker.ID())
+	}
+	ref, err := w.FromRemote(ctx, remote)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to import %s to worker %s", wr.ImmutableRef.ID(), w.ID())
+	}
+	return &WorkerRef{ImmutableRef: ref, Worker: w}, nil
+}
+
 type workerRefResult struct {
 	*WorkerRef
 }
@@ -17,11 +17,13 @@ import (
 	"github.com/moby/buildkit/executor/oci"
 	"github.com/moby/buildkit/executor/runcexecutor"
 	containerdsnapshot "github.com/moby/buildkit/snapshot/containerd"
+	"github.com/moby/buildkit/util/contentutil"
 	"github.com/moby/buildkit/util/leaseutil"
 	"github.com/moby/buildkit/util/network/netproviders"
 	"github.com/moby/buildkit/util/winlayers"
 	"github.com/moby/buildkit/worker/base"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	rspecs "github.com/opencontainers/runtime-spec/specs-go"
 	bolt "go.etcd.io/bbolt"
 	"golang.org/x/sync/semaphore"
 )
@@ -32,8 +34,20 @@ type SnapshotterFactory struct {
 	New  func(root string) (ctdsnapshot.Snapshotter, error)
 }
 
+// Options are the optional settings of an OCI worker
+type Options struct {
+	// Hooks are the OCI hooks of the containers of the worker
+	Hooks *rspecs.Hooks
+	// Devices are the host devices that execs are allowed to use
+	Devices []string
+	// TempDir is the directory for the temporary files of the executor
+	TempDir string
+	// WriteBufferSize is the size of the buffer of the content writes
+	WriteBufferSize int
+}
+
 // NewWorkerOpt creates a WorkerOpt.
-func NewWorkerOpt(root string, snFactory SnapshotterFactory, rootless bool, processMode oci.ProcessMode, labels map[string]string, idmap *idtools.IdentityMapping, nopt netproviders.Opt, dns *oci.DNSConfig, binary, apparmorProfile string, parallelismSem *semaphore.Weighted, traceSocket string) (base.WorkerOpt, error) {
+func NewWorkerOpt(root string, snFactory SnapshotterFactory, rootless bool, processMode oci.ProcessMode, labels map[string]string, idmap *idtools.IdentityMapping, nopt netproviders.Opt, dns *oci.DNSConfig, binary, apparmorProfile string, parallelismSem *semaphore.Weighted, traceSocket string, wopt Options) (base.WorkerOpt, error) {
 	var opt base.WorkerOpt
 	name := "runc-" + snFactory.Name
 	root = filepath.Join(root, name)
@@ -65,6 +79,9 @@ func NewWorkerOpt(root string, snFactory SnapshotterFactory, rootless bool, proc
 		DNS:             dns,
 		ApparmorProfile: apparmorProfile,
 		TracingSocket:   traceSocket,
+		Hooks:           wopt.Hooks,
+		AllowedDevices:  wopt.Devices,
+		TempDir:         wopt.TempDir,
 	}, np)
 	if err != nil {
 		return opt, err
@@ -92,6 +109,10 @@ func NewWorkerOpt(root string, snFactory SnapshotterFactory, rootless bool, proc
 	}
 
 	c = containerdsnapshot.NewContentStore(mdb.ContentStore(), "buildkit")
+	c, err = contentutil.WithWriteBuffer(c, wopt.WriteBufferSize)
+	if err != nil {
+		return opt, err
+	}
 
 	id, err := base.ID(root)
 	if err != nil {
@@ -127,6 +148,7 @@ func NewWorkerOpt(root string, snFactory SnapshotterFactory, rootless bool, proc
 		LeaseManager:    lm,
 		GarbageCollect:  mdb.GarbageCollect,
 		ParallelismSem:  parallelismSem,
+		SourceTempDir:   filepath.Join(root, "sourcetmp"),
 	}
 	return opt, nil
 }
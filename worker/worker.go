@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/leases"
 	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/cache/metadata"
 	"github.com/moby/buildkit/client"
@@ -34,9 +35,38 @@ type Worker interface {
 	FromRemote(ctx context.Context, remote *solver.Remote) (cache.ImmutableRef, error)
 	PruneCacheMounts(ctx context.Context, ids []string) error
 	ContentStore() content.Store
+	LeaseManager() leases.Manager
 	Executor() executor.Executor
 	CacheManager() cache.Manager
 	MetadataStore() *metadata.Store
+	// SecurityConfig returns the settings that allow build containers to
+	// access the host or to relax their isolation
+	SecurityConfig() SecurityConfig
+}
+
+// SecurityConfig has the settings of the daemon that allow build containers
+// to access the host or to relax their isolation. The zero value allows
+// nothing.
+type SecurityConfig struct {
+	// HostPaths maps names to host directories that builds are allowed to
+	// bind mount
+	HostPaths map[string]string
+	// PassthroughEnv lists the env variables of the host that builds are
+	// allowed to pass to their processes
+	PassthroughEnv []string
+	// HostZoneinfo lists the patterns of the timezones whose zoneinfo file of
+	// the host builds are allowed to mount
+	HostZoneinfo []string
+	// AllowedApparmorProfiles lists the apparmor profiles builds are allowed
+	// to run processes with
+	AllowedApparmorProfiles []string
+	// AllowSeccompUnconfined allows builds to run processes without seccomp
+	// or with custom seccomp profiles. The builds also need the
+	// security.insecure entitlement.
+	AllowSeccompUnconfined bool
+	// AllowedSysctls lists the patterns of the sysctls that builds are
+	// allowed to set
+	AllowedSysctls []string
 }
 
 type Infos interface {
@@ -1,33 +1,75 @@
 package worker
 
 import (
+	"sync"
+
 	"github.com/containerd/containerd/filters"
 	"github.com/moby/buildkit/client"
 	"github.com/pkg/errors"
 )
 
 // Controller holds worker instances.
-// Currently, only local workers are supported.
+// Workers may be local or remote implementations of the Worker interface.
 type Controller struct {
-	// TODO: define worker interface and support remote ones
+	mu      sync.RWMutex
 	workers []Worker
+	onAdd   []func(Worker)
+}
+
+// OnAdd registers a function that is called with every worker added after
+// the call
+func (c *Controller) OnAdd(fn func(Worker)) {
+	c.mu.Lock()
+	c.onAdd = append(c.onAdd, fn)
+	c.mu.Unlock()
 }
 
-// Add adds a local worker.
+// Add adds a worker.
 // The first worker becomes the default.
-//
-// Add is not thread-safe.
+// Workers can be added while the daemon is running, e.g. when a remote worker
+// registers itself.
 func (c *Controller) Add(w Worker) error {
+	c.mu.Lock()
+	for _, w2 := range c.workers {
+		if w2.ID() == w.ID() {
+			c.mu.Unlock()
+			return errors.Errorf("worker %s already registered", w.ID())
+		}
+	}
 	c.workers = append(c.workers, w)
+	onAdd := c.onAdd
+	c.mu.Unlock()
+	for _, fn := range onAdd {
+		fn(w)
+	}
 	return nil
 }
 
+// Remove removes a previously added worker.
+// The default worker can not be removed.
+func (c *Controller) Remove(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, w := range c.workers {
+		if w.ID() == id {
+			if i == 0 {
+				return errors.Errorf("default worker %s can not be removed", id)
+			}
+			c.workers = append(c.workers[:i:i], c.workers[i+1:]...)
+			return nil
+		}
+	}
+	return errors.Errorf("worker %s not found", id)
+}
+
 // List lists workers
 func (c *Controller) List(filterStrings ...string) ([]Worker, error) {
 	filter, err := filters.ParseAll(filterStrings...)
 	if err != nil {
 		return nil, err
 	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	var workers []Worker
 	for _, w := range c.workers {
 		if filter.Match(adaptWorker(w)) {
@@ -39,6 +81,8 @@ func (c *Controller) List(filterStrings ...string) ([]Worker, error) {
 
 // GetDefault returns the default local worker
 func (c *Controller) GetDefault() (Worker, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	if len(c.workers) == 0 {
 		return nil, errors.Errorf("no default worker")
 	}
@@ -46,6 +90,8 @@ func (c *Controller) GetDefault() (Worker, error) {
 }
 
 func (c *Controller) Get(id string) (Worker, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	for _, w := range c.workers {
 		if w.ID() == id {
 			return w, nil
@@ -54,11 +100,27 @@ func (c *Controller) Get(id string) (Worker, error) {
 	return nil, errors.Errorf("worker %s not found", id)
 }
 
-// TODO: add Get(Constraint) (*Worker, error)
+// Select returns the first worker matching any of the containerd-style
+// filters. If no filters are passed the default worker is returned.
+func (c *Controller) Select(filterStrings ...string) (Worker, error) {
+	if len(filterStrings) == 0 {
+		return c.GetDefault()
+	}
+	workers, err := c.List(filterStrings...)
+	if err != nil {
+		return nil, err
+	}
+	if len(workers) == 0 {
+		return nil, errors.Errorf("no worker found for constraints %v", filterStrings)
+	}
+	return workers[0], nil
+}
 
 // WorkerInfos returns slice of WorkerInfo.
 // The first item is the default worker.
 func (c *Controller) WorkerInfos() []client.WorkerInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	out := make([]client.WorkerInfo, 0, len(c.workers))
 	for _, w := range c.workers {
 		out = append(out, client.WorkerInfo{